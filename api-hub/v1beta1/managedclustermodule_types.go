@@ -22,6 +22,7 @@ import (
 )
 
 // ManagedClusterModuleSpec defines the desired state of ManagedClusterModule
+// +kubebuilder:validation:XValidation:message="exactly one of selector and placement must be set",rule="has(self.selector) != has(self.placement)"
 type ManagedClusterModuleSpec struct {
 	// ModuleSpec describes how the KMM operator should deploy a Module on those nodes that need it.
 	ModuleSpec kmmv1beta1.ModuleSpec `json:"moduleSpec,omitempty"`
@@ -30,9 +31,173 @@ type ManagedClusterModuleSpec struct {
 	SpokeNamespace string `json:"spokeNamespace"`
 
 	// Selector describes on which managed clusters the ModuleSpec should be applied.
+	// Exactly one of Selector and Placement must be set.
+	// +optional
+	Selector map[string]string `json:"selector,omitempty"`
+
+	// Placement references an OCM Placement whose PlacementDecisions select the managed clusters on which the
+	// ModuleSpec should be applied, instead of Selector.
+	// Exactly one of Selector and Placement must be set.
+	// +optional
+	Placement *PlacementReference `json:"placement,omitempty"`
+
+	// Rollout describes how changes to ModuleSpec and SpokeNamespace are rolled out to the selected managed clusters.
+	// If it is not set, they are rolled out to all the selected managed clusters at once.
+	// +optional
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
+
+	// Overrides are patches applied, in order, to the Module created on the managed clusters they select.
+	// +optional
+	Overrides []ClusterOverride `json:"overrides,omitempty"`
+}
+
+// PatchType is the type of the patch of a ClusterOverride.
+// +kubebuilder:validation:Enum=StrategicMerge;JSON
+type PatchType string
+
+const (
+	// StrategicMergePatchType is a Kubernetes strategic merge patch.
+	StrategicMergePatchType PatchType = "StrategicMerge"
+	// JSONPatchType is a RFC 6902 JSON patch.
+	JSONPatchType PatchType = "JSON"
+)
+
+// ClusterOverride is a patch applied to the Module created on some managed clusters.
+// +kubebuilder:validation:XValidation:message="exactly one of clusterName and clusterSelector must be set",rule="has(self.clusterName) != has(self.clusterSelector)"
+type ClusterOverride struct {
+	// ClusterName selects the managed cluster with that name.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// ClusterSelector selects the managed clusters by their labels.
+	// +optional
+	ClusterSelector map[string]string `json:"clusterSelector,omitempty"`
+
+	// Type is the type of Patch.
+	// +kubebuilder:default=StrategicMerge
+	// +optional
+	Type PatchType `json:"type,omitempty"`
+
+	// Patch is applied to the Module before the kernel mappings are generated for the managed cluster, so that the
+	// images built and signed on the hub for the managed cluster are the ones of the patched Module. It may only change
+	// the spec of the Module.
+	// It can be written in JSON or YAML.
+	Patch string `json:"patch"`
+}
+
+// RolloutStrategy describes a progressive rollout to the selected managed clusters.
+// The clusters are rolled out to in stages: each group, or each batch of a group if BatchSize is set, is a stage, and a
+// stage is only started once the Module is available on all the managed clusters of the previous stages.
+type RolloutStrategy struct {
+	// Groups are the ordered groups of managed clusters to roll out to.
+	// Each managed cluster belongs to the first group selecting it; the clusters that no group selects are rolled out
+	// to last.
+	// +optional
+	Groups []RolloutGroup `json:"groups,omitempty"`
+
+	// BatchSize is the maximum number of managed clusters of a group that are rolled out to at once.
+	// 0 means all the clusters of the group.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty"`
+
+	// Paused stops the rollout to further managed clusters.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// ResumeRevision resumes the rollout of a revision that was paused because the ManifestWork of a managed cluster
+	// already rolled out to was degraded, when set to the Status.Rollout.PausedRevision of that revision.
+	// Degraded ManifestWorks do not pause the rollout of that revision again.
+	// +optional
+	ResumeRevision string `json:"resumeRevision,omitempty"`
+}
+
+// RolloutGroup is a group of managed clusters rolled out to together.
+type RolloutGroup struct {
+	// Name is the name of the group.
+	Name string `json:"name"`
+
+	// Selector selects the managed clusters of the group by their labels.
 	Selector map[string]string `json:"selector"`
 }
 
+// PlacementReference references an OCM Placement.
+type PlacementReference struct {
+	// Name is the name of the Placement.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Placement.
+	Namespace string `json:"namespace"`
+}
+
+const (
+	// ManagedClusterModuleImagesReady is true if the kmod images exist for all the selected managed clusters.
+	ManagedClusterModuleImagesReady = "ImagesReady"
+	// ManagedClusterModuleApplied is true if the ManifestWorks were applied on all the selected managed clusters.
+	ManagedClusterModuleApplied = "Applied"
+	// ManagedClusterModuleAvailable is true if the kernel module is loaded on all the nodes of all the selected
+	// managed clusters that need it.
+	ManagedClusterModuleAvailable = "Available"
+	// ManagedClusterModuleDegraded is true if the ManifestWork of at least one managed cluster is degraded.
+	ManagedClusterModuleDegraded = "Degraded"
+	// ManagedClusterModuleRolloutPaused is true if the rollout to further managed clusters is paused, either by
+	// Spec.Rollout.Paused or because ManifestWorks were degraded.
+	ManagedClusterModuleRolloutPaused = "RolloutPaused"
+)
+
+// ClusterModuleStatus describes the state of the Module on a managed cluster.
+type ClusterModuleStatus struct {
+	// ClusterName is the name of the managed cluster.
+	ClusterName string `json:"clusterName"`
+
+	// KernelVersions are the kernel versions running on the nodes of the managed cluster.
+	// +optional
+	KernelVersions []string `json:"kernelVersions,omitempty"`
+
+	// ImagesReady is true if the kmod images exist for all the kernel versions of the managed cluster.
+	ImagesReady bool `json:"imagesReady"`
+
+	// ManifestWorkApplied is true if the ManifestWork was applied on the managed cluster.
+	ManifestWorkApplied bool `json:"manifestWorkApplied"`
+
+	// ManifestWorkAvailable is true if the resources of the ManifestWork exist on the managed cluster.
+	ManifestWorkAvailable bool `json:"manifestWorkAvailable"`
+
+	// ModuleLoader is the status of the ModuleLoader of the Module on the managed cluster, as reported by the
+	// ManifestWork.
+	// +optional
+	ModuleLoader *kmmv1beta1.DaemonSetStatus `json:"moduleLoader,omitempty"`
+
+	// Message explains why the Module could not be deployed to the managed cluster, if applicable.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RolloutStatus describes the progress of a rollout.
+type RolloutStatus struct {
+	// Revision identifies the ModuleSpec and SpokeNamespace being rolled out.
+	Revision string `json:"revision"`
+
+	// Stage is the index of the stage being rolled out to; it is equal to Stages once the rollout is complete.
+	Stage int32 `json:"stage"`
+
+	// Stages is the number of stages of the rollout.
+	Stages int32 `json:"stages"`
+
+	// UpdatedClusters is the number of selected managed clusters whose ManifestWork is at Revision.
+	UpdatedClusters int32 `json:"updatedClusters"`
+
+	// PausedRevision is set to Revision when its rollout was paused because the ManifestWork of a managed cluster
+	// already rolled out to was degraded. The rollout stays paused until Spec.Rollout.ResumeRevision is set to it, or
+	// a new revision is rolled out.
+	// +optional
+	PausedRevision string `json:"pausedRevision,omitempty"`
+
+	// Message describes the state of the rollout.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // ManagedClusterModuleStatus defines the observed state of ManagedClusterModule.
 type ManagedClusterModuleStatus struct {
 	// Number of ManifestWorks to be applied.
@@ -43,6 +208,22 @@ type ManagedClusterModuleStatus struct {
 
 	// Number of ManifestWorks that could not be successfully applied.
 	NumberDegraded int32 `json:"numberDegraded,omitempty"`
+
+	// Clusters describes the state of the Module on each selected managed cluster.
+	// +optional
+	// +listType=map
+	// +listMapKey=clusterName
+	Clusters []ClusterModuleStatus `json:"clusters,omitempty"`
+
+	// Conditions aggregate the state of the Module on all the selected managed clusters.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Rollout is the progress of the rollout, if Spec.Rollout is set.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,91 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ManagedClusterPreflightValidationSpec describes the kernel version that all the ManagedClusterModules need to be
+// verified against.
+// +kubebuilder:validation:Required
+type ManagedClusterPreflightValidationSpec struct {
+	// KernelVersion describes the kernel image that all ManagedClusterModules need to be checked against.
+	// +kubebuilder:validation:Required
+	KernelVersion string `json:"kernelVersion"`
+
+	// Boolean flag that determines whether images built during preflight must also be pushed to a defined repository,
+	// so that they are ready before the managed clusters are upgraded.
+	// +optional
+	PushBuiltImage bool `json:"pushBuiltImage"`
+
+	// ClusterSelector restricts the validation to the managed clusters matching it, for example the clusters about to
+	// be upgraded. All the managed clusters selected by the ManagedClusterModules are considered if it is not set.
+	// +optional
+	ClusterSelector map[string]string `json:"clusterSelector,omitempty"`
+}
+
+// ManagedClusterModulePreflightStatus is the preflight validation status of a ManagedClusterModule.
+type ManagedClusterModulePreflightStatus struct {
+	v1beta2.CRBaseStatus `json:",inline"`
+
+	// Name is the name of the ManagedClusterModule.
+	Name string `json:"name"`
+
+	// Clusters are the managed clusters selected by the ManagedClusterModule that the validation applies to.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+// ManagedClusterPreflightValidationStatus is the most recently observed status of the
+// ManagedClusterPreflightValidation.
+type ManagedClusterPreflightValidationStatus struct {
+	// ManagedClusterModules contain observations about each ManagedClusterModule's preflight upgradability validation.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ManagedClusterModules []ManagedClusterModulePreflightStatus `json:"managedClusterModules,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=managedclusterpreflightvalidations,scope=Cluster,shortName=mcpfv
+//+kubebuilder:subresource:status
+
+// ManagedClusterPreflightValidation initiates a preflight validation of all ManagedClusterModules from the Hub.
+// +operator-sdk:csv:customresourcedefinitions:displayName="Managed Cluster Preflight Validation"
+type ManagedClusterPreflightValidation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// +kubebuilder:validation:Required
+
+	Spec   ManagedClusterPreflightValidationSpec   `json:"spec,omitempty"`
+	Status ManagedClusterPreflightValidationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ManagedClusterPreflightValidationList contains a list of ManagedClusterPreflightValidation
+type ManagedClusterPreflightValidationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ManagedClusterPreflightValidation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ManagedClusterPreflightValidation{}, &ManagedClusterPreflightValidationList{})
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KernelBuilderEntry maps kernels to the image in which kernel modules are built for them.
+// +kubebuilder:validation:XValidation:rule="has(self.kernelVersionRegexp) || has(self.osImageRegexp)",message="at least one of kernelVersionRegexp and osImageRegexp must be set"
+type KernelBuilderEntry struct {
+	// +optional
+	// KernelVersionRegexp is a regular expression matched against the kernel version.
+	KernelVersionRegexp string `json:"kernelVersionRegexp,omitempty"`
+
+	// +optional
+	// OSImageRegexp is a regular expression matched against the OS image (for example "Ubuntu 22.04.4 LTS") reported
+	// by the nodes running the kernel.
+	// If KernelVersionRegexp is also set, both must match.
+	OSImageRegexp string `json:"osImageRegexp,omitempty"`
+
+	// BuilderImage is the image containing the headers and the toolchain needed to build kernel modules.
+	// The variables listed in the documentation (for example ${KERNEL_FULL_VERSION}) are replaced by their values.
+	// +kubebuilder:validation:MinLength=1
+	BuilderImage string `json:"builderImage"`
+}
+
+// KernelBuilderCatalogSpec describes the builder images of a catalog.
+type KernelBuilderCatalogSpec struct {
+	// Entries are evaluated in order; the first one matching a kernel provides its builder image.
+	// +kubebuilder:validation:MinItems=1
+	Entries []KernelBuilderEntry `json:"entries"`
+}
+
+// +kubebuilder:object:root=true
+
+// KernelBuilderCatalog maps kernel versions and OS images to the images used to build kernel modules.
+// It is consulted before the Driver Toolkit to resolve the DTK_AUTO and KERNEL_BUILDER_IMAGE build arguments.
+// +kubebuilder:resource:path=kernelbuildercatalogs,scope=Cluster,shortName=kbc
+// +operator-sdk:csv:customresourcedefinitions:displayName="Kernel Builder Catalog"
+type KernelBuilderCatalog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KernelBuilderCatalogSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// KernelBuilderCatalogList is a list of KernelBuilderCatalog objects.
+type KernelBuilderCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of KernelBuilderCatalog. More info:
+	// https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md
+	Items []KernelBuilderCatalog `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KernelBuilderCatalog{}, &KernelBuilderCatalogList{})
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KernelDTKOverride sets the Driver Toolkit image of a kernel.
+type KernelDTKOverride struct {
+	// KernelVersion is the exact kernel version, as reported by the nodes without the trailing "+".
+	// +kubebuilder:validation:MinLength=1
+	KernelVersion string `json:"kernelVersion"`
+
+	// DTKImage is the Driver Toolkit image used to build kernel modules for KernelVersion.
+	// +kubebuilder:validation:MinLength=1
+	DTKImage string `json:"dtkImage"`
+}
+
+// KernelDTKMappingSpec describes the manual overrides of the kernel to Driver Toolkit mapping.
+type KernelDTKMappingSpec struct {
+	// +optional
+	// Overrides take precedence over the mapping discovered from the nodes and the driver-toolkit ImageStream.
+	// +listType=map
+	// +listMapKey=kernelVersion
+	Overrides []KernelDTKOverride `json:"overrides,omitempty"`
+}
+
+// KernelDTKStatus describes the Driver Toolkit image discovered for a kernel.
+type KernelDTKStatus struct {
+	// KernelVersion is the kernel version reported by the nodes, without the trailing "+".
+	KernelVersion string `json:"kernelVersion"`
+
+	// OSImageVersion is the version of the OS image of the nodes running the kernel.
+	OSImageVersion string `json:"osImageVersion"`
+
+	// DTKImage is the Driver Toolkit image of OSImageVersion in the driver-toolkit ImageStream.
+	DTKImage string `json:"dtkImage"`
+
+	// +optional
+	// UnusedSince is the time from which no node runs the kernel. The kernel is removed from the status once no node
+	// has run it for the retention period of the operator, unless it has an override.
+	UnusedSince *metav1.Time `json:"unusedSince,omitempty"`
+}
+
+// KernelDTKMappingStatus holds the kernel to Driver Toolkit mapping discovered by the operator.
+type KernelDTKMappingStatus struct {
+	// +optional
+	// Kernels are kept for a retention period after the nodes stop running them, so that they can still be resolved.
+	// +listType=map
+	// +listMapKey=kernelVersion
+	Kernels []KernelDTKStatus `json:"kernels,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// KernelDTKMapping persists the mapping between kernels and the Driver Toolkit images used to resolve the DTK_AUTO
+// build argument. The operator maintains a single KernelDTKMapping named default.
+// +kubebuilder:resource:path=kerneldtkmappings,scope=Cluster
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'default'",message="the KernelDTKMapping must be named default"
+// +operator-sdk:csv:customresourcedefinitions:displayName="Kernel DTK Mapping"
+type KernelDTKMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KernelDTKMappingSpec   `json:"spec,omitempty"`
+	Status KernelDTKMappingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// KernelDTKMappingList is a list of KernelDTKMapping objects.
+type KernelDTKMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of KernelDTKMapping. More info:
+	// https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md
+	Items []KernelDTKMapping `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KernelDTKMapping{}, &KernelDTKMappingList{})
+}
@@ -70,6 +70,95 @@ type Build struct {
 	// +optional
 	// Selector describes on which nodes will run the building process.
 	Selector map[string]string `json:"selector,omitempty"`
+
+	// +optional
+	// ContextConfigMaps is an optional list of ConfigMaps whose keys are added as files to the build context.
+	// They can hold patches, Kbuild fragments or helper scripts referenced by COPY instructions in the Dockerfile.
+	ContextConfigMaps []BuildContextConfigMap `json:"contextConfigMaps,omitempty"`
+
+	// +optional
+	// ContextSecrets is an optional list of Secrets whose keys are added as files to the build context.
+	// Unlike Secrets, their content is part of the build context and may be copied into the image.
+	ContextSecrets []BuildContextSecret `json:"contextSecrets,omitempty"`
+
+	// +optional
+	// Git is an optional Git repository used as the build context.
+	// The Dockerfile from DockerfileConfigMap is used instead of any Dockerfile present in the repository.
+	Git *GitBuildSource `json:"git,omitempty"`
+
+	// +optional
+	// RetryPolicy defines the timeout of the build and how it is retried when it fails.
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// LogRetention is the number of archived build and sign logs kept per kernel for this Module; 0 keeps all of them.
+	// Defaults to the logRetentionPerKernel setting of the operator.
+	LogRetention *int32 `json:"logRetention,omitempty"`
+}
+
+// RetryPolicy describes how a build or a sign is retried when it fails.
+// Only failures that are likely to be transient, such as errors while pulling the base image, fetching the sources
+// or pushing the image, and timeouts, are retried. Compilation errors are never retried.
+type RetryPolicy struct {
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+
+	// +optional
+	// Backoff is the delay before the first retry. It is doubled after each failed attempt. Defaults to 30s.
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+
+	// +optional
+	// MaxBackoff is the maximum delay between two attempts. Defaults to 10m.
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+
+	// +optional
+	// Timeout is the maximum duration of a single attempt. Attempts that run longer are failed and may be retried.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// BuildContextConfigMap describes a ConfigMap to add to the build context.
+type BuildContextConfigMap struct {
+	// ConfigMap is the ConfigMap to add to the build context.
+	ConfigMap v1.LocalObjectReference `json:"configMap"`
+
+	// +optional
+	// DestinationDir is the directory of the build context in which the keys of the ConfigMap are created.
+	// It must be a relative path. Defaults to the root of the build context.
+	DestinationDir string `json:"destinationDir,omitempty"`
+}
+
+// BuildContextSecret describes a Secret to add to the build context.
+type BuildContextSecret struct {
+	// Secret is the Secret to add to the build context.
+	Secret v1.LocalObjectReference `json:"secret"`
+
+	// +optional
+	// DestinationDir is the directory of the build context in which the keys of the Secret are created.
+	// It must be a relative path. Defaults to the root of the build context.
+	DestinationDir string `json:"destinationDir,omitempty"`
+}
+
+// GitBuildSource describes a Git repository used as the build context.
+type GitBuildSource struct {
+	// URI is the URI of the Git repository.
+	URI string `json:"uri"`
+
+	// +optional
+	// Ref is the branch, tag or commit to check out. Defaults to the default branch of the repository.
+	// It is resolved to the commit that is built; repositories that are not served over HTTP(S) require a full commit.
+	Ref string `json:"ref,omitempty"`
+
+	// +optional
+	// ContextDir is the sub-directory of the repository used as the root of the build context.
+	ContextDir string `json:"contextDir,omitempty"`
+
+	// +optional
+	// SourceSecret is a Secret holding the credentials used to clone the repository.
+	SourceSecret *v1.LocalObjectReference `json:"sourceSecret,omitempty"`
 }
 
 type Sign struct {
@@ -81,16 +170,98 @@ type Sign struct {
 	// UnsignedImageRegistryTLS contains settings determining how to access registries of the unsigned image.
 	UnsignedImageRegistryTLS TLSOptions `json:"unsignedImageRegistryTLS,omitempty"`
 
-	// a secret containing the private key used to sign kernel modules for secureboot
+	// +optional
+	// a secret containing the private key used to sign kernel modules for secureboot.
+	// Mutually exclusive with PKCS11, SigningService and SigningKey.
 	KeySecret *v1.LocalObjectReference `json:"keySecret"`
 
-	// a secret containing the public key used to sign kernel modules for secureboot
-	CertSecret *v1.LocalObjectReference `json:"certSecret"`
+	// +optional
+	// PKCS11 signs kernel modules with a private key held by a PKCS#11 token, such as an HSM, so that the key never
+	// needs to be stored in the cluster.
+	// Mutually exclusive with KeySecret, SigningService and SigningKey.
+	PKCS11 *PKCS11Key `json:"pkcs11,omitempty"`
+
+	// +optional
+	// SigningService signs kernel modules by sending them to an external HTTP signing service, which returns their
+	// PKCS#7 signature.
+	// Mutually exclusive with KeySecret, PKCS11 and SigningKey.
+	SigningService *SigningService `json:"signingService,omitempty"`
+
+	// +optional
+	// SigningKey references a SigningKey in the Module's namespace, whose key pair is generated and rotated by KMM.
+	// Kernel modules are signed again when the key pair is rotated.
+	// Mutually exclusive with KeySecret, PKCS11, SigningService and CertSecret.
+	SigningKey *v1.LocalObjectReference `json:"signingKey,omitempty"`
+
+	// +optional
+	// a secret containing the public key used to sign kernel modules for secureboot.
+	// Required unless SigningKey is set.
+	CertSecret *v1.LocalObjectReference `json:"certSecret,omitempty"`
 
 	// +optional
 	// Paths inside the image for the kernel modules to sign.
 	// Full path to explicit files are required or any globs supported by the Bash shell
 	FilesToSign []string `json:"filesToSign,omitempty"`
+
+	// +optional
+	// AutoDiscover signs all the kernel modules found in the image, instead of the files listed in FilesToSign.
+	// Mutually exclusive with FilesToSign.
+	AutoDiscover *SignAutoDiscovery `json:"autoDiscover,omitempty"`
+
+	// +optional
+	// RetryPolicy defines the timeout of the sign and how it is retried when it fails.
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// SignAutoDiscovery selects the kernel modules to sign among the ones found under <dirName>/lib/modules/<kernel>,
+// whether uncompressed (.ko) or compressed with xz (.ko.xz) or zstd (.ko.zst).
+// Patterns are shell patterns matched against the path of each module relative to that directory, in which "*"
+// also matches "/"; for example "kernel/drivers/net/*" or "*/mlx5_*.ko".
+type SignAutoDiscovery struct {
+	// +optional
+	// Include lists the patterns of the modules to sign. All the modules are signed if it is empty.
+	// +kubebuilder:validation:items:Pattern=`^[A-Za-z0-9_.*?/+\[\]-]+$`
+	Include []string `json:"include,omitempty"`
+
+	// +optional
+	// Exclude lists the patterns of the modules not to sign, even if they match Include.
+	// +kubebuilder:validation:items:Pattern=`^[A-Za-z0-9_.*?/+\[\]-]+$`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// PKCS11Key references a private key stored in a PKCS#11 token.
+type PKCS11Key struct {
+	// URI is the PKCS#11 URI of the private key, as defined in RFC 7512; for example
+	// "pkcs11:token=secureboot;object=signing-key".
+	// +kubebuilder:validation:Pattern=`^pkcs11:[^']*$`
+	URI string `json:"uri"`
+
+	// ModulePath is the path of the PKCS#11 module (provider library) loaded by sign-file.
+	// It can point to a file in the sign image, or to a file of ConfigSecret under /run/secrets/pkcs11.
+	ModulePath string `json:"modulePath"`
+
+	// +optional
+	// ConfigSecret is a secret mounted under /run/secrets/pkcs11 during signing.
+	// It can hold the configuration of the PKCS#11 module, such as the address of a network HSM proxy, and the PIN
+	// of the token under the "pin" key.
+	ConfigSecret *v1.LocalObjectReference `json:"configSecret,omitempty"`
+}
+
+// SigningService describes an external HTTP service that signs kernel modules.
+// Each file to sign is sent as the body of a POST request; the service must reply with the DER-encoded PKCS#7
+// detached signature of the file.
+type SigningService struct {
+	// +optional
+	// URL is the HTTPS endpoint of the signing service.
+	// Defaults to the signing service URL of the operator's configuration.
+	// +kubebuilder:validation:Pattern=`^https://[^']*$`
+	URL string `json:"url,omitempty"`
+
+	// +optional
+	// TLSSecret is a secret holding the client certificate and key used to authenticate to the signing service
+	// (mutual TLS), under the "tls.crt" and "tls.key" keys, and the CA certificate of the service under the "ca.crt"
+	// key.
+	TLSSecret *v1.LocalObjectReference `json:"tlsSecret,omitempty"`
 }
 
 // KernelMapping pairs kernel versions with a DriverContainer image.
@@ -406,6 +577,45 @@ type ModuleSpec struct {
 	// all module images.
 	// +optional
 	ImageRebuildTriggerGeneration *int `json:"imageRebuildTriggerGeneration,omitempty"`
+
+	// BuildPriority is used to order the builds and signs of the module images when they are queued because of the
+	// concurrency limits configured in the operator. Higher values are started first.
+	// +optional
+	BuildPriority int32 `json:"buildPriority,omitempty"`
+
+	// ImagePrePull enables pulling the kmod images onto the targeted nodes before they are needed there.
+	// When set, the kernel module is only loaded on a node, or upgraded there, once its image was pulled onto it.
+	// +optional
+	ImagePrePull *ImagePrePullSpec `json:"imagePrePull,omitempty"`
+
+	// ImageGC enables the garbage collection of the kmod images of the kernels that no node runs anymore.
+	// +optional
+	ImageGC *ImageGCSpec `json:"imageGC,omitempty"`
+}
+
+// ImageGCSpec describes how the kmod images of the kernels that no node runs anymore are collected.
+type ImageGCSpec struct {
+	// RetentionPeriod is how long the image of a kernel is kept after the last node running that kernel was upgraded
+	// or left the cluster. The images that are still used by another Module or by a PreflightValidation are kept.
+	RetentionPeriod metav1.Duration `json:"retentionPeriod"`
+
+	// DeleteFromRegistry deletes the collected images that were built in-cluster from their registry.
+	// Images that were not built by KMM are never deleted from their registry.
+	// +optional
+	DeleteFromRegistry bool `json:"deleteFromRegistry,omitempty"`
+
+	// DryRun only reports the images that would be collected in the status of the ModuleImagesConfig of the Module,
+	// without collecting them.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// ImagePrePullSpec describes which kmod images are pulled onto the targeted nodes ahead of time.
+type ImagePrePullSpec struct {
+	// KernelVersions are the kernels that the nodes are about to run, for example after an upgrade.
+	// The images mapped to those kernels are built if needed and pulled onto all targeted nodes ahead of their reboot.
+	// +optional
+	KernelVersions []string `json:"kernelVersions,omitempty"`
 }
 
 // DaemonSetStatus contains the status for a daemonset deployed during
@@ -433,6 +643,12 @@ type ModuleStatus struct {
 	// When this differs from spec.imageRebuildTriggerGeneration, all module images will be re-verified and potentially rebuilt.
 	// +optional
 	ImageRebuildTriggerGeneration *int `json:"imageRebuildTriggerGeneration,omitempty"`
+	// ManagedClusterModuleRevision is the revision of the ManagedClusterModule that the Module was deployed from, as
+	// found in its kmm.node.kubernetes.io/managedclustermodule.revision annotation, once all the nodes of the Module
+	// were configured for it. It is synced back to the hub, so that the rollout of the ManagedClusterModule only
+	// proceeds once the Module runs its latest revision.
+	// +optional
+	ManagedClusterModuleRevision string `json:"managedClusterModuleRevision,omitempty"`
 }
 
 //+kubebuilder:object:root=true
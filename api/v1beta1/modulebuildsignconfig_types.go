@@ -23,6 +23,7 @@ import (
 
 type BuildOrSignAction string
 type BuildOrSignStatus string
+type BuildOrSignFailureReason string
 
 const (
 	// BuildImage means that image needs to be built
@@ -36,6 +37,32 @@ const (
 
 	// ActionFailure means that action (sign or build, depending on the action field) has failed
 	ActionFailure BuildOrSignStatus = "Failure"
+
+	// ActionQueued means that action (sign or build, depending on the action field) is waiting for the number of
+	// concurrent builds and signs to go below the configured limits
+	ActionQueued BuildOrSignStatus = "Queued"
+
+	// FailureFetch means that the sources, the builder image or the content of the images could not be fetched
+	FailureFetch BuildOrSignFailureReason = "Fetch"
+
+	// FailureCompile means that the Dockerfile failed to build, e.g. because the kernel module did not compile
+	FailureCompile BuildOrSignFailureReason = "Compile"
+
+	// FailurePush means that the image could not be pushed to the registry
+	FailurePush BuildOrSignFailureReason = "Push"
+
+	// FailureVerification means that the sign failed: a kernel module could not be signed or verified with the
+	// configured certificate, or a pattern of filesToSign did not match any file
+	FailureVerification BuildOrSignFailureReason = "Verification"
+
+	// FailureTimeout means that the action did not complete within the timeout of its retry policy
+	FailureTimeout BuildOrSignFailureReason = "Timeout"
+
+	// FailureCancelled means that the build or sign was cancelled
+	FailureCancelled BuildOrSignFailureReason = "Cancelled"
+
+	// FailureUnknown means that the action failed for any other reason
+	FailureUnknown BuildOrSignFailureReason = "Unknown"
 )
 
 // ModuleBuildSignSpec describes the image whose state needs to be queried
@@ -64,23 +91,65 @@ type ModuleBuildSignConfigSpec struct {
 	// Tolerations specifies the tolerations for build/sign pods.
 	// +optional
 	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// BuildPriority determines the order in which queued builds and signs are started.
+	// Propagated from Module.spec.buildPriority.
+	// +optional
+	BuildPriority int32 `json:"buildPriority,omitempty"`
 }
 
 // BuildSignImageState contains the status of the image that was requested to be built/signed
 type BuildSignImageState struct {
 	Image string `json:"image"`
 
-	// +kubebuilder:validation:Enum=Success;Failure
+	// +kubebuilder:validation:Enum=Success;Failure;Queued
 	Status BuildOrSignStatus `json:"status"`
 
 	// +kubebuilder:validation:Enum=BuildImage;SignImage
 	Action BuildOrSignAction `json:"action"`
+
+	// BuildInputsHash is the fingerprint of the build inputs (Dockerfile, resolved build arguments, build secrets
+	// and base image digests) that were used to build the image.
+	// +optional
+	BuildInputsHash string `json:"buildInputsHash,omitempty"`
+
+	// SigningKeyFingerprint is the fingerprint of the SigningKey certificate that the image was signed with.
+	// +optional
+	SigningKeyFingerprint string `json:"signingKeyFingerprint,omitempty"`
+
+	// FailedAttempts is the number of attempts of the action that failed.
+	// +optional
+	FailedAttempts int32 `json:"failedAttempts,omitempty"`
+
+	// FailureReason is the classification of the last failure of the action.
+	// +kubebuilder:validation:Enum=Fetch;Compile;Push;Verification;Timeout;Cancelled;Unknown
+	// +optional
+	FailureReason BuildOrSignFailureReason `json:"failureReason,omitempty"`
+
+	// NextAttemptTime is the time after which the failed action is retried.
+	// It is not set if the failure cannot be retried or if all the attempts were used.
+	// +optional
+	NextAttemptTime *metav1.Time `json:"nextAttemptTime,omitempty"`
+
+	// LogConfigMap is the name of the ConfigMap holding the logs of the last finished build or sign of the image.
+	// +optional
+	LogConfigMap string `json:"logConfigMap,omitempty"`
+
+	// SignedFiles lists the kernel modules that were signed by the last successful sign of the image, as resolved
+	// from filesToSign or autoDiscover.
+	// +optional
+	SignedFiles []string `json:"signedFiles,omitempty"`
 }
 
 // ModuleBuildSignConfigStatus describes the status of the images that needed to be built/signed
 // More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
 type ModuleBuildSignConfigStatus struct {
 	Images []BuildSignImageState `json:"images"`
+
+	// LastBuildInputsCheckTime is the last time the build inputs of the built images were compared to the ones they
+	// were built from.
+	// +optional
+	LastBuildInputsCheckTime *metav1.Time `json:"lastBuildInputsCheckTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
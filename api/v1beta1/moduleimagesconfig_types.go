@@ -32,6 +32,8 @@ const (
 	ImageNeedsBuilding ImageState = "NeedsBuilding"
 	// ImageNeedsSigning means that images needs signing, because it was pre-built, or in-cluster build succeeded
 	ImageNeedsSigning ImageState = "NeedsSigning"
+	// ImageInvalidLayout means that image exists, but does not contain the kernel modules expected for its kernel
+	ImageInvalidLayout ImageState = "InvalidLayout"
 )
 
 // ModuleImageSpec describes the image whose state needs to be queried
@@ -62,6 +64,15 @@ type ModuleImageSpec struct {
 	// DirName is the root directory for modules, used during signing.
 	// +kubebuilder:default=/opt
 	DirName string `json:"dirName,omitempty"`
+
+	// +optional
+	// ModuleNames are the names of the kernel modules that the image must contain for KernelVersion.
+	// The layout of the image is only validated if it is set.
+	ModuleNames []string `json:"moduleNames,omitempty"`
+
+	// +optional
+	// FirmwarePath is the path of the firmware that the image must contain, if any.
+	FirmwarePath string `json:"firmwarePath,omitempty"`
 }
 
 // ModuleImagesConfigSpec describes the images of the Module whose status needs to be verified
@@ -88,9 +99,54 @@ type ModuleImagesConfigSpec struct {
 	// +optional
 	ImageRebuildTriggerGeneration *int `json:"imageRebuildTriggerGeneration,omitempty"`
 
+	// BuildPriority determines the order in which queued builds and signs are started.
+	// Propagated from Module.spec.buildPriority.
+	// +optional
+	BuildPriority int32 `json:"buildPriority,omitempty"`
+
 	// Tolerations specifies the tolerations for build/sign pods.
 	// +optional
 	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// PrePulls lists the images to pull onto nodes before the kernel module is loaded there.
+	// Propagated from Module.spec.imagePrePull.
+	// +optional
+	PrePulls []PrePullSpec `json:"prePulls,omitempty"`
+
+	// ImageGC enables the garbage collection of the images of the kernels that no node runs anymore.
+	// Propagated from Module.spec.imageGC.
+	// +optional
+	ImageGC *ImageGCSpec `json:"imageGC,omitempty"`
+}
+
+// PrePullSpec describes the nodes onto which an image must be pulled.
+type PrePullSpec struct {
+	// Image is one of the images of the spec; it is only pulled onto the nodes once it exists.
+	Image string `json:"image"`
+
+	// Nodes are the names of the nodes onto which the image is pulled.
+	Nodes []string `json:"nodes"`
+}
+
+type PrePullState string
+
+const (
+	// PrePullPulling means that the image is being pulled onto the node
+	PrePullPulling PrePullState = "Pulling"
+	// PrePullPulled means that the image was pulled onto the node
+	PrePullPulled PrePullState = "Pulled"
+	// PrePullFailed means that the image could not be pulled onto the node; it is not pulled again
+	PrePullFailed PrePullState = "Failed"
+)
+
+// PrePullNodeState describes the progress of the pulling of an image onto a node.
+type PrePullNodeState struct {
+	// Image is the image pulled onto the node, by digest if it is known.
+	Image string `json:"image"`
+	// Node is the name of the node.
+	Node string `json:"node"`
+	// State of the pulling of the image onto the node.
+	State PrePullState `json:"state"`
 }
 
 type ModuleImageState struct {
@@ -99,6 +155,43 @@ type ModuleImageState struct {
 	// status of the image
 	// one of: Exists, notExists
 	Status ImageState `json:"status"`
+	// BuildInputsHash is the fingerprint of the build inputs used to build the image, if it was built in-cluster.
+	// +optional
+	BuildInputsHash string `json:"buildInputsHash,omitempty"`
+	// Digest is the digest of the image that was verified, if known.
+	// Nodes load the image by this digest, so that they all run the same bits even if the tag is pushed again.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+	// LastVerifiedTime is the last time the image was successfully pulled from the registry.
+	// +optional
+	LastVerifiedTime *metav1.Time `json:"lastVerifiedTime,omitempty"`
+	// VerificationFailures is the number of consecutive times the image could not be pulled from the registry since
+	// its status was last set.
+	// +optional
+	VerificationFailures int32 `json:"verificationFailures,omitempty"`
+	// LastVerificationFailureTime is the last time the image could not be pulled from the registry.
+	// +optional
+	LastVerificationFailureTime *metav1.Time `json:"lastVerificationFailureTime,omitempty"`
+	// KernelVersion is the kernel of the image, kept once the image is removed from the spec, if the garbage
+	// collection is enabled.
+	// +optional
+	KernelVersion string `json:"kernelVersion,omitempty"`
+	// UnusedSince is the time since which the image is not in the spec, no node runs its kernel and no
+	// PreflightValidation validates it, if the garbage collection is enabled.
+	// +optional
+	UnusedSince *metav1.Time `json:"unusedSince,omitempty"`
+	// LayoutFindings lists what is wrong with the content of the image if its status is InvalidLayout.
+	// +optional
+	LayoutFindings []string `json:"layoutFindings,omitempty"`
+}
+
+// CollectedImage describes an image collected by the garbage collection.
+type CollectedImage struct {
+	// Image is the collected image.
+	Image string `json:"image"`
+	// DeleteFromRegistry is true if the image is deleted from its registry as well.
+	// +optional
+	DeleteFromRegistry bool `json:"deleteFromRegistry,omitempty"`
 }
 
 // ModuleImagesConfigStatus describes the status of the images that need to be verified (defined in the spec)
@@ -112,6 +205,14 @@ type ModuleImagesConfigStatus struct {
 	// to trigger re-verification and potential rebuilds.
 	// +optional
 	ImageRebuildTriggerGeneration *int `json:"imageRebuildTriggerGeneration,omitempty"`
+
+	// PrePullStates reports the progress of the pulling of the images listed in spec.prePulls onto their nodes.
+	// +optional
+	PrePullStates []PrePullNodeState `json:"prePullStates,omitempty"`
+
+	// ImageGCDryRun lists the images that the garbage collection would collect if spec.imageGC.dryRun was not set.
+	// +optional
+	ImageGCDryRun []CollectedImage `json:"imageGCDryRun,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -26,11 +26,13 @@ import (
 )
 
 const (
-	VerificationSuccess    = v1beta2.VerificationSuccess
-	VerificationFailure    = v1beta2.VerificationFailure
-	VerificationInProgress = v1beta2.VerificationInProgress
-	VerificationStageImage = v1beta2.VerificationStageImage
-	VerificationStageDone  = v1beta2.VerificationStageDone
+	VerificationSuccess            = v1beta2.VerificationSuccess
+	VerificationFailure            = v1beta2.VerificationFailure
+	VerificationInProgress         = v1beta2.VerificationInProgress
+	VerificationUnverified         = v1beta2.VerificationUnverified
+	VerificationStageImage         = v1beta2.VerificationStageImage
+	VerificationStageCompatibility = v1beta2.VerificationStageCompatibility
+	VerificationStageDone          = v1beta2.VerificationStageDone
 )
 
 // PreflightValidationSpec describes the desired state of the resource, such as the kernel version
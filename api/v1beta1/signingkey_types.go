@@ -0,0 +1,113 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SigningKeySpec describes the key pair that KMM generates to sign kernel modules.
+type SigningKeySpec struct {
+	// +optional
+	// CommonName is the common name of the certificate's subject. Defaults to the name of the SigningKey.
+	CommonName string `json:"commonName,omitempty"`
+
+	// +optional
+	// +kubebuilder:default=4096
+	// +kubebuilder:validation:Enum=2048;3072;4096
+	// KeySize is the size in bits of the generated RSA key.
+	KeySize int32 `json:"keySize,omitempty"`
+
+	// +optional
+	// +kubebuilder:default="17520h"
+	// Validity is the duration for which the generated certificates are valid.
+	Validity metav1.Duration `json:"validity,omitempty"`
+
+	// +optional
+	// RenewBefore is the duration before the expiry of the certificate from which Warning events report that the key
+	// pair is due for rotation. The key pair is never rotated automatically, since the new certificate must be enrolled
+	// on the nodes before the kernel modules signed with it can be loaded: increase Rotation at a convenient time instead.
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+
+	// +optional
+	// Rotation is increased to rotate the key pair immediately.
+	Rotation int64 `json:"rotation,omitempty"`
+}
+
+// SigningKeyStatus describes the key pair currently held by the SigningKey.
+type SigningKeyStatus struct {
+	// +optional
+	// SecretName is the name of the Secret holding the private key (key) and the DER certificate (cert).
+	SecretName string `json:"secretName,omitempty"`
+
+	// +optional
+	// CertificateConfigMap is the name of the ConfigMap publishing the DER certificate (cert.der) to be enrolled
+	// with mokutil on the nodes.
+	CertificateConfigMap string `json:"certificateConfigMap,omitempty"`
+
+	// +optional
+	// NotBefore is the time from which the certificate is valid.
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+
+	// +optional
+	// NotAfter is the time at which the certificate expires.
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// +optional
+	// SerialNumber is the hexadecimal serial number of the certificate.
+	SerialNumber string `json:"serialNumber,omitempty"`
+
+	// +optional
+	// Fingerprint is the hexadecimal SHA-256 fingerprint of the DER certificate.
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// +optional
+	// Rotation is the last rotation requested in the spec that was handled.
+	Rotation int64 `json:"rotation,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// SigningKey is a key pair generated and rotated by KMM to sign kernel modules for Secure Boot.
+// +kubebuilder:resource:path=signingkeys,scope=Namespaced,shortName=sk
+// +kubebuilder:printcolumn:name="Fingerprint",type=string,JSONPath=`.status.fingerprint`
+// +kubebuilder:printcolumn:name="Expires",type=string,format=date-time,JSONPath=`.status.notAfter`
+// +operator-sdk:csv:customresourcedefinitions:displayName="Signing Key"
+type SigningKey struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SigningKeySpec   `json:"spec,omitempty"`
+	Status SigningKeyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SigningKeyList is a list of SigningKey objects.
+type SigningKeyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of SigningKey. More info:
+	// https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md
+	Items []SigningKey `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SigningKey{}, &SigningKeyList{})
+}
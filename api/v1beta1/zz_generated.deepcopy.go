@@ -23,6 +23,7 @@ package v1beta1
 import (
 	"k8s.io/api/core/v1"
 	resourcev1 "k8s.io/api/resource/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -151,6 +152,31 @@ func (in *Build) DeepCopyInto(out *Build) {
 			(*out)[key] = val
 		}
 	}
+	if in.ContextConfigMaps != nil {
+		in, out := &in.ContextConfigMaps, &out.ContextConfigMaps
+		*out = make([]BuildContextConfigMap, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContextSecrets != nil {
+		in, out := &in.ContextSecrets, &out.ContextSecrets
+		*out = make([]BuildContextSecret, len(*in))
+		copy(*out, *in)
+	}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitBuildSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogRetention != nil {
+		in, out := &in.LogRetention, &out.LogRetention
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Build.
@@ -178,9 +204,50 @@ func (in *BuildArg) DeepCopy() *BuildArg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildContextConfigMap) DeepCopyInto(out *BuildContextConfigMap) {
+	*out = *in
+	out.ConfigMap = in.ConfigMap
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildContextConfigMap.
+func (in *BuildContextConfigMap) DeepCopy() *BuildContextConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildContextConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildContextSecret) DeepCopyInto(out *BuildContextSecret) {
+	*out = *in
+	out.Secret = in.Secret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildContextSecret.
+func (in *BuildContextSecret) DeepCopy() *BuildContextSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildContextSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildSignImageState) DeepCopyInto(out *BuildSignImageState) {
 	*out = *in
+	if in.NextAttemptTime != nil {
+		in, out := &in.NextAttemptTime, &out.NextAttemptTime
+		*out = (*in).DeepCopy()
+	}
+	if in.SignedFiles != nil {
+		in, out := &in.SignedFiles, &out.SignedFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildSignImageState.
@@ -193,6 +260,21 @@ func (in *BuildSignImageState) DeepCopy() *BuildSignImageState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollectedImage) DeepCopyInto(out *CollectedImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollectedImage.
+func (in *CollectedImage) DeepCopy() *CollectedImage {
+	if in == nil {
+		return nil
+	}
+	out := new(CollectedImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonContainerSpec) DeepCopyInto(out *CommonContainerSpec) {
 	*out = *in
@@ -350,6 +432,62 @@ func (in *DevicePluginSpec) DeepCopy() *DevicePluginSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitBuildSource) DeepCopyInto(out *GitBuildSource) {
+	*out = *in
+	if in.SourceSecret != nil {
+		in, out := &in.SourceSecret, &out.SourceSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitBuildSource.
+func (in *GitBuildSource) DeepCopy() *GitBuildSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GitBuildSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageGCSpec) DeepCopyInto(out *ImageGCSpec) {
+	*out = *in
+	out.RetentionPeriod = in.RetentionPeriod
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageGCSpec.
+func (in *ImageGCSpec) DeepCopy() *ImageGCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageGCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePrePullSpec) DeepCopyInto(out *ImagePrePullSpec) {
+	*out = *in
+	if in.KernelVersions != nil {
+		in, out := &in.KernelVersions, &out.KernelVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullSpec.
+func (in *ImagePrePullSpec) DeepCopy() *ImagePrePullSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePrePullSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KanikoParams) DeepCopyInto(out *KanikoParams) {
 	*out = *in
@@ -365,6 +503,234 @@ func (in *KanikoParams) DeepCopy() *KanikoParams {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelBuilderCatalog) DeepCopyInto(out *KernelBuilderCatalog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelBuilderCatalog.
+func (in *KernelBuilderCatalog) DeepCopy() *KernelBuilderCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelBuilderCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KernelBuilderCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelBuilderCatalogList) DeepCopyInto(out *KernelBuilderCatalogList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KernelBuilderCatalog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelBuilderCatalogList.
+func (in *KernelBuilderCatalogList) DeepCopy() *KernelBuilderCatalogList {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelBuilderCatalogList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KernelBuilderCatalogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelBuilderCatalogSpec) DeepCopyInto(out *KernelBuilderCatalogSpec) {
+	*out = *in
+	if in.Entries != nil {
+		in, out := &in.Entries, &out.Entries
+		*out = make([]KernelBuilderEntry, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelBuilderCatalogSpec.
+func (in *KernelBuilderCatalogSpec) DeepCopy() *KernelBuilderCatalogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelBuilderCatalogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelBuilderEntry) DeepCopyInto(out *KernelBuilderEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelBuilderEntry.
+func (in *KernelBuilderEntry) DeepCopy() *KernelBuilderEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelBuilderEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelDTKMapping) DeepCopyInto(out *KernelDTKMapping) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelDTKMapping.
+func (in *KernelDTKMapping) DeepCopy() *KernelDTKMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelDTKMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KernelDTKMapping) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelDTKMappingList) DeepCopyInto(out *KernelDTKMappingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KernelDTKMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelDTKMappingList.
+func (in *KernelDTKMappingList) DeepCopy() *KernelDTKMappingList {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelDTKMappingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KernelDTKMappingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelDTKMappingSpec) DeepCopyInto(out *KernelDTKMappingSpec) {
+	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]KernelDTKOverride, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelDTKMappingSpec.
+func (in *KernelDTKMappingSpec) DeepCopy() *KernelDTKMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelDTKMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelDTKMappingStatus) DeepCopyInto(out *KernelDTKMappingStatus) {
+	*out = *in
+	if in.Kernels != nil {
+		in, out := &in.Kernels, &out.Kernels
+		*out = make([]KernelDTKStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelDTKMappingStatus.
+func (in *KernelDTKMappingStatus) DeepCopy() *KernelDTKMappingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelDTKMappingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelDTKOverride) DeepCopyInto(out *KernelDTKOverride) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelDTKOverride.
+func (in *KernelDTKOverride) DeepCopy() *KernelDTKOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelDTKOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelDTKStatus) DeepCopyInto(out *KernelDTKStatus) {
+	*out = *in
+	if in.UnusedSince != nil {
+		in, out := &in.UnusedSince, &out.UnusedSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelDTKStatus.
+func (in *KernelDTKStatus) DeepCopy() *KernelDTKStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelDTKStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KernelMapping) DeepCopyInto(out *KernelMapping) {
 	*out = *in
@@ -586,7 +952,13 @@ func (in *ModuleBuildSignConfigStatus) DeepCopyInto(out *ModuleBuildSignConfigSt
 	if in.Images != nil {
 		in, out := &in.Images, &out.Images
 		*out = make([]BuildSignImageState, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastBuildInputsCheckTime != nil {
+		in, out := &in.LastBuildInputsCheckTime, &out.LastBuildInputsCheckTime
+		*out = (*in).DeepCopy()
 	}
 }
 
@@ -655,6 +1027,11 @@ func (in *ModuleImageSpec) DeepCopyInto(out *ModuleImageSpec) {
 		*out = new(TLSOptions)
 		**out = **in
 	}
+	if in.ModuleNames != nil {
+		in, out := &in.ModuleNames, &out.ModuleNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleImageSpec.
@@ -670,6 +1047,23 @@ func (in *ModuleImageSpec) DeepCopy() *ModuleImageSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModuleImageState) DeepCopyInto(out *ModuleImageState) {
 	*out = *in
+	if in.LastVerifiedTime != nil {
+		in, out := &in.LastVerifiedTime, &out.LastVerifiedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastVerificationFailureTime != nil {
+		in, out := &in.LastVerificationFailureTime, &out.LastVerificationFailureTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UnusedSince != nil {
+		in, out := &in.UnusedSince, &out.UnusedSince
+		*out = (*in).DeepCopy()
+	}
+	if in.LayoutFindings != nil {
+		in, out := &in.LayoutFindings, &out.LayoutFindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleImageState.
@@ -768,6 +1162,18 @@ func (in *ModuleImagesConfigSpec) DeepCopyInto(out *ModuleImagesConfigSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PrePulls != nil {
+		in, out := &in.PrePulls, &out.PrePulls
+		*out = make([]PrePullSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImageGC != nil {
+		in, out := &in.ImageGC, &out.ImageGC
+		*out = new(ImageGCSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleImagesConfigSpec.
@@ -786,13 +1192,25 @@ func (in *ModuleImagesConfigStatus) DeepCopyInto(out *ModuleImagesConfigStatus)
 	if in.ImagesStates != nil {
 		in, out := &in.ImagesStates, &out.ImagesStates
 		*out = make([]ModuleImageState, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.ImageRebuildTriggerGeneration != nil {
 		in, out := &in.ImageRebuildTriggerGeneration, &out.ImageRebuildTriggerGeneration
 		*out = new(int)
 		**out = **in
 	}
+	if in.PrePullStates != nil {
+		in, out := &in.PrePullStates, &out.PrePullStates
+		*out = make([]PrePullNodeState, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageGCDryRun != nil {
+		in, out := &in.ImageGCDryRun, &out.ImageGCDryRun
+		*out = make([]CollectedImage, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleImagesConfigStatus.
@@ -961,6 +1379,16 @@ func (in *ModuleSpec) DeepCopyInto(out *ModuleSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.ImagePrePull != nil {
+		in, out := &in.ImagePrePull, &out.ImagePrePull
+		*out = new(ImagePrePullSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageGC != nil {
+		in, out := &in.ImageGC, &out.ImageGC
+		*out = new(ImageGCSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleSpec.
@@ -1133,6 +1561,61 @@ func (in *NodeModulesConfigStatus) DeepCopy() *NodeModulesConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PKCS11Key) DeepCopyInto(out *PKCS11Key) {
+	*out = *in
+	if in.ConfigSecret != nil {
+		in, out := &in.ConfigSecret, &out.ConfigSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PKCS11Key.
+func (in *PKCS11Key) DeepCopy() *PKCS11Key {
+	if in == nil {
+		return nil
+	}
+	out := new(PKCS11Key)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrePullNodeState) DeepCopyInto(out *PrePullNodeState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrePullNodeState.
+func (in *PrePullNodeState) DeepCopy() *PrePullNodeState {
+	if in == nil {
+		return nil
+	}
+	out := new(PrePullNodeState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrePullSpec) DeepCopyInto(out *PrePullSpec) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrePullSpec.
+func (in *PrePullSpec) DeepCopy() *PrePullSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrePullSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PreflightValidation) DeepCopyInto(out *PreflightValidation) {
 	*out = *in
@@ -1282,6 +1765,36 @@ func (in *PreflightValidationStatus) DeepCopy() *PreflightValidationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sign) DeepCopyInto(out *Sign) {
 	*out = *in
@@ -1291,6 +1804,21 @@ func (in *Sign) DeepCopyInto(out *Sign) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.PKCS11 != nil {
+		in, out := &in.PKCS11, &out.PKCS11
+		*out = new(PKCS11Key)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SigningService != nil {
+		in, out := &in.SigningService, &out.SigningService
+		*out = new(SigningService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SigningKey != nil {
+		in, out := &in.SigningKey, &out.SigningKey
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 	if in.CertSecret != nil {
 		in, out := &in.CertSecret, &out.CertSecret
 		*out = new(v1.LocalObjectReference)
@@ -1301,6 +1829,16 @@ func (in *Sign) DeepCopyInto(out *Sign) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AutoDiscover != nil {
+		in, out := &in.AutoDiscover, &out.AutoDiscover
+		*out = new(SignAutoDiscovery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sign.
@@ -1313,6 +1851,154 @@ func (in *Sign) DeepCopy() *Sign {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SignAutoDiscovery) DeepCopyInto(out *SignAutoDiscovery) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SignAutoDiscovery.
+func (in *SignAutoDiscovery) DeepCopy() *SignAutoDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(SignAutoDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SigningKey) DeepCopyInto(out *SigningKey) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKey.
+func (in *SigningKey) DeepCopy() *SigningKey {
+	if in == nil {
+		return nil
+	}
+	out := new(SigningKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SigningKey) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SigningKeyList) DeepCopyInto(out *SigningKeyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SigningKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKeyList.
+func (in *SigningKeyList) DeepCopy() *SigningKeyList {
+	if in == nil {
+		return nil
+	}
+	out := new(SigningKeyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SigningKeyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SigningKeySpec) DeepCopyInto(out *SigningKeySpec) {
+	*out = *in
+	out.Validity = in.Validity
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKeySpec.
+func (in *SigningKeySpec) DeepCopy() *SigningKeySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SigningKeySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SigningKeyStatus) DeepCopyInto(out *SigningKeyStatus) {
+	*out = *in
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKeyStatus.
+func (in *SigningKeyStatus) DeepCopy() *SigningKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SigningKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SigningService) DeepCopyInto(out *SigningService) {
+	*out = *in
+	if in.TLSSecret != nil {
+		in, out := &in.TLSSecret, &out.TLSSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningService.
+func (in *SigningService) DeepCopy() *SigningService {
+	if in == nil {
+		return nil
+	}
+	out := new(SigningService)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSOptions) DeepCopyInto(out *TLSOptions) {
 	*out = *in
@@ -21,11 +21,13 @@ import (
 )
 
 const (
-	VerificationSuccess    string = "Success"
-	VerificationFailure    string = "Failure"
-	VerificationInProgress string = "InProgress"
-	VerificationStageImage string = "Image"
-	VerificationStageDone  string = "Done"
+	VerificationSuccess            string = "Success"
+	VerificationFailure            string = "Failure"
+	VerificationInProgress         string = "InProgress"
+	VerificationUnverified         string = "Unverified"
+	VerificationStageImage         string = "Image"
+	VerificationStageCompatibility string = "Compatibility"
+	VerificationStageDone          string = "Done"
 )
 
 // PreflightValidationSpec describes the desired state of the resource, such as the kernel version
@@ -48,7 +50,7 @@ type CRBaseStatus struct {
 	// error (error during verification process), unknown (verification has not started yet)
 	// +required
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=Success;Failure;InProgress
+	// +kubebuilder:validation:Enum=Success;Failure;InProgress;Unverified
 	VerificationStatus string `json:"verificationStatus"`
 
 	// StatusReason contains a string describing the status source.
@@ -56,10 +58,11 @@ type CRBaseStatus struct {
 	StatusReason string `json:"statusReason,omitempty"`
 
 	// Current stage of the verification process:
-	// image (image existence verification), build(build process verification)
+	// image (image existence verification), build(build process verification),
+	// compatibility (verification of the kernel modules against the kernel)
 	// +required
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=Image;Done
+	// +kubebuilder:validation:Enum=Image;Compatibility;Done
 	VerificationStage string `json:"verificationStage"`
 
 	// LastTransitionTime is the last time the CR status transitioned from one status to another.
@@ -0,0 +1,109 @@
+// signing-service-stub is a minimal implementation of the external signing service used by Sign.SigningService.
+// It signs each request body with a local key and certificate and replies with the DER-encoded PKCS#7 detached
+// signature, in the format produced by sign-file.
+// It is meant for testing only: the private key is read from the local filesystem.
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+)
+
+const maxModuleSize = 512 * 1024 * 1024
+
+func main() {
+	var (
+		addr      string
+		certFile  string
+		keyFile   string
+		tlsCert   string
+		tlsKey    string
+		clientCAs string
+	)
+
+	flag.StringVar(&addr, "addr", ":8443", "address to listen on")
+	flag.StringVar(&certFile, "sign-cert", "signing-cert.pem", "certificate used to sign kernel modules")
+	flag.StringVar(&keyFile, "sign-key", "signing-key.pem", "private key used to sign kernel modules")
+	flag.StringVar(&tlsCert, "tls-cert", "", "server certificate")
+	flag.StringVar(&tlsKey, "tls-key", "", "server private key")
+	flag.StringVar(&clientCAs, "client-ca", "", "if set, require client certificates signed by this CA (mutual TLS)")
+	flag.Parse()
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: &signHandler{certFile: certFile, keyFile: keyFile},
+	}
+
+	if clientCAs != "" {
+		pem, err := os.ReadFile(clientCAs)
+		if err != nil {
+			log.Fatalf("could not read the client CA: %v", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			log.Fatalf("no certificate found in %s", clientCAs)
+		}
+
+		server.TLSConfig = &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	log.Printf("listening on %s", addr)
+	log.Fatal(server.ListenAndServeTLS(tlsCert, tlsKey))
+}
+
+type signHandler struct {
+	certFile string
+	keyFile  string
+}
+
+func (h *signHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	f, err := os.CreateTemp("", "module-*.ko")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err = io.Copy(f, io.LimitReader(r.Body, maxModuleSize)); err != nil {
+		http.Error(w, fmt.Sprintf("could not read the request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// same CMS options as sign-file: detached, binary, no signed attributes and no embedded certificates
+	cmd := exec.CommandContext(r.Context(), "openssl", "cms", "-sign", "-binary", "-noattr", "-nocerts", "-nosmimecap",
+		"-md", "sha256", "-outform", "DER", "-signer", h.certFile, "-inkey", h.keyFile, "-in", f.Name())
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err = cmd.Run(); err != nil {
+		log.Printf("could not sign the module: %v: %s", err, stderr.String())
+		http.Error(w, "could not sign the module", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pkcs7-signature")
+	if _, err = w.Write(stdout.Bytes()); err != nil {
+		log.Printf("could not write the signature: %v", err)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 
 	buildv1 "github.com/openshift/api/build/v1"
+	configv1 "github.com/openshift/api/config/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/config"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mbsc"
@@ -30,9 +31,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog/v2/textlogger"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	workv1 "open-cluster-management.io/api/work/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,18 +47,23 @@ import (
 
 	hubv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildercatalog"
 	buildsignresource "github.com/rh-ecosystem-edge/kernel-module-management/internal/buildsign/resource"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/cluster"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/cmd"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/controllers"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/controllers/hub"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/dtkmapping"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/filter"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/git"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/manifestwork"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/metrics"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/networkpolicy"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/nmc"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/registry"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/signingkey"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/statusupdater"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/syncronizedmap"
 
@@ -75,7 +83,9 @@ func init() {
 	utilruntime.Must(hubv1beta1.AddToScheme(scheme))
 	utilruntime.Must(kmmv1beta1.AddToScheme(scheme))
 	utilruntime.Must(buildv1.Install(scheme))
+	utilruntime.Must(configv1.Install(scheme))
 	utilruntime.Must(clusterv1.Install(scheme))
+	utilruntime.Must(clusterv1beta1.Install(scheme))
 	utilruntime.Must(imagev1.Install(scheme))
 	utilruntime.Must(workv1.Install(scheme))
 	//+kubebuilder:scaffold:scheme
@@ -128,12 +138,19 @@ func main() {
 	metricsAPI.Register()
 
 	buildArgOverrider := module.NewBuildArgOverrider()
-	resourceManager := buildsignresource.NewResourceManager(client, buildArgOverrider, kernelOsDtkMapping, scheme)
+	builderCatalogAPI := buildercatalog.NewForManagedClusters(client)
+	dtkMappingAPI := dtkmapping.New(client, kernelOsDtkMapping)
+	registryAPI := registry.NewRegistry(client)
+	gitAPI := git.NewGit(client)
+	resourceManager := buildsignresource.NewResourceManager(client, buildArgOverrider, dtkMappingAPI, builderCatalogAPI,
+		registryAPI, gitAPI, scheme, cfg.Job.SigningServiceURL)
 
 	micAPI := mic.New(client, scheme)
 	mbscAPI := mbsc.New(client, scheme)
 	imagePullerAPI := pod.NewImagePuller(client, scheme)
-	builSignAPI := buildsign.NewManager(client, resourceManager, scheme)
+	buildSignLimiter := buildsign.NewLimiter(client, resourceManager, cfg.Job.MaxConcurrentBuilds,
+		cfg.Job.MaxConcurrentBuildsPerNamespace)
+	builSignAPI := buildsign.NewManager(client, resourceManager, buildSignLimiter, scheme)
 	networkPolicyAPI := networkpolicy.NewNetworkPolicy(client, scheme)
 
 	kernelAPI := module.NewKernelMapper(buildArgOverrider)
@@ -141,21 +158,26 @@ func main() {
 	ctrlLogger := setupLogger.WithValues("name", hub.ManagedClusterModuleReconcilerName)
 	ctrlLogger.Info("Adding controller")
 
+	clusterAPI := cluster.NewClusterAPI(client, kernelAPI, operatorNamespace)
+
 	mcmr := hub.NewManagedClusterModuleReconciler(
 		client,
 		manifestwork.NewCreator(client, scheme, kernelAPI, operatorNamespace),
-		cluster.NewClusterAPI(client, kernelAPI, operatorNamespace),
+		clusterAPI,
 		statusupdater.NewManagedClusterModuleStatusUpdater(client),
 		filterAPI,
 		micAPI,
 		networkPolicyAPI,
 	)
 
-	if err = controllers.NewMICReconciler(client, micAPI, mbscAPI, imagePullerAPI, scheme).SetupWithManager(mgr); err != nil {
+	eventRecorder := mgr.GetEventRecorderFor("kmm-hub")
+
+	if err = controllers.NewMICReconciler(client, micAPI, mbscAPI, imagePullerAPI, registryAPI, eventRecorder, scheme,
+		cfg.Job.ImageVerificationInterval).SetupWithManager(mgr); err != nil {
 		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.MICReconcilerName)
 	}
 
-	if err = controllers.NewMBSCReconciler(client, builSignAPI, mbscAPI).SetupWithManager(mgr); err != nil {
+	if err = controllers.NewMBSCReconciler(client, builSignAPI, mbscAPI, cfg.Job.BuildInputsCheckInterval).SetupWithManager(mgr); err != nil {
 		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.MBSCReconcilerName)
 	}
 
@@ -163,6 +185,11 @@ func main() {
 		cmd.FatalError(ctrlLogger, err, "unable to create controller")
 	}
 
+	if err = hub.NewManagedClusterPreflightValidationReconciler(client, clusterAPI, filterAPI, micAPI).
+		SetupWithManager(mgr); err != nil {
+		cmd.FatalError(setupLogger, err, "unable to create controller", "name", hub.ManagedClusterPreflightValidationReconcilerName)
+	}
+
 	dtkNSN := types.NamespacedName{
 		Namespace: constants.DTKImageStreamNamespace,
 		Name:      "driver-toolkit",
@@ -175,14 +202,27 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = controllers.NewKernelDTKMappingReconciler(client, dtkNSN).SetupWithManager(mgr); err != nil {
+		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.KernelDTKMappingReconcilerName)
+	}
+
 	if err = controllers.NewKernelDTKReconciler(client, kernelOsDtkMapping).SetupWithManager(mgr); err != nil {
 		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.KernelDTKReconcilerName)
 	}
 
-	eventRecorder := mgr.GetEventRecorderFor("kmm-hub")
 	jobEventReconcilerHelper := controllers.NewJobEventReconcilerHelper(client)
 
-	if err = controllers.NewBuildSignEventsReconciler(client, jobEventReconcilerHelper, eventRecorder).SetupWithManager(mgr); err != nil {
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		cmd.FatalError(setupLogger, err, "unable to create the Kubernetes clientset")
+	}
+
+	podLogReader := buildsign.NewPodLogReader(clientset)
+	logArchiver := buildsign.NewLogArchiver(client, podLogReader, scheme, cfg.Job.LogTailLines, cfg.Job.LogRetentionPerKernel)
+	signedFilesReader := buildsign.NewSignedFilesReader(podLogReader)
+
+	if err = controllers.NewBuildSignEventsReconciler(client, jobEventReconcilerHelper, logArchiver, signedFilesReader, eventRecorder).
+		SetupWithManager(mgr); err != nil {
 		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.BuildSignEventsReconcilerName)
 	}
 
@@ -190,6 +230,11 @@ func main() {
 		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.JobGCReconcilerName)
 	}
 
+	if err = controllers.NewSigningKeyReconciler(client, signingkey.NewGenerator(), metricsAPI, eventRecorder, scheme).
+		SetupWithManager(mgr); err != nil {
+		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.SigningKeyReconcilerName)
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err = mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildercatalog"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildsign"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mbsc"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mic"
@@ -31,6 +32,7 @@ import (
 	"strconv"
 
 	buildv1 "github.com/openshift/api/build/v1"
+	configv1 "github.com/openshift/api/config/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	apimcfgv1 "github.com/openshift/api/machineconfiguration/v1"
 	apioperatorv1 "github.com/openshift/api/operator/v1"
@@ -41,12 +43,16 @@ import (
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/config"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/controllers"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/dtkmapping"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/filter"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/git"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mcfg"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/metrics"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/networkpolicy"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/nmc"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/registry"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/signingkey"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/syncronizedmap"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/version"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -55,6 +61,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog/v2/textlogger"
 	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
@@ -81,6 +88,7 @@ func init() {
 	utilruntime.Must(v1beta2.AddToScheme(scheme))
 	utilruntime.Must(resourcev1.AddToScheme(scheme))
 	utilruntime.Must(buildv1.Install(scheme))
+	utilruntime.Must(configv1.Install(scheme))
 	utilruntime.Must(imagev1.Install(scheme))
 	utilruntime.Must(apimcfgv1.Install(scheme))
 	utilruntime.Must(apioperatorv1.Install(scheme))
@@ -151,7 +159,12 @@ func main() {
 	metricsAPI.Register()
 
 	buildArgOverriderAPI := module.NewBuildArgOverrider()
-	resourceManager := buildsignresource.NewResourceManager(client, buildArgOverriderAPI, kernelOsDtkMapping, scheme)
+	builderCatalogAPI := buildercatalog.New(client)
+	dtkMappingAPI := dtkmapping.New(client, kernelOsDtkMapping)
+	registryAPI := registry.NewRegistry(client)
+	gitAPI := git.NewGit(client)
+	resourceManager := buildsignresource.NewResourceManager(client, buildArgOverriderAPI, dtkMappingAPI, builderCatalogAPI,
+		registryAPI, gitAPI, scheme, cfg.Job.SigningServiceURL)
 	nodeAPI := node.NewNode(client)
 	kernelAPI := module.NewKernelMapper(buildArgOverriderAPI)
 	micAPI := mic.New(client, scheme)
@@ -220,7 +233,8 @@ func main() {
 		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.NodeLabelModuleVersionReconcilerName)
 	}
 
-	if err = controllers.NewMICReconciler(client, micAPI, mbscAPI, imagePullerAPI, scheme).SetupWithManager(mgr); err != nil {
+	if err = controllers.NewMICReconciler(client, micAPI, mbscAPI, imagePullerAPI, registryAPI, eventRecorder, scheme,
+		cfg.Job.ImageVerificationInterval).SetupWithManager(mgr); err != nil {
 		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.MICReconcilerName)
 	}
 
@@ -248,16 +262,28 @@ func main() {
 			cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.NodeKernelClusterClaimReconcilerName)
 		}
 	} else {
-		builSignAPI := buildsign.NewManager(client, resourceManager, scheme)
+		buildSignLimiter := buildsign.NewLimiter(client, resourceManager, cfg.Job.MaxConcurrentBuilds,
+			cfg.Job.MaxConcurrentBuildsPerNamespace)
+		builSignAPI := buildsign.NewManager(client, resourceManager, buildSignLimiter, scheme)
 
-		mbscr := controllers.NewMBSCReconciler(client, builSignAPI, mbscAPI)
+		mbscr := controllers.NewMBSCReconciler(client, builSignAPI, mbscAPI, cfg.Job.BuildInputsCheckInterval)
 		if err = mbscr.SetupWithManager(mgr); err != nil {
 			cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.MBSCReconcilerName)
 		}
 
 		helper := controllers.NewJobEventReconcilerHelper(client)
 
-		if err = controllers.NewBuildSignEventsReconciler(client, helper, eventRecorder).SetupWithManager(mgr); err != nil {
+		clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			cmd.FatalError(setupLogger, err, "unable to create the Kubernetes clientset")
+		}
+
+		podLogReader := buildsign.NewPodLogReader(clientset)
+		logArchiver := buildsign.NewLogArchiver(client, podLogReader, scheme, cfg.Job.LogTailLines, cfg.Job.LogRetentionPerKernel)
+		signedFilesReader := buildsign.NewSignedFilesReader(podLogReader)
+
+		if err = controllers.NewBuildSignEventsReconciler(client, helper, logArchiver, signedFilesReader, eventRecorder).
+			SetupWithManager(mgr); err != nil {
 			cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.BuildSignEventsReconcilerName)
 		}
 
@@ -265,9 +291,16 @@ func main() {
 			cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.JobGCReconcilerName)
 		}
 
+		if err = controllers.NewSigningKeyReconciler(client, signingkey.NewGenerator(), metricsAPI, eventRecorder, scheme).
+			SetupWithManager(mgr); err != nil {
+			cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.SigningKeyReconcilerName)
+		}
+
 		preflightAPI := preflight.NewPreflightAPI()
+		compatibilityCheckerAPI := pod.NewCompatibilityChecker(client, scheme)
 
-		if err = controllers.NewPreflightValidationReconciler(client, filterAPI, metricsAPI, micAPI, kernelAPI, preflightAPI).SetupWithManager(mgr); err != nil {
+		if err = controllers.NewPreflightValidationReconciler(client, filterAPI, metricsAPI, micAPI, kernelAPI, preflightAPI,
+			compatibilityCheckerAPI, resourceManager).SetupWithManager(mgr); err != nil {
 			cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.PreflightValidationReconcilerName)
 		}
 
@@ -287,6 +320,10 @@ func main() {
 		cmd.FatalError(setupLogger, err, "unable to create controller", "controller", controllers.ImageStreamReconcilerName)
 	}
 
+	if err = controllers.NewKernelDTKMappingReconciler(client, dtkNSN).SetupWithManager(mgr); err != nil {
+		cmd.FatalError(setupLogger, err, "unable to create controller", "name", controllers.KernelDTKMappingReconcilerName)
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err = mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -40,6 +40,7 @@ func main() {
 	logConfig.AddFlags(flag.CommandLine)
 
 	var (
+		enableKernelBuilderCatalog bool
 		enableModule               bool
 		enableManagedClusterModule bool
 		enableNamespaceDeletion    bool
@@ -48,6 +49,7 @@ func main() {
 	)
 
 	flag.StringVar(&userConfigMapName, "config", "", "Name of the ConfigMap containing user config.")
+	flag.BoolVar(&enableKernelBuilderCatalog, "enable-kernelbuildercatalog", false, "Enable the webhook for KernelBuilderCatalog resources")
 	flag.BoolVar(&enableModule, "enable-module", false, "Enable the webhook for Module resources")
 	flag.BoolVar(&enableManagedClusterModule, "enable-managedclustermodule", false, "Enable the webhook for ManagedClusterModule resources")
 	flag.BoolVar(&enableNamespaceDeletion, "enable-namespace", false, "Enable the webhook for Namespace deletion")
@@ -104,6 +106,14 @@ func main() {
 		}
 	}
 
+	if enableKernelBuilderCatalog {
+		logger.Info("Enabling KernelBuilderCatalog webhook")
+
+		if err = webhook.NewKernelBuilderCatalogValidator(logger).SetupWebhookWithManager(mgr); err != nil {
+			cmd.FatalError(setupLogger, err, "unable to create webhook", "webhook", "KernelBuilderCatalogValidator")
+		}
+	}
+
 	if enableNamespaceDeletion {
 		logger.Info("Enabling Namespace deletion webhook")
 
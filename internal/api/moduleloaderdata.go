@@ -1,6 +1,8 @@
 package api
 
 import (
+	"slices"
+
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -66,6 +68,16 @@ type ModuleLoaderData struct {
 	// If specified, the pod's tolerations.
 	// +optional
 	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// BuildPriority orders the queued builds and signs; higher values are started first.
+	BuildPriority int32
+
+	// ImagePrePull, if set, requires the image to be pulled onto a node before the module is loaded there.
+	ImagePrePull *kmmv1beta1.ImagePrePullSpec
+
+	// Attempt is the number of previous failed attempts of the build or sign.
+	// Resources created for different attempts are considered different, so a retry replaces the failed resource.
+	Attempt int32
 }
 
 func (mld *ModuleLoaderData) NamespacedName() types.NamespacedName {
@@ -74,3 +86,20 @@ func (mld *ModuleLoaderData) NamespacedName() types.NamespacedName {
 		Namespace: mld.Namespace,
 	}
 }
+
+// KernelModuleNames returns the names of the kernel modules that modprobe loads from the image, or nil if they are not
+// known because modprobe is passed raw arguments.
+func (mld *ModuleLoaderData) KernelModuleNames() []string {
+	if mld.Modprobe.RawArgs != nil || mld.Modprobe.ModuleName == "" {
+		return nil
+	}
+
+	names := []string{mld.Modprobe.ModuleName}
+	for _, name := range mld.Modprobe.ModulesLoadingOrder {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
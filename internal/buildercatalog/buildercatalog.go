@@ -0,0 +1,222 @@
+package buildercatalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/cluster"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+//go:generate mockgen -source=buildercatalog.go -package=buildercatalog -destination=mock_buildercatalog.go
+
+type BuilderCatalog interface {
+	GetImage(ctx context.Context, kernelVersion string) (string, error)
+}
+
+type builderCatalog struct {
+	client         client.Client
+	osImagesGetter func(ctx context.Context, kernelVersion string) ([]string, error)
+}
+
+func New(client client.Client) BuilderCatalog {
+	bc := &builderCatalog{client: client}
+	bc.osImagesGetter = bc.getOSImages
+	return bc
+}
+
+// NewForManagedClusters returns a BuilderCatalog that matches the OS images of the nodes of the managed cluster the
+// image is built for, as claimed by KMM on the Spoke, instead of the ones of the local nodes.
+// The images must be looked up with a context returned by WithModuleImagesConfig.
+func NewForManagedClusters(client client.Client) BuilderCatalog {
+	bc := &builderCatalog{client: client}
+	bc.osImagesGetter = bc.getManagedClusterOSImages
+	return bc
+}
+
+type moduleImagesConfigKey struct{}
+
+// WithModuleImagesConfig returns a context in which the builder images are looked up for the images of the
+// ModuleImagesConfig nsn. On the Hub, it determines the managed cluster whose OS images are matched.
+func WithModuleImagesConfig(ctx context.Context, nsn types.NamespacedName) context.Context {
+	return context.WithValue(ctx, moduleImagesConfigKey{}, nsn)
+}
+
+// GetImage returns the builder image of the first KernelBuilderCatalog entry matching kernelVersion, or an empty
+// string if none matches. Catalogs are evaluated in the alphabetical order of their names.
+func (bc *builderCatalog) GetImage(ctx context.Context, kernelVersion string) (string, error) {
+	catalogList := kmmv1beta1.KernelBuilderCatalogList{}
+	if err := bc.client.List(ctx, &catalogList); err != nil {
+		return "", fmt.Errorf("failed to list KernelBuilderCatalogs: %v", err)
+	}
+
+	catalogs := catalogList.Items
+	sort.Slice(catalogs, func(i, j int) bool {
+		return catalogs[i].Name < catalogs[j].Name
+	})
+
+	// the OS images are only looked up if an entry needs them
+	var osImages []string
+
+	for _, catalog := range catalogs {
+		entries, err := compileEntries(&catalog)
+		if err != nil {
+			return "", fmt.Errorf("invalid KernelBuilderCatalog %s: %v", catalog.Name, err)
+		}
+
+		for _, entry := range entries {
+			if entry.kernelVersionRegexp != nil && !entry.kernelVersionRegexp.MatchString(kernelVersion) {
+				continue
+			}
+
+			if entry.osImageRegexp != nil {
+				if osImages == nil {
+					if osImages, err = bc.osImagesGetter(ctx, kernelVersion); err != nil {
+						return "", err
+					}
+				}
+				if !matchesAny(entry.osImageRegexp, osImages) {
+					continue
+				}
+			}
+
+			return replaceKernelVariables(entry.builderImage, kernelVersion)
+		}
+	}
+
+	return "", nil
+}
+
+// Validate checks that the regular expressions of all the entries of catalog compile.
+func Validate(catalog *kmmv1beta1.KernelBuilderCatalog) error {
+	_, err := compileEntries(catalog)
+	return err
+}
+
+type compiledEntry struct {
+	kernelVersionRegexp *regexp.Regexp
+	osImageRegexp       *regexp.Regexp
+	builderImage        string
+}
+
+// compileEntries compiles the regular expressions of the entries of catalog; the expressions that are not set are nil.
+func compileEntries(catalog *kmmv1beta1.KernelBuilderCatalog) ([]compiledEntry, error) {
+	entries := make([]compiledEntry, 0, len(catalog.Spec.Entries))
+
+	for i, entry := range catalog.Spec.Entries {
+		ce := compiledEntry{builderImage: entry.BuilderImage}
+
+		if entry.KernelVersionRegexp != "" {
+			re, err := regexp.Compile(entry.KernelVersionRegexp)
+			if err != nil {
+				return nil, fmt.Errorf("entries[%d].kernelVersionRegexp %q is not a valid regular expression: %v",
+					i, entry.KernelVersionRegexp, err)
+			}
+			ce.kernelVersionRegexp = re
+		}
+
+		if entry.OSImageRegexp != "" {
+			re, err := regexp.Compile(entry.OSImageRegexp)
+			if err != nil {
+				return nil, fmt.Errorf("entries[%d].osImageRegexp %q is not a valid regular expression: %v",
+					i, entry.OSImageRegexp, err)
+			}
+			ce.osImageRegexp = re
+		}
+
+		entries = append(entries, ce)
+	}
+
+	return entries, nil
+}
+
+// getOSImages returns the OS images of the nodes running kernelVersion.
+func (bc *builderCatalog) getOSImages(ctx context.Context, kernelVersion string) ([]string, error) {
+	nodeList := v1.NodeList{}
+	if err := bc.client.List(ctx, &nodeList); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+
+	osImages := make([]string, 0)
+	for _, node := range nodeList.Items {
+		if strings.TrimSuffix(node.Status.NodeInfo.KernelVersion, "+") == kernelVersion {
+			osImages = append(osImages, node.Status.NodeInfo.OSImage)
+		}
+	}
+	return osImages, nil
+}
+
+// getManagedClusterOSImages returns the OS images of the nodes running kernelVersion in the managed cluster that the
+// ModuleImagesConfig of ctx was created for. The ModuleImagesConfig of a ManagedClusterModule is named after it and
+// the cluster.
+func (bc *builderCatalog) getManagedClusterOSImages(ctx context.Context, kernelVersion string) ([]string, error) {
+	nsn, ok := ctx.Value(moduleImagesConfigKey{}).(types.NamespacedName)
+	if !ok {
+		return nil, errors.New("the ModuleImagesConfig of the image is not known")
+	}
+
+	micObj := kmmv1beta1.ModuleImagesConfig{}
+	if err := bc.client.Get(ctx, nsn, &micObj); err != nil {
+		return nil, fmt.Errorf("failed to get ModuleImagesConfig %s: %v", nsn, err)
+	}
+
+	owner := metav1.GetControllerOf(&micObj)
+	if owner == nil || owner.Kind != "ManagedClusterModule" {
+		return nil, fmt.Errorf("ModuleImagesConfig %s is not owned by a ManagedClusterModule", nsn)
+	}
+
+	clusterName, ok := strings.CutPrefix(micObj.Name, owner.Name+"-")
+	if !ok {
+		return nil, fmt.Errorf("ModuleImagesConfig %s is not named after its ManagedClusterModule %s", nsn, owner.Name)
+	}
+
+	mc := clusterv1.ManagedCluster{}
+	if err := bc.client.Get(ctx, types.NamespacedName{Name: clusterName}, &mc); err != nil {
+		return nil, fmt.Errorf("failed to get ManagedCluster %s: %v", clusterName, err)
+	}
+
+	kernels, err := cluster.ManagedClusterKernels(mc)
+	if err != nil {
+		log.FromContext(ctx).V(1).Info("No OS images claimed by the ManagedCluster", "name", mc.Name, "reason", err.Error())
+		return []string{}, nil
+	}
+
+	return kernels.OSImages(kernelVersion), nil
+}
+
+func matchesAny(re *regexp.Regexp, values []string) bool {
+	for _, v := range values {
+		if re.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func replaceKernelVariables(image, kernelVersion string) (string, error) {
+	if !strings.Contains(image, "$") {
+		return image, nil
+	}
+
+	envvars, err := utils.KernelComponentsAsEnvVars(kernelVersion)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the kernel components of %s: %v", kernelVersion, err)
+	}
+
+	replaced, err := utils.ReplaceInTemplates(envvars, image)
+	if err != nil {
+		return "", fmt.Errorf("failed to substitute the kernel variables in builder image %s: %v", image, err)
+	}
+	return replaced[0], nil
+}
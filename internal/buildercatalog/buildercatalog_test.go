@@ -0,0 +1,331 @@
+package buildercatalog
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+)
+
+var _ = Describe("GetImage", func() {
+	const kernelVersion = "6.8.0-45-generic"
+
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		bc   BuilderCatalog
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		bc = New(clnt)
+	})
+
+	ctx := context.Background()
+
+	catalog := func(name string, entries ...kmmv1beta1.KernelBuilderEntry) kmmv1beta1.KernelBuilderCatalog {
+		return kmmv1beta1.KernelBuilderCatalog{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       kmmv1beta1.KernelBuilderCatalogSpec{Entries: entries},
+		}
+	}
+
+	expectCatalogs := func(catalogs ...kmmv1beta1.KernelBuilderCatalog) *gomock.Call {
+		return clnt.EXPECT().List(ctx, &kmmv1beta1.KernelBuilderCatalogList{}).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.KernelBuilderCatalogList, _ ...ctrlclient.ListOption) error {
+				list.Items = catalogs
+				return nil
+			},
+		)
+	}
+
+	expectNodes := func(nodes ...v1.Node) *gomock.Call {
+		return clnt.EXPECT().List(ctx, &v1.NodeList{}).DoAndReturn(
+			func(_ interface{}, list *v1.NodeList, _ ...ctrlclient.ListOption) error {
+				list.Items = nodes
+				return nil
+			},
+		)
+	}
+
+	node := func(kernelVersion, osImage string) v1.Node {
+		return v1.Node{
+			Status: v1.NodeStatus{
+				NodeInfo: v1.NodeSystemInfo{KernelVersion: kernelVersion, OSImage: osImage},
+			},
+		}
+	}
+
+	It("should return an error if the catalogs could not be listed", func() {
+		clnt.EXPECT().List(ctx, gomock.Any()).Return(errors.New("random error"))
+
+		_, err := bc.GetImage(ctx, kernelVersion)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an empty image if no entry matches", func() {
+		expectCatalogs(
+			catalog("ubuntu", kmmv1beta1.KernelBuilderEntry{KernelVersionRegexp: `^5\.`, BuilderImage: "some-image"}),
+		)
+
+		image, err := bc.GetImage(ctx, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image).To(BeEmpty())
+	})
+
+	It("should return the image of the first matching entry, in the order of the catalog names", func() {
+		expectCatalogs(
+			catalog("b",
+				kmmv1beta1.KernelBuilderEntry{KernelVersionRegexp: `-generic$`, BuilderImage: "b-image"},
+			),
+			catalog("a",
+				kmmv1beta1.KernelBuilderEntry{KernelVersionRegexp: `^5\.`, BuilderImage: "a-image-1"},
+				kmmv1beta1.KernelBuilderEntry{KernelVersionRegexp: `^6\.`, BuilderImage: "a-image-2"},
+			),
+		)
+
+		image, err := bc.GetImage(ctx, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image).To(Equal("a-image-2"))
+	})
+
+	It("should replace the kernel variables in the builder image", func() {
+		expectCatalogs(
+			catalog("ubuntu", kmmv1beta1.KernelBuilderEntry{
+				KernelVersionRegexp: `-generic$`,
+				BuilderImage:        "quay.io/example/builder:${KERNEL_FULL_VERSION}-${KERNEL_X}.${KERNEL_Y}",
+			}),
+		)
+
+		image, err := bc.GetImage(ctx, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image).To(Equal("quay.io/example/builder:6.8.0-45-generic-6.8"))
+	})
+
+	It("should match the OS images of the nodes running the kernel", func() {
+		gomock.InOrder(
+			expectCatalogs(
+				catalog("distros",
+					kmmv1beta1.KernelBuilderEntry{OSImageRegexp: `^SUSE`, BuilderImage: "suse-image"},
+					kmmv1beta1.KernelBuilderEntry{OSImageRegexp: `^Ubuntu 24\.04`, BuilderImage: "ubuntu-image"},
+				),
+			),
+			expectNodes(
+				node("6.4.0-150600.23.25-default", "SUSE Linux Enterprise Server 15 SP6"),
+				node(kernelVersion+"+", "Ubuntu 24.04.1 LTS"),
+			),
+		)
+
+		image, err := bc.GetImage(ctx, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image).To(Equal("ubuntu-image"))
+	})
+
+	It("should require both the kernel version and the OS image to match when both are set", func() {
+		gomock.InOrder(
+			expectCatalogs(
+				catalog("ubuntu", kmmv1beta1.KernelBuilderEntry{
+					KernelVersionRegexp: `-generic$`,
+					OSImageRegexp:       `^Ubuntu 22\.04`,
+					BuilderImage:        "ubuntu-image",
+				}),
+			),
+			expectNodes(node(kernelVersion, "Ubuntu 24.04.1 LTS")),
+		)
+
+		image, err := bc.GetImage(ctx, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image).To(BeEmpty())
+	})
+
+	It("should return an error for an invalid regular expression", func() {
+		expectCatalogs(
+			catalog("ubuntu",
+				kmmv1beta1.KernelBuilderEntry{KernelVersionRegexp: `^5\.`, BuilderImage: "some-image"},
+				kmmv1beta1.KernelBuilderEntry{OSImageRegexp: `(`, BuilderImage: "some-image"},
+			),
+		)
+
+		_, err := bc.GetImage(ctx, kernelVersion)
+		Expect(err).To(MatchError(ContainSubstring("invalid KernelBuilderCatalog ubuntu: entries[1].osImageRegexp")))
+	})
+})
+
+var _ = Describe("Validate", func() {
+	DescribeTable("should check the regular expressions of all entries",
+		func(entries []kmmv1beta1.KernelBuilderEntry, expectedErr string) {
+			catalog := &kmmv1beta1.KernelBuilderCatalog{Spec: kmmv1beta1.KernelBuilderCatalogSpec{Entries: entries}}
+
+			err := Validate(catalog)
+			if expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+		},
+		Entry("valid entries",
+			[]kmmv1beta1.KernelBuilderEntry{
+				{KernelVersionRegexp: `-generic$`, OSImageRegexp: `^Ubuntu`},
+				{OSImageRegexp: `^SUSE`},
+			},
+			"",
+		),
+		Entry("invalid kernelVersionRegexp",
+			[]kmmv1beta1.KernelBuilderEntry{
+				{OSImageRegexp: `^SUSE`},
+				{KernelVersionRegexp: `[`},
+			},
+			`entries[1].kernelVersionRegexp "[" is not a valid regular expression`,
+		),
+		Entry("invalid osImageRegexp",
+			[]kmmv1beta1.KernelBuilderEntry{
+				{KernelVersionRegexp: `-generic$`, OSImageRegexp: `a**`},
+			},
+			`entries[0].osImageRegexp "a**" is not a valid regular expression`,
+		),
+	)
+})
+
+var _ = Describe("GetImage for managed clusters", func() {
+	const kernelVersion = "5.14.0-427.13.1.el9_4.x86_64+rt"
+
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		bc   BuilderCatalog
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		bc = NewForManagedClusters(clnt)
+	})
+
+	micNSN := types.NamespacedName{Name: "some-mcm-some-cluster", Namespace: "some-namespace"}
+	ctx := WithModuleImagesConfig(context.Background(), micNSN)
+
+	catalogs := []kmmv1beta1.KernelBuilderCatalog{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "rhel"},
+			Spec: kmmv1beta1.KernelBuilderCatalogSpec{
+				Entries: []kmmv1beta1.KernelBuilderEntry{
+					{OSImageRegexp: `^Red Hat Enterprise Linux CoreOS`, BuilderImage: "rhcos-image"},
+				},
+			},
+		},
+	}
+
+	expectCatalogs := func() *gomock.Call {
+		return clnt.EXPECT().List(gomock.Any(), &kmmv1beta1.KernelBuilderCatalogList{}).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.KernelBuilderCatalogList, _ ...ctrlclient.ListOption) error {
+				list.Items = catalogs
+				return nil
+			},
+		)
+	}
+
+	expectMIC := func(ownerKind, ownerName string) *gomock.Call {
+		return clnt.EXPECT().Get(ctx, micNSN, &kmmv1beta1.ModuleImagesConfig{}).DoAndReturn(
+			func(_ interface{}, _ types.NamespacedName, micObj *kmmv1beta1.ModuleImagesConfig, _ ...ctrlclient.GetOption) error {
+				micObj.Name = micNSN.Name
+				micObj.Namespace = micNSN.Namespace
+				micObj.OwnerReferences = []metav1.OwnerReference{
+					{Kind: ownerKind, Name: ownerName, Controller: ptr.To(true)},
+				}
+				return nil
+			},
+		)
+	}
+
+	expectCluster := func(claims ...clusterv1.ManagedClusterClaim) *gomock.Call {
+		return clnt.EXPECT().Get(ctx, types.NamespacedName{Name: "some-cluster"}, &clusterv1.ManagedCluster{}).DoAndReturn(
+			func(_ interface{}, _ types.NamespacedName, mc *clusterv1.ManagedCluster, _ ...ctrlclient.GetOption) error {
+				mc.Name = "some-cluster"
+				mc.Status.ClusterClaims = claims
+				return nil
+			},
+		)
+	}
+
+	It("should return an error if the ModuleImagesConfig of the image is not known", func() {
+		expectCatalogs()
+
+		_, err := bc.GetImage(context.Background(), kernelVersion)
+		Expect(err).To(MatchError(ContainSubstring("the ModuleImagesConfig of the image is not known")))
+	})
+
+	It("should return an error if the ModuleImagesConfig could not be fetched", func() {
+		gomock.InOrder(
+			expectCatalogs(),
+			clnt.EXPECT().Get(ctx, micNSN, gomock.Any()).Return(errors.New("random error")),
+		)
+
+		_, err := bc.GetImage(ctx, kernelVersion)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the ModuleImagesConfig is not owned by a ManagedClusterModule", func() {
+		gomock.InOrder(
+			expectCatalogs(),
+			expectMIC("Module", "some-mcm"),
+		)
+
+		_, err := bc.GetImage(ctx, kernelVersion)
+		Expect(err).To(MatchError(ContainSubstring("is not owned by a ManagedClusterModule")))
+	})
+
+	It("should return an error if the ManagedCluster could not be fetched", func() {
+		gomock.InOrder(
+			expectCatalogs(),
+			expectMIC("ManagedClusterModule", "some-mcm"),
+			clnt.EXPECT().Get(ctx, types.NamespacedName{Name: "some-cluster"}, gomock.Any()).Return(errors.New("random error")),
+		)
+
+		_, err := bc.GetImage(ctx, kernelVersion)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should match the OS images claimed by the managed cluster of the image", func() {
+		gomock.InOrder(
+			expectCatalogs(),
+			expectMIC("ManagedClusterModule", "some-mcm"),
+			expectCluster(
+				clusterv1.ManagedClusterClaim{
+					Name:  constants.KernelsClusterClaimName,
+					Value: `[{"kernelVersion":"` + kernelVersion + `","osImage":"Red Hat Enterprise Linux CoreOS 416.94"}]`,
+				},
+			),
+		)
+
+		image, err := bc.GetImage(ctx, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image).To(Equal("rhcos-image"))
+	})
+
+	It("should not match if the managed cluster does not claim the OS image of the kernel", func() {
+		gomock.InOrder(
+			expectCatalogs(),
+			expectMIC("ManagedClusterModule", "some-mcm"),
+			expectCluster(
+				clusterv1.ManagedClusterClaim{Name: constants.KernelVersionsClusterClaimName, Value: kernelVersion},
+			),
+		)
+
+		image, err := bc.GetImage(ctx, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image).To(BeEmpty())
+	})
+})
@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: buildercatalog.go
+//
+// Generated by this command:
+//
+//	mockgen -source=buildercatalog.go -package=buildercatalog -destination=mock_buildercatalog.go
+//
+// Package buildercatalog is a generated GoMock package.
+package buildercatalog
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBuilderCatalog is a mock of BuilderCatalog interface.
+type MockBuilderCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockBuilderCatalogMockRecorder
+}
+
+// MockBuilderCatalogMockRecorder is the mock recorder for MockBuilderCatalog.
+type MockBuilderCatalogMockRecorder struct {
+	mock *MockBuilderCatalog
+}
+
+// NewMockBuilderCatalog creates a new mock instance.
+func NewMockBuilderCatalog(ctrl *gomock.Controller) *MockBuilderCatalog {
+	mock := &MockBuilderCatalog{ctrl: ctrl}
+	mock.recorder = &MockBuilderCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBuilderCatalog) EXPECT() *MockBuilderCatalogMockRecorder {
+	return m.recorder
+}
+
+// GetImage mocks base method.
+func (m *MockBuilderCatalog) GetImage(ctx context.Context, kernelVersion string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImage", ctx, kernelVersion)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImage indicates an expected call of GetImage.
+func (mr *MockBuilderCatalogMockRecorder) GetImage(ctx, kernelVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImage", reflect.TypeOf((*MockBuilderCatalog)(nil).GetImage), ctx, kernelVersion)
+}
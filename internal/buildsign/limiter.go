@@ -0,0 +1,163 @@
+package buildsign
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//go:generate mockgen -source=limiter.go -package=buildsign -destination=mock_limiter.go
+
+// Limiter decides whether a new build or sign resource can be started, given the operator-level and per-namespace
+// limits on the number of concurrent builds and signs.
+type Limiter interface {
+	ShouldQueue(ctx context.Context, mld *api.ModuleLoaderData) (bool, error)
+}
+
+type limiter struct {
+	client                    client.Client
+	resourceManager           ResourceManager
+	maxConcurrent             int
+	maxConcurrentPerNamespace int
+}
+
+// NewLimiter returns a Limiter that allows at most maxConcurrent builds and signs in the cluster, and at most
+// maxConcurrentPerNamespace in a single namespace. A limit of 0 disables it.
+func NewLimiter(client client.Client, resourceManager ResourceManager, maxConcurrent, maxConcurrentPerNamespace int) Limiter {
+	return &limiter{
+		client:                    client,
+		resourceManager:           resourceManager,
+		maxConcurrent:             maxConcurrent,
+		maxConcurrentPerNamespace: maxConcurrentPerNamespace,
+	}
+}
+
+type createdResourcesKey struct{}
+
+// WithCreatedResources returns a context in which the resources created by Sync are remembered, so that they take a
+// slot even if the cache does not hold them yet.
+func WithCreatedResources(ctx context.Context) context.Context {
+	return context.WithValue(ctx, createdResourcesKey{}, sets.New[types.NamespacedName]())
+}
+
+func recordCreatedResource(ctx context.Context, obj metav1.Object) {
+	if created, ok := ctx.Value(createdResourcesKey{}).(sets.Set[types.NamespacedName]); ok {
+		created.Insert(types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()})
+	}
+}
+
+// queueEntry identifies a queued build or sign.
+type queueEntry struct {
+	priority          int32
+	creationTimestamp metav1.Time
+	namespace         string
+	name              string
+	image             string
+}
+
+// isAheadOf returns true if e should be started before other.
+// Higher priorities go first; for the same priority, older ModuleBuildSignConfigs go first.
+func (e queueEntry) isAheadOf(other queueEntry) bool {
+	if e.priority != other.priority {
+		return e.priority > other.priority
+	}
+
+	if !e.creationTimestamp.Equal(&other.creationTimestamp) {
+		return e.creationTimestamp.Before(&other.creationTimestamp)
+	}
+
+	return strings.Join([]string{e.namespace, e.name, e.image}, "/") <
+		strings.Join([]string{other.namespace, other.name, other.image}, "/")
+}
+
+// ShouldQueue returns true if starting the build or sign of mld would exceed one of the limits, either because
+// too many builds and signs are already running or because queued ones with a higher priority should start first.
+func (l *limiter) ShouldQueue(ctx context.Context, mld *api.ModuleLoaderData) (bool, error) {
+	if l.maxConcurrent <= 0 && l.maxConcurrentPerNamespace <= 0 {
+		return false, nil
+	}
+
+	activeResources, err := l.resourceManager.GetActiveResources(ctx, "")
+	if err != nil {
+		return false, fmt.Errorf("failed to get the active build and sign resources: %v", err)
+	}
+
+	active := sets.New[types.NamespacedName]()
+	for _, r := range activeResources {
+		active.Insert(types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()})
+	}
+	if created, ok := ctx.Value(createdResourcesKey{}).(sets.Set[types.NamespacedName]); ok {
+		active = active.Union(created)
+	}
+
+	activePerNamespace := make(map[string]int)
+	for nsn := range active {
+		activePerNamespace[nsn.Namespace]++
+	}
+
+	mbscList := kmmv1beta1.ModuleBuildSignConfigList{}
+	if err = l.client.List(ctx, &mbscList); err != nil {
+		return false, fmt.Errorf("failed to list ModuleBuildSignConfigs: %v", err)
+	}
+
+	self := queueEntry{
+		priority:  mld.BuildPriority,
+		namespace: mld.Namespace,
+		name:      mld.Name,
+		image:     mld.ContainerImage,
+	}
+	if mld.Owner != nil {
+		self.creationTimestamp = mld.Owner.GetCreationTimestamp()
+	}
+
+	aheadPerNamespace := make(map[string]int)
+	for _, mbscObj := range mbscList.Items {
+		for _, imageState := range mbscObj.Status.Images {
+			if imageState.Status != kmmv1beta1.ActionQueued {
+				continue
+			}
+
+			entry := queueEntry{
+				priority:          mbscObj.Spec.BuildPriority,
+				creationTimestamp: mbscObj.CreationTimestamp,
+				namespace:         mbscObj.Namespace,
+				name:              mbscObj.Name,
+				image:             imageState.Image,
+			}
+			if entry.namespace == self.namespace && entry.name == self.name && entry.image == self.image {
+				continue
+			}
+
+			if entry.isAheadOf(self) {
+				aheadPerNamespace[entry.namespace]++
+			}
+		}
+	}
+
+	if l.maxConcurrentPerNamespace > 0 &&
+		activePerNamespace[mld.Namespace]+aheadPerNamespace[mld.Namespace] >= l.maxConcurrentPerNamespace {
+		return true, nil
+	}
+
+	if l.maxConcurrent <= 0 {
+		return false, nil
+	}
+
+	// queued entries that are blocked by the limit of their own namespace do not take a slot
+	ahead := 0
+	for ns, count := range aheadPerNamespace {
+		if l.maxConcurrentPerNamespace > 0 {
+			count = min(count, max(0, l.maxConcurrentPerNamespace-activePerNamespace[ns]))
+		}
+		ahead += count
+	}
+
+	return active.Len()+ahead >= l.maxConcurrent, nil
+}
@@ -0,0 +1,198 @@
+package buildsign
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	buildv1 "github.com/openshift/api/build/v1"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+)
+
+var _ = Describe("ShouldQueue", func() {
+	const (
+		namespace      = "some-namespace"
+		otherNamespace = "other-namespace"
+	)
+
+	var (
+		ctrl                *gomock.Controller
+		clnt                *client.MockClient
+		mockResourceManager *MockResourceManager
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mockResourceManager = NewMockResourceManager(ctrl)
+	})
+
+	ctx := context.Background()
+	now := metav1.Now()
+	older := metav1.NewTime(now.Add(-time.Hour))
+
+	mbscOwner := &kmmv1beta1.ModuleBuildSignConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "some-module", Namespace: namespace, CreationTimestamp: now},
+	}
+	mld := &api.ModuleLoaderData{
+		Name:           "some-module",
+		Namespace:      namespace,
+		ContainerImage: "some-image",
+		BuildPriority:  5,
+		Owner:          mbscOwner,
+	}
+
+	activeBuild := func(ns string) metav1.Object {
+		return &buildv1.Build{ObjectMeta: metav1.ObjectMeta{Namespace: ns}}
+	}
+
+	queuedMBSC := func(name, ns string, priority int32, creationTimestamp metav1.Time, images ...string) kmmv1beta1.ModuleBuildSignConfig {
+		mbscObj := kmmv1beta1.ModuleBuildSignConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, CreationTimestamp: creationTimestamp},
+			Spec:       kmmv1beta1.ModuleBuildSignConfigSpec{BuildPriority: priority},
+		}
+		for _, img := range images {
+			mbscObj.Status.Images = append(mbscObj.Status.Images, kmmv1beta1.BuildSignImageState{
+				Image:  img,
+				Action: kmmv1beta1.BuildImage,
+				Status: kmmv1beta1.ActionQueued,
+			})
+		}
+		return mbscObj
+	}
+
+	expectState := func(active []metav1.Object, mbscs ...kmmv1beta1.ModuleBuildSignConfig) {
+		gomock.InOrder(
+			mockResourceManager.EXPECT().GetActiveResources(ctx, "").Return(active, nil),
+			clnt.EXPECT().List(ctx, &kmmv1beta1.ModuleBuildSignConfigList{}).DoAndReturn(
+				func(_ interface{}, list *kmmv1beta1.ModuleBuildSignConfigList, _ ...ctrlclient.ListOption) error {
+					list.Items = mbscs
+					return nil
+				},
+			),
+		)
+	}
+
+	It("should not queue anything if no limit is set", func() {
+		l := NewLimiter(clnt, mockResourceManager, 0, 0)
+
+		queued, err := l.ShouldQueue(ctx, mld)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(queued).To(BeFalse())
+	})
+
+	It("should return an error if the active resources could not be listed", func() {
+		l := NewLimiter(clnt, mockResourceManager, 1, 0)
+		mockResourceManager.EXPECT().GetActiveResources(ctx, "").Return(nil, fmt.Errorf("some error"))
+
+		_, err := l.ShouldQueue(ctx, mld)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the MBSCs could not be listed", func() {
+		l := NewLimiter(clnt, mockResourceManager, 1, 0)
+		gomock.InOrder(
+			mockResourceManager.EXPECT().GetActiveResources(ctx, "").Return(nil, nil),
+			clnt.EXPECT().List(ctx, gomock.Any()).Return(fmt.Errorf("some error")),
+		)
+
+		_, err := l.ShouldQueue(ctx, mld)
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("should apply the limits",
+		func(maxConcurrent, maxConcurrentPerNamespace int, active []metav1.Object, mbscs []kmmv1beta1.ModuleBuildSignConfig, expected bool) {
+			l := NewLimiter(clnt, mockResourceManager, maxConcurrent, maxConcurrentPerNamespace)
+			expectState(active, mbscs...)
+
+			queued, err := l.ShouldQueue(ctx, mld)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queued).To(Equal(expected))
+		},
+		Entry("below the global limit", 2, 0, []metav1.Object{activeBuild(otherNamespace)}, nil, false),
+		Entry("global limit reached", 2, 0, []metav1.Object{activeBuild(otherNamespace), activeBuild(namespace)}, nil, true),
+		Entry("below the namespace limit", 0, 2, []metav1.Object{activeBuild(namespace), activeBuild(otherNamespace)}, nil, false),
+		Entry("namespace limit reached", 0, 1, []metav1.Object{activeBuild(namespace)}, nil, true),
+		Entry(
+			"the image itself is queued",
+			2, 0,
+			[]metav1.Object{activeBuild(otherNamespace)},
+			[]kmmv1beta1.ModuleBuildSignConfig{queuedMBSC("some-module", namespace, 5, now, "some-image")},
+			false,
+		),
+		Entry(
+			"an image with a higher priority is queued",
+			2, 0,
+			[]metav1.Object{activeBuild(otherNamespace)},
+			[]kmmv1beta1.ModuleBuildSignConfig{queuedMBSC("other-module", otherNamespace, 10, now, "other-image")},
+			true,
+		),
+		Entry(
+			"an image with a lower priority is queued",
+			2, 0,
+			[]metav1.Object{activeBuild(otherNamespace)},
+			[]kmmv1beta1.ModuleBuildSignConfig{queuedMBSC("other-module", otherNamespace, 1, older, "other-image")},
+			false,
+		),
+		Entry(
+			"an older image with the same priority is queued",
+			2, 0,
+			[]metav1.Object{activeBuild(otherNamespace)},
+			[]kmmv1beta1.ModuleBuildSignConfig{queuedMBSC("other-module", otherNamespace, 5, older, "other-image")},
+			true,
+		),
+		Entry(
+			"an image with a higher priority is queued in the same namespace",
+			0, 2,
+			[]metav1.Object{activeBuild(namespace)},
+			[]kmmv1beta1.ModuleBuildSignConfig{queuedMBSC("other-module", namespace, 10, now, "other-image")},
+			true,
+		),
+		Entry(
+			"images with a higher priority are queued but blocked by the limit of their namespace",
+			3, 1,
+			[]metav1.Object{activeBuild(otherNamespace)},
+			[]kmmv1beta1.ModuleBuildSignConfig{queuedMBSC("other-module", otherNamespace, 10, now, "image-1", "image-2")},
+			false,
+		),
+	)
+
+	It("should count the resources created in the same pass that are not in the cache yet", func() {
+		l := NewLimiter(clnt, mockResourceManager, 0, 2)
+		active := &buildv1.Build{ObjectMeta: metav1.ObjectMeta{Name: "active", Namespace: namespace}}
+		created := &buildv1.Build{ObjectMeta: metav1.ObjectMeta{Name: "created", Namespace: namespace}}
+		passCtx := WithCreatedResources(ctx)
+		recordCreatedResource(passCtx, created)
+		gomock.InOrder(
+			mockResourceManager.EXPECT().GetActiveResources(passCtx, "").Return([]metav1.Object{active}, nil),
+			clnt.EXPECT().List(passCtx, &kmmv1beta1.ModuleBuildSignConfigList{}).Return(nil),
+		)
+
+		queued, err := l.ShouldQueue(passCtx, mld)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(queued).To(BeTrue())
+	})
+
+	It("should not count a created resource twice once it is in the cache", func() {
+		l := NewLimiter(clnt, mockResourceManager, 0, 2)
+		created := &buildv1.Build{ObjectMeta: metav1.ObjectMeta{Name: "created", Namespace: namespace}}
+		passCtx := WithCreatedResources(ctx)
+		recordCreatedResource(passCtx, created)
+		gomock.InOrder(
+			mockResourceManager.EXPECT().GetActiveResources(passCtx, "").Return([]metav1.Object{created}, nil),
+			clnt.EXPECT().List(passCtx, &kmmv1beta1.ModuleBuildSignConfigList{}).Return(nil),
+		)
+
+		queued, err := l.ShouldQueue(passCtx, mld)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(queued).To(BeFalse())
+	})
+})
@@ -0,0 +1,261 @@
+package buildsign
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// maxArchivedLogBytes keeps the archived log well below the 1MiB size limit of a ConfigMap.
+const maxArchivedLogBytes = 512 * 1024
+
+const (
+	buildLogDataKey    = "log"
+	buildPhaseDataKey  = "phase"
+	buildReasonDataKey = "reason"
+)
+
+//go:generate mockgen -source=logarchiver.go -package=buildsign -destination=mock_logarchiver.go
+
+// LogArchiver stores the tail of the logs of a finished build or sign resource into a ConfigMap, so that they remain
+// available after the resource and its pod are garbage collected.
+type LogArchiver interface {
+	Archive(ctx context.Context, build *buildv1.Build, owner client.Object) (string, error)
+}
+
+// PodLogReader reads the logs of a single container.
+type PodLogReader interface {
+	ReadLogs(ctx context.Context, namespace, podName, container string, tailLines int64) ([]byte, error)
+}
+
+type podLogReader struct {
+	clientset kubernetes.Interface
+}
+
+func NewPodLogReader(clientset kubernetes.Interface) PodLogReader {
+	return &podLogReader{clientset: clientset}
+}
+
+func (r *podLogReader) ReadLogs(ctx context.Context, namespace, podName, container string, tailLines int64) ([]byte, error) {
+	opts := &v1.PodLogOptions{
+		Container: container,
+		TailLines: &tailLines,
+	}
+
+	rc, err := r.clientset.CoreV1().Pods(namespace).GetLogs(podName, opts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not stream the logs of container %s in pod %s/%s: %v", container, namespace, podName, err)
+	}
+	defer rc.Close()
+
+	return io.ReadAll(io.LimitReader(rc, maxArchivedLogBytes))
+}
+
+type logArchiver struct {
+	client       client.Client
+	podLogReader PodLogReader
+	scheme       *runtime.Scheme
+	tailLines    int64
+	retention    int
+}
+
+// NewLogArchiver returns a LogArchiver that keeps the last tailLines lines of each container, and at most retention
+// archived logs per Module, kernel and action, unless the Module sets its own retention. A tailLines of 0 disables
+// archiving; a retention of 0 keeps all logs.
+func NewLogArchiver(client client.Client, podLogReader PodLogReader, scheme *runtime.Scheme, tailLines int64, retention int) LogArchiver {
+	return &logArchiver{
+		client:       client,
+		podLogReader: podLogReader,
+		scheme:       scheme,
+		tailLines:    tailLines,
+		retention:    retention,
+	}
+}
+
+// Archive creates the ConfigMap holding the logs of build, owned by owner, and returns its name.
+// It returns an empty name if archiving is disabled or if no pod was ever created for the build.
+func (a *logArchiver) Archive(ctx context.Context, build *buildv1.Build, owner client.Object) (string, error) {
+	if a.tailLines <= 0 {
+		return "", nil
+	}
+
+	podName := build.Annotations[buildv1.BuildPodNameAnnotation]
+	if podName == "" {
+		return "", nil
+	}
+
+	retention, err := a.getRetention(build)
+	if err != nil {
+		return "", err
+	}
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      buildLogConfigMapName(build),
+			Namespace: build.Namespace,
+			Labels: map[string]string{
+				constants.BuildLogLabel:      "",
+				constants.ModuleNameLabel:    build.Labels[constants.ModuleNameLabel],
+				constants.TargetKernelTarget: build.Labels[constants.TargetKernelTarget],
+				constants.ResourceType:       build.Labels[constants.ResourceType],
+			},
+		},
+		Data: map[string]string{
+			buildLogDataKey:    a.readPodLogs(ctx, build.Namespace, podName),
+			buildPhaseDataKey:  string(build.Status.Phase),
+			buildReasonDataKey: string(build.Status.Reason),
+		},
+	}
+
+	if err := controllerutil.SetOwnerReference(owner, cm, a.scheme); err != nil {
+		return "", fmt.Errorf("could not set the owner of ConfigMap %s/%s: %v", cm.Namespace, cm.Name, err)
+	}
+
+	if err := a.client.Create(ctx, cm); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("could not create ConfigMap %s/%s: %v", cm.Namespace, cm.Name, err)
+	}
+
+	if err := a.pruneOlderLogs(ctx, cm, retention); err != nil {
+		return "", fmt.Errorf("could not delete older logs: %v", err)
+	}
+
+	return cm.Name, nil
+}
+
+// readPodLogs concatenates the logs of all init and regular containers of the pod, keeping only the end if the
+// result is too large. Errors are written in place of the logs, so that a partial archive is still stored.
+func (a *logArchiver) readPodLogs(ctx context.Context, namespace, podName string) string {
+	pod := v1.Pod{}
+
+	if err := a.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: podName}, &pod); err != nil {
+		return fmt.Sprintf("could not get pod %s/%s: %v\n", namespace, podName, err)
+	}
+
+	var buf bytes.Buffer
+
+	containers := append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+
+	for _, c := range containers {
+		fmt.Fprintf(&buf, "==> container %s <==\n", c.Name)
+
+		logs, err := a.podLogReader.ReadLogs(ctx, namespace, podName, c.Name, a.tailLines)
+		if err != nil {
+			fmt.Fprintf(&buf, "could not read logs: %v\n", err)
+			continue
+		}
+
+		buf.Write(logs)
+
+		if len(logs) > 0 && logs[len(logs)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+
+	out := buf.String()
+
+	if len(out) > maxArchivedLogBytes {
+		out = out[len(out)-maxArchivedLogBytes:]
+	}
+
+	return strings.ToValidUTF8(out, "")
+}
+
+// getRetention returns the retention set by the Module in the annotations of build, or the default one.
+func (a *logArchiver) getRetention(build *buildv1.Build) (int, error) {
+	value, ok := build.Annotations[constants.BuildLogRetentionAnnotation]
+	if !ok {
+		return a.retention, nil
+	}
+
+	retention, err := strconv.Atoi(value)
+	if err != nil || retention < 0 {
+		return 0, fmt.Errorf("invalid log retention %q in annotation %s", value, constants.BuildLogRetentionAnnotation)
+	}
+
+	return retention, nil
+}
+
+// pruneOlderLogs deletes the oldest logs archived for the same Module, kernel and action as current, so that at most
+// retention logs are kept, current included.
+func (a *logArchiver) pruneOlderLogs(ctx context.Context, current *v1.ConfigMap, retention int) error {
+	if retention <= 0 {
+		return nil
+	}
+
+	cmList := v1.ConfigMapList{}
+
+	opts := []client.ListOption{
+		client.InNamespace(current.Namespace),
+		client.MatchingLabels{
+			constants.BuildLogLabel:      "",
+			constants.ModuleNameLabel:    current.Labels[constants.ModuleNameLabel],
+			constants.TargetKernelTarget: current.Labels[constants.TargetKernelTarget],
+			constants.ResourceType:       current.Labels[constants.ResourceType],
+		},
+	}
+
+	if err := a.client.List(ctx, &cmList, opts...); err != nil {
+		return fmt.Errorf("could not list ConfigMaps: %v", err)
+	}
+
+	others := make([]v1.ConfigMap, 0, len(cmList.Items))
+
+	for _, cm := range cmList.Items {
+		if cm.Name != current.Name {
+			others = append(others, cm)
+		}
+	}
+
+	if len(others) < retention {
+		return nil
+	}
+
+	sort.Slice(others, func(i, j int) bool {
+		ti, tj := others[i].CreationTimestamp, others[j].CreationTimestamp
+
+		if !ti.Equal(&tj) {
+			return tj.Before(&ti)
+		}
+
+		return others[i].Name > others[j].Name
+	})
+
+	for i := range others[retention-1:] {
+		cm := &others[retention-1+i]
+
+		if err := a.client.Delete(ctx, cm); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("could not delete ConfigMap %s/%s: %v", cm.Namespace, cm.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// buildLogConfigMapName derives a stable name from the build, so that archiving the same build twice is idempotent
+// while the logs of successive builds with the same name are kept apart.
+func buildLogConfigMapName(build *buildv1.Build) string {
+	suffix := string(build.UID)
+	if len(suffix) > 8 {
+		suffix = suffix[:8]
+	}
+
+	if suffix == "" {
+		return build.Name + "-log"
+	}
+
+	return build.Name + "-log-" + suffix
+}
@@ -0,0 +1,248 @@
+package buildsign
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	buildv1 "github.com/openshift/api/build/v1"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+)
+
+var _ = Describe("Archive", func() {
+	const (
+		namespace = "some-namespace"
+		podName   = "some-build-pod"
+	)
+
+	var (
+		ctrl             *gomock.Controller
+		clnt             *client.MockClient
+		mockPodLogReader *MockPodLogReader
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mockPodLogReader = NewMockPodLogReader(ctrl)
+	})
+
+	ctx := context.Background()
+
+	owner := &kmmv1beta1.ModuleBuildSignConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "some-module", Namespace: namespace, UID: "owner-uid"},
+	}
+
+	newBuild := func() *buildv1.Build {
+		return &buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "some-module-build-some-kernel",
+				Namespace:   namespace,
+				UID:         "0123456789abcdef",
+				Annotations: map[string]string{buildv1.BuildPodNameAnnotation: podName},
+				Labels: map[string]string{
+					constants.ModuleNameLabel:    "some-module",
+					constants.TargetKernelTarget: "some-kernel",
+					constants.ResourceType:       string(kmmv1beta1.BuildImage),
+				},
+			},
+			Status: buildv1.BuildStatus{
+				Phase:  buildv1.BuildPhaseFailed,
+				Reason: buildv1.StatusReasonDockerBuildFailed,
+			},
+		}
+	}
+
+	expectPod := func() *gomock.Call {
+		return clnt.EXPECT().Get(ctx, ctrlclient.ObjectKey{Namespace: namespace, Name: podName}, &v1.Pod{}).DoAndReturn(
+			func(_ interface{}, _ interface{}, pod *v1.Pod, _ ...ctrlclient.GetOption) error {
+				pod.Spec.InitContainers = []v1.Container{{Name: "git-clone"}}
+				pod.Spec.Containers = []v1.Container{{Name: "docker-build"}}
+				return nil
+			},
+		)
+	}
+
+	It("should do nothing if archiving is disabled", func() {
+		la := NewLogArchiver(clnt, mockPodLogReader, scheme, 0, 3)
+
+		name, err := la.Archive(ctx, newBuild(), owner)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(BeEmpty())
+	})
+
+	It("should do nothing if the build has no pod", func() {
+		la := NewLogArchiver(clnt, mockPodLogReader, scheme, 100, 3)
+		build := newBuild()
+		build.Annotations = nil
+
+		name, err := la.Archive(ctx, build, owner)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(BeEmpty())
+	})
+
+	It("should store the logs of all containers and delete the oldest logs", func() {
+		la := NewLogArchiver(clnt, mockPodLogReader, scheme, 100, 2)
+
+		now := time.Now()
+		archivedLog := func(name string, age time.Duration) v1.ConfigMap {
+			return v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Namespace:         namespace,
+					CreationTimestamp: metav1.NewTime(now.Add(-age)),
+				},
+			}
+		}
+
+		var created *v1.ConfigMap
+
+		gomock.InOrder(
+			expectPod(),
+			mockPodLogReader.EXPECT().ReadLogs(ctx, namespace, podName, "git-clone", int64(100)).Return([]byte("cloned"), nil),
+			mockPodLogReader.EXPECT().ReadLogs(ctx, namespace, podName, "docker-build", int64(100)).Return(nil, errors.New("some error")),
+			clnt.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ interface{}, cm *v1.ConfigMap, _ ...ctrlclient.CreateOption) error {
+					created = cm
+					return nil
+				},
+			),
+			clnt.EXPECT().List(ctx, &v1.ConfigMapList{}, gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *v1.ConfigMapList, _ ...ctrlclient.ListOption) error {
+					list.Items = []v1.ConfigMap{
+						archivedLog("oldest", 3*time.Hour),
+						archivedLog("some-module-build-some-kernel-log-01234567", 0),
+						archivedLog("newest", time.Hour),
+						archivedLog("older", 2*time.Hour),
+					}
+					return nil
+				},
+			),
+			clnt.EXPECT().Delete(ctx, &v1.ConfigMap{ObjectMeta: archivedLog("older", 2*time.Hour).ObjectMeta}),
+			clnt.EXPECT().Delete(ctx, &v1.ConfigMap{ObjectMeta: archivedLog("oldest", 3*time.Hour).ObjectMeta}),
+		)
+
+		name, err := la.Archive(ctx, newBuild(), owner)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("some-module-build-some-kernel-log-01234567"))
+
+		Expect(created.Name).To(Equal(name))
+		Expect(created.Labels).To(HaveKeyWithValue(constants.ModuleNameLabel, "some-module"))
+		Expect(created.Labels).To(HaveKeyWithValue(constants.TargetKernelTarget, "some-kernel"))
+		Expect(created.Labels).To(HaveKeyWithValue(constants.ResourceType, string(kmmv1beta1.BuildImage)))
+		Expect(created.OwnerReferences).To(HaveLen(1))
+		Expect(created.OwnerReferences[0].UID).To(Equal(owner.UID))
+		Expect(created.Data).To(HaveKeyWithValue(buildPhaseDataKey, string(buildv1.BuildPhaseFailed)))
+		Expect(created.Data).To(HaveKeyWithValue(buildReasonDataKey, string(buildv1.StatusReasonDockerBuildFailed)))
+		Expect(created.Data[buildLogDataKey]).To(Equal(
+			"==> container git-clone <==\ncloned\n==> container docker-build <==\ncould not read logs: some error\n",
+		))
+	})
+
+	It("should use the log retention of the Module over the default one", func() {
+		la := NewLogArchiver(clnt, mockPodLogReader, scheme, 100, 0)
+		build := newBuild()
+		build.Annotations[constants.BuildLogRetentionAnnotation] = "1"
+
+		older := v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "older", Namespace: namespace}}
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, podName)),
+			clnt.EXPECT().Create(ctx, gomock.Any()),
+			clnt.EXPECT().List(ctx, &v1.ConfigMapList{}, gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *v1.ConfigMapList, _ ...ctrlclient.ListOption) error {
+					list.Items = []v1.ConfigMap{older}
+					return nil
+				},
+			),
+			clnt.EXPECT().Delete(ctx, &older),
+		)
+
+		_, err := la.Archive(ctx, build, owner)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should keep all logs if the Module sets a log retention of 0", func() {
+		la := NewLogArchiver(clnt, mockPodLogReader, scheme, 100, 2)
+		build := newBuild()
+		build.Annotations[constants.BuildLogRetentionAnnotation] = "0"
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, podName)),
+			clnt.EXPECT().Create(ctx, gomock.Any()),
+		)
+
+		_, err := la.Archive(ctx, build, owner)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return an error if the log retention of the Module is invalid", func() {
+		la := NewLogArchiver(clnt, mockPodLogReader, scheme, 100, 2)
+		build := newBuild()
+		build.Annotations[constants.BuildLogRetentionAnnotation] = "-1"
+
+		_, err := la.Archive(ctx, build, owner)
+		Expect(err).To(MatchError(ContainSubstring("invalid log retention")))
+	})
+
+	It("should keep only the end of large logs", func() {
+		la := NewLogArchiver(clnt, mockPodLogReader, scheme, 100, 0)
+
+		var created *v1.ConfigMap
+
+		gomock.InOrder(
+			expectPod(),
+			mockPodLogReader.EXPECT().ReadLogs(ctx, namespace, podName, "git-clone", int64(100)).Return(nil, nil),
+			mockPodLogReader.EXPECT().ReadLogs(ctx, namespace, podName, "docker-build", int64(100)).
+				Return([]byte(strings.Repeat("a", maxArchivedLogBytes)+"end\n"), nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ interface{}, cm *v1.ConfigMap, _ ...ctrlclient.CreateOption) error {
+					created = cm
+					return nil
+				},
+			),
+		)
+
+		_, err := la.Archive(ctx, newBuild(), owner)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.Data[buildLogDataKey]).To(HaveLen(maxArchivedLogBytes))
+		Expect(created.Data[buildLogDataKey]).To(HaveSuffix("aend\n"))
+	})
+
+	It("should not fail if the logs were already archived", func() {
+		la := NewLogArchiver(clnt, mockPodLogReader, scheme, 100, 0)
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, podName)),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(k8serrors.NewAlreadyExists(schema.GroupResource{}, "whatever")),
+		)
+
+		name, err := la.Archive(ctx, newBuild(), owner)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("some-module-build-some-kernel-log-01234567"))
+	})
+
+	It("should return an error if the ConfigMap could not be created", func() {
+		la := NewLogArchiver(clnt, mockPodLogReader, scheme, 100, 0)
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(errors.New("some error")),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(errors.New("some error")),
+		)
+
+		_, err := la.Archive(ctx, newBuild(), owner)
+		Expect(err).To(HaveOccurred())
+	})
+})
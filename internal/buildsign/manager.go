@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,6 +14,7 @@ import (
 
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/kernel"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
 )
@@ -22,19 +24,27 @@ import (
 type Manager interface {
 	GetStatus(ctx context.Context, name, namespace, kernelVersion string,
 		action kmmv1beta1.BuildOrSignAction, owner metav1.Object) (kmmv1beta1.BuildOrSignStatus, error)
+	GetFailure(ctx context.Context, name, namespace, kernelVersion string,
+		action kmmv1beta1.BuildOrSignAction, owner metav1.Object) (kmmv1beta1.BuildOrSignFailureReason, int32, error)
+	GetLogConfigMap(ctx context.Context, name, namespace, kernelVersion string,
+		action kmmv1beta1.BuildOrSignAction, owner metav1.Object) (string, error)
+	GetSignedFiles(ctx context.Context, name, namespace, kernelVersion string, owner metav1.Object) ([]string, error)
 	Sync(ctx context.Context, mld *api.ModuleLoaderData, pushImage bool, action kmmv1beta1.BuildOrSignAction, owner metav1.Object) error
 	GarbageCollect(ctx context.Context, name, namespace string, action kmmv1beta1.BuildOrSignAction, owner metav1.Object) ([]string, error)
+	GetBuildInputsHash(ctx context.Context, mld *api.ModuleLoaderData) (string, error)
 }
 
 type manager struct {
 	client          client.Client
 	resourceManager ResourceManager
+	limiter         Limiter
 }
 
-func NewManager(client client.Client, resourceManager ResourceManager, scheme *runtime.Scheme) Manager {
+func NewManager(client client.Client, resourceManager ResourceManager, limiter Limiter, scheme *runtime.Scheme) Manager {
 	return &manager{
 		client:          client,
 		resourceManager: resourceManager,
+		limiter:         limiter,
 	}
 }
 
@@ -50,6 +60,10 @@ func (m *manager) GetStatus(ctx context.Context, name, namespace, kernelVersion
 		}
 		return kmmv1beta1.BuildOrSignStatus(""), nil
 	}
+	if foundResource.GetDeletionTimestamp() != nil {
+		// the resource is being replaced by a new one, or removed after its action succeeded
+		return kmmv1beta1.BuildOrSignStatus(""), nil
+	}
 	status, err := m.resourceManager.GetResourceStatus(foundResource)
 	if err != nil {
 		return kmmv1beta1.BuildOrSignStatus(""), fmt.Errorf("failed to get status for the resource %s/%s, action %s: %v",
@@ -66,6 +80,61 @@ func (m *manager) GetStatus(ctx context.Context, name, namespace, kernelVersion
 	return kmmv1beta1.BuildOrSignStatus(""), nil
 }
 
+// GetFailure returns the classified reason of the failure of a failed resource, and the attempt for which the
+// resource was created.
+func (m *manager) GetFailure(ctx context.Context, name, namespace, kernelVersion string,
+	action kmmv1beta1.BuildOrSignAction, owner metav1.Object) (kmmv1beta1.BuildOrSignFailureReason, int32, error) {
+
+	normalizedKernel := kernel.DNSSafeKernelVersion(kernelVersion)
+	foundResource, err := m.resourceManager.GetResourceByKernel(ctx, name, namespace, normalizedKernel, action, owner)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get resource %s/%s, action %s: %v", namespace, name, action, err)
+	}
+	reason, err := m.resourceManager.GetResourceFailureReason(foundResource)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get the failure reason of the resource %s/%s, action %s: %v",
+			foundResource.GetNamespace(), foundResource.GetName(), action, err)
+	}
+	attempt, err := m.resourceManager.GetResourceAttempt(foundResource)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get the attempt of the resource %s/%s, action %s: %v",
+			foundResource.GetNamespace(), foundResource.GetName(), action, err)
+	}
+	return reason, attempt, nil
+}
+
+// GetLogConfigMap returns the name of the ConfigMap in which the logs of the resource were archived, or an empty
+// string if there is no resource or if its logs were not archived yet.
+func (m *manager) GetLogConfigMap(ctx context.Context, name, namespace, kernelVersion string,
+	action kmmv1beta1.BuildOrSignAction, owner metav1.Object) (string, error) {
+
+	normalizedKernel := kernel.DNSSafeKernelVersion(kernelVersion)
+	foundResource, err := m.resourceManager.GetResourceByKernel(ctx, name, namespace, normalizedKernel, action, owner)
+	if err != nil {
+		if !errors.Is(err, ErrNoMatchingBuildSignResource) {
+			return "", fmt.Errorf("failed to get resource %s/%s, action %s: %v", namespace, name, action, err)
+		}
+		return "", nil
+	}
+	return foundResource.GetAnnotations()[constants.BuildLogAnnotation], nil
+}
+
+// GetSignedFiles returns the kernel modules signed by the sign resource, or nil if there is no sign resource or if
+// its signed files were not recorded yet.
+func (m *manager) GetSignedFiles(ctx context.Context, name, namespace, kernelVersion string, owner metav1.Object) ([]string, error) {
+
+	normalizedKernel := kernel.DNSSafeKernelVersion(kernelVersion)
+	foundResource, err := m.resourceManager.GetResourceByKernel(ctx, name, namespace, normalizedKernel,
+		kmmv1beta1.SignImage, owner)
+	if err != nil {
+		if !errors.Is(err, ErrNoMatchingBuildSignResource) {
+			return nil, fmt.Errorf("failed to get resource %s/%s, action %s: %v", namespace, name, kmmv1beta1.SignImage, err)
+		}
+		return nil, nil
+	}
+	return strings.Fields(foundResource.GetAnnotations()[constants.SignedFilesAnnotation]), nil
+}
+
 func (m *manager) Sync(ctx context.Context, mld *api.ModuleLoaderData, pushImage bool, action kmmv1beta1.BuildOrSignAction,
 	owner metav1.Object) error {
 
@@ -90,15 +159,26 @@ func (m *manager) Sync(ctx context.Context, mld *api.ModuleLoaderData, pushImage
 			return fmt.Errorf("error getting the %s resource: %v", action, err)
 		}
 
+		queued, err := m.limiter.ShouldQueue(ctx, mld)
+		if err != nil {
+			return fmt.Errorf("could not determine if the %s resource should be queued: %v", action, err)
+		}
+		if queued {
+			logger.Info("Too many builds or signs are running, queuing resource", "action", action)
+			return ErrResourceQueued
+		}
+
 		logger.Info("Creating resource")
 		err = m.resourceManager.CreateResource(ctx, resourceTemplate)
 		if err != nil {
 			if k8serrors.IsAlreadyExists(err) {
 				logger.Info("Resource already exists, skipping creation (likely the client cache didn't update yet)")
+				recordCreatedResource(ctx, resourceTemplate)
 				return nil
 			}
 			return fmt.Errorf("could not create resource: %v", err)
 		}
+		recordCreatedResource(ctx, resourceTemplate)
 
 		return nil
 	}
@@ -150,3 +230,12 @@ func (m *manager) GarbageCollect(ctx context.Context, name, namespace string, ac
 	}
 	return deleteResourceNames, errors.Join(errs...)
 }
+
+func (m *manager) GetBuildInputsHash(ctx context.Context, mld *api.ModuleLoaderData) (string, error) {
+	hash, err := m.resourceManager.GetBuildInputsHash(ctx, mld)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the build inputs hash for %s/%s, kernel %s: %v",
+			mld.Namespace, mld.Name, mld.KernelVersion, err)
+	}
+	return hash, nil
+}
@@ -3,6 +3,7 @@ package buildsign
 import (
 	"context"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -11,10 +12,13 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/kernel"
 )
 
@@ -36,7 +40,7 @@ var _ = Describe("GetStatus", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
 		mockResourceManager = NewMockResourceManager(ctrl)
-		mgr = NewManager(clnt, mockResourceManager, scheme)
+		mgr = NewManager(clnt, mockResourceManager, nil, scheme)
 	})
 
 	ctx := context.Background()
@@ -64,6 +68,20 @@ var _ = Describe("GetStatus", func() {
 		Expect(status).To(Equal(kmmv1beta1.BuildOrSignStatus("")))
 	})
 
+	It("should return an empty status for a resource being deleted", func() {
+		foundBuild := buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &metav1.Time{Time: time.Now()}},
+		}
+		normalizedKernel := kernel.DNSSafeKernelVersion(kernelVersion)
+		mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, normalizedKernel,
+			kmmv1beta1.BuildImage, &testMBSC).
+			Return(&foundBuild, nil)
+
+		status, err := mgr.GetStatus(ctx, mbscName, mbscNamespace, kernelVersion, kmmv1beta1.BuildImage, &testMBSC)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(kmmv1beta1.BuildOrSignStatus("")))
+	})
+
 	It("failed flow, GetResourceStatus fails", func() {
 		foundBuild := buildv1.Build{}
 		normalizedKernel := kernel.DNSSafeKernelVersion(kernelVersion)
@@ -99,11 +117,202 @@ var _ = Describe("GetStatus", func() {
 	)
 })
 
+var _ = Describe("GetFailure", func() {
+	var (
+		ctrl                *gomock.Controller
+		clnt                *client.MockClient
+		mockResourceManager *MockResourceManager
+		mgr                 Manager
+	)
+	const (
+		mbscName      = "some-name"
+		mbscNamespace = "some-namespace"
+		kernelVersion = "some version"
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mockResourceManager = NewMockResourceManager(ctrl)
+		mgr = NewManager(clnt, mockResourceManager, nil, scheme)
+	})
+
+	ctx := context.Background()
+	testMBSC := kmmv1beta1.ModuleBuildSignConfig{}
+	normalizedKernel := kernel.DNSSafeKernelVersion(kernelVersion)
+
+	It("should return an error if the resource could not be found", func() {
+		mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, normalizedKernel,
+			kmmv1beta1.BuildImage, &testMBSC).
+			Return(nil, ErrNoMatchingBuildSignResource)
+
+		_, _, err := mgr.GetFailure(ctx, mbscName, mbscNamespace, kernelVersion, kmmv1beta1.BuildImage, &testMBSC)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the failure reason could not be determined", func() {
+		foundBuild := buildv1.Build{}
+		gomock.InOrder(
+			mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, normalizedKernel,
+				kmmv1beta1.BuildImage, &testMBSC).
+				Return(&foundBuild, nil),
+			mockResourceManager.EXPECT().GetResourceFailureReason(&foundBuild).
+				Return(kmmv1beta1.BuildOrSignFailureReason(""), fmt.Errorf("some error")),
+		)
+
+		_, _, err := mgr.GetFailure(ctx, mbscName, mbscNamespace, kernelVersion, kmmv1beta1.BuildImage, &testMBSC)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the attempt could not be determined", func() {
+		foundBuild := buildv1.Build{}
+		gomock.InOrder(
+			mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, normalizedKernel,
+				kmmv1beta1.BuildImage, &testMBSC).
+				Return(&foundBuild, nil),
+			mockResourceManager.EXPECT().GetResourceFailureReason(&foundBuild).Return(kmmv1beta1.FailurePush, nil),
+			mockResourceManager.EXPECT().GetResourceAttempt(&foundBuild).Return(int32(0), fmt.Errorf("some error")),
+		)
+
+		_, _, err := mgr.GetFailure(ctx, mbscName, mbscNamespace, kernelVersion, kmmv1beta1.BuildImage, &testMBSC)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return the failure reason and the attempt", func() {
+		foundBuild := buildv1.Build{}
+		gomock.InOrder(
+			mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, normalizedKernel,
+				kmmv1beta1.SignImage, &testMBSC).
+				Return(&foundBuild, nil),
+			mockResourceManager.EXPECT().GetResourceFailureReason(&foundBuild).Return(kmmv1beta1.FailurePush, nil),
+			mockResourceManager.EXPECT().GetResourceAttempt(&foundBuild).Return(int32(2), nil),
+		)
+
+		reason, attempt, err := mgr.GetFailure(ctx, mbscName, mbscNamespace, kernelVersion, kmmv1beta1.SignImage, &testMBSC)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(Equal(kmmv1beta1.FailurePush))
+		Expect(attempt).To(Equal(int32(2)))
+	})
+})
+
+var _ = Describe("GetLogConfigMap", func() {
+	var (
+		ctrl                *gomock.Controller
+		clnt                *client.MockClient
+		mockResourceManager *MockResourceManager
+		mgr                 Manager
+	)
+	const (
+		mbscName      = "some-name"
+		mbscNamespace = "some-namespace"
+		kernelVersion = "some version"
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mockResourceManager = NewMockResourceManager(ctrl)
+		mgr = NewManager(clnt, mockResourceManager, nil, scheme)
+	})
+
+	ctx := context.Background()
+	testMBSC := kmmv1beta1.ModuleBuildSignConfig{}
+	normalizedKernel := kernel.DNSSafeKernelVersion(kernelVersion)
+
+	It("should return an error if the resource could not be fetched", func() {
+		mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, normalizedKernel,
+			kmmv1beta1.BuildImage, &testMBSC).
+			Return(nil, fmt.Errorf("some error"))
+
+		_, err := mgr.GetLogConfigMap(ctx, mbscName, mbscNamespace, kernelVersion, kmmv1beta1.BuildImage, &testMBSC)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an empty name if the resource does not exist", func() {
+		mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, normalizedKernel,
+			kmmv1beta1.BuildImage, &testMBSC).
+			Return(nil, ErrNoMatchingBuildSignResource)
+
+		name, err := mgr.GetLogConfigMap(ctx, mbscName, mbscNamespace, kernelVersion, kmmv1beta1.BuildImage, &testMBSC)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(BeEmpty())
+	})
+
+	It("should return the ConfigMap recorded on the resource", func() {
+		foundBuild := buildv1.Build{}
+		foundBuild.SetAnnotations(map[string]string{constants.BuildLogAnnotation: "some-log"})
+		mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, normalizedKernel,
+			kmmv1beta1.SignImage, &testMBSC).
+			Return(&foundBuild, nil)
+
+		name, err := mgr.GetLogConfigMap(ctx, mbscName, mbscNamespace, kernelVersion, kmmv1beta1.SignImage, &testMBSC)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("some-log"))
+	})
+})
+
+var _ = Describe("GetSignedFiles", func() {
+	var (
+		ctrl                *gomock.Controller
+		clnt                *client.MockClient
+		mockResourceManager *MockResourceManager
+		mgr                 Manager
+	)
+	const (
+		mbscName      = "some-name"
+		mbscNamespace = "some-namespace"
+		kernelVersion = "some version"
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mockResourceManager = NewMockResourceManager(ctrl)
+		mgr = NewManager(clnt, mockResourceManager, nil, scheme)
+	})
+
+	ctx := context.Background()
+	testMBSC := kmmv1beta1.ModuleBuildSignConfig{}
+	normalizedKernel := kernel.DNSSafeKernelVersion(kernelVersion)
+
+	It("should return an error if the resource could not be fetched", func() {
+		mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, normalizedKernel,
+			kmmv1beta1.SignImage, &testMBSC).
+			Return(nil, fmt.Errorf("some error"))
+
+		_, err := mgr.GetSignedFiles(ctx, mbscName, mbscNamespace, kernelVersion, &testMBSC)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return nothing if the resource does not exist", func() {
+		mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, normalizedKernel,
+			kmmv1beta1.SignImage, &testMBSC).
+			Return(nil, ErrNoMatchingBuildSignResource)
+
+		files, err := mgr.GetSignedFiles(ctx, mbscName, mbscNamespace, kernelVersion, &testMBSC)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(BeEmpty())
+	})
+
+	It("should return the files recorded on the resource", func() {
+		foundBuild := buildv1.Build{}
+		foundBuild.SetAnnotations(map[string]string{constants.SignedFilesAnnotation: "/modules/a.ko\n/modules/b.ko.zst"})
+		mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, normalizedKernel,
+			kmmv1beta1.SignImage, &testMBSC).
+			Return(&foundBuild, nil)
+
+		files, err := mgr.GetSignedFiles(ctx, mbscName, mbscNamespace, kernelVersion, &testMBSC)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(Equal([]string{"/modules/a.ko", "/modules/b.ko.zst"}))
+	})
+})
+
 var _ = Describe("Sync", func() {
 	var (
 		ctrl                *gomock.Controller
 		clnt                *client.MockClient
 		mockResourceManager *MockResourceManager
+		mockLimiter         *MockLimiter
 		mgr                 Manager
 	)
 	const (
@@ -117,7 +326,8 @@ var _ = Describe("Sync", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
 		mockResourceManager = NewMockResourceManager(ctrl)
-		mgr = NewManager(clnt, mockResourceManager, scheme)
+		mockLimiter = NewMockLimiter(ctrl)
+		mgr = NewManager(clnt, mockResourceManager, mockLimiter, scheme)
 	})
 
 	ctx := context.Background()
@@ -162,6 +372,7 @@ var _ = Describe("Sync", func() {
 			mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, kernelVersion,
 				kmmv1beta1.BuildImage, &testMBSC).
 				Return(nil, ErrNoMatchingBuildSignResource),
+			mockLimiter.EXPECT().ShouldQueue(ctx, testMLD).Return(false, nil),
 			mockResourceManager.EXPECT().CreateResource(ctx, &testTemplate).Return(fmt.Errorf("some error")),
 		)
 		err := mgr.Sync(ctx, testMLD, true, kmmv1beta1.BuildImage, &testMBSC)
@@ -178,12 +389,60 @@ var _ = Describe("Sync", func() {
 			mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, kernelVersion,
 				kmmv1beta1.BuildImage, &testMBSC).
 				Return(nil, ErrNoMatchingBuildSignResource),
+			mockLimiter.EXPECT().ShouldQueue(ctx, testMLD).Return(false, nil),
 			mockResourceManager.EXPECT().CreateResource(ctx, &testTemplate).Return(alreadyExistsErr),
 		)
 		err := mgr.Sync(ctx, testMLD, true, kmmv1beta1.BuildImage, &testMBSC)
 		Expect(err).To(BeNil())
 	})
 
+	It("should record the created resource for the limiter", func() {
+		testTemplate := buildv1.Build{ObjectMeta: metav1.ObjectMeta{Name: "some-build", Namespace: mbscNamespace}}
+		passCtx := WithCreatedResources(ctx)
+		gomock.InOrder(
+			mockResourceManager.EXPECT().MakeResourceTemplate(passCtx, testMLD, &testMBSC, true, kmmv1beta1.BuildImage).
+				Return(&testTemplate, nil),
+			mockResourceManager.EXPECT().GetResourceByKernel(passCtx, mbscName, mbscNamespace, kernelVersion,
+				kmmv1beta1.BuildImage, &testMBSC).
+				Return(nil, ErrNoMatchingBuildSignResource),
+			mockLimiter.EXPECT().ShouldQueue(passCtx, testMLD).Return(false, nil),
+			mockResourceManager.EXPECT().CreateResource(passCtx, &testTemplate).Return(nil),
+		)
+		err := mgr.Sync(passCtx, testMLD, true, kmmv1beta1.BuildImage, &testMBSC)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(passCtx.Value(createdResourcesKey{})).To(
+			Equal(sets.New(types.NamespacedName{Name: "some-build", Namespace: mbscNamespace})),
+		)
+	})
+
+	It("ShouldQueue failed", func() {
+		testTemplate := buildv1.Build{}
+		gomock.InOrder(
+			mockResourceManager.EXPECT().MakeResourceTemplate(ctx, testMLD, &testMBSC, true, kmmv1beta1.BuildImage).
+				Return(&testTemplate, nil),
+			mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, kernelVersion,
+				kmmv1beta1.BuildImage, &testMBSC).
+				Return(nil, ErrNoMatchingBuildSignResource),
+			mockLimiter.EXPECT().ShouldQueue(ctx, testMLD).Return(false, fmt.Errorf("some error")),
+		)
+		err := mgr.Sync(ctx, testMLD, true, kmmv1beta1.BuildImage, &testMBSC)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not create the resource if it is queued", func() {
+		testTemplate := buildv1.Build{}
+		gomock.InOrder(
+			mockResourceManager.EXPECT().MakeResourceTemplate(ctx, testMLD, &testMBSC, true, kmmv1beta1.BuildImage).
+				Return(&testTemplate, nil),
+			mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, kernelVersion,
+				kmmv1beta1.BuildImage, &testMBSC).
+				Return(nil, ErrNoMatchingBuildSignResource),
+			mockLimiter.EXPECT().ShouldQueue(ctx, testMLD).Return(true, nil),
+		)
+		err := mgr.Sync(ctx, testMLD, true, kmmv1beta1.BuildImage, &testMBSC)
+		Expect(err).To(MatchError(ErrResourceQueued))
+	})
+
 	It("IsResourceChanged failed", func() {
 		testTemplate := buildv1.Build{}
 		testBuild := buildv1.Build{}
@@ -237,6 +496,7 @@ var _ = Describe("Sync", func() {
 		mockResourceManager.EXPECT().GetResourceByKernel(ctx, mbscName, mbscNamespace, kernelVersion,
 			testAction, &testMBSC).Return(&existingTestBuild, getBuildError)
 		if !buildExists {
+			mockLimiter.EXPECT().ShouldQueue(ctx, testMLD).Return(false, nil)
 			mockResourceManager.EXPECT().CreateResource(ctx, &testBuildTemplate).Return(nil)
 			goto executeTestFunction
 		}
@@ -276,7 +536,7 @@ var _ = Describe("GarbageCollect", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
 		mockResourceManager = NewMockResourceManager(ctrl)
-		mgr = NewManager(clnt, mockResourceManager, scheme)
+		mgr = NewManager(clnt, mockResourceManager, nil, scheme)
 	})
 
 	ctx := context.Background()
@@ -345,3 +605,35 @@ var _ = Describe("GarbageCollect", func() {
 		Expect(res).To(Equal([]string{"buildSuccess"}))
 	})
 })
+
+var _ = Describe("GetBuildInputsHash", func() {
+	var (
+		ctrl                *gomock.Controller
+		mockResourceManager *MockResourceManager
+		mgr                 Manager
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockResourceManager = NewMockResourceManager(ctrl)
+		mgr = NewManager(client.NewMockClient(ctrl), mockResourceManager, nil, scheme)
+	})
+
+	ctx := context.Background()
+	mld := &api.ModuleLoaderData{}
+
+	It("should return an error if the hash could not be computed", func() {
+		mockResourceManager.EXPECT().GetBuildInputsHash(ctx, mld).Return("", fmt.Errorf("some error"))
+
+		_, err := mgr.GetBuildInputsHash(ctx, mld)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return the hash", func() {
+		mockResourceManager.EXPECT().GetBuildInputsHash(ctx, mld).Return("1234", nil)
+
+		hash, err := mgr.GetBuildInputsHash(ctx, mld)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hash).To(Equal("1234"))
+	})
+})
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: limiter.go
+//
+// Generated by this command:
+//
+//	mockgen -source=limiter.go -package=buildsign -destination=mock_limiter.go
+//
+// Package buildsign is a generated GoMock package.
+package buildsign
+
+import (
+	context "context"
+	reflect "reflect"
+
+	api "github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLimiter is a mock of Limiter interface.
+type MockLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimiterMockRecorder
+}
+
+// MockLimiterMockRecorder is the mock recorder for MockLimiter.
+type MockLimiterMockRecorder struct {
+	mock *MockLimiter
+}
+
+// NewMockLimiter creates a new mock instance.
+func NewMockLimiter(ctrl *gomock.Controller) *MockLimiter {
+	mock := &MockLimiter{ctrl: ctrl}
+	mock.recorder = &MockLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLimiter) EXPECT() *MockLimiterMockRecorder {
+	return m.recorder
+}
+
+// ShouldQueue mocks base method.
+func (m *MockLimiter) ShouldQueue(ctx context.Context, mld *api.ModuleLoaderData) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShouldQueue", ctx, mld)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShouldQueue indicates an expected call of ShouldQueue.
+func (mr *MockLimiterMockRecorder) ShouldQueue(ctx, mld any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldQueue", reflect.TypeOf((*MockLimiter)(nil).ShouldQueue), ctx, mld)
+}
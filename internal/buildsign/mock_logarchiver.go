@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: logarchiver.go
+//
+// Generated by this command:
+//
+//	mockgen -source=logarchiver.go -package=buildsign -destination=mock_logarchiver.go
+//
+// Package buildsign is a generated GoMock package.
+package buildsign
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1 "github.com/openshift/api/build/v1"
+	gomock "go.uber.org/mock/gomock"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MockLogArchiver is a mock of LogArchiver interface.
+type MockLogArchiver struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogArchiverMockRecorder
+}
+
+// MockLogArchiverMockRecorder is the mock recorder for MockLogArchiver.
+type MockLogArchiverMockRecorder struct {
+	mock *MockLogArchiver
+}
+
+// NewMockLogArchiver creates a new mock instance.
+func NewMockLogArchiver(ctrl *gomock.Controller) *MockLogArchiver {
+	mock := &MockLogArchiver{ctrl: ctrl}
+	mock.recorder = &MockLogArchiverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogArchiver) EXPECT() *MockLogArchiverMockRecorder {
+	return m.recorder
+}
+
+// Archive mocks base method.
+func (m *MockLogArchiver) Archive(ctx context.Context, build *v1.Build, owner client.Object) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx, build, owner)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockLogArchiverMockRecorder) Archive(ctx, build, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockLogArchiver)(nil).Archive), ctx, build, owner)
+}
+
+// MockPodLogReader is a mock of PodLogReader interface.
+type MockPodLogReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockPodLogReaderMockRecorder
+}
+
+// MockPodLogReaderMockRecorder is the mock recorder for MockPodLogReader.
+type MockPodLogReaderMockRecorder struct {
+	mock *MockPodLogReader
+}
+
+// NewMockPodLogReader creates a new mock instance.
+func NewMockPodLogReader(ctrl *gomock.Controller) *MockPodLogReader {
+	mock := &MockPodLogReader{ctrl: ctrl}
+	mock.recorder = &MockPodLogReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPodLogReader) EXPECT() *MockPodLogReaderMockRecorder {
+	return m.recorder
+}
+
+// ReadLogs mocks base method.
+func (m *MockPodLogReader) ReadLogs(ctx context.Context, namespace, podName, container string, tailLines int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadLogs", ctx, namespace, podName, container, tailLines)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadLogs indicates an expected call of ReadLogs.
+func (mr *MockPodLogReaderMockRecorder) ReadLogs(ctx, namespace, podName, container, tailLines any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadLogs", reflect.TypeOf((*MockPodLogReader)(nil).ReadLogs), ctx, namespace, podName, container, tailLines)
+}
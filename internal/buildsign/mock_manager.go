@@ -56,6 +56,67 @@ func (mr *MockManagerMockRecorder) GarbageCollect(ctx, name, namespace, action,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GarbageCollect", reflect.TypeOf((*MockManager)(nil).GarbageCollect), ctx, name, namespace, action, owner)
 }
 
+// GetBuildInputsHash mocks base method.
+func (m *MockManager) GetBuildInputsHash(ctx context.Context, mld *api.ModuleLoaderData) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBuildInputsHash", ctx, mld)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBuildInputsHash indicates an expected call of GetBuildInputsHash.
+func (mr *MockManagerMockRecorder) GetBuildInputsHash(ctx, mld any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuildInputsHash", reflect.TypeOf((*MockManager)(nil).GetBuildInputsHash), ctx, mld)
+}
+
+// GetFailure mocks base method.
+func (m *MockManager) GetFailure(ctx context.Context, name, namespace, kernelVersion string, action v1beta1.BuildOrSignAction, owner v1.Object) (v1beta1.BuildOrSignFailureReason, int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFailure", ctx, name, namespace, kernelVersion, action, owner)
+	ret0, _ := ret[0].(v1beta1.BuildOrSignFailureReason)
+	ret1, _ := ret[1].(int32)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFailure indicates an expected call of GetFailure.
+func (mr *MockManagerMockRecorder) GetFailure(ctx, name, namespace, kernelVersion, action, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFailure", reflect.TypeOf((*MockManager)(nil).GetFailure), ctx, name, namespace, kernelVersion, action, owner)
+}
+
+// GetLogConfigMap mocks base method.
+func (m *MockManager) GetLogConfigMap(ctx context.Context, name, namespace, kernelVersion string, action v1beta1.BuildOrSignAction, owner v1.Object) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogConfigMap", ctx, name, namespace, kernelVersion, action, owner)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogConfigMap indicates an expected call of GetLogConfigMap.
+func (mr *MockManagerMockRecorder) GetLogConfigMap(ctx, name, namespace, kernelVersion, action, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogConfigMap", reflect.TypeOf((*MockManager)(nil).GetLogConfigMap), ctx, name, namespace, kernelVersion, action, owner)
+}
+
+// GetSignedFiles mocks base method.
+func (m *MockManager) GetSignedFiles(ctx context.Context, name, namespace, kernelVersion string, owner v1.Object) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSignedFiles", ctx, name, namespace, kernelVersion, owner)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSignedFiles indicates an expected call of GetSignedFiles.
+func (mr *MockManagerMockRecorder) GetSignedFiles(ctx, name, namespace, kernelVersion, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSignedFiles", reflect.TypeOf((*MockManager)(nil).GetSignedFiles), ctx, name, namespace, kernelVersion, owner)
+}
+
 // GetStatus mocks base method.
 func (m *MockManager) GetStatus(ctx context.Context, name, namespace, kernelVersion string, action v1beta1.BuildOrSignAction, owner v1.Object) (v1beta1.BuildOrSignStatus, error) {
 	m.ctrl.T.Helper()
@@ -69,6 +69,51 @@ func (mr *MockResourceManagerMockRecorder) DeleteResource(ctx, obj any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResource", reflect.TypeOf((*MockResourceManager)(nil).DeleteResource), ctx, obj)
 }
 
+// GetActiveResources mocks base method.
+func (m *MockResourceManager) GetActiveResources(ctx context.Context, namespace string) ([]v1.Object, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveResources", ctx, namespace)
+	ret0, _ := ret[0].([]v1.Object)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveResources indicates an expected call of GetActiveResources.
+func (mr *MockResourceManagerMockRecorder) GetActiveResources(ctx, namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveResources", reflect.TypeOf((*MockResourceManager)(nil).GetActiveResources), ctx, namespace)
+}
+
+// GetBuildInputsHash mocks base method.
+func (m *MockResourceManager) GetBuildInputsHash(ctx context.Context, mld *api.ModuleLoaderData) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBuildInputsHash", ctx, mld)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBuildInputsHash indicates an expected call of GetBuildInputsHash.
+func (mr *MockResourceManagerMockRecorder) GetBuildInputsHash(ctx, mld any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuildInputsHash", reflect.TypeOf((*MockResourceManager)(nil).GetBuildInputsHash), ctx, mld)
+}
+
+// GetBuilderImage mocks base method.
+func (m *MockResourceManager) GetBuilderImage(ctx context.Context, kernelVersion string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBuilderImage", ctx, kernelVersion)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBuilderImage indicates an expected call of GetBuilderImage.
+func (mr *MockResourceManagerMockRecorder) GetBuilderImage(ctx, kernelVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuilderImage", reflect.TypeOf((*MockResourceManager)(nil).GetBuilderImage), ctx, kernelVersion)
+}
+
 // GetModuleResources mocks base method.
 func (m *MockResourceManager) GetModuleResources(ctx context.Context, modName, namespace string, resourceType v1beta1.BuildOrSignAction, owner v1.Object) ([]v1.Object, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +129,21 @@ func (mr *MockResourceManagerMockRecorder) GetModuleResources(ctx, modName, name
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleResources", reflect.TypeOf((*MockResourceManager)(nil).GetModuleResources), ctx, modName, namespace, resourceType, owner)
 }
 
+// GetResourceAttempt mocks base method.
+func (m *MockResourceManager) GetResourceAttempt(obj v1.Object) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourceAttempt", obj)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceAttempt indicates an expected call of GetResourceAttempt.
+func (mr *MockResourceManagerMockRecorder) GetResourceAttempt(obj any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceAttempt", reflect.TypeOf((*MockResourceManager)(nil).GetResourceAttempt), obj)
+}
+
 // GetResourceByKernel mocks base method.
 func (m *MockResourceManager) GetResourceByKernel(ctx context.Context, name, namespace, targetKernel string, resourceType v1beta1.BuildOrSignAction, owner v1.Object) (v1.Object, error) {
 	m.ctrl.T.Helper()
@@ -99,6 +159,21 @@ func (mr *MockResourceManagerMockRecorder) GetResourceByKernel(ctx, name, namesp
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceByKernel", reflect.TypeOf((*MockResourceManager)(nil).GetResourceByKernel), ctx, name, namespace, targetKernel, resourceType, owner)
 }
 
+// GetResourceFailureReason mocks base method.
+func (m *MockResourceManager) GetResourceFailureReason(obj v1.Object) (v1beta1.BuildOrSignFailureReason, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourceFailureReason", obj)
+	ret0, _ := ret[0].(v1beta1.BuildOrSignFailureReason)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceFailureReason indicates an expected call of GetResourceFailureReason.
+func (mr *MockResourceManagerMockRecorder) GetResourceFailureReason(obj any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceFailureReason", reflect.TypeOf((*MockResourceManager)(nil).GetResourceFailureReason), obj)
+}
+
 // GetResourceStatus mocks base method.
 func (m *MockResourceManager) GetResourceStatus(obj v1.Object) (Status, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: signedfiles.go
+//
+// Generated by this command:
+//
+//	mockgen -source=signedfiles.go -package=buildsign -destination=mock_signedfiles.go
+//
+// Package buildsign is a generated GoMock package.
+package buildsign
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1 "github.com/openshift/api/build/v1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSignedFilesReader is a mock of SignedFilesReader interface.
+type MockSignedFilesReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignedFilesReaderMockRecorder
+}
+
+// MockSignedFilesReaderMockRecorder is the mock recorder for MockSignedFilesReader.
+type MockSignedFilesReaderMockRecorder struct {
+	mock *MockSignedFilesReader
+}
+
+// NewMockSignedFilesReader creates a new mock instance.
+func NewMockSignedFilesReader(ctrl *gomock.Controller) *MockSignedFilesReader {
+	mock := &MockSignedFilesReader{ctrl: ctrl}
+	mock.recorder = &MockSignedFilesReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSignedFilesReader) EXPECT() *MockSignedFilesReaderMockRecorder {
+	return m.recorder
+}
+
+// Read mocks base method.
+func (m *MockSignedFilesReader) Read(ctx context.Context, build *v1.Build) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", ctx, build)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockSignedFilesReaderMockRecorder) Read(ctx, build any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockSignedFilesReader)(nil).Read), ctx, build)
+}
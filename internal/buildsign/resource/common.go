@@ -6,6 +6,8 @@ import (
 	"embed"
 	"fmt"
 	"os"
+	"path"
+	"slices"
 	"strings"
 	"text/template"
 
@@ -13,23 +15,46 @@ import (
 	buildv1 "github.com/openshift/api/build/v1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildsign"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-const dtkBuildArg = "DTK_AUTO"
+const (
+	dtkBuildArg          = "DTK_AUTO"
+	builderImageBuildArg = "KERNEL_BUILDER_IMAGE"
+)
+
+// signVerificationFailedMarkerPrefix prefixes the error printed by the sign when a module is not signed as expected
+// after signing. The builder echoes the RUN commands in its log, so the full marker is only assembled when the sign
+// fails.
+const signVerificationFailedMarkerPrefix = "KMM_SIGN_VERIFICATION"
 
 type TemplateData struct {
-	FilesToSign   []string
-	SignImage     string
-	UnsignedImage string
-	DirName       string
+	FilesToSign       []string
+	SignImage         string
+	UnsignedImage     string
+	DirName           string
+	SigningKey        string
+	PKCS11ModulePath  string
+	SigningServiceURL string
+	SigningServiceTLS bool
+
+	AutoDiscoverDir     string
+	AutoDiscoverInclude string
+	AutoDiscoverExclude string
+
+	VerificationFailedMarkerPrefix string
+	SignedFilesMarker              string
 }
 
 //go:embed templates
@@ -39,7 +64,7 @@ var tmpl = template.Must(
 	template.ParseFS(templateFS, "templates/Dockerfile.gotmpl"),
 )
 
-func (rm *resourceManager) buildSpec(mld *api.ModuleLoaderData, dockerfileData, destinationImg string,
+func (rm *resourceManager) buildSpec(ctx context.Context, mld *api.ModuleLoaderData, dockerfileData, destinationImg string,
 	pushImage bool) (*buildv1.BuildSpec, error) {
 
 	buildConfig := mld.Build
@@ -50,12 +75,16 @@ func (rm *resourceManager) buildSpec(mld *api.ModuleLoaderData, dockerfileData,
 		{Name: "MOD_NAME", Value: mld.Name},
 		{Name: "MOD_NAMESPACE", Value: mld.Namespace},
 	}
-	if strings.Contains(dockerfileData, dtkBuildArg) {
-		dtkImage, err := rm.kernelOsDtkMapping.GetImage(mld.KernelVersion)
+	if strings.Contains(dockerfileData, dtkBuildArg) || strings.Contains(dockerfileData, builderImageBuildArg) {
+		builderImage, err := rm.GetBuilderImage(ctx, mld.KernelVersion)
 		if err != nil {
-			return nil, fmt.Errorf("could not get DTK image for kernel %v: %v", mld.KernelVersion, err)
+			return nil, err
+		}
+		for _, name := range []string{dtkBuildArg, builderImageBuildArg} {
+			if strings.Contains(dockerfileData, name) {
+				overrides = append(overrides, kmmv1beta1.BuildArg{Name: name, Value: builderImage})
+			}
 		}
-		overrides = append(overrides, kmmv1beta1.BuildArg{Name: dtkBuildArg, Value: dtkImage})
 	}
 	buildArgs := rm.buildArgOverrider.ApplyBuildArgOverrides(
 		buildConfig.BuildArgs,
@@ -81,13 +110,21 @@ func (rm *resourceManager) buildSpec(mld *api.ModuleLoaderData, dockerfileData,
 
 	volumes := makeBuildResourceVolumes(buildConfig)
 
+	// the ref of the Git build context may be a branch or a tag: build the commit it points to, so that the Build
+	// hash tracks the source that is actually built and the image is rebuilt when the ref moves
+	gitCommit := ""
+	if g := buildConfig.Git; g != nil {
+		commit, err := rm.gitAPI.ResolveRef(ctx, g.URI, g.Ref, mld.Namespace, g.SourceSecret)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve ref %q of Git repository %s: %v", g.Ref, g.URI, err)
+		}
+		gitCommit = commit
+	}
+
 	spec := &buildv1.BuildSpec{
 		CommonSpec: buildv1.CommonSpec{
 			ServiceAccount: constants.OCPBuilderServiceAccountName,
-			Source: buildv1.BuildSource{
-				Dockerfile: &dockerfileData,
-				Type:       buildv1.BuildSourceDockerfile,
-			},
+			Source:         buildSource(buildConfig, dockerfileData, gitCommit),
 			Strategy: buildv1.BuildStrategy{
 				Type: buildv1.DockerBuildStrategyType,
 				DockerStrategy: &buildv1.DockerBuildStrategy{
@@ -96,15 +133,100 @@ func (rm *resourceManager) buildSpec(mld *api.ModuleLoaderData, dockerfileData,
 					PullSecret: mld.ImageRepoSecret,
 				},
 			},
-			Output:         buildTarget,
-			NodeSelector:   selector,
-			MountTrustedCA: ptr.To(true),
+			Output:                    buildTarget,
+			NodeSelector:              selector,
+			MountTrustedCA:            ptr.To(true),
+			CompletionDeadlineSeconds: completionDeadlineSeconds(buildConfig.RetryPolicy),
 		},
 	}
 
 	return spec, nil
 }
 
+// GetBuilderImage returns the image in which the kernel modules are built for kernelVersion: the image from the
+// KernelBuilderCatalogs if one of their entries matches, or the Driver Toolkit image otherwise.
+func (rm *resourceManager) GetBuilderImage(ctx context.Context, kernelVersion string) (string, error) {
+	builderImage, err := rm.builderCatalog.GetImage(ctx, kernelVersion)
+	if err != nil {
+		return "", fmt.Errorf("could not get the builder image for kernel %v from the KernelBuilderCatalogs: %v", kernelVersion, err)
+	}
+	if builderImage != "" {
+		return builderImage, nil
+	}
+
+	dtkImage, err := rm.dtkMapping.GetImage(ctx, kernelVersion)
+	if err != nil {
+		return "", fmt.Errorf("could not get DTK image for kernel %v: %v", kernelVersion, err)
+	}
+	return dtkImage, nil
+}
+
+// completionDeadlineSeconds returns the deadline of the Build from the timeout of the retry policy, if any.
+func completionDeadlineSeconds(retryPolicy *kmmv1beta1.RetryPolicy) *int64 {
+	if retryPolicy == nil || retryPolicy.Timeout == nil {
+		return nil
+	}
+	return ptr.To(int64(retryPolicy.Timeout.Seconds()))
+}
+
+// resourceAnnotations returns the annotations of a build or sign resource.
+// The attempt is only recorded for retries, so that resources created before retries were supported are not
+// considered changed.
+func resourceAnnotations(hash uint64, attempt int32, logRetention *int32) map[string]string {
+	annotations := map[string]string{constants.ResourceHashAnnotation: fmt.Sprintf("%d", hash)}
+	if attempt > 0 {
+		annotations[constants.ResourceAttemptAnnotation] = fmt.Sprintf("%d", attempt)
+	}
+	if logRetention != nil {
+		annotations[constants.BuildLogRetentionAnnotation] = fmt.Sprintf("%d", *logRetention)
+	}
+	return annotations
+}
+
+// buildLogRetention returns the number of archived logs the Module keeps per kernel, or nil to use the default of the
+// operator.
+func buildLogRetention(mld *api.ModuleLoaderData) *int32 {
+	if mld.Build == nil {
+		return nil
+	}
+	return mld.Build.LogRetention
+}
+
+// buildSource returns the source of the Build: the Dockerfile, plus the additional build context from ConfigMaps,
+// Secrets and Git, if any. The Git repository is checked out at gitCommit, the commit its ref resolves to.
+func buildSource(buildConfig *kmmv1beta1.Build, dockerfileData, gitCommit string) buildv1.BuildSource {
+	source := buildv1.BuildSource{
+		Dockerfile: &dockerfileData,
+		Type:       buildv1.BuildSourceDockerfile,
+	}
+
+	for _, cm := range buildConfig.ContextConfigMaps {
+		source.ConfigMaps = append(source.ConfigMaps, buildv1.ConfigMapBuildSource{
+			ConfigMap:      cm.ConfigMap,
+			DestinationDir: cm.DestinationDir,
+		})
+	}
+
+	for _, s := range buildConfig.ContextSecrets {
+		source.Secrets = append(source.Secrets, buildv1.SecretBuildSource{
+			Secret:         s.Secret,
+			DestinationDir: s.DestinationDir,
+		})
+	}
+
+	if git := buildConfig.Git; git != nil {
+		source.Type = buildv1.BuildSourceGit
+		source.Git = &buildv1.GitBuildSource{
+			URI: git.URI,
+			Ref: gitCommit,
+		}
+		source.ContextDir = git.ContextDir
+		source.SourceSecret = git.SourceSecret
+	}
+
+	return source
+}
+
 func signSpec(mld *api.ModuleLoaderData, dockerfileData string, pushImage bool) buildv1.BuildSpec {
 
 	buildTarget := buildv1.BuildOutput{}
@@ -134,9 +256,10 @@ func signSpec(mld *api.ModuleLoaderData, dockerfileData string, pushImage bool)
 					PullSecret: mld.ImageRepoSecret,
 				},
 			},
-			Output:         buildTarget,
-			NodeSelector:   mld.Selector,
-			MountTrustedCA: ptr.To(true),
+			Output:                    buildTarget,
+			NodeSelector:              mld.Selector,
+			MountTrustedCA:            ptr.To(true),
+			CompletionDeadlineSeconds: completionDeadlineSeconds(mld.Sign.RetryPolicy),
 		},
 	}
 
@@ -157,27 +280,152 @@ func envVarsFromKMMBuildArgs(args []kmmv1beta1.BuildArg) []v1.EnvVar {
 	return ev
 }
 
-func (rm *resourceManager) getBuildHashAnnotationValue(ctx context.Context, dockerfileData string) (uint64, error) {
+// buildInputs contains everything that determines the outcome of a build.
+// A change in any of these fields results in a new Build.
+type buildInputs struct {
+	Source         buildv1.BuildSource
+	BuildArgs      []v1.EnvVar
+	SecretsData    map[string]map[string][]byte
+	ConfigMapsData map[string]configMapData
+	BaseImages     map[string]string
+}
 
-	sourceConfig := buildv1.BuildSource{
-		Dockerfile: &dockerfileData,
-		Type:       buildv1.BuildSourceDockerfile,
+type configMapData struct {
+	Data       map[string]string
+	BinaryData map[string][]byte
+}
+
+func (rm *resourceManager) getBuildHashAnnotationValue(ctx context.Context, mld *api.ModuleLoaderData,
+	buildSpec *buildv1.BuildSpec) (uint64, error) {
+
+	inputs := buildInputs{
+		Source:         buildSpec.Source,
+		BuildArgs:      buildSpec.Strategy.DockerStrategy.BuildArgs,
+		SecretsData:    make(map[string]map[string][]byte, len(mld.Build.Secrets)+len(mld.Build.ContextSecrets)),
+		ConfigMapsData: make(map[string]configMapData, len(mld.Build.ContextConfigMaps)),
+		BaseImages:     make(map[string]string),
+	}
+
+	secretNames := make([]string, 0, len(mld.Build.Secrets)+len(mld.Build.ContextSecrets))
+	for _, s := range mld.Build.Secrets {
+		secretNames = append(secretNames, s.Name)
+	}
+	for _, s := range mld.Build.ContextSecrets {
+		secretNames = append(secretNames, s.Secret.Name)
+	}
+
+	for _, name := range secretNames {
+		if _, ok := inputs.SecretsData[name]; ok {
+			continue
+		}
+		secret := v1.Secret{}
+		namespacedName := types.NamespacedName{Name: name, Namespace: mld.Namespace}
+		if err := rm.client.Get(ctx, namespacedName, &secret); err != nil {
+			return 0, fmt.Errorf("failed to get build Secret %s: %v", namespacedName, err)
+		}
+		inputs.SecretsData[name] = secret.Data
+	}
+
+	for _, cm := range mld.Build.ContextConfigMaps {
+		configMap := v1.ConfigMap{}
+		namespacedName := types.NamespacedName{Name: cm.ConfigMap.Name, Namespace: mld.Namespace}
+		if err := rm.client.Get(ctx, namespacedName, &configMap); err != nil {
+			return 0, fmt.Errorf("failed to get build context ConfigMap %s: %v", namespacedName, err)
+		}
+		inputs.ConfigMapsData[cm.ConfigMap.Name] = configMapData{Data: configMap.Data, BinaryData: configMap.BinaryData}
 	}
 
-	sourceConfigHash, err := hashstructure.Hash(sourceConfig, hashstructure.FormatV2, nil)
+	for _, img := range getBaseImages(*buildSpec.Source.Dockerfile, inputs.BuildArgs) {
+		digest, err := rm.registryAPI.GetDigest(ctx, img, &mld.Build.BaseImageRegistryTLS, mld.Namespace, mld.ImageRepoSecret)
+		if err != nil {
+			// the builder may still be able to pull the image, e.g. through a mirror that KMM cannot reach; only
+			// the reference of the image is tracked until its digest can be resolved
+			log.FromContext(ctx).Info(utils.WarnString("Could not resolve the digest of base image, using its reference instead"),
+				"image", img, "error", err.Error())
+			digest = ""
+		}
+		inputs.BaseImages[img] = digest
+	}
+
+	hashValue, err := hashstructure.Hash(inputs, hashstructure.FormatV2, nil)
 	if err != nil {
-		return 0, fmt.Errorf("could not hash build's Buildsource template: %v", err)
+		return 0, fmt.Errorf("could not hash build's inputs: %v", err)
+	}
+
+	return hashValue, nil
+}
+
+// getBaseImages returns the images referenced by the FROM instructions of dockerfile, after expanding the build
+// arguments and the global ARG defaults. References to previous build stages and to scratch are ignored.
+func getBaseImages(dockerfile string, buildArgs []v1.EnvVar) []string {
+	args := make(map[string]string, len(buildArgs))
+	for _, ba := range buildArgs {
+		args[ba.Name] = ba.Value
 	}
 
-	return sourceConfigHash, nil
+	argDefaults := make(map[string]string)
+	stages := sets.New[string]()
+	images := make([]string, 0)
+	seenFrom := false
+
+	for _, line := range strings.Split(dockerfile, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "ARG":
+			// only ARGs declared before the first FROM can be used in FROM instructions
+			if !seenFrom {
+				name, value, _ := strings.Cut(fields[1], "=")
+				argDefaults[name] = strings.Trim(value, `"'`)
+			}
+		case "FROM":
+			seenFrom = true
+
+			params := fields[1:]
+			for len(params) > 0 && strings.HasPrefix(params[0], "--") {
+				params = params[1:]
+			}
+			if len(params) == 0 {
+				continue
+			}
+
+			image := os.Expand(params[0], func(key string) string {
+				if v, ok := args[key]; ok {
+					return v
+				}
+				return argDefaults[key]
+			})
+
+			if image != "" && image != "scratch" && !stages.Has(image) && !slices.Contains(images, image) {
+				images = append(images, image)
+			}
+
+			if len(params) >= 3 && strings.EqualFold(params[1], "as") {
+				stages.Insert(params[2])
+			}
+		}
+	}
+
+	return images
 }
 
+// getSignHashAnnotationValue hashes the sign's spec with the signing keys.
+// When the private key is held by a PKCS#11 token, privateSecret is empty and only the certificate is hashed.
 func (rm *resourceManager) getSignHashAnnotationValue(ctx context.Context, privateSecret, publicSecret, namespace string,
-	signSpec *buildv1.BuildSpec) (uint64, error) {
+	signSpec *buildv1.BuildSpec, buildInputsHash string) (uint64, error) {
 
-	privateKeyData, err := rm.getSecretData(ctx, privateSecret, constants.PrivateSignDataKey, namespace)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get private secret %s for signing: %v", privateSecret, err)
+	var (
+		privateKeyData []byte
+		err            error
+	)
+	if privateSecret != "" {
+		privateKeyData, err = rm.getSecretData(ctx, privateSecret, constants.PrivateSignDataKey, namespace)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get private secret %s for signing: %v", privateSecret, err)
+		}
 	}
 	publicKeyData, err := rm.getSecretData(ctx, publicSecret, constants.PublicSignDataKey, namespace)
 	if err != nil {
@@ -185,13 +433,15 @@ func (rm *resourceManager) getSignHashAnnotationValue(ctx context.Context, priva
 	}
 
 	dataToHash := struct {
-		SignSpec       *buildv1.BuildSpec
-		PrivateKeyData []byte
-		PublicKeyData  []byte
+		SignSpec        *buildv1.BuildSpec
+		PrivateKeyData  []byte
+		PublicKeyData   []byte
+		BuildInputsHash string
 	}{
-		SignSpec:       signSpec,
-		PrivateKeyData: privateKeyData,
-		PublicKeyData:  publicKeyData,
+		SignSpec:        signSpec,
+		PrivateKeyData:  privateKeyData,
+		PublicKeyData:   publicKeyData,
+		BuildInputsHash: buildInputsHash,
 	}
 	hashValue, err := hashstructure.Hash(dataToHash, hashstructure.FormatV2, nil)
 	if err != nil {
@@ -270,11 +520,11 @@ func (rm *resourceManager) makeBuildTemplate(ctx context.Context, mld *api.Modul
 		return nil, fmt.Errorf("failed to get dockerfile data from configmap: %v", err)
 	}
 
-	buildSpec, err := rm.buildSpec(mld, dockerfileData, mld.ContainerImage, pushImage)
+	buildSpec, err := rm.buildSpec(ctx, mld, dockerfileData, mld.ContainerImage, pushImage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate Build spec: %v", err)
 	}
-	sourceConfigHash, err := rm.getBuildHashAnnotationValue(ctx, dockerfileData)
+	buildInputsHash, err := rm.getBuildHashAnnotationValue(ctx, mld, buildSpec)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get build annotation value: %v", err)
 	}
@@ -284,7 +534,7 @@ func (rm *resourceManager) makeBuildTemplate(ctx context.Context, mld *api.Modul
 			Name:        mld.Name + "-build-" + mld.KernelNormalizedVersion,
 			Namespace:   mld.Namespace,
 			Labels:      resourceLabels(mld.Name, mld.KernelNormalizedVersion, kmmv1beta1.BuildImage),
-			Annotations: map[string]string{constants.ResourceHashAnnotation: fmt.Sprintf("%d", sourceConfigHash)},
+			Annotations: resourceAnnotations(buildInputsHash, mld.Attempt, buildLogRetention(mld)),
 			Finalizers:  []string{constants.GCDelayFinalizer, constants.JobEventFinalizer},
 		},
 		Spec: *buildSpec,
@@ -302,16 +552,58 @@ func (rm *resourceManager) makeSignTemplate(ctx context.Context, mld *api.Module
 
 	signConfig := mld.Sign
 
-	var buf bytes.Buffer
+	var (
+		buf             bytes.Buffer
+		buildInputsHash string
+	)
 
 	td := TemplateData{
-		FilesToSign: mld.Sign.FilesToSign,
-		SignImage:   os.Getenv("RELATED_IMAGE_SIGN"),
-		DirName:     mld.Modprobe.DirName,
+		FilesToSign:                    mld.Sign.FilesToSign,
+		SignImage:                      os.Getenv("RELATED_IMAGE_SIGN"),
+		DirName:                        mld.Modprobe.DirName,
+		VerificationFailedMarkerPrefix: signVerificationFailedMarkerPrefix,
+		SignedFilesMarker:              buildsign.SignedFilesLogMarker,
+	}
+
+	if autoDiscover := signConfig.AutoDiscover; autoDiscover != nil {
+		td.AutoDiscoverDir = path.Join(mld.Modprobe.DirName, "lib/modules", mld.KernelVersion)
+		// the patterns are alternatives of shell case statements
+		td.AutoDiscoverInclude = strings.Join(autoDiscover.Include, "|")
+		td.AutoDiscoverExclude = strings.Join(autoDiscover.Exclude, "|")
+	}
+
+	privateKeySecret := ""
+	switch {
+	case signConfig.SigningService != nil:
+		// the signature is computed by the external service and appended by sign-file
+		td.SigningServiceURL = signConfig.SigningService.URL
+		if td.SigningServiceURL == "" {
+			td.SigningServiceURL = rm.signingServiceURL
+		}
+		if td.SigningServiceURL == "" {
+			return nil, fmt.Errorf("no signing service URL given")
+		}
+		td.SigningServiceTLS = signConfig.SigningService.TLSSecret != nil
+	case signConfig.PKCS11 != nil:
+		// sign-file accepts a PKCS#11 URI in place of the private key file
+		td.SigningKey = "'" + signConfig.PKCS11.URI + "'"
+		td.PKCS11ModulePath = signConfig.PKCS11.ModulePath
+	case signConfig.KeySecret != nil:
+		td.SigningKey = "/run/secrets/key/key"
+		privateKeySecret = signConfig.KeySecret.Name
+	default:
+		return nil, fmt.Errorf("no signing key given")
 	}
 
 	if module.ShouldBeBuilt(mld) {
 		td.UnsignedImage = mld.ContainerImage
+
+		// the image must be signed again whenever it is rebuilt
+		hash, err := rm.GetBuildInputsHash(ctx, mld)
+		if err != nil {
+			return nil, fmt.Errorf("could not get the build inputs hash of the unsigned image: %v", err)
+		}
+		buildInputsHash = hash
 	} else if signConfig.UnsignedImage != "" {
 		td.UnsignedImage = signConfig.UnsignedImage
 	} else {
@@ -324,21 +616,19 @@ func (rm *resourceManager) makeSignTemplate(ctx context.Context, mld *api.Module
 	dockerfileData := buf.String()
 
 	signSpec := signSpec(mld, dockerfileData, pushImage)
-	signSpecHash, err := rm.getSignHashAnnotationValue(ctx, signConfig.KeySecret.Name,
-		signConfig.CertSecret.Name, mld.Namespace, &signSpec)
+	signSpecHash, err := rm.getSignHashAnnotationValue(ctx, privateKeySecret,
+		signConfig.CertSecret.Name, mld.Namespace, &signSpec, buildInputsHash)
 	if err != nil {
 		return nil, fmt.Errorf("could not hash resource's definitions: %v", err)
 	}
 
 	sign := &buildv1.Build{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      mld.Name + "-sign-" + mld.KernelNormalizedVersion,
-			Namespace: mld.Namespace,
-			Labels:    resourceLabels(mld.Name, mld.KernelNormalizedVersion, kmmv1beta1.SignImage),
-			Annotations: map[string]string{
-				constants.ResourceHashAnnotation: fmt.Sprintf("%d", signSpecHash),
-			},
-			Finalizers: []string{constants.GCDelayFinalizer, constants.JobEventFinalizer},
+			Name:        mld.Name + "-sign-" + mld.KernelNormalizedVersion,
+			Namespace:   mld.Namespace,
+			Labels:      resourceLabels(mld.Name, mld.KernelNormalizedVersion, kmmv1beta1.SignImage),
+			Annotations: resourceAnnotations(signSpecHash, mld.Attempt, buildLogRetention(mld)),
+			Finalizers:  []string{constants.GCDelayFinalizer, constants.JobEventFinalizer},
 		},
 		Spec: signSpec,
 	}
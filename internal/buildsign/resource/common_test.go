@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/hashstructure/v2"
@@ -13,10 +14,13 @@ import (
 	buildv1 "github.com/openshift/api/build/v1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildercatalog"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/dtkmapping"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/git"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
-	"github.com/rh-ecosystem-edge/kernel-module-management/internal/syncronizedmap"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/registry"
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,25 +39,31 @@ var _ = Describe("makeBuildTemplate", func() {
 	)
 
 	var (
-		ctrl                   *gomock.Controller
-		clnt                   *client.MockClient
-		mbao                   *module.MockBuildArgOverrider
-		mockKernelOSDTKMapping *syncronizedmap.MockKernelOsDtkMapping
-		ctx                    context.Context
-		rm                     *resourceManager
+		ctrl               *gomock.Controller
+		clnt               *client.MockClient
+		mbao               *module.MockBuildArgOverrider
+		mockDTKMapping     *dtkmapping.MockDTKMapping
+		mockBuilderCatalog *buildercatalog.MockBuilderCatalog
+		mockRegistry       *registry.MockRegistry
+		ctx                context.Context
+		rm                 *resourceManager
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
 		mbao = module.NewMockBuildArgOverrider(ctrl)
-		mockKernelOSDTKMapping = syncronizedmap.NewMockKernelOsDtkMapping(ctrl)
+		mockDTKMapping = dtkmapping.NewMockDTKMapping(ctrl)
+		mockBuilderCatalog = buildercatalog.NewMockBuilderCatalog(ctrl)
+		mockRegistry = registry.NewMockRegistry(ctrl)
 		ctx = context.Background()
 		rm = &resourceManager{
-			client:             clnt,
-			buildArgOverrider:  mbao,
-			kernelOsDtkMapping: mockKernelOSDTKMapping,
-			scheme:             scheme,
+			client:            clnt,
+			buildArgOverrider: mbao,
+			dtkMapping:        mockDTKMapping,
+			builderCatalog:    mockBuilderCatalog,
+			registryAPI:       mockRegistry,
+			scheme:            scheme,
 		}
 	})
 
@@ -179,13 +189,27 @@ var _ = Describe("makeBuildTemplate", func() {
 			expected.Spec.CommonSpec.Strategy.DockerStrategy.Volumes = makeBuildResourceVolumes(mld.Build)
 		}
 
-		hash, err := hashstructure.Hash(expected.Spec.CommonSpec.Source, hashstructure.FormatV2, nil)
+		const baseImageDigest = "sha256:0123456789"
+
+		secretsData := make(map[string]map[string][]byte)
+		for _, s := range buildSecrets {
+			secretsData[s.Name] = map[string][]byte{"key": []byte("value-" + s.Name)}
+		}
+
+		inputs := buildInputs{
+			Source:      expected.Spec.CommonSpec.Source,
+			BuildArgs:   expected.Spec.CommonSpec.Strategy.DockerStrategy.BuildArgs,
+			SecretsData: secretsData,
+			BaseImages:  map[string]string{"some-image": baseImageDigest},
+		}
+
+		hash, err := hashstructure.Hash(inputs, hashstructure.FormatV2, nil)
 		Expect(err).NotTo(HaveOccurred())
 
 		annotations := map[string]string{constants.ResourceHashAnnotation: fmt.Sprintf("%d", hash)}
 		expected.SetAnnotations(annotations)
 
-		gomock.InOrder(
+		calls := []any{
 			clnt.EXPECT().Get(ctx, types.NamespacedName{Name: dockerfileConfigMap.Name, Namespace: mld.Namespace}, gomock.Any()).DoAndReturn(
 				func(_ interface{}, _ interface{}, cm *v1.ConfigMap, _ ...ctrlclient.GetOption) error {
 					cm.Data = dockerfileCMData
@@ -199,8 +223,26 @@ var _ = Describe("makeBuildTemplate", func() {
 					kmmv1beta1.BuildArg{Name: "MOD_NAME", Value: moduleName},
 					kmmv1beta1.BuildArg{Name: "MOD_NAMESPACE", Value: namespace}),
 			),
+		}
+
+		for _, s := range buildSecrets {
+			calls = append(calls,
+				clnt.EXPECT().Get(ctx, types.NamespacedName{Name: s.Name, Namespace: mld.Namespace}, gomock.Any()).DoAndReturn(
+					func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+						secret.Data = secretsData[s.Name]
+						return nil
+					},
+				),
+			)
+		}
+
+		calls = append(calls,
+			mockRegistry.EXPECT().GetDigest(ctx, "some-image", &mld.Build.BaseImageRegistryTLS, mld.Namespace, mld.ImageRepoSecret).
+				Return(baseImageDigest, nil),
 		)
 
+		gomock.InOrder(calls...)
+
 		bc, err := rm.makeBuildTemplate(ctx, &mld, mld.Owner, true)
 		Expect(err).NotTo(HaveOccurred())
 
@@ -253,7 +295,8 @@ var _ = Describe("makeBuildTemplate", func() {
 						return nil
 					},
 				),
-				mockKernelOSDTKMapping.EXPECT().GetImage(gomock.Any()).Return("", errors.New("random error")),
+				mockBuilderCatalog.EXPECT().GetImage(ctx, gomock.Any()).Return("", nil),
+				mockDTKMapping.EXPECT().GetImage(ctx, gomock.Any()).Return("", errors.New("random error")),
 			)
 
 			mld := api.ModuleLoaderData{
@@ -284,8 +327,10 @@ var _ = Describe("makeBuildTemplate", func() {
 						return nil
 					},
 				),
-				mockKernelOSDTKMapping.EXPECT().GetImage(gomock.Any()).Return(dtkImage, nil),
+				mockBuilderCatalog.EXPECT().GetImage(ctx, gomock.Any()).Return("", nil),
+				mockDTKMapping.EXPECT().GetImage(ctx, gomock.Any()).Return(dtkImage, nil),
 				mbao.EXPECT().ApplyBuildArgOverrides(gomock.Any(), gomock.Any()).Return(buildArgs),
+				mockRegistry.EXPECT().GetDigest(ctx, dtkBuildArg, gomock.Any(), gomock.Any(), gomock.Any()).Return("sha256:111", nil),
 			)
 
 			mld := api.ModuleLoaderData{
@@ -304,6 +349,135 @@ var _ = Describe("makeBuildTemplate", func() {
 		})
 	})
 
+	Context("using a KernelBuilderCatalog", func() {
+		const builderImage = "quay.io/example/ubuntu-builder:6.8.0-45-generic"
+
+		expectDockerfile := func(dockerfileData string) *gomock.Call {
+			return clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, cm *v1.ConfigMap, _ ...ctrlclient.GetOption) error {
+					cm.Data = map[string]string{constants.DockerfileCMKey: dockerfileData}
+					return nil
+				},
+			)
+		}
+
+		It("should fail if the catalogs could not be read", func() {
+			gomock.InOrder(
+				expectDockerfile(fmt.Sprintf("FROM %s", builderImageBuildArg)),
+				mockBuilderCatalog.EXPECT().GetImage(ctx, targetKernel).Return("", errors.New("random error")),
+			)
+
+			mld := api.ModuleLoaderData{
+				Build:         &kmmv1beta1.Build{DockerfileConfigMap: &dockerfileConfigMap},
+				KernelVersion: targetKernel,
+			}
+			_, err := rm.makeBuildTemplate(ctx, &mld, mld.Owner, false)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should prefer the catalog image over the DTK image for both build args", func() {
+			gomock.InOrder(
+				expectDockerfile(fmt.Sprintf("FROM ${%s}\nFROM ${%s}", dtkBuildArg, builderImageBuildArg)),
+				mockBuilderCatalog.EXPECT().GetImage(ctx, targetKernel).Return(builderImage, nil),
+				mbao.EXPECT().ApplyBuildArgOverrides(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(args []kmmv1beta1.BuildArg, overrides ...kmmv1beta1.BuildArg) []kmmv1beta1.BuildArg {
+						Expect(overrides).To(ContainElements(
+							kmmv1beta1.BuildArg{Name: dtkBuildArg, Value: builderImage},
+							kmmv1beta1.BuildArg{Name: builderImageBuildArg, Value: builderImage},
+						))
+						return overrides
+					},
+				),
+				mockRegistry.EXPECT().GetDigest(ctx, builderImage, gomock.Any(), gomock.Any(), gomock.Any()).Return("sha256:111", nil),
+			)
+
+			mld := api.ModuleLoaderData{
+				Build:         &kmmv1beta1.Build{DockerfileConfigMap: &dockerfileConfigMap},
+				KernelVersion: targetKernel,
+				Owner:         &kmmv1beta1.Module{},
+			}
+			_, err := rm.makeBuildTemplate(ctx, &mld, mld.Owner, false)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("with a Git build context", func() {
+		const commit = "1111111111111111111111111111111111111111"
+
+		var mockGit *git.MockGit
+
+		BeforeEach(func() {
+			mockGit = git.NewMockGit(ctrl)
+			rm.gitAPI = mockGit
+		})
+
+		sourceSecret := &v1.LocalObjectReference{Name: "git-credentials"}
+
+		gitMLD := func() api.ModuleLoaderData {
+			return api.ModuleLoaderData{
+				Namespace: namespace,
+				Build: &kmmv1beta1.Build{
+					DockerfileConfigMap: &dockerfileConfigMap,
+					Git: &kmmv1beta1.GitBuildSource{
+						URI:          "https://example.com/org/repo.git",
+						Ref:          "main",
+						SourceSecret: sourceSecret,
+					},
+				},
+				Owner: &kmmv1beta1.Module{},
+			}
+		}
+
+		expectDockerfile := func() *gomock.Call {
+			return clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, cm *v1.ConfigMap, _ ...ctrlclient.GetOption) error {
+					cm.Data = dockerfileCMData
+					return nil
+				},
+			)
+		}
+
+		buildHash := func(commit string) string {
+			gomock.InOrder(
+				expectDockerfile(),
+				mbao.EXPECT().ApplyBuildArgOverrides(gomock.Any(), gomock.Any()),
+				mockGit.EXPECT().ResolveRef(ctx, "https://example.com/org/repo.git", "main", namespace, sourceSecret).
+					Return(commit, nil),
+				mockRegistry.EXPECT().GetDigest(ctx, "some-image", gomock.Any(), gomock.Any(), gomock.Any()).Return("sha256:111", nil),
+			)
+
+			mld := gitMLD()
+			obj, err := rm.makeBuildTemplate(ctx, &mld, mld.Owner, false)
+			Expect(err).NotTo(HaveOccurred())
+			build, ok := obj.(*buildv1.Build)
+			Expect(ok).To(BeTrue())
+			Expect(build.Spec.Source.Git.Ref).To(Equal(commit))
+
+			return build.GetAnnotations()[constants.ResourceHashAnnotation]
+		}
+
+		It("should build the commit the ref points to", func() {
+			buildHash(commit)
+		})
+
+		It("should change the hash when the ref points to another commit", func() {
+			Expect(buildHash(commit)).NotTo(Equal(buildHash("2222222222222222222222222222222222222222")))
+		})
+
+		It("should return an error if the ref cannot be resolved", func() {
+			gomock.InOrder(
+				expectDockerfile(),
+				mbao.EXPECT().ApplyBuildArgOverrides(gomock.Any(), gomock.Any()),
+				mockGit.EXPECT().ResolveRef(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return("", errors.New("some error")),
+			)
+
+			mld := gitMLD()
+			_, err := rm.makeBuildTemplate(ctx, &mld, mld.Owner, false)
+			Expect(err).To(MatchError(ContainSubstring(`could not resolve ref "main"`)))
+		})
+	})
+
 	It("should use the final container image as the build destination even when sign is defined", func() {
 		mld := api.ModuleLoaderData{
 			Name:      moduleName,
@@ -331,6 +505,7 @@ var _ = Describe("makeBuildTemplate", func() {
 				},
 			),
 			mbao.EXPECT().ApplyBuildArgOverrides(gomock.Any(), gomock.Any()),
+			mockRegistry.EXPECT().GetDigest(ctx, "some-image", gomock.Any(), gomock.Any(), gomock.Any()).Return("sha256:111", nil),
 		)
 
 		actual, err := rm.makeBuildTemplate(ctx, &mld, mld.Owner, true)
@@ -357,18 +532,24 @@ var _ = Describe("makeSignTemplate", func() {
 	)
 
 	var (
-		ctrl *gomock.Controller
-		clnt *client.MockClient
-		rm   *resourceManager
-		mld  api.ModuleLoaderData
+		ctrl         *gomock.Controller
+		clnt         *client.MockClient
+		mbao         *module.MockBuildArgOverrider
+		mockRegistry *registry.MockRegistry
+		rm           *resourceManager
+		mld          api.ModuleLoaderData
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
+		mbao = module.NewMockBuildArgOverrider(ctrl)
+		mockRegistry = registry.NewMockRegistry(ctrl)
 		rm = &resourceManager{
-			client: clnt,
-			scheme: scheme,
+			client:            clnt,
+			buildArgOverrider: mbao,
+			registryAPI:       mockRegistry,
+			scheme:            scheme,
 		}
 		mld = api.ModuleLoaderData{
 			Name:      moduleName,
@@ -413,9 +594,39 @@ FROM some-signer-image:some-tag AS signimage
 USER 0
 
 COPY --from=source /modules /opt/modules
-RUN for file in /opt/modules/simple-kmod.ko:/modules/simple-procfs-kmod.ko; do \
-      [ -e "${file}" ] && /usr/local/bin/sign-file sha256 /run/secrets/key/key /run/secrets/cert/cert "${file}"; \
-    done
+RUN verification_failed() { marker=KMM_SIGN_VERIFICATION; echo "${marker}_FAILED: $*" >&2; exit 1; }; \
+    verify_module_signature() { \
+      size=$(stat -c %s "$1"); \
+      [ "$(tail -c 28 "$1")" = "~Module signature appended~" ] || return 1; \
+      siglen=$(tail -c 32 "$1" | head -c 4 | od -An -tu4 --endian=big | tr -d ' '); \
+      head -c $((size - 40 - siglen)) "$1" > /tmp/module.unsigned; \
+      tail -c $((siglen + 40)) "$1" | head -c "${siglen}" > /tmp/module.p7s; \
+      openssl cms -verify -binary -inform DER -in /tmp/module.p7s -content /tmp/module.unsigned \
+        -certfile /tmp/cert.pem -nointern -noverify -out /dev/null 2>/tmp/verify.log || { cat /tmp/verify.log >&2; return 1; }; \
+    }; \
+    sign_module() { \
+      /usr/local/bin/sign-file sha256 /run/secrets/key/key /run/secrets/cert/cert "$1" || exit 1; \
+      verify_module_signature "$1" || verification_failed "$2 is not signed with the configured certificate"; \
+    }; \
+    sign_file() { \
+      case "$1" in \
+        *.ko.xz) xz -dc "$1" > /tmp/module.ko || exit 1; sign_module /tmp/module.ko "$1"; \
+          xz -zc --check=crc32 --lzma2=dict=1MiB /tmp/module.ko > "$1" || exit 1 ;; \
+        *.ko.zst) zstd -qdc "$1" > /tmp/module.ko || exit 1; sign_module /tmp/module.ko "$1"; \
+          zstd -qc /tmp/module.ko > "$1" || exit 1 ;; \
+        *) sign_module "$1" "$1" ;; \
+      esac; \
+      signed="${signed} ${1#/opt}"; \
+    }; \
+    openssl x509 -inform DER -in /run/secrets/cert/cert -out /tmp/cert.pem 2>/dev/null || \
+      openssl x509 -in /run/secrets/cert/cert -out /tmp/cert.pem || exit 1; \
+    signed=""; \
+    for file in /opt/modules/simple-kmod.ko:/modules/simple-procfs-kmod.ko; do \
+      [ -e "${file}" ] || continue; \
+      sign_file "${file}"; \
+    done; \
+    [ -n "${signed}" ] || verification_failed "no file matches /modules/simple-kmod.ko:/modules/simple-procfs-kmod.ko"; \
+    echo "KMM_SIGNED_FILES:${signed}"
 
 FROM source
 COPY --from=signimage /opt/modules /modules
@@ -506,7 +717,7 @@ COPY --from=signimage /opt/modules /modules
 				),
 		)
 
-		hash, err := rm.getSignHashAnnotationValue(ctx, mld.Sign.KeySecret.Name, mld.Sign.CertSecret.Name, mld.Namespace, &expected.Spec)
+		hash, err := rm.getSignHashAnnotationValue(ctx, mld.Sign.KeySecret.Name, mld.Sign.CertSecret.Name, mld.Namespace, &expected.Spec, "")
 		Expect(err).NotTo(HaveOccurred())
 		annotations := map[string]string{
 			constants.ResourceHashAnnotation: fmt.Sprintf("%d", hash),
@@ -662,11 +873,198 @@ COPY --from=signimage /opt/modules /modules
 		),
 	)
 
+	It("should sign the kernel modules found in the image", func() {
+		ctx := context.Background()
+		mld.Sign.FilesToSign = nil
+		mld.Sign.AutoDiscover = &kmmv1beta1.SignAutoDiscovery{
+			Include: []string{"kernel/drivers/*", "extra/*"},
+			Exclude: []string{"*/test-*.ko"},
+		}
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, types.NamespacedName{Name: keySecretName, Namespace: mld.Namespace}, gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+					secret.Data = privateSignData
+					return nil
+				},
+			),
+			clnt.EXPECT().Get(ctx, types.NamespacedName{Name: certSecretName, Namespace: mld.Namespace}, gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+					secret.Data = publicSignData
+					return nil
+				},
+			),
+		)
+
+		actual, err := rm.makeSignTemplate(ctx, &mld, mld.Owner, true)
+		Expect(err).NotTo(HaveOccurred())
+		actualBuild, ok := actual.(*buildv1.Build)
+		Expect(ok).To(BeTrue())
+
+		dir := "/modules/lib/modules/" + kernelVersion
+		dockerfile := *actualBuild.Spec.CommonSpec.Source.Dockerfile
+		Expect(dockerfile).To(ContainSubstring(
+			"for file in $(find /opt" + dir + ` -type f \( -name '*.ko' -o -name '*.ko.xz' -o -name '*.ko.zst' \) | sort); do`,
+		))
+		Expect(dockerfile).To(ContainSubstring(`case "${module}" in kernel/drivers/*|extra/*) ;; *) continue ;; esac;`))
+		Expect(dockerfile).To(ContainSubstring(`case "${module}" in */test-*.ko) continue ;; esac;`))
+		Expect(dockerfile).To(ContainSubstring(`echo "KMM_SIGNED_FILES:${signed}"`))
+		Expect(strings.Count(dockerfile, "RUN ")).To(Equal(1))
+	})
+
+	It("should sign with a PKCS#11 token without reading a private key", func() {
+		GinkgoT().Setenv("RELATED_IMAGE_SIGN", "some-sign-image:some-tag")
+
+		ctx := context.Background()
+		mld.Sign = &kmmv1beta1.Sign{
+			UnsignedImage: unsignedImage,
+			PKCS11: &kmmv1beta1.PKCS11Key{
+				URI:          "pkcs11:token=secureboot;object=signing-key",
+				ModulePath:   "/usr/lib64/pkcs11/libsofthsm2.so",
+				ConfigSecret: &v1.LocalObjectReference{Name: "softhsm-config"},
+			},
+			CertSecret:  &v1.LocalObjectReference{Name: "securebootcert"},
+			FilesToSign: []string{"/modules/test.ko"},
+		}
+		mld.ContainerImage = signedImage
+		mld.RegistryTLS = &kmmv1beta1.TLSOptions{}
+
+		clnt.EXPECT().Get(ctx, types.NamespacedName{Name: mld.Sign.CertSecret.Name, Namespace: mld.Namespace}, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+				secret.Data = publicSignData
+				return nil
+			},
+		)
+
+		actual, err := rm.makeSignTemplate(ctx, &mld, mld.Owner, true)
+		Expect(err).NotTo(HaveOccurred())
+		actualBuild, ok := actual.(*buildv1.Build)
+		Expect(ok).To(BeTrue())
+
+		dockerfile := *actualBuild.Spec.CommonSpec.Source.Dockerfile
+		Expect(dockerfile).To(ContainSubstring("export PKCS11_MODULE_PATH=/usr/lib64/pkcs11/libsofthsm2.so;"))
+		Expect(dockerfile).To(ContainSubstring(`export KBUILD_SIGN_PIN="$(cat /run/secrets/pkcs11/pin)"`))
+		Expect(dockerfile).To(ContainSubstring(
+			"/usr/local/bin/sign-file sha256 'pkcs11:token=secureboot;object=signing-key' /run/secrets/cert/cert",
+		))
+		Expect(dockerfile).NotTo(ContainSubstring("/run/secrets/key"))
+
+		volumes := actualBuild.Spec.Strategy.DockerStrategy.Volumes
+		Expect(volumes).To(HaveLen(2))
+		Expect(volumes[0].Source.Secret.SecretName).To(Equal("securebootcert"))
+		Expect(volumes[1].Source.Secret.SecretName).To(Equal("softhsm-config"))
+		Expect(volumes[1].Mounts).To(Equal([]buildv1.BuildVolumeMount{{DestinationPath: "/run/secrets/pkcs11"}}))
+	})
+
+	It("should sign with an external signing service", func() {
+		GinkgoT().Setenv("RELATED_IMAGE_SIGN", "some-sign-image:some-tag")
+
+		ctx := context.Background()
+		rm.signingServiceURL = "https://default.example.org/sign"
+		mld.Sign = &kmmv1beta1.Sign{
+			UnsignedImage: unsignedImage,
+			SigningService: &kmmv1beta1.SigningService{
+				URL:       "https://signer.example.org/sign",
+				TLSSecret: &v1.LocalObjectReference{Name: "signer-tls"},
+			},
+			CertSecret:  &v1.LocalObjectReference{Name: "securebootcert"},
+			FilesToSign: []string{"/modules/test.ko"},
+		}
+		mld.ContainerImage = signedImage
+		mld.RegistryTLS = &kmmv1beta1.TLSOptions{}
+
+		clnt.EXPECT().Get(ctx, types.NamespacedName{Name: mld.Sign.CertSecret.Name, Namespace: mld.Namespace}, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+				secret.Data = publicSignData
+				return nil
+			},
+		)
+
+		actual, err := rm.makeSignTemplate(ctx, &mld, mld.Owner, true)
+		Expect(err).NotTo(HaveOccurred())
+		actualBuild, ok := actual.(*buildv1.Build)
+		Expect(ok).To(BeTrue())
+
+		const expectedRun = `    sign_module() { \
+      curl --fail --silent --show-error --retry 5 --retry-all-errors \
+        --cacert /run/secrets/signing-service/ca.crt \
+        --cert /run/secrets/signing-service/tls.crt \
+        --key /run/secrets/signing-service/tls.key \
+        -H 'Content-Type: application/octet-stream' --data-binary "@$1" -o "$1.p7s" \
+        'https://signer.example.org/sign' || exit 1; \
+      /usr/local/bin/sign-file -s "$1.p7s" sha256 /run/secrets/cert/cert "$1" || exit 1; \
+      rm -f "$1.p7s"; \
+      verify_module_signature "$1" || verification_failed "$2 is not signed with the configured certificate"; \
+    }; \
+`
+		Expect(*actualBuild.Spec.CommonSpec.Source.Dockerfile).To(ContainSubstring(expectedRun))
+
+		volumes := actualBuild.Spec.Strategy.DockerStrategy.Volumes
+		Expect(volumes).To(HaveLen(2))
+		Expect(volumes[1].Source.Secret.SecretName).To(Equal("signer-tls"))
+		Expect(volumes[1].Mounts).To(Equal([]buildv1.BuildVolumeMount{{DestinationPath: "/run/secrets/signing-service"}}))
+	})
+
+	It("should use the default signing service of the operator", func() {
+		GinkgoT().Setenv("RELATED_IMAGE_SIGN", "some-sign-image:some-tag")
+
+		ctx := context.Background()
+		rm.signingServiceURL = "https://default.example.org/sign"
+		mld.Sign = &kmmv1beta1.Sign{
+			UnsignedImage:  unsignedImage,
+			SigningService: &kmmv1beta1.SigningService{},
+			CertSecret:     &v1.LocalObjectReference{Name: "securebootcert"},
+			FilesToSign:    []string{"/modules/test.ko"},
+		}
+		mld.ContainerImage = signedImage
+		mld.RegistryTLS = &kmmv1beta1.TLSOptions{}
+
+		clnt.EXPECT().Get(ctx, types.NamespacedName{Name: mld.Sign.CertSecret.Name, Namespace: mld.Namespace}, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+				secret.Data = publicSignData
+				return nil
+			},
+		)
+
+		actual, err := rm.makeSignTemplate(ctx, &mld, mld.Owner, true)
+		Expect(err).NotTo(HaveOccurred())
+		actualBuild, ok := actual.(*buildv1.Build)
+		Expect(ok).To(BeTrue())
+
+		dockerfile := *actualBuild.Spec.CommonSpec.Source.Dockerfile
+		Expect(dockerfile).To(ContainSubstring("'https://default.example.org/sign'"))
+		Expect(dockerfile).NotTo(ContainSubstring("--cert"))
+		Expect(actualBuild.Spec.Strategy.DockerStrategy.Volumes).To(HaveLen(1))
+	})
+
+	It("should return an error if no signing service URL is given", func() {
+		mld.Sign = &kmmv1beta1.Sign{
+			UnsignedImage:  unsignedImage,
+			SigningService: &kmmv1beta1.SigningService{},
+			CertSecret:     &v1.LocalObjectReference{Name: "securebootcert"},
+		}
+
+		_, err := rm.makeSignTemplate(context.Background(), &mld, mld.Owner, true)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if no signing key is given", func() {
+		mld.Sign = &kmmv1beta1.Sign{
+			UnsignedImage: unsignedImage,
+			CertSecret:    &v1.LocalObjectReference{Name: "securebootcert"},
+		}
+
+		_, err := rm.makeSignTemplate(context.Background(), &mld, mld.Owner, true)
+		Expect(err).To(HaveOccurred())
+	})
+
 	It("should use the container image as source when build is also defined", func() {
 		GinkgoT().Setenv("RELATED_IMAGE_SIGN", "some-sign-image:some-tag")
 
 		ctx := context.Background()
-		mld.Build = &kmmv1beta1.Build{}
+		mld.Build = &kmmv1beta1.Build{
+			DockerfileConfigMap: &v1.LocalObjectReference{Name: "dockerfile"},
+		}
 		mld.Sign = &kmmv1beta1.Sign{
 			KeySecret:   &v1.LocalObjectReference{Name: "securebootkey"},
 			CertSecret:  &v1.LocalObjectReference{Name: "securebootcert"},
@@ -676,6 +1074,14 @@ COPY --from=signimage /opt/modules /modules
 		mld.RegistryTLS = &kmmv1beta1.TLSOptions{}
 
 		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, types.NamespacedName{Name: "dockerfile", Namespace: mld.Namespace}, gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, cm *v1.ConfigMap, _ ...ctrlclient.GetOption) error {
+					cm.Data = map[string]string{constants.DockerfileCMKey: "FROM some-image"}
+					return nil
+				},
+			),
+			mbao.EXPECT().ApplyBuildArgOverrides(gomock.Any(), gomock.Any()),
+			mockRegistry.EXPECT().GetDigest(ctx, "some-image", gomock.Any(), gomock.Any(), gomock.Any()).Return("sha256:111", nil),
 			clnt.EXPECT().Get(ctx, types.NamespacedName{Name: mld.Sign.KeySecret.Name, Namespace: mld.Namespace}, gomock.Any()).DoAndReturn(
 				func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
 					secret.Data = privateSignData
@@ -717,3 +1123,249 @@ var _ = Describe("resourceLabels", func() {
 		Expect(labels).To(Equal(expected))
 	})
 })
+
+var _ = Describe("getBaseImages", func() {
+
+	DescribeTable("should return the base images of the Dockerfile",
+		func(dockerfile string, buildArgs []v1.EnvVar, expected []string) {
+			Expect(getBaseImages(dockerfile, buildArgs)).To(Equal(expected))
+		},
+		Entry("no FROM", "RUN true", nil, []string{}),
+		Entry("single FROM", "FROM some-image:some-tag", nil, []string{"some-image:some-tag"}),
+		Entry(
+			"multi-stage",
+			"FROM builder-image AS builder\nRUN make\nFROM builder AS intermediate\nFROM final-image\nCOPY --from=builder /a /b",
+			nil,
+			[]string{"builder-image", "final-image"},
+		),
+		Entry("scratch is ignored", "FROM builder-image AS builder\nFROM scratch", nil, []string{"builder-image"}),
+		Entry("flags are ignored", "FROM --platform=linux/amd64 some-image", nil, []string{"some-image"}),
+		Entry("duplicates are removed", "from some-image AS a\nFROM some-image", nil, []string{"some-image"}),
+		Entry(
+			"build args are expanded",
+			"FROM ${DTK_AUTO}\nFROM $OTHER_IMAGE",
+			[]v1.EnvVar{{Name: "DTK_AUTO", Value: "dtk-image"}, {Name: "OTHER_IMAGE", Value: "other-image"}},
+			[]string{"dtk-image", "other-image"},
+		),
+		Entry(
+			"global ARG defaults are expanded",
+			"ARG IMAGE=default-image\nARG TAG=latest\nFROM ${IMAGE}:${TAG}",
+			[]v1.EnvVar{{Name: "TAG", Value: "some-tag"}},
+			[]string{"default-image:some-tag"},
+		),
+	)
+})
+
+var _ = Describe("getBuildHashAnnotationValue", func() {
+	var (
+		ctrl         *gomock.Controller
+		clnt         *client.MockClient
+		mockRegistry *registry.MockRegistry
+		rm           *resourceManager
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mockRegistry = registry.NewMockRegistry(ctrl)
+		rm = &resourceManager{
+			client:      clnt,
+			registryAPI: mockRegistry,
+		}
+	})
+
+	ctx := context.Background()
+
+	mld := api.ModuleLoaderData{
+		Namespace: "some-namespace",
+		Build: &kmmv1beta1.Build{
+			Secrets: []v1.LocalObjectReference{{Name: "some-secret"}},
+		},
+	}
+
+	buildSpec := buildv1.BuildSpec{
+		CommonSpec: buildv1.CommonSpec{
+			Source: buildv1.BuildSource{Dockerfile: ptr.To("FROM some-image")},
+			Strategy: buildv1.BuildStrategy{
+				DockerStrategy: &buildv1.DockerBuildStrategy{},
+			},
+		},
+	}
+
+	hashWith := func(secretValue, digest string) uint64 {
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, types.NamespacedName{Name: "some-secret", Namespace: mld.Namespace}, gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+					secret.Data = map[string][]byte{"key": []byte(secretValue)}
+					return nil
+				},
+			),
+			mockRegistry.EXPECT().GetDigest(ctx, "some-image", &mld.Build.BaseImageRegistryTLS, mld.Namespace, nil).
+				Return(digest, nil),
+		)
+
+		hash, err := rm.getBuildHashAnnotationValue(ctx, &mld, &buildSpec)
+		Expect(err).NotTo(HaveOccurred())
+
+		return hash
+	}
+
+	It("should return an error if a build secret could not be fetched", func() {
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+
+		_, err := rm.getBuildHashAnnotationValue(ctx, &mld, &buildSpec)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should change when the content of a build secret changes", func() {
+		Expect(hashWith("value", "sha256:111")).NotTo(Equal(hashWith("other-value", "sha256:111")))
+	})
+
+	It("should change when the digest of a base image changes", func() {
+		Expect(hashWith("value", "sha256:111")).NotTo(Equal(hashWith("value", "sha256:222")))
+	})
+
+	It("should change when the content of a build context ConfigMap or Secret changes", func() {
+		contextMLD := api.ModuleLoaderData{
+			Namespace: "some-namespace",
+			Build: &kmmv1beta1.Build{
+				ContextConfigMaps: []kmmv1beta1.BuildContextConfigMap{{ConfigMap: v1.LocalObjectReference{Name: "some-cm"}}},
+				ContextSecrets:    []kmmv1beta1.BuildContextSecret{{Secret: v1.LocalObjectReference{Name: "some-secret"}}},
+			},
+		}
+
+		hashWithContext := func(cmValue, secretValue string) uint64 {
+			gomock.InOrder(
+				clnt.EXPECT().Get(ctx, types.NamespacedName{Name: "some-secret", Namespace: contextMLD.Namespace}, gomock.Any()).DoAndReturn(
+					func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+						secret.Data = map[string][]byte{"key": []byte(secretValue)}
+						return nil
+					},
+				),
+				clnt.EXPECT().Get(ctx, types.NamespacedName{Name: "some-cm", Namespace: contextMLD.Namespace}, gomock.Any()).DoAndReturn(
+					func(_ interface{}, _ interface{}, cm *v1.ConfigMap, _ ...ctrlclient.GetOption) error {
+						cm.Data = map[string]string{"some.patch": cmValue}
+						return nil
+					},
+				),
+				mockRegistry.EXPECT().GetDigest(ctx, "some-image", gomock.Any(), gomock.Any(), gomock.Any()).Return("sha256:111", nil),
+			)
+
+			hash, err := rm.getBuildHashAnnotationValue(ctx, &contextMLD, &buildSpec)
+			Expect(err).NotTo(HaveOccurred())
+
+			return hash
+		}
+
+		Expect(hashWithContext("patch", "value")).NotTo(Equal(hashWithContext("other-patch", "value")))
+		Expect(hashWithContext("patch", "value")).NotTo(Equal(hashWithContext("patch", "other-value")))
+	})
+
+	It("should return an error if a build context ConfigMap could not be fetched", func() {
+		contextMLD := api.ModuleLoaderData{
+			Build: &kmmv1beta1.Build{
+				ContextConfigMaps: []kmmv1beta1.BuildContextConfigMap{{ConfigMap: v1.LocalObjectReference{Name: "some-cm"}}},
+			},
+		}
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+
+		_, err := rm.getBuildHashAnnotationValue(ctx, &contextMLD, &buildSpec)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should hash the reference of a base image whose digest cannot be resolved", func() {
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, types.NamespacedName{Name: "some-secret", Namespace: mld.Namespace}, gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+					secret.Data = map[string][]byte{"key": []byte("value")}
+					return nil
+				},
+			),
+			mockRegistry.EXPECT().GetDigest(ctx, "some-image", &mld.Build.BaseImageRegistryTLS, mld.Namespace, nil).
+				Return("", errors.New("some error")),
+		)
+
+		hash, err := rm.getBuildHashAnnotationValue(ctx, &mld, &buildSpec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hash).To(Equal(hashWith("value", "")))
+		Expect(hash).NotTo(Equal(hashWith("value", "sha256:111")))
+	})
+})
+
+var _ = Describe("buildSource", func() {
+	const dockerfile = "FROM some-image"
+
+	It("should only use the Dockerfile if no build context is defined", func() {
+		source := buildSource(&kmmv1beta1.Build{}, dockerfile, "")
+		Expect(source).To(Equal(buildv1.BuildSource{
+			Type:       buildv1.BuildSourceDockerfile,
+			Dockerfile: ptr.To(dockerfile),
+		}))
+	})
+
+	It("should add the ConfigMaps, Secrets and Git repository at the resolved commit to the build context", func() {
+		build := &kmmv1beta1.Build{
+			ContextConfigMaps: []kmmv1beta1.BuildContextConfigMap{
+				{ConfigMap: v1.LocalObjectReference{Name: "patches"}, DestinationDir: "patches"},
+				{ConfigMap: v1.LocalObjectReference{Name: "scripts"}},
+			},
+			ContextSecrets: []kmmv1beta1.BuildContextSecret{
+				{Secret: v1.LocalObjectReference{Name: "keys"}, DestinationDir: "keys"},
+			},
+			Git: &kmmv1beta1.GitBuildSource{
+				URI:          "https://example.org/driver.git",
+				Ref:          "v1.0.0",
+				ContextDir:   "src",
+				SourceSecret: &v1.LocalObjectReference{Name: "git-credentials"},
+			},
+		}
+
+		source := buildSource(build, dockerfile, "1111111111111111111111111111111111111111")
+		Expect(source).To(Equal(buildv1.BuildSource{
+			Type:       buildv1.BuildSourceGit,
+			Dockerfile: ptr.To(dockerfile),
+			Git: &buildv1.GitBuildSource{
+				URI: "https://example.org/driver.git",
+				Ref: "1111111111111111111111111111111111111111",
+			},
+			ContextDir: "src",
+			ConfigMaps: []buildv1.ConfigMapBuildSource{
+				{ConfigMap: v1.LocalObjectReference{Name: "patches"}, DestinationDir: "patches"},
+				{ConfigMap: v1.LocalObjectReference{Name: "scripts"}},
+			},
+			Secrets: []buildv1.SecretBuildSource{
+				{Secret: v1.LocalObjectReference{Name: "keys"}, DestinationDir: "keys"},
+			},
+			SourceSecret: &v1.LocalObjectReference{Name: "git-credentials"},
+		}))
+	})
+})
+
+var _ = Describe("completionDeadlineSeconds", func() {
+	It("should not set a deadline without a timeout", func() {
+		Expect(completionDeadlineSeconds(nil)).To(BeNil())
+		Expect(completionDeadlineSeconds(&kmmv1beta1.RetryPolicy{MaxAttempts: 2})).To(BeNil())
+	})
+
+	It("should use the timeout of the retry policy", func() {
+		retryPolicy := kmmv1beta1.RetryPolicy{Timeout: &metav1.Duration{Duration: 90 * time.Minute}}
+		Expect(completionDeadlineSeconds(&retryPolicy)).To(Equal(ptr.To[int64](5400)))
+	})
+})
+
+var _ = Describe("resourceAnnotations", func() {
+	It("should only record the attempt of retries", func() {
+		Expect(resourceAnnotations(123, 0, nil)).To(Equal(map[string]string{constants.ResourceHashAnnotation: "123"}))
+		Expect(resourceAnnotations(123, 2, nil)).To(Equal(map[string]string{
+			constants.ResourceHashAnnotation:    "123",
+			constants.ResourceAttemptAnnotation: "2",
+		}))
+	})
+
+	It("should record the log retention of the Module", func() {
+		Expect(resourceAnnotations(123, 0, ptr.To[int32](0))).To(Equal(map[string]string{
+			constants.ResourceHashAnnotation:      "123",
+			constants.BuildLogRetentionAnnotation: "0",
+		}))
+	})
+})
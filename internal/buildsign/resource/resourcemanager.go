@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	buildv1 "github.com/openshift/api/build/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,27 +13,41 @@ import (
 
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildercatalog"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildsign"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/dtkmapping"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/git"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
-	"github.com/rh-ecosystem-edge/kernel-module-management/internal/syncronizedmap"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/registry"
 )
 
 type resourceManager struct {
-	client             client.Client
-	buildArgOverrider  module.BuildArgOverrider
-	kernelOsDtkMapping syncronizedmap.KernelOsDtkMapping
-	scheme             *runtime.Scheme
+	client            client.Client
+	buildArgOverrider module.BuildArgOverrider
+	dtkMapping        dtkmapping.DTKMapping
+	builderCatalog    buildercatalog.BuilderCatalog
+	registryAPI       registry.Registry
+	gitAPI            git.Git
+	scheme            *runtime.Scheme
+	signingServiceURL string
 }
 
-func NewResourceManager(client client.Client, buildArgOverrider module.BuildArgOverrider, kernelOsDtkMapping syncronizedmap.KernelOsDtkMapping,
-	scheme *runtime.Scheme) buildsign.ResourceManager {
+// NewResourceManager returns a ResourceManager; signingServiceURL is the default endpoint of the external signing
+// service, used when a Module does not set one.
+func NewResourceManager(client client.Client, buildArgOverrider module.BuildArgOverrider, dtkMapping dtkmapping.DTKMapping,
+	builderCatalog buildercatalog.BuilderCatalog, registryAPI registry.Registry, gitAPI git.Git,
+	scheme *runtime.Scheme, signingServiceURL string) buildsign.ResourceManager {
 
 	return &resourceManager{
-		client:             client,
-		buildArgOverrider:  buildArgOverrider,
-		kernelOsDtkMapping: kernelOsDtkMapping,
-		scheme:             scheme,
+		client:            client,
+		buildArgOverrider: buildArgOverrider,
+		dtkMapping:        dtkMapping,
+		builderCatalog:    builderCatalog,
+		registryAPI:       registryAPI,
+		gitAPI:            gitAPI,
+		scheme:            scheme,
+		signingServiceURL: signingServiceURL,
 	}
 }
 
@@ -127,12 +142,57 @@ func (rm *resourceManager) IsResourceChanged(existingObj metav1.Object, newObj m
 	if existingAnnotations == nil {
 		return false, fmt.Errorf("annotations are not present in the existing resource %s", existingResource.Name)
 	}
-	if existingAnnotations[constants.ResourceHashAnnotation] == newAnnotations[constants.ResourceHashAnnotation] {
+	if existingAnnotations[constants.ResourceHashAnnotation] == newAnnotations[constants.ResourceHashAnnotation] &&
+		existingAnnotations[constants.ResourceAttemptAnnotation] == newAnnotations[constants.ResourceAttemptAnnotation] {
 		return false, nil
 	}
 	return true, nil
 }
 
+// GetResourceFailureReason classifies the failure of a failed resource from the reason of its status.
+func (rm *resourceManager) GetResourceFailureReason(obj metav1.Object) (kmmv1beta1.BuildOrSignFailureReason, error) {
+
+	resource, ok := obj.(*buildv1.Build)
+	if !ok {
+		return "", errors.New("the existing resource cannot be converted to the corect resource")
+	}
+
+	switch resource.Status.Reason {
+	case buildv1.StatusReasonFetchSourceFailed, buildv1.StatusReasonPullBuilderImageFailed,
+		buildv1.StatusReasonFetchImageContentFailed:
+		return kmmv1beta1.FailureFetch, nil
+	case buildv1.StatusReasonDockerBuildFailed, buildv1.StatusReasonManageDockerfileFailed:
+		// the sign Dockerfile only fails if a kernel module cannot be signed or verified
+		if resource.GetLabels()[constants.ResourceType] == string(kmmv1beta1.SignImage) {
+			return kmmv1beta1.FailureVerification, nil
+		}
+		return kmmv1beta1.FailureCompile, nil
+	case buildv1.StatusReasonPushImageToRegistryFailed:
+		return kmmv1beta1.FailurePush, nil
+	case buildv1.StatusReasonBuildPodExists, buildv1.StatusReasonExceededRetryTimeout:
+		return kmmv1beta1.FailureTimeout, nil
+	case buildv1.StatusReasonCancelledBuild:
+		return kmmv1beta1.FailureCancelled, nil
+	default:
+		return kmmv1beta1.FailureUnknown, nil
+	}
+}
+
+// GetResourceAttempt returns the attempt for which the resource was created.
+func (rm *resourceManager) GetResourceAttempt(obj metav1.Object) (int32, error) {
+
+	value, ok := obj.GetAnnotations()[constants.ResourceAttemptAnnotation]
+	if !ok {
+		return 0, nil
+	}
+
+	attempt, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q for annotation %s: %v", value, constants.ResourceAttemptAnnotation, err)
+	}
+	return int32(attempt), nil
+}
+
 func (rm *resourceManager) GetModuleResources(ctx context.Context, modName, namespace string,
 	resourceType kmmv1beta1.BuildOrSignAction, owner metav1.Object) ([]metav1.Object, error) {
 
@@ -161,3 +221,43 @@ func (rm *resourceManager) HasResourcesCompletedSuccessfully(ctx context.Context
 
 	return resource.Status.Phase == buildv1.BuildPhaseComplete, nil
 }
+
+// GetActiveResources returns the build and sign resources created by KMM in namespace that have not finished yet.
+// An empty namespace returns the resources of all namespaces.
+func (rm *resourceManager) GetActiveResources(ctx context.Context, namespace string) ([]metav1.Object, error) {
+
+	resources, err := rm.getResources(ctx, namespace, map[string]string{"app.kubernetes.io/part-of": "kmm"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resources in namespace %q: %v", namespace, err)
+	}
+
+	activeObjects := []metav1.Object{}
+	for _, r := range resources {
+		switch r.Status.Phase {
+		case buildv1.BuildPhaseNew, buildv1.BuildPhasePending, buildv1.BuildPhaseRunning:
+			activeObjects = append(activeObjects, &r)
+		}
+	}
+	return activeObjects, nil
+}
+
+// GetBuildInputsHash returns the fingerprint of all the inputs of the Build that would be created for mld.
+func (rm *resourceManager) GetBuildInputsHash(ctx context.Context, mld *api.ModuleLoaderData) (string, error) {
+
+	dockerfileData, err := rm.getDockerfileData(ctx, mld.Build, mld.Namespace)
+	if err != nil {
+		return "", fmt.Errorf("failed to get dockerfile data from configmap: %v", err)
+	}
+
+	buildSpec, err := rm.buildSpec(ctx, mld, dockerfileData, mld.ContainerImage, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate Build spec: %v", err)
+	}
+
+	hash, err := rm.getBuildHashAnnotationValue(ctx, mld, buildSpec)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the build inputs: %v", err)
+	}
+
+	return fmt.Sprintf("%d", hash), nil
+}
@@ -11,8 +11,8 @@ import (
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildsign"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/dtkmapping"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
-	"github.com/rh-ecosystem-edge/kernel-module-management/internal/syncronizedmap"
 	"go.uber.org/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -23,18 +23,18 @@ var _ = Describe("GetResourceByKernel", func() {
 	const targetKernel = "target-kernels"
 
 	var (
-		mockKubeClient         *client.MockClient
-		rm                     buildsign.ResourceManager
-		mockBuildArgOverrider  *module.MockBuildArgOverrider
-		mockKernelOSDTKMapping *syncronizedmap.MockKernelOsDtkMapping
+		mockKubeClient        *client.MockClient
+		rm                    buildsign.ResourceManager
+		mockBuildArgOverrider *module.MockBuildArgOverrider
+		mockDTKMapping        *dtkmapping.MockDTKMapping
 	)
 
 	BeforeEach(func() {
 		ctrl := gomock.NewController(GinkgoT())
 		mockKubeClient = client.NewMockClient(ctrl)
 		mockBuildArgOverrider = module.NewMockBuildArgOverrider(ctrl)
-		mockKernelOSDTKMapping = syncronizedmap.NewMockKernelOsDtkMapping(ctrl)
-		rm = NewResourceManager(mockKubeClient, mockBuildArgOverrider, mockKernelOSDTKMapping, scheme)
+		mockDTKMapping = dtkmapping.NewMockDTKMapping(ctrl)
+		rm = NewResourceManager(mockKubeClient, mockBuildArgOverrider, mockDTKMapping, nil, nil, nil, scheme, "")
 
 	})
 
@@ -109,17 +109,17 @@ var _ = Describe("GetModuleResources", func() {
 	)
 
 	var (
-		mockKubeClient         *client.MockClient
-		rm                     buildsign.ResourceManager
-		mockBuildArgOverrider  *module.MockBuildArgOverrider
-		mockKernelOSDTKMapping *syncronizedmap.MockKernelOsDtkMapping
+		mockKubeClient        *client.MockClient
+		rm                    buildsign.ResourceManager
+		mockBuildArgOverrider *module.MockBuildArgOverrider
+		mockDTKMapping        *dtkmapping.MockDTKMapping
 	)
 
 	BeforeEach(func() {
 		ctrl := gomock.NewController(GinkgoT())
 		mockKubeClient = client.NewMockClient(ctrl)
-		mockKernelOSDTKMapping = syncronizedmap.NewMockKernelOsDtkMapping(ctrl)
-		rm = NewResourceManager(mockKubeClient, mockBuildArgOverrider, mockKernelOSDTKMapping, scheme)
+		mockDTKMapping = dtkmapping.NewMockDTKMapping(ctrl)
+		rm = NewResourceManager(mockKubeClient, mockBuildArgOverrider, mockDTKMapping, nil, nil, nil, scheme, "")
 
 	})
 
@@ -180,21 +180,82 @@ var _ = Describe("GetModuleResources", func() {
 	})
 })
 
+var _ = Describe("GetActiveResources", func() {
+	var (
+		mockKubeClient *client.MockClient
+		rm             buildsign.ResourceManager
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		mockKubeClient = client.NewMockClient(ctrl)
+		rm = NewResourceManager(mockKubeClient, nil, nil, nil, nil, nil, scheme, "")
+	})
+
+	ctx := context.Background()
+
+	It("should return an error if an error occurred", func() {
+		mockKubeClient.
+			EXPECT().
+			List(ctx, &buildv1.BuildList{}, gomock.Any(), gomock.Any()).
+			Return(errors.New("random error"))
+
+		_, err := rm.GetActiveResources(ctx, "")
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should only return the builds that have not finished", func() {
+		newBuild := buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{Name: "new"},
+			Status:     buildv1.BuildStatus{Phase: buildv1.BuildPhaseNew},
+		}
+		pendingBuild := buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending"},
+			Status:     buildv1.BuildStatus{Phase: buildv1.BuildPhasePending},
+		}
+		runningBuild := buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{Name: "running"},
+			Status:     buildv1.BuildStatus{Phase: buildv1.BuildPhaseRunning},
+		}
+		completeBuild := buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{Name: "complete"},
+			Status:     buildv1.BuildStatus{Phase: buildv1.BuildPhaseComplete},
+		}
+		failedBuild := buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{Name: "failed"},
+			Status:     buildv1.BuildStatus{Phase: buildv1.BuildPhaseFailed},
+		}
+
+		mockKubeClient.
+			EXPECT().
+			List(ctx, &buildv1.BuildList{}, gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, bcs *buildv1.BuildList, _ ...ctrlclient.ListOption) {
+				bcs.Items = []buildv1.Build{newBuild, completeBuild, pendingBuild, failedBuild, runningBuild}
+			})
+
+		res, err := rm.GetActiveResources(ctx, "")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal([]metav1.Object{&newBuild, &pendingBuild, &runningBuild}))
+	})
+})
+
 var _ = Describe("DeleteResource", func() {
 
 	var (
-		ctrl                   *gomock.Controller
-		mockKubeClient         *client.MockClient
-		rm                     buildsign.ResourceManager
-		mockBuildArgOverrider  *module.MockBuildArgOverrider
-		mockKernelOSDTKMapping *syncronizedmap.MockKernelOsDtkMapping
+		ctrl                  *gomock.Controller
+		mockKubeClient        *client.MockClient
+		rm                    buildsign.ResourceManager
+		mockBuildArgOverrider *module.MockBuildArgOverrider
+		mockDTKMapping        *dtkmapping.MockDTKMapping
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		mockKubeClient = client.NewMockClient(ctrl)
-		mockKernelOSDTKMapping = syncronizedmap.NewMockKernelOsDtkMapping(ctrl)
-		rm = NewResourceManager(mockKubeClient, mockBuildArgOverrider, mockKernelOSDTKMapping, scheme)
+		mockDTKMapping = dtkmapping.NewMockDTKMapping(ctrl)
+		rm = NewResourceManager(mockKubeClient, mockBuildArgOverrider, mockDTKMapping, nil, nil, nil, scheme, "")
 	})
 
 	ctx := context.Background()
@@ -227,18 +288,18 @@ var _ = Describe("DeleteResource", func() {
 
 var _ = Describe("CreateResource", func() {
 	var (
-		ctrl                   *gomock.Controller
-		mockKubeClient         *client.MockClient
-		rm                     buildsign.ResourceManager
-		mockBuildArgOverrider  *module.MockBuildArgOverrider
-		mockKernelOSDTKMapping *syncronizedmap.MockKernelOsDtkMapping
+		ctrl                  *gomock.Controller
+		mockKubeClient        *client.MockClient
+		rm                    buildsign.ResourceManager
+		mockBuildArgOverrider *module.MockBuildArgOverrider
+		mockDTKMapping        *dtkmapping.MockDTKMapping
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		mockKubeClient = client.NewMockClient(ctrl)
-		mockKernelOSDTKMapping = syncronizedmap.NewMockKernelOsDtkMapping(ctrl)
-		rm = NewResourceManager(mockKubeClient, mockBuildArgOverrider, mockKernelOSDTKMapping, scheme)
+		mockDTKMapping = dtkmapping.NewMockDTKMapping(ctrl)
+		rm = NewResourceManager(mockKubeClient, mockBuildArgOverrider, mockDTKMapping, nil, nil, nil, scheme, "")
 	})
 
 	It("good flow", func() {
@@ -267,18 +328,18 @@ var _ = Describe("CreateResource", func() {
 
 var _ = Describe("GetResourceStatus", func() {
 	var (
-		ctrl                   *gomock.Controller
-		mockKubeClient         *client.MockClient
-		rm                     buildsign.ResourceManager
-		mockBuildArgOverrider  *module.MockBuildArgOverrider
-		mockKernelOSDTKMapping *syncronizedmap.MockKernelOsDtkMapping
+		ctrl                  *gomock.Controller
+		mockKubeClient        *client.MockClient
+		rm                    buildsign.ResourceManager
+		mockBuildArgOverrider *module.MockBuildArgOverrider
+		mockDTKMapping        *dtkmapping.MockDTKMapping
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		mockKubeClient = client.NewMockClient(ctrl)
-		mockKernelOSDTKMapping = syncronizedmap.NewMockKernelOsDtkMapping(ctrl)
-		rm = NewResourceManager(mockKubeClient, mockBuildArgOverrider, mockKernelOSDTKMapping, scheme)
+		mockDTKMapping = dtkmapping.NewMockDTKMapping(ctrl)
+		rm = NewResourceManager(mockKubeClient, mockBuildArgOverrider, mockDTKMapping, nil, nil, nil, scheme, "")
 	})
 
 	DescribeTable("should return the correct status depending on the build status",
@@ -311,18 +372,18 @@ var _ = Describe("GetResourceStatus", func() {
 
 var _ = Describe("IsResourceChanged", func() {
 	var (
-		ctrl                   *gomock.Controller
-		mockKubeClient         *client.MockClient
-		rm                     buildsign.ResourceManager
-		mockBuildArgOverrider  *module.MockBuildArgOverrider
-		mockKernelOSDTKMapping *syncronizedmap.MockKernelOsDtkMapping
+		ctrl                  *gomock.Controller
+		mockKubeClient        *client.MockClient
+		rm                    buildsign.ResourceManager
+		mockBuildArgOverrider *module.MockBuildArgOverrider
+		mockDTKMapping        *dtkmapping.MockDTKMapping
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		mockKubeClient = client.NewMockClient(ctrl)
-		mockKernelOSDTKMapping = syncronizedmap.NewMockKernelOsDtkMapping(ctrl)
-		rm = NewResourceManager(mockKubeClient, mockBuildArgOverrider, mockKernelOSDTKMapping, scheme)
+		mockDTKMapping = dtkmapping.NewMockDTKMapping(ctrl)
+		rm = NewResourceManager(mockKubeClient, mockBuildArgOverrider, mockDTKMapping, nil, nil, nil, scheme, "")
 	})
 
 	DescribeTable("should detect if a build has changed",
@@ -352,5 +413,91 @@ var _ = Describe("IsResourceChanged", func() {
 			true, false),
 		Entry("should return false is build has not changed ", map[string]string{constants.ResourceHashAnnotation: "some hash"},
 			false, false),
+		Entry(
+			"should return true if build was created for another attempt",
+			map[string]string{constants.ResourceHashAnnotation: "some hash", constants.ResourceAttemptAnnotation: "1"},
+			true,
+			false,
+		),
+	)
+})
+
+var _ = Describe("GetResourceFailureReason", func() {
+	rm := NewResourceManager(nil, nil, nil, nil, nil, nil, scheme, "")
+
+	DescribeTable("should classify the failure of the build",
+		func(phase buildv1.BuildPhase, reason buildv1.StatusReason, expected kmmv1beta1.BuildOrSignFailureReason) {
+			build := buildv1.Build{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{constants.ResourceType: string(kmmv1beta1.BuildImage)},
+				},
+				Status: buildv1.BuildStatus{Phase: phase, Reason: reason},
+			}
+
+			res, err := rm.GetResourceFailureReason(&build)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(expected))
+		},
+		Entry(nil, buildv1.BuildPhaseFailed, buildv1.StatusReasonFetchSourceFailed, kmmv1beta1.FailureFetch),
+		Entry(nil, buildv1.BuildPhaseFailed, buildv1.StatusReasonPullBuilderImageFailed, kmmv1beta1.FailureFetch),
+		Entry(nil, buildv1.BuildPhaseFailed, buildv1.StatusReasonFetchImageContentFailed, kmmv1beta1.FailureFetch),
+		Entry(nil, buildv1.BuildPhaseFailed, buildv1.StatusReasonDockerBuildFailed, kmmv1beta1.FailureCompile),
+		Entry(nil, buildv1.BuildPhaseFailed, buildv1.StatusReasonManageDockerfileFailed, kmmv1beta1.FailureCompile),
+		Entry(nil, buildv1.BuildPhaseFailed, buildv1.StatusReasonPushImageToRegistryFailed, kmmv1beta1.FailurePush),
+		Entry(nil, buildv1.BuildPhaseFailed, buildv1.StatusReasonBuildPodExists, kmmv1beta1.FailureTimeout),
+		Entry(nil, buildv1.BuildPhaseFailed, buildv1.StatusReasonExceededRetryTimeout, kmmv1beta1.FailureTimeout),
+		Entry(nil, buildv1.BuildPhaseCancelled, buildv1.StatusReasonCancelledBuild, kmmv1beta1.FailureCancelled),
+		Entry(nil, buildv1.BuildPhaseError, buildv1.StatusReasonBuildPodEvicted, kmmv1beta1.FailureUnknown),
+	)
+
+	DescribeTable("should classify the failure of the sign",
+		func(reason buildv1.StatusReason, expected kmmv1beta1.BuildOrSignFailureReason) {
+			build := buildv1.Build{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{constants.ResourceType: string(kmmv1beta1.SignImage)},
+				},
+				Status: buildv1.BuildStatus{Phase: buildv1.BuildPhaseFailed, Reason: reason},
+			}
+
+			res, err := rm.GetResourceFailureReason(&build)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(expected))
+		},
+		Entry(nil, buildv1.StatusReasonDockerBuildFailed, kmmv1beta1.FailureVerification),
+		Entry(nil, buildv1.StatusReasonFetchImageContentFailed, kmmv1beta1.FailureFetch),
+		Entry(nil, buildv1.StatusReasonPushImageToRegistryFailed, kmmv1beta1.FailurePush),
 	)
 })
+
+var _ = Describe("GetResourceAttempt", func() {
+	rm := NewResourceManager(nil, nil, nil, nil, nil, nil, scheme, "")
+
+	It("should return 0 if the annotation is not set", func() {
+		attempt, err := rm.GetResourceAttempt(&buildv1.Build{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempt).To(Equal(int32(0)))
+	})
+
+	It("should return the attempt from the annotation", func() {
+		build := buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{constants.ResourceAttemptAnnotation: "2"},
+			},
+		}
+
+		attempt, err := rm.GetResourceAttempt(&build)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempt).To(Equal(int32(2)))
+	})
+
+	It("should return an error if the annotation is invalid", func() {
+		build := buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{constants.ResourceAttemptAnnotation: "invalid"},
+			},
+		}
+
+		_, err := rm.GetResourceAttempt(&build)
+		Expect(err).To(HaveOccurred())
+	})
+})
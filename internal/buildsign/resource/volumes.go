@@ -41,37 +41,41 @@ func makeBuildResourceVolumes(buildConfig *kmmv1beta1.Build) []buildv1.BuildVolu
 func makeSignResourceVolumes(signConfig *kmmv1beta1.Sign) []buildv1.BuildVolume {
 
 	volumes := []buildv1.BuildVolume{
-		{
-			Name: "key",
-			Source: buildv1.BuildVolumeSource{
-				Type: buildv1.BuildVolumeSourceTypeSecret,
-				Secret: &v1.SecretVolumeSource{
-					SecretName: signConfig.KeySecret.Name,
-					Optional:   ptr.To(false),
-				},
-			},
-			Mounts: []buildv1.BuildVolumeMount{
-				{
-					DestinationPath: "/run/secrets/key",
-				},
+		makeSecretVolume("cert", signConfig.CertSecret.Name, "/run/secrets/cert"),
+	}
+
+	if signConfig.SigningService != nil {
+		// the private key stays in the signing service; only the client's TLS credentials are mounted
+		if signConfig.SigningService.TLSSecret != nil {
+			volumes = append(volumes,
+				makeSecretVolume("signing-service", signConfig.SigningService.TLSSecret.Name, "/run/secrets/signing-service"))
+		}
+	} else if signConfig.PKCS11 != nil {
+		// the private key stays in the PKCS#11 token; only the module's configuration is mounted
+		if signConfig.PKCS11.ConfigSecret != nil {
+			volumes = append(volumes, makeSecretVolume("pkcs11", signConfig.PKCS11.ConfigSecret.Name, "/run/secrets/pkcs11"))
+		}
+	} else {
+		volumes = append([]buildv1.BuildVolume{makeSecretVolume("key", signConfig.KeySecret.Name, "/run/secrets/key")}, volumes...)
+	}
+
+	return volumes
+}
+
+func makeSecretVolume(name, secretName, destinationPath string) buildv1.BuildVolume {
+	return buildv1.BuildVolume{
+		Name: name,
+		Source: buildv1.BuildVolumeSource{
+			Type: buildv1.BuildVolumeSourceTypeSecret,
+			Secret: &v1.SecretVolumeSource{
+				SecretName: secretName,
+				Optional:   ptr.To(false),
 			},
 		},
-		{
-			Name: "cert",
-			Source: buildv1.BuildVolumeSource{
-				Type: buildv1.BuildVolumeSourceTypeSecret,
-				Secret: &v1.SecretVolumeSource{
-					SecretName: signConfig.CertSecret.Name,
-					Optional:   ptr.To(false),
-				},
-			},
-			Mounts: []buildv1.BuildVolumeMount{
-				{
-					DestinationPath: "/run/secrets/cert",
-				},
+		Mounts: []buildv1.BuildVolumeMount{
+			{
+				DestinationPath: destinationPath,
 			},
 		},
 	}
-
-	return volumes
 }
@@ -18,7 +18,13 @@ const (
 	StatusFailed     Status = "failed"
 )
 
-var ErrNoMatchingBuildSignResource = errors.New("no matching build or sign resource")
+var (
+	ErrNoMatchingBuildSignResource = errors.New("no matching build or sign resource")
+
+	// ErrResourceQueued is returned when a build or sign resource could not be created because of the limits
+	// on the number of concurrent builds and signs.
+	ErrResourceQueued = errors.New("build or sign resource is queued")
+)
 
 //go:generate mockgen -source=resourcemanager.go -package=buildsign -destination=mock_resourcemanager.go
 
@@ -31,7 +37,12 @@ type ResourceManager interface {
 		owner metav1.Object) (metav1.Object, error)
 	GetResourceStatus(obj metav1.Object) (Status, error)
 	IsResourceChanged(existingObj metav1.Object, newObj metav1.Object) (bool, error)
+	GetResourceFailureReason(obj metav1.Object) (kmmv1beta1.BuildOrSignFailureReason, error)
+	GetResourceAttempt(obj metav1.Object) (int32, error)
 	GetModuleResources(ctx context.Context, modName, namespace string, resourceType kmmv1beta1.BuildOrSignAction,
 		owner metav1.Object) ([]metav1.Object, error)
 	HasResourcesCompletedSuccessfully(ctx context.Context, obj metav1.Object) (bool, error)
+	GetBuildInputsHash(ctx context.Context, mld *api.ModuleLoaderData) (string, error)
+	GetActiveResources(ctx context.Context, namespace string) ([]metav1.Object, error)
+	GetBuilderImage(ctx context.Context, kernelVersion string) (string, error)
 }
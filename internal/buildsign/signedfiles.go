@@ -0,0 +1,69 @@
+package buildsign
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	buildv1 "github.com/openshift/api/build/v1"
+)
+
+// SignedFilesLogMarker starts the lines printed by a sign with the space-separated list of the kernel modules it
+// signed.
+const SignedFilesLogMarker = "KMM_SIGNED_FILES"
+
+const (
+	// dockerBuildContainerName is the name of the container running the Dockerfile in the pods of Docker builds.
+	dockerBuildContainerName = "docker-build"
+
+	// signedFilesLogTailLines only needs to cover the output of the sign's steps, which print one line each.
+	signedFilesLogTailLines = 1000
+)
+
+//go:generate mockgen -source=signedfiles.go -package=buildsign -destination=mock_signedfiles.go
+
+// SignedFilesReader returns the kernel modules that were signed by a successful sign, as printed in its logs.
+type SignedFilesReader interface {
+	Read(ctx context.Context, build *buildv1.Build) ([]string, error)
+}
+
+type signedFilesReader struct {
+	podLogReader PodLogReader
+}
+
+func NewSignedFilesReader(podLogReader PodLogReader) SignedFilesReader {
+	return &signedFilesReader{podLogReader: podLogReader}
+}
+
+// Read returns nil if no pod was ever created for the build.
+func (r *signedFilesReader) Read(ctx context.Context, build *buildv1.Build) ([]string, error) {
+	podName := build.Annotations[buildv1.BuildPodNameAnnotation]
+	if podName == "" {
+		return nil, nil
+	}
+
+	logs, err := r.podLogReader.ReadLogs(ctx, build.Namespace, podName, dockerBuildContainerName, signedFilesLogTailLines)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the logs of build %s/%s: %v", build.Namespace, build.Name, err)
+	}
+
+	var files []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(logs))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxArchivedLogBytes)
+
+	for scanner.Scan() {
+		// the builder also prints the sign's commands, in which the marker is not at the start of the line
+		if list, ok := strings.CutPrefix(scanner.Text(), SignedFilesLogMarker+":"); ok {
+			files = append(files, strings.Fields(list)...)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not parse the logs of build %s/%s: %v", build.Namespace, build.Name, err)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,76 @@
+package buildsign
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	buildv1 "github.com/openshift/api/build/v1"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("SignedFilesReader", func() {
+	const (
+		namespace = "some-namespace"
+		podName   = "some-sign-pod"
+	)
+
+	var (
+		ctrl             *gomock.Controller
+		mockPodLogReader *MockPodLogReader
+		sfr              SignedFilesReader
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockPodLogReader = NewMockPodLogReader(ctrl)
+		sfr = NewSignedFilesReader(mockPodLogReader)
+	})
+
+	ctx := context.Background()
+
+	build := &buildv1.Build{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "some-module-sign-some-kernel",
+			Namespace:   namespace,
+			Annotations: map[string]string{buildv1.BuildPodNameAnnotation: podName},
+		},
+	}
+
+	It("should do nothing if the build has no pod", func() {
+		files, err := sfr.Read(ctx, &buildv1.Build{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(BeNil())
+	})
+
+	It("should return the files printed by the sign", func() {
+		const logs = `STEP 5/7: RUN verification_failed() { ...; echo "KMM_SIGNED_FILES:${signed}"
+KMM_SIGNED_FILES: /modules/lib/modules/6.0/a.ko /modules/lib/modules/6.0/b.ko.xz
+STEP 6/7: RUN ...
+    echo "KMM_SIGNED_FILES:${signed}"
+KMM_SIGNED_FILES: /modules/lib/modules/6.0/c.ko.zst
+STEP 7/7: FROM source
+`
+
+		mockPodLogReader.EXPECT().ReadLogs(ctx, namespace, podName, "docker-build", int64(signedFilesLogTailLines)).
+			Return([]byte(logs), nil)
+
+		files, err := sfr.Read(ctx, build)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(Equal([]string{
+			"/modules/lib/modules/6.0/a.ko",
+			"/modules/lib/modules/6.0/b.ko.xz",
+			"/modules/lib/modules/6.0/c.ko.zst",
+		}))
+	})
+
+	It("should return an error if the logs could not be read", func() {
+		mockPodLogReader.EXPECT().ReadLogs(ctx, namespace, podName, "docker-build", int64(signedFilesLogTailLines)).
+			Return(nil, errors.New("some error"))
+
+		_, err := sfr.Read(ctx, build)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -3,26 +3,32 @@ package cluster
 import (
 	"context"
 	"errors"
-	"sort"
-	"strings"
+	"fmt"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	hubv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
-	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
 )
 
+// ErrPlacementNotReady is returned when the Placement of a ManagedClusterModule does not exist or has not made any
+// decision yet, so that the clusters it selects are unknown.
+var ErrPlacementNotReady = errors.New("placement is not ready")
+
 //go:generate mockgen -source=cluster.go -package=cluster -destination=mock_cluster.go
 
 type ClusterAPI interface {
 	SelectedManagedClusters(ctx context.Context, mcm *hubv1beta1.ManagedClusterModule) (*clusterv1.ManagedClusterList, error)
-	KernelVersions(cluster clusterv1.ManagedCluster) ([]string, error)
+	Kernels(cluster clusterv1.ManagedCluster) (NodeKernels, error)
 	GetModuleLoaderDataForKernel(mcm *hubv1beta1.ManagedClusterModule, kernelVersion string) (*api.ModuleLoaderData, error)
 	GetDefaultArtifactsNamespace() string
 }
@@ -48,6 +54,15 @@ func (c *clusterAPI) SelectedManagedClusters(
 	ctx context.Context,
 	mcm *hubv1beta1.ManagedClusterModule) (*clusterv1.ManagedClusterList, error) {
 
+	if mcm.Spec.Placement != nil {
+		return c.placementManagedClusters(ctx, mcm.Spec.Placement)
+	}
+
+	// a nil selector would match all the ManagedClusters
+	if mcm.Spec.Selector == nil {
+		return nil, errors.New("neither a selector nor a placement is set")
+	}
+
 	clusterList := &clusterv1.ManagedClusterList{}
 
 	opts := []client.ListOption{
@@ -61,18 +76,70 @@ func (c *clusterAPI) SelectedManagedClusters(
 	return clusterList, err
 }
 
-func (c *clusterAPI) KernelVersions(cluster clusterv1.ManagedCluster) ([]string, error) {
-	for _, clusterClaim := range cluster.Status.ClusterClaims {
-		if clusterClaim.Name != constants.KernelVersionsClusterClaimName {
-			continue
+// placementManagedClusters returns the ManagedClusters listed in the PlacementDecisions of the Placement.
+// It returns ErrPlacementNotReady if the Placement does not exist or has not selected any cluster, rather than an
+// empty list that would remove the ManagedClusterModule from all the clusters.
+func (c *clusterAPI) placementManagedClusters(
+	ctx context.Context,
+	placement *hubv1beta1.PlacementReference) (*clusterv1.ManagedClusterList, error) {
+
+	nsn := types.NamespacedName{Namespace: placement.Namespace, Name: placement.Name}
+	if err := c.client.Get(ctx, nsn, &clusterv1beta1.Placement{}); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: Placement %s was not found", ErrPlacementNotReady, nsn)
+		}
+		return nil, fmt.Errorf("failed to get Placement %s: %v", nsn, err)
+	}
+
+	clusterNames, err := PlacementClusterNames(ctx, c.client, placement.Namespace, placement.Name)
+	if err != nil {
+		return nil, err
+	}
+	if clusterNames.Len() == 0 {
+		return nil, fmt.Errorf("%w: Placement %s has no decisions", ErrPlacementNotReady, nsn)
+	}
+
+	clusterList := &clusterv1.ManagedClusterList{}
+	if err = c.client.List(ctx, clusterList); err != nil {
+		return nil, fmt.Errorf("failed to list ManagedClusters: %v", err)
+	}
+
+	selected := make([]clusterv1.ManagedCluster, 0, len(clusterNames))
+	for _, cluster := range clusterList.Items {
+		if clusterNames.Has(cluster.Name) {
+			selected = append(selected, cluster)
 		}
+	}
+	clusterList.Items = selected
+
+	return clusterList, nil
+}
+
+// PlacementClusterNames returns the names of the clusters listed in the PlacementDecisions of a Placement.
+func PlacementClusterNames(ctx context.Context, clnt client.Client, namespace, name string) (sets.Set[string], error) {
+	decisionList := &clusterv1beta1.PlacementDecisionList{}
+
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels{clusterv1beta1.PlacementLabel: name},
+	}
 
-		kernelVersions := strings.Split(clusterClaim.Value, "\n")
-		sort.Strings(kernelVersions)
+	if err := clnt.List(ctx, decisionList, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list PlacementDecisions of Placement %s/%s: %v", namespace, name, err)
+	}
 
-		return kernelVersions, nil
+	clusterNames := sets.New[string]()
+	for _, decision := range decisionList.Items {
+		for _, d := range decision.Status.Decisions {
+			clusterNames.Insert(d.ClusterName)
+		}
 	}
-	return nil, errors.New("KMM kernel version ClusterClaim not found")
+
+	return clusterNames, nil
+}
+
+func (c *clusterAPI) Kernels(cluster clusterv1.ManagedCluster) (NodeKernels, error) {
+	return ManagedClusterKernels(cluster)
 }
 
 func (c *clusterAPI) GetModuleLoaderDataForKernel(mcm *hubv1beta1.ManagedClusterModule,
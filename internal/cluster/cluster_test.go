@@ -3,15 +3,19 @@ package cluster
 import (
 	"context"
 	"errors"
-	"sort"
 	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
 	"go.uber.org/mock/gomock"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	hubv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
@@ -78,15 +82,148 @@ var _ = Describe("SelectedManagedClusters", func() {
 			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(errors.New("generic-error")),
 		)
 
-		res, err := c.SelectedManagedClusters(ctx, &hubv1beta1.ManagedClusterModule{})
+		mcm := &hubv1beta1.ManagedClusterModule{
+			Spec: hubv1beta1.ManagedClusterModuleSpec{Selector: map[string]string{"key": "value"}},
+		}
+
+		res, err := c.SelectedManagedClusters(ctx, mcm)
 
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("generic-error"))
 		Expect(res.Items).To(BeEmpty())
 	})
+
+	It("should return an error rather than all the ManagedClusters if neither a selector nor a placement is set", func() {
+		res, err := c.SelectedManagedClusters(context.Background(), &hubv1beta1.ManagedClusterModule{})
+
+		Expect(err).To(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+
+	It("should return the ManagedClusters of the PlacementDecisions of the ManagedClusterModule Placement", func() {
+		mcm := &hubv1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{Name: mcmName},
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				Placement: &hubv1beta1.PlacementReference{
+					Name:      "placement",
+					Namespace: namespace,
+				},
+			},
+		}
+
+		selectedCluster := clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "selected"},
+		}
+
+		ctx := context.Background()
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, types.NamespacedName{Namespace: namespace, Name: "placement"}, &clusterv1beta1.Placement{}),
+			clnt.EXPECT().List(
+				ctx,
+				gomock.Any(),
+				gomock.Any(),
+				ctrlclient.MatchingLabels{clusterv1beta1.PlacementLabel: "placement"},
+			).DoAndReturn(
+				func(_ interface{}, list *clusterv1beta1.PlacementDecisionList, _ ...interface{}) error {
+					list.Items = []clusterv1beta1.PlacementDecision{
+						{
+							Status: clusterv1beta1.PlacementDecisionStatus{
+								Decisions: []clusterv1beta1.ClusterDecision{{ClusterName: "selected"}},
+							},
+						},
+					}
+					return nil
+				},
+			),
+			clnt.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *clusterv1.ManagedClusterList, _ ...interface{}) error {
+					list.Items = []clusterv1.ManagedCluster{
+						selectedCluster,
+						{ObjectMeta: metav1.ObjectMeta{Name: "not-selected"}},
+					}
+					return nil
+				},
+			),
+		)
+
+		res, err := c.SelectedManagedClusters(ctx, mcm)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Items).To(Equal([]clusterv1.ManagedCluster{selectedCluster}))
+	})
+
+	It("should return an error when the PlacementDecisions cannot be listed", func() {
+		mcm := &hubv1beta1.ManagedClusterModule{
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				Placement: &hubv1beta1.PlacementReference{
+					Name:      "placement",
+					Namespace: namespace,
+				},
+			},
+		}
+
+		ctx := context.Background()
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("generic-error")),
+		)
+
+		_, err := c.SelectedManagedClusters(ctx, mcm)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to list PlacementDecisions of Placement namespace/placement"))
+	})
+
+	It("should return ErrPlacementNotReady if the Placement does not exist", func() {
+		mcm := &hubv1beta1.ManagedClusterModule{
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				Placement: &hubv1beta1.PlacementReference{
+					Name:      "placement",
+					Namespace: namespace,
+				},
+			},
+		}
+
+		ctx := context.Background()
+
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "placement"))
+
+		_, err := c.SelectedManagedClusters(ctx, mcm)
+
+		Expect(err).To(MatchError(ErrPlacementNotReady))
+	})
+
+	It("should return ErrPlacementNotReady if the Placement has no decisions", func() {
+		mcm := &hubv1beta1.ManagedClusterModule{
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				Placement: &hubv1beta1.PlacementReference{
+					Name:      "placement",
+					Namespace: namespace,
+				},
+			},
+		}
+
+		ctx := context.Background()
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *clusterv1beta1.PlacementDecisionList, _ ...interface{}) error {
+					list.Items = []clusterv1beta1.PlacementDecision{{}}
+					return nil
+				},
+			),
+		)
+
+		_, err := c.SelectedManagedClusters(ctx, mcm)
+
+		Expect(err).To(MatchError(ErrPlacementNotReady))
+	})
 })
 
-var _ = Describe("KernelVersions", func() {
+var _ = Describe("Kernels", func() {
 	var c ClusterAPI
 
 	BeforeEach(func() {
@@ -101,13 +238,13 @@ var _ = Describe("KernelVersions", func() {
 			},
 		}
 
-		versions, err := c.KernelVersions(cluster)
+		kernels, err := c.Kernels(cluster)
 
 		Expect(err).To(HaveOccurred())
-		Expect(versions).To(BeNil())
+		Expect(kernels).To(BeNil())
 	})
 
-	It("should return the sorted kernel versions found in the KMM cluster claim", func() {
+	It("should return the sorted kernel versions found in the KMM kernel versions cluster claim", func() {
 		kernelVersions := []string{"2.0.0", "1.0.0"}
 
 		cluster := clusterv1.ManagedCluster{
@@ -125,12 +262,49 @@ var _ = Describe("KernelVersions", func() {
 			},
 		}
 
-		versions, err := c.KernelVersions(cluster)
+		kernels, err := c.Kernels(cluster)
 
 		Expect(err).ToNot(HaveOccurred())
-		Expect(versions).To(HaveLen(2))
-		Expect(versions).To(ContainElements(kernelVersions[0], kernelVersions[1]))
-		Expect(sort.StringsAreSorted(versions)).To(BeTrue())
+		Expect(kernels).To(Equal(NodeKernels{{KernelVersion: "1.0.0"}, {KernelVersion: "2.0.0"}}))
+	})
+
+	It("should prefer the KMM kernels cluster claim", func() {
+		cluster := clusterv1.ManagedCluster{
+			Status: clusterv1.ManagedClusterStatus{
+				ClusterClaims: []clusterv1.ManagedClusterClaim{
+					{
+						Name:  constants.KernelVersionsClusterClaimName,
+						Value: "1.0.0",
+					},
+					{
+						Name:  constants.KernelsClusterClaimName,
+						Value: `[{"kernelVersion":"1.0.0","architecture":"arm64","osImage":"os","nodes":2}]`,
+					},
+				},
+			},
+		}
+
+		kernels, err := c.Kernels(cluster)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(kernels).To(Equal(NodeKernels{{KernelVersion: "1.0.0", Architecture: "arm64", OSImage: "os", Nodes: 2}}))
+	})
+
+	It("should return an error if the KMM kernels cluster claim is invalid", func() {
+		cluster := clusterv1.ManagedCluster{
+			Status: clusterv1.ManagedClusterStatus{
+				ClusterClaims: []clusterv1.ManagedClusterClaim{
+					{
+						Name:  constants.KernelsClusterClaimName,
+						Value: "1.0.0",
+					},
+				},
+			},
+		}
+
+		_, err := c.Kernels(cluster)
+
+		Expect(err).To(HaveOccurred())
 	})
 })
 
@@ -0,0 +1,153 @@
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+)
+
+// maxClusterClaimValueLength is the maximum length of the value of a ClusterClaim.
+const maxClusterClaimValueLength = 1024
+
+// NodeKernel describes the nodes of a managed cluster that run the same kernel, architecture and OS image.
+type NodeKernel struct {
+	KernelVersion string `json:"kernelVersion"`
+	Architecture  string `json:"architecture,omitempty"`
+	OSImage       string `json:"osImage,omitempty"`
+	Nodes         int    `json:"nodes,omitempty"`
+}
+
+type NodeKernels []NodeKernel
+
+// Versions returns the sorted, distinct kernel versions.
+func (nk NodeKernels) Versions() []string {
+	versions := sets.New[string]()
+
+	for _, k := range nk {
+		versions.Insert(k.KernelVersion)
+	}
+
+	return sets.List(versions)
+}
+
+// OSImages returns the OS images of the nodes running kernelVersion.
+func (nk NodeKernels) OSImages(kernelVersion string) []string {
+	osImages := make([]string, 0)
+
+	for _, k := range nk {
+		if strings.TrimSuffix(k.KernelVersion, "+") == kernelVersion && k.OSImage != "" {
+			osImages = append(osImages, k.OSImage)
+		}
+	}
+
+	return osImages
+}
+
+// NodeKernelsFromNodes groups the nodes by kernel, architecture and OS image.
+// The result is sorted, so that it only changes if the nodes do.
+func NodeKernelsFromNodes(nodes []v1.Node) NodeKernels {
+	counts := make(map[NodeKernel]int)
+
+	for _, n := range nodes {
+		k := NodeKernel{
+			KernelVersion: n.Status.NodeInfo.KernelVersion,
+			Architecture:  n.Status.NodeInfo.Architecture,
+			OSImage:       n.Status.NodeInfo.OSImage,
+		}
+		counts[k]++
+	}
+
+	kernels := make(NodeKernels, 0, len(counts))
+
+	for k, count := range counts {
+		k.Nodes = count
+		kernels = append(kernels, k)
+	}
+
+	sort.Slice(kernels, func(i, j int) bool {
+		a, b := kernels[i], kernels[j]
+
+		if a.KernelVersion != b.KernelVersion {
+			return a.KernelVersion < b.KernelVersion
+		}
+		if a.Architecture != b.Architecture {
+			return a.Architecture < b.Architecture
+		}
+		return a.OSImage < b.OSImage
+	})
+
+	return kernels
+}
+
+// KernelsClaimValue returns the value of the kernels ClusterClaim.
+// The OS images are left out if the value would otherwise be too long for a ClusterClaim.
+func KernelsClaimValue(kernels NodeKernels) (string, error) {
+	b, err := json.Marshal(kernels)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal the kernels: %v", err)
+	}
+
+	if len(b) <= maxClusterClaimValueLength {
+		return string(b), nil
+	}
+
+	withoutOSImages := make(NodeKernels, 0, len(kernels))
+
+	for _, k := range kernels {
+		k.OSImage = ""
+		withoutOSImages = append(withoutOSImages, k)
+	}
+
+	if b, err = json.Marshal(withoutOSImages); err != nil {
+		return "", fmt.Errorf("could not marshal the kernels: %v", err)
+	}
+
+	if len(b) > maxClusterClaimValueLength {
+		return "", fmt.Errorf("the kernels do not fit in %d characters", maxClusterClaimValueLength)
+	}
+
+	return string(b), nil
+}
+
+// ManagedClusterKernels returns the kernels of the nodes of the managed cluster, as claimed by KMM on the Spoke.
+// Spokes that only publish the kernel versions ClusterClaim are supported, in which case only the kernel versions are
+// known.
+func ManagedClusterKernels(cluster clusterv1.ManagedCluster) (NodeKernels, error) {
+	var kernelVersionsClaim *clusterv1.ManagedClusterClaim
+
+	for i, clusterClaim := range cluster.Status.ClusterClaims {
+		switch clusterClaim.Name {
+		case constants.KernelsClusterClaimName:
+			kernels := make(NodeKernels, 0)
+			if err := json.Unmarshal([]byte(clusterClaim.Value), &kernels); err != nil {
+				return nil, fmt.Errorf("could not unmarshal the KMM kernels ClusterClaim: %v", err)
+			}
+			return kernels, nil
+		case constants.KernelVersionsClusterClaimName:
+			kernelVersionsClaim = &cluster.Status.ClusterClaims[i]
+		}
+	}
+
+	if kernelVersionsClaim == nil {
+		return nil, errors.New("KMM kernel version ClusterClaim not found")
+	}
+
+	kernelVersions := strings.Split(kernelVersionsClaim.Value, "\n")
+	sort.Strings(kernelVersions)
+
+	kernels := make(NodeKernels, 0, len(kernelVersions))
+
+	for _, kv := range kernelVersions {
+		kernels = append(kernels, NodeKernel{KernelVersion: kv})
+	}
+
+	return kernels, nil
+}
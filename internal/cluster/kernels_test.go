@@ -0,0 +1,86 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("NodeKernelsFromNodes", func() {
+	node := func(kernelVersion, arch, osImage string) v1.Node {
+		return v1.Node{
+			Status: v1.NodeStatus{
+				NodeInfo: v1.NodeSystemInfo{KernelVersion: kernelVersion, Architecture: arch, OSImage: osImage},
+			},
+		}
+	}
+
+	It("should group and sort the nodes", func() {
+		nodes := []v1.Node{
+			node("b", "amd64", "os"),
+			node("a", "arm64", "os"),
+			node("a", "amd64", "rt-os"),
+			node("b", "amd64", "os"),
+			node("a", "amd64", "os"),
+		}
+
+		Expect(
+			NodeKernelsFromNodes(nodes),
+		).To(
+			Equal(NodeKernels{
+				{KernelVersion: "a", Architecture: "amd64", OSImage: "os", Nodes: 1},
+				{KernelVersion: "a", Architecture: "amd64", OSImage: "rt-os", Nodes: 1},
+				{KernelVersion: "a", Architecture: "arm64", OSImage: "os", Nodes: 1},
+				{KernelVersion: "b", Architecture: "amd64", OSImage: "os", Nodes: 2},
+			}),
+		)
+	})
+})
+
+var _ = Describe("NodeKernels", func() {
+	kernels := NodeKernels{
+		{KernelVersion: "b+", OSImage: "os-1"},
+		{KernelVersion: "a", OSImage: "os-2"},
+		{KernelVersion: "b+", OSImage: "os-3"},
+		{KernelVersion: "c"},
+	}
+
+	It("should return the distinct kernel versions", func() {
+		Expect(kernels.Versions()).To(Equal([]string{"a", "b+", "c"}))
+	})
+
+	It("should return the OS images of a kernel", func() {
+		Expect(kernels.OSImages("b")).To(Equal([]string{"os-1", "os-3"}))
+		Expect(kernels.OSImages("c")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("KernelsClaimValue", func() {
+	It("should return the kernels as JSON", func() {
+		value, err := KernelsClaimValue(NodeKernels{{KernelVersion: "a", Architecture: "amd64", OSImage: "os", Nodes: 2}})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal(`[{"kernelVersion":"a","architecture":"amd64","osImage":"os","nodes":2}]`))
+	})
+
+	It("should leave the OS images out if the value is too long", func() {
+		kernels := make(NodeKernels, 0)
+		for i := 0; i < 10; i++ {
+			kernels = append(kernels, NodeKernel{KernelVersion: fmt.Sprint(i), OSImage: strings.Repeat("o", 100)})
+		}
+
+		value, err := KernelsClaimValue(kernels)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).NotTo(ContainSubstring("osImage"))
+	})
+
+	It("should return an error if the value is too long without the OS images", func() {
+		_, err := KernelsClaimValue(NodeKernels{{KernelVersion: strings.Repeat("k", 1024)}})
+
+		Expect(err).To(HaveOccurred())
+	})
+})
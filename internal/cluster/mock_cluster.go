@@ -70,19 +70,19 @@ func (mr *MockClusterAPIMockRecorder) GetModuleLoaderDataForKernel(mcm, kernelVe
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleLoaderDataForKernel", reflect.TypeOf((*MockClusterAPI)(nil).GetModuleLoaderDataForKernel), mcm, kernelVersion)
 }
 
-// KernelVersions mocks base method.
-func (m *MockClusterAPI) KernelVersions(cluster v1.ManagedCluster) ([]string, error) {
+// Kernels mocks base method.
+func (m *MockClusterAPI) Kernels(cluster v1.ManagedCluster) (NodeKernels, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "KernelVersions", cluster)
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "Kernels", cluster)
+	ret0, _ := ret[0].(NodeKernels)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// KernelVersions indicates an expected call of KernelVersions.
-func (mr *MockClusterAPIMockRecorder) KernelVersions(cluster any) *gomock.Call {
+// Kernels indicates an expected call of Kernels.
+func (mr *MockClusterAPIMockRecorder) Kernels(cluster any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KernelVersions", reflect.TypeOf((*MockClusterAPI)(nil).KernelVersions), cluster)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Kernels", reflect.TypeOf((*MockClusterAPI)(nil).Kernels), cluster)
 }
 
 // SelectedManagedClusters mocks base method.
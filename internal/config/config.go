@@ -25,7 +25,14 @@ import (
 )
 
 type Job struct {
-	GCDelay time.Duration `yaml:"gcDelay,omitempty"`
+	GCDelay                         time.Duration `yaml:"gcDelay,omitempty"`
+	ImageVerificationInterval       time.Duration `yaml:"imageVerificationInterval,omitempty"`
+	BuildInputsCheckInterval        time.Duration `yaml:"buildInputsCheckInterval,omitempty"`
+	MaxConcurrentBuilds             int           `yaml:"maxConcurrentBuilds,omitempty"`
+	MaxConcurrentBuildsPerNamespace int           `yaml:"maxConcurrentBuildsPerNamespace,omitempty"`
+	LogTailLines                    int64         `yaml:"logTailLines,omitempty"`
+	LogRetentionPerKernel           int           `yaml:"logRetentionPerKernel,omitempty"`
+	SigningServiceURL               string        `yaml:"signingServiceURL,omitempty"`
 }
 
 type Webhook struct {
@@ -192,6 +199,7 @@ func (ch *configHelper) decodeStrictYAMLIntoConfig(yamlData []byte, config *Conf
 func (ch *configHelper) newDefaultConfig(isHubConfig bool) *Config {
 	leaderElectionResourceID := "kmm.sigs.x-k8s.io"
 	gcDelay, _ := time.ParseDuration("0s")
+	buildInputsCheckInterval, _ := time.ParseDuration("1h")
 	if isHubConfig {
 		leaderElectionResourceID = "kmm-hub.sigs.x-k8s.io"
 	}
@@ -217,7 +225,10 @@ func (ch *configHelper) newDefaultConfig(isHubConfig bool) *Config {
 			Port:         9443,
 		},
 		Job: Job{
-			GCDelay: gcDelay,
+			GCDelay:                  gcDelay,
+			BuildInputsCheckInterval: buildInputsCheckInterval,
+			LogTailLines:             500,
+			LogRetentionPerKernel:    3,
 		},
 	}
 }
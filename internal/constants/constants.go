@@ -3,29 +3,42 @@ package constants
 const (
 	OCPBuilderServiceAccountName = "builder"
 	DTKImageStreamNamespace      = "openshift"
-
-	ModuleNameLabel        = "kmm.node.kubernetes.io/module.name"
-	ModuleNamespaceLabel   = "kmm.node.kubernetes.io/module.namespace"
-	NodeLabelerFinalizer   = "kmm.node.kubernetes.io/node-labeler"
-	TargetKernelTarget     = "kmm.node.kubernetes.io/target-kernel"
-	ResourceType           = "kmm.openshift.io/build.type"
-	ResourceHashAnnotation = "kmm.node.kubernetes.io/last-hash"
-	NamespaceLabelKey      = "kmm.node.k8s.io/contains-modules"
+	KernelDTKMappingName         = "default"
+
+	ModuleNameLabel             = "kmm.node.kubernetes.io/module.name"
+	ModuleNamespaceLabel        = "kmm.node.kubernetes.io/module.namespace"
+	NodeLabelerFinalizer        = "kmm.node.kubernetes.io/node-labeler"
+	TargetKernelTarget          = "kmm.node.kubernetes.io/target-kernel"
+	ResourceType                = "kmm.openshift.io/build.type"
+	ResourceHashAnnotation      = "kmm.node.kubernetes.io/last-hash"
+	ResourceAttemptAnnotation   = "kmm.node.kubernetes.io/attempt"
+	BuildLogLabel               = "kmm.node.kubernetes.io/build-log"
+	BuildLogAnnotation          = "kmm.node.kubernetes.io/build-log"
+	BuildLogRetentionAnnotation = "kmm.node.kubernetes.io/build-log-retention"
+	SignedFilesAnnotation       = "kmm.node.kubernetes.io/signed-files"
+	NamespaceLabelKey           = "kmm.node.k8s.io/contains-modules"
 
 	WorkerPodVersionLabelPrefix   = "beta.kmm.node.kubernetes.io/version-worker-pod"
 	SchedulePodVersionLabelPrefix = "beta.kmm.node.kubernetes.io/version-schedule-pod"
 	ModuleVersionLabelPrefix      = "kmm.node.kubernetes.io/version-module"
 
-	GCDelayFinalizer  = "kmm.node.kubernetes.io/gc-delay"
-	ModuleFinalizer   = "kmm.node.kubernetes.io/module-finalizer"
-	JobEventFinalizer = "kmm.node.kubernetes.io/job-event-finalizer"
-	BMCFinalizer      = "kmm.node.kubernetes.io/bmc-finalizer"
-
-	ManagedClusterModuleNameLabel  = "kmm.node.kubernetes.io/managedclustermodule.name"
-	KernelVersionsClusterClaimName = "kernel-versions.kmm.node.kubernetes.io"
-	DockerfileCMKey                = "dockerfile"
-	PublicSignDataKey              = "cert"
-	PrivateSignDataKey             = "key"
+	// DigestRolloutVersionLabelValue is set on the worker and schedule Pod version labels of a node to reload a
+	// Module from a new digest of its image.
+	DigestRolloutVersionLabelValue = "digest-rollout"
+
+	GCDelayFinalizer    = "kmm.node.kubernetes.io/gc-delay"
+	ModuleFinalizer     = "kmm.node.kubernetes.io/module-finalizer"
+	JobEventFinalizer   = "kmm.node.kubernetes.io/job-event-finalizer"
+	BMCFinalizer        = "kmm.node.kubernetes.io/bmc-finalizer"
+	SigningKeyFinalizer = "kmm.node.kubernetes.io/signing-key-finalizer"
+
+	ManagedClusterModuleNameLabel          = "kmm.node.kubernetes.io/managedclustermodule.name"
+	ManagedClusterModuleRevisionAnnotation = "kmm.node.kubernetes.io/managedclustermodule.revision"
+	KernelVersionsClusterClaimName         = "kernel-versions.kmm.node.kubernetes.io"
+	KernelsClusterClaimName                = "kernels.kmm.node.kubernetes.io"
+	DockerfileCMKey                        = "dockerfile"
+	PublicSignDataKey                      = "cert"
+	PrivateSignDataKey                     = "key"
 
 	DaemonSetRole              = "kmm.node.kubernetes.io/role"
 	DevicePluginRoleLabelValue = "device-plugin"
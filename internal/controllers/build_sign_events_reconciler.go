@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	buildv1 "github.com/openshift/api/build/v1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildsign"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/meta"
 	"golang.org/x/exp/maps"
@@ -72,16 +74,25 @@ func newJobEvent(jobType string) (*jobEvent, error) {
 }
 
 type JobEventReconciler struct {
-	client   client.Client
-	helper   JobEventReconcilerHelper
-	recorder record.EventRecorder
+	client            client.Client
+	helper            JobEventReconcilerHelper
+	logArchiver       buildsign.LogArchiver
+	signedFilesReader buildsign.SignedFilesReader
+	recorder          record.EventRecorder
 }
 
-func NewBuildSignEventsReconciler(client client.Client, helper JobEventReconcilerHelper, eventRecorder record.EventRecorder) *JobEventReconciler {
+func NewBuildSignEventsReconciler(
+	client client.Client,
+	helper JobEventReconcilerHelper,
+	logArchiver buildsign.LogArchiver,
+	signedFilesReader buildsign.SignedFilesReader,
+	eventRecorder record.EventRecorder) *JobEventReconciler {
 	return &JobEventReconciler{
-		client:   client,
-		helper:   helper,
-		recorder: eventRecorder,
+		client:            client,
+		helper:            helper,
+		logArchiver:       logArchiver,
+		signedFilesReader: signedFilesReader,
+		recorder:          eventRecorder,
 	}
 }
 
@@ -157,18 +168,52 @@ func (r *JobEventReconciler) Reconcile(ctx context.Context, build *buildv1.Build
 		return ctrl.Result{}, nil
 	}
 
-	if err = r.removeFinalizer(ctx, build); err != nil {
+	// archiving the logs is best effort: it must not prevent the build from being garbage collected
+	logConfigMap, err := r.logArchiver.Archive(ctx, build, owner)
+	if err != nil {
+		logger.Error(err, "Could not archive the logs")
+	}
+
+	var signedFiles []string
+
+	if build.Status.Phase == buildv1.BuildPhaseComplete && build.Labels[constants.ResourceType] == string(kmmv1beta1.SignImage) {
+		// like the logs, the signed files are only reported on a best effort basis
+		if signedFiles, err = r.signedFilesReader.Read(ctx, build); err != nil {
+			logger.Error(err, "Could not read the signed files")
+		}
+	}
+
+	patchFrom := client.MergeFrom(build.DeepCopy())
+
+	if logConfigMap != "" {
+		meta.SetAnnotation(build, constants.BuildLogAnnotation, logConfigMap)
+	}
+
+	if len(signedFiles) > 0 {
+		meta.SetAnnotation(build, constants.SignedFilesAnnotation, strings.Join(signedFiles, "\n"))
+	}
+
+	controllerutil.RemoveFinalizer(build, constants.JobEventFinalizer)
+
+	if err = r.client.Patch(ctx, build, patchFrom); err != nil {
 		return reconcile.Result{}, fmt.Errorf("could not patch build %s/%s: %v", build.Namespace, build.Name, err)
 	}
 
+	args := []interface{}{je.String(), kernelVersion}
+
+	if logConfigMap != "" {
+		eventAnnotations["log-configmap"] = logConfigMap
+		fmtString += "; logs archived in ConfigMap %s"
+		args = append(args, logConfigMap)
+	}
+
 	r.recorder.AnnotatedEventf(
 		owner,
 		eventAnnotations,
 		eventType,
 		reason,
 		fmtString,
-		je.String(),
-		kernelVersion,
+		args...,
 	)
 
 	return ctrl.Result{}, nil
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -9,6 +10,7 @@ import (
 	"github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	kmmv1beta2 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta2"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildsign"
 	testclient "github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/meta"
@@ -126,10 +128,12 @@ var _ = Describe("JobEventReconciler_Reconcile", func() {
 	var (
 		ctx = context.TODO()
 
-		fakeRecorder *record.FakeRecorder
-		mockClient   *testclient.MockClient
-		mockHelper   *MockJobEventReconcilerHelper
-		r            *JobEventReconciler
+		fakeRecorder    *record.FakeRecorder
+		mockClient      *testclient.MockClient
+		mockHelper      *MockJobEventReconcilerHelper
+		mockLogArchiver *buildsign.MockLogArchiver
+		mockSFR         *buildsign.MockSignedFilesReader
+		r               *JobEventReconciler
 	)
 
 	BeforeEach(func() {
@@ -137,7 +141,9 @@ var _ = Describe("JobEventReconciler_Reconcile", func() {
 		fakeRecorder = record.NewFakeRecorder(2)
 		mockClient = testclient.NewMockClient(ctrl)
 		mockHelper = NewMockJobEventReconcilerHelper(ctrl)
-		r = NewBuildSignEventsReconciler(mockClient, mockHelper, fakeRecorder)
+		mockLogArchiver = buildsign.NewMockLogArchiver(ctrl)
+		mockSFR = buildsign.NewMockSignedFilesReader(ctrl)
+		r = NewBuildSignEventsReconciler(mockClient, mockHelper, mockLogArchiver, mockSFR, fakeRecorder)
 	})
 
 	closeAndGetAllEvents := func(events chan string) []string {
@@ -247,6 +253,111 @@ var _ = Describe("JobEventReconciler_Reconcile", func() {
 		Expect(events).To(BeEmpty())
 	})
 
+	It("should link the archived logs from the Build and the event", func() {
+		or := getOwnerReferenceFromObject(ownerModule)
+
+		build := &buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{createdAnnotationKey: ""},
+				Labels: map[string]string{
+					constants.ResourceType:       string(kmmv1beta1.BuildImage),
+					constants.TargetKernelTarget: kernelVersion,
+				},
+				Finalizers:      []string{constants.JobEventFinalizer},
+				Namespace:       namespace,
+				OwnerReferences: []metav1.OwnerReference{or},
+			},
+			Status: buildv1.BuildStatus{Phase: buildv1.BuildPhaseFailed},
+		}
+
+		patchedBuild := build.DeepCopy()
+		meta.SetAnnotation(patchedBuild, constants.BuildLogAnnotation, "some-log")
+		controllerutil.RemoveFinalizer(patchedBuild, constants.JobEventFinalizer)
+
+		gomock.InOrder(
+			mockHelper.EXPECT().GetOwner(ctx, or, namespace),
+			mockLogArchiver.EXPECT().Archive(ctx, build, gomock.Any()).Return("some-log", nil),
+			mockClient.EXPECT().Patch(ctx, patchedBuild, gomock.Any()),
+		)
+
+		Expect(
+			r.Reconcile(ctx, build),
+		).To(
+			Equal(ctrl.Result{}),
+		)
+
+		events := closeAndGetAllEvents(fakeRecorder.Events)
+		Expect(events).To(HaveLen(1))
+		Expect(events[0]).To(ContainSubstring("Buildimage job failed for kernel " + kernelVersion + "; logs archived in ConfigMap some-log"))
+	})
+
+	It("should record the files signed by a successful sign", func() {
+		or := getOwnerReferenceFromObject(ownerModule)
+
+		build := &buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations:     map[string]string{createdAnnotationKey: ""},
+				Labels:          map[string]string{constants.ResourceType: string(kmmv1beta1.SignImage)},
+				Finalizers:      []string{constants.JobEventFinalizer},
+				Namespace:       namespace,
+				OwnerReferences: []metav1.OwnerReference{or},
+			},
+			Status: buildv1.BuildStatus{Phase: buildv1.BuildPhaseComplete},
+		}
+
+		patchedBuild := build.DeepCopy()
+		meta.SetAnnotation(patchedBuild, constants.SignedFilesAnnotation, "/modules/a.ko\n/modules/b.ko.xz")
+		controllerutil.RemoveFinalizer(patchedBuild, constants.JobEventFinalizer)
+
+		gomock.InOrder(
+			mockHelper.EXPECT().GetOwner(ctx, or, namespace),
+			mockLogArchiver.EXPECT().Archive(ctx, build, gomock.Any()),
+			mockSFR.EXPECT().Read(ctx, build).Return([]string{"/modules/a.ko", "/modules/b.ko.xz"}, nil),
+			mockClient.EXPECT().Patch(ctx, patchedBuild, gomock.Any()),
+		)
+
+		Expect(
+			r.Reconcile(ctx, build),
+		).To(
+			Equal(ctrl.Result{}),
+		)
+	})
+
+	It("should remove the finalizer even if the logs and signed files could not be read", func() {
+		or := getOwnerReferenceFromObject(ownerModule)
+
+		build := &buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations:     map[string]string{createdAnnotationKey: ""},
+				Labels:          map[string]string{constants.ResourceType: string(kmmv1beta1.SignImage)},
+				Finalizers:      []string{constants.JobEventFinalizer},
+				Namespace:       namespace,
+				OwnerReferences: []metav1.OwnerReference{or},
+			},
+			Status: buildv1.BuildStatus{Phase: buildv1.BuildPhaseComplete},
+		}
+
+		buildWithoutFinalizer := build.DeepCopy()
+		controllerutil.RemoveFinalizer(buildWithoutFinalizer, constants.JobEventFinalizer)
+
+		gomock.InOrder(
+			mockHelper.EXPECT().GetOwner(ctx, or, namespace),
+			mockLogArchiver.EXPECT().Archive(ctx, build, gomock.Any()).Return("", errors.New("some error")),
+			mockSFR.EXPECT().Read(ctx, build).Return(nil, errors.New("some error")),
+			mockClient.EXPECT().Patch(ctx, buildWithoutFinalizer, gomock.Any()),
+		)
+
+		Expect(
+			r.Reconcile(ctx, build),
+		).To(
+			Equal(ctrl.Result{}),
+		)
+
+		events := closeAndGetAllEvents(fakeRecorder.Events)
+		Expect(events).To(HaveLen(1))
+		Expect(events[0]).NotTo(ContainSubstring("logs archived"))
+	})
+
 	DescribeTable(
 		"should send the event for terminated builds",
 		func(jobType string, phase buildv1.BuildPhase, sendEventAndRemoveFinalizer bool, substring string, owner ctrlclient.Object) {
@@ -271,7 +382,8 @@ var _ = Describe("JobEventReconciler_Reconcile", func() {
 
 			getOwner := mockHelper.EXPECT().GetOwner(ctx, or, namespace)
 			if sendEventAndRemoveFinalizer {
-				mockClient.EXPECT().Patch(ctx, &podWithoutFinalizer, gomock.Any()).After(getOwner)
+				archive := mockLogArchiver.EXPECT().Archive(ctx, build, gomock.Any()).After(getOwner)
+				mockClient.EXPECT().Patch(ctx, &podWithoutFinalizer, gomock.Any()).After(archive)
 			}
 
 			Expect(
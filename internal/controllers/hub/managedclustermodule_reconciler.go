@@ -22,8 +22,11 @@ import (
 	"fmt"
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	workv1 "open-cluster-management.io/api/work/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -71,11 +74,16 @@ type ManagedClusterModuleReconciler struct {
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=modulebuildsignconfigs/finalizers,verbs=update
 //+kubebuilder:rbac:groups=work.open-cluster-management.io,resources=manifestworks,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=placementdecisions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=placements,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=create;delete;get;list;patch;watch
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups="core",resources=serviceaccounts,verbs=get;list;watch
 //+kubebuilder:rbac:groups=build.openshift.io,resources=builds,verbs=get;list;create;delete;watch;patch
+//+kubebuilder:rbac:groups=config.openshift.io,resources=images,verbs=get;list;watch
+//+kubebuilder:rbac:groups=config.openshift.io,resources=imagetagmirrorsets,verbs=list;watch
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=create;delete;get;list;patch;watch
+//+kubebuilder:rbac:groups=core,resources=pods/log,verbs=get
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=create;delete;get;list;patch;watch
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies/finalizers,verbs=update;patch
 
@@ -113,35 +121,72 @@ func (r *ManagedClusterModuleReconciler) Reconcile(ctx context.Context, mcm *hub
 
 	clusters, err := r.clusterAPI.SelectedManagedClusters(ctx, mcm)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to get selected clusters: %v", err)
+		return ctrl.Result{}, fmt.Errorf("failed to get selected clusters: %w", err)
 	}
 
+	var plan *rolloutPlan
+	if mcm.Spec.Rollout != nil {
+		if plan, err = r.planRollout(ctx, mcm, clusters.Items); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to plan the rollout: %v", err)
+		}
+	}
+
+	clusterStatuses := make([]hubv1beta1.ClusterModuleStatus, 0, len(clusters.Items))
+
 	for _, cluster := range clusters.Items {
 
 		logger := log.FromContext(ctx).WithValues("cluster", cluster.Name)
 		clusterCtx := log.IntoContext(ctx, logger)
 
-		kernelVersions, err := r.clusterAPI.KernelVersions(cluster)
+		clusterStatuses = append(clusterStatuses, hubv1beta1.ClusterModuleStatus{ClusterName: cluster.Name})
+		clusterStatus := &clusterStatuses[len(clusterStatuses)-1]
+
+		kernels, err := r.clusterAPI.Kernels(cluster)
 		if err != nil {
 			logger.Info(utils.WarnString(
 				fmt.Sprintf("No kernel versions found for managed cluster; skipping MIC patch: %v", err),
 			))
+			clusterStatus.Message = fmt.Sprintf("no kernel versions found: %v", err)
+			continue
+		}
+		kernelVersions := kernels.Versions()
+		clusterStatus.KernelVersions = kernelVersions
+
+		// the images of the cluster are the ones of the overridden Module
+		clusterMCM, err := manifestwork.ModuleForCluster(mcm, cluster)
+		if err != nil {
+			logger.Info(utils.WarnString(fmt.Sprintf("Failed to apply the overrides: %v", err)))
+			clusterStatus.Message = fmt.Sprintf("failed to apply the overrides: %v", err)
 			continue
 		}
 
-		err = r.reconHelper.setMicAsDesired(ctx, mcm, cluster.Name, kernelVersions)
+		unbuildableArchs, err := r.reconHelper.setMicAsDesired(ctx, clusterMCM, cluster.Name, kernels)
 		if err != nil {
 			logger.Info(utils.WarnString(fmt.Sprintf("Failed to set MIC as desired: %v", err)))
+			clusterStatus.Message = fmt.Sprintf("failed to set MIC as desired: %v", err)
 			continue
 		}
 
 		allImagesReady, err := r.reconHelper.areImagesReady(ctx, mcm.Name, cluster.Name)
 		if err != nil {
 			logger.Info(utils.WarnString(fmt.Sprintf("Failed to check if MIC is ready: %v", err)))
+			clusterStatus.Message = fmt.Sprintf("failed to check if the kmod images are ready: %v", err)
 			continue
 		}
 		if !allImagesReady {
 			logger.Info("not all images exist yet for the cluster; skipping ManifestWork reconciliation")
+			clusterStatus.Message = "waiting for the kmod images to be ready"
+			if len(unbuildableArchs) > 0 {
+				clusterStatus.Message += fmt.Sprintf("; no Hub node can build them for architectures %s",
+					strings.Join(unbuildableArchs, ", "))
+			}
+			continue
+		}
+		clusterStatus.ImagesReady = true
+
+		if plan != nil && !plan.allowed.Has(cluster.Name) {
+			logger.Info("the rollout has not reached the cluster yet; skipping ManifestWork reconciliation")
+			clusterStatus.Message = "waiting for the rollout to reach the cluster"
 			continue
 		}
 
@@ -153,10 +198,11 @@ func (r *ManagedClusterModuleReconciler) Reconcile(ctx context.Context, mcm *hub
 		}
 
 		opRes, err := controllerutil.CreateOrPatch(clusterCtx, r.client, mw, func() error {
-			return r.manifestAPI.SetManifestWorkAsDesired(ctx, mw, *mcm, kernelVersions)
+			return r.manifestAPI.SetManifestWorkAsDesired(ctx, mw, *mcm, cluster, kernelVersions)
 		})
 		if err != nil {
 			logger.Info(utils.WarnString(fmt.Sprintf("failed to create/patch ManifestWork for managed cluster: %v", err)))
+			clusterStatus.Message = fmt.Sprintf("failed to create/patch ManifestWork: %v", err)
 			continue
 		}
 
@@ -172,13 +218,43 @@ func (r *ManagedClusterModuleReconciler) Reconcile(ctx context.Context, mcm *hub
 		return ctrl.Result{}, fmt.Errorf("failed to fetch owned ManifestWorks of the ManagedClusterModule: %v", err)
 	}
 
-	if err := r.statusupdaterAPI.ManagedClusterModuleUpdateStatus(ctx, mcm, ownedManifestWorkList.Items); err != nil {
+	var rolloutStatus *hubv1beta1.RolloutStatus
+	if plan != nil {
+		rolloutStatus = plan.status
+	}
+
+	if err := r.statusupdaterAPI.ManagedClusterModuleUpdateStatus(ctx, mcm, clusterStatuses, rolloutStatus,
+		ownedManifestWorkList.Items); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to update status of the ManagedClusterModule: %v", err)
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// planRollout returns the rollout plan of the ManagedClusterModule to the clusters.
+func (r *ManagedClusterModuleReconciler) planRollout(ctx context.Context, mcm *hubv1beta1.ManagedClusterModule,
+	clusters []clusterv1.ManagedCluster) (*rolloutPlan, error) {
+
+	revision, err := manifestwork.Revision(mcm)
+	if err != nil {
+		return nil, err
+	}
+
+	ownedManifestWorkList, err := r.manifestAPI.GetOwnedManifestWorks(ctx, *mcm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch owned ManifestWorks of the ManagedClusterModule: %v", err)
+	}
+
+	plan := newRolloutPlan(mcm, clusters, ownedManifestWorkList.Items, revision)
+
+	if plan.status.PausedRevision != "" && (mcm.Status.Rollout == nil || mcm.Status.Rollout.PausedRevision != revision) {
+		log.FromContext(ctx).Info(utils.WarnString("ManifestWorks are degraded; pausing the rollout"),
+			"clusters", plan.degraded, "revision", revision)
+	}
+
+	return plan, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ManagedClusterModuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -192,6 +268,10 @@ func (r *ManagedClusterModuleReconciler) SetupWithManager(mgr ctrl.Manager) erro
 				r.filter.ManagedClusterModuleReconcilerManagedClusterPredicate(),
 			),
 		).
+		Watches(
+			&clusterv1beta1.PlacementDecision{},
+			handler.EnqueueRequestsFromMapFunc(r.filter.FindManagedClusterModulesForPlacementDecision),
+		).
 		Named(ManagedClusterModuleReconcilerName).
 		Complete(
 			reconcile.AsReconciler[*hubv1beta1.ManagedClusterModule](mgr.GetClient(), r),
@@ -201,7 +281,7 @@ func (r *ManagedClusterModuleReconciler) SetupWithManager(mgr ctrl.Manager) erro
 //go:generate mockgen -source=managedclustermodule_reconciler.go -package=hub -destination=mock_managedclustermodule_reconciler.go managedClusterModuleReconcilerHelperAPI
 
 type managedClusterModuleReconcilerHelperAPI interface {
-	setMicAsDesired(ctx context.Context, mcm *hubv1beta1.ManagedClusterModule, clusterName string, kernelVersions []string) error
+	setMicAsDesired(ctx context.Context, mcm *hubv1beta1.ManagedClusterModule, clusterName string, kernels cluster.NodeKernels) ([]string, error)
 	areImagesReady(ctx context.Context, mcmName, clusterName string) (bool, error)
 	handleHubNetworkPolicies(ctx context.Context, mcm *hubv1beta1.ManagedClusterModule) error
 }
@@ -222,28 +302,70 @@ func newManagedClusterModuleReconcilerHelper(client client.Client, clusterAPI cl
 	}
 }
 
+// setMicAsDesired creates or patches the MIC of the images needed by the nodes of the cluster, one per kernel and
+// architecture. It returns the architectures that the images are built for on the Hub but that no Hub node has.
 func (rh *managedClusterModuleReconcilerHelper) setMicAsDesired(ctx context.Context, mcm *hubv1beta1.ManagedClusterModule,
-	clusterName string, kernelVersions []string) error {
+	clusterName string, kernels cluster.NodeKernels) ([]string, error) {
 
-	var images []kmmv1beta1.ModuleImageSpec
-	for _, kver := range kernelVersions {
+	type kernelArch struct {
+		kernelVersion string
+		arch          string
+	}
+
+	var (
+		images   []kmmv1beta1.ModuleImageSpec
+		hubArchs sets.Set[string]
+	)
+	seen := sets.New[kernelArch]()
+	imageArchs := make(map[string]string)
+	unbuildableArchs := sets.New[string]()
+	for _, kernel := range kernels {
+
+		kver := strings.TrimSuffix(kernel.KernelVersion, "+")
+		if seen.Has(kernelArch{kernelVersion: kver, arch: kernel.Architecture}) {
+			// the nodes running the same kernel with other OS images need the same image
+			continue
+		}
+		seen.Insert(kernelArch{kernelVersion: kver, arch: kernel.Architecture})
 
-		kver = strings.TrimSuffix(kver, "+")
 		mld, err := rh.clusterAPI.GetModuleLoaderDataForKernel(mcm, kver)
 		if err != nil {
 			if !errors.Is(err, module.ErrNoMatchingKernelMapping) {
-				return fmt.Errorf("failed to get MLD for kernel %s: %v", kver, err)
+				return nil, fmt.Errorf("failed to get MLD for kernel %s: %v", kver, err)
 			}
 			// error getting kernelVersion or kernelVersion is not targeted by the managedClusterModule
 			continue
 		}
+
+		// an image is built for a single architecture
+		if arch, ok := imageArchs[mld.ContainerImage]; ok && arch != kernel.Architecture {
+			return nil, fmt.Errorf("image %s is needed by nodes of architectures %s and %s running kernel %s",
+				mld.ContainerImage, arch, kernel.Architecture, kver)
+		}
+		imageArchs[mld.ContainerImage] = kernel.Architecture
+
+		build := buildForArchitecture(mld.Build, kernel.Architecture)
+		if build != nil && build.Selector[v1.LabelArchStable] != "" {
+			arch := build.Selector[v1.LabelArchStable]
+			if hubArchs == nil {
+				if hubArchs, err = rh.getHubArchitectures(ctx); err != nil {
+					return nil, err
+				}
+			}
+			if !hubArchs.Has(arch) {
+				unbuildableArchs.Insert(arch)
+			}
+		}
+
 		mis := kmmv1beta1.ModuleImageSpec{
 			Image:         mld.ContainerImage,
 			KernelVersion: mld.KernelVersion,
-			Build:         mld.Build,
+			Build:         build,
 			Sign:          mld.Sign,
 			RegistryTLS:   mld.RegistryTLS,
 			DirName:       mld.Modprobe.DirName,
+			ModuleNames:   mld.KernelModuleNames(),
+			FirmwarePath:  mld.Modprobe.FirmwarePath,
 		}
 		images = append(images, mis)
 	}
@@ -251,11 +373,48 @@ func (rh *managedClusterModuleReconcilerHelper) setMicAsDesired(ctx context.Cont
 	micName := mcm.Name + "-" + clusterName
 	micNamespace := rh.clusterAPI.GetDefaultArtifactsNamespace()
 	if err := rh.micAPI.CreateOrPatch(ctx, micName, micNamespace, images, mcm.Spec.ModuleSpec.ImageRepoSecret,
-		mcm.Spec.ModuleSpec.ModuleLoader.Container.ImagePullPolicy, true, mcm.Spec.ModuleSpec.ImageRebuildTriggerGeneration, mcm.Spec.ModuleSpec.Tolerations, mcm); err != nil {
-		return fmt.Errorf("failed to createOrPatch MIC %s: %v", micName, err)
+		mcm.Spec.ModuleSpec.ModuleLoader.Container.ImagePullPolicy, true, mcm.Spec.ModuleSpec.ImageRebuildTriggerGeneration,
+		mcm.Spec.ModuleSpec.BuildPriority, mcm.Spec.ModuleSpec.Tolerations, nil, mcm.Spec.ModuleSpec.ImageGC, mcm); err != nil {
+		return nil, fmt.Errorf("failed to createOrPatch MIC %s: %v", micName, err)
 	}
 
-	return nil
+	return sets.List(unbuildableArchs), nil
+}
+
+// getHubArchitectures returns the architectures of the Hub nodes.
+func (rh *managedClusterModuleReconcilerHelper) getHubArchitectures(ctx context.Context) (sets.Set[string], error) {
+	nodeList := v1.NodeList{}
+	if err := rh.client.List(ctx, &nodeList); err != nil {
+		return nil, fmt.Errorf("failed to list the Hub nodes: %v", err)
+	}
+
+	archs := sets.New[string]()
+	for _, node := range nodeList.Items {
+		if arch := node.Labels[v1.LabelArchStable]; arch != "" {
+			archs.Insert(arch)
+		}
+	}
+
+	return archs, nil
+}
+
+// buildForArchitecture returns the build scheduled on the Hub nodes of the architecture of the Spoke nodes running the
+// kernel, unless it already selects an architecture or the architecture is not known.
+func buildForArchitecture(build *kmmv1beta1.Build, arch string) *kmmv1beta1.Build {
+	if build == nil || arch == "" {
+		return build
+	}
+	if _, ok := build.Selector[v1.LabelArchStable]; ok {
+		return build
+	}
+
+	build = build.DeepCopy()
+	if build.Selector == nil {
+		build.Selector = make(map[string]string, 1)
+	}
+	build.Selector[v1.LabelArchStable] = arch
+
+	return build
 }
 
 func (rh *managedClusterModuleReconcilerHelper) areImagesReady(ctx context.Context, mcmName, clusterName string) (bool, error) {
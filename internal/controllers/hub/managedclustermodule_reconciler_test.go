@@ -19,6 +19,7 @@ package hub
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -27,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
 	hubv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
@@ -34,6 +36,7 @@ import (
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/cluster"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/manifestwork"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mic"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
@@ -91,6 +94,32 @@ var _ = Describe("ManagedClusterModuleReconciler_Reconcile", func() {
 		Expect(err.Error()).To(ContainSubstring("failed to get selected clusters"))
 	})
 
+	It("should not garbage collect the ManifestWorks if the Placement is not ready", func() {
+		mcm := &v1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: mcmName,
+			},
+			Spec: v1beta1.ManagedClusterModuleSpec{
+				Placement: &v1beta1.PlacementReference{Name: "placement", Namespace: "some-namespace"},
+			},
+		}
+
+		gomock.InOrder(
+			mockMCMReconHelperAPI.EXPECT().handleHubNetworkPolicies(ctx, mcm).Return(nil),
+			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, mcm).
+				Return(nil, fmt.Errorf("%w: Placement some-namespace/placement was not found", cluster.ErrPlacementNotReady)),
+		)
+
+		mcmr := &ManagedClusterModuleReconciler{
+			clusterAPI:  mockClusterAPI,
+			manifestAPI: mockManifestAPI,
+			reconHelper: mockMCMReconHelperAPI,
+		}
+
+		_, err := mcmr.Reconcile(context.Background(), mcm)
+		Expect(err).To(MatchError(cluster.ErrPlacementNotReady))
+	})
+
 	It("should fail if we fail to garbage collect", func() {
 
 		mcm := &v1beta1.ManagedClusterModule{
@@ -174,7 +203,7 @@ var _ = Describe("ManagedClusterModuleReconciler_Reconcile", func() {
 			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, mcm).Return(expectedClusters, nil),
 			mockManifestAPI.EXPECT().GarbageCollect(ctx, *expectedClusters, *mcm).Return(nil),
 			mockManifestAPI.EXPECT().GetOwnedManifestWorks(ctx, *mcm).Return(expectedOwnManifestWork, nil),
-			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, expectedOwnManifestWork.Items).
+			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, []v1beta1.ClusterModuleStatus{}, nil, expectedOwnManifestWork.Items).
 				Return(errors.New("some error")),
 		)
 
@@ -216,11 +245,13 @@ var _ = Describe("ManagedClusterModuleReconciler_Reconcile", func() {
 		gomock.InOrder(
 			mockMCMReconHelperAPI.EXPECT().handleHubNetworkPolicies(ctx, mcm).Return(nil),
 			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, mcm).Return(expectedClusters, nil),
-			mockClusterAPI.EXPECT().KernelVersions(expectedClusters.Items[0]).Return([]string{}, errors.New("some error")),
+			mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[0]).Return(nil, errors.New("some error")),
 			// we expecte all the loop to be skipped with no errors
 			mockManifestAPI.EXPECT().GarbageCollect(ctx, *expectedClusters, *mcm).Return(nil),
 			mockManifestAPI.EXPECT().GetOwnedManifestWorks(ctx, *mcm).Return(expectedOwnManifestWork, nil),
-			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, expectedOwnManifestWork.Items).Return(nil),
+			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, []v1beta1.ClusterModuleStatus{
+				{ClusterName: "cluster-1", Message: "no kernel versions found: some error"},
+			}, nil, expectedOwnManifestWork.Items).Return(nil),
 		)
 
 		mcmr := &ManagedClusterModuleReconciler{
@@ -254,6 +285,7 @@ var _ = Describe("ManagedClusterModuleReconciler_Reconcile", func() {
 		}
 
 		expectedKernelVersion := []string{"v1.2.3"}
+		expectedKernels := cluster.NodeKernels{{KernelVersion: "v1.2.3"}}
 
 		expectedOwnManifestWork := &workv1.ManifestWorkList{
 			Items: []workv1.ManifestWork{},
@@ -262,12 +294,84 @@ var _ = Describe("ManagedClusterModuleReconciler_Reconcile", func() {
 		gomock.InOrder(
 			mockMCMReconHelperAPI.EXPECT().handleHubNetworkPolicies(ctx, mcm).Return(nil),
 			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, mcm).Return(expectedClusters, nil),
-			mockClusterAPI.EXPECT().KernelVersions(expectedClusters.Items[0]).Return(expectedKernelVersion, nil),
-			mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-1", expectedKernelVersion).Return(errors.New("error")),
+			mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[0]).Return(expectedKernels, nil),
+			mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-1", expectedKernels).Return(nil, errors.New("error")),
 			// we expecte all the loop to be skipped with no errors
 			mockManifestAPI.EXPECT().GarbageCollect(ctx, *expectedClusters, *mcm).Return(nil),
 			mockManifestAPI.EXPECT().GetOwnedManifestWorks(ctx, *mcm).Return(expectedOwnManifestWork, nil),
-			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, expectedOwnManifestWork.Items).Return(nil),
+			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, []v1beta1.ClusterModuleStatus{
+				{ClusterName: "cluster-1", KernelVersions: expectedKernelVersion, Message: "failed to set MIC as desired: error"},
+			}, nil, expectedOwnManifestWork.Items).Return(nil),
+		)
+
+		mcmr := &ManagedClusterModuleReconciler{
+			manifestAPI:      mockManifestAPI,
+			clusterAPI:       mockClusterAPI,
+			statusupdaterAPI: mockStatusupdaterAPI,
+			reconHelper:      mockMCMReconHelperAPI,
+		}
+
+		_, err := mcmr.Reconcile(context.Background(), mcm)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should set the MIC of the cluster as desired from the overridden Module", func() {
+
+		mcm := &v1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: mcmName,
+			},
+			Spec: v1beta1.ManagedClusterModuleSpec{
+				ModuleSpec: kmmv1beta1.ModuleSpec{
+					ModuleLoader: &kmmv1beta1.ModuleLoaderSpec{
+						Container: kmmv1beta1.ModuleLoaderContainerSpec{ContainerImage: "image"},
+					},
+				},
+				Overrides: []v1beta1.ClusterOverride{
+					{
+						ClusterName: "cluster-1",
+						Patch:       `{"spec": {"moduleLoader": {"container": {"containerImage": "cluster-1-image"}}}}`,
+					},
+					{
+						ClusterName: "cluster-2",
+						Type:        v1beta1.JSONPatchType,
+						Patch:       `[{"op": "remove", "path": "/spec/missing"}]`,
+					},
+				},
+			},
+		}
+
+		expectedClusters := &clusterv1.ManagedClusterList{
+			Items: []clusterv1.ManagedCluster{
+				{ObjectMeta: metav1.ObjectMeta{Name: "cluster-1"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "cluster-2"}},
+			},
+		}
+
+		expectedKernels := cluster.NodeKernels{{KernelVersion: "v1.2.3"}}
+
+		expectedOwnManifestWork := &workv1.ManifestWorkList{
+			Items: []workv1.ManifestWork{},
+		}
+
+		clusterMCM := mcm.DeepCopy()
+		clusterMCM.Spec.ModuleSpec.ModuleLoader.Container.ContainerImage = "cluster-1-image"
+
+		gomock.InOrder(
+			mockMCMReconHelperAPI.EXPECT().handleHubNetworkPolicies(ctx, mcm).Return(nil),
+			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, mcm).Return(expectedClusters, nil),
+			mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[0]).Return(expectedKernels, nil),
+			mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, clusterMCM, "cluster-1", expectedKernels).Return(nil, errors.New("error")),
+			mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[1]).Return(expectedKernels, nil),
+			mockManifestAPI.EXPECT().GarbageCollect(ctx, *expectedClusters, *mcm).Return(nil),
+			mockManifestAPI.EXPECT().GetOwnedManifestWorks(ctx, *mcm).Return(expectedOwnManifestWork, nil),
+			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, gomock.Any(), nil, expectedOwnManifestWork.Items).DoAndReturn(
+				func(_ context.Context, _ *v1beta1.ManagedClusterModule, statuses []v1beta1.ClusterModuleStatus, _ *v1beta1.RolloutStatus, _ []workv1.ManifestWork) error {
+					Expect(statuses).To(HaveLen(2))
+					Expect(statuses[1].Message).To(HavePrefix("failed to apply the overrides: "))
+					return nil
+				},
+			),
 		)
 
 		mcmr := &ManagedClusterModuleReconciler{
@@ -301,6 +405,7 @@ var _ = Describe("ManagedClusterModuleReconciler_Reconcile", func() {
 		}
 
 		expectedKernelVersion := []string{"v1.2.3"}
+		expectedKernels := cluster.NodeKernels{{KernelVersion: "v1.2.3"}}
 
 		expectedOwnManifestWork := &workv1.ManifestWorkList{
 			Items: []workv1.ManifestWork{},
@@ -309,13 +414,19 @@ var _ = Describe("ManagedClusterModuleReconciler_Reconcile", func() {
 		gomock.InOrder(
 			mockMCMReconHelperAPI.EXPECT().handleHubNetworkPolicies(ctx, mcm).Return(nil),
 			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, mcm).Return(expectedClusters, nil),
-			mockClusterAPI.EXPECT().KernelVersions(expectedClusters.Items[0]).Return(expectedKernelVersion, nil),
-			mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-1", expectedKernelVersion).Return(nil),
+			mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[0]).Return(expectedKernels, nil),
+			mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-1", expectedKernels).Return(nil, nil),
 			mockMCMReconHelperAPI.EXPECT().areImagesReady(ctx, mcm.Name, "cluster-1").Return(false, errors.New("some error")),
 			// we expecte the rest of the loop to be skipped with no errors
 			mockManifestAPI.EXPECT().GarbageCollect(ctx, *expectedClusters, *mcm).Return(nil),
 			mockManifestAPI.EXPECT().GetOwnedManifestWorks(ctx, *mcm).Return(expectedOwnManifestWork, nil),
-			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, expectedOwnManifestWork.Items).Return(nil),
+			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, []v1beta1.ClusterModuleStatus{
+				{
+					ClusterName:    "cluster-1",
+					KernelVersions: expectedKernelVersion,
+					Message:        "failed to check if the kmod images are ready: some error",
+				},
+			}, nil, expectedOwnManifestWork.Items).Return(nil),
 		)
 
 		mcmr := &ManagedClusterModuleReconciler{
@@ -349,6 +460,62 @@ var _ = Describe("ManagedClusterModuleReconciler_Reconcile", func() {
 		}
 
 		expectedKernelVersion := []string{"v1.2.3"}
+		expectedKernels := cluster.NodeKernels{{KernelVersion: "v1.2.3"}}
+
+		expectedOwnManifestWork := &workv1.ManifestWorkList{
+			Items: []workv1.ManifestWork{},
+		}
+
+		gomock.InOrder(
+			mockMCMReconHelperAPI.EXPECT().handleHubNetworkPolicies(ctx, mcm).Return(nil),
+			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, mcm).Return(expectedClusters, nil),
+			mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[0]).Return(expectedKernels, nil),
+			mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-1", expectedKernels).Return(nil, nil),
+			mockMCMReconHelperAPI.EXPECT().areImagesReady(ctx, mcm.Name, "cluster-1").Return(false, nil),
+			// we expecte the rest of the loop to be skipped with no errors
+			mockManifestAPI.EXPECT().GarbageCollect(ctx, *expectedClusters, *mcm).Return(nil),
+			mockManifestAPI.EXPECT().GetOwnedManifestWorks(ctx, *mcm).Return(expectedOwnManifestWork, nil),
+			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, []v1beta1.ClusterModuleStatus{
+				{
+					ClusterName:    "cluster-1",
+					KernelVersions: expectedKernelVersion,
+					Message:        "waiting for the kmod images to be ready",
+				},
+			}, nil, expectedOwnManifestWork.Items).Return(nil),
+		)
+
+		mcmr := &ManagedClusterModuleReconciler{
+			manifestAPI:      mockManifestAPI,
+			clusterAPI:       mockClusterAPI,
+			statusupdaterAPI: mockStatusupdaterAPI,
+			reconHelper:      mockMCMReconHelperAPI,
+		}
+
+		_, err := mcmr.Reconcile(context.Background(), mcm)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report the architectures that no Hub node can build the images for", func() {
+
+		mcm := &v1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: mcmName,
+			},
+			Spec: v1beta1.ManagedClusterModuleSpec{},
+		}
+
+		expectedClusters := &clusterv1.ManagedClusterList{
+			Items: []clusterv1.ManagedCluster{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cluster-1",
+					},
+				},
+			},
+		}
+
+		expectedKernelVersion := []string{"v1.2.3"}
+		expectedKernels := cluster.NodeKernels{{KernelVersion: "v1.2.3"}}
 
 		expectedOwnManifestWork := &workv1.ManifestWorkList{
 			Items: []workv1.ManifestWork{},
@@ -357,13 +524,19 @@ var _ = Describe("ManagedClusterModuleReconciler_Reconcile", func() {
 		gomock.InOrder(
 			mockMCMReconHelperAPI.EXPECT().handleHubNetworkPolicies(ctx, mcm).Return(nil),
 			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, mcm).Return(expectedClusters, nil),
-			mockClusterAPI.EXPECT().KernelVersions(expectedClusters.Items[0]).Return(expectedKernelVersion, nil),
-			mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-1", expectedKernelVersion).Return(nil),
+			mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[0]).Return(expectedKernels, nil),
+			mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-1", expectedKernels).Return([]string{"arm64", "s390x"}, nil),
 			mockMCMReconHelperAPI.EXPECT().areImagesReady(ctx, mcm.Name, "cluster-1").Return(false, nil),
 			// we expecte the rest of the loop to be skipped with no errors
 			mockManifestAPI.EXPECT().GarbageCollect(ctx, *expectedClusters, *mcm).Return(nil),
 			mockManifestAPI.EXPECT().GetOwnedManifestWorks(ctx, *mcm).Return(expectedOwnManifestWork, nil),
-			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, expectedOwnManifestWork.Items).Return(nil),
+			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, []v1beta1.ClusterModuleStatus{
+				{
+					ClusterName:    "cluster-1",
+					KernelVersions: expectedKernelVersion,
+					Message:        "waiting for the kmod images to be ready; no Hub node can build them for architectures arm64, s390x",
+				},
+			}, nil, expectedOwnManifestWork.Items).Return(nil),
 		)
 
 		mcmr := &ManagedClusterModuleReconciler{
@@ -402,6 +575,7 @@ var _ = Describe("ManagedClusterModuleReconciler_Reconcile", func() {
 		}
 
 		expectedKernelVersion := []string{"v1.2.3"}
+		expectedKernels := cluster.NodeKernels{{KernelVersion: "v1.2.3"}}
 
 		expectedOwnManifestWork := &workv1.ManifestWorkList{
 			Items: []workv1.ManifestWork{},
@@ -409,17 +583,20 @@ var _ = Describe("ManagedClusterModuleReconciler_Reconcile", func() {
 
 		mockMCMReconHelperAPI.EXPECT().handleHubNetworkPolicies(ctx, mcm).Return(nil)
 		mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, mcm).Return(expectedClusters, nil)
-		mockClusterAPI.EXPECT().KernelVersions(expectedClusters.Items[0]).Return(expectedKernelVersion, nil)
-		mockClusterAPI.EXPECT().KernelVersions(expectedClusters.Items[1]).Return(expectedKernelVersion, nil)
-		mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-1", expectedKernelVersion).Return(nil)
+		mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[0]).Return(expectedKernels, nil)
+		mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[1]).Return(expectedKernels, nil)
+		mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-1", expectedKernels).Return(nil, nil)
 		mockMCMReconHelperAPI.EXPECT().areImagesReady(ctx, mcm.Name, "cluster-1").Return(true, nil)
-		mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-2", expectedKernelVersion).Return(nil)
+		mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-2", expectedKernels).Return(nil, nil)
 		mockMCMReconHelperAPI.EXPECT().areImagesReady(ctx, mcm.Name, "cluster-2").Return(true, nil)
 		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
-		mockManifestAPI.EXPECT().SetManifestWorkAsDesired(ctx, gomock.Any(), *mcm, expectedKernelVersion).Return(nil).Times(2)
+		mockManifestAPI.EXPECT().SetManifestWorkAsDesired(ctx, gomock.Any(), *mcm, gomock.Any(), expectedKernelVersion).Return(nil).Times(2)
 		mockManifestAPI.EXPECT().GarbageCollect(ctx, *expectedClusters, *mcm).Return(nil)
 		mockManifestAPI.EXPECT().GetOwnedManifestWorks(ctx, *mcm).Return(expectedOwnManifestWork, nil)
-		mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, expectedOwnManifestWork.Items).Return(nil)
+		mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, []v1beta1.ClusterModuleStatus{
+			{ClusterName: "cluster-1", KernelVersions: expectedKernelVersion, ImagesReady: true},
+			{ClusterName: "cluster-2", KernelVersions: expectedKernelVersion, ImagesReady: true},
+		}, nil, expectedOwnManifestWork.Items).Return(nil)
 
 		mcmr := &ManagedClusterModuleReconciler{
 			client:           mockClient,
@@ -432,6 +609,245 @@ var _ = Describe("ManagedClusterModuleReconciler_Reconcile", func() {
 		_, err := mcmr.Reconcile(context.Background(), mcm)
 		Expect(err).NotTo(HaveOccurred())
 	})
+
+	It("should only create the ManifestWorks of the clusters the rollout has reached", func() {
+
+		mcm := &v1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: mcmName,
+			},
+			Spec: v1beta1.ManagedClusterModuleSpec{
+				Rollout: &v1beta1.RolloutStrategy{BatchSize: 1},
+			},
+		}
+
+		expectedClusters := &clusterv1.ManagedClusterList{
+			Items: []clusterv1.ManagedCluster{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cluster-1",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cluster-2",
+					},
+				},
+			},
+		}
+
+		expectedKernelVersion := []string{"v1.2.3"}
+		expectedKernels := cluster.NodeKernels{{KernelVersion: "v1.2.3"}}
+
+		expectedOwnManifestWork := &workv1.ManifestWorkList{
+			Items: []workv1.ManifestWork{},
+		}
+
+		revision, err := manifestwork.Revision(mcm)
+		Expect(err).NotTo(HaveOccurred())
+
+		mockMCMReconHelperAPI.EXPECT().handleHubNetworkPolicies(ctx, mcm).Return(nil)
+		mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, mcm).Return(expectedClusters, nil)
+		mockManifestAPI.EXPECT().GetOwnedManifestWorks(ctx, *mcm).Return(expectedOwnManifestWork, nil).Times(2)
+		mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[0]).Return(expectedKernels, nil)
+		mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[1]).Return(expectedKernels, nil)
+		mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-1", expectedKernels).Return(nil, nil)
+		mockMCMReconHelperAPI.EXPECT().areImagesReady(ctx, mcm.Name, "cluster-1").Return(true, nil)
+		mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-2", expectedKernels).Return(nil, nil)
+		mockMCMReconHelperAPI.EXPECT().areImagesReady(ctx, mcm.Name, "cluster-2").Return(true, nil)
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockManifestAPI.EXPECT().SetManifestWorkAsDesired(ctx, gomock.Any(), *mcm, expectedClusters.Items[0], expectedKernelVersion).Return(nil)
+		mockManifestAPI.EXPECT().GarbageCollect(ctx, *expectedClusters, *mcm).Return(nil)
+		mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, []v1beta1.ClusterModuleStatus{
+			{ClusterName: "cluster-1", KernelVersions: expectedKernelVersion, ImagesReady: true},
+			{
+				ClusterName:    "cluster-2",
+				KernelVersions: expectedKernelVersion,
+				ImagesReady:    true,
+				Message:        "waiting for the rollout to reach the cluster",
+			},
+		}, &v1beta1.RolloutStatus{
+			Revision: revision,
+			Stage:    0,
+			Stages:   2,
+			Message:  "rolling out to clusters: cluster-1",
+		}, expectedOwnManifestWork.Items).Return(nil)
+
+		mcmr := &ManagedClusterModuleReconciler{
+			client:           mockClient,
+			manifestAPI:      mockManifestAPI,
+			clusterAPI:       mockClusterAPI,
+			statusupdaterAPI: mockStatusupdaterAPI,
+			reconHelper:      mockMCMReconHelperAPI,
+		}
+
+		_, err = mcmr.Reconcile(context.Background(), mcm)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should pause the rollout if the ManifestWork of a cluster already rolled out to is degraded", func() {
+
+		mcm := &v1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: mcmName,
+			},
+			Spec: v1beta1.ManagedClusterModuleSpec{
+				Rollout: &v1beta1.RolloutStrategy{},
+			},
+		}
+
+		expectedClusters := &clusterv1.ManagedClusterList{
+			Items: []clusterv1.ManagedCluster{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cluster-1",
+					},
+				},
+			},
+		}
+
+		revision, err := manifestwork.Revision(mcm)
+		Expect(err).NotTo(HaveOccurred())
+
+		expectedOwnManifestWork := &workv1.ManifestWorkList{
+			Items: []workv1.ManifestWork{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        mcmName,
+						Namespace:   "cluster-1",
+						Annotations: map[string]string{constants.ManagedClusterModuleRevisionAnnotation: revision},
+					},
+					Status: workv1.ManifestWorkStatus{
+						Conditions: []metav1.Condition{
+							{Type: workv1.WorkDegraded, Status: metav1.ConditionTrue},
+						},
+					},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			mockMCMReconHelperAPI.EXPECT().handleHubNetworkPolicies(ctx, mcm).Return(nil),
+			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, mcm).Return(expectedClusters, nil),
+			mockManifestAPI.EXPECT().GetOwnedManifestWorks(ctx, *mcm).Return(expectedOwnManifestWork, nil),
+			mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[0]).Return(nil, errors.New("some error")),
+			mockManifestAPI.EXPECT().GarbageCollect(ctx, *expectedClusters, gomock.Any()).Return(nil),
+			mockManifestAPI.EXPECT().GetOwnedManifestWorks(ctx, gomock.Any()).Return(expectedOwnManifestWork, nil),
+			mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, gomock.Any(), &v1beta1.RolloutStatus{
+				Revision:        revision,
+				Stage:           0,
+				Stages:          1,
+				UpdatedClusters: 1,
+				PausedRevision:  revision,
+				Message: "the rollout is paused: ManifestWorks are degraded on clusters: cluster-1; " +
+					"set .spec.rollout.resumeRevision to " + revision + " to resume it",
+			}, expectedOwnManifestWork.Items).Return(nil),
+		)
+
+		mcmr := &ManagedClusterModuleReconciler{
+			client:           mockClient,
+			manifestAPI:      mockManifestAPI,
+			clusterAPI:       mockClusterAPI,
+			statusupdaterAPI: mockStatusupdaterAPI,
+			reconHelper:      mockMCMReconHelperAPI,
+		}
+
+		_, err = mcmr.Reconcile(context.Background(), mcm)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should move the rollout forward once it is resumed after a degradation", func() {
+
+		mcm := &v1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: mcmName,
+			},
+			Spec: v1beta1.ManagedClusterModuleSpec{
+				Rollout: &v1beta1.RolloutStrategy{BatchSize: 1},
+			},
+		}
+
+		revision, err := manifestwork.Revision(mcm)
+		Expect(err).NotTo(HaveOccurred())
+
+		mcm.Spec.Rollout.ResumeRevision = revision
+		mcm.Status.Rollout = &v1beta1.RolloutStatus{Revision: revision, PausedRevision: revision}
+
+		expectedClusters := &clusterv1.ManagedClusterList{
+			Items: []clusterv1.ManagedCluster{
+				{ObjectMeta: metav1.ObjectMeta{Name: "cluster-1"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "cluster-2"}},
+			},
+		}
+
+		expectedKernelVersion := []string{"v1.2.3"}
+		expectedKernels := cluster.NodeKernels{{KernelVersion: "v1.2.3"}}
+
+		expectedOwnManifestWork := &workv1.ManifestWorkList{
+			Items: []workv1.ManifestWork{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        mcmName,
+						Namespace:   "cluster-1",
+						Annotations: map[string]string{constants.ManagedClusterModuleRevisionAnnotation: revision},
+					},
+					Status: workv1.ManifestWorkStatus{
+						Conditions: []metav1.Condition{
+							{Type: workv1.WorkAvailable, Status: metav1.ConditionTrue},
+							{Type: workv1.WorkDegraded, Status: metav1.ConditionTrue},
+						},
+						ResourceStatus: workv1.ManifestResourceStatus{
+							Manifests: []workv1.ManifestCondition{
+								{
+									ResourceMeta: workv1.ManifestResourceMeta{Group: kmmv1beta1.GroupVersion.Group, Resource: "modules"},
+									StatusFeedbacks: workv1.StatusFeedbackResult{
+										Values: []workv1.FeedbackValue{
+											{
+												Name:  "managedClusterModuleRevision",
+												Value: workv1.FieldValue{Type: workv1.String, String: &revision},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		mockMCMReconHelperAPI.EXPECT().handleHubNetworkPolicies(ctx, mcm).Return(nil)
+		mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, mcm).Return(expectedClusters, nil)
+		mockManifestAPI.EXPECT().GetOwnedManifestWorks(ctx, *mcm).Return(expectedOwnManifestWork, nil).Times(2)
+		mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[0]).Return(expectedKernels, nil)
+		mockClusterAPI.EXPECT().Kernels(expectedClusters.Items[1]).Return(expectedKernels, nil)
+		mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-1", expectedKernels).Return(nil, nil)
+		mockMCMReconHelperAPI.EXPECT().areImagesReady(ctx, mcm.Name, "cluster-1").Return(true, nil)
+		mockMCMReconHelperAPI.EXPECT().setMicAsDesired(ctx, mcm, "cluster-2", expectedKernels).Return(nil, nil)
+		mockMCMReconHelperAPI.EXPECT().areImagesReady(ctx, mcm.Name, "cluster-2").Return(true, nil)
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		mockManifestAPI.EXPECT().SetManifestWorkAsDesired(ctx, gomock.Any(), *mcm, expectedClusters.Items[0], expectedKernelVersion).Return(nil)
+		mockManifestAPI.EXPECT().SetManifestWorkAsDesired(ctx, gomock.Any(), *mcm, expectedClusters.Items[1], expectedKernelVersion).Return(nil)
+		mockManifestAPI.EXPECT().GarbageCollect(ctx, *expectedClusters, *mcm).Return(nil)
+		mockStatusupdaterAPI.EXPECT().ManagedClusterModuleUpdateStatus(ctx, mcm, gomock.Any(), &v1beta1.RolloutStatus{
+			Revision:        revision,
+			Stage:           1,
+			Stages:          2,
+			UpdatedClusters: 1,
+			Message:         "rolling out to clusters: cluster-2",
+		}, expectedOwnManifestWork.Items).Return(nil)
+
+		mcmr := &ManagedClusterModuleReconciler{
+			client:           mockClient,
+			manifestAPI:      mockManifestAPI,
+			clusterAPI:       mockClusterAPI,
+			statusupdaterAPI: mockStatusupdaterAPI,
+			reconHelper:      mockMCMReconHelperAPI,
+		}
+
+		_, err = mcmr.Reconcile(context.Background(), mcm)
+		Expect(err).NotTo(HaveOccurred())
+	})
 })
 
 var _ = Describe("managedClusterModuleReconcilerHelperAPI_setMicAsDesired", func() {
@@ -472,11 +888,11 @@ var _ = Describe("managedClusterModuleReconcilerHelperAPI_setMicAsDesired", func
 				ModuleSpec: kmmv1beta1.ModuleSpec{},
 			},
 		}
-		kernelVersions := []string{"v1.1.1"}
+		kernels := cluster.NodeKernels{{KernelVersion: "v1.1.1"}}
 
-		mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, kernelVersions[0]).Return(nil, errors.New("some error"))
+		mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, kernels[0].KernelVersion).Return(nil, errors.New("some error"))
 
-		err := mcmReconHelperAPI.setMicAsDesired(ctx, mcm, clusterName, kernelVersions)
+		_, err := mcmReconHelperAPI.setMicAsDesired(ctx, mcm, clusterName, kernels)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("failed to get MLD for kernel"))
 	})
@@ -493,16 +909,16 @@ var _ = Describe("managedClusterModuleReconcilerHelperAPI_setMicAsDesired", func
 				},
 			},
 		}
-		kernelVersions := []string{"v1.1.1"}
+		kernels := cluster.NodeKernels{{KernelVersion: "v1.1.1"}}
 
 		gomock.InOrder(
-			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, kernelVersions[0]).Return(&api.ModuleLoaderData{}, nil),
+			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, kernels[0].KernelVersion).Return(&api.ModuleLoaderData{}, nil),
 			mockClusterAPI.EXPECT().GetDefaultArtifactsNamespace().Return(defaultNs),
-			mockMIC.EXPECT().CreateOrPatch(ctx, micName, defaultNs, gomock.Any(), nil, v1.PullPolicy(""), true, mcm.Spec.ModuleSpec.ImageRebuildTriggerGeneration, gomock.Any(), mcm).
+			mockMIC.EXPECT().CreateOrPatch(ctx, micName, defaultNs, gomock.Any(), nil, v1.PullPolicy(""), true, mcm.Spec.ModuleSpec.ImageRebuildTriggerGeneration, mcm.Spec.ModuleSpec.BuildPriority, gomock.Any(), nil, mcm.Spec.ModuleSpec.ImageGC, mcm).
 				Return(errors.New("some error")),
 		)
 
-		err := mcmReconHelperAPI.setMicAsDesired(ctx, mcm, clusterName, kernelVersions)
+		_, err := mcmReconHelperAPI.setMicAsDesired(ctx, mcm, clusterName, kernels)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("failed to createOrPatch MIC"))
 	})
@@ -519,7 +935,7 @@ var _ = Describe("managedClusterModuleReconcilerHelperAPI_setMicAsDesired", func
 				},
 			},
 		}
-		kernelVersions := []string{"v1.1.1", "1.1.2"}
+		kernels := cluster.NodeKernels{{KernelVersion: "v1.1.1"}, {KernelVersion: "1.1.2"}}
 
 		expectedMLD := &api.ModuleLoaderData{
 			ContainerImage: "some-image",
@@ -533,13 +949,13 @@ var _ = Describe("managedClusterModuleReconcilerHelperAPI_setMicAsDesired", func
 		}
 
 		gomock.InOrder(
-			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, kernelVersions[0]).Return(nil, module.ErrNoMatchingKernelMapping),
-			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, kernelVersions[1]).Return(expectedMLD, nil),
+			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, kernels[0].KernelVersion).Return(nil, module.ErrNoMatchingKernelMapping),
+			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, kernels[1].KernelVersion).Return(expectedMLD, nil),
 			mockClusterAPI.EXPECT().GetDefaultArtifactsNamespace().Return(defaultNs),
-			mockMIC.EXPECT().CreateOrPatch(ctx, micName, defaultNs, expectedImages, gomock.Any(), v1.PullPolicy(""), true, mcm.Spec.ModuleSpec.ImageRebuildTriggerGeneration, gomock.Any(), mcm).Return(nil),
+			mockMIC.EXPECT().CreateOrPatch(ctx, micName, defaultNs, expectedImages, gomock.Any(), v1.PullPolicy(""), true, mcm.Spec.ModuleSpec.ImageRebuildTriggerGeneration, mcm.Spec.ModuleSpec.BuildPriority, gomock.Any(), nil, mcm.Spec.ModuleSpec.ImageGC, mcm).Return(nil),
 		)
 
-		err := mcmReconHelperAPI.setMicAsDesired(ctx, mcm, clusterName, kernelVersions)
+		_, err := mcmReconHelperAPI.setMicAsDesired(ctx, mcm, clusterName, kernels)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -555,7 +971,7 @@ var _ = Describe("managedClusterModuleReconcilerHelperAPI_setMicAsDesired", func
 				},
 			},
 		}
-		kernelVersions := []string{"v1.1.1", "1.1.2"}
+		kernels := cluster.NodeKernels{{KernelVersion: "v1.1.1"}, {KernelVersion: "1.1.2"}}
 
 		expectedMLDs := []*api.ModuleLoaderData{
 			{
@@ -579,14 +995,105 @@ var _ = Describe("managedClusterModuleReconcilerHelperAPI_setMicAsDesired", func
 		}
 
 		gomock.InOrder(
-			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, kernelVersions[0]).Return(expectedMLDs[0], nil),
-			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, kernelVersions[1]).Return(expectedMLDs[1], nil),
+			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, kernels[0].KernelVersion).Return(expectedMLDs[0], nil),
+			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, kernels[1].KernelVersion).Return(expectedMLDs[1], nil),
+			mockClusterAPI.EXPECT().GetDefaultArtifactsNamespace().Return(defaultNs),
+			mockMIC.EXPECT().CreateOrPatch(ctx, micName, defaultNs, expectedImages, gomock.Any(), v1.PullPolicy(""), true, mcm.Spec.ModuleSpec.ImageRebuildTriggerGeneration, mcm.Spec.ModuleSpec.BuildPriority, gomock.Any(), nil, mcm.Spec.ModuleSpec.ImageGC, mcm).Return(nil),
+		)
+
+		_, err := mcmReconHelperAPI.setMicAsDesired(ctx, mcm, clusterName, kernels)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should build the images on nodes of the architecture of the Spoke nodes", func() {
+
+		mcm := &hubv1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: mcmName,
+			},
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				ModuleSpec: kmmv1beta1.ModuleSpec{
+					ModuleLoader: &kmmv1beta1.ModuleLoaderSpec{},
+				},
+			},
+		}
+		kernels := cluster.NodeKernels{
+			{KernelVersion: "v1.1.1", Architecture: "arm64", OSImage: "os-1"},
+			{KernelVersion: "v1.1.1", Architecture: "arm64", OSImage: "os-2"},
+			{KernelVersion: "v1.1.2", Architecture: "amd64"},
+		}
+
+		build := &kmmv1beta1.Build{Selector: map[string]string{"key": "value"}}
+		archBuild := &kmmv1beta1.Build{Selector: map[string]string{v1.LabelArchStable: "s390x"}}
+
+		expectedImages := []kmmv1beta1.ModuleImageSpec{
+			{
+				Image:         "some-image",
+				KernelVersion: "v1.1.1",
+				Build:         &kmmv1beta1.Build{Selector: map[string]string{"key": "value", v1.LabelArchStable: "arm64"}},
+			},
+			{
+				Image:         "other-image",
+				KernelVersion: "v1.1.2",
+				Build:         archBuild,
+			},
+		}
+
+		gomock.InOrder(
+			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, "v1.1.1").Return(
+				&api.ModuleLoaderData{ContainerImage: "some-image", KernelVersion: "v1.1.1", Build: build}, nil,
+			),
+			mockClient.EXPECT().List(ctx, &v1.NodeList{}).DoAndReturn(
+				func(_ interface{}, nodeList *v1.NodeList, _ ...ctrlclient.ListOption) error {
+					nodeList.Items = []v1.Node{
+						{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1.LabelArchStable: "amd64"}}},
+						{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1.LabelArchStable: "arm64"}}},
+					}
+					return nil
+				},
+			),
+			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, "v1.1.2").Return(
+				&api.ModuleLoaderData{ContainerImage: "other-image", KernelVersion: "v1.1.2", Build: archBuild}, nil,
+			),
 			mockClusterAPI.EXPECT().GetDefaultArtifactsNamespace().Return(defaultNs),
-			mockMIC.EXPECT().CreateOrPatch(ctx, micName, defaultNs, expectedImages, gomock.Any(), v1.PullPolicy(""), true, mcm.Spec.ModuleSpec.ImageRebuildTriggerGeneration, gomock.Any(), mcm).Return(nil),
+			mockMIC.EXPECT().CreateOrPatch(ctx, micName, defaultNs, expectedImages, gomock.Any(), v1.PullPolicy(""), true, mcm.Spec.ModuleSpec.ImageRebuildTriggerGeneration, mcm.Spec.ModuleSpec.BuildPriority, gomock.Any(), nil, mcm.Spec.ModuleSpec.ImageGC, mcm).Return(nil),
 		)
 
-		err := mcmReconHelperAPI.setMicAsDesired(ctx, mcm, clusterName, kernelVersions)
+		unbuildableArchs, err := mcmReconHelperAPI.setMicAsDesired(ctx, mcm, clusterName, kernels)
 		Expect(err).NotTo(HaveOccurred())
+		Expect(unbuildableArchs).To(Equal([]string{"s390x"}))
+		Expect(build.Selector).To(Equal(map[string]string{"key": "value"}))
+	})
+
+	It("should build an image per kernel and architecture", func() {
+
+		mcm := &hubv1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: mcmName,
+			},
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				ModuleSpec: kmmv1beta1.ModuleSpec{
+					ModuleLoader: &kmmv1beta1.ModuleLoaderSpec{},
+				},
+			},
+		}
+		kernels := cluster.NodeKernels{
+			{KernelVersion: "v1.1.1", Architecture: "amd64"},
+			{KernelVersion: "v1.1.1", Architecture: "arm64"},
+		}
+
+		gomock.InOrder(
+			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, "v1.1.1").Return(
+				&api.ModuleLoaderData{ContainerImage: "some-image", KernelVersion: "v1.1.1"}, nil,
+			),
+			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(mcm, "v1.1.1").Return(
+				&api.ModuleLoaderData{ContainerImage: "some-image", KernelVersion: "v1.1.1"}, nil,
+			),
+		)
+
+		_, err := mcmReconHelperAPI.setMicAsDesired(ctx, mcm, clusterName, kernels)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("image some-image is needed by nodes of architectures amd64 and arm64"))
 	})
 })
 
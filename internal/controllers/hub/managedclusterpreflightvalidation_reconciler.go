@@ -0,0 +1,303 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hubv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta2"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/cluster"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/filter"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mic"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/preflight"
+)
+
+const ManagedClusterPreflightValidationReconcilerName = "ManagedClusterPreflightValidation"
+
+// ManagedClusterPreflightValidationReconciler reconciles a ManagedClusterPreflightValidation object
+type ManagedClusterPreflightValidationReconciler struct {
+	client      client.Client
+	filter      *filter.Filter
+	reconHelper managedClusterPreflightValidationReconcilerHelperAPI
+}
+
+//+kubebuilder:rbac:groups=hub.kmm.sigs.x-k8s.io,resources=managedclusterpreflightvalidations,verbs=get;list;watch
+//+kubebuilder:rbac:groups=hub.kmm.sigs.x-k8s.io,resources=managedclusterpreflightvalidations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=hub.kmm.sigs.x-k8s.io,resources=managedclusterpreflightvalidations/finalizers,verbs=update
+
+func NewManagedClusterPreflightValidationReconciler(
+	client client.Client,
+	clusterAPI cluster.ClusterAPI,
+	filter *filter.Filter,
+	micAPI mic.MIC,
+) *ManagedClusterPreflightValidationReconciler {
+
+	reconHelper := newManagedClusterPreflightValidationReconcilerHelper(client, clusterAPI, micAPI)
+	return &ManagedClusterPreflightValidationReconciler{
+		client:      client,
+		filter:      filter,
+		reconHelper: reconHelper,
+	}
+}
+
+func (r *ManagedClusterPreflightValidationReconciler) Reconcile(ctx context.Context,
+	mcpv *hubv1beta1.ManagedClusterPreflightValidation) (ctrl.Result, error) {
+
+	logger := log.FromContext(ctx)
+
+	logger.Info("Starting ManagedClusterPreflightValidation reconciliation")
+
+	mcmsData, err := r.reconHelper.getManagedClusterModulesData(ctx, mcpv)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get ManagedClusterModules' data: %v", err)
+	}
+
+	if err = r.reconHelper.updateStatus(ctx, mcmsData, mcpv); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update ManagedClusterPreflightValidation's status: %v", err)
+	}
+
+	if err = r.reconHelper.processPreflightValidation(ctx, mcmsData, mcpv); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to process the preflight validation: %v", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ManagedClusterPreflightValidationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hubv1beta1.ManagedClusterPreflightValidation{}, builder.WithPredicates(filter.PreflightReconcilerUpdatePredicate())).
+		Owns(&kmmv1beta1.ModuleImagesConfig{}).
+		Watches(
+			&hubv1beta1.ManagedClusterModule{},
+			handler.EnqueueRequestsFromMapFunc(r.filter.EnqueueAllManagedClusterPreflightValidations),
+			builder.WithPredicates(filter.PreflightReconcilerUpdatePredicate()),
+		).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: 1,
+		}).
+		Named(ManagedClusterPreflightValidationReconcilerName).
+		Complete(
+			reconcile.AsReconciler[*hubv1beta1.ManagedClusterPreflightValidation](mgr.GetClient(), r),
+		)
+}
+
+// managedClusterModulePreflightData is the data needed to validate a ManagedClusterModule against the kernel of the
+// ManagedClusterPreflightValidation.
+type managedClusterModulePreflightData struct {
+	name string
+	// clusters are the selected managed clusters the validation applies to.
+	clusters []string
+	// mld is nil if no kernel mapping of the ManagedClusterModule matches the kernel.
+	mld *api.ModuleLoaderData
+}
+
+//go:generate mockgen -source=managedclusterpreflightvalidation_reconciler.go -package=hub -destination=mock_managedclusterpreflightvalidation_reconciler.go managedClusterPreflightValidationReconcilerHelperAPI
+
+type managedClusterPreflightValidationReconcilerHelperAPI interface {
+	getManagedClusterModulesData(ctx context.Context, mcpv *hubv1beta1.ManagedClusterPreflightValidation) ([]managedClusterModulePreflightData, error)
+	updateStatus(ctx context.Context, mcmsData []managedClusterModulePreflightData, mcpv *hubv1beta1.ManagedClusterPreflightValidation) error
+	processPreflightValidation(ctx context.Context, mcmsData []managedClusterModulePreflightData, mcpv *hubv1beta1.ManagedClusterPreflightValidation) error
+}
+
+type managedClusterPreflightValidationReconcilerHelper struct {
+	client     client.Client
+	clusterAPI cluster.ClusterAPI
+	micAPI     mic.MIC
+}
+
+func newManagedClusterPreflightValidationReconcilerHelper(client client.Client, clusterAPI cluster.ClusterAPI,
+	micAPI mic.MIC) managedClusterPreflightValidationReconcilerHelperAPI {
+
+	return &managedClusterPreflightValidationReconcilerHelper{
+		client:     client,
+		clusterAPI: clusterAPI,
+		micAPI:     micAPI,
+	}
+}
+
+func (rh *managedClusterPreflightValidationReconcilerHelper) getManagedClusterModulesData(ctx context.Context,
+	mcpv *hubv1beta1.ManagedClusterPreflightValidation) ([]managedClusterModulePreflightData, error) {
+
+	mcmList := hubv1beta1.ManagedClusterModuleList{}
+	if err := rh.client.List(ctx, &mcmList); err != nil {
+		return nil, fmt.Errorf("failed to get list of all ManagedClusterModules: %v", err)
+	}
+
+	clusterSelector := labels.SelectorFromSet(mcpv.Spec.ClusterSelector)
+	kernelVersion := strings.TrimSuffix(mcpv.Spec.KernelVersion, "+")
+
+	mcmsData := make([]managedClusterModulePreflightData, 0, len(mcmList.Items))
+	for _, mcm := range mcmList.Items {
+		// ignore ManagedClusterModules being deleted or not loading any kernel module
+		if mcm.GetDeletionTimestamp() != nil || mcm.Spec.ModuleSpec.ModuleLoader == nil {
+			continue
+		}
+
+		clusters, err := rh.clusterAPI.SelectedManagedClusters(ctx, &mcm)
+		if errors.Is(err, cluster.ErrPlacementNotReady) {
+			// the clusters of the ManagedClusterModule are not known yet
+			log.FromContext(ctx).Info("Skipping ManagedClusterModule", "name", mcm.Name, "reason", err.Error())
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get selected clusters of ManagedClusterModule %s: %v", mcm.Name, err)
+		}
+
+		clusterNames := make([]string, 0, len(clusters.Items))
+		for _, c := range clusters.Items {
+			if clusterSelector.Matches(labels.Set(c.Labels)) {
+				clusterNames = append(clusterNames, c.Name)
+			}
+		}
+		if len(clusterNames) == 0 {
+			continue
+		}
+		slices.Sort(clusterNames)
+
+		mld, err := rh.clusterAPI.GetModuleLoaderDataForKernel(&mcm, kernelVersion)
+		if err != nil && !errors.Is(err, module.ErrNoMatchingKernelMapping) {
+			return nil, fmt.Errorf("failed to get MLD for ManagedClusterModule %s: %v", mcm.Name, err)
+		}
+
+		mcmsData = append(mcmsData, managedClusterModulePreflightData{name: mcm.Name, clusters: clusterNames, mld: mld})
+	}
+
+	return mcmsData, nil
+}
+
+func (rh *managedClusterPreflightValidationReconcilerHelper) updateStatus(ctx context.Context,
+	mcmsData []managedClusterModulePreflightData, mcpv *hubv1beta1.ManagedClusterPreflightValidation) error {
+
+	unmodifiedMCPV := mcpv.DeepCopy()
+
+	statuses := make([]hubv1beta1.ManagedClusterModulePreflightStatus, 0, len(mcmsData))
+	for _, data := range mcmsData {
+		status := v1beta2.VerificationFailure
+		reason := "mapping not found"
+
+		if data.mld != nil {
+			status = v1beta2.VerificationInProgress
+			reason = "verification is not finished yet"
+			micObj, err := rh.micAPI.Get(ctx, preflightMICName(mcpv.Name, data.name), rh.clusterAPI.GetDefaultArtifactsNamespace())
+			if err == nil {
+				status, reason = preflight.ImageVerificationStatus(rh.micAPI, micObj, data.mld)
+			}
+		}
+
+		stage := v1beta2.VerificationStageImage
+		if preflight.VerificationDone(status) {
+			stage = v1beta2.VerificationStageDone
+		}
+
+		statuses = append(statuses, hubv1beta1.ManagedClusterModulePreflightStatus{
+			Name:     data.name,
+			Clusters: data.clusters,
+			CRBaseStatus: v1beta2.CRBaseStatus{
+				VerificationStatus: status,
+				StatusReason:       reason,
+				VerificationStage:  stage,
+				LastTransitionTime: transitionTime(unmodifiedMCPV, data.name, status, reason),
+			},
+		})
+	}
+
+	mcpv.Status.ManagedClusterModules = statuses
+
+	return rh.client.Status().Patch(ctx, mcpv, client.MergeFrom(unmodifiedMCPV))
+}
+
+func (rh *managedClusterPreflightValidationReconcilerHelper) processPreflightValidation(ctx context.Context,
+	mcmsData []managedClusterModulePreflightData, mcpv *hubv1beta1.ManagedClusterPreflightValidation) error {
+
+	errs := []error{}
+	for _, data := range mcmsData {
+		if data.mld == nil {
+			continue
+		}
+
+		status := managedClusterModulePreflightStatus(mcpv, data.name)
+		if status != nil && status.VerificationStage == v1beta2.VerificationStageDone {
+			continue
+		}
+
+		mld := data.mld
+		micObjSpec := kmmv1beta1.ModuleImageSpec{
+			Image:         mld.ContainerImage,
+			KernelVersion: mld.KernelVersion,
+			Build:         mld.Build,
+			Sign:          mld.Sign,
+			RegistryTLS:   mld.RegistryTLS,
+			DirName:       mld.Modprobe.DirName,
+			ModuleNames:   mld.KernelModuleNames(),
+			FirmwarePath:  mld.Modprobe.FirmwarePath,
+		}
+		micName := preflightMICName(mcpv.Name, data.name)
+		err := rh.micAPI.CreateOrPatch(ctx, micName, rh.clusterAPI.GetDefaultArtifactsNamespace(),
+			[]kmmv1beta1.ModuleImageSpec{micObjSpec}, mld.ImageRepoSecret, mld.ImagePullPolicy, mcpv.Spec.PushBuiltImage,
+			nil, mld.BuildPriority, mld.Tolerations, nil, nil, mcpv)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply MIC %s: %v", micName, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// preflightMICName returns the name of the MIC holding the image a ManagedClusterModule needs for the kernel of a
+// ManagedClusterPreflightValidation. The names of the clusters cannot contain dots, so it cannot collide with the name
+// of the MIC of a ManagedClusterModule for a cluster.
+func preflightMICName(mcpvName, mcmName string) string {
+	return mcpvName + "." + mcmName + ".preflight"
+}
+
+func managedClusterModulePreflightStatus(mcpv *hubv1beta1.ManagedClusterPreflightValidation,
+	mcmName string) *hubv1beta1.ManagedClusterModulePreflightStatus {
+
+	for i, status := range mcpv.Status.ManagedClusterModules {
+		if status.Name == mcmName {
+			return &mcpv.Status.ManagedClusterModules[i]
+		}
+	}
+	return nil
+}
+
+// transitionTime returns the time the ManagedClusterModule's preflight status last changed, or now if it changes.
+func transitionTime(mcpv *hubv1beta1.ManagedClusterPreflightValidation, mcmName, status, reason string) metav1.Time {
+	previous := managedClusterModulePreflightStatus(mcpv, mcmName)
+	if previous != nil && previous.VerificationStatus == status && previous.StatusReason == reason {
+		return previous.LastTransitionTime
+	}
+	return metav1.Now()
+}
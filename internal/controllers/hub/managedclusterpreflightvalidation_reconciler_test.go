@@ -0,0 +1,400 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	hubv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta2"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/cluster"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mic"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
+)
+
+var _ = Describe("ManagedClusterPreflightValidationReconciler_Reconcile", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockHelper *MockmanagedClusterPreflightValidationReconcilerHelperAPI
+		r          *ManagedClusterPreflightValidationReconciler
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockHelper = NewMockmanagedClusterPreflightValidationReconcilerHelperAPI(ctrl)
+		r = &ManagedClusterPreflightValidationReconciler{reconHelper: mockHelper}
+	})
+
+	ctx := context.Background()
+	mcpv := &hubv1beta1.ManagedClusterPreflightValidation{}
+	mcmsData := []managedClusterModulePreflightData{{name: "mcm"}}
+
+	It("should fail if we fail to get the ManagedClusterModules' data", func() {
+		mockHelper.EXPECT().getManagedClusterModulesData(ctx, mcpv).Return(nil, errors.New("some error"))
+
+		_, err := r.Reconcile(ctx, mcpv)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail if we fail to update the status", func() {
+		gomock.InOrder(
+			mockHelper.EXPECT().getManagedClusterModulesData(ctx, mcpv).Return(mcmsData, nil),
+			mockHelper.EXPECT().updateStatus(ctx, mcmsData, mcpv).Return(errors.New("some error")),
+		)
+
+		_, err := r.Reconcile(ctx, mcpv)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail if we fail to process the preflight validation", func() {
+		gomock.InOrder(
+			mockHelper.EXPECT().getManagedClusterModulesData(ctx, mcpv).Return(mcmsData, nil),
+			mockHelper.EXPECT().updateStatus(ctx, mcmsData, mcpv).Return(nil),
+			mockHelper.EXPECT().processPreflightValidation(ctx, mcmsData, mcpv).Return(errors.New("some error")),
+		)
+
+		_, err := r.Reconcile(ctx, mcpv)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should work as expected", func() {
+		gomock.InOrder(
+			mockHelper.EXPECT().getManagedClusterModulesData(ctx, mcpv).Return(mcmsData, nil),
+			mockHelper.EXPECT().updateStatus(ctx, mcmsData, mcpv).Return(nil),
+			mockHelper.EXPECT().processPreflightValidation(ctx, mcmsData, mcpv).Return(nil),
+		)
+
+		_, err := r.Reconcile(ctx, mcpv)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("ManagedClusterPreflightValidationReconciler_getManagedClusterModulesData", func() {
+	const kernelVersion = "5.14.0-1.el9.x86_64"
+
+	var (
+		ctrl           *gomock.Controller
+		mockClient     *client.MockClient
+		mockClusterAPI *cluster.MockClusterAPI
+		rh             managedClusterPreflightValidationReconcilerHelperAPI
+		mcpv           *hubv1beta1.ManagedClusterPreflightValidation
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = client.NewMockClient(ctrl)
+		mockClusterAPI = cluster.NewMockClusterAPI(ctrl)
+		rh = newManagedClusterPreflightValidationReconcilerHelper(mockClient, mockClusterAPI, nil)
+		mcpv = &hubv1beta1.ManagedClusterPreflightValidation{
+			Spec: hubv1beta1.ManagedClusterPreflightValidationSpec{KernelVersion: kernelVersion},
+		}
+	})
+
+	ctx := context.Background()
+
+	newMCM := func(name string) hubv1beta1.ManagedClusterModule {
+		return hubv1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				ModuleSpec: kmmv1beta1.ModuleSpec{
+					ModuleLoader: &kmmv1beta1.ModuleLoaderSpec{},
+				},
+			},
+		}
+	}
+
+	clusterList := &clusterv1.ManagedClusterList{
+		Items: []clusterv1.ManagedCluster{
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-2", Labels: map[string]string{"upgrade": "true"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Labels: map[string]string{"upgrade": "true"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-3"}},
+		},
+	}
+
+	It("should fail if we fail to list the ManagedClusterModules", func() {
+		mockClient.EXPECT().List(ctx, gomock.Any()).Return(errors.New("some error"))
+
+		_, err := rh.getManagedClusterModulesData(ctx, mcpv)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail if we fail to get the selected clusters", func() {
+		gomock.InOrder(
+			mockClient.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *hubv1beta1.ManagedClusterModuleList, _ ...interface{}) error {
+					list.Items = []hubv1beta1.ManagedClusterModule{newMCM("mcm")}
+					return nil
+				},
+			),
+			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, gomock.Any()).Return(nil, errors.New("some error")),
+		)
+
+		_, err := rh.getManagedClusterModulesData(ctx, mcpv)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should skip the ManagedClusterModules whose Placement is not ready", func() {
+		gomock.InOrder(
+			mockClient.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *hubv1beta1.ManagedClusterModuleList, _ ...interface{}) error {
+					list.Items = []hubv1beta1.ManagedClusterModule{newMCM("mcm")}
+					return nil
+				},
+			),
+			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, gomock.Any()).Return(
+				nil, fmt.Errorf("%w: some reason", cluster.ErrPlacementNotReady),
+			),
+		)
+
+		mcmsData, err := rh.getManagedClusterModulesData(ctx, mcpv)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mcmsData).To(BeEmpty())
+	})
+
+	It("should fail if we fail to get the MLD for a reason other than a missing mapping", func() {
+		gomock.InOrder(
+			mockClient.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *hubv1beta1.ManagedClusterModuleList, _ ...interface{}) error {
+					list.Items = []hubv1beta1.ManagedClusterModule{newMCM("mcm")}
+					return nil
+				},
+			),
+			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, gomock.Any()).Return(clusterList, nil),
+			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(gomock.Any(), kernelVersion).Return(nil, errors.New("some error")),
+		)
+
+		_, err := rh.getManagedClusterModulesData(ctx, mcpv)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return the data of the ManagedClusterModules selecting the clusters", func() {
+		mcpv.Spec.ClusterSelector = map[string]string{"upgrade": "true"}
+
+		deletedMCM := newMCM("deleted")
+		deletedMCM.DeletionTimestamp = &metav1.Time{}
+
+		noModuleLoaderMCM := newMCM("no-module-loader")
+		noModuleLoaderMCM.Spec.ModuleSpec.ModuleLoader = nil
+
+		mld := &api.ModuleLoaderData{Name: "mcm-with-mapping"}
+
+		gomock.InOrder(
+			mockClient.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *hubv1beta1.ManagedClusterModuleList, _ ...interface{}) error {
+					list.Items = []hubv1beta1.ManagedClusterModule{
+						deletedMCM,
+						noModuleLoaderMCM,
+						newMCM("no-selected-cluster"),
+						newMCM("mcm-with-mapping"),
+						newMCM("mcm-without-mapping"),
+					}
+					return nil
+				},
+			),
+			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, gomock.Any()).Return(
+				&clusterv1.ManagedClusterList{Items: clusterList.Items[2:]}, nil,
+			),
+			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, gomock.Any()).Return(clusterList, nil),
+			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(gomock.Any(), kernelVersion).Return(mld, nil),
+			mockClusterAPI.EXPECT().SelectedManagedClusters(ctx, gomock.Any()).Return(clusterList, nil),
+			mockClusterAPI.EXPECT().GetModuleLoaderDataForKernel(gomock.Any(), kernelVersion).Return(
+				nil, fmt.Errorf("failed to find mapping: %w", module.ErrNoMatchingKernelMapping),
+			),
+		)
+
+		mcmsData, err := rh.getManagedClusterModulesData(ctx, mcpv)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mcmsData).To(Equal([]managedClusterModulePreflightData{
+			{name: "mcm-with-mapping", clusters: []string{"cluster-1", "cluster-2"}, mld: mld},
+			{name: "mcm-without-mapping", clusters: []string{"cluster-1", "cluster-2"}},
+		}))
+	})
+})
+
+var _ = Describe("ManagedClusterPreflightValidationReconciler_updateStatus", func() {
+	const (
+		artifactsNamespace = "artifacts-namespace"
+		image              = "example.org/repo/image:tag"
+	)
+
+	var (
+		ctrl             *gomock.Controller
+		mockClient       *client.MockClient
+		mockStatusWriter *client.MockStatusWriter
+		mockClusterAPI   *cluster.MockClusterAPI
+		mockMICAPI       *mic.MockMIC
+		rh               managedClusterPreflightValidationReconcilerHelperAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = client.NewMockClient(ctrl)
+		mockStatusWriter = client.NewMockStatusWriter(ctrl)
+		mockClusterAPI = cluster.NewMockClusterAPI(ctrl)
+		mockMICAPI = mic.NewMockMIC(ctrl)
+		rh = newManagedClusterPreflightValidationReconcilerHelper(mockClient, mockClusterAPI, mockMICAPI)
+	})
+
+	ctx := context.Background()
+
+	It("should report the verification status of each ManagedClusterModule", func() {
+		lastTransitionTime := metav1.Unix(1, 0)
+
+		mcpv := &hubv1beta1.ManagedClusterPreflightValidation{
+			ObjectMeta: metav1.ObjectMeta{Name: "mcpv"},
+			Status: hubv1beta1.ManagedClusterPreflightValidationStatus{
+				ManagedClusterModules: []hubv1beta1.ManagedClusterModulePreflightStatus{
+					{
+						Name: "mcm-without-mapping",
+						CRBaseStatus: v1beta2.CRBaseStatus{
+							VerificationStatus: v1beta2.VerificationFailure,
+							StatusReason:       "mapping not found",
+							LastTransitionTime: lastTransitionTime,
+						},
+					},
+					{
+						Name: "deleted-mcm",
+					},
+				},
+			},
+		}
+
+		mcmsData := []managedClusterModulePreflightData{
+			{name: "mcm-without-mapping", clusters: []string{"cluster-1"}},
+			{name: "mcm-without-mic", clusters: []string{"cluster-1"}, mld: &api.ModuleLoaderData{ContainerImage: image}},
+			{name: "mcm-with-image", clusters: []string{"cluster-2"}, mld: &api.ModuleLoaderData{ContainerImage: image}},
+		}
+
+		micObj := &kmmv1beta1.ModuleImagesConfig{}
+
+		mockClusterAPI.EXPECT().GetDefaultArtifactsNamespace().Return(artifactsNamespace).AnyTimes()
+		gomock.InOrder(
+			mockMICAPI.EXPECT().Get(ctx, "mcpv.mcm-without-mic.preflight", artifactsNamespace).Return(nil, errors.New("some error")),
+			mockMICAPI.EXPECT().Get(ctx, "mcpv.mcm-with-image.preflight", artifactsNamespace).Return(micObj, nil),
+			mockMICAPI.EXPECT().GetImageState(micObj, image).Return(kmmv1beta1.ImageExists),
+			mockClient.EXPECT().Status().Return(mockStatusWriter),
+			mockStatusWriter.EXPECT().Patch(ctx, mcpv, gomock.Any()).Return(nil),
+		)
+
+		err := rh.updateStatus(ctx, mcmsData, mcpv)
+		Expect(err).NotTo(HaveOccurred())
+
+		statuses := mcpv.Status.ManagedClusterModules
+		Expect(statuses).To(HaveLen(3))
+
+		Expect(statuses[0].Name).To(Equal("mcm-without-mapping"))
+		Expect(statuses[0].Clusters).To(Equal([]string{"cluster-1"}))
+		Expect(statuses[0].VerificationStatus).To(Equal(v1beta2.VerificationFailure))
+		Expect(statuses[0].VerificationStage).To(Equal(v1beta2.VerificationStageDone))
+		Expect(statuses[0].LastTransitionTime).To(Equal(lastTransitionTime))
+
+		Expect(statuses[1].Name).To(Equal("mcm-without-mic"))
+		Expect(statuses[1].VerificationStatus).To(Equal(v1beta2.VerificationInProgress))
+		Expect(statuses[1].StatusReason).To(Equal("verification is not finished yet"))
+		Expect(statuses[1].VerificationStage).To(Equal(v1beta2.VerificationStageImage))
+
+		Expect(statuses[2].Name).To(Equal("mcm-with-image"))
+		Expect(statuses[2].VerificationStatus).To(Equal(v1beta2.VerificationSuccess))
+		Expect(statuses[2].StatusReason).To(Equal("verified image exists"))
+		Expect(statuses[2].VerificationStage).To(Equal(v1beta2.VerificationStageDone))
+	})
+})
+
+var _ = Describe("ManagedClusterPreflightValidationReconciler_processPreflightValidation", func() {
+	const artifactsNamespace = "artifacts-namespace"
+
+	var (
+		ctrl           *gomock.Controller
+		mockClusterAPI *cluster.MockClusterAPI
+		mockMICAPI     *mic.MockMIC
+		rh             managedClusterPreflightValidationReconcilerHelperAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClusterAPI = cluster.NewMockClusterAPI(ctrl)
+		mockMICAPI = mic.NewMockMIC(ctrl)
+		rh = newManagedClusterPreflightValidationReconcilerHelper(nil, mockClusterAPI, mockMICAPI)
+	})
+
+	ctx := context.Background()
+
+	mld := &api.ModuleLoaderData{
+		ContainerImage:  "example.org/repo/image:tag",
+		KernelVersion:   "5.14.0-1.el9.x86_64",
+		Build:           &kmmv1beta1.Build{},
+		ImagePullPolicy: "Always",
+		Modprobe:        kmmv1beta1.ModprobeSpec{ModuleName: "kmod", DirName: "/opt"},
+	}
+
+	It("should create the MIC of the ManagedClusterModules that are not verified yet", func() {
+		mcpv := &hubv1beta1.ManagedClusterPreflightValidation{
+			ObjectMeta: metav1.ObjectMeta{Name: "mcpv"},
+			Spec:       hubv1beta1.ManagedClusterPreflightValidationSpec{PushBuiltImage: true},
+			Status: hubv1beta1.ManagedClusterPreflightValidationStatus{
+				ManagedClusterModules: []hubv1beta1.ManagedClusterModulePreflightStatus{
+					{
+						Name:         "verified-mcm",
+						CRBaseStatus: v1beta2.CRBaseStatus{VerificationStage: v1beta2.VerificationStageDone},
+					},
+					{
+						Name:         "mcm",
+						CRBaseStatus: v1beta2.CRBaseStatus{VerificationStage: v1beta2.VerificationStageImage},
+					},
+				},
+			},
+		}
+
+		mcmsData := []managedClusterModulePreflightData{
+			{name: "mcm-without-mapping"},
+			{name: "verified-mcm", mld: mld},
+			{name: "mcm", mld: mld},
+		}
+
+		expectedSpec := kmmv1beta1.ModuleImageSpec{
+			Image:         mld.ContainerImage,
+			KernelVersion: mld.KernelVersion,
+			Build:         mld.Build,
+			DirName:       "/opt",
+			ModuleNames:   mld.KernelModuleNames(),
+		}
+
+		mockClusterAPI.EXPECT().GetDefaultArtifactsNamespace().Return(artifactsNamespace)
+		mockMICAPI.EXPECT().CreateOrPatch(ctx, "mcpv.mcm.preflight", artifactsNamespace, []kmmv1beta1.ModuleImageSpec{expectedSpec},
+			mld.ImageRepoSecret, mld.ImagePullPolicy, true, nil, mld.BuildPriority, mld.Tolerations, nil, nil, mcpv).Return(nil)
+
+		err := rh.processPreflightValidation(ctx, mcmsData, mcpv)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return an error if we fail to create a MIC", func() {
+		mcpv := &hubv1beta1.ManagedClusterPreflightValidation{ObjectMeta: metav1.ObjectMeta{Name: "mcpv"}}
+
+		mockClusterAPI.EXPECT().GetDefaultArtifactsNamespace().Return(artifactsNamespace)
+		mockMICAPI.EXPECT().CreateOrPatch(ctx, "mcpv.mcm.preflight", artifactsNamespace, gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), mcpv).Return(errors.New("some error"))
+
+		err := rh.processPreflightValidation(ctx, []managedClusterModulePreflightData{{name: "mcm", mld: mld}}, mcpv)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("preflightMICName", func() {
+	It("should not collide with the MICs of the ManagedClusterModules or of other ManagedClusterPreflightValidations", func() {
+		const mcmName = "mcm"
+
+		name := preflightMICName("mcpv", mcmName)
+		Expect(name).To(Equal("mcpv.mcm.preflight"))
+		Expect(name).NotTo(Equal(mcmName + "-preflight"))
+		Expect(name).NotTo(Equal(preflightMICName("other-mcpv", mcmName)))
+	})
+})
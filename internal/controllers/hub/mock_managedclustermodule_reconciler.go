@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 
 	v1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
+	cluster "github.com/rh-ecosystem-edge/kernel-module-management/internal/cluster"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -69,15 +70,16 @@ func (mr *MockmanagedClusterModuleReconcilerHelperAPIMockRecorder) handleHubNetw
 }
 
 // setMicAsDesired mocks base method.
-func (m *MockmanagedClusterModuleReconcilerHelperAPI) setMicAsDesired(ctx context.Context, mcm *v1beta1.ManagedClusterModule, clusterName string, kernelVersions []string) error {
+func (m *MockmanagedClusterModuleReconcilerHelperAPI) setMicAsDesired(ctx context.Context, mcm *v1beta1.ManagedClusterModule, clusterName string, kernels cluster.NodeKernels) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "setMicAsDesired", ctx, mcm, clusterName, kernelVersions)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "setMicAsDesired", ctx, mcm, clusterName, kernels)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // setMicAsDesired indicates an expected call of setMicAsDesired.
-func (mr *MockmanagedClusterModuleReconcilerHelperAPIMockRecorder) setMicAsDesired(ctx, mcm, clusterName, kernelVersions any) *gomock.Call {
+func (mr *MockmanagedClusterModuleReconcilerHelperAPIMockRecorder) setMicAsDesired(ctx, mcm, clusterName, kernels any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "setMicAsDesired", reflect.TypeOf((*MockmanagedClusterModuleReconcilerHelperAPI)(nil).setMicAsDesired), ctx, mcm, clusterName, kernelVersions)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "setMicAsDesired", reflect.TypeOf((*MockmanagedClusterModuleReconcilerHelperAPI)(nil).setMicAsDesired), ctx, mcm, clusterName, kernels)
 }
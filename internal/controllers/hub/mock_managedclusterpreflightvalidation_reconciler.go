@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: managedclusterpreflightvalidation_reconciler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=managedclusterpreflightvalidation_reconciler.go -package=hub -destination=mock_managedclusterpreflightvalidation_reconciler.go managedClusterPreflightValidationReconcilerHelperAPI
+//
+// Package hub is a generated GoMock package.
+package hub
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockmanagedClusterPreflightValidationReconcilerHelperAPI is a mock of managedClusterPreflightValidationReconcilerHelperAPI interface.
+type MockmanagedClusterPreflightValidationReconcilerHelperAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockmanagedClusterPreflightValidationReconcilerHelperAPIMockRecorder
+}
+
+// MockmanagedClusterPreflightValidationReconcilerHelperAPIMockRecorder is the mock recorder for MockmanagedClusterPreflightValidationReconcilerHelperAPI.
+type MockmanagedClusterPreflightValidationReconcilerHelperAPIMockRecorder struct {
+	mock *MockmanagedClusterPreflightValidationReconcilerHelperAPI
+}
+
+// NewMockmanagedClusterPreflightValidationReconcilerHelperAPI creates a new mock instance.
+func NewMockmanagedClusterPreflightValidationReconcilerHelperAPI(ctrl *gomock.Controller) *MockmanagedClusterPreflightValidationReconcilerHelperAPI {
+	mock := &MockmanagedClusterPreflightValidationReconcilerHelperAPI{ctrl: ctrl}
+	mock.recorder = &MockmanagedClusterPreflightValidationReconcilerHelperAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmanagedClusterPreflightValidationReconcilerHelperAPI) EXPECT() *MockmanagedClusterPreflightValidationReconcilerHelperAPIMockRecorder {
+	return m.recorder
+}
+
+// getManagedClusterModulesData mocks base method.
+func (m *MockmanagedClusterPreflightValidationReconcilerHelperAPI) getManagedClusterModulesData(ctx context.Context, mcpv *v1beta1.ManagedClusterPreflightValidation) ([]managedClusterModulePreflightData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getManagedClusterModulesData", ctx, mcpv)
+	ret0, _ := ret[0].([]managedClusterModulePreflightData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getManagedClusterModulesData indicates an expected call of getManagedClusterModulesData.
+func (mr *MockmanagedClusterPreflightValidationReconcilerHelperAPIMockRecorder) getManagedClusterModulesData(ctx, mcpv any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getManagedClusterModulesData", reflect.TypeOf((*MockmanagedClusterPreflightValidationReconcilerHelperAPI)(nil).getManagedClusterModulesData), ctx, mcpv)
+}
+
+// processPreflightValidation mocks base method.
+func (m *MockmanagedClusterPreflightValidationReconcilerHelperAPI) processPreflightValidation(ctx context.Context, mcmsData []managedClusterModulePreflightData, mcpv *v1beta1.ManagedClusterPreflightValidation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "processPreflightValidation", ctx, mcmsData, mcpv)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// processPreflightValidation indicates an expected call of processPreflightValidation.
+func (mr *MockmanagedClusterPreflightValidationReconcilerHelperAPIMockRecorder) processPreflightValidation(ctx, mcmsData, mcpv any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "processPreflightValidation", reflect.TypeOf((*MockmanagedClusterPreflightValidationReconcilerHelperAPI)(nil).processPreflightValidation), ctx, mcmsData, mcpv)
+}
+
+// updateStatus mocks base method.
+func (m *MockmanagedClusterPreflightValidationReconcilerHelperAPI) updateStatus(ctx context.Context, mcmsData []managedClusterModulePreflightData, mcpv *v1beta1.ManagedClusterPreflightValidation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "updateStatus", ctx, mcmsData, mcpv)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// updateStatus indicates an expected call of updateStatus.
+func (mr *MockmanagedClusterPreflightValidationReconcilerHelperAPIMockRecorder) updateStatus(ctx, mcmsData, mcpv any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "updateStatus", reflect.TypeOf((*MockmanagedClusterPreflightValidationReconcilerHelperAPI)(nil).updateStatus), ctx, mcmsData, mcpv)
+}
@@ -0,0 +1,145 @@
+package hub
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	hubv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/manifestwork"
+)
+
+// rolloutPlan describes which of the selected managed clusters a ManagedClusterModule can be rolled out to.
+type rolloutPlan struct {
+	// allowed are the clusters whose ManifestWork can be created or patched.
+	allowed sets.Set[string]
+	// degraded are the clusters already rolled out to whose ManifestWork is degraded.
+	degraded []string
+	status   *hubv1beta1.RolloutStatus
+}
+
+// newRolloutPlan returns the rollout plan of revision to the clusters, given their current ManifestWorks.
+// The clusters already rolled out to can always be updated, and those of the first stage not yet available can be
+// rolled out to unless the rollout is paused.
+// A degraded ManifestWork pauses the rollout of revision until Spec.Rollout.ResumeRevision is set to it; the pause is
+// recorded in the status rather than in the spec, so that it is not reverted by tools managing the spec.
+func newRolloutPlan(
+	mcm *hubv1beta1.ManagedClusterModule,
+	clusters []clusterv1.ManagedCluster,
+	manifestWorks []workv1.ManifestWork,
+	revision string) *rolloutPlan {
+
+	mws := make(map[string]*workv1.ManifestWork, len(manifestWorks))
+	for i, mw := range manifestWorks {
+		mws[mw.Namespace] = &manifestWorks[i]
+	}
+
+	isUpdated := func(clusterName string) (*workv1.ManifestWork, bool) {
+		mw, ok := mws[clusterName]
+		return mw, ok && manifestwork.GetRevision(mw) == revision
+	}
+
+	plan := &rolloutPlan{allowed: sets.New[string]()}
+
+	for _, cluster := range clusters {
+		mw, updated := isUpdated(cluster.Name)
+		if !updated {
+			continue
+		}
+
+		plan.allowed.Insert(cluster.Name)
+		if meta.IsStatusConditionTrue(mw.Status.Conditions, workv1.WorkDegraded) {
+			plan.degraded = append(plan.degraded, cluster.Name)
+		}
+	}
+	slices.Sort(plan.degraded)
+
+	loadsKernelModule := mcm.Spec.ModuleSpec.ModuleLoader != nil
+	stages := rolloutStages(mcm.Spec.Rollout, clusters)
+
+	stage := 0
+	for ; stage < len(stages); stage++ {
+		available := slices.IndexFunc(stages[stage], func(clusterName string) bool {
+			mw, updated := isUpdated(clusterName)
+			return !updated || !manifestwork.IsModuleAvailable(mw, loadsKernelModule)
+		}) == -1
+		if !available {
+			break
+		}
+	}
+
+	plan.status = &hubv1beta1.RolloutStatus{
+		Revision:        revision,
+		Stage:           int32(stage),
+		Stages:          int32(len(stages)),
+		UpdatedClusters: int32(plan.allowed.Len()),
+	}
+
+	autoPaused := false
+	if mcm.Spec.Rollout.ResumeRevision != revision {
+		wasPaused := mcm.Status.Rollout != nil && mcm.Status.Rollout.PausedRevision == revision
+		autoPaused = len(plan.degraded) > 0 || wasPaused
+	}
+	if autoPaused && stage < len(stages) {
+		plan.status.PausedRevision = revision
+	}
+
+	switch {
+	case stage == len(stages):
+		plan.status.Message = "the rollout is complete"
+	case mcm.Spec.Rollout.Paused:
+		plan.status.Message = "the rollout is paused"
+	case autoPaused && len(plan.degraded) > 0:
+		plan.status.Message = fmt.Sprintf("the rollout is paused: ManifestWorks are degraded on clusters: %s; "+
+			"set .spec.rollout.resumeRevision to %s to resume it", strings.Join(plan.degraded, ", "), revision)
+	case autoPaused:
+		plan.status.Message = fmt.Sprintf("the rollout is paused: ManifestWorks were degraded; "+
+			"set .spec.rollout.resumeRevision to %s to resume it", revision)
+	default:
+		plan.allowed.Insert(stages[stage]...)
+		plan.status.Message = fmt.Sprintf("rolling out to clusters: %s", strings.Join(stages[stage], ", "))
+	}
+
+	return plan
+}
+
+// rolloutStages splits the clusters into the ordered stages of the rollout: each group, then each batch of a group if
+// a batch size is set, is a stage.
+func rolloutStages(strategy *hubv1beta1.RolloutStrategy, clusters []clusterv1.ManagedCluster) [][]string {
+	// the clusters that no group selects are part of an implicit last group
+	groups := make([][]string, len(strategy.Groups)+1)
+
+	for _, cluster := range clusters {
+		i := slices.IndexFunc(strategy.Groups, func(group hubv1beta1.RolloutGroup) bool {
+			return labels.SelectorFromSet(group.Selector).Matches(labels.Set(cluster.Labels))
+		})
+		if i == -1 {
+			i = len(strategy.Groups)
+		}
+
+		groups[i] = append(groups[i], cluster.Name)
+	}
+
+	stages := make([][]string, 0, len(groups))
+
+	for _, group := range groups {
+		slices.Sort(group)
+
+		batchSize := int(strategy.BatchSize)
+		if batchSize <= 0 {
+			batchSize = len(group)
+		}
+
+		for start := 0; start < len(group); start += batchSize {
+			stages = append(stages, group[start:min(start+batchSize, len(group))])
+		}
+	}
+
+	return stages
+}
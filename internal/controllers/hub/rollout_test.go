@@ -0,0 +1,244 @@
+package hub
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	hubv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+)
+
+var _ = Describe("rolloutStages", func() {
+	clusters := []clusterv1.ManagedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "prod-2", Labels: map[string]string{"env": "prod"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "canary", Labels: map[string]string{"env": "prod", "canary": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "prod-1", Labels: map[string]string{"env": "prod"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "prod-3", Labels: map[string]string{"env": "prod"}}},
+	}
+
+	It("should return a single stage if there are neither groups nor a batch size", func() {
+		Expect(
+			rolloutStages(&hubv1beta1.RolloutStrategy{}, clusters),
+		).To(
+			Equal([][]string{{"canary", "other", "prod-1", "prod-2", "prod-3"}}),
+		)
+	})
+
+	It("should split the clusters into batches", func() {
+		Expect(
+			rolloutStages(&hubv1beta1.RolloutStrategy{BatchSize: 2}, clusters),
+		).To(
+			Equal([][]string{{"canary", "other"}, {"prod-1", "prod-2"}, {"prod-3"}}),
+		)
+	})
+
+	It("should order the groups and roll out to the clusters no group selects last", func() {
+		strategy := &hubv1beta1.RolloutStrategy{
+			Groups: []hubv1beta1.RolloutGroup{
+				{Name: "canary", Selector: map[string]string{"canary": "true"}},
+				{Name: "prod", Selector: map[string]string{"env": "prod"}},
+			},
+			BatchSize: 2,
+		}
+
+		Expect(
+			rolloutStages(strategy, clusters),
+		).To(
+			Equal([][]string{{"canary"}, {"prod-1", "prod-2"}, {"prod-3"}, {"other"}}),
+		)
+	})
+})
+
+var _ = Describe("newRolloutPlan", func() {
+	const revision = "revision"
+
+	clusters := []clusterv1.ManagedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-3"}},
+	}
+
+	// the Module on the Spoke cluster reports the revision of the ManifestWork
+	manifestWork := func(clusterName, revision string, conditions ...metav1.Condition) workv1.ManifestWork {
+		return workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   clusterName,
+				Annotations: map[string]string{constants.ManagedClusterModuleRevisionAnnotation: revision},
+			},
+			Status: workv1.ManifestWorkStatus{
+				Conditions: conditions,
+				ResourceStatus: workv1.ManifestResourceStatus{
+					Manifests: []workv1.ManifestCondition{
+						{
+							ResourceMeta: workv1.ManifestResourceMeta{Group: kmmv1beta1.GroupVersion.Group, Resource: "modules"},
+							StatusFeedbacks: workv1.StatusFeedbackResult{
+								Values: []workv1.FeedbackValue{
+									{
+										Name:  "managedClusterModuleRevision",
+										Value: workv1.FieldValue{Type: workv1.String, String: &revision},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	available := metav1.Condition{Type: workv1.WorkAvailable, Status: metav1.ConditionTrue}
+	degraded := metav1.Condition{Type: workv1.WorkDegraded, Status: metav1.ConditionTrue}
+
+	var mcm *hubv1beta1.ManagedClusterModule
+
+	BeforeEach(func() {
+		mcm = &hubv1beta1.ManagedClusterModule{
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				Rollout: &hubv1beta1.RolloutStrategy{BatchSize: 1},
+			},
+		}
+	})
+
+	It("should roll out to the first stage that is not available yet", func() {
+		mws := []workv1.ManifestWork{
+			manifestWork("cluster-1", revision, available),
+			manifestWork("cluster-2", "old-revision", available),
+			manifestWork("cluster-3", "old-revision", available),
+		}
+
+		plan := newRolloutPlan(mcm, clusters, mws, revision)
+
+		Expect(plan.allowed.UnsortedList()).To(ConsistOf("cluster-1", "cluster-2"))
+		Expect(plan.degraded).To(BeEmpty())
+		Expect(plan.status).To(Equal(&hubv1beta1.RolloutStatus{
+			Revision:        revision,
+			Stage:           1,
+			Stages:          3,
+			UpdatedClusters: 1,
+			Message:         "rolling out to clusters: cluster-2",
+		}))
+	})
+
+	It("should not move to the next stage until the Module is available", func() {
+		mws := []workv1.ManifestWork{
+			manifestWork("cluster-1", revision),
+		}
+
+		plan := newRolloutPlan(mcm, clusters, mws, revision)
+
+		Expect(plan.allowed.UnsortedList()).To(ConsistOf("cluster-1"))
+		Expect(plan.status.Stage).To(BeEquivalentTo(0))
+	})
+
+	It("should not move to the next stage until the Module runs the revision on the Spoke cluster", func() {
+		mw := manifestWork("cluster-1", revision, available)
+		mw.Status.ResourceStatus.Manifests[0].StatusFeedbacks.Values[0].Value.String = ptr.To("old-revision")
+
+		plan := newRolloutPlan(mcm, clusters, []workv1.ManifestWork{mw}, revision)
+
+		Expect(plan.allowed.UnsortedList()).To(ConsistOf("cluster-1"))
+		Expect(plan.status.Stage).To(BeEquivalentTo(0))
+	})
+
+	It("should not move to the next stage until the availability of the latest ManifestWork is observed", func() {
+		mw := manifestWork("cluster-1", revision, available)
+		mw.Generation = 2
+
+		plan := newRolloutPlan(mcm, clusters, []workv1.ManifestWork{mw}, revision)
+
+		Expect(plan.allowed.UnsortedList()).To(ConsistOf("cluster-1"))
+		Expect(plan.status.Stage).To(BeEquivalentTo(0))
+	})
+
+	It("should not roll out to further clusters if the rollout is paused", func() {
+		mcm.Spec.Rollout.Paused = true
+
+		mws := []workv1.ManifestWork{
+			manifestWork("cluster-1", revision, available),
+		}
+
+		plan := newRolloutPlan(mcm, clusters, mws, revision)
+
+		Expect(plan.allowed.UnsortedList()).To(ConsistOf("cluster-1"))
+		Expect(plan.status.Message).To(Equal("the rollout is paused"))
+	})
+
+	It("should not roll out to further clusters if a ManifestWork is degraded", func() {
+		mws := []workv1.ManifestWork{
+			manifestWork("cluster-1", revision, available),
+			manifestWork("cluster-2", revision, degraded),
+			manifestWork("cluster-3", "old-revision", degraded),
+		}
+
+		plan := newRolloutPlan(mcm, clusters, mws, revision)
+
+		Expect(plan.allowed.UnsortedList()).To(ConsistOf("cluster-1", "cluster-2"))
+		Expect(plan.degraded).To(Equal([]string{"cluster-2"}))
+		Expect(plan.status.PausedRevision).To(Equal(revision))
+		Expect(plan.status.Message).To(Equal("the rollout is paused: ManifestWorks are degraded on clusters: cluster-2; " +
+			"set .spec.rollout.resumeRevision to revision to resume it"))
+	})
+
+	It("should keep the rollout paused once the degraded ManifestWorks recover", func() {
+		mcm.Status.Rollout = &hubv1beta1.RolloutStatus{Revision: revision, PausedRevision: revision}
+
+		mws := []workv1.ManifestWork{
+			manifestWork("cluster-1", revision, available),
+		}
+
+		plan := newRolloutPlan(mcm, clusters, mws, revision)
+
+		Expect(plan.allowed.UnsortedList()).To(ConsistOf("cluster-1"))
+		Expect(plan.status.PausedRevision).To(Equal(revision))
+		Expect(plan.status.Message).To(Equal("the rollout is paused: ManifestWorks were degraded; " +
+			"set .spec.rollout.resumeRevision to revision to resume it"))
+	})
+
+	It("should resume the rollout of the paused revision", func() {
+		mcm.Spec.Rollout.ResumeRevision = revision
+		mcm.Status.Rollout = &hubv1beta1.RolloutStatus{Revision: revision, PausedRevision: revision}
+
+		mws := []workv1.ManifestWork{
+			manifestWork("cluster-1", revision, available, degraded),
+		}
+
+		plan := newRolloutPlan(mcm, clusters, mws, revision)
+
+		Expect(plan.allowed.UnsortedList()).To(ConsistOf("cluster-1", "cluster-2"))
+		Expect(plan.status.PausedRevision).To(BeEmpty())
+		Expect(plan.status.Message).To(Equal("rolling out to clusters: cluster-2"))
+	})
+
+	It("should not keep the pause of a previous revision", func() {
+		mcm.Status.Rollout = &hubv1beta1.RolloutStatus{Revision: "old-revision", PausedRevision: "old-revision"}
+
+		mws := []workv1.ManifestWork{
+			manifestWork("cluster-1", revision, available),
+		}
+
+		plan := newRolloutPlan(mcm, clusters, mws, revision)
+
+		Expect(plan.allowed.UnsortedList()).To(ConsistOf("cluster-1", "cluster-2"))
+		Expect(plan.status.PausedRevision).To(BeEmpty())
+	})
+
+	It("should report a complete rollout", func() {
+		mws := []workv1.ManifestWork{
+			manifestWork("cluster-1", revision, available),
+			manifestWork("cluster-2", revision, available),
+			manifestWork("cluster-3", revision, available),
+		}
+
+		plan := newRolloutPlan(mcm, clusters, mws, revision)
+
+		Expect(plan.allowed.UnsortedList()).To(ConsistOf("cluster-1", "cluster-2", "cluster-3"))
+		Expect(plan.status.Stage).To(BeEquivalentTo(3))
+		Expect(plan.status.Message).To(Equal("the rollout is complete"))
+	})
+})
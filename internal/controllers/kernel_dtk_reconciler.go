@@ -16,6 +16,7 @@ import (
 )
 
 //+kubebuilder:rbac:groups="core",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=kernelbuildercatalogs,verbs=get;list;watch
 
 const (
 	KernelDTKReconcilerName = "KernelDTK"
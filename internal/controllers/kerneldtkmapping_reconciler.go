@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/filter"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=kerneldtkmappings,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=kerneldtkmappings/status,verbs=get;patch;update
+
+const (
+	KernelDTKMappingReconcilerName = "KernelDTKMapping"
+
+	// kernelDTKRetention is the period for which a kernel that no node runs anymore is kept in the KernelDTKMapping
+	kernelDTKRetention = 30 * 24 * time.Hour
+)
+
+// KernelDTKMappingReconciler persists the kernel to DTK mapping discovered from the nodes and the driver-toolkit
+// ImageStream in the status of the KernelDTKMapping, so that it survives restarts of the operator. The kernels that no
+// node runs anymore are removed after a retention period, unless they have an override.
+type KernelDTKMappingReconciler struct {
+	client client.Client
+	dtkNSN types.NamespacedName
+}
+
+func NewKernelDTKMappingReconciler(client client.Client, dtkNSN types.NamespacedName) *KernelDTKMappingReconciler {
+	return &KernelDTKMappingReconciler{
+		client: client,
+		dtkNSN: dtkNSN,
+	}
+}
+
+func (r *KernelDTKMappingReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	mapping := kmmv1beta1.KernelDTKMapping{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: constants.KernelDTKMappingName}, &mapping); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("could not get KernelDTKMapping %s: %v", constants.KernelDTKMappingName, err)
+		}
+		mapping = kmmv1beta1.KernelDTKMapping{
+			ObjectMeta: metav1.ObjectMeta{Name: constants.KernelDTKMappingName},
+		}
+		if err = r.client.Create(ctx, &mapping); err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not create KernelDTKMapping %s: %v", constants.KernelDTKMappingName, err)
+		}
+		logger.Info("Created the KernelDTKMapping", "name", constants.KernelDTKMappingName)
+	}
+
+	discovered, err := r.discoverKernels(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the live discovery takes precedence over the persisted entries
+	kernels := make(map[string]kmmv1beta1.KernelDTKStatus, len(mapping.Status.Kernels)+len(discovered))
+	for _, k := range discovered {
+		kernels[k.KernelVersion] = k
+	}
+
+	overridden := sets.New[string]()
+	for _, o := range mapping.Spec.Overrides {
+		overridden.Insert(o.KernelVersion)
+	}
+
+	now := time.Now()
+	res := ctrl.Result{}
+	for _, k := range mapping.Status.Kernels {
+		if _, ok := kernels[k.KernelVersion]; ok {
+			continue
+		}
+		if k.UnusedSince == nil {
+			k.UnusedSince = &metav1.Time{Time: now}
+		}
+		if !overridden.Has(k.KernelVersion) {
+			remaining := k.UnusedSince.Add(kernelDTKRetention).Sub(now)
+			if remaining <= 0 {
+				logger.Info("Removing a kernel that no node runs anymore", "kernel", k.KernelVersion,
+					"unusedSince", k.UnusedSince)
+				continue
+			}
+			if res.RequeueAfter == 0 || remaining < res.RequeueAfter {
+				res.RequeueAfter = remaining
+			}
+		}
+		kernels[k.KernelVersion] = k
+	}
+
+	status := kmmv1beta1.KernelDTKMappingStatus{}
+	for _, k := range kernels {
+		status.Kernels = append(status.Kernels, k)
+	}
+	sort.Slice(status.Kernels, func(i, j int) bool {
+		return status.Kernels[i].KernelVersion < status.Kernels[j].KernelVersion
+	})
+
+	if equality.Semantic.DeepEqual(mapping.Status, status) {
+		return res, nil
+	}
+
+	mappingCopy := mapping.DeepCopy()
+	mapping.Status = status
+	if err = r.client.Status().Patch(ctx, &mapping, client.MergeFrom(mappingCopy)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not patch the status of KernelDTKMapping %s: %v", mapping.Name, err)
+	}
+	logger.Info("Updated the KernelDTKMapping", "kernels", len(status.Kernels))
+
+	return res, nil
+}
+
+// discoverKernels returns the kernels run by the nodes whose OS image has a tag in the driver-toolkit ImageStream.
+func (r *KernelDTKMappingReconciler) discoverKernels(ctx context.Context) ([]kmmv1beta1.KernelDTKStatus, error) {
+	is := imagev1.ImageStream{}
+	if err := r.client.Get(ctx, r.dtkNSN, &is); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not get imagestream %v: %v", r.dtkNSN, err)
+	}
+
+	osToDTK := make(map[string]string, len(is.Spec.Tags))
+	for _, t := range is.Spec.Tags {
+		if t.Name != "latest" && t.From != nil {
+			osToDTK[t.Name] = t.From.Name
+		}
+	}
+
+	nodeList := v1.NodeList{}
+	if err := r.client.List(ctx, &nodeList); err != nil {
+		return nil, fmt.Errorf("could not list nodes: %v", err)
+	}
+
+	kernels := make([]kmmv1beta1.KernelDTKStatus, 0, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		osImageVersion := osVersionRegexp.FindString(node.Status.NodeInfo.OSImage)
+		dtkImage, ok := osToDTK[osImageVersion]
+		if osImageVersion == "" || !ok {
+			continue
+		}
+		kernels = append(kernels, kmmv1beta1.KernelDTKStatus{
+			KernelVersion:  strings.TrimSuffix(node.Status.NodeInfo.KernelVersion, "+"),
+			OSImageVersion: osImageVersion,
+			DTKImage:       dtkImage,
+		})
+	}
+
+	return kernels, nil
+}
+
+func (r *KernelDTKMappingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	enqueueMapping := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+		return []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Name: constants.KernelDTKMappingName}},
+		}
+	})
+
+	return ctrl.
+		NewControllerManagedBy(mgr).
+		Named(KernelDTKMappingReconcilerName).
+		For(
+			&kmmv1beta1.KernelDTKMapping{},
+			builder.WithPredicates(
+				filter.MatchesNamespacedNamePredicate(types.NamespacedName{Name: constants.KernelDTKMappingName}),
+			),
+		).
+		Watches(
+			&v1.Node{},
+			enqueueMapping,
+			builder.WithPredicates(filter.KernelDTKReconcilerPredicate()),
+		).
+		Watches(
+			&imagev1.ImageStream{},
+			enqueueMapping,
+			builder.WithPredicates(filter.MatchesNamespacedNamePredicate(r.dtkNSN)),
+		).
+		Complete(r)
+}
@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	imagev1 "github.com/openshift/api/image/v1"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("KernelDTKMappingReconciler_Reconcile", func() {
+	const (
+		osVersion = "411.86.202210072320-0"
+		dtkImage  = "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:111"
+	)
+
+	var (
+		gCtrl        *gomock.Controller
+		clnt         *client.MockClient
+		statusWriter *client.MockStatusWriter
+		r            *KernelDTKMappingReconciler
+	)
+
+	dtkNSN := types.NamespacedName{Namespace: constants.DTKImageStreamNamespace, Name: "driver-toolkit"}
+
+	BeforeEach(func() {
+		gCtrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(gCtrl)
+		statusWriter = client.NewMockStatusWriter(gCtrl)
+		r = NewKernelDTKMappingReconciler(clnt, dtkNSN)
+	})
+
+	ctx := context.Background()
+	mappingNSN := types.NamespacedName{Name: constants.KernelDTKMappingName}
+	req := ctrl.Request{NamespacedName: mappingNSN}
+
+	expectMapping := func(status kmmv1beta1.KernelDTKMappingStatus) *gomock.Call {
+		return clnt.EXPECT().Get(ctx, mappingNSN, &kmmv1beta1.KernelDTKMapping{}).DoAndReturn(
+			func(_ interface{}, _ interface{}, m *kmmv1beta1.KernelDTKMapping, _ ...ctrlclient.GetOption) error {
+				m.Name = constants.KernelDTKMappingName
+				m.Status = status
+				return nil
+			},
+		)
+	}
+
+	expectImageStream := func() *gomock.Call {
+		return clnt.EXPECT().Get(ctx, dtkNSN, &imagev1.ImageStream{}).DoAndReturn(
+			func(_ interface{}, _ interface{}, is *imagev1.ImageStream, _ ...ctrlclient.GetOption) error {
+				is.Spec.Tags = []imagev1.TagReference{
+					{Name: "latest", From: &v1.ObjectReference{Name: "latest-image"}},
+					{Name: osVersion, From: &v1.ObjectReference{Name: dtkImage}},
+				}
+				return nil
+			},
+		)
+	}
+
+	expectNodes := func() *gomock.Call {
+		return clnt.EXPECT().List(ctx, &v1.NodeList{}).DoAndReturn(
+			func(_ interface{}, list *v1.NodeList, _ ...ctrlclient.ListOption) error {
+				list.Items = []v1.Node{
+					{
+						Status: v1.NodeStatus{
+							NodeInfo: v1.NodeSystemInfo{
+								KernelVersion: "5.14.0-284.el9.x86_64+",
+								OSImage:       "Red Hat Enterprise Linux CoreOS " + osVersion + " (Plow)",
+							},
+						},
+					},
+					{
+						Status: v1.NodeStatus{
+							NodeInfo: v1.NodeSystemInfo{KernelVersion: "6.8.0-45-generic", OSImage: "Ubuntu 24.04.1 LTS"},
+						},
+					},
+				}
+				return nil
+			},
+		)
+	}
+
+	discovered := kmmv1beta1.KernelDTKStatus{
+		KernelVersion:  "5.14.0-284.el9.x86_64",
+		OSImageVersion: osVersion,
+		DTKImage:       dtkImage,
+	}
+
+	It("should return an error if the KernelDTKMapping could not be read", func() {
+		clnt.EXPECT().Get(ctx, mappingNSN, gomock.Any()).Return(errors.New("random error"))
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should create the KernelDTKMapping and persist the discovered kernels", func() {
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, mappingNSN, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "default")),
+			clnt.EXPECT().Create(ctx, &kmmv1beta1.KernelDTKMapping{
+				ObjectMeta: metav1.ObjectMeta{Name: constants.KernelDTKMappingName},
+			}),
+			expectImageStream(),
+			expectNodes(),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, m *kmmv1beta1.KernelDTKMapping, _ ctrlclient.Patch, _ ...ctrlclient.SubResourcePatchOption) error {
+					Expect(m.Status.Kernels).To(Equal([]kmmv1beta1.KernelDTKStatus{discovered}))
+					return nil
+				},
+			),
+		)
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	previous := kmmv1beta1.KernelDTKStatus{
+		KernelVersion:  "5.14.0-162.el9.x86_64",
+		OSImageVersion: "412.86.202301010000-0",
+		DTKImage:       "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:000",
+	}
+
+	expectStatusPatch := func(check func(kernels []kmmv1beta1.KernelDTKStatus)) *gomock.Call {
+		return statusWriter.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, m *kmmv1beta1.KernelDTKMapping, _ ctrlclient.Patch, _ ...ctrlclient.SubResourcePatchOption) error {
+				check(m.Status.Kernels)
+				return nil
+			},
+		)
+	}
+
+	It("should keep the kernels that are not running anymore", func() {
+		gomock.InOrder(
+			expectMapping(kmmv1beta1.KernelDTKMappingStatus{Kernels: []kmmv1beta1.KernelDTKStatus{previous}}),
+			expectImageStream(),
+			expectNodes(),
+			clnt.EXPECT().Status().Return(statusWriter),
+			expectStatusPatch(func(kernels []kmmv1beta1.KernelDTKStatus) {
+				Expect(kernels).To(HaveLen(2))
+				Expect(kernels[0].KernelVersion).To(Equal(previous.KernelVersion))
+				Expect(kernels[0].UnusedSince).NotTo(BeNil())
+				Expect(kernels[1]).To(Equal(discovered))
+			}),
+		)
+
+		res, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(BeNumerically("~", kernelDTKRetention, time.Minute))
+	})
+
+	It("should remove the kernels that no node ran for the retention period", func() {
+		unused := previous
+		unused.UnusedSince = &metav1.Time{Time: time.Now().Add(-kernelDTKRetention - time.Hour)}
+
+		gomock.InOrder(
+			expectMapping(kmmv1beta1.KernelDTKMappingStatus{Kernels: []kmmv1beta1.KernelDTKStatus{unused, discovered}}),
+			expectImageStream(),
+			expectNodes(),
+			clnt.EXPECT().Status().Return(statusWriter),
+			expectStatusPatch(func(kernels []kmmv1beta1.KernelDTKStatus) {
+				Expect(kernels).To(Equal([]kmmv1beta1.KernelDTKStatus{discovered}))
+			}),
+		)
+
+		res, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(BeZero())
+	})
+
+	It("should keep the kernels that have an override after the retention period", func() {
+		unused := previous
+		unused.UnusedSince = &metav1.Time{Time: time.Now().Add(-kernelDTKRetention - time.Hour)}
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, mappingNSN, &kmmv1beta1.KernelDTKMapping{}).DoAndReturn(
+				func(_ interface{}, _ interface{}, m *kmmv1beta1.KernelDTKMapping, _ ...ctrlclient.GetOption) error {
+					m.Name = constants.KernelDTKMappingName
+					m.Spec.Overrides = []kmmv1beta1.KernelDTKOverride{
+						{KernelVersion: previous.KernelVersion, DTKImage: "some-image"},
+					}
+					m.Status.Kernels = []kmmv1beta1.KernelDTKStatus{unused, discovered}
+					return nil
+				},
+			),
+			expectImageStream(),
+			expectNodes(),
+		)
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should overwrite the persisted kernels with the discovered ones", func() {
+		outdated := discovered
+		outdated.DTKImage = "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:000"
+		outdated.UnusedSince = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+
+		gomock.InOrder(
+			expectMapping(kmmv1beta1.KernelDTKMappingStatus{Kernels: []kmmv1beta1.KernelDTKStatus{outdated}}),
+			expectImageStream(),
+			expectNodes(),
+			clnt.EXPECT().Status().Return(statusWriter),
+			expectStatusPatch(func(kernels []kmmv1beta1.KernelDTKStatus) {
+				Expect(kernels).To(Equal([]kmmv1beta1.KernelDTKStatus{discovered}))
+			}),
+		)
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not patch the status if nothing changed", func() {
+		gomock.InOrder(
+			expectMapping(kmmv1beta1.KernelDTKMappingStatus{Kernels: []kmmv1beta1.KernelDTKStatus{discovered}}),
+			expectImageStream(),
+			expectNodes(),
+		)
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not discover any kernel if the ImageStream does not exist", func() {
+		gomock.InOrder(
+			expectMapping(kmmv1beta1.KernelDTKMappingStatus{}),
+			clnt.EXPECT().Get(ctx, dtkNSN, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, dtkNSN.Name)),
+		)
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -20,35 +20,52 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	buildv1 "github.com/openshift/api/build/v1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildercatalog"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildsign"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/kernel"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mbsc"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/registry"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-const MBSCReconcilerName = "MBSCReconciler"
+const (
+	MBSCReconcilerName = "MBSCReconciler"
+
+	queuedImagesRequeueInterval = 30 * time.Second
+
+	defaultRetryBackoff    = 30 * time.Second
+	defaultRetryMaxBackoff = 10 * time.Minute
+)
 
 // mbscReconciler reconciles a ModuleBuldSignConfig object
 type mbscReconciler struct {
-	reconHelperAPI mbscReconcilerHelperAPI
+	reconHelperAPI           mbscReconcilerHelperAPI
+	buildInputsCheckInterval time.Duration
 }
 
 func NewMBSCReconciler(
 	client client.Client,
 	buildSignAPI buildsign.Manager,
 	mbscAPI mbsc.MBSC,
+	buildInputsCheckInterval time.Duration,
 ) *mbscReconciler {
-	reconHelperAPI := newMBSCReconcilerHelper(client, buildSignAPI, mbscAPI)
+	reconHelperAPI := newMBSCReconcilerHelper(client, buildSignAPI, mbscAPI, buildInputsCheckInterval)
 	return &mbscReconciler{
-		reconHelperAPI: reconHelperAPI,
+		reconHelperAPI:           reconHelperAPI,
+		buildInputsCheckInterval: buildInputsCheckInterval,
 	}
 }
 
@@ -57,12 +74,38 @@ func (r *mbscReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&kmmv1beta1.ModuleBuildSignConfig{}).
 		Owns(&buildv1.Build{}).
+		Watches(
+			&kmmv1beta1.SigningKey{},
+			handler.EnqueueRequestsFromMapFunc(r.findMBSCsForSigningKey(mgr.GetClient())),
+		).
 		Named(MBSCReconcilerName).
 		Complete(
 			reconcile.AsReconciler[*kmmv1beta1.ModuleBuildSignConfig](mgr.GetClient(), r),
 		)
 }
 
+// findMBSCsForSigningKey enqueues the MBSCs that sign images with the SigningKey, so that they are signed again
+// when it is rotated.
+func (r *mbscReconciler) findMBSCsForSigningKey(clnt client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		mbscList := kmmv1beta1.ModuleBuildSignConfigList{}
+		if err := clnt.List(ctx, &mbscList, client.InNamespace(obj.GetNamespace())); err != nil {
+			log.FromContext(ctx).Error(err, "could not list MBSCs", "namespace", obj.GetNamespace())
+			return nil
+		}
+
+		reqs := make([]reconcile.Request, 0, len(mbscList.Items))
+		for _, mbscObj := range mbscList.Items {
+			if slices.ContainsFunc(mbscObj.Spec.Images, func(imageSpec kmmv1beta1.ModuleBuildSignSpec) bool {
+				return imageSpec.Sign != nil && imageSpec.Sign.SigningKey != nil && imageSpec.Sign.SigningKey.Name == obj.GetName()
+			}) {
+				reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&mbscObj)})
+			}
+		}
+		return reqs
+	}
+}
+
 func (r *mbscReconciler) Reconcile(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig) (ctrl.Result, error) {
 	res := ctrl.Result{}
 
@@ -73,11 +116,28 @@ func (r *mbscReconciler) Reconcile(ctx context.Context, mbscObj *kmmv1beta1.Modu
 		return res, nil
 	}
 
+	// the digests of the base images are resolved when checking the build inputs and when syncing the builds
+	ctx = registry.WithDigestCache(ctx)
+	// the resources created while processing the images are not in the cache yet, but count towards the limits
+	ctx = buildsign.WithCreatedResources(ctx)
+	// the MBSC is named after the MIC whose images it builds and signs
+	ctx = buildercatalog.WithModuleImagesConfig(ctx, client.ObjectKeyFromObject(mbscObj))
+
 	err := r.reconHelperAPI.updateStatus(ctx, mbscObj)
 	if err != nil {
 		return res, fmt.Errorf("failed to update MSBC %s status based on the result of the builds: %v", mbscObj.Name, err)
 	}
 
+	err = r.reconHelperAPI.checkBuildInputs(ctx, mbscObj)
+	if err != nil {
+		return res, fmt.Errorf("failed to check the build inputs of MSBC %s: %v", mbscObj.Name, err)
+	}
+
+	err = r.reconHelperAPI.checkSigningKeys(ctx, mbscObj)
+	if err != nil {
+		return res, fmt.Errorf("failed to check the signing keys of MSBC %s: %v", mbscObj.Name, err)
+	}
+
 	err = r.reconHelperAPI.processImagesSpecs(ctx, mbscObj)
 	if err != nil {
 		return res, fmt.Errorf("failed to process images of MSBC %s: %v", mbscObj.Name, err)
@@ -89,6 +149,22 @@ func (r *mbscReconciler) Reconcile(ctx context.Context, mbscObj *kmmv1beta1.Modu
 		return res, fmt.Errorf("failed to run garbage collector for MBSC %s: %v", mbscObj.Name, err)
 	}
 
+	// base images and build secrets can change without any event on the MBSC; check them again periodically
+	res.RequeueAfter = nextBuildInputsCheckDelay(mbscObj, r.buildInputsCheckInterval, time.Now())
+	if res.RequeueAfter == 0 {
+		res.RequeueAfter = r.buildInputsCheckInterval
+	}
+
+	// queued images are started once other builds and signs are done, which does not trigger any event on this MBSC
+	if hasQueuedImages(mbscObj) && (res.RequeueAfter == 0 || res.RequeueAfter > queuedImagesRequeueInterval) {
+		res.RequeueAfter = queuedImagesRequeueInterval
+	}
+
+	// failed images are retried once their backoff expires
+	if delay, ok := nextRetryDelay(mbscObj, time.Now()); ok && (res.RequeueAfter == 0 || res.RequeueAfter > delay) {
+		res.RequeueAfter = delay
+	}
+
 	return res, nil
 }
 
@@ -96,21 +172,30 @@ func (r *mbscReconciler) Reconcile(ctx context.Context, mbscObj *kmmv1beta1.Modu
 
 type mbscReconcilerHelperAPI interface {
 	updateStatus(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig) error
+	checkBuildInputs(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig) error
+	checkSigningKeys(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig) error
 	processImagesSpecs(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig) error
 	garbageCollect(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig) error
 }
 
 type mbscReconcilerHelper struct {
-	client       client.Client
-	buildSignAPI buildsign.Manager
-	mbscAPI      mbsc.MBSC
+	client                   client.Client
+	buildSignAPI             buildsign.Manager
+	mbscAPI                  mbsc.MBSC
+	buildInputsCheckInterval time.Duration
 }
 
-func newMBSCReconcilerHelper(client client.Client, buildSignAPI buildsign.Manager, mbscAPI mbsc.MBSC) mbscReconcilerHelperAPI {
+func newMBSCReconcilerHelper(
+	client client.Client,
+	buildSignAPI buildsign.Manager,
+	mbscAPI mbsc.MBSC,
+	buildInputsCheckInterval time.Duration,
+) mbscReconcilerHelperAPI {
 	return &mbscReconcilerHelper{
-		client:       client,
-		buildSignAPI: buildSignAPI,
-		mbscAPI:      mbscAPI,
+		client:                   client,
+		buildSignAPI:             buildSignAPI,
+		mbscAPI:                  mbscAPI,
+		buildInputsCheckInterval: buildInputsCheckInterval,
 	}
 }
 
@@ -125,7 +210,17 @@ func (mrh *mbscReconcilerHelper) updateStatus(ctx context.Context, mbscObj *kmmv
 			errs = append(errs, err)
 			continue
 		}
-		mrh.mbscAPI.SetImageStatus(mbscObj, imageSpec.Image, imageSpec.Action, status)
+		if status == kmmv1beta1.ActionFailure {
+			errs = append(errs, mrh.recordFailure(ctx, mbscObj, &imageSpec))
+		} else {
+			mrh.mbscAPI.SetImageStatus(mbscObj, imageSpec.Image, imageSpec.Action, status)
+		}
+		if status == kmmv1beta1.ActionSuccess || status == kmmv1beta1.ActionFailure {
+			errs = append(errs, mrh.recordLogConfigMap(ctx, mbscObj, &imageSpec))
+		}
+		if status == kmmv1beta1.ActionSuccess && imageSpec.Action == kmmv1beta1.SignImage {
+			errs = append(errs, mrh.recordSignedFiles(ctx, mbscObj, &imageSpec))
+		}
 	}
 
 	err := mrh.client.Status().Patch(ctx, mbscObj, patchFrom)
@@ -133,23 +228,232 @@ func (mrh *mbscReconcilerHelper) updateStatus(ctx context.Context, mbscObj *kmmv
 	return errors.Join(errs...)
 }
 
+// recordLogConfigMap links the image's status to the ConfigMap holding the logs of its finished build or sign,
+// once they have been archived.
+func (mrh *mbscReconcilerHelper) recordLogConfigMap(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig,
+	imageSpec *kmmv1beta1.ModuleBuildSignSpec) error {
+
+	logConfigMap, err := mrh.buildSignAPI.GetLogConfigMap(ctx, mbscObj.Name, mbscObj.Namespace, imageSpec.KernelVersion,
+		imageSpec.Action, mbscObj)
+	if err != nil {
+		return err
+	}
+	if logConfigMap != "" {
+		mrh.mbscAPI.SetImageLogConfigMap(mbscObj, imageSpec.Image, logConfigMap)
+	}
+	return nil
+}
+
+// recordSignedFiles reports the kernel modules signed by the successful sign of the image, once they have been
+// recorded on the sign resource.
+func (mrh *mbscReconcilerHelper) recordSignedFiles(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig,
+	imageSpec *kmmv1beta1.ModuleBuildSignSpec) error {
+
+	signedFiles, err := mrh.buildSignAPI.GetSignedFiles(ctx, mbscObj.Name, mbscObj.Namespace, imageSpec.KernelVersion, mbscObj)
+	if err != nil {
+		return err
+	}
+	if len(signedFiles) > 0 {
+		mrh.mbscAPI.SetImageSignedFiles(mbscObj, imageSpec.Image, signedFiles)
+	}
+	return nil
+}
+
+// recordFailure records the failure of the current attempt of the image's action, and schedules a retry if the
+// failure is likely to be transient and the retry policy allows another attempt.
+func (mrh *mbscReconcilerHelper) recordFailure(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig,
+	imageSpec *kmmv1beta1.ModuleBuildSignSpec) error {
+
+	logger := log.FromContext(ctx)
+
+	reason, attempt, err := mrh.buildSignAPI.GetFailure(ctx, mbscObj.Name, mbscObj.Namespace, imageSpec.KernelVersion,
+		imageSpec.Action, mbscObj)
+	if err != nil {
+		return err
+	}
+
+	var failedAttempts int32
+	if imageState := mrh.mbscAPI.GetImageState(mbscObj, imageSpec.Image, imageSpec.Action); imageState != nil {
+		failedAttempts = imageState.FailedAttempts
+	}
+	if attempt < failedAttempts {
+		// the failure of that attempt was already recorded
+		return nil
+	}
+
+	var nextAttemptTime *metav1.Time
+	retryPolicy := getRetryPolicy(imageSpec)
+	if isRetryable(reason) && failedAttempts+1 < maxAttempts(retryPolicy) {
+		nextAttemptTime = &metav1.Time{Time: time.Now().Add(retryBackoff(retryPolicy, failedAttempts+1))}
+	}
+
+	logger.Info("Image action failed", "image", imageSpec.Image, "action", imageSpec.Action, "reason", reason,
+		"failedAttempts", failedAttempts+1, "nextAttemptTime", nextAttemptTime)
+	mrh.mbscAPI.SetImageFailure(mbscObj, imageSpec.Image, imageSpec.Action, reason, nextAttemptTime)
+
+	return nil
+}
+
+// checkBuildInputs records the build inputs hash of the images that were successfully built, and schedules a new
+// build for the images whose build inputs changed since then.
+// Recorded hashes are only compared once every buildInputsCheckInterval, as tracked by the LastBuildInputsCheckTime
+// status field.
+func (mrh *mbscReconcilerHelper) checkBuildInputs(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig) error {
+	logger := log.FromContext(ctx)
+	errs := make([]error, 0, len(mbscObj.Spec.Images))
+	imagesToRebuild := make([]string, 0, len(mbscObj.Spec.Images))
+	patchFrom := client.MergeFrom(mbscObj.DeepCopy())
+	now := time.Now()
+	due := nextBuildInputsCheckDelay(mbscObj, mrh.buildInputsCheckInterval, now) <= 0
+	for _, imageSpec := range mbscObj.Spec.Images {
+		if imageSpec.Build == nil || mrh.mbscAPI.GetImageStatus(mbscObj, imageSpec.Image, imageSpec.Action) != kmmv1beta1.ActionSuccess {
+			continue
+		}
+		recordedHash := mrh.mbscAPI.GetImageBuildInputsHash(mbscObj, imageSpec.Image)
+		if recordedHash != "" && !due {
+			// resolving the digests of the base images is expensive; only do it once per interval
+			continue
+		}
+		mld := createMLD(mbscObj, &imageSpec.ModuleImageSpec)
+		hash, err := mrh.buildSignAPI.GetBuildInputsHash(ctx, mld)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		switch recordedHash {
+		case hash:
+			// build inputs did not change since the image was built
+		case "":
+			mrh.mbscAPI.SetImageBuildInputsHash(mbscObj, imageSpec.Image, hash)
+		default:
+			logger.Info("Build inputs changed, rebuilding image", "image", imageSpec.Image, "old", recordedHash, "new", hash)
+			mrh.mbscAPI.RemoveImageStatus(mbscObj, imageSpec.Image)
+			imagesToRebuild = append(imagesToRebuild, imageSpec.Image)
+		}
+	}
+
+	// images that could not be checked are checked again on the next reconciliation
+	if due && len(errs) == 0 {
+		mbscObj.Status.LastBuildInputsCheckTime = &metav1.Time{Time: now}
+	}
+
+	if err := mrh.client.Status().Patch(ctx, mbscObj, patchFrom); err != nil {
+		return errors.Join(append(errs, fmt.Errorf("failed to patch the status of MBSC %s: %v", mbscObj.Name, err))...)
+	}
+
+	if len(imagesToRebuild) == 0 {
+		return errors.Join(errs...)
+	}
+
+	// a signed image has to go through the build action again before being re-signed
+	patchFrom = client.MergeFrom(mbscObj.DeepCopy())
+	for _, image := range imagesToRebuild {
+		mrh.mbscAPI.SetImageAction(mbscObj, image, kmmv1beta1.BuildImage)
+	}
+	if err := mrh.client.Patch(ctx, mbscObj, patchFrom); err != nil {
+		errs = append(errs, fmt.Errorf("failed to patch MBSC %s: %v", mbscObj.Name, err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// nextBuildInputsCheckDelay returns the time left until the build inputs of the MBSC are due to be checked again.
+// A zero interval means that they are checked on every reconciliation.
+func nextBuildInputsCheckDelay(mbscObj *kmmv1beta1.ModuleBuildSignConfig, interval time.Duration, now time.Time) time.Duration {
+	lastCheck := mbscObj.Status.LastBuildInputsCheckTime
+	if interval == 0 || lastCheck == nil {
+		return 0
+	}
+	return max(lastCheck.Add(interval).Sub(now), 0)
+}
+
+// checkSigningKeys records the fingerprint of the SigningKey certificate of the images that were successfully signed,
+// and schedules a new sign for the images whose SigningKey was rotated since then.
+func (mrh *mbscReconcilerHelper) checkSigningKeys(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig) error {
+	logger := log.FromContext(ctx)
+	errs := make([]error, 0, len(mbscObj.Spec.Images))
+	patchFrom := client.MergeFrom(mbscObj.DeepCopy())
+	for _, imageSpec := range mbscObj.Spec.Images {
+		if imageSpec.Action != kmmv1beta1.SignImage || imageSpec.Sign == nil || imageSpec.Sign.SigningKey == nil ||
+			mrh.mbscAPI.GetImageStatus(mbscObj, imageSpec.Image, imageSpec.Action) != kmmv1beta1.ActionSuccess {
+			continue
+		}
+		sk := kmmv1beta1.SigningKey{}
+		nsn := types.NamespacedName{Name: imageSpec.Sign.SigningKey.Name, Namespace: mbscObj.Namespace}
+		if err := mrh.client.Get(ctx, nsn, &sk); err != nil {
+			errs = append(errs, fmt.Errorf("failed to get SigningKey %s: %v", nsn, err))
+			continue
+		}
+		fingerprint := sk.Status.Fingerprint
+		if fingerprint == "" {
+			continue
+		}
+		recordedFingerprint := mrh.mbscAPI.GetImageSigningKeyFingerprint(mbscObj, imageSpec.Image)
+		switch recordedFingerprint {
+		case fingerprint:
+			// the SigningKey was not rotated since the image was signed
+		case "":
+			mrh.mbscAPI.SetImageSigningKeyFingerprint(mbscObj, imageSpec.Image, fingerprint)
+		default:
+			logger.Info("SigningKey rotated, signing image again", "image", imageSpec.Image, "signingKey", sk.Name)
+			mrh.mbscAPI.RemoveImageStatus(mbscObj, imageSpec.Image)
+		}
+	}
+
+	if err := mrh.client.Status().Patch(ctx, mbscObj, patchFrom); err != nil {
+		errs = append(errs, fmt.Errorf("failed to patch the status of MBSC %s: %v", mbscObj.Name, err))
+	}
+
+	return errors.Join(errs...)
+}
+
 func (mrh *mbscReconcilerHelper) processImagesSpecs(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig) error {
 	logger := log.FromContext(ctx)
 	errs := make([]error, 0, len(mbscObj.Spec.Images))
+	patchFrom := client.MergeFrom(mbscObj.DeepCopy())
+	queueChanged := false
+	now := time.Now()
 	for _, imageSpec := range mbscObj.Spec.Images {
-		imageStatus := mrh.mbscAPI.GetImageStatus(mbscObj, imageSpec.Image, imageSpec.Action)
+		imageState := mrh.mbscAPI.GetImageState(mbscObj, imageSpec.Image, imageSpec.Action)
+		imageStatus := kmmv1beta1.BuildOrSignStatus("")
+		if imageState != nil {
+			imageStatus = imageState.Status
+		}
 		if imageStatus == kmmv1beta1.ActionSuccess {
 			// in case action succeeded - skip to the next image. Otherwise, action had not been handled yet, or is being handled, or already failed.
 			// in that case the Sync API will take care of what is needed to be done
 			continue
 		}
 		mld := createMLD(mbscObj, &imageSpec.ModuleImageSpec)
+		mld.Attempt = currentAttempt(imageState, now)
 		err := mrh.buildSignAPI.Sync(ctx, mld, mbscObj.Spec.PushBuiltImage, imageSpec.Action, mbscObj)
-		if err != nil {
+		switch {
+		case errors.Is(err, buildsign.ErrResourceQueued):
+			if imageStatus != kmmv1beta1.ActionQueued {
+				logger.Info("Image queued", "image", imageSpec.Image, "action", imageSpec.Action)
+				mrh.mbscAPI.SetImageStatus(mbscObj, imageSpec.Image, imageSpec.Action, kmmv1beta1.ActionQueued)
+				queueChanged = true
+			}
+		case err != nil:
 			errs = append(errs, err)
 			logger.Info(utils.WarnString(fmt.Sprintf("sync for image %s, action %s failed: %v", imageSpec.Image, imageSpec.Action, err)))
+		case imageStatus == kmmv1beta1.ActionQueued && imageState.FailedAttempts > 0:
+			// the resource of a retry was created; keep the failed attempts until it completes
+			mrh.mbscAPI.SetImageStatus(mbscObj, imageSpec.Image, imageSpec.Action, kmmv1beta1.ActionFailure)
+			queueChanged = true
+		case imageStatus == kmmv1beta1.ActionQueued:
+			// the resource was created, the image is not queued anymore
+			mrh.mbscAPI.RemoveImageStatus(mbscObj, imageSpec.Image)
+			queueChanged = true
 		}
 	}
+
+	if queueChanged {
+		if err := mrh.client.Status().Patch(ctx, mbscObj, patchFrom); err != nil {
+			errs = append(errs, fmt.Errorf("failed to patch the status of MBSC %s: %v", mbscObj.Name, err))
+		}
+	}
+
 	return errors.Join(errs...)
 }
 
@@ -175,6 +479,86 @@ func (mrh *mbscReconcilerHelper) garbageCollect(ctx context.Context, mbscObj *km
 	return nil
 }
 
+func hasQueuedImages(mbscObj *kmmv1beta1.ModuleBuildSignConfig) bool {
+	return slices.ContainsFunc(mbscObj.Status.Images, func(imageState kmmv1beta1.BuildSignImageState) bool {
+		return imageState.Status == kmmv1beta1.ActionQueued
+	})
+}
+
+// currentAttempt returns the attempt of the image's action that must be synced: a new one once the retry of a
+// failure is due, the failed one otherwise.
+func currentAttempt(imageState *kmmv1beta1.BuildSignImageState, now time.Time) int32 {
+	if imageState == nil {
+		return 0
+	}
+	if imageState.Status == kmmv1beta1.ActionFailure && imageState.FailedAttempts > 0 &&
+		(imageState.NextAttemptTime == nil || now.Before(imageState.NextAttemptTime.Time)) {
+		return imageState.FailedAttempts - 1
+	}
+	return imageState.FailedAttempts
+}
+
+// nextRetryDelay returns the delay until the next retry of a failed image, if any is scheduled.
+func nextRetryDelay(mbscObj *kmmv1beta1.ModuleBuildSignConfig, now time.Time) (time.Duration, bool) {
+	var (
+		delay time.Duration
+		found bool
+	)
+	for _, imageState := range mbscObj.Status.Images {
+		if imageState.Status != kmmv1beta1.ActionFailure || imageState.NextAttemptTime == nil ||
+			!now.Before(imageState.NextAttemptTime.Time) {
+			continue
+		}
+		d := imageState.NextAttemptTime.Sub(now)
+		if !found || d < delay {
+			delay = d
+			found = true
+		}
+	}
+	return delay, found
+}
+
+func getRetryPolicy(imageSpec *kmmv1beta1.ModuleBuildSignSpec) *kmmv1beta1.RetryPolicy {
+	switch {
+	case imageSpec.Action == kmmv1beta1.BuildImage && imageSpec.Build != nil:
+		return imageSpec.Build.RetryPolicy
+	case imageSpec.Action == kmmv1beta1.SignImage && imageSpec.Sign != nil:
+		return imageSpec.Sign.RetryPolicy
+	}
+	return nil
+}
+
+// isRetryable returns false for the failures that would happen again with the same inputs.
+func isRetryable(reason kmmv1beta1.BuildOrSignFailureReason) bool {
+	return reason != kmmv1beta1.FailureCompile && reason != kmmv1beta1.FailureVerification &&
+		reason != kmmv1beta1.FailureCancelled
+}
+
+func maxAttempts(retryPolicy *kmmv1beta1.RetryPolicy) int32 {
+	if retryPolicy == nil || retryPolicy.MaxAttempts < 1 {
+		return 1
+	}
+	return retryPolicy.MaxAttempts
+}
+
+// retryBackoff returns the delay before the next attempt: the backoff is doubled after each failed attempt, up to
+// the maximum backoff.
+func retryBackoff(retryPolicy *kmmv1beta1.RetryPolicy, failedAttempts int32) time.Duration {
+	backoff := defaultRetryBackoff
+	maxBackoff := defaultRetryMaxBackoff
+	if retryPolicy != nil && retryPolicy.Backoff != nil {
+		backoff = retryPolicy.Backoff.Duration
+	}
+	if retryPolicy != nil && retryPolicy.MaxBackoff != nil {
+		maxBackoff = retryPolicy.MaxBackoff.Duration
+	}
+
+	for i := int32(1); i < failedAttempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
+
 func createMLD(mbscObj *kmmv1beta1.ModuleBuildSignConfig, imageSpec *kmmv1beta1.ModuleImageSpec) *api.ModuleLoaderData {
 	return &api.ModuleLoaderData{
 		Name:                    mbscObj.Name,
@@ -189,5 +573,6 @@ func createMLD(mbscObj *kmmv1beta1.ModuleBuildSignConfig, imageSpec *kmmv1beta1.
 		RegistryTLS:             imageSpec.RegistryTLS,
 		Tolerations:             mbscObj.Spec.Tolerations,
 		Modprobe:                kmmv1beta1.ModprobeSpec{DirName: imageSpec.DirName},
+		BuildPriority:           mbscObj.Spec.BuildPriority,
 	}
 }
@@ -4,16 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildsign"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mbsc"
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -29,49 +34,85 @@ var _ = Describe("MBSCReconciler_Reconcile", func() {
 		mockMBSCReconHelper = NewMockmbscReconcilerHelperAPI(ctrl)
 
 		mr = &mbscReconciler{
-			reconHelperAPI: mockMBSCReconHelper,
+			reconHelperAPI:           mockMBSCReconHelper,
+			buildInputsCheckInterval: time.Hour,
 		}
 	})
 
 	ctx := context.Background()
 	testMBSC := kmmv1beta1.ModuleBuildSignConfig{}
 
-	DescribeTable("check good and error flows", func(updateStatusError, processImagesSpecsError, garbageCollectError bool) {
+	DescribeTable("check good and error flows", func(updateStatusError, checkBuildInputsError, checkSigningKeysError,
+		processImagesSpecsError, garbageCollectError bool) {
 
 		returnedError := errors.New("some error")
 		expectedErr := returnedError
 		if updateStatusError {
-			mockMBSCReconHelper.EXPECT().updateStatus(ctx, &testMBSC).Return(returnedError)
+			mockMBSCReconHelper.EXPECT().updateStatus(gomock.Any(), &testMBSC).Return(returnedError)
 			goto executeTestFunction
 		}
-		mockMBSCReconHelper.EXPECT().updateStatus(ctx, &testMBSC).Return(nil)
+		mockMBSCReconHelper.EXPECT().updateStatus(gomock.Any(), &testMBSC).Return(nil)
+		if checkBuildInputsError {
+			mockMBSCReconHelper.EXPECT().checkBuildInputs(gomock.Any(), &testMBSC).Return(returnedError)
+			goto executeTestFunction
+		}
+		mockMBSCReconHelper.EXPECT().checkBuildInputs(gomock.Any(), &testMBSC).Return(nil)
+		if checkSigningKeysError {
+			mockMBSCReconHelper.EXPECT().checkSigningKeys(gomock.Any(), &testMBSC).Return(returnedError)
+			goto executeTestFunction
+		}
+		mockMBSCReconHelper.EXPECT().checkSigningKeys(gomock.Any(), &testMBSC).Return(nil)
 		if processImagesSpecsError {
-			mockMBSCReconHelper.EXPECT().processImagesSpecs(ctx, &testMBSC).Return(returnedError)
+			mockMBSCReconHelper.EXPECT().processImagesSpecs(gomock.Any(), &testMBSC).Return(returnedError)
 			goto executeTestFunction
 		}
-		mockMBSCReconHelper.EXPECT().processImagesSpecs(ctx, &testMBSC).Return(nil)
+		mockMBSCReconHelper.EXPECT().processImagesSpecs(gomock.Any(), &testMBSC).Return(nil)
 		if garbageCollectError {
-			mockMBSCReconHelper.EXPECT().garbageCollect(ctx, &testMBSC).Return(returnedError)
+			mockMBSCReconHelper.EXPECT().garbageCollect(gomock.Any(), &testMBSC).Return(returnedError)
 			goto executeTestFunction
 		}
-		mockMBSCReconHelper.EXPECT().garbageCollect(ctx, &testMBSC).Return(nil)
+		mockMBSCReconHelper.EXPECT().garbageCollect(gomock.Any(), &testMBSC).Return(nil)
 		expectedErr = nil
 
 	executeTestFunction:
 		res, err := mr.Reconcile(ctx, &testMBSC)
 
-		Expect(res).To(Equal(reconcile.Result{}))
 		if expectedErr != nil {
+			Expect(res).To(Equal(reconcile.Result{}))
 			Expect(err).To(HaveOccurred())
 		} else {
+			Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Hour}))
 			Expect(err).To(BeNil())
 		}
 	},
-		Entry("updateStatus failed", true, false, false),
-		Entry("processImageSpecs failed", false, true, false),
-		Entry("garbageCollect failed", false, false, true),
-		Entry("everything worked", false, false, false),
+		Entry("updateStatus failed", true, false, false, false, false),
+		Entry("checkBuildInputs failed", false, true, false, false, false),
+		Entry("checkSigningKeys failed", false, false, true, false, false),
+		Entry("processImageSpecs failed", false, false, false, true, false),
+		Entry("garbageCollect failed", false, false, false, false, true),
+		Entry("everything worked", false, false, false, false, false),
 	)
+
+	It("should requeue sooner if some images are queued", func() {
+		mbscObj := kmmv1beta1.ModuleBuildSignConfig{
+			Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+				Images: []kmmv1beta1.BuildSignImageState{
+					{Image: "some image", Action: kmmv1beta1.BuildImage, Status: kmmv1beta1.ActionQueued},
+				},
+			},
+		}
+		gomock.InOrder(
+			mockMBSCReconHelper.EXPECT().updateStatus(gomock.Any(), &mbscObj).Return(nil),
+			mockMBSCReconHelper.EXPECT().checkBuildInputs(gomock.Any(), &mbscObj).Return(nil),
+			mockMBSCReconHelper.EXPECT().checkSigningKeys(gomock.Any(), &mbscObj).Return(nil),
+			mockMBSCReconHelper.EXPECT().processImagesSpecs(gomock.Any(), &mbscObj).Return(nil),
+			mockMBSCReconHelper.EXPECT().garbageCollect(gomock.Any(), &mbscObj).Return(nil),
+		)
+
+		res, err := mr.Reconcile(ctx, &mbscObj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(reconcile.Result{RequeueAfter: queuedImagesRequeueInterval}))
+	})
 })
 
 var _ = Describe("updateStatus", func() {
@@ -91,7 +132,7 @@ var _ = Describe("updateStatus", func() {
 		statusWriter = client.NewMockStatusWriter(ctrl)
 		mockManager = buildsign.NewMockManager(ctrl)
 		mockMBSC = mbsc.NewMockMBSC(ctrl)
-		mrh = newMBSCReconcilerHelper(clnt, mockManager, mockMBSC)
+		mrh = newMBSCReconcilerHelper(clnt, mockManager, mockMBSC, 0)
 		testMBSC = kmmv1beta1.ModuleBuildSignConfig{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "some name",
@@ -140,6 +181,9 @@ var _ = Describe("updateStatus", func() {
 			mockManager.EXPECT().GetStatus(ctx, "some name", "some namespace", "kernel version 1", kmmv1beta1.BuildImage, &testMBSC).
 				Return(kmmv1beta1.ActionSuccess, nil),
 			mockMBSC.EXPECT().SetImageStatus(&testMBSC, "image 1", kmmv1beta1.BuildImage, kmmv1beta1.ActionSuccess),
+			mockManager.EXPECT().GetLogConfigMap(ctx, "some name", "some namespace", "kernel version 1", kmmv1beta1.BuildImage, &testMBSC).
+				Return("some-log", nil),
+			mockMBSC.EXPECT().SetImageLogConfigMap(&testMBSC, "image 1", "some-log"),
 			mockManager.EXPECT().GetStatus(ctx, "some name", "some namespace", "kernel version 2", kmmv1beta1.SignImage, &testMBSC).
 				Return(kmmv1beta1.BuildOrSignStatus(""), nil),
 			mockManager.EXPECT().GetStatus(ctx, "some name", "some namespace", "kernel version 3", kmmv1beta1.BuildImage, &testMBSC).
@@ -151,15 +195,445 @@ var _ = Describe("updateStatus", func() {
 		err := mrh.updateStatus(ctx, &testMBSC)
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("should report the files signed by a successful sign", func() {
+		testMBSC.Spec.Images = []kmmv1beta1.ModuleBuildSignSpec{
+			{
+				ModuleImageSpec: kmmv1beta1.ModuleImageSpec{
+					Image:         "image 1",
+					KernelVersion: "kernel version 1",
+				},
+				Action: kmmv1beta1.SignImage,
+			},
+		}
+		signedFiles := []string{"/modules/a.ko", "/modules/b.ko.zst"}
+
+		gomock.InOrder(
+			mockManager.EXPECT().GetStatus(ctx, "some name", "some namespace", "kernel version 1", kmmv1beta1.SignImage, &testMBSC).
+				Return(kmmv1beta1.ActionSuccess, nil),
+			mockMBSC.EXPECT().SetImageStatus(&testMBSC, "image 1", kmmv1beta1.SignImage, kmmv1beta1.ActionSuccess),
+			mockManager.EXPECT().GetLogConfigMap(ctx, "some name", "some namespace", "kernel version 1", kmmv1beta1.SignImage, &testMBSC).
+				Return("", nil),
+			mockManager.EXPECT().GetSignedFiles(ctx, "some name", "some namespace", "kernel version 1", &testMBSC).
+				Return(signedFiles, nil),
+			mockMBSC.EXPECT().SetImageSignedFiles(&testMBSC, "image 1", signedFiles),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		err := mrh.updateStatus(ctx, &testMBSC)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("failures", func() {
+		BeforeEach(func() {
+			testMBSC.Spec.Images = []kmmv1beta1.ModuleBuildSignSpec{
+				{
+					ModuleImageSpec: kmmv1beta1.ModuleImageSpec{
+						Image:         "image 1",
+						KernelVersion: "kernel version 1",
+						Build: &kmmv1beta1.Build{
+							RetryPolicy: &kmmv1beta1.RetryPolicy{MaxAttempts: 3},
+						},
+					},
+					Action: kmmv1beta1.BuildImage,
+				},
+			}
+			mockManager.EXPECT().GetLogConfigMap(ctx, "some name", "some namespace", "kernel version 1", kmmv1beta1.BuildImage, &testMBSC).
+				Return("", nil)
+		})
+
+		expectFailure := func(reason kmmv1beta1.BuildOrSignFailureReason, attempt int32, imageState *kmmv1beta1.BuildSignImageState) {
+			gomock.InOrder(
+				mockManager.EXPECT().GetStatus(ctx, "some name", "some namespace", "kernel version 1", kmmv1beta1.BuildImage, &testMBSC).
+					Return(kmmv1beta1.ActionFailure, nil),
+				mockManager.EXPECT().GetFailure(ctx, "some name", "some namespace", "kernel version 1", kmmv1beta1.BuildImage, &testMBSC).
+					Return(reason, attempt, nil),
+				mockMBSC.EXPECT().GetImageState(&testMBSC, "image 1", kmmv1beta1.BuildImage).Return(imageState),
+			)
+		}
+
+		It("should schedule a retry for a transient failure", func() {
+			expectFailure(kmmv1beta1.FailurePush, 0, nil)
+			gomock.InOrder(
+				mockMBSC.EXPECT().SetImageFailure(&testMBSC, "image 1", kmmv1beta1.BuildImage, kmmv1beta1.FailurePush, gomock.Not(gomock.Nil())),
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+			)
+
+			err := mrh.updateStatus(ctx, &testMBSC)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not schedule a retry for a compilation error", func() {
+			expectFailure(kmmv1beta1.FailureCompile, 0, nil)
+			gomock.InOrder(
+				mockMBSC.EXPECT().SetImageFailure(&testMBSC, "image 1", kmmv1beta1.BuildImage, kmmv1beta1.FailureCompile, nil),
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+			)
+
+			err := mrh.updateStatus(ctx, &testMBSC)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not schedule a retry for a signature verification error", func() {
+			expectFailure(kmmv1beta1.FailureVerification, 0, nil)
+			gomock.InOrder(
+				mockMBSC.EXPECT().SetImageFailure(&testMBSC, "image 1", kmmv1beta1.BuildImage, kmmv1beta1.FailureVerification, nil),
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+			)
+
+			err := mrh.updateStatus(ctx, &testMBSC)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not schedule a retry once all the attempts were used", func() {
+			expectFailure(kmmv1beta1.FailureFetch, 2, &kmmv1beta1.BuildSignImageState{Status: kmmv1beta1.ActionFailure, FailedAttempts: 2})
+			gomock.InOrder(
+				mockMBSC.EXPECT().SetImageFailure(&testMBSC, "image 1", kmmv1beta1.BuildImage, kmmv1beta1.FailureFetch, nil),
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+			)
+
+			err := mrh.updateStatus(ctx, &testMBSC)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not record the failure of an attempt twice", func() {
+			expectFailure(kmmv1beta1.FailureFetch, 0, &kmmv1beta1.BuildSignImageState{Status: kmmv1beta1.ActionFailure, FailedAttempts: 1})
+			gomock.InOrder(
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+			)
+
+			err := mrh.updateStatus(ctx, &testMBSC)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return an error if the failure could not be retrieved", func() {
+			gomock.InOrder(
+				mockManager.EXPECT().GetStatus(ctx, "some name", "some namespace", "kernel version 1", kmmv1beta1.BuildImage, &testMBSC).
+					Return(kmmv1beta1.ActionFailure, nil),
+				mockManager.EXPECT().GetFailure(ctx, "some name", "some namespace", "kernel version 1", kmmv1beta1.BuildImage, &testMBSC).
+					Return(kmmv1beta1.BuildOrSignFailureReason(""), int32(0), fmt.Errorf("some error")),
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+			)
+
+			err := mrh.updateStatus(ctx, &testMBSC)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("checkBuildInputs", func() {
+	var (
+		ctrl         *gomock.Controller
+		clnt         *client.MockClient
+		statusWriter *client.MockStatusWriter
+		mockManager  *buildsign.MockManager
+		mockMBSC     *mbsc.MockMBSC
+		mrh          mbscReconcilerHelperAPI
+		testMBSC     kmmv1beta1.ModuleBuildSignConfig
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		statusWriter = client.NewMockStatusWriter(ctrl)
+		mockManager = buildsign.NewMockManager(ctrl)
+		mockMBSC = mbsc.NewMockMBSC(ctrl)
+		mrh = newMBSCReconcilerHelper(clnt, mockManager, mockMBSC, time.Hour)
+		testMBSC = kmmv1beta1.ModuleBuildSignConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "some name",
+				Namespace: "some namespace",
+			},
+			Spec: kmmv1beta1.ModuleBuildSignConfigSpec{
+				Images: []kmmv1beta1.ModuleBuildSignSpec{
+					{
+						ModuleImageSpec: kmmv1beta1.ModuleImageSpec{
+							Image: "image 1",
+							Build: &kmmv1beta1.Build{},
+						},
+						Action: kmmv1beta1.BuildImage,
+					},
+					{
+						ModuleImageSpec: kmmv1beta1.ModuleImageSpec{
+							Image: "image 2",
+						},
+						Action: kmmv1beta1.SignImage,
+					},
+				},
+			},
+		}
+	})
+
+	ctx := context.Background()
+
+	It("should skip images that are not built or not built yet", func() {
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.BuildImage).Return(kmmv1beta1.ActionFailure),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		err := mrh.checkBuildInputs(ctx, &testMBSC)
+		Expect(err).To(BeNil())
+	})
+
+	It("should return an error if the build inputs hash could not be computed", func() {
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.BuildImage).Return(kmmv1beta1.ActionSuccess),
+			mockMBSC.EXPECT().GetImageBuildInputsHash(&testMBSC, "image 1").Return(""),
+			mockManager.EXPECT().GetBuildInputsHash(ctx, gomock.Any()).Return("", errors.New("some error")),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		err := mrh.checkBuildInputs(ctx, &testMBSC)
+		Expect(err).To(HaveOccurred())
+		Expect(testMBSC.Status.LastBuildInputsCheckTime).To(BeNil())
+	})
+
+	It("should record the hash if none was recorded yet", func() {
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.BuildImage).Return(kmmv1beta1.ActionSuccess),
+			mockMBSC.EXPECT().GetImageBuildInputsHash(&testMBSC, "image 1").Return(""),
+			mockManager.EXPECT().GetBuildInputsHash(ctx, gomock.Any()).Return("1234", nil),
+			mockMBSC.EXPECT().SetImageBuildInputsHash(&testMBSC, "image 1", "1234"),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		err := mrh.checkBuildInputs(ctx, &testMBSC)
+		Expect(err).To(BeNil())
+		Expect(testMBSC.Status.LastBuildInputsCheckTime).NotTo(BeNil())
+	})
+
+	It("should record the hash if none was recorded yet even if the check is not due", func() {
+		lastCheck := metav1.NewTime(time.Now().Add(-time.Minute))
+		testMBSC.Status.LastBuildInputsCheckTime = &lastCheck
+
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.BuildImage).Return(kmmv1beta1.ActionSuccess),
+			mockMBSC.EXPECT().GetImageBuildInputsHash(&testMBSC, "image 1").Return(""),
+			mockManager.EXPECT().GetBuildInputsHash(ctx, gomock.Any()).Return("1234", nil),
+			mockMBSC.EXPECT().SetImageBuildInputsHash(&testMBSC, "image 1", "1234"),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		err := mrh.checkBuildInputs(ctx, &testMBSC)
+		Expect(err).To(BeNil())
+		Expect(testMBSC.Status.LastBuildInputsCheckTime).To(Equal(&lastCheck))
+	})
+
+	It("should not compute the build inputs hash again if the check is not due", func() {
+		lastCheck := metav1.NewTime(time.Now().Add(-time.Minute))
+		testMBSC.Status.LastBuildInputsCheckTime = &lastCheck
+
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.BuildImage).Return(kmmv1beta1.ActionSuccess),
+			mockMBSC.EXPECT().GetImageBuildInputsHash(&testMBSC, "image 1").Return("1234"),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		err := mrh.checkBuildInputs(ctx, &testMBSC)
+		Expect(err).To(BeNil())
+		Expect(testMBSC.Status.LastBuildInputsCheckTime).To(Equal(&lastCheck))
+	})
+
+	It("should do nothing if the build inputs did not change", func() {
+		lastCheck := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		testMBSC.Status.LastBuildInputsCheckTime = &lastCheck
+
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.BuildImage).Return(kmmv1beta1.ActionSuccess),
+			mockMBSC.EXPECT().GetImageBuildInputsHash(&testMBSC, "image 1").Return("1234"),
+			mockManager.EXPECT().GetBuildInputsHash(ctx, gomock.Any()).Return("1234", nil),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		err := mrh.checkBuildInputs(ctx, &testMBSC)
+		Expect(err).To(BeNil())
+		Expect(testMBSC.Status.LastBuildInputsCheckTime.Time).To(BeTemporally(">", lastCheck.Time))
+	})
+
+	It("should rebuild the image if the build inputs changed", func() {
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.BuildImage).Return(kmmv1beta1.ActionSuccess),
+			mockMBSC.EXPECT().GetImageBuildInputsHash(&testMBSC, "image 1").Return("1234"),
+			mockManager.EXPECT().GetBuildInputsHash(ctx, gomock.Any()).Return("5678", nil),
+			mockMBSC.EXPECT().RemoveImageStatus(&testMBSC, "image 1"),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+			mockMBSC.EXPECT().SetImageAction(&testMBSC, "image 1", kmmv1beta1.BuildImage),
+			clnt.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		err := mrh.checkBuildInputs(ctx, &testMBSC)
+		Expect(err).To(BeNil())
+	})
+
+	It("should return an error if the MBSC could not be patched", func() {
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.BuildImage).Return(kmmv1beta1.ActionSuccess),
+			mockMBSC.EXPECT().GetImageBuildInputsHash(&testMBSC, "image 1").Return("1234"),
+			mockManager.EXPECT().GetBuildInputsHash(ctx, gomock.Any()).Return("5678", nil),
+			mockMBSC.EXPECT().RemoveImageStatus(&testMBSC, "image 1"),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+			mockMBSC.EXPECT().SetImageAction(&testMBSC, "image 1", kmmv1beta1.BuildImage),
+			clnt.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(errors.New("some error")),
+		)
+
+		err := mrh.checkBuildInputs(ctx, &testMBSC)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = DescribeTable("nextBuildInputsCheckDelay",
+	func(interval time.Duration, sinceLastCheck *time.Duration, expected time.Duration) {
+		now := time.Now()
+		mbscObj := kmmv1beta1.ModuleBuildSignConfig{}
+		if sinceLastCheck != nil {
+			mbscObj.Status.LastBuildInputsCheckTime = &metav1.Time{Time: now.Add(-*sinceLastCheck)}
+		}
+
+		Expect(nextBuildInputsCheckDelay(&mbscObj, interval, now)).To(Equal(expected))
+	},
+	Entry("no interval", time.Duration(0), ptr.To(time.Minute), time.Duration(0)),
+	Entry("never checked", time.Hour, nil, time.Duration(0)),
+	Entry("checked recently", time.Hour, ptr.To(time.Minute), 59*time.Minute),
+	Entry("check overdue", time.Hour, ptr.To(2*time.Hour), time.Duration(0)),
+)
+
+var _ = Describe("checkSigningKeys", func() {
+	var (
+		ctrl         *gomock.Controller
+		clnt         *client.MockClient
+		statusWriter *client.MockStatusWriter
+		mockMBSC     *mbsc.MockMBSC
+		mrh          mbscReconcilerHelperAPI
+		testMBSC     kmmv1beta1.ModuleBuildSignConfig
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		statusWriter = client.NewMockStatusWriter(ctrl)
+		mockMBSC = mbsc.NewMockMBSC(ctrl)
+		mrh = newMBSCReconcilerHelper(clnt, nil, mockMBSC, 0)
+		testMBSC = kmmv1beta1.ModuleBuildSignConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "some name",
+				Namespace: "some namespace",
+			},
+			Spec: kmmv1beta1.ModuleBuildSignConfigSpec{
+				Images: []kmmv1beta1.ModuleBuildSignSpec{
+					{
+						ModuleImageSpec: kmmv1beta1.ModuleImageSpec{
+							Image: "image 1",
+							Sign:  &kmmv1beta1.Sign{SigningKey: &v1.LocalObjectReference{Name: "sk"}},
+						},
+						Action: kmmv1beta1.SignImage,
+					},
+					{
+						ModuleImageSpec: kmmv1beta1.ModuleImageSpec{
+							Image: "image 2",
+							Sign:  &kmmv1beta1.Sign{KeySecret: &v1.LocalObjectReference{Name: "key"}},
+						},
+						Action: kmmv1beta1.SignImage,
+					},
+				},
+			},
+		}
+	})
+
+	ctx := context.Background()
+
+	expectSigningKey := func(fingerprint string) *gomock.Call {
+		return clnt.EXPECT().Get(ctx, types.NamespacedName{Name: "sk", Namespace: "some namespace"}, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, sk *kmmv1beta1.SigningKey, _ ...ctrlclient.GetOption) error {
+				sk.Status.Fingerprint = fingerprint
+				return nil
+			},
+		)
+	}
+
+	It("should skip images that are not signed yet", func() {
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.SignImage).Return(kmmv1beta1.ActionFailure),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		Expect(mrh.checkSigningKeys(ctx, &testMBSC)).To(Succeed())
+	})
+
+	It("should return an error if the SigningKey could not be fetched", func() {
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.SignImage).Return(kmmv1beta1.ActionSuccess),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(errors.New("some error")),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		Expect(mrh.checkSigningKeys(ctx, &testMBSC)).To(HaveOccurred())
+	})
+
+	It("should record the fingerprint if none was recorded yet", func() {
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.SignImage).Return(kmmv1beta1.ActionSuccess),
+			expectSigningKey("abcd"),
+			mockMBSC.EXPECT().GetImageSigningKeyFingerprint(&testMBSC, "image 1").Return(""),
+			mockMBSC.EXPECT().SetImageSigningKeyFingerprint(&testMBSC, "image 1", "abcd"),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		Expect(mrh.checkSigningKeys(ctx, &testMBSC)).To(Succeed())
+	})
+
+	It("should do nothing if the SigningKey was not rotated", func() {
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.SignImage).Return(kmmv1beta1.ActionSuccess),
+			expectSigningKey("abcd"),
+			mockMBSC.EXPECT().GetImageSigningKeyFingerprint(&testMBSC, "image 1").Return("abcd"),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		Expect(mrh.checkSigningKeys(ctx, &testMBSC)).To(Succeed())
+	})
+
+	It("should sign the image again if the SigningKey was rotated", func() {
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.SignImage).Return(kmmv1beta1.ActionSuccess),
+			expectSigningKey("ef01"),
+			mockMBSC.EXPECT().GetImageSigningKeyFingerprint(&testMBSC, "image 1").Return("abcd"),
+			mockMBSC.EXPECT().RemoveImageStatus(&testMBSC, "image 1"),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		Expect(mrh.checkSigningKeys(ctx, &testMBSC)).To(Succeed())
+	})
 })
 
 var _ = Describe("processImagesSpecs", func() {
 	var (
-		ctrl        *gomock.Controller
-		clnt        *client.MockClient
-		mockManager *buildsign.MockManager
-		mockMBSC    *mbsc.MockMBSC
-		mrh         mbscReconcilerHelperAPI
+		ctrl             *gomock.Controller
+		clnt             *client.MockClient
+		mockManager      *buildsign.MockManager
+		mockMBSC         *mbsc.MockMBSC
+		mockStatusWriter *client.MockStatusWriter
+		mrh              mbscReconcilerHelperAPI
 	)
 
 	BeforeEach(func() {
@@ -167,7 +641,8 @@ var _ = Describe("processImagesSpecs", func() {
 		clnt = client.NewMockClient(ctrl)
 		mockManager = buildsign.NewMockManager(ctrl)
 		mockMBSC = mbsc.NewMockMBSC(ctrl)
-		mrh = newMBSCReconcilerHelper(clnt, mockManager, mockMBSC)
+		mockStatusWriter = client.NewMockStatusWriter(ctrl)
+		mrh = newMBSCReconcilerHelper(clnt, mockManager, mockMBSC, 0)
 	})
 
 	ctx := context.Background()
@@ -203,16 +678,174 @@ var _ = Describe("processImagesSpecs", func() {
 
 	It("multiple image, some statuses are success, some failures", func() {
 		gomock.InOrder(
-			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 1", kmmv1beta1.BuildImage).Return(kmmv1beta1.ActionSuccess),
-			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 2", kmmv1beta1.SignImage).Return(kmmv1beta1.ActionFailure),
+			mockMBSC.EXPECT().GetImageState(&testMBSC, "image 1", kmmv1beta1.BuildImage).Return(&kmmv1beta1.BuildSignImageState{Status: kmmv1beta1.ActionSuccess}),
+			mockMBSC.EXPECT().GetImageState(&testMBSC, "image 2", kmmv1beta1.SignImage).Return(&kmmv1beta1.BuildSignImageState{Status: kmmv1beta1.ActionFailure}),
 			mockManager.EXPECT().Sync(ctx, gomock.Any(), true, kmmv1beta1.SignImage, &testMBSC).Return(nil),
-			mockMBSC.EXPECT().GetImageStatus(&testMBSC, "image 3", kmmv1beta1.BuildImage).Return(kmmv1beta1.BuildOrSignStatus("")),
+			mockMBSC.EXPECT().GetImageState(&testMBSC, "image 3", kmmv1beta1.BuildImage).Return(nil),
 			mockManager.EXPECT().Sync(ctx, gomock.Any(), true, kmmv1beta1.BuildImage, &testMBSC).Return(fmt.Errorf("some error")),
 		)
 
 		err := mrh.processImagesSpecs(ctx, &testMBSC)
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("should mark queued images and images leaving the queue in the status", func() {
+		mbscObj := testMBSC.DeepCopy()
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageState(mbscObj, "image 1", kmmv1beta1.BuildImage).Return(nil),
+			mockManager.EXPECT().Sync(ctx, gomock.Any(), true, kmmv1beta1.BuildImage, mbscObj).Return(buildsign.ErrResourceQueued),
+			mockMBSC.EXPECT().SetImageStatus(mbscObj, "image 1", kmmv1beta1.BuildImage, kmmv1beta1.ActionQueued),
+			mockMBSC.EXPECT().GetImageState(mbscObj, "image 2", kmmv1beta1.SignImage).Return(&kmmv1beta1.BuildSignImageState{Status: kmmv1beta1.ActionQueued}),
+			mockManager.EXPECT().Sync(ctx, gomock.Any(), true, kmmv1beta1.SignImage, mbscObj).Return(nil),
+			mockMBSC.EXPECT().RemoveImageStatus(mbscObj, "image 2"),
+			mockMBSC.EXPECT().GetImageState(mbscObj, "image 3", kmmv1beta1.BuildImage).Return(&kmmv1beta1.BuildSignImageState{Status: kmmv1beta1.ActionQueued}),
+			mockManager.EXPECT().Sync(ctx, gomock.Any(), true, kmmv1beta1.BuildImage, mbscObj).Return(buildsign.ErrResourceQueued),
+			clnt.EXPECT().Status().Return(mockStatusWriter),
+			mockStatusWriter.EXPECT().Patch(ctx, mbscObj, gomock.Any()).Return(nil),
+		)
+
+		err := mrh.processImagesSpecs(ctx, mbscObj)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should sync a new attempt once the retry of a failure is due", func() {
+		mbscObj := testMBSC.DeepCopy()
+		mbscObj.Spec.Images = mbscObj.Spec.Images[:1]
+		imageState := kmmv1beta1.BuildSignImageState{
+			Status:          kmmv1beta1.ActionFailure,
+			FailedAttempts:  1,
+			NextAttemptTime: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+		}
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageState(mbscObj, "image 1", kmmv1beta1.BuildImage).Return(&imageState),
+			mockManager.EXPECT().Sync(ctx, gomock.Any(), true, kmmv1beta1.BuildImage, mbscObj).DoAndReturn(
+				func(_ context.Context, mld *api.ModuleLoaderData, _ bool, _ kmmv1beta1.BuildOrSignAction, _ metav1.Object) error {
+					Expect(mld.Attempt).To(Equal(int32(1)))
+					return nil
+				},
+			),
+		)
+
+		err := mrh.processImagesSpecs(ctx, mbscObj)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should keep the failed attempts when a queued retry starts", func() {
+		mbscObj := testMBSC.DeepCopy()
+		mbscObj.Spec.Images = mbscObj.Spec.Images[:1]
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageState(mbscObj, "image 1", kmmv1beta1.BuildImage).
+				Return(&kmmv1beta1.BuildSignImageState{Status: kmmv1beta1.ActionQueued, FailedAttempts: 1}),
+			mockManager.EXPECT().Sync(ctx, gomock.Any(), true, kmmv1beta1.BuildImage, mbscObj).Return(nil),
+			mockMBSC.EXPECT().SetImageStatus(mbscObj, "image 1", kmmv1beta1.BuildImage, kmmv1beta1.ActionFailure),
+			clnt.EXPECT().Status().Return(mockStatusWriter),
+			mockStatusWriter.EXPECT().Patch(ctx, mbscObj, gomock.Any()).Return(nil),
+		)
+
+		err := mrh.processImagesSpecs(ctx, mbscObj)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not patch the status if images are still queued", func() {
+		mbscObj := testMBSC.DeepCopy()
+		mbscObj.Spec.Images = mbscObj.Spec.Images[:1]
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageState(mbscObj, "image 1", kmmv1beta1.BuildImage).Return(&kmmv1beta1.BuildSignImageState{Status: kmmv1beta1.ActionQueued}),
+			mockManager.EXPECT().Sync(ctx, gomock.Any(), true, kmmv1beta1.BuildImage, mbscObj).Return(buildsign.ErrResourceQueued),
+		)
+
+		err := mrh.processImagesSpecs(ctx, mbscObj)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return an error if the status could not be patched", func() {
+		mbscObj := testMBSC.DeepCopy()
+		mbscObj.Spec.Images = mbscObj.Spec.Images[:1]
+		gomock.InOrder(
+			mockMBSC.EXPECT().GetImageState(mbscObj, "image 1", kmmv1beta1.BuildImage).Return(nil),
+			mockManager.EXPECT().Sync(ctx, gomock.Any(), true, kmmv1beta1.BuildImage, mbscObj).Return(buildsign.ErrResourceQueued),
+			mockMBSC.EXPECT().SetImageStatus(mbscObj, "image 1", kmmv1beta1.BuildImage, kmmv1beta1.ActionQueued),
+			clnt.EXPECT().Status().Return(mockStatusWriter),
+			mockStatusWriter.EXPECT().Patch(ctx, mbscObj, gomock.Any()).Return(fmt.Errorf("some error")),
+		)
+
+		err := mrh.processImagesSpecs(ctx, mbscObj)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("currentAttempt", func() {
+	now := time.Now()
+	past := metav1.NewTime(now.Add(-time.Minute))
+	future := metav1.NewTime(now.Add(time.Minute))
+
+	DescribeTable("should return the attempt to sync",
+		func(imageState *kmmv1beta1.BuildSignImageState, expected int32) {
+			Expect(currentAttempt(imageState, now)).To(Equal(expected))
+		},
+		Entry("no status", nil, int32(0)),
+		Entry("failure without retry", &kmmv1beta1.BuildSignImageState{Status: kmmv1beta1.ActionFailure, FailedAttempts: 2}, int32(1)),
+		Entry(
+			"failure with a retry to come",
+			&kmmv1beta1.BuildSignImageState{Status: kmmv1beta1.ActionFailure, FailedAttempts: 2, NextAttemptTime: &future},
+			int32(1),
+		),
+		Entry(
+			"failure with a retry due",
+			&kmmv1beta1.BuildSignImageState{Status: kmmv1beta1.ActionFailure, FailedAttempts: 2, NextAttemptTime: &past},
+			int32(2),
+		),
+		Entry("queued retry", &kmmv1beta1.BuildSignImageState{Status: kmmv1beta1.ActionQueued, FailedAttempts: 2}, int32(2)),
+	)
+})
+
+var _ = Describe("nextRetryDelay", func() {
+	now := time.Now()
+
+	It("should return the delay until the earliest retry", func() {
+		mbscObj := kmmv1beta1.ModuleBuildSignConfig{
+			Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+				Images: []kmmv1beta1.BuildSignImageState{
+					{Image: "image 1", Status: kmmv1beta1.ActionFailure, NextAttemptTime: &metav1.Time{Time: now.Add(time.Hour)}},
+					{Image: "image 2", Status: kmmv1beta1.ActionFailure, NextAttemptTime: &metav1.Time{Time: now.Add(time.Minute)}},
+					{Image: "image 3", Status: kmmv1beta1.ActionFailure, NextAttemptTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+					{Image: "image 4", Status: kmmv1beta1.ActionFailure},
+				},
+			},
+		}
+
+		delay, ok := nextRetryDelay(&mbscObj, now)
+		Expect(ok).To(BeTrue())
+		Expect(delay).To(Equal(time.Minute))
+	})
+
+	It("should return false if no retry is scheduled", func() {
+		_, ok := nextRetryDelay(&kmmv1beta1.ModuleBuildSignConfig{}, now)
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("retryBackoff", func() {
+	DescribeTable("should double the backoff up to the maximum",
+		func(retryPolicy *kmmv1beta1.RetryPolicy, failedAttempts int32, expected time.Duration) {
+			Expect(retryBackoff(retryPolicy, failedAttempts)).To(Equal(expected))
+		},
+		Entry("default backoff", nil, int32(1), defaultRetryBackoff),
+		Entry("default backoff, third failure", nil, int32(3), 4*defaultRetryBackoff),
+		Entry("default maximum backoff", nil, int32(20), defaultRetryMaxBackoff),
+		Entry(
+			"custom backoff",
+			&kmmv1beta1.RetryPolicy{Backoff: &metav1.Duration{Duration: time.Second}},
+			int32(2),
+			2*time.Second,
+		),
+		Entry(
+			"custom maximum backoff",
+			&kmmv1beta1.RetryPolicy{Backoff: &metav1.Duration{Duration: time.Second}, MaxBackoff: &metav1.Duration{Duration: 3 * time.Second}},
+			int32(3),
+			3*time.Second,
+		),
+	)
 })
 
 var _ = Describe("createMLD", func() {
@@ -280,7 +913,7 @@ var _ = Describe("garbageCollect", func() {
 		clnt = client.NewMockClient(ctrl)
 		mockManager = buildsign.NewMockManager(ctrl)
 		mockMBSC = mbsc.NewMockMBSC(ctrl)
-		mrh = newMBSCReconcilerHelper(clnt, mockManager, mockMBSC)
+		mrh = newMBSCReconcilerHelper(clnt, mockManager, mockMBSC, 0)
 	})
 
 	ctx := context.Background()
@@ -17,52 +17,150 @@ limitations under the License.
 package controllers
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
 
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta2"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/filter"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mbsc"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mic"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/pod"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/registry"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-const MICReconcilerName = "MICReconciler"
+const (
+	MICReconcilerName = "MICReconciler"
+
+	// maxImageVerificationFailures is the number of consecutive times an existing image must fail to be pulled before
+	// it is considered gone, unless the registry reports that it does not exist.
+	maxImageVerificationFailures = 3
+
+	// imageRetryBaseDelay and imageRetryMaxDelay bound the backoff after which an image that failed to be pulled is
+	// pulled again.
+	imageRetryBaseDelay = time.Minute
+	imageRetryMaxDelay  = time.Hour
+)
 
 // micReconciler reconciles a MIC (moduleimagesconfig) object
 type micReconciler struct {
+	client         client.Client
 	micReconHelper micReconcilerHelper
 	imagePullerAPI pod.ImagePuller
+	micAPI         mic.MIC
 }
 
+// NewMICReconciler returns a MIC reconciler; existing images are pulled again every imageVerificationInterval to
+// verify that they still exist, unless it is 0.
 func NewMICReconciler(client client.Client, micAPI mic.MIC, mbscAPI mbsc.MBSC, imagePullerAPI pod.ImagePuller,
-	scheme *runtime.Scheme) *micReconciler {
+	registryAPI registry.Registry, recorder record.EventRecorder, scheme *runtime.Scheme,
+	imageVerificationInterval time.Duration) *micReconciler {
 
-	micReconHelper := newMICReconcilerHelper(client, imagePullerAPI, micAPI, mbscAPI, scheme)
+	micReconHelper := newMICReconcilerHelper(client, imagePullerAPI, micAPI, mbscAPI, registryAPI, recorder, scheme,
+		imageVerificationInterval)
 	return &micReconciler{
+		client:         client,
 		micReconHelper: micReconHelper,
 		imagePullerAPI: imagePullerAPI,
+		micAPI:         micAPI,
 	}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *micReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// MICs are indexed by their images so that the state of an image is shared by all the MICs referencing it
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &kmmv1beta1.ModuleImagesConfig{},
+		mic.ImageIndexKey, mic.IndexImages); err != nil {
+		return fmt.Errorf("could not start the MIC image indexer: %v", err)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&kmmv1beta1.ModuleImagesConfig{}).
 		Owns(&v1.Pod{}).
 		Owns(&kmmv1beta1.ModuleBuildSignConfig{}).
+		Watches(
+			&kmmv1beta1.ModuleImagesConfig{},
+			handler.EnqueueRequestsFromMapFunc(r.findMICsSharingImages),
+		).
+		Watches(
+			&v1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.findMICsWithImageGC),
+			builder.WithPredicates(filter.NodeUpdateKernelChangedPredicate()),
+		).
+		Watches(
+			&v1beta2.PreflightValidation{},
+			handler.EnqueueRequestsFromMapFunc(r.findMICsWithImageGC),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Named(MICReconcilerName).
 		Complete(
 			reconcile.AsReconciler[*kmmv1beta1.ModuleImagesConfig](mgr.GetClient(), r),
 		)
 }
 
+// findMICsSharingImages enqueues the other MICs referencing the images of a MIC, so that they pick up the images it
+// verified or built.
+func (r *micReconciler) findMICsSharingImages(ctx context.Context, obj client.Object) []reconcile.Request {
+	key := client.ObjectKeyFromObject(obj)
+	keys := sets.New[types.NamespacedName]()
+	for _, image := range mic.IndexImages(obj) {
+		mics, err := r.micAPI.ListByImage(ctx, image)
+		if err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "could not list the MICs sharing the image", "image", image)
+			continue
+		}
+		for _, micObj := range mics {
+			if micKey := client.ObjectKeyFromObject(&micObj); micKey != key {
+				keys.Insert(micKey)
+			}
+		}
+	}
+
+	reqs := make([]reconcile.Request, 0, keys.Len())
+	for k := range keys {
+		reqs = append(reqs, reconcile.Request{NamespacedName: k})
+	}
+	return reqs
+}
+
+// findMICsWithImageGC enqueues the MICs whose garbage collection is enabled, so that it picks up the kernels that
+// the nodes or the PreflightValidations stopped using.
+func (r *micReconciler) findMICsWithImageGC(ctx context.Context, _ client.Object) []reconcile.Request {
+	micList := kmmv1beta1.ModuleImagesConfigList{}
+	if err := r.client.List(ctx, &micList); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "could not list the MICs")
+		return nil
+	}
+
+	reqs := make([]reconcile.Request, 0, len(micList.Items))
+	for _, micObj := range micList.Items {
+		if micObj.Spec.ImageGC != nil {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&micObj)})
+		}
+	}
+	return reqs
+}
+
 func (r *micReconciler) Reconcile(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig) (ctrl.Result, error) {
 	res := ctrl.Result{}
 	if micObj.GetDeletionTimestamp() != nil {
@@ -88,15 +186,38 @@ func (r *micReconciler) Reconcile(ctx context.Context, micObj *kmmv1beta1.Module
 		return res, fmt.Errorf("failed tp update the status for MIC %s based on pull pods: %v", micObj.Name, err)
 	}
 
-	err = r.micReconHelper.updateStatusByMBSC(ctx, micObj)
+	err = r.micReconHelper.updateStatusByMBSC(ctx, micObj, pods)
 	if err != nil {
 		return res, fmt.Errorf("failed tp update the status for MIC %s based on builds: %v", micObj.Name, err)
 	}
 
-	err = r.micReconHelper.processImagesSpecs(ctx, micObj, pods)
+	sharedBuilds, err := r.micReconHelper.processSharedImages(ctx, micObj)
+	if err != nil {
+		return res, fmt.Errorf("failed to process the images shared with other MICs: %v", err)
+	}
+
+	err = r.micReconHelper.processImagesSpecs(ctx, micObj, pods, sharedBuilds)
 	if err != nil {
 		return res, fmt.Errorf("failed to process images spec: %v", err)
 	}
+
+	err = r.micReconHelper.processPrePulls(ctx, micObj)
+	if err != nil {
+		return res, fmt.Errorf("failed to process the pre-pulls of MIC %s: %v", micObj.Name, err)
+	}
+
+	res.RequeueAfter, err = r.micReconHelper.verifyExistingImages(ctx, micObj, pods)
+	if err != nil {
+		return res, fmt.Errorf("failed to verify the existing images: %v", err)
+	}
+
+	gcRequeueAfter, err := r.micReconHelper.collectUnusedImages(ctx, micObj)
+	if err != nil {
+		return res, fmt.Errorf("failed to collect the unused images: %v", err)
+	}
+	if gcRequeueAfter > 0 && (res.RequeueAfter == 0 || gcRequeueAfter < res.RequeueAfter) {
+		res.RequeueAfter = gcRequeueAfter
+	}
 	return res, nil
 }
 
@@ -104,30 +225,43 @@ func (r *micReconciler) Reconcile(ctx context.Context, micObj *kmmv1beta1.Module
 type micReconcilerHelper interface {
 	handleImageRebuildTriggerGeneration(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig) (bool, error)
 	updateStatusByPullPods(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig, pods []v1.Pod) error
-	updateStatusByMBSC(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig) error
-	processImagesSpecs(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig, pullPods []v1.Pod) error
+	updateStatusByMBSC(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig, pullPods []v1.Pod) error
+	processSharedImages(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig) (sets.Set[string], error)
+	processImagesSpecs(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig, pullPods []v1.Pod, sharedBuilds sets.Set[string]) error
+	processPrePulls(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig) error
+	verifyExistingImages(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig, pullPods []v1.Pod) (time.Duration, error)
+	collectUnusedImages(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig) (time.Duration, error)
 }
 
 type micReconcilerHelperImpl struct {
-	client         client.Client
-	imagePullerAPI pod.ImagePuller
-	micHelper      mic.MIC
-	mbscHelper     mbsc.MBSC
-	scheme         *runtime.Scheme
+	client                    client.Client
+	imagePullerAPI            pod.ImagePuller
+	micHelper                 mic.MIC
+	mbscHelper                mbsc.MBSC
+	registryAPI               registry.Registry
+	recorder                  record.EventRecorder
+	scheme                    *runtime.Scheme
+	imageVerificationInterval time.Duration
 }
 
 func newMICReconcilerHelper(client client.Client,
 	imagePullerAPI pod.ImagePuller,
 	micAPI mic.MIC,
 	mbscAPI mbsc.MBSC,
-	scheme *runtime.Scheme) micReconcilerHelper {
+	registryAPI registry.Registry,
+	recorder record.EventRecorder,
+	scheme *runtime.Scheme,
+	imageVerificationInterval time.Duration) micReconcilerHelper {
 
 	return &micReconcilerHelperImpl{
-		client:         client,
-		imagePullerAPI: imagePullerAPI,
-		mbscHelper:     mbscAPI,
-		micHelper:      micAPI,
-		scheme:         scheme,
+		client:                    client,
+		imagePullerAPI:            imagePullerAPI,
+		mbscHelper:                mbscAPI,
+		micHelper:                 micAPI,
+		registryAPI:               registryAPI,
+		recorder:                  recorder,
+		scheme:                    scheme,
+		imageVerificationInterval: imageVerificationInterval,
 	}
 }
 
@@ -181,9 +315,31 @@ func (mrhi *micReconcilerHelperImpl) updateStatusByPullPods(ctx context.Context,
 			podsToDelete = append(podsToDelete, p)
 			continue
 		}
+		previousState := mrhi.micHelper.GetImageState(micObj, image)
 		podStatus := mrhi.imagePullerAPI.GetPullPodStatus(&p)
 		switch podStatus {
-		case pod.PullImageFailed:
+		case pod.PullImageFailed, pod.PullImageNotFound:
+			// the image being validated after it was built or signed is pulled again after a backoff
+			if previousState == kmmv1beta1.ImageNeedsBuilding || previousState == kmmv1beta1.ImageNeedsSigning {
+				failures := mrhi.micHelper.SetImageVerificationFailed(micObj, image, metav1.Now())
+				logger.Info("pull pod validating a pushed image failed, validating it again later", "failures", failures)
+				mrhi.recorder.Eventf(micObj, v1.EventTypeWarning, "ImageVerificationFailed",
+					"Image %s could not be pulled after it was pushed; it is verified again later", image)
+				podsToDelete = append(podsToDelete, p)
+				continue
+			}
+			// an existing image is only considered gone once the registry says so, or after several failures, so
+			// that an unavailable registry does not trigger rebuilds
+			if previousState == kmmv1beta1.ImageExists && podStatus == pod.PullImageFailed {
+				failures := mrhi.micHelper.SetImageVerificationFailed(micObj, image, metav1.Now())
+				if failures < maxImageVerificationFailures {
+					logger.Info("pull pod verifying an existing image failed, verifying it again later", "failures", failures)
+					mrhi.recorder.Eventf(micObj, v1.EventTypeWarning, "ImageVerificationFailed",
+						"Image %s could not be pulled (%d/%d); it is verified again later", image, failures, maxImageVerificationFailures)
+					podsToDelete = append(podsToDelete, p)
+					continue
+				}
+			}
 			switch {
 			case imageSpec.Build != nil:
 				logger.Info("pull pod failed, build exists, setting status to kmmv1beta1.ImageNeedsBuilding")
@@ -194,14 +350,52 @@ func (mrhi *micReconcilerHelperImpl) updateStatusByPullPods(ctx context.Context,
 			case imageSpec.SkipWaitMissingImage:
 				logger.Info("pull pod failed, SkipWaitMissingImage was set, setting status to kmmv1beta1.ImageDoesNotExist")
 				mrhi.micHelper.SetImageStatus(micObj, image, kmmv1beta1.ImageDoesNotExist)
+				// the images that do not exist are looked for again after a backoff
+				mrhi.micHelper.SetImageVerificationFailed(micObj, image, metav1.Now())
+			case previousState == kmmv1beta1.ImageExists, previousState == kmmv1beta1.ImageDoesNotExist:
+				logger.Info("pull pod verifying the image failed, setting status to kmmv1beta1.ImageDoesNotExist")
+				mrhi.micHelper.SetImageStatus(micObj, image, kmmv1beta1.ImageDoesNotExist)
+				mrhi.micHelper.SetImageVerificationFailed(micObj, image, metav1.Now())
 			default:
 				logger.Info(utils.WarnString("failed pod without build or sign spec, shoud not have happened"))
 			}
+			if previousState == kmmv1beta1.ImageExists {
+				mrhi.recorder.Eventf(micObj, v1.EventTypeWarning, "ImageNotFound",
+					"Image %s could not be pulled anymore; its status is now %s", image, mrhi.micHelper.GetImageState(micObj, image))
+			}
+			podsToDelete = append(podsToDelete, p)
+
+		case pod.PullImageInvalidLayout:
+			findings := mrhi.imagePullerAPI.GetPullPodLayoutFindings(p)
+			logger.Info("pull pod found that the image does not have the expected layout, setting status to kmmv1beta1.ImageInvalidLayout",
+				"findings", findings)
+			mrhi.micHelper.SetImageStatus(micObj, image, kmmv1beta1.ImageInvalidLayout)
+			mrhi.micHelper.SetImageLayoutFindings(micObj, image, findings)
+			// the image may be fixed in its registry: it is verified again after a backoff
+			mrhi.micHelper.SetImageVerificationFailed(micObj, image, metav1.Now())
+			if previousState != kmmv1beta1.ImageInvalidLayout {
+				mrhi.recorder.Eventf(micObj, v1.EventTypeWarning, "InvalidImageLayout",
+					"Image %s does not contain the expected kernel modules: %s", image, strings.Join(findings, "; "))
+			}
 			podsToDelete = append(podsToDelete, p)
 
 		case pod.PullImageSuccess:
-			logger.Info("successful pod, updating image status to ImageExists")
+			digest := mrhi.imagePullerAPI.GetPullPodImageDigest(p)
+			if previousState == kmmv1beta1.ImageExists {
+				// Nodes are only pinned to a digest when an image is verified for the first time, so that verifying
+				// the existing images does not reload their kernel modules. A new digest is a new version of the image.
+				previousDigest := mrhi.micHelper.GetImageDigest(micObj, image)
+				switch {
+				case previousDigest == "" || digest == "":
+					digest = previousDigest
+				case digest != previousDigest:
+					mrhi.recorder.Eventf(micObj, v1.EventTypeNormal, "ImageDigestChanged",
+						"The digest of image %s changed from %s to %s", image, previousDigest, digest)
+				}
+			}
+			logger.Info("successful pod, updating image status to ImageExists", "digest", digest)
 			mrhi.micHelper.SetImageStatus(micObj, image, kmmv1beta1.ImageExists)
+			mrhi.micHelper.SetImageVerified(micObj, image, digest, metav1.Now())
 			podsToDelete = append(podsToDelete, p)
 		}
 	}
@@ -222,7 +416,9 @@ func (mrhi *micReconcilerHelperImpl) updateStatusByPullPods(ctx context.Context,
 	return errors.Join(errs...)
 }
 
-func (mrhi *micReconcilerHelperImpl) updateStatusByMBSC(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig) error {
+func (mrhi *micReconcilerHelperImpl) updateStatusByMBSC(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig,
+	pullPods []v1.Pod) error {
+
 	logger := ctrl.LoggerFrom(ctx).WithValues("mic name", micObj.Name)
 	mbsc, err := mrhi.mbscHelper.Get(ctx, micObj.Name, micObj.Namespace)
 	if err != nil {
@@ -233,6 +429,7 @@ func (mrhi *micReconcilerHelperImpl) updateStatusByMBSC(ctx context.Context, mic
 		return nil
 	}
 
+	errs := make([]error, 0, len(mbsc.Status.Images)+1)
 	patchFrom := client.MergeFrom(micObj.DeepCopy())
 	for _, mbscImageState := range mbsc.Status.Images {
 		micImageSpec := mrhi.micHelper.GetModuleImageSpec(micObj, mbscImageState.Image)
@@ -245,14 +442,23 @@ func (mrhi *micReconcilerHelperImpl) updateStatusByMBSC(ctx context.Context, mic
 		mbscAction := mbscImageState.Action
 
 		switch {
+		case mbscStatus == kmmv1beta1.ActionFailure && mbscImageState.NextAttemptTime != nil:
+			// the failed action is retried - the image is still being built or signed, so that the MICs sharing
+			// it keep waiting for it
+			logger.Info("mbsc status failed and the action is retried, keeping mic image state", "action", mbscAction)
+			imageState := kmmv1beta1.ImageNeedsBuilding
+			if mbscAction == kmmv1beta1.SignImage {
+				imageState = kmmv1beta1.ImageNeedsSigning
+			}
+			mrhi.micHelper.SetImageStatus(micObj, mbscImageState.Image, imageState)
 		case mbscStatus == kmmv1beta1.ActionFailure:
-			// any failure (build or sign) - image does not exists
+			// any failure (build or sign) that is not retried - image does not exists
 			logger.Info("mbsc status failed, updating mic image to DoesNotExist")
 			mrhi.micHelper.SetImageStatus(micObj, mbscImageState.Image, kmmv1beta1.ImageDoesNotExist)
 		case mbscStatus == kmmv1beta1.ActionSuccess && mbscAction == kmmv1beta1.SignImage:
 			// sign action succeeded - image exists, nothing more to do
 			logger.Info("mbsc status success and action as sign, updating mic image to Exists")
-			mrhi.micHelper.SetImageStatus(micObj, mbscImageState.Image, kmmv1beta1.ImageExists)
+			errs = append(errs, mrhi.processPushedImage(ctx, micObj, micImageSpec, pullPods))
 		case mbscStatus == kmmv1beta1.ActionSuccess && micImageSpec.Sign != nil:
 			// build succeeded and sign exists - image needs to be signed
 			logger.Info("mbsc status success and sign section exists, updating mic image to NeedsSigning")
@@ -260,16 +466,133 @@ func (mrhi *micReconcilerHelperImpl) updateStatusByMBSC(ctx context.Context, mic
 		case mbscStatus == kmmv1beta1.ActionSuccess:
 			// build succeeded, no sign - image exists
 			logger.Info("mbsc status success and no sign section exists, updating mic image to Exists")
-			mrhi.micHelper.SetImageStatus(micObj, mbscImageState.Image, kmmv1beta1.ImageExists)
+			errs = append(errs, mrhi.processPushedImage(ctx, micObj, micImageSpec, pullPods))
+		}
+
+		if mbscImageState.BuildInputsHash != "" {
+			mrhi.micHelper.SetImageBuildInputsHash(micObj, mbscImageState.Image, mbscImageState.BuildInputsHash)
+		}
+	}
+
+	if err := mrhi.client.Status().Patch(ctx, micObj, patchFrom); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// processPushedImage marks an image that was built or signed in-cluster as existing, and records the digest it was
+// pushed with, so that the nodes load that image like the images that were pulled.
+// The digest is not recorded if the registry cannot be reached; the tag is used until the image is verified again.
+// If the layout of the image is known, the image is pulled instead, and only marked as existing if it has the
+// expected layout; a pull that fails is retried after a backoff by verifyExistingImages.
+func (mrhi *micReconcilerHelperImpl) processPushedImage(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig,
+	imageSpec *kmmv1beta1.ModuleImageSpec, pullPods []v1.Pod) error {
+
+	logger := ctrl.LoggerFrom(ctx).WithValues("mic name", micObj.Name)
+
+	switch mrhi.micHelper.GetImageState(micObj, imageSpec.Image) {
+	case kmmv1beta1.ImageExists, kmmv1beta1.ImageInvalidLayout:
+		return nil
+	}
+
+	if layout := imageLayout(imageSpec); layout != nil {
+		if mrhi.micHelper.GetImageVerificationFailures(micObj, imageSpec.Image) > 0 ||
+			mrhi.imagePullerAPI.GetPullPodForImage(pullPods, imageSpec.Image) != nil {
+			return nil
+		}
+		logger.Info("validating the layout of the pushed image", "image", imageSpec.Image)
+		err := mrhi.imagePullerAPI.CreatePullPod(ctx, micObj.Name, micObj.Namespace, imageSpec.Image, layout, true,
+			micObj.Spec.ImageRepoSecret, v1.PullAlways, micObj)
+		if err != nil {
+			return fmt.Errorf("failed to create the pull pod validating image %s: %v", imageSpec.Image, err)
+		}
+		return nil
+	}
+
+	mrhi.micHelper.SetImageStatus(micObj, imageSpec.Image, kmmv1beta1.ImageExists)
+
+	digest, err := mrhi.registryAPI.GetDigest(ctx, imageSpec.Image, imageSpec.RegistryTLS, micObj.Namespace,
+		micObj.Spec.ImageRepoSecret)
+	if err != nil {
+		logger.Info(utils.WarnString("failed to get the digest of the pushed image"), "image", imageSpec.Image, "error", err)
+		return nil
+	}
+	mrhi.micHelper.SetImageVerified(micObj, imageSpec.Image, digest, metav1.Now())
+	return nil
+}
+
+// processSharedImages looks the images that do not exist yet up in the other MICs of the cluster, so that each image
+// is verified and built once. The images that were verified by another MIC expecting the same layout are marked as
+// existing, or as not having the expected layout, like that MIC marked them. It returns the
+// images that another MIC is building: they are neither pulled nor built until that MIC is done.
+func (mrhi *micReconcilerHelperImpl) processSharedImages(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig) (sets.Set[string], error) {
+	logger := ctrl.LoggerFrom(ctx).WithValues("mic name", micObj.Name)
+
+	sharedBuilds := sets.New[string]()
+	patchFrom := client.MergeFrom(micObj.DeepCopy())
+	changed := false
+	for _, imageSpec := range micObj.Spec.Images {
+		if imageState := mrhi.micHelper.GetImageState(micObj, imageSpec.Image); imageState == kmmv1beta1.ImageExists ||
+			imageState == kmmv1beta1.ImageInvalidLayout {
+			continue
+		}
+
+		sharedImageState, err := mrhi.micHelper.GetSharedImageState(ctx, micObj, imageSpec.Image)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the shared state of image %s: %v", imageSpec.Image, err)
+		}
+
+		switch {
+		case sharedImageState.Verified != nil && sharedImageState.Verified.Status == kmmv1beta1.ImageInvalidLayout:
+			findings := sharedImageState.Verified.LayoutFindings
+			logger.Info("image does not have the expected layout according to another MIC, updating image status to ImageInvalidLayout",
+				"image", imageSpec.Image, "findings", findings)
+			mrhi.micHelper.SetImageStatus(micObj, imageSpec.Image, kmmv1beta1.ImageInvalidLayout)
+			mrhi.micHelper.SetImageLayoutFindings(micObj, imageSpec.Image, findings)
+			mrhi.micHelper.SetImageVerificationFailed(micObj, imageSpec.Image, metav1.Now())
+			mrhi.recorder.Eventf(micObj, v1.EventTypeWarning, "InvalidImageLayout",
+				"Image %s does not contain the expected kernel modules: %s", imageSpec.Image, strings.Join(findings, "; "))
+			changed = true
+		case sharedImageState.Verified != nil:
+			logger.Info("image exists according to another MIC, updating image status to ImageExists", "image", imageSpec.Image)
+			mrhi.micHelper.SetImageStatus(micObj, imageSpec.Image, kmmv1beta1.ImageExists)
+			if sharedImageState.Verified.LastVerifiedTime != nil {
+				mrhi.micHelper.SetImageVerified(micObj, imageSpec.Image, sharedImageState.Verified.Digest,
+					*sharedImageState.Verified.LastVerifiedTime)
+			}
+			changed = true
+		case sharedImageState.Builder != nil:
+			logger.Info("image is being built by another MIC, waiting for it", "image", imageSpec.Image,
+				"builder", sharedImageState.Builder)
+			sharedBuilds.Insert(imageSpec.Image)
 		}
 	}
 
-	return mrhi.client.Status().Patch(ctx, micObj, patchFrom)
+	if changed {
+		if err := mrhi.client.Status().Patch(ctx, micObj, patchFrom); err != nil {
+			return nil, fmt.Errorf("failed to patch the status of mic %s: %v", micObj.Name, err)
+		}
+	}
+
+	return sharedBuilds, nil
 }
 
-func (mrhi *micReconcilerHelperImpl) processImagesSpecs(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig, pullPods []v1.Pod) error {
+func (mrhi *micReconcilerHelperImpl) processImagesSpecs(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig, pullPods []v1.Pod,
+	sharedBuilds sets.Set[string]) error {
+
 	errs := make([]error, len(micObj.Spec.Images))
+
+	// images that were already built or signed must be rebuilt if their build inputs changed
+	if err := mrhi.mbscHelper.UpdateImagesSpecs(ctx, micObj); err != nil {
+		return fmt.Errorf("failed to update the images specs of the MBSC: %v", err)
+	}
+
 	for _, imageSpec := range micObj.Spec.Images {
+		if sharedBuilds.Has(imageSpec.Image) {
+			continue
+		}
+
 		imageState := mrhi.micHelper.GetImageState(micObj, imageSpec.Image)
 
 		var err error
@@ -283,6 +606,7 @@ func (mrhi *micReconcilerHelperImpl) processImagesSpecs(ctx context.Context, mic
 					micObj.Name,
 					micObj.Namespace,
 					imageSpec.Image,
+					imageLayout(&imageSpec),
 					oneTimePod,
 					micObj.Spec.ImageRepoSecret,
 					micObj.Spec.ImagePullPolicy,
@@ -305,3 +629,389 @@ func (mrhi *micReconcilerHelperImpl) processImagesSpecs(ctx context.Context, mic
 	}
 	return errors.Join(errs...)
 }
+
+type prePullKey struct {
+	image    string
+	nodeName string
+}
+
+// processPrePulls pulls the existing images listed in the spec's pre-pulls onto their nodes, and reports the progress
+// in the status. Each image is pulled once onto each node; the pods pulling images that are not needed anymore are
+// deleted.
+func (mrhi *micReconcilerHelperImpl) processPrePulls(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig) error {
+	logger := ctrl.LoggerFrom(ctx).WithValues("mic name", micObj.Name)
+
+	prePullPods, err := mrhi.imagePullerAPI.ListPrePullPods(ctx, micObj.Name, micObj.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to list the pre-pull pods: %v", err)
+	}
+
+	// images are pulled by digest, if it is known, so that the nodes hold the image they are going to load
+	desired := sets.New[prePullKey]()
+	for _, prePull := range micObj.Spec.PrePulls {
+		if mrhi.micHelper.GetImageState(micObj, prePull.Image) != kmmv1beta1.ImageExists {
+			continue
+		}
+		image := mrhi.micHelper.GetPinnedImage(micObj, prePull.Image)
+		for _, nodeName := range prePull.Nodes {
+			desired.Insert(prePullKey{image: image, nodeName: nodeName})
+		}
+	}
+
+	states := make(map[prePullKey]kmmv1beta1.PrePullState, desired.Len())
+	podsToDelete := make([]v1.Pod, 0, len(prePullPods))
+	for _, p := range prePullPods {
+		key := prePullKey{image: mrhi.imagePullerAPI.GetPullPodImage(p), nodeName: p.Spec.NodeName}
+		if !desired.Has(key) {
+			podsToDelete = append(podsToDelete, p)
+			continue
+		}
+		switch podStatus := mrhi.imagePullerAPI.GetPullPodStatus(&p); {
+		case podStatus == pod.PullImageSuccess:
+			states[key] = kmmv1beta1.PrePullPulled
+			podsToDelete = append(podsToDelete, p)
+		case podStatus == pod.PullImageFailed || podStatus == pod.PullImageNotFound || p.Status.Phase == v1.PodFailed:
+			logger.Info("Failed to pull the image onto the node", "image", key.image, "node", key.nodeName)
+			states[key] = kmmv1beta1.PrePullFailed
+			podsToDelete = append(podsToDelete, p)
+		default:
+			states[key] = kmmv1beta1.PrePullPulling
+		}
+	}
+
+	previousStates := make(map[prePullKey]kmmv1beta1.PrePullState, len(micObj.Status.PrePullStates))
+	for _, prePullState := range micObj.Status.PrePullStates {
+		previousStates[prePullKey{image: prePullState.Image, nodeName: prePullState.Node}] = prePullState.State
+	}
+
+	tolerations := append(slices.Clone(micObj.Spec.Tolerations), module.InternalTolerations...)
+	errs := make([]error, 0)
+	for key := range desired {
+		if _, ok := states[key]; ok {
+			continue
+		}
+		if previousState := previousStates[key]; previousState == kmmv1beta1.PrePullPulled || previousState == kmmv1beta1.PrePullFailed {
+			states[key] = previousState
+			continue
+		}
+		logger.Info("Pulling the image onto the node", "image", key.image, "node", key.nodeName)
+		err = mrhi.imagePullerAPI.CreatePrePullPod(ctx, micObj.Name, micObj.Namespace, key.image, key.nodeName,
+			micObj.Spec.ImageRepoSecret, tolerations, micObj)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create the pre-pull pod for image %s on node %s: %v", key.image, key.nodeName, err))
+			continue
+		}
+		states[key] = kmmv1beta1.PrePullPulling
+	}
+
+	var prePullStates []kmmv1beta1.PrePullNodeState
+	for key, state := range states {
+		prePullStates = append(prePullStates, kmmv1beta1.PrePullNodeState{Image: key.image, Node: key.nodeName, State: state})
+	}
+	slices.SortFunc(prePullStates, func(a, b kmmv1beta1.PrePullNodeState) int {
+		return cmp.Or(strings.Compare(a.Image, b.Image), strings.Compare(a.Node, b.Node))
+	})
+
+	if !reflect.DeepEqual(prePullStates, micObj.Status.PrePullStates) {
+		patchFrom := client.MergeFrom(micObj.DeepCopy())
+		micObj.Status.PrePullStates = prePullStates
+		if err = mrhi.client.Status().Patch(ctx, micObj, patchFrom); err != nil {
+			return fmt.Errorf("failed to patch the pre-pull states of MIC %s: %v", micObj.Name, err)
+		}
+	}
+
+	// the states are patched before the pods are deleted, so that no pull is lost
+	for _, p := range podsToDelete {
+		if err = mrhi.imagePullerAPI.DeletePod(ctx, &p); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete pre-pull pod %s: %v", p.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// verifyExistingImages pulls again the existing image that was verified the longest time ago, if it is due for a
+// verification. Images with an invalid layout are verified as well, so that fixing the image is eventually noticed.
+// A single image is verified at a time for each MIC so that the registry is not flooded. It returns the duration after
+// which the next image is due.
+func (mrhi *micReconcilerHelperImpl) verifyExistingImages(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig,
+	pullPods []v1.Pod) (time.Duration, error) {
+
+	imagesSpecs := make(map[string]*kmmv1beta1.ModuleImageSpec, len(micObj.Spec.Images))
+	for i, imageSpec := range micObj.Spec.Images {
+		imagesSpecs[imageSpec.Image] = &micObj.Spec.Images[i]
+	}
+
+	now := time.Now()
+	var (
+		imageToVerify string
+		oldest        time.Time
+		requeueAfter  time.Duration
+	)
+	for _, imageState := range micObj.Status.ImagesStates {
+		imageSpec, ok := imagesSpecs[imageState.Image]
+		if !ok {
+			continue
+		}
+
+		// images that were never verified, such as images built in-cluster, come first
+		var due time.Time
+		switch {
+		case imageState.Status == kmmv1beta1.ImageDoesNotExist && imageSpec.Build == nil && imageSpec.Sign == nil,
+			imageState.Status == kmmv1beta1.ImageInvalidLayout,
+			imageState.Status == kmmv1beta1.ImageExists && imageState.VerificationFailures > 0,
+			imageState.Status == kmmv1beta1.ImageNeedsBuilding && imageState.VerificationFailures > 0,
+			imageState.Status == kmmv1beta1.ImageNeedsSigning && imageState.VerificationFailures > 0:
+			// the images that failed to be pulled or that do not have the expected layout, including the images
+			// validated after they were pushed, are pulled again after a backoff, even if the existing images are not
+			// verified periodically
+			if imageState.LastVerificationFailureTime != nil {
+				due = imageState.LastVerificationFailureTime.Add(imageRetryDelay(imageState.VerificationFailures))
+			}
+		case imageState.Status != kmmv1beta1.ImageExists, mrhi.imageVerificationInterval == 0:
+			continue
+		case imageState.LastVerifiedTime != nil:
+			due = imageState.LastVerifiedTime.Add(mrhi.imageVerificationInterval)
+		}
+
+		if untilDue := due.Sub(now); untilDue > 0 {
+			if requeueAfter == 0 || untilDue < requeueAfter {
+				requeueAfter = untilDue
+			}
+			continue
+		}
+
+		if imageToVerify == "" || due.Before(oldest) {
+			imageToVerify = imageState.Image
+			oldest = due
+		}
+	}
+
+	// the pods that are still running will trigger a new reconciliation when they complete
+	if imageToVerify == "" || len(pullPods) > 0 {
+		return requeueAfter, nil
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Verifying the image", "mic name", micObj.Name, "image", imageToVerify)
+
+	// the image must be pulled from the registry even if it is present on the node
+	err := mrhi.imagePullerAPI.CreatePullPod(ctx, micObj.Name, micObj.Namespace, imageToVerify,
+		imageLayout(imagesSpecs[imageToVerify]), true, micObj.Spec.ImageRepoSecret, v1.PullAlways, micObj)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create the pull pod verifying image %s: %v", imageToVerify, err)
+	}
+
+	return requeueAfter, nil
+}
+
+// imageRetryDelay returns the delay after which an image that failed to be pulled failures times in a row is pulled
+// again.
+func imageRetryDelay(failures int32) time.Duration {
+	delay := imageRetryBaseDelay
+	for i := int32(1); i < failures && delay < imageRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, imageRetryMaxDelay)
+}
+
+// getKernelsInUse returns the kernels that the nodes run or that the PreflightValidations being processed validate.
+func (mrhi *micReconcilerHelperImpl) getKernelsInUse(ctx context.Context) (sets.Set[string], error) {
+	nodeList := v1.NodeList{}
+	if err := mrhi.client.List(ctx, &nodeList); err != nil {
+		return nil, fmt.Errorf("could not list the nodes: %v", err)
+	}
+
+	pvList := v1beta2.PreflightValidationList{}
+	if err := mrhi.client.List(ctx, &pvList); err != nil {
+		return nil, fmt.Errorf("could not list the PreflightValidations: %v", err)
+	}
+
+	kernels := sets.New[string]()
+	for _, node := range nodeList.Items {
+		kernels.Insert(strings.TrimSuffix(node.Status.NodeInfo.KernelVersion, "+"))
+	}
+	for _, pv := range pvList.Items {
+		if pv.GetDeletionTimestamp() == nil {
+			kernels.Insert(pv.Spec.KernelVersion)
+		}
+	}
+	return kernels, nil
+}
+
+// collectUnusedImages collects the images that are not in the spec and whose kernel no node ran and no
+// PreflightValidation validated for more than the retention period, if the garbage collection is enabled. They are removed from the status of the MIC and from the MBSC; the images that were
+// built or signed in-cluster are deleted from their registry as well if requested, unless another MIC, for example
+// the MIC of another Module or of a PreflightValidation, still references them. In dry-run mode, the images that
+// would be collected are only reported in the status. It returns the duration after which the next image is due.
+func (mrhi *micReconcilerHelperImpl) collectUnusedImages(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig) (time.Duration, error) {
+	logger := ctrl.LoggerFrom(ctx).WithValues("mic name", micObj.Name)
+
+	imageGC := micObj.Spec.ImageGC
+	patchFrom := client.MergeFrom(micObj.DeepCopy())
+
+	if imageGC == nil {
+		if micObj.Status.ImageGCDryRun == nil {
+			return 0, nil
+		}
+		micObj.Status.ImageGCDryRun = nil
+		if err := mrhi.client.Status().Patch(ctx, micObj, patchFrom); err != nil {
+			return 0, fmt.Errorf("failed to patch the status of mic %s: %v", micObj.Name, err)
+		}
+		return 0, nil
+	}
+
+	specKernels := make(map[string]string, len(micObj.Spec.Images))
+	for _, imageSpec := range micObj.Spec.Images {
+		specKernels[imageSpec.Image] = imageSpec.KernelVersion
+	}
+
+	var kernelsInUse sets.Set[string]
+	if slices.ContainsFunc(micObj.Status.ImagesStates, func(imageState kmmv1beta1.ModuleImageState) bool {
+		_, ok := specKernels[imageState.Image]
+		return !ok
+	}) {
+		var err error
+		if kernelsInUse, err = mrhi.getKernelsInUse(ctx); err != nil {
+			return 0, fmt.Errorf("failed to get the kernels in use: %v", err)
+		}
+	}
+
+	now := metav1.Now()
+	var (
+		unusedImages []string
+		requeueAfter time.Duration
+	)
+	for i, imageState := range micObj.Status.ImagesStates {
+		kernel, inSpec := specKernels[imageState.Image]
+		if inSpec {
+			// the kernel of the image is kept once it leaves the spec
+			micObj.Status.ImagesStates[i].KernelVersion = kernel
+		}
+
+		switch {
+		case inSpec || kernelsInUse.Has(imageState.KernelVersion):
+			micObj.Status.ImagesStates[i].UnusedSince = nil
+			continue
+		case imageState.UnusedSince == nil:
+			micObj.Status.ImagesStates[i].UnusedSince = &now
+		}
+
+		untilDue := micObj.Status.ImagesStates[i].UnusedSince.Add(imageGC.RetentionPeriod.Duration).Sub(now.Time)
+		if untilDue > 0 {
+			if requeueAfter == 0 || untilDue < requeueAfter {
+				requeueAfter = untilDue
+			}
+			continue
+		}
+		unusedImages = append(unusedImages, imageState.Image)
+	}
+
+	var (
+		mbscObj *kmmv1beta1.ModuleBuildSignConfig
+		err     error
+	)
+	if len(unusedImages) > 0 {
+		mbscObj, err = mrhi.mbscHelper.Get(ctx, micObj.Name, micObj.Namespace)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get the MBSC of mic %s: %v", micObj.Name, err)
+		}
+	}
+
+	collectedImages := make([]kmmv1beta1.CollectedImage, 0, len(unusedImages))
+	for _, image := range unusedImages {
+		collectedImage := kmmv1beta1.CollectedImage{Image: image}
+		// only the images that KMM built or signed, and therefore pushed, are deleted from their registry
+		if imageGC.DeleteFromRegistry && mbscObj != nil && mrhi.mbscHelper.GetImageSpec(mbscObj, image) != nil {
+			mics, err := mrhi.micHelper.ListByImage(ctx, image)
+			if err != nil {
+				return 0, fmt.Errorf("failed to list the MICs referencing image %s: %v", image, err)
+			}
+			collectedImage.DeleteFromRegistry = !slices.ContainsFunc(mics, func(other kmmv1beta1.ModuleImagesConfig) bool {
+				return client.ObjectKeyFromObject(&other) != client.ObjectKeyFromObject(micObj)
+			})
+		}
+		collectedImages = append(collectedImages, collectedImage)
+	}
+
+	if imageGC.DryRun {
+		if len(collectedImages) == 0 {
+			collectedImages = nil
+		}
+		micObj.Status.ImageGCDryRun = collectedImages
+		if err = mrhi.client.Status().Patch(ctx, micObj, patchFrom); err != nil {
+			return 0, fmt.Errorf("failed to patch the status of mic %s: %v", micObj.Name, err)
+		}
+		return requeueAfter, nil
+	}
+
+	var inUseImages []string
+	if slices.ContainsFunc(collectedImages, func(ci kmmv1beta1.CollectedImage) bool { return ci.DeleteFromRegistry }) {
+		// another tag of a manifest still in use must not be deleted with it
+		if inUseImages, err = mrhi.micHelper.ListImages(ctx); err != nil {
+			return 0, fmt.Errorf("failed to list the images in use: %v", err)
+		}
+	}
+
+	errs := make([]error, 0)
+	collected := sets.New[string]()
+	for _, collectedImage := range collectedImages {
+		if collectedImage.DeleteFromRegistry {
+			var tlsOptions *kmmv1beta1.TLSOptions
+			if imageSpec := mrhi.mbscHelper.GetImageSpec(mbscObj, collectedImage.Image); imageSpec != nil {
+				tlsOptions = imageSpec.RegistryTLS
+			}
+			logger.Info("Deleting the unused image from its registry", "image", collectedImage.Image)
+			err = mrhi.registryAPI.DeleteImage(ctx, collectedImage.Image, tlsOptions, micObj.Namespace,
+				micObj.Spec.ImageRepoSecret, inUseImages)
+			switch {
+			case errors.Is(err, registry.ErrImageShared):
+				logger.Info("Not deleting the unused image from its registry, its manifest is still in use", "image", collectedImage.Image)
+				mrhi.recorder.Eventf(micObj, v1.EventTypeNormal, "ImageNotDeleted",
+					"Unused image %s was not deleted from its registry, its manifest is referenced by an image in use", collectedImage.Image)
+			case err != nil:
+				// the image is collected again at the next reconciliation
+				errs = append(errs, fmt.Errorf("failed to delete image %s from its registry: %v", collectedImage.Image, err))
+				continue
+			default:
+				mrhi.recorder.Eventf(micObj, v1.EventTypeNormal, "ImageDeleted",
+					"Unused image %s was deleted from its registry", collectedImage.Image)
+			}
+		}
+		collected.Insert(collectedImage.Image)
+	}
+
+	if mbscObj != nil && collected.Len() > 0 {
+		if err = mrhi.mbscHelper.RemoveImages(ctx, mbscObj, collected); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove the unused images from the MBSC: %v", err))
+			collected.Clear()
+		}
+	}
+
+	for image := range collected {
+		logger.Info("Collected the unused image", "image", image)
+	}
+	micObj.Status.ImagesStates = slices.DeleteFunc(micObj.Status.ImagesStates, func(imageState kmmv1beta1.ModuleImageState) bool {
+		return collected.Has(imageState.Image)
+	})
+	micObj.Status.ImageGCDryRun = nil
+
+	if err = mrhi.client.Status().Patch(ctx, micObj, patchFrom); err != nil {
+		errs = append(errs, fmt.Errorf("failed to patch the status of mic %s: %v", micObj.Name, err))
+	}
+
+	return requeueAfter, errors.Join(errs...)
+}
+
+// imageLayout returns the layout that the image must have, or nil if it is not known.
+func imageLayout(imageSpec *kmmv1beta1.ModuleImageSpec) *pod.ImageLayout {
+	if len(imageSpec.ModuleNames) == 0 {
+		return nil
+	}
+
+	return &pod.ImageLayout{
+		DirName:       imageSpec.DirName,
+		KernelVersion: imageSpec.KernelVersion,
+		ModuleNames:   imageSpec.ModuleNames,
+		FirmwarePath:  imageSpec.FirmwarePath,
+	}
+}
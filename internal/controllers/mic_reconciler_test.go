@@ -4,17 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta2"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mbsc"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mic"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/pod"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/registry"
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -45,11 +54,16 @@ var _ = Describe("MicReconciler_Reconcile", func() {
 	DescribeTable("check good and error flows", func(listPullPodsError,
 		updateStatusByPodsError,
 		updateStatusByMBSCError,
-		processImagesSpecsError bool) {
+		processSharedImagesError,
+		processImagesSpecsError,
+		processPrePullsError,
+		verifyExistingImagesError,
+		collectUnusedImagesError bool) {
 
 		returnedError := errors.New("some error")
 		expectedErr := returnedError
 		pullPods := []v1.Pod{}
+		sharedBuilds := sets.New[string]()
 
 		mockMicReconHelper.EXPECT().handleImageRebuildTriggerGeneration(ctx, &testMic).Return(false, nil)
 
@@ -64,15 +78,35 @@ var _ = Describe("MicReconciler_Reconcile", func() {
 		}
 		mockMicReconHelper.EXPECT().updateStatusByPullPods(ctx, &testMic, pullPods).Return(nil)
 		if updateStatusByMBSCError {
-			mockMicReconHelper.EXPECT().updateStatusByMBSC(ctx, &testMic).Return(returnedError)
+			mockMicReconHelper.EXPECT().updateStatusByMBSC(ctx, &testMic, pullPods).Return(returnedError)
 			goto executeTestFunction
 		}
-		mockMicReconHelper.EXPECT().updateStatusByMBSC(ctx, &testMic).Return(nil)
+		mockMicReconHelper.EXPECT().updateStatusByMBSC(ctx, &testMic, pullPods).Return(nil)
+		if processSharedImagesError {
+			mockMicReconHelper.EXPECT().processSharedImages(ctx, &testMic).Return(nil, returnedError)
+			goto executeTestFunction
+		}
+		mockMicReconHelper.EXPECT().processSharedImages(ctx, &testMic).Return(sharedBuilds, nil)
 		if processImagesSpecsError {
-			mockMicReconHelper.EXPECT().processImagesSpecs(ctx, &testMic, pullPods).Return(returnedError)
+			mockMicReconHelper.EXPECT().processImagesSpecs(ctx, &testMic, pullPods, sharedBuilds).Return(returnedError)
+			goto executeTestFunction
+		}
+		mockMicReconHelper.EXPECT().processImagesSpecs(ctx, &testMic, pullPods, sharedBuilds).Return(nil)
+		if processPrePullsError {
+			mockMicReconHelper.EXPECT().processPrePulls(ctx, &testMic).Return(returnedError)
+			goto executeTestFunction
+		}
+		mockMicReconHelper.EXPECT().processPrePulls(ctx, &testMic).Return(nil)
+		if verifyExistingImagesError {
+			mockMicReconHelper.EXPECT().verifyExistingImages(ctx, &testMic, pullPods).Return(time.Duration(0), returnedError)
+			goto executeTestFunction
+		}
+		mockMicReconHelper.EXPECT().verifyExistingImages(ctx, &testMic, pullPods).Return(time.Duration(0), nil)
+		if collectUnusedImagesError {
+			mockMicReconHelper.EXPECT().collectUnusedImages(ctx, &testMic).Return(time.Duration(0), returnedError)
 			goto executeTestFunction
 		}
-		mockMicReconHelper.EXPECT().processImagesSpecs(ctx, &testMic, pullPods).Return(nil)
+		mockMicReconHelper.EXPECT().collectUnusedImages(ctx, &testMic).Return(time.Duration(0), nil)
 		expectedErr = nil
 
 	executeTestFunction:
@@ -85,11 +119,43 @@ var _ = Describe("MicReconciler_Reconcile", func() {
 			Expect(err).To(BeNil())
 		}
 	},
-		Entry("listPullPods failed", true, false, false, false),
-		Entry("updateStatusByPullPods failed", false, true, false, false),
-		Entry("updateStatusByMBSC failed", false, false, true, false),
-		Entry("processImagesSpecs failed", false, false, false, true),
-		Entry("everything worked", false, false, false, false),
+		Entry("listPullPods failed", true, false, false, false, false, false, false, false),
+		Entry("updateStatusByPullPods failed", false, true, false, false, false, false, false, false),
+		Entry("updateStatusByMBSC failed", false, false, true, false, false, false, false, false),
+		Entry("processSharedImages failed", false, false, false, true, false, false, false, false),
+		Entry("processImagesSpecs failed", false, false, false, false, true, false, false, false),
+		Entry("processPrePulls failed", false, false, false, false, false, true, false, false),
+		Entry("verifyExistingImages failed", false, false, false, false, false, false, true, false),
+		Entry("collectUnusedImages failed", false, false, false, false, false, false, false, true),
+		Entry("everything worked", false, false, false, false, false, false, false, false),
+	)
+
+	DescribeTable("should requeue when the next image verification or collection is due", func(verifyAfter,
+		collectAfter, expectedRequeueAfter time.Duration) {
+
+		pullPods := []v1.Pod{}
+
+		gomock.InOrder(
+			mockMicReconHelper.EXPECT().handleImageRebuildTriggerGeneration(ctx, &testMic).Return(false, nil),
+			mockImagePuller.EXPECT().ListPullPods(ctx, "some name", "some namespace").Return(pullPods, nil),
+			mockMicReconHelper.EXPECT().updateStatusByPullPods(ctx, &testMic, pullPods).Return(nil),
+			mockMicReconHelper.EXPECT().updateStatusByMBSC(ctx, &testMic, pullPods).Return(nil),
+			mockMicReconHelper.EXPECT().processSharedImages(ctx, &testMic).Return(sets.New[string](), nil),
+			mockMicReconHelper.EXPECT().processImagesSpecs(ctx, &testMic, pullPods, sets.New[string]()).Return(nil),
+			mockMicReconHelper.EXPECT().processPrePulls(ctx, &testMic).Return(nil),
+			mockMicReconHelper.EXPECT().verifyExistingImages(ctx, &testMic, pullPods).Return(verifyAfter, nil),
+			mockMicReconHelper.EXPECT().collectUnusedImages(ctx, &testMic).Return(collectAfter, nil),
+		)
+
+		res, err := mr.Reconcile(ctx, &testMic)
+
+		Expect(err).To(BeNil())
+		Expect(res.RequeueAfter).To(Equal(expectedRequeueAfter))
+	},
+		Entry("verification only", time.Hour, time.Duration(0), time.Hour),
+		Entry("collection only", time.Duration(0), time.Minute, time.Minute),
+		Entry("collection first", time.Hour, time.Minute, time.Minute),
+		Entry("verification first", time.Minute, time.Hour, time.Minute),
 	)
 
 	It("should return error if handleImageRebuildTriggerGeneration fails", func() {
@@ -126,7 +192,7 @@ var _ = Describe("handleImageRebuildTriggerGeneration", func() {
 		clnt = client.NewMockClient(ctrl)
 		statusWriter = client.NewMockStatusWriter(ctrl)
 		mbscHelper = mbsc.NewMockMBSC(ctrl)
-		mrh = newMICReconcilerHelper(clnt, nil, nil, mbscHelper, nil)
+		mrh = newMICReconcilerHelper(clnt, nil, nil, mbscHelper, nil, nil, nil, 0)
 	})
 
 	ctx := context.Background()
@@ -285,6 +351,7 @@ var _ = Describe("updateStatusByPullPods", func() {
 		statusWriter    *client.MockStatusWriter
 		mockImagePuller *pod.MockImagePuller
 		micHelper       *mic.MockMIC
+		fakeRecorder    *record.FakeRecorder
 		mrh             micReconcilerHelper
 	)
 
@@ -294,7 +361,8 @@ var _ = Describe("updateStatusByPullPods", func() {
 		statusWriter = client.NewMockStatusWriter(ctrl)
 		mockImagePuller = pod.NewMockImagePuller(ctrl)
 		micHelper = mic.NewMockMIC(ctrl)
-		mrh = newMICReconcilerHelper(clnt, mockImagePuller, micHelper, nil, nil)
+		fakeRecorder = record.NewFakeRecorder(10)
+		mrh = newMICReconcilerHelper(clnt, mockImagePuller, micHelper, nil, nil, fakeRecorder, nil, 0)
 	})
 
 	ctx := context.Background()
@@ -332,15 +400,22 @@ var _ = Describe("updateStatusByPullPods", func() {
 				micSpec.Sign = &kmmv1beta1.Sign{}
 			}
 			micSpec.SkipWaitMissingImage = skipWaitMissingImage
-			gomock.InOrder(
+			calls := []any{
 				mockImagePuller.EXPECT().GetPullPodImage(pullPod).Return("some test image"),
 				micHelper.EXPECT().GetModuleImageSpec(&testMic, "some test image").Return(&micSpec),
+				micHelper.EXPECT().GetImageState(&testMic, "some test image").Return(kmmv1beta1.ImageState("")),
 				mockImagePuller.EXPECT().GetPullPodStatus(&pullPod).Return(pod.PullImageFailed),
 				micHelper.EXPECT().SetImageStatus(&testMic, "some test image", stateToSet),
+			}
+			if stateToSet == kmmv1beta1.ImageDoesNotExist {
+				calls = append(calls, micHelper.EXPECT().SetImageVerificationFailed(&testMic, "some test image", gomock.Any()).Return(int32(1)))
+			}
+			calls = append(calls,
 				clnt.EXPECT().Status().Return(statusWriter),
 				statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
 				mockImagePuller.EXPECT().DeletePod(ctx, &pullPod).Return(nil),
 			)
+			gomock.InOrder(calls...)
 			err := mrh.updateStatusByPullPods(ctx, &testMic, []v1.Pod{pullPod})
 			Expect(err).To(BeNil())
 		},
@@ -349,6 +424,49 @@ var _ = Describe("updateStatusByPullPods", func() {
 		Entry("build missing, sign missing, skipWait true, state ImageDoesNotExist", false, false, true, kmmv1beta1.ImageDoesNotExist),
 	)
 
+	It("should mark an image with an invalid layout and record the findings", func() {
+		pullPod := v1.Pod{}
+		findings := []string{"finding 1", "finding 2"}
+		gomock.InOrder(
+			mockImagePuller.EXPECT().GetPullPodImage(pullPod).Return("some test image"),
+			micHelper.EXPECT().GetModuleImageSpec(&testMic, "some test image").Return(&kmmv1beta1.ModuleImageSpec{}),
+			micHelper.EXPECT().GetImageState(&testMic, "some test image").Return(kmmv1beta1.ImageExists),
+			mockImagePuller.EXPECT().GetPullPodStatus(&pullPod).Return(pod.PullImageInvalidLayout),
+			mockImagePuller.EXPECT().GetPullPodLayoutFindings(pullPod).Return(findings),
+			micHelper.EXPECT().SetImageStatus(&testMic, "some test image", kmmv1beta1.ImageInvalidLayout),
+			micHelper.EXPECT().SetImageLayoutFindings(&testMic, "some test image", findings),
+			micHelper.EXPECT().SetImageVerificationFailed(&testMic, "some test image", gomock.Any()).Return(int32(1)),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+			mockImagePuller.EXPECT().DeletePod(ctx, &pullPod).Return(nil),
+		)
+		err := mrh.updateStatusByPullPods(ctx, &testMic, []v1.Pod{pullPod})
+		Expect(err).To(BeNil())
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("finding 1; finding 2")))
+	})
+
+	DescribeTable("should validate a pushed image again later if it cannot be pulled",
+		func(previousState kmmv1beta1.ImageState) {
+			pullPod := v1.Pod{}
+			micSpec := kmmv1beta1.ModuleImageSpec{Image: "some test image", Build: &kmmv1beta1.Build{}}
+			gomock.InOrder(
+				mockImagePuller.EXPECT().GetPullPodImage(pullPod).Return("some test image"),
+				micHelper.EXPECT().GetModuleImageSpec(&testMic, "some test image").Return(&micSpec),
+				micHelper.EXPECT().GetImageState(&testMic, "some test image").Return(previousState),
+				mockImagePuller.EXPECT().GetPullPodStatus(&pullPod).Return(pod.PullImageFailed),
+				micHelper.EXPECT().SetImageVerificationFailed(&testMic, "some test image", gomock.Any()).Return(int32(1)),
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+				mockImagePuller.EXPECT().DeletePod(ctx, &pullPod).Return(nil),
+			)
+			err := mrh.updateStatusByPullPods(ctx, &testMic, []v1.Pod{pullPod})
+			Expect(err).To(BeNil())
+			Expect(fakeRecorder.Events).To(Receive(ContainSubstring("ImageVerificationFailed")))
+		},
+		Entry("built image", kmmv1beta1.ImageNeedsBuilding),
+		Entry("signed image", kmmv1beta1.ImageNeedsSigning),
+	)
+
 	It("pod failed, build or sign configs are not present, skipWait is false", func() {
 		pullPod := v1.Pod{}
 		micSpec := kmmv1beta1.ModuleImageSpec{
@@ -358,6 +476,7 @@ var _ = Describe("updateStatusByPullPods", func() {
 		gomock.InOrder(
 			mockImagePuller.EXPECT().GetPullPodImage(pullPod).Return("some test image"),
 			micHelper.EXPECT().GetModuleImageSpec(&testMic, "some test image").Return(&micSpec),
+			micHelper.EXPECT().GetImageState(&testMic, "some test image").Return(kmmv1beta1.ImageState("")),
 			mockImagePuller.EXPECT().GetPullPodStatus(&pullPod).Return(pod.PullImageFailed),
 			clnt.EXPECT().Status().Return(statusWriter),
 			statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
@@ -376,224 +495,858 @@ var _ = Describe("updateStatusByPullPods", func() {
 		gomock.InOrder(
 			mockImagePuller.EXPECT().GetPullPodImage(pullPod).Return("some test image"),
 			micHelper.EXPECT().GetModuleImageSpec(&testMic, "some test image").Return(&micSpec),
+			micHelper.EXPECT().GetImageState(&testMic, "some test image").Return(kmmv1beta1.ImageState("")),
 			mockImagePuller.EXPECT().GetPullPodStatus(&pullPod).Return(pod.PullImageSuccess),
+			mockImagePuller.EXPECT().GetPullPodImageDigest(pullPod).Return("sha256:111"),
 			micHelper.EXPECT().SetImageStatus(&testMic, "some test image", kmmv1beta1.ImageExists),
+			micHelper.EXPECT().SetImageVerified(&testMic, "some test image", "sha256:111", gomock.Any()),
 			clnt.EXPECT().Status().Return(statusWriter),
 			statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
 			mockImagePuller.EXPECT().DeletePod(ctx, &pullPod).Return(nil),
 		)
 		err := mrh.updateStatusByPullPods(ctx, &testMic, []v1.Pod{pullPod})
 		Expect(err).To(BeNil())
+		Expect(fakeRecorder.Events).To(BeEmpty())
 	})
-})
 
-var _ = Describe("updateStatusByMBSC", func() {
-	var (
-		ctrl         *gomock.Controller
-		clnt         *client.MockClient
-		statusWriter *client.MockStatusWriter
-		mbscHelper   *mbsc.MockMBSC
-		micHelper    *mic.MockMIC
-		mrh          micReconcilerHelper
-	)
+	It("should keep an existing image that failed to be verified until it failed several times", func() {
+		pullPod := v1.Pod{}
+		micSpec := kmmv1beta1.ModuleImageSpec{
+			Image: "some test image",
+			Build: &kmmv1beta1.Build{},
+		}
 
-	BeforeEach(func() {
-		ctrl = gomock.NewController(GinkgoT())
-		clnt = client.NewMockClient(ctrl)
-		statusWriter = client.NewMockStatusWriter(ctrl)
-		micHelper = mic.NewMockMIC(ctrl)
-		mbscHelper = mbsc.NewMockMBSC(ctrl)
-		mrh = newMICReconcilerHelper(clnt, nil, micHelper, mbscHelper, nil)
+		gomock.InOrder(
+			mockImagePuller.EXPECT().GetPullPodImage(pullPod).Return("some test image"),
+			micHelper.EXPECT().GetModuleImageSpec(&testMic, "some test image").Return(&micSpec),
+			micHelper.EXPECT().GetImageState(&testMic, "some test image").Return(kmmv1beta1.ImageExists),
+			mockImagePuller.EXPECT().GetPullPodStatus(&pullPod).Return(pod.PullImageFailed),
+			micHelper.EXPECT().SetImageVerificationFailed(&testMic, "some test image", gomock.Any()).
+				Return(int32(maxImageVerificationFailures-1)),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+			mockImagePuller.EXPECT().DeletePod(ctx, &pullPod).Return(nil),
+		)
+		err := mrh.updateStatusByPullPods(ctx, &testMic, []v1.Pod{pullPod})
+		Expect(err).To(BeNil())
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("ImageVerificationFailed")))
 	})
 
-	ctx := context.Background()
-	testMic := kmmv1beta1.ModuleImagesConfig{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "some name",
-			Namespace: "some namespace",
-		},
-		Spec: kmmv1beta1.ModuleImagesConfigSpec{
-			Images: []kmmv1beta1.ModuleImageSpec{
-				{
-					Image: "image 1",
-					Build: &kmmv1beta1.Build{},
-				},
-				{
-
-					Image: "image 2",
-				},
-			},
-		},
-	}
+	It("verification of an existing image failed too many times", func() {
+		pullPod := v1.Pod{}
+		micSpec := kmmv1beta1.ModuleImageSpec{
+			Image: "some test image",
+		}
 
-	It("failed to get MBSC", func() {
-		mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(nil, fmt.Errorf("some error"))
-		err := mrh.updateStatusByMBSC(ctx, &testMic)
-		Expect(err).To(HaveOccurred())
+		gomock.InOrder(
+			mockImagePuller.EXPECT().GetPullPodImage(pullPod).Return("some test image"),
+			micHelper.EXPECT().GetModuleImageSpec(&testMic, "some test image").Return(&micSpec),
+			micHelper.EXPECT().GetImageState(&testMic, "some test image").Return(kmmv1beta1.ImageExists),
+			mockImagePuller.EXPECT().GetPullPodStatus(&pullPod).Return(pod.PullImageFailed),
+			micHelper.EXPECT().SetImageVerificationFailed(&testMic, "some test image", gomock.Any()).
+				Return(int32(maxImageVerificationFailures)),
+			micHelper.EXPECT().SetImageStatus(&testMic, "some test image", kmmv1beta1.ImageDoesNotExist),
+			micHelper.EXPECT().SetImageVerificationFailed(&testMic, "some test image", gomock.Any()).Return(int32(1)),
+			micHelper.EXPECT().GetImageState(&testMic, "some test image").Return(kmmv1beta1.ImageDoesNotExist),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+			mockImagePuller.EXPECT().DeletePod(ctx, &pullPod).Return(nil),
+		)
+		err := mrh.updateStatusByPullPods(ctx, &testMic, []v1.Pod{pullPod})
+		Expect(err).To(BeNil())
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("ImageNotFound")))
 	})
 
-	It("MBSC does not exists", func() {
-		mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(nil, nil)
-		err := mrh.updateStatusByMBSC(ctx, &testMic)
+	It("verification of an existing image that the registry does not have anymore", func() {
+		pullPod := v1.Pod{}
+		micSpec := kmmv1beta1.ModuleImageSpec{
+			Image: "some test image",
+			Build: &kmmv1beta1.Build{},
+		}
+
+		gomock.InOrder(
+			mockImagePuller.EXPECT().GetPullPodImage(pullPod).Return("some test image"),
+			micHelper.EXPECT().GetModuleImageSpec(&testMic, "some test image").Return(&micSpec),
+			micHelper.EXPECT().GetImageState(&testMic, "some test image").Return(kmmv1beta1.ImageExists),
+			mockImagePuller.EXPECT().GetPullPodStatus(&pullPod).Return(pod.PullImageNotFound),
+			micHelper.EXPECT().SetImageStatus(&testMic, "some test image", kmmv1beta1.ImageNeedsBuilding),
+			micHelper.EXPECT().GetImageState(&testMic, "some test image").Return(kmmv1beta1.ImageNeedsBuilding),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+			mockImagePuller.EXPECT().DeletePod(ctx, &pullPod).Return(nil),
+		)
+		err := mrh.updateStatusByPullPods(ctx, &testMic, []v1.Pod{pullPod})
 		Expect(err).To(BeNil())
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("ImageNotFound")))
 	})
 
-	It("Image in MBSC status does not exists in MIC spec", func() {
-		testMBSC := kmmv1beta1.ModuleBuildSignConfig{
-			Status: kmmv1beta1.ModuleBuildSignConfigStatus{
-				Images: []kmmv1beta1.BuildSignImageState{
-					{
-						Image: "some image",
-					},
-				},
-			},
+	It("should count the failures to pull an image that does not exist", func() {
+		pullPod := v1.Pod{}
+		micSpec := kmmv1beta1.ModuleImageSpec{
+			Image: "some test image",
 		}
+
 		gomock.InOrder(
-			mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(&testMBSC, nil),
-			micHelper.EXPECT().GetModuleImageSpec(&testMic, "some image").Return(nil),
+			mockImagePuller.EXPECT().GetPullPodImage(pullPod).Return("some test image"),
+			micHelper.EXPECT().GetModuleImageSpec(&testMic, "some test image").Return(&micSpec),
+			micHelper.EXPECT().GetImageState(&testMic, "some test image").Return(kmmv1beta1.ImageDoesNotExist),
+			mockImagePuller.EXPECT().GetPullPodStatus(&pullPod).Return(pod.PullImageFailed),
+			micHelper.EXPECT().SetImageStatus(&testMic, "some test image", kmmv1beta1.ImageDoesNotExist),
+			micHelper.EXPECT().SetImageVerificationFailed(&testMic, "some test image", gomock.Any()).Return(int32(2)),
 			clnt.EXPECT().Status().Return(statusWriter),
 			statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+			mockImagePuller.EXPECT().DeletePod(ctx, &pullPod).Return(nil),
 		)
-		err := mrh.updateStatusByMBSC(ctx, &testMic)
+		err := mrh.updateStatusByPullPods(ctx, &testMic, []v1.Pod{pullPod})
 		Expect(err).To(BeNil())
+		Expect(fakeRecorder.Events).To(BeEmpty())
 	})
 
-	DescribeTable("image has status in MBSC and spec in MIC",
-		func(signExists bool, mbscImageAction kmmv1beta1.BuildOrSignAction, mbscImageStatus kmmv1beta1.BuildOrSignStatus,
-			expectedMICImageState kmmv1beta1.ImageState) {
-			testMBSC := kmmv1beta1.ModuleBuildSignConfig{
-				Status: kmmv1beta1.ModuleBuildSignConfigStatus{
-					Images: []kmmv1beta1.BuildSignImageState{
-						{
-							Image:  "some image",
-							Status: mbscImageStatus,
-							Action: mbscImageAction,
-						},
-					},
-				},
-			}
-			imageSpec := kmmv1beta1.ModuleImageSpec{}
-			if signExists {
-				imageSpec.Sign = &kmmv1beta1.Sign{}
+	DescribeTable("verification of an existing image succeeded",
+		func(previousDigest, pulledDigest, expectedDigest string, digestChanged bool) {
+			pullPod := v1.Pod{}
+			micSpec := kmmv1beta1.ModuleImageSpec{
+				Image: "some test image",
 			}
+
 			gomock.InOrder(
-				mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(&testMBSC, nil),
-				micHelper.EXPECT().GetModuleImageSpec(&testMic, "some image").Return(&imageSpec),
-				micHelper.EXPECT().SetImageStatus(&testMic, "some image", expectedMICImageState),
+				mockImagePuller.EXPECT().GetPullPodImage(pullPod).Return("some test image"),
+				micHelper.EXPECT().GetModuleImageSpec(&testMic, "some test image").Return(&micSpec),
+				micHelper.EXPECT().GetImageState(&testMic, "some test image").Return(kmmv1beta1.ImageExists),
+				mockImagePuller.EXPECT().GetPullPodStatus(&pullPod).Return(pod.PullImageSuccess),
+				mockImagePuller.EXPECT().GetPullPodImageDigest(pullPod).Return(pulledDigest),
+				micHelper.EXPECT().GetImageDigest(&testMic, "some test image").Return(previousDigest),
+				micHelper.EXPECT().SetImageStatus(&testMic, "some test image", kmmv1beta1.ImageExists),
+				micHelper.EXPECT().SetImageVerified(&testMic, "some test image", expectedDigest, gomock.Any()),
 				clnt.EXPECT().Status().Return(statusWriter),
 				statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+				mockImagePuller.EXPECT().DeletePod(ctx, &pullPod).Return(nil),
 			)
-
-			err := mrh.updateStatusByMBSC(ctx, &testMic)
+			err := mrh.updateStatusByPullPods(ctx, &testMic, []v1.Pod{pullPod})
 			Expect(err).To(BeNil())
+			if digestChanged {
+				Expect(fakeRecorder.Events).To(Receive(ContainSubstring("ImageDigestChanged")))
+			} else {
+				Expect(fakeRecorder.Events).To(BeEmpty())
+			}
 		},
-		Entry("sign config does not exists, action Build, status Failed", false, kmmv1beta1.BuildImage, kmmv1beta1.ActionFailure, kmmv1beta1.ImageDoesNotExist),
-		Entry("sign config does not exists, action Sign, status Failed", false, kmmv1beta1.SignImage, kmmv1beta1.ActionFailure, kmmv1beta1.ImageDoesNotExist),
-		Entry("sign config does not exists, action Build, status Succeeded", false, kmmv1beta1.BuildImage, kmmv1beta1.ActionSuccess, kmmv1beta1.ImageExists),
-		Entry("sign config does not exists, action Sign, status Succeeded", false, kmmv1beta1.SignImage, kmmv1beta1.ActionSuccess, kmmv1beta1.ImageExists),
-		Entry("sign config exists, action Build, status Failed", true, kmmv1beta1.BuildImage, kmmv1beta1.ActionFailure, kmmv1beta1.ImageDoesNotExist),
-		Entry("sign config exists, action Sign, status Failed", true, kmmv1beta1.SignImage, kmmv1beta1.ActionFailure, kmmv1beta1.ImageDoesNotExist),
-		Entry("sign config exists, action Build, status Succeeded", true, kmmv1beta1.BuildImage, kmmv1beta1.ActionSuccess, kmmv1beta1.ImageNeedsSigning),
-		Entry("sign config exists, action Sign, status Succeeded", true, kmmv1beta1.SignImage, kmmv1beta1.ActionSuccess, kmmv1beta1.ImageExists),
+		Entry("same digest", "sha256:111", "sha256:111", "sha256:111", false),
+		Entry("new digest", "sha256:111", "sha256:222", "sha256:222", true),
+		Entry("image was not pinned", "", "sha256:222", "", false),
+		Entry("digest is unknown", "sha256:111", "", "sha256:111", false),
 	)
 })
 
-var _ = Describe("processImagesSpecs", func() {
+var _ = Describe("processPrePulls", func() {
 	var (
 		ctrl            *gomock.Controller
 		clnt            *client.MockClient
+		statusWriter    *client.MockStatusWriter
 		mockImagePuller *pod.MockImagePuller
-		mbscHelper      *mbsc.MockMBSC
-		micHelper       *mic.MockMIC
 		mrh             micReconcilerHelper
-		testMic         kmmv1beta1.ModuleImagesConfig
+		micObj          *kmmv1beta1.ModuleImagesConfig
+	)
+
+	const (
+		image       = "example.com/repo:tag"
+		pinnedImage = "example.com/repo:tag@sha256:111"
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
+		statusWriter = client.NewMockStatusWriter(ctrl)
 		mockImagePuller = pod.NewMockImagePuller(ctrl)
-		micHelper = mic.NewMockMIC(ctrl)
-		mbscHelper = mbsc.NewMockMBSC(ctrl)
-		mrh = newMICReconcilerHelper(clnt, mockImagePuller, micHelper, mbscHelper, scheme)
-		testMic = kmmv1beta1.ModuleImagesConfig{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "some name",
-				Namespace: "some namespace",
-			},
+		mrh = newMICReconcilerHelper(clnt, mockImagePuller, mic.New(clnt, scheme), nil, nil, nil, scheme, 0)
+		micObj = &kmmv1beta1.ModuleImagesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "some name", Namespace: "some namespace"},
 			Spec: kmmv1beta1.ModuleImagesConfigSpec{
-				Images: []kmmv1beta1.ModuleImageSpec{
-					{
-						Image: "image 1",
-					},
+				Images:      []kmmv1beta1.ModuleImageSpec{{Image: image}},
+				PrePulls:    []kmmv1beta1.PrePullSpec{{Image: image, Nodes: []string{"node1", "node2"}}},
+				Tolerations: []v1.Toleration{{Key: "some key", Operator: v1.TolerationOpExists}},
+			},
+			Status: kmmv1beta1.ModuleImagesConfigStatus{
+				ImagesStates: []kmmv1beta1.ModuleImageState{
+					{Image: image, Status: kmmv1beta1.ImageExists, Digest: "sha256:111"},
 				},
 			},
 		}
-
 	})
 
 	ctx := context.Background()
-	pullPods := []v1.Pod{}
 
-	DescribeTable("image status empty, pull pod does not exists, create a pull pod",
-		func(buildExists, signExists, skipWaitMissingImage, expectedOneTimePodFlag bool) {
-			if buildExists {
-				testMic.Spec.Images[0].Build = &kmmv1beta1.Build{}
-			}
-			if signExists {
-				testMic.Spec.Images[0].Sign = &kmmv1beta1.Sign{}
-			}
-			testMic.Spec.Images[0].SkipWaitMissingImage = skipWaitMissingImage
-			gomock.InOrder(
-				micHelper.EXPECT().GetImageState(&testMic, "image 1").Return(kmmv1beta1.ImageState("")),
-				mockImagePuller.EXPECT().GetPullPodForImage(pullPods, "image 1").Return(nil),
-				mockImagePuller.EXPECT().CreatePullPod(ctx, "some name", "some namespace", "image 1", expectedOneTimePodFlag,
-					nil, v1.PullPolicy(""), &testMic).Return(nil),
-			)
-			err := mrh.processImagesSpecs(ctx, &testMic, pullPods)
-			Expect(err).To(BeNil())
-		},
-		Entry("build exists, sign missing, skipWait false, expectedFlag true", true, false, false, true),
-		Entry("build missing, sign exists, skipWait false, expectedFlag true", false, true, false, true),
-		Entry("build missing, sign missing, skipWait true, expectedFlag true", false, false, true, true),
-		Entry("build exists, sign exists, skipWait false, expectedFlag true", true, true, false, true),
-		Entry("build exists, sign exists, skipWait true, expectedFlag true", true, true, true, true),
-		Entry("build missing, sign missing, skipWait false, expectedFlag false", false, false, false, false),
-	)
+	prePullPod := func(image, nodeName string, phase v1.PodPhase) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pre-pull-" + nodeName},
+			Spec: v1.PodSpec{
+				NodeName:   nodeName,
+				Containers: []v1.Container{{Image: image}},
+			},
+			Status: v1.PodStatus{Phase: phase},
+		}
+	}
 
-	It("image status empty, pull pod exists, nothing to do", func() {
+	expectPodStatus := func(p v1.Pod, status pod.PullPodStatus) {
+		mockImagePuller.EXPECT().GetPullPodImage(p).Return(p.Spec.Containers[0].Image)
+		mockImagePuller.EXPECT().GetPullPodStatus(&p).Return(status)
+	}
+
+	It("should pull the pinned image onto the nodes", func() {
+		tolerations := append(slices.Clone(micObj.Spec.Tolerations), module.InternalTolerations...)
+
+		mockImagePuller.EXPECT().ListPrePullPods(ctx, "some name", "some namespace").Return(nil, nil)
+		mockImagePuller.EXPECT().CreatePrePullPod(ctx, "some name", "some namespace", pinnedImage, "node1", nil, tolerations, micObj)
+		mockImagePuller.EXPECT().CreatePrePullPod(ctx, "some name", "some namespace", pinnedImage, "node2", nil, tolerations, micObj)
 		gomock.InOrder(
-			micHelper.EXPECT().GetImageState(&testMic, "image 1").Return(kmmv1beta1.ImageState("")),
-			mockImagePuller.EXPECT().GetPullPodForImage(pullPods, "image 1").Return(&v1.Pod{}),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, micObj, gomock.Any()),
 		)
-		err := mrh.processImagesSpecs(ctx, &testMic, pullPods)
-		Expect(err).To(BeNil())
+
+		err := mrh.processPrePulls(ctx, micObj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(micObj.Status.PrePullStates).To(Equal([]kmmv1beta1.PrePullNodeState{
+			{Image: pinnedImage, Node: "node1", State: kmmv1beta1.PrePullPulling},
+			{Image: pinnedImage, Node: "node2", State: kmmv1beta1.PrePullPulling},
+		}))
 	})
 
-	DescribeTable("images in MBSC status exist in MIC spec",
-		func(imageState kmmv1beta1.ImageState, buildExists, signExists, updateMSBC bool, msbcAction kmmv1beta1.BuildOrSignAction) {
-			testMic := kmmv1beta1.ModuleImagesConfig{
-				Spec: kmmv1beta1.ModuleImagesConfigSpec{
-					Images: []kmmv1beta1.ModuleImageSpec{
-						{
-							Image: "image 1",
-						},
-					},
-				},
-			}
-			if buildExists {
+	It("should not pull images that do not exist yet", func() {
+		micObj.Status.ImagesStates[0].Status = kmmv1beta1.ImageNeedsBuilding
+
+		mockImagePuller.EXPECT().ListPrePullPods(ctx, "some name", "some namespace").Return(nil, nil)
+
+		err := mrh.processPrePulls(ctx, micObj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(micObj.Status.PrePullStates).To(BeEmpty())
+	})
+
+	It("should report the completed pulls and delete their pods", func() {
+		pulledPod := prePullPod(pinnedImage, "node1", v1.PodSucceeded)
+		failedPod := prePullPod(pinnedImage, "node2", v1.PodPending)
+
+		mockImagePuller.EXPECT().ListPrePullPods(ctx, "some name", "some namespace").Return([]v1.Pod{pulledPod, failedPod}, nil)
+		expectPodStatus(pulledPod, pod.PullImageSuccess)
+		expectPodStatus(failedPod, pod.PullImageFailed)
+		gomock.InOrder(
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, micObj, gomock.Any()),
+			mockImagePuller.EXPECT().DeletePod(ctx, &pulledPod),
+			mockImagePuller.EXPECT().DeletePod(ctx, &failedPod),
+		)
+
+		err := mrh.processPrePulls(ctx, micObj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(micObj.Status.PrePullStates).To(Equal([]kmmv1beta1.PrePullNodeState{
+			{Image: pinnedImage, Node: "node1", State: kmmv1beta1.PrePullPulled},
+			{Image: pinnedImage, Node: "node2", State: kmmv1beta1.PrePullFailed},
+		}))
+	})
+
+	It("should keep the completed pulls and delete the pods that are not needed anymore", func() {
+		micObj.Status.PrePullStates = []kmmv1beta1.PrePullNodeState{
+			{Image: "example.com/repo:tag@sha256:000", Node: "node1", State: kmmv1beta1.PrePullPulled},
+			{Image: pinnedImage, Node: "node1", State: kmmv1beta1.PrePullPulled},
+			{Image: pinnedImage, Node: "node2", State: kmmv1beta1.PrePullPulling},
+		}
+		runningPod := prePullPod(pinnedImage, "node2", v1.PodRunning)
+		oldPod := prePullPod("example.com/repo:tag@sha256:000", "node2", v1.PodPending)
+
+		mockImagePuller.EXPECT().ListPrePullPods(ctx, "some name", "some namespace").Return([]v1.Pod{runningPod, oldPod}, nil)
+		expectPodStatus(runningPod, pod.PullImageInProcess)
+		mockImagePuller.EXPECT().GetPullPodImage(oldPod).Return(oldPod.Spec.Containers[0].Image)
+		gomock.InOrder(
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, micObj, gomock.Any()),
+			mockImagePuller.EXPECT().DeletePod(ctx, &oldPod),
+		)
+
+		err := mrh.processPrePulls(ctx, micObj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(micObj.Status.PrePullStates).To(Equal([]kmmv1beta1.PrePullNodeState{
+			{Image: pinnedImage, Node: "node1", State: kmmv1beta1.PrePullPulled},
+			{Image: pinnedImage, Node: "node2", State: kmmv1beta1.PrePullPulling},
+		}))
+	})
+
+	It("should return an error if the pre-pull pods cannot be listed", func() {
+		mockImagePuller.EXPECT().ListPrePullPods(ctx, "some name", "some namespace").Return(nil, errors.New("some error"))
+
+		err := mrh.processPrePulls(ctx, micObj)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("verifyExistingImages", func() {
+	var (
+		ctrl            *gomock.Controller
+		mockImagePuller *pod.MockImagePuller
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockImagePuller = pod.NewMockImagePuller(ctrl)
+	})
+
+	ctx := context.Background()
+
+	newMIC := func(states ...kmmv1beta1.ModuleImageState) *kmmv1beta1.ModuleImagesConfig {
+		micObj := &kmmv1beta1.ModuleImagesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "some name", Namespace: "some namespace"},
+		}
+		for _, state := range states {
+			micObj.Spec.Images = append(micObj.Spec.Images, kmmv1beta1.ModuleImageSpec{Image: state.Image})
+		}
+		micObj.Status.ImagesStates = states
+		return micObj
+	}
+
+	verifiedAgo := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: time.Now().Add(-d)}
+	}
+
+	It("should do nothing if the verification is disabled", func() {
+		mrh := newMICReconcilerHelper(nil, mockImagePuller, nil, nil, nil, nil, nil, 0)
+		micObj := newMIC(kmmv1beta1.ModuleImageState{Image: "image1", Status: kmmv1beta1.ImageExists})
+
+		requeueAfter, err := mrh.verifyExistingImages(ctx, micObj, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeZero())
+	})
+
+	It("should verify the image verified the longest time ago", func() {
+		mrh := newMICReconcilerHelper(nil, mockImagePuller, nil, nil, nil, nil, nil, time.Hour)
+		micObj := newMIC(
+			kmmv1beta1.ModuleImageState{Image: "image1", Status: kmmv1beta1.ImageExists, LastVerifiedTime: verifiedAgo(2 * time.Hour)},
+			kmmv1beta1.ModuleImageState{Image: "image2", Status: kmmv1beta1.ImageExists, LastVerifiedTime: verifiedAgo(3 * time.Hour)},
+			kmmv1beta1.ModuleImageState{Image: "image3", Status: kmmv1beta1.ImageExists, LastVerifiedTime: verifiedAgo(30 * time.Minute)},
+			kmmv1beta1.ModuleImageState{Image: "image4", Status: kmmv1beta1.ImageNeedsBuilding},
+		)
+
+		mockImagePuller.EXPECT().CreatePullPod(ctx, "some name", "some namespace", "image2", nil, true, nil, v1.PullAlways, micObj)
+
+		requeueAfter, err := mrh.verifyExistingImages(ctx, micObj, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeNumerically("~", 30*time.Minute, time.Minute))
+	})
+
+	It("should verify the images that were never verified first", func() {
+		mrh := newMICReconcilerHelper(nil, mockImagePuller, nil, nil, nil, nil, nil, time.Hour)
+		micObj := newMIC(
+			kmmv1beta1.ModuleImageState{Image: "image1", Status: kmmv1beta1.ImageExists, LastVerifiedTime: verifiedAgo(3 * time.Hour)},
+			kmmv1beta1.ModuleImageState{Image: "image2", Status: kmmv1beta1.ImageExists},
+		)
+
+		mockImagePuller.EXPECT().CreatePullPod(ctx, "some name", "some namespace", "image2", nil, true, nil, v1.PullAlways, micObj)
+
+		_, err := mrh.verifyExistingImages(ctx, micObj, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should verify the images with an invalid layout and validate their layout", func() {
+		mrh := newMICReconcilerHelper(nil, mockImagePuller, nil, nil, nil, nil, nil, time.Hour)
+		micObj := newMIC(
+			kmmv1beta1.ModuleImageState{Image: "image1", Status: kmmv1beta1.ImageExists, LastVerifiedTime: verifiedAgo(30 * time.Minute)},
+			kmmv1beta1.ModuleImageState{Image: "image2", Status: kmmv1beta1.ImageInvalidLayout, LastVerifiedTime: verifiedAgo(2 * time.Hour)},
+		)
+		micObj.Spec.Images[1].KernelVersion = "some kernel"
+		micObj.Spec.Images[1].DirName = "/opt"
+		micObj.Spec.Images[1].ModuleNames = []string{"some-kmod"}
+
+		expectedLayout := &pod.ImageLayout{DirName: "/opt", KernelVersion: "some kernel", ModuleNames: []string{"some-kmod"}}
+		mockImagePuller.EXPECT().CreatePullPod(ctx, "some name", "some namespace", "image2", expectedLayout, true, nil,
+			v1.PullAlways, micObj)
+
+		_, err := mrh.verifyExistingImages(ctx, micObj, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should pull the images that failed to be pulled again after a backoff, even if the verification is disabled", func() {
+		mrh := newMICReconcilerHelper(nil, mockImagePuller, nil, nil, nil, nil, nil, 0)
+		micObj := newMIC(
+			kmmv1beta1.ModuleImageState{Image: "image1", Status: kmmv1beta1.ImageExists, VerificationFailures: 2,
+				LastVerificationFailureTime: verifiedAgo(time.Minute)},
+			kmmv1beta1.ModuleImageState{Image: "image2", Status: kmmv1beta1.ImageDoesNotExist, VerificationFailures: 1,
+				LastVerificationFailureTime: verifiedAgo(2 * time.Minute)},
+			kmmv1beta1.ModuleImageState{Image: "image3", Status: kmmv1beta1.ImageDoesNotExist, VerificationFailures: 1,
+				LastVerificationFailureTime: verifiedAgo(3 * time.Minute)},
+		)
+		// the images that are built are not looked for in their registry
+		micObj.Spec.Images[2].Build = &kmmv1beta1.Build{}
+
+		mockImagePuller.EXPECT().CreatePullPod(ctx, "some name", "some namespace", "image2", nil, true, nil, v1.PullAlways, micObj)
+
+		requeueAfter, err := mrh.verifyExistingImages(ctx, micObj, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeNumerically("~", time.Minute, 10*time.Second))
+	})
+
+	It("should pull the images with an invalid layout or that failed to be validated after a backoff, even if the verification is disabled", func() {
+		mrh := newMICReconcilerHelper(nil, mockImagePuller, nil, nil, nil, nil, nil, 0)
+		micObj := newMIC(
+			kmmv1beta1.ModuleImageState{Image: "image1", Status: kmmv1beta1.ImageInvalidLayout, VerificationFailures: 2,
+				LastVerificationFailureTime: verifiedAgo(time.Minute)},
+			kmmv1beta1.ModuleImageState{Image: "image2", Status: kmmv1beta1.ImageInvalidLayout, VerificationFailures: 1,
+				LastVerificationFailureTime: verifiedAgo(2 * time.Minute)},
+			kmmv1beta1.ModuleImageState{Image: "image3", Status: kmmv1beta1.ImageNeedsBuilding, VerificationFailures: 1,
+				LastVerificationFailureTime: verifiedAgo(3 * time.Minute)},
+			kmmv1beta1.ModuleImageState{Image: "image4", Status: kmmv1beta1.ImageNeedsSigning},
+		)
+		micObj.Spec.Images[2].Build = &kmmv1beta1.Build{}
+		micObj.Spec.Images[3].Sign = &kmmv1beta1.Sign{}
+
+		mockImagePuller.EXPECT().CreatePullPod(ctx, "some name", "some namespace", "image3", nil, true, nil, v1.PullAlways, micObj)
+
+		requeueAfter, err := mrh.verifyExistingImages(ctx, micObj, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeNumerically("~", time.Minute, 10*time.Second))
+	})
+
+	It("should not verify an image while pull pods are running", func() {
+		mrh := newMICReconcilerHelper(nil, mockImagePuller, nil, nil, nil, nil, nil, time.Hour)
+		micObj := newMIC(kmmv1beta1.ModuleImageState{Image: "image1", Status: kmmv1beta1.ImageExists})
+
+		_, err := mrh.verifyExistingImages(ctx, micObj, []v1.Pod{{}})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return an error if the pull pod cannot be created", func() {
+		mrh := newMICReconcilerHelper(nil, mockImagePuller, nil, nil, nil, nil, nil, time.Hour)
+		micObj := newMIC(kmmv1beta1.ModuleImageState{Image: "image1", Status: kmmv1beta1.ImageExists})
+
+		mockImagePuller.EXPECT().CreatePullPod(ctx, "some name", "some namespace", "image1", nil, true, nil, v1.PullAlways, micObj).
+			Return(errors.New("some error"))
+
+		_, err := mrh.verifyExistingImages(ctx, micObj, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("imageRetryDelay", func() {
+	DescribeTable("should back off exponentially", func(failures int32, expected time.Duration) {
+		Expect(imageRetryDelay(failures)).To(Equal(expected))
+	},
+		Entry(nil, int32(0), time.Minute),
+		Entry(nil, int32(1), time.Minute),
+		Entry(nil, int32(3), 4*time.Minute),
+		Entry(nil, int32(100), time.Hour),
+	)
+})
+
+var _ = Describe("updateStatusByMBSC", func() {
+	var (
+		ctrl            *gomock.Controller
+		clnt            *client.MockClient
+		statusWriter    *client.MockStatusWriter
+		mbscHelper      *mbsc.MockMBSC
+		micHelper       *mic.MockMIC
+		registryAPI     *registry.MockRegistry
+		mockImagePuller *pod.MockImagePuller
+		mrh             micReconcilerHelper
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		statusWriter = client.NewMockStatusWriter(ctrl)
+		micHelper = mic.NewMockMIC(ctrl)
+		mbscHelper = mbsc.NewMockMBSC(ctrl)
+		registryAPI = registry.NewMockRegistry(ctrl)
+		mockImagePuller = pod.NewMockImagePuller(ctrl)
+		mrh = newMICReconcilerHelper(clnt, mockImagePuller, micHelper, mbscHelper, registryAPI, nil, nil, 0)
+	})
+
+	ctx := context.Background()
+	testMic := kmmv1beta1.ModuleImagesConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "some name",
+			Namespace: "some namespace",
+		},
+		Spec: kmmv1beta1.ModuleImagesConfigSpec{
+			Images: []kmmv1beta1.ModuleImageSpec{
+				{
+					Image: "image 1",
+					Build: &kmmv1beta1.Build{},
+				},
+				{
+
+					Image: "image 2",
+				},
+			},
+		},
+	}
+
+	It("failed to get MBSC", func() {
+		mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(nil, fmt.Errorf("some error"))
+		err := mrh.updateStatusByMBSC(ctx, &testMic, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("MBSC does not exists", func() {
+		mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(nil, nil)
+		err := mrh.updateStatusByMBSC(ctx, &testMic, nil)
+		Expect(err).To(BeNil())
+	})
+
+	It("Image in MBSC status does not exists in MIC spec", func() {
+		testMBSC := kmmv1beta1.ModuleBuildSignConfig{
+			Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+				Images: []kmmv1beta1.BuildSignImageState{
+					{
+						Image: "some image",
+					},
+				},
+			},
+		}
+		gomock.InOrder(
+			mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(&testMBSC, nil),
+			micHelper.EXPECT().GetModuleImageSpec(&testMic, "some image").Return(nil),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+		)
+		err := mrh.updateStatusByMBSC(ctx, &testMic, nil)
+		Expect(err).To(BeNil())
+	})
+
+	DescribeTable("image has status in MBSC and spec in MIC",
+		func(signExists bool, mbscImageAction kmmv1beta1.BuildOrSignAction, mbscImageStatus kmmv1beta1.BuildOrSignStatus,
+			expectedMICImageState kmmv1beta1.ImageState) {
+			testMBSC := kmmv1beta1.ModuleBuildSignConfig{
+				Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+					Images: []kmmv1beta1.BuildSignImageState{
+						{
+							Image:  "some image",
+							Status: mbscImageStatus,
+							Action: mbscImageAction,
+						},
+					},
+				},
+			}
+			imageSpec := kmmv1beta1.ModuleImageSpec{Image: "some image"}
+			if signExists {
+				imageSpec.Sign = &kmmv1beta1.Sign{}
+			}
+			calls := []any{
+				mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(&testMBSC, nil),
+				micHelper.EXPECT().GetModuleImageSpec(&testMic, "some image").Return(&imageSpec),
+			}
+			if expectedMICImageState == kmmv1beta1.ImageExists {
+				calls = append(calls,
+					micHelper.EXPECT().GetImageState(&testMic, "some image").Return(kmmv1beta1.ImageNeedsBuilding),
+					micHelper.EXPECT().SetImageStatus(&testMic, "some image", expectedMICImageState),
+					registryAPI.EXPECT().GetDigest(ctx, "some image", nil, testMic.Namespace, nil).Return("sha256:111", nil),
+					micHelper.EXPECT().SetImageVerified(&testMic, "some image", "sha256:111", gomock.Any()),
+				)
+			} else {
+				calls = append(calls, micHelper.EXPECT().SetImageStatus(&testMic, "some image", expectedMICImageState))
+			}
+			calls = append(calls,
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+			)
+			gomock.InOrder(calls...)
+
+			err := mrh.updateStatusByMBSC(ctx, &testMic, nil)
+			Expect(err).To(BeNil())
+		},
+		Entry("sign config does not exists, action Build, status Failed", false, kmmv1beta1.BuildImage, kmmv1beta1.ActionFailure, kmmv1beta1.ImageDoesNotExist),
+		Entry("sign config does not exists, action Sign, status Failed", false, kmmv1beta1.SignImage, kmmv1beta1.ActionFailure, kmmv1beta1.ImageDoesNotExist),
+		Entry("sign config does not exists, action Build, status Succeeded", false, kmmv1beta1.BuildImage, kmmv1beta1.ActionSuccess, kmmv1beta1.ImageExists),
+		Entry("sign config does not exists, action Sign, status Succeeded", false, kmmv1beta1.SignImage, kmmv1beta1.ActionSuccess, kmmv1beta1.ImageExists),
+		Entry("sign config exists, action Build, status Failed", true, kmmv1beta1.BuildImage, kmmv1beta1.ActionFailure, kmmv1beta1.ImageDoesNotExist),
+		Entry("sign config exists, action Sign, status Failed", true, kmmv1beta1.SignImage, kmmv1beta1.ActionFailure, kmmv1beta1.ImageDoesNotExist),
+		Entry("sign config exists, action Build, status Succeeded", true, kmmv1beta1.BuildImage, kmmv1beta1.ActionSuccess, kmmv1beta1.ImageNeedsSigning),
+		Entry("sign config exists, action Sign, status Succeeded", true, kmmv1beta1.SignImage, kmmv1beta1.ActionSuccess, kmmv1beta1.ImageExists),
+	)
+
+	DescribeTable("should keep building or signing an image whose failed action is retried",
+		func(mbscImageAction kmmv1beta1.BuildOrSignAction, expectedMICImageState kmmv1beta1.ImageState) {
+			testMBSC := kmmv1beta1.ModuleBuildSignConfig{
+				Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+					Images: []kmmv1beta1.BuildSignImageState{
+						{
+							Image:           "some image",
+							Status:          kmmv1beta1.ActionFailure,
+							Action:          mbscImageAction,
+							FailedAttempts:  1,
+							NextAttemptTime: &metav1.Time{Time: time.Now().Add(time.Minute)},
+						},
+					},
+				},
+			}
+			imageSpec := kmmv1beta1.ModuleImageSpec{Image: "some image", Sign: &kmmv1beta1.Sign{}}
+			gomock.InOrder(
+				mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(&testMBSC, nil),
+				micHelper.EXPECT().GetModuleImageSpec(&testMic, "some image").Return(&imageSpec),
+				micHelper.EXPECT().SetImageStatus(&testMic, "some image", expectedMICImageState),
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+			)
+
+			err := mrh.updateStatusByMBSC(ctx, &testMic, nil)
+			Expect(err).To(BeNil())
+		},
+		Entry("build retried", kmmv1beta1.BuildImage, kmmv1beta1.ImageNeedsBuilding),
+		Entry("sign retried", kmmv1beta1.SignImage, kmmv1beta1.ImageNeedsSigning),
+	)
+
+	It("should not resolve the digest of an image that already exists again", func() {
+		testMBSC := kmmv1beta1.ModuleBuildSignConfig{
+			Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+				Images: []kmmv1beta1.BuildSignImageState{
+					{Image: "some image", Status: kmmv1beta1.ActionSuccess, Action: kmmv1beta1.BuildImage},
+				},
+			},
+		}
+		imageSpec := kmmv1beta1.ModuleImageSpec{Image: "some image"}
+		gomock.InOrder(
+			mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(&testMBSC, nil),
+			micHelper.EXPECT().GetModuleImageSpec(&testMic, "some image").Return(&imageSpec),
+			micHelper.EXPECT().GetImageState(&testMic, "some image").Return(kmmv1beta1.ImageExists),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+		)
+
+		err := mrh.updateStatusByMBSC(ctx, &testMic, nil)
+		Expect(err).To(BeNil())
+	})
+
+	Context("the layout of the pushed image is known", func() {
+		var (
+			testMBSC  kmmv1beta1.ModuleBuildSignConfig
+			imageSpec kmmv1beta1.ModuleImageSpec
+		)
+
+		BeforeEach(func() {
+			testMBSC = kmmv1beta1.ModuleBuildSignConfig{
+				Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+					Images: []kmmv1beta1.BuildSignImageState{
+						{Image: "some image", Status: kmmv1beta1.ActionSuccess, Action: kmmv1beta1.BuildImage},
+					},
+				},
+			}
+			imageSpec = kmmv1beta1.ModuleImageSpec{
+				Image:         "some image",
+				KernelVersion: "some kernel",
+				ModuleNames:   []string{"some-kmod"},
+			}
+		})
+
+		It("should validate the layout of the image before marking it as existing", func() {
+			expectedLayout := &pod.ImageLayout{KernelVersion: "some kernel", ModuleNames: []string{"some-kmod"}}
+			gomock.InOrder(
+				mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(&testMBSC, nil),
+				micHelper.EXPECT().GetModuleImageSpec(&testMic, "some image").Return(&imageSpec),
+				micHelper.EXPECT().GetImageState(&testMic, "some image").Return(kmmv1beta1.ImageNeedsBuilding),
+				micHelper.EXPECT().GetImageVerificationFailures(&testMic, "some image").Return(int32(0)),
+				mockImagePuller.EXPECT().GetPullPodForImage(nil, "some image").Return(nil),
+				mockImagePuller.EXPECT().CreatePullPod(ctx, testMic.Name, testMic.Namespace, "some image", expectedLayout,
+					true, nil, v1.PullAlways, &testMic),
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+			)
+
+			err := mrh.updateStatusByMBSC(ctx, &testMic, nil)
+			Expect(err).To(BeNil())
+		})
+
+		It("should not validate the layout of the image while it is being validated", func() {
+			pullPods := []v1.Pod{{}}
+			gomock.InOrder(
+				mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(&testMBSC, nil),
+				micHelper.EXPECT().GetModuleImageSpec(&testMic, "some image").Return(&imageSpec),
+				micHelper.EXPECT().GetImageState(&testMic, "some image").Return(kmmv1beta1.ImageNeedsBuilding),
+				micHelper.EXPECT().GetImageVerificationFailures(&testMic, "some image").Return(int32(0)),
+				mockImagePuller.EXPECT().GetPullPodForImage(pullPods, "some image").Return(&pullPods[0]),
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+			)
+
+			err := mrh.updateStatusByMBSC(ctx, &testMic, pullPods)
+			Expect(err).To(BeNil())
+		})
+
+		It("should leave the validations that failed to verifyExistingImages", func() {
+			gomock.InOrder(
+				mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(&testMBSC, nil),
+				micHelper.EXPECT().GetModuleImageSpec(&testMic, "some image").Return(&imageSpec),
+				micHelper.EXPECT().GetImageState(&testMic, "some image").Return(kmmv1beta1.ImageNeedsBuilding),
+				micHelper.EXPECT().GetImageVerificationFailures(&testMic, "some image").Return(int32(1)),
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+			)
+
+			err := mrh.updateStatusByMBSC(ctx, &testMic, nil)
+			Expect(err).To(BeNil())
+		})
+
+		It("should keep the image marked with an invalid layout", func() {
+			gomock.InOrder(
+				mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(&testMBSC, nil),
+				micHelper.EXPECT().GetModuleImageSpec(&testMic, "some image").Return(&imageSpec),
+				micHelper.EXPECT().GetImageState(&testMic, "some image").Return(kmmv1beta1.ImageInvalidLayout),
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+			)
+
+			err := mrh.updateStatusByMBSC(ctx, &testMic, nil)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return an error if the pull pod cannot be created", func() {
+			gomock.InOrder(
+				mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(&testMBSC, nil),
+				micHelper.EXPECT().GetModuleImageSpec(&testMic, "some image").Return(&imageSpec),
+				micHelper.EXPECT().GetImageState(&testMic, "some image").Return(kmmv1beta1.ImageNeedsBuilding),
+				micHelper.EXPECT().GetImageVerificationFailures(&testMic, "some image").Return(int32(0)),
+				mockImagePuller.EXPECT().GetPullPodForImage(nil, "some image").Return(nil),
+				mockImagePuller.EXPECT().CreatePullPod(ctx, testMic.Name, testMic.Namespace, "some image", gomock.Any(),
+					true, nil, v1.PullAlways, &testMic).Return(errors.New("some error")),
+				clnt.EXPECT().Status().Return(statusWriter),
+				statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+			)
+
+			err := mrh.updateStatusByMBSC(ctx, &testMic, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("should mark the image as existing if its digest cannot be resolved", func() {
+		testMBSC := kmmv1beta1.ModuleBuildSignConfig{
+			Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+				Images: []kmmv1beta1.BuildSignImageState{
+					{Image: "some image", Status: kmmv1beta1.ActionSuccess, Action: kmmv1beta1.SignImage},
+				},
+			},
+		}
+		imageSpec := kmmv1beta1.ModuleImageSpec{Image: "some image"}
+		gomock.InOrder(
+			mbscHelper.EXPECT().Get(ctx, testMic.Name, testMic.Namespace).Return(&testMBSC, nil),
+			micHelper.EXPECT().GetModuleImageSpec(&testMic, "some image").Return(&imageSpec),
+			micHelper.EXPECT().GetImageState(&testMic, "some image").Return(kmmv1beta1.ImageNeedsSigning),
+			micHelper.EXPECT().SetImageStatus(&testMic, "some image", kmmv1beta1.ImageExists),
+			registryAPI.EXPECT().GetDigest(ctx, "some image", nil, testMic.Namespace, nil).Return("", errors.New("some error")),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &testMic, gomock.Any()),
+		)
+
+		err := mrh.updateStatusByMBSC(ctx, &testMic, nil)
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("processImagesSpecs", func() {
+	var (
+		ctrl            *gomock.Controller
+		clnt            *client.MockClient
+		mockImagePuller *pod.MockImagePuller
+		mbscHelper      *mbsc.MockMBSC
+		micHelper       *mic.MockMIC
+		mrh             micReconcilerHelper
+		testMic         kmmv1beta1.ModuleImagesConfig
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mockImagePuller = pod.NewMockImagePuller(ctrl)
+		micHelper = mic.NewMockMIC(ctrl)
+		mbscHelper = mbsc.NewMockMBSC(ctrl)
+		mrh = newMICReconcilerHelper(clnt, mockImagePuller, micHelper, mbscHelper, nil, nil, scheme, 0)
+		testMic = kmmv1beta1.ModuleImagesConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "some name",
+				Namespace: "some namespace",
+			},
+			Spec: kmmv1beta1.ModuleImagesConfigSpec{
+				Images: []kmmv1beta1.ModuleImageSpec{
+					{
+						Image: "image 1",
+					},
+				},
+			},
+		}
+
+	})
+
+	ctx := context.Background()
+	pullPods := []v1.Pod{}
+
+	DescribeTable("image status empty, pull pod does not exists, create a pull pod",
+		func(buildExists, signExists, skipWaitMissingImage, expectedOneTimePodFlag bool) {
+			if buildExists {
+				testMic.Spec.Images[0].Build = &kmmv1beta1.Build{}
+			}
+			if signExists {
+				testMic.Spec.Images[0].Sign = &kmmv1beta1.Sign{}
+			}
+			testMic.Spec.Images[0].SkipWaitMissingImage = skipWaitMissingImage
+			gomock.InOrder(
+				mbscHelper.EXPECT().UpdateImagesSpecs(ctx, &testMic).Return(nil),
+				micHelper.EXPECT().GetImageState(&testMic, "image 1").Return(kmmv1beta1.ImageState("")),
+				mockImagePuller.EXPECT().GetPullPodForImage(pullPods, "image 1").Return(nil),
+				mockImagePuller.EXPECT().CreatePullPod(ctx, "some name", "some namespace", "image 1", nil, expectedOneTimePodFlag,
+					nil, v1.PullPolicy(""), &testMic).Return(nil),
+			)
+			err := mrh.processImagesSpecs(ctx, &testMic, pullPods, sets.New[string]())
+			Expect(err).To(BeNil())
+		},
+		Entry("build exists, sign missing, skipWait false, expectedFlag true", true, false, false, true),
+		Entry("build missing, sign exists, skipWait false, expectedFlag true", false, true, false, true),
+		Entry("build missing, sign missing, skipWait true, expectedFlag true", false, false, true, true),
+		Entry("build exists, sign exists, skipWait false, expectedFlag true", true, true, false, true),
+		Entry("build exists, sign exists, skipWait true, expectedFlag true", true, true, true, true),
+		Entry("build missing, sign missing, skipWait false, expectedFlag false", false, false, false, false),
+	)
+
+	It("should return an error if the MBSC images specs could not be updated", func() {
+		mbscHelper.EXPECT().UpdateImagesSpecs(ctx, &testMic).Return(errors.New("some error"))
+
+		err := mrh.processImagesSpecs(ctx, &testMic, pullPods, sets.New[string]())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should neither pull nor build the images built by another MIC", func() {
+		mbscHelper.EXPECT().UpdateImagesSpecs(ctx, &testMic).Return(nil)
+
+		err := mrh.processImagesSpecs(ctx, &testMic, pullPods, sets.New("image 1"))
+		Expect(err).To(BeNil())
+	})
+
+	It("image status empty, pull pod exists, nothing to do", func() {
+		gomock.InOrder(
+			mbscHelper.EXPECT().UpdateImagesSpecs(ctx, &testMic).Return(nil),
+			micHelper.EXPECT().GetImageState(&testMic, "image 1").Return(kmmv1beta1.ImageState("")),
+			mockImagePuller.EXPECT().GetPullPodForImage(pullPods, "image 1").Return(&v1.Pod{}),
+		)
+		err := mrh.processImagesSpecs(ctx, &testMic, pullPods, sets.New[string]())
+		Expect(err).To(BeNil())
+	})
+
+	DescribeTable("images in MBSC status exist in MIC spec",
+		func(imageState kmmv1beta1.ImageState, buildExists, signExists, updateMSBC bool, msbcAction kmmv1beta1.BuildOrSignAction) {
+			testMic := kmmv1beta1.ModuleImagesConfig{
+				Spec: kmmv1beta1.ModuleImagesConfigSpec{
+					Images: []kmmv1beta1.ModuleImageSpec{
+						{
+							Image: "image 1",
+						},
+					},
+				},
+			}
+			if buildExists {
 				testMic.Spec.Images[0].Build = &kmmv1beta1.Build{}
 			}
 			if signExists {
 				testMic.Spec.Images[0].Sign = &kmmv1beta1.Sign{}
 			}
+			mbscHelper.EXPECT().UpdateImagesSpecs(ctx, &testMic).Return(nil)
 			micHelper.EXPECT().GetImageState(&testMic, "image 1").Return(imageState)
 
 			if updateMSBC {
 				mbscHelper.EXPECT().CreateOrPatch(ctx, &testMic, &testMic.Spec.Images[0], msbcAction).Return(nil)
 			}
 
-			err := mrh.processImagesSpecs(ctx, &testMic, pullPods)
+			err := mrh.processImagesSpecs(ctx, &testMic, pullPods, sets.New[string]())
 			Expect(err).To(BeNil())
 		},
 		Entry("image state ImageDoesNotExist, no build or sign configs, do nothing",
@@ -608,3 +1361,401 @@ var _ = Describe("processImagesSpecs", func() {
 			kmmv1beta1.ImageNeedsSigning, false, false, true, kmmv1beta1.SignImage),
 	)
 })
+
+var _ = Describe("processSharedImages", func() {
+	var (
+		ctrl       *gomock.Controller
+		clnt       *client.MockClient
+		statusWrt  *client.MockStatusWriter
+		micHelper  *mic.MockMIC
+		mbscHelper *mbsc.MockMBSC
+		mrh        micReconcilerHelper
+		testMic    kmmv1beta1.ModuleImagesConfig
+	)
+
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		statusWrt = client.NewMockStatusWriter(ctrl)
+		micHelper = mic.NewMockMIC(ctrl)
+		mbscHelper = mbsc.NewMockMBSC(ctrl)
+		mrh = newMICReconcilerHelper(clnt, nil, micHelper, mbscHelper, nil, nil, scheme, 0)
+		testMic = kmmv1beta1.ModuleImagesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "some name", Namespace: "some namespace"},
+			Spec: kmmv1beta1.ModuleImagesConfigSpec{
+				Images: []kmmv1beta1.ModuleImageSpec{{Image: "image 1"}, {Image: "image 2"}},
+			},
+		}
+	})
+
+	It("should not look the existing images up", func() {
+		micHelper.EXPECT().GetImageState(&testMic, "image 1").Return(kmmv1beta1.ImageExists)
+		micHelper.EXPECT().GetImageState(&testMic, "image 2").Return(kmmv1beta1.ImageExists)
+
+		sharedBuilds, err := mrh.processSharedImages(ctx, &testMic)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sharedBuilds).To(BeEmpty())
+	})
+
+	It("should mark the images existing according to another MIC as existing", func() {
+		verifiedTime := metav1.Now()
+		gomock.InOrder(
+			micHelper.EXPECT().GetImageState(&testMic, "image 1").Return(kmmv1beta1.ImageState("")),
+			micHelper.EXPECT().GetSharedImageState(ctx, &testMic, "image 1").Return(&mic.SharedImageState{
+				Verified: &kmmv1beta1.ModuleImageState{
+					Image:            "image 1",
+					Status:           kmmv1beta1.ImageExists,
+					Digest:           "sha256:111",
+					LastVerifiedTime: &verifiedTime,
+				},
+			}, nil),
+			micHelper.EXPECT().SetImageStatus(&testMic, "image 1", kmmv1beta1.ImageExists),
+			micHelper.EXPECT().SetImageVerified(&testMic, "image 1", "sha256:111", verifiedTime),
+			micHelper.EXPECT().GetImageState(&testMic, "image 2").Return(kmmv1beta1.ImageNeedsBuilding),
+			micHelper.EXPECT().GetSharedImageState(ctx, &testMic, "image 2").Return(&mic.SharedImageState{
+				Verified: &kmmv1beta1.ModuleImageState{Image: "image 2", Status: kmmv1beta1.ImageExists},
+			}, nil),
+			micHelper.EXPECT().SetImageStatus(&testMic, "image 2", kmmv1beta1.ImageExists),
+			clnt.EXPECT().Status().Return(statusWrt),
+			statusWrt.EXPECT().Patch(ctx, &testMic, gomock.Any()).Return(nil),
+		)
+
+		sharedBuilds, err := mrh.processSharedImages(ctx, &testMic)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sharedBuilds).To(BeEmpty())
+	})
+
+	It("should mark the images with an invalid layout according to another MIC as such", func() {
+		fakeRecorder := record.NewFakeRecorder(10)
+		mrh = newMICReconcilerHelper(clnt, nil, micHelper, mbscHelper, nil, fakeRecorder, scheme, 0)
+		findings := []string{"finding 1", "finding 2"}
+		gomock.InOrder(
+			micHelper.EXPECT().GetImageState(&testMic, "image 1").Return(kmmv1beta1.ImageState("")),
+			micHelper.EXPECT().GetSharedImageState(ctx, &testMic, "image 1").Return(&mic.SharedImageState{
+				Verified: &kmmv1beta1.ModuleImageState{
+					Image:          "image 1",
+					Status:         kmmv1beta1.ImageInvalidLayout,
+					LayoutFindings: findings,
+				},
+			}, nil),
+			micHelper.EXPECT().SetImageStatus(&testMic, "image 1", kmmv1beta1.ImageInvalidLayout),
+			micHelper.EXPECT().SetImageLayoutFindings(&testMic, "image 1", findings),
+			micHelper.EXPECT().SetImageVerificationFailed(&testMic, "image 1", gomock.Any()).Return(int32(1)),
+			micHelper.EXPECT().GetImageState(&testMic, "image 2").Return(kmmv1beta1.ImageInvalidLayout),
+			clnt.EXPECT().Status().Return(statusWrt),
+			statusWrt.EXPECT().Patch(ctx, &testMic, gomock.Any()).Return(nil),
+		)
+
+		sharedBuilds, err := mrh.processSharedImages(ctx, &testMic)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sharedBuilds).To(BeEmpty())
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("finding 1; finding 2")))
+	})
+
+	It("should return the images built by another MIC", func() {
+		gomock.InOrder(
+			micHelper.EXPECT().GetImageState(&testMic, "image 1").Return(kmmv1beta1.ImageNeedsBuilding),
+			micHelper.EXPECT().GetSharedImageState(ctx, &testMic, "image 1").Return(&mic.SharedImageState{
+				Builder: &types.NamespacedName{Namespace: "other namespace", Name: "other name"},
+			}, nil),
+			micHelper.EXPECT().GetImageState(&testMic, "image 2").Return(kmmv1beta1.ImageState("")),
+			micHelper.EXPECT().GetSharedImageState(ctx, &testMic, "image 2").Return(&mic.SharedImageState{}, nil),
+		)
+
+		sharedBuilds, err := mrh.processSharedImages(ctx, &testMic)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sharedBuilds).To(Equal(sets.New("image 1")))
+	})
+
+	It("should return an error if the shared state of an image cannot be determined", func() {
+		gomock.InOrder(
+			micHelper.EXPECT().GetImageState(&testMic, "image 1").Return(kmmv1beta1.ImageState("")),
+			micHelper.EXPECT().GetSharedImageState(ctx, &testMic, "image 1").Return(nil, errors.New("some error")),
+		)
+
+		_, err := mrh.processSharedImages(ctx, &testMic)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("collectUnusedImages", func() {
+	var (
+		ctrl        *gomock.Controller
+		clnt        *client.MockClient
+		statusWrt   *client.MockStatusWriter
+		micHelper   *mic.MockMIC
+		mbscHelper  *mbsc.MockMBSC
+		registryAPI *registry.MockRegistry
+		mrh         micReconcilerHelper
+		testMic     kmmv1beta1.ModuleImagesConfig
+		unusedSince metav1.Time
+	)
+
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		statusWrt = client.NewMockStatusWriter(ctrl)
+		micHelper = mic.NewMockMIC(ctrl)
+		mbscHelper = mbsc.NewMockMBSC(ctrl)
+		registryAPI = registry.NewMockRegistry(ctrl)
+		mrh = newMICReconcilerHelper(clnt, nil, micHelper, mbscHelper, registryAPI, record.NewFakeRecorder(10), scheme, 0)
+		unusedSince = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		testMic = kmmv1beta1.ModuleImagesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "some name", Namespace: "some namespace"},
+			Spec: kmmv1beta1.ModuleImagesConfigSpec{
+				Images:          []kmmv1beta1.ModuleImageSpec{{Image: "image 1", KernelVersion: "kernel 1"}},
+				ImageRepoSecret: &v1.LocalObjectReference{Name: "pull-secret"},
+				ImageGC: &kmmv1beta1.ImageGCSpec{
+					RetentionPeriod:    metav1.Duration{Duration: time.Hour},
+					DeleteFromRegistry: true,
+				},
+			},
+			Status: kmmv1beta1.ModuleImagesConfigStatus{
+				ImagesStates: []kmmv1beta1.ModuleImageState{
+					{Image: "image 1", Status: kmmv1beta1.ImageExists, UnusedSince: &unusedSince},
+					{Image: "image 2", Status: kmmv1beta1.ImageExists, KernelVersion: "kernel 2", UnusedSince: &unusedSince},
+					{Image: "image 3", Status: kmmv1beta1.ImageExists, KernelVersion: "kernel 3", UnusedSince: &unusedSince},
+					{Image: "image 4", Status: kmmv1beta1.ImageExists, KernelVersion: "kernel 4"},
+				},
+			},
+		}
+	})
+
+	// expectKernelsInUse expects the nodes and the PreflightValidations to be listed, and returns them with the
+	// kernels passed.
+	expectKernelsInUse := func(nodeKernel, pvKernel string) []any {
+		return []any{
+			clnt.EXPECT().List(ctx, &v1.NodeList{}).DoAndReturn(
+				func(_ interface{}, list *v1.NodeList, _ ...ctrlclient.ListOption) error {
+					list.Items = []v1.Node{{Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KernelVersion: nodeKernel}}}}
+					return nil
+				},
+			),
+			clnt.EXPECT().List(ctx, &v1beta2.PreflightValidationList{}).DoAndReturn(
+				func(_ interface{}, list *v1beta2.PreflightValidationList, _ ...ctrlclient.ListOption) error {
+					list.Items = []v1beta2.PreflightValidation{{Spec: v1beta2.PreflightValidationSpec{KernelVersion: pvKernel}}}
+					return nil
+				},
+			),
+		}
+	}
+
+	It("should do nothing if the garbage collection is disabled", func() {
+		testMic.Spec.ImageGC = nil
+
+		requeueAfter, err := mrh.collectUnusedImages(ctx, &testMic)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeZero())
+	})
+
+	It("should clear the dry-run results if the garbage collection is disabled", func() {
+		testMic.Spec.ImageGC = nil
+		testMic.Status.ImageGCDryRun = []kmmv1beta1.CollectedImage{{Image: "image 2"}}
+
+		gomock.InOrder(
+			clnt.EXPECT().Status().Return(statusWrt),
+			statusWrt.EXPECT().Patch(ctx, &testMic, gomock.Any()).Return(nil),
+		)
+
+		_, err := mrh.collectUnusedImages(ctx, &testMic)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testMic.Status.ImageGCDryRun).To(BeNil())
+	})
+
+	It("should only report the images to collect in dry-run mode", func() {
+		testMic.Spec.ImageGC.DryRun = true
+		mbscObj := &kmmv1beta1.ModuleBuildSignConfig{}
+		otherMic := kmmv1beta1.ModuleImagesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "other name", Namespace: "some namespace"},
+		}
+
+		gomock.InOrder(append(expectKernelsInUse("some kernel", "other kernel"),
+			mbscHelper.EXPECT().Get(ctx, "some name", "some namespace").Return(mbscObj, nil),
+			mbscHelper.EXPECT().GetImageSpec(mbscObj, "image 2").Return(&kmmv1beta1.ModuleBuildSignSpec{}),
+			micHelper.EXPECT().ListByImage(ctx, "image 2").Return([]kmmv1beta1.ModuleImagesConfig{testMic}, nil),
+			mbscHelper.EXPECT().GetImageSpec(mbscObj, "image 3").Return(&kmmv1beta1.ModuleBuildSignSpec{}),
+			micHelper.EXPECT().ListByImage(ctx, "image 3").Return([]kmmv1beta1.ModuleImagesConfig{testMic, otherMic}, nil),
+			clnt.EXPECT().Status().Return(statusWrt),
+			statusWrt.EXPECT().Patch(ctx, &testMic, gomock.Any()).Return(nil),
+		)...)
+
+		requeueAfter, err := mrh.collectUnusedImages(ctx, &testMic)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+		Expect(testMic.Status.ImageGCDryRun).To(Equal([]kmmv1beta1.CollectedImage{
+			{Image: "image 2", DeleteFromRegistry: true},
+			{Image: "image 3"},
+		}))
+		Expect(testMic.Status.ImagesStates).To(HaveLen(4))
+		Expect(testMic.Status.ImagesStates[0].UnusedSince).To(BeNil())
+		Expect(testMic.Status.ImagesStates[0].KernelVersion).To(Equal("kernel 1"))
+		Expect(testMic.Status.ImagesStates[3].UnusedSince).NotTo(BeNil())
+	})
+
+	It("should collect the images unused for longer than the retention period", func() {
+		mbscObj := &kmmv1beta1.ModuleBuildSignConfig{}
+		tlsOptions := &kmmv1beta1.TLSOptions{Insecure: true}
+
+		gomock.InOrder(append(expectKernelsInUse("some kernel", "other kernel"),
+			mbscHelper.EXPECT().Get(ctx, "some name", "some namespace").Return(mbscObj, nil),
+			mbscHelper.EXPECT().GetImageSpec(mbscObj, "image 2").Return(&kmmv1beta1.ModuleBuildSignSpec{}),
+			micHelper.EXPECT().ListByImage(ctx, "image 2").Return([]kmmv1beta1.ModuleImagesConfig{testMic}, nil),
+			mbscHelper.EXPECT().GetImageSpec(mbscObj, "image 3").Return(nil),
+			micHelper.EXPECT().ListImages(ctx).Return([]string{"image 4", "image 5"}, nil),
+			mbscHelper.EXPECT().GetImageSpec(mbscObj, "image 2").Return(&kmmv1beta1.ModuleBuildSignSpec{
+				ModuleImageSpec: kmmv1beta1.ModuleImageSpec{RegistryTLS: tlsOptions},
+			}),
+			registryAPI.EXPECT().DeleteImage(ctx, "image 2", tlsOptions, "some namespace", testMic.Spec.ImageRepoSecret,
+				[]string{"image 4", "image 5"}).Return(nil),
+			mbscHelper.EXPECT().RemoveImages(ctx, mbscObj, sets.New("image 2", "image 3")).Return(nil),
+			clnt.EXPECT().Status().Return(statusWrt),
+			statusWrt.EXPECT().Patch(ctx, &testMic, gomock.Any()).Return(nil),
+		)...)
+
+		_, err := mrh.collectUnusedImages(ctx, &testMic)
+		Expect(err).NotTo(HaveOccurred())
+		images := make([]string, 0, len(testMic.Status.ImagesStates))
+		for _, imageState := range testMic.Status.ImagesStates {
+			images = append(images, imageState.Image)
+		}
+		Expect(images).To(Equal([]string{"image 1", "image 4"}))
+	})
+
+	It("should keep the images that could not be deleted from their registry", func() {
+		testMic.Status.ImagesStates = testMic.Status.ImagesStates[1:2]
+		mbscObj := &kmmv1beta1.ModuleBuildSignConfig{}
+
+		gomock.InOrder(append(expectKernelsInUse("some kernel", "other kernel"),
+			mbscHelper.EXPECT().Get(ctx, "some name", "some namespace").Return(mbscObj, nil),
+			mbscHelper.EXPECT().GetImageSpec(mbscObj, "image 2").Return(&kmmv1beta1.ModuleBuildSignSpec{}),
+			micHelper.EXPECT().ListByImage(ctx, "image 2").Return(nil, nil),
+			micHelper.EXPECT().ListImages(ctx).Return(nil, nil),
+			mbscHelper.EXPECT().GetImageSpec(mbscObj, "image 2").Return(&kmmv1beta1.ModuleBuildSignSpec{}),
+			registryAPI.EXPECT().DeleteImage(ctx, "image 2", nil, "some namespace", testMic.Spec.ImageRepoSecret, nil).
+				Return(errors.New("some error")),
+			clnt.EXPECT().Status().Return(statusWrt),
+			statusWrt.EXPECT().Patch(ctx, &testMic, gomock.Any()).Return(nil),
+		)...)
+
+		_, err := mrh.collectUnusedImages(ctx, &testMic)
+		Expect(err).To(HaveOccurred())
+		Expect(testMic.Status.ImagesStates).To(HaveLen(1))
+	})
+
+	It("should collect the images whose manifest is still in use without deleting them from their registry", func() {
+		testMic.Status.ImagesStates = testMic.Status.ImagesStates[1:2]
+		mbscObj := &kmmv1beta1.ModuleBuildSignConfig{}
+
+		gomock.InOrder(append(expectKernelsInUse("some kernel", "other kernel"),
+			mbscHelper.EXPECT().Get(ctx, "some name", "some namespace").Return(mbscObj, nil),
+			mbscHelper.EXPECT().GetImageSpec(mbscObj, "image 2").Return(&kmmv1beta1.ModuleBuildSignSpec{}),
+			micHelper.EXPECT().ListByImage(ctx, "image 2").Return(nil, nil),
+			micHelper.EXPECT().ListImages(ctx).Return([]string{"image 2 with another tag"}, nil),
+			mbscHelper.EXPECT().GetImageSpec(mbscObj, "image 2").Return(&kmmv1beta1.ModuleBuildSignSpec{}),
+			registryAPI.EXPECT().DeleteImage(ctx, "image 2", nil, "some namespace", testMic.Spec.ImageRepoSecret,
+				[]string{"image 2 with another tag"}).Return(registry.ErrImageShared),
+			mbscHelper.EXPECT().RemoveImages(ctx, mbscObj, sets.New("image 2")).Return(nil),
+			clnt.EXPECT().Status().Return(statusWrt),
+			statusWrt.EXPECT().Patch(ctx, &testMic, gomock.Any()).Return(nil),
+		)...)
+
+		_, err := mrh.collectUnusedImages(ctx, &testMic)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testMic.Status.ImagesStates).To(BeEmpty())
+	})
+
+	It("should keep the images whose kernel is still used by a node or a PreflightValidation", func() {
+		testMic.Status.ImagesStates = testMic.Status.ImagesStates[1:]
+
+		gomock.InOrder(append(expectKernelsInUse("kernel 2+", "kernel 3"),
+			clnt.EXPECT().Status().Return(statusWrt),
+			statusWrt.EXPECT().Patch(ctx, &testMic, gomock.Any()).Return(nil),
+		)...)
+
+		requeueAfter, err := mrh.collectUnusedImages(ctx, &testMic)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+		Expect(testMic.Status.ImagesStates).To(HaveLen(3))
+		Expect(testMic.Status.ImagesStates[0].UnusedSince).To(BeNil())
+		Expect(testMic.Status.ImagesStates[1].UnusedSince).To(BeNil())
+		Expect(testMic.Status.ImagesStates[2].UnusedSince).NotTo(BeNil())
+	})
+
+	It("should return an error if the nodes could not be listed", func() {
+		clnt.EXPECT().List(ctx, &v1.NodeList{}).Return(errors.New("some error"))
+
+		_, err := mrh.collectUnusedImages(ctx, &testMic)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the images in use could not be listed", func() {
+		testMic.Status.ImagesStates = testMic.Status.ImagesStates[1:2]
+		mbscObj := &kmmv1beta1.ModuleBuildSignConfig{}
+
+		gomock.InOrder(append(expectKernelsInUse("some kernel", "other kernel"),
+			mbscHelper.EXPECT().Get(ctx, "some name", "some namespace").Return(mbscObj, nil),
+			mbscHelper.EXPECT().GetImageSpec(mbscObj, "image 2").Return(&kmmv1beta1.ModuleBuildSignSpec{}),
+			micHelper.EXPECT().ListByImage(ctx, "image 2").Return(nil, nil),
+			micHelper.EXPECT().ListImages(ctx).Return(nil, errors.New("some error")),
+		)...)
+
+		_, err := mrh.collectUnusedImages(ctx, &testMic)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("findMICsSharingImages", func() {
+	It("should enqueue the other MICs referencing the images", func() {
+		ctx := context.Background()
+		mockMIC := mic.NewMockMIC(gomock.NewController(GinkgoT()))
+		r := &micReconciler{micAPI: mockMIC}
+
+		testMic := kmmv1beta1.ModuleImagesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "some name", Namespace: "some namespace"},
+			Spec: kmmv1beta1.ModuleImagesConfigSpec{
+				Images: []kmmv1beta1.ModuleImageSpec{{Image: "image 1"}, {Image: "image 2"}},
+			},
+		}
+		other := kmmv1beta1.ModuleImagesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "some name", Namespace: "other namespace"},
+		}
+
+		mockMIC.EXPECT().ListByImage(ctx, "image 1").Return([]kmmv1beta1.ModuleImagesConfig{testMic, other}, nil)
+		mockMIC.EXPECT().ListByImage(ctx, "image 2").Return([]kmmv1beta1.ModuleImagesConfig{testMic, other}, nil)
+
+		Expect(r.findMICsSharingImages(ctx, &testMic)).To(Equal([]reconcile.Request{
+			{NamespacedName: types.NamespacedName{Name: "some name", Namespace: "other namespace"}},
+		}))
+	})
+})
+
+var _ = Describe("findMICsWithImageGC", func() {
+	It("should enqueue the MICs whose garbage collection is enabled", func() {
+		ctx := context.Background()
+		clnt := client.NewMockClient(gomock.NewController(GinkgoT()))
+		r := &micReconciler{client: clnt}
+
+		clnt.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.ModuleImagesConfigList, _ ...ctrlclient.ListOption) error {
+				list.Items = []kmmv1beta1.ModuleImagesConfig{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "with-gc", Namespace: "some namespace"},
+						Spec:       kmmv1beta1.ModuleImagesConfigSpec{ImageGC: &kmmv1beta1.ImageGCSpec{}},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "without-gc", Namespace: "some namespace"},
+					},
+				}
+				return nil
+			},
+		)
+
+		Expect(r.findMICsWithImageGC(ctx, &v1.Node{})).To(Equal([]reconcile.Request{
+			{NamespacedName: types.NamespacedName{Name: "with-gc", Namespace: "some namespace"}},
+		}))
+	})
+})
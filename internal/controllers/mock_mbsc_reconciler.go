@@ -39,6 +39,34 @@ func (m *MockmbscReconcilerHelperAPI) EXPECT() *MockmbscReconcilerHelperAPIMockR
 	return m.recorder
 }
 
+// checkBuildInputs mocks base method.
+func (m *MockmbscReconcilerHelperAPI) checkBuildInputs(ctx context.Context, mbscObj *v1beta1.ModuleBuildSignConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "checkBuildInputs", ctx, mbscObj)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// checkBuildInputs indicates an expected call of checkBuildInputs.
+func (mr *MockmbscReconcilerHelperAPIMockRecorder) checkBuildInputs(ctx, mbscObj any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "checkBuildInputs", reflect.TypeOf((*MockmbscReconcilerHelperAPI)(nil).checkBuildInputs), ctx, mbscObj)
+}
+
+// checkSigningKeys mocks base method.
+func (m *MockmbscReconcilerHelperAPI) checkSigningKeys(ctx context.Context, mbscObj *v1beta1.ModuleBuildSignConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "checkSigningKeys", ctx, mbscObj)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// checkSigningKeys indicates an expected call of checkSigningKeys.
+func (mr *MockmbscReconcilerHelperAPIMockRecorder) checkSigningKeys(ctx, mbscObj any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "checkSigningKeys", reflect.TypeOf((*MockmbscReconcilerHelperAPI)(nil).checkSigningKeys), ctx, mbscObj)
+}
+
 // garbageCollect mocks base method.
 func (m *MockmbscReconcilerHelperAPI) garbageCollect(ctx context.Context, mbscObj *v1beta1.ModuleBuildSignConfig) error {
 	m.ctrl.T.Helper()
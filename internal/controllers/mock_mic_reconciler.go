@@ -11,10 +11,12 @@ package controllers
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	v1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	gomock "go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
+	sets "k8s.io/apimachinery/pkg/util/sets"
 )
 
 // MockmicReconcilerHelper is a mock of micReconcilerHelper interface.
@@ -40,6 +42,21 @@ func (m *MockmicReconcilerHelper) EXPECT() *MockmicReconcilerHelperMockRecorder
 	return m.recorder
 }
 
+// collectUnusedImages mocks base method.
+func (m *MockmicReconcilerHelper) collectUnusedImages(ctx context.Context, micObj *v1beta1.ModuleImagesConfig) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "collectUnusedImages", ctx, micObj)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// collectUnusedImages indicates an expected call of collectUnusedImages.
+func (mr *MockmicReconcilerHelperMockRecorder) collectUnusedImages(ctx, micObj any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "collectUnusedImages", reflect.TypeOf((*MockmicReconcilerHelper)(nil).collectUnusedImages), ctx, micObj)
+}
+
 // handleImageRebuildTriggerGeneration mocks base method.
 func (m *MockmicReconcilerHelper) handleImageRebuildTriggerGeneration(ctx context.Context, micObj *v1beta1.ModuleImagesConfig) (bool, error) {
 	m.ctrl.T.Helper()
@@ -56,31 +73,60 @@ func (mr *MockmicReconcilerHelperMockRecorder) handleImageRebuildTriggerGenerati
 }
 
 // processImagesSpecs mocks base method.
-func (m *MockmicReconcilerHelper) processImagesSpecs(ctx context.Context, micObj *v1beta1.ModuleImagesConfig, pullPods []v1.Pod) error {
+func (m *MockmicReconcilerHelper) processImagesSpecs(ctx context.Context, micObj *v1beta1.ModuleImagesConfig, pullPods []v1.Pod, sharedBuilds sets.Set[string]) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "processImagesSpecs", ctx, micObj, pullPods)
+	ret := m.ctrl.Call(m, "processImagesSpecs", ctx, micObj, pullPods, sharedBuilds)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // processImagesSpecs indicates an expected call of processImagesSpecs.
-func (mr *MockmicReconcilerHelperMockRecorder) processImagesSpecs(ctx, micObj, pullPods any) *gomock.Call {
+func (mr *MockmicReconcilerHelperMockRecorder) processImagesSpecs(ctx, micObj, pullPods, sharedBuilds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "processImagesSpecs", reflect.TypeOf((*MockmicReconcilerHelper)(nil).processImagesSpecs), ctx, micObj, pullPods, sharedBuilds)
+}
+
+// processPrePulls mocks base method.
+func (m *MockmicReconcilerHelper) processPrePulls(ctx context.Context, micObj *v1beta1.ModuleImagesConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "processPrePulls", ctx, micObj)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// processPrePulls indicates an expected call of processPrePulls.
+func (mr *MockmicReconcilerHelperMockRecorder) processPrePulls(ctx, micObj any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "processImagesSpecs", reflect.TypeOf((*MockmicReconcilerHelper)(nil).processImagesSpecs), ctx, micObj, pullPods)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "processPrePulls", reflect.TypeOf((*MockmicReconcilerHelper)(nil).processPrePulls), ctx, micObj)
+}
+
+// processSharedImages mocks base method.
+func (m *MockmicReconcilerHelper) processSharedImages(ctx context.Context, micObj *v1beta1.ModuleImagesConfig) (sets.Set[string], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "processSharedImages", ctx, micObj)
+	ret0, _ := ret[0].(sets.Set[string])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// processSharedImages indicates an expected call of processSharedImages.
+func (mr *MockmicReconcilerHelperMockRecorder) processSharedImages(ctx, micObj any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "processSharedImages", reflect.TypeOf((*MockmicReconcilerHelper)(nil).processSharedImages), ctx, micObj)
 }
 
 // updateStatusByMBSC mocks base method.
-func (m *MockmicReconcilerHelper) updateStatusByMBSC(ctx context.Context, micObj *v1beta1.ModuleImagesConfig) error {
+func (m *MockmicReconcilerHelper) updateStatusByMBSC(ctx context.Context, micObj *v1beta1.ModuleImagesConfig, pullPods []v1.Pod) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "updateStatusByMBSC", ctx, micObj)
+	ret := m.ctrl.Call(m, "updateStatusByMBSC", ctx, micObj, pullPods)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // updateStatusByMBSC indicates an expected call of updateStatusByMBSC.
-func (mr *MockmicReconcilerHelperMockRecorder) updateStatusByMBSC(ctx, micObj any) *gomock.Call {
+func (mr *MockmicReconcilerHelperMockRecorder) updateStatusByMBSC(ctx, micObj, pullPods any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "updateStatusByMBSC", reflect.TypeOf((*MockmicReconcilerHelper)(nil).updateStatusByMBSC), ctx, micObj)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "updateStatusByMBSC", reflect.TypeOf((*MockmicReconcilerHelper)(nil).updateStatusByMBSC), ctx, micObj, pullPods)
 }
 
 // updateStatusByPullPods mocks base method.
@@ -96,3 +142,18 @@ func (mr *MockmicReconcilerHelperMockRecorder) updateStatusByPullPods(ctx, micOb
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "updateStatusByPullPods", reflect.TypeOf((*MockmicReconcilerHelper)(nil).updateStatusByPullPods), ctx, micObj, pods)
 }
+
+// verifyExistingImages mocks base method.
+func (m *MockmicReconcilerHelper) verifyExistingImages(ctx context.Context, micObj *v1beta1.ModuleImagesConfig, pullPods []v1.Pod) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "verifyExistingImages", ctx, micObj, pullPods)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// verifyExistingImages indicates an expected call of verifyExistingImages.
+func (mr *MockmicReconcilerHelperMockRecorder) verifyExistingImages(ctx, micObj, pullPods any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "verifyExistingImages", reflect.TypeOf((*MockmicReconcilerHelper)(nil).verifyExistingImages), ctx, micObj, pullPods)
+}
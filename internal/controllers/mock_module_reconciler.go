@@ -71,17 +71,18 @@ func (mr *MockmoduleReconcilerHelperAPIMockRecorder) disableModuleOnNode(ctx, mo
 }
 
 // enableModuleOnNode mocks base method.
-func (m *MockmoduleReconcilerHelperAPI) enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node) error {
+func (m *MockmoduleReconcilerHelperAPI) enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node, allowDigestRollout bool) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "enableModuleOnNode", ctx, mld, node)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "enableModuleOnNode", ctx, mld, node, allowDigestRollout)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // enableModuleOnNode indicates an expected call of enableModuleOnNode.
-func (mr *MockmoduleReconcilerHelperAPIMockRecorder) enableModuleOnNode(ctx, mld, node any) *gomock.Call {
+func (mr *MockmoduleReconcilerHelperAPIMockRecorder) enableModuleOnNode(ctx, mld, node, allowDigestRollout any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "enableModuleOnNode", reflect.TypeOf((*MockmoduleReconcilerHelperAPI)(nil).enableModuleOnNode), ctx, mld, node)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "enableModuleOnNode", reflect.TypeOf((*MockmoduleReconcilerHelperAPI)(nil).enableModuleOnNode), ctx, mld, node, allowDigestRollout)
 }
 
 // finalizeModule mocks base method.
@@ -141,6 +142,21 @@ func (mr *MockmoduleReconcilerHelperAPIMockRecorder) handleNetworkPolicies(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleNetworkPolicies", reflect.TypeOf((*MockmoduleReconcilerHelperAPI)(nil).handleNetworkPolicies), ctx, mod)
 }
 
+// isDigestRolloutInProgress mocks base method.
+func (m *MockmoduleReconcilerHelperAPI) isDigestRolloutInProgress(ctx context.Context, mod *v1beta1.Module, targetedNodes []v1.Node) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "isDigestRolloutInProgress", ctx, mod, targetedNodes)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// isDigestRolloutInProgress indicates an expected call of isDigestRolloutInProgress.
+func (mr *MockmoduleReconcilerHelperAPIMockRecorder) isDigestRolloutInProgress(ctx, mod, targetedNodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "isDigestRolloutInProgress", reflect.TypeOf((*MockmoduleReconcilerHelperAPI)(nil).isDigestRolloutInProgress), ctx, mod, targetedNodes)
+}
+
 // prepareSchedulingData mocks base method.
 func (m *MockmoduleReconcilerHelperAPI) prepareSchedulingData(ctx context.Context, mod *v1beta1.Module, targetedNodes []v1.Node, currentNMCs sets.Set[string]) (map[string]schedulingData, []error) {
 	m.ctrl.T.Helper()
@@ -171,17 +187,17 @@ func (mr *MockmoduleReconcilerHelperAPIMockRecorder) setFinalizerAndStatus(ctx,
 }
 
 // updateModuleStatus mocks base method.
-func (m *MockmoduleReconcilerHelperAPI) updateModuleStatus(ctx context.Context, mod *v1beta1.Module, targetedNodes []v1.Node) error {
+func (m *MockmoduleReconcilerHelperAPI) updateModuleStatus(ctx context.Context, mod *v1beta1.Module, targetedNodes []v1.Node, nodesConfigured bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "updateModuleStatus", ctx, mod, targetedNodes)
+	ret := m.ctrl.Call(m, "updateModuleStatus", ctx, mod, targetedNodes, nodesConfigured)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // updateModuleStatus indicates an expected call of updateModuleStatus.
-func (mr *MockmoduleReconcilerHelperAPIMockRecorder) updateModuleStatus(ctx, mod, targetedNodes any) *gomock.Call {
+func (mr *MockmoduleReconcilerHelperAPIMockRecorder) updateModuleStatus(ctx, mod, targetedNodes, nodesConfigured any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "updateModuleStatus", reflect.TypeOf((*MockmoduleReconcilerHelperAPI)(nil).updateModuleStatus), ctx, mod, targetedNodes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "updateModuleStatus", reflect.TypeOf((*MockmoduleReconcilerHelperAPI)(nil).updateModuleStatus), ctx, mod, targetedNodes, nodesConfigured)
 }
 
 // MocknamespaceLabeler is a mock of namespaceLabeler interface.
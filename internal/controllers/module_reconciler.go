@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	buildv1 "github.com/openshift/api/build/v1"
@@ -37,12 +38,15 @@ import (
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=create;delete;deletecollection;get;list;patch;watch
 // +kubebuilder:rbac:groups=build.openshift.io,resources=builds,verbs=create;delete;get;list;patch;watch
 // +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get
-// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=clusterclaims,resourceNames=kernel-versions.kmm.node.kubernetes.io,verbs=delete;patch;update
+// +kubebuilder:rbac:groups=config.openshift.io,resources=images,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=imagetagmirrorsets,verbs=list;watch
+// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=clusterclaims,resourceNames=kernel-versions.kmm.node.kubernetes.io;kernels.kmm.node.kubernetes.io,verbs=delete;patch;update
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=clusterclaims,verbs=create;get;list;watch
-// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=delete;get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=create;delete;get;list;watch
 // +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;patch;watch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;patch;watch
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=create;delete;get;list;patch;watch
+// +kubebuilder:rbac:groups=core,resources=pods/log,verbs=get
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch
 // +kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=modulebuildsignconfigs,verbs=create;delete;get;list;patch;update;watch
@@ -176,9 +180,18 @@ func (mr *ModuleReconciler) Reconcile(ctx context.Context, mod *kmmv1beta1.Modul
 	errs := make([]error, 0, len(sdMap)+1)
 	errs = append(errs, prepareErrs...)
 
+	// a new digest of the image is rolled out to one node at a time, so that the nodes do not all reload the kernel
+	// module at once
+	digestRolloutInProgress, err := mr.reconHelper.isDigestRolloutInProgress(ctx, mod, targetedNodes)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to check the digest rollout of Module %s/%s: %v", mod.Namespace, mod.Name, err)
+	}
+
 	for nodeName, sd := range sdMap {
 		if sd.action == actionAdd {
-			err = mr.reconHelper.enableModuleOnNode(ctx, sd.mld, sd.node)
+			var rolloutStarted bool
+			rolloutStarted, err = mr.reconHelper.enableModuleOnNode(ctx, sd.mld, sd.node, !digestRolloutInProgress)
+			digestRolloutInProgress = digestRolloutInProgress || rolloutStarted
 		}
 		if sd.action == actionDelete {
 			err = mr.reconHelper.disableModuleOnNode(ctx, mod.Namespace, mod.Name, nodeName)
@@ -187,7 +200,7 @@ func (mr *ModuleReconciler) Reconcile(ctx context.Context, mod *kmmv1beta1.Modul
 		errs = append(errs, err)
 	}
 
-	err = mr.reconHelper.updateModuleStatus(ctx, mod, targetedNodes)
+	err = mr.reconHelper.updateModuleStatus(ctx, mod, targetedNodes, errors.Join(errs...) == nil)
 	errs = append(errs, err)
 
 	err = errors.Join(errs...)
@@ -232,10 +245,11 @@ type moduleReconcilerHelperAPI interface {
 	finalizeModule(ctx context.Context, mod *kmmv1beta1.Module) error
 	getNMCsByModuleSet(ctx context.Context, mod *kmmv1beta1.Module) (sets.Set[string], error)
 	prepareSchedulingData(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node, currentNMCs sets.Set[string]) (map[string]schedulingData, []error)
-	enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node) error
+	isDigestRolloutInProgress(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) (bool, error)
+	enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node, allowDigestRollout bool) (bool, error)
 	disableModuleOnNode(ctx context.Context, modNamespace, modName, nodeName string) error
 	handleNetworkPolicies(ctx context.Context, mod *kmmv1beta1.Module) error
-	updateModuleStatus(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) error
+	updateModuleStatus(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node, nodesConfigured bool) error
 	clearModuleLoaderStatus(ctx context.Context, mod *kmmv1beta1.Module) error
 }
 
@@ -411,6 +425,18 @@ func (mrh *moduleReconcilerHelper) handleMIC(ctx context.Context, mod *kmmv1beta
 		errs   []error
 	)
 
+	// images to pull onto each node, if pre-pulling is enabled
+	prePulls := make(map[string]sets.Set[string])
+	addPrePull := func(image, nodeName string) {
+		if mod.Spec.ImagePrePull == nil {
+			return
+		}
+		if prePulls[image] == nil {
+			prePulls[image] = sets.New[string]()
+		}
+		prePulls[image].Insert(nodeName)
+	}
+
 	for _, node := range targetedNodes {
 		kernelVersion := strings.TrimSuffix(node.Status.NodeInfo.KernelVersion, "+")
 		mld, err := mrh.kernelAPI.GetModuleLoaderDataForKernel(mod, kernelVersion)
@@ -424,44 +450,129 @@ func (mrh *moduleReconcilerHelper) handleMIC(ctx context.Context, mod *kmmv1beta
 			// node is not targeted by module
 			continue
 		}
-		mis := kmmv1beta1.ModuleImageSpec{
-			Image:         mld.ContainerImage,
-			KernelVersion: mld.KernelVersion,
-			Build:         mld.Build,
-			Sign:          mld.Sign,
-			RegistryTLS:   mld.RegistryTLS,
-			DirName:       mld.Modprobe.DirName,
+		images = append(images, moduleImageSpec(mld))
+		addPrePull(mld.ContainerImage, node.Name)
+	}
+
+	if mod.Spec.ImagePrePull != nil {
+		// the images of the upcoming kernels are built, if needed, and pulled onto all nodes before they reboot
+		for _, kernelVersion := range mod.Spec.ImagePrePull.KernelVersions {
+			mld, err := mrh.kernelAPI.GetModuleLoaderDataForKernel(mod, kernelVersion)
+			if err != nil {
+				if !errors.Is(err, module.ErrNoMatchingKernelMapping) {
+					errs = append(errs, fmt.Errorf("failed to get moduleLoaderData for upcoming kernel %s: %v", kernelVersion, err))
+				}
+				continue
+			}
+			images = append(images, moduleImageSpec(mld))
+			for _, node := range targetedNodes {
+				addPrePull(mld.ContainerImage, node.Name)
+			}
 		}
-		images = append(images, mis)
 	}
 
+	var prePullSpecs []kmmv1beta1.PrePullSpec
+	for image, nodeNames := range prePulls {
+		prePullSpecs = append(prePullSpecs, kmmv1beta1.PrePullSpec{Image: image, Nodes: sets.List(nodeNames)})
+	}
+	slices.SortFunc(prePullSpecs, func(a, b kmmv1beta1.PrePullSpec) int {
+		return strings.Compare(a.Image, b.Image)
+	})
+
 	if err := mrh.micAPI.CreateOrPatch(ctx, mod.Name, mod.Namespace, images, mod.Spec.ImageRepoSecret,
-		mod.Spec.ModuleLoader.Container.ImagePullPolicy, true, mod.Spec.ImageRebuildTriggerGeneration, mod.Spec.Tolerations, mod); err != nil {
+		mod.Spec.ModuleLoader.Container.ImagePullPolicy, true, mod.Spec.ImageRebuildTriggerGeneration, mod.Spec.BuildPriority,
+		mod.Spec.Tolerations, prePullSpecs, mod.Spec.ImageGC, mod); err != nil {
 		errs = append(errs, fmt.Errorf("failed to apply %s/%s MIC: %v", mod.Namespace, mod.Name, err))
 	}
 
 	return errors.Join(errs...)
 }
 
-func (mrh *moduleReconcilerHelper) enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node) error {
+func moduleImageSpec(mld *api.ModuleLoaderData) kmmv1beta1.ModuleImageSpec {
+	return kmmv1beta1.ModuleImageSpec{
+		Image:         mld.ContainerImage,
+		KernelVersion: mld.KernelVersion,
+		Build:         mld.Build,
+		Sign:          mld.Sign,
+		RegistryTLS:   mld.RegistryTLS,
+		DirName:       mld.Modprobe.DirName,
+		ModuleNames:   mld.KernelModuleNames(),
+		FirmwarePath:  mld.Modprobe.FirmwarePath,
+	}
+}
+
+// isDigestRolloutInProgress returns true if a node is still reloading the Module.
+// For a versioned Module, this is a node whose worker or schedule Pod version label does not match its module version
+// label yet; otherwise, this is a node that has not loaded the image configured in its NMC yet.
+func (mrh *moduleReconcilerHelper) isDigestRolloutInProgress(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) (bool, error) {
+	if mod.Spec.ModuleLoader.Container.Version != "" {
+		moduleLabel := utils.GetModuleVersionLabelName(mod.Namespace, mod.Name)
+		workerPodLabel := utils.GetWorkerPodVersionLabelName(mod.Namespace, mod.Name)
+		schedulePodLabel := utils.GetSchedulePodVersionLabelName(mod.Namespace, mod.Name)
+
+		for _, node := range targetedNodes {
+			labels := node.GetLabels()
+			if version, ok := labels[moduleLabel]; ok && (labels[workerPodLabel] != version || labels[schedulePodLabel] != version) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+
+	nmcs, err := mrh.getNMCsForModule(ctx, mod)
+	if err != nil {
+		return false, fmt.Errorf("failed to get NMCs for Module %s/%s: %v", mod.Namespace, mod.Name, err)
+	}
+
+	for i := range nmcs {
+		spec, _ := mrh.nmcHelper.GetModuleSpecEntry(&nmcs[i], mod.Namespace, mod.Name)
+		if spec == nil {
+			continue
+		}
+
+		status := mrh.nmcHelper.GetModuleStatusEntry(&nmcs[i], mod.Namespace, mod.Name)
+		if status == nil || status.Config.ContainerImage != spec.Config.ContainerImage && !isFirstPinning(status.Config, spec.Config) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// enableModuleOnNode configures the Module in the NMC of the node.
+// If the node runs a previous digest of the same image, the new digest is only rolled out if allowDigestRollout is
+// true; enableModuleOnNode then returns true. A versioned Module is reloaded through its worker and schedule Pod
+// version labels, which are reset on the node; otherwise, the new digest is written to the NMC.
+func (mrh *moduleReconcilerHelper) enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node, allowDigestRollout bool) (bool, error) {
 
 	logger := log.FromContext(ctx)
 
 	micObj, err := mrh.micAPI.Get(ctx, mld.Name, mld.Namespace)
 	if err != nil {
-		return fmt.Errorf("failed to get moduleImagesConfig %s: %v", mld.Name, err)
+		return false, fmt.Errorf("failed to get moduleImagesConfig %s: %v", mld.Name, err)
 	}
 
 	imageStatus := mrh.micAPI.GetImageState(micObj, mld.ContainerImage)
 	if imageStatus != kmmv1beta1.ImageExists {
 		// skip updating NMC, reconciliation will kick in once the build pod is completed
 		logger.V(1).Info("Image does not exist, not adding to NMC", "nmc name", node.Name, "container image", mld.ContainerImage)
-		return nil
+		return false, nil
+	}
+
+	if mld.ImagePrePull != nil {
+		// a failed pre-pull does not block the module: the worker pod pulls the image and reports the error
+		prePullState := mrh.micAPI.GetPrePullState(micObj, mld.ContainerImage, node.Name)
+		if prePullState != kmmv1beta1.PrePullPulled && prePullState != kmmv1beta1.PrePullFailed {
+			// reconciliation will kick in once the MIC reports that the image was pulled onto the node
+			logger.V(1).Info("Image is not pulled onto the node yet, not updating NMC", "nmc name", node.Name, "container image", mld.ContainerImage)
+			return false, nil
+		}
 	}
 
 	moduleConfig := kmmv1beta1.ModuleConfig{
 		KernelVersion:         mld.KernelVersion,
-		ContainerImage:        mld.ContainerImage,
+		ContainerImage:        mrh.micAPI.GetPinnedImage(micObj, mld.ContainerImage),
 		ImagePullPolicy:       mld.ImagePullPolicy,
 		InTreeModulesToRemove: mld.InTreeModulesToRemove,
 		Modprobe:              mld.Modprobe,
@@ -475,7 +586,24 @@ func (mrh *moduleReconcilerHelper) enableModuleOnNode(ctx context.Context, mld *
 		ObjectMeta: metav1.ObjectMeta{Name: node.Name},
 	}
 
+	rolloutStarted := false
+
 	opRes, err := controllerutil.CreateOrPatch(ctx, mrh.client, nmcObj, func() error {
+		if spec, _ := mrh.nmcHelper.GetModuleSpecEntry(nmcObj, mld.Namespace, mld.Name); spec != nil &&
+			isPreviousDigestOfImage(spec, mld, moduleConfig.ContainerImage) {
+			switch {
+			case moduleConfig.ContainerImage == mld.ContainerImage || !allowDigestRollout:
+				// the digest of the image is unknown, or another node is rolling a new digest out
+				moduleConfig.ContainerImage = spec.Config.ContainerImage
+			case mld.ModuleVersion != "":
+				// the worker and schedule Pod version labels unload the Module before the new digest is written
+				moduleConfig.ContainerImage = spec.Config.ContainerImage
+				rolloutStarted = true
+			default:
+				rolloutStarted = true
+			}
+		}
+
 		if err := mrh.nmcHelper.SetModuleConfig(nmcObj, mld, &moduleConfig); err != nil {
 			return err
 		}
@@ -487,10 +615,32 @@ func (mrh *moduleReconcilerHelper) enableModuleOnNode(ctx context.Context, mld *
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to enable module %s/%s in NMC %s: %v", mld.Namespace, mld.Name, node.Name, err)
+		return false, fmt.Errorf("failed to enable module %s/%s in NMC %s: %v", mld.Namespace, mld.Name, node.Name, err)
 	}
 	logger.Info("Enable module in NMC", "name", mld.Name, "namespace", mld.Namespace, "node", node.Name, "result", opRes)
-	return nil
+
+	if rolloutStarted && mld.ModuleVersion != "" {
+		logger.Info("Rolling a new digest of the image out", "node", node.Name, "container image", moduleConfig.ContainerImage)
+
+		nodeCopy := node.DeepCopy()
+		meta.SetLabel(node, utils.GetWorkerPodVersionLabelName(mld.Namespace, mld.Name), constants.DigestRolloutVersionLabelValue)
+		meta.SetLabel(node, utils.GetSchedulePodVersionLabelName(mld.Namespace, mld.Name), constants.DigestRolloutVersionLabelValue)
+
+		if err = mrh.client.Patch(ctx, node, client.MergeFrom(nodeCopy)); err != nil {
+			return false, fmt.Errorf("failed to reset the version labels of node %s: %v", node.Name, err)
+		}
+	}
+
+	return rolloutStarted, nil
+}
+
+// isPreviousDigestOfImage returns true if the node is configured with another digest of the same image, version and
+// kernel as mld than pinnedImage.
+func isPreviousDigestOfImage(spec *kmmv1beta1.NodeModuleSpec, mld *api.ModuleLoaderData, pinnedImage string) bool {
+	return spec.Version == mld.ModuleVersion &&
+		spec.Config.KernelVersion == mld.KernelVersion &&
+		strings.HasPrefix(spec.Config.ContainerImage, mld.ContainerImage+"@") &&
+		spec.Config.ContainerImage != pinnedImage
 }
 
 func (mrh *moduleReconcilerHelper) disableModuleOnNode(ctx context.Context, modNamespace, modName, nodeName string) error {
@@ -521,9 +671,16 @@ func (mrh *moduleReconcilerHelper) removeModuleFromNMC(ctx context.Context, nmcO
 	return nil
 }
 
-func (mrh *moduleReconcilerHelper) updateModuleStatus(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) error {
+func (mrh *moduleReconcilerHelper) updateModuleStatus(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node,
+	nodesConfigured bool) error {
 	unmodifiedMod := mod.DeepCopy()
 
+	// Only report the revision set by the Hub once all NMCs were updated; otherwise the available number may
+	// still count nodes running the previous revision.
+	if nodesConfigured {
+		mod.Status.ManagedClusterModuleRevision = mod.GetAnnotations()[constants.ManagedClusterModuleRevisionAnnotation]
+	}
+
 	var errs []error
 
 	if err := mrh.updateModuleLoaderStatus(ctx, mod, targetedNodes); err != nil {
@@ -574,13 +731,15 @@ func (mrh *moduleReconcilerHelper) updateModuleLoaderStatus(ctx context.Context,
 
 func (mrh *moduleReconcilerHelper) clearModuleLoaderStatus(ctx context.Context, mod *kmmv1beta1.Module) error {
 	emptyStatus := kmmv1beta1.DaemonSetStatus{}
-	if mod.Status.ModuleLoader == emptyStatus {
+	revision := mod.GetAnnotations()[constants.ManagedClusterModuleRevisionAnnotation]
+	if mod.Status.ModuleLoader == emptyStatus && mod.Status.ManagedClusterModuleRevision == revision {
 		return nil
 	}
 
 	unmodifiedMod := mod.DeepCopy()
 
 	mod.Status.ModuleLoader = kmmv1beta1.DaemonSetStatus{}
+	mod.Status.ManagedClusterModuleRevision = revision
 
 	return mrh.client.Status().Patch(ctx, mod, client.MergeFrom(unmodifiedMod))
 }
@@ -97,6 +97,7 @@ var _ = Describe("Reconcile", func() {
 		handleMICError             bool
 		getNMCsMapError            bool
 		prepareSchedulingError     bool
+		digestRolloutError         bool
 		shouldBeOnNode             bool
 		disableEnableError         bool
 		moduleUpdateStatusErr      bool
@@ -139,28 +140,34 @@ var _ = Describe("Reconcile", func() {
 		mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil)
 		if c.prepareSchedulingError {
 			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nil, []error{returnedError})
+			mockReconHelper.EXPECT().isDigestRolloutInProgress(ctx, mod, targetedNodes).Return(false, nil)
 			goto moduleStatusUpdateFunction
 		}
 		mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, []error{})
+		if c.digestRolloutError {
+			mockReconHelper.EXPECT().isDigestRolloutInProgress(ctx, mod, targetedNodes).Return(false, returnedError)
+			goto executeTestFunction
+		}
+		mockReconHelper.EXPECT().isDigestRolloutInProgress(ctx, mod, targetedNodes).Return(false, nil)
 		if c.disableEnableError {
 			if c.shouldBeOnNode {
-				mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(returnedError)
+				mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node, true).Return(false, returnedError)
 			} else {
 				mockReconHelper.EXPECT().disableModuleOnNode(ctx, mod.Namespace, mod.Name, node.Name).Return(returnedError)
 			}
 			goto moduleStatusUpdateFunction
 		}
 		if c.shouldBeOnNode {
-			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(nil)
+			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node, true).Return(false, nil)
 		} else {
 			mockReconHelper.EXPECT().disableModuleOnNode(ctx, mod.Namespace, mod.Name, node.Name).Return(nil)
 		}
 
 	moduleStatusUpdateFunction:
 		if c.moduleUpdateStatusErr {
-			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes).Return(returnedError)
+			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes, !c.prepareSchedulingError && !c.disableEnableError).Return(returnedError)
 		} else {
-			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes).Return(nil)
+			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes, !c.prepareSchedulingError && !c.disableEnableError).Return(nil)
 		}
 
 	executeTestFunction:
@@ -174,6 +181,7 @@ var _ = Describe("Reconcile", func() {
 		Entry("handleMIC failed", errorFlowTestCase{handleMICError: true}),
 		Entry("getNMCsByModuleMap failed", errorFlowTestCase{getNMCsMapError: true}),
 		Entry("prepareSchedulingData failed", errorFlowTestCase{prepareSchedulingError: true}),
+		Entry("isDigestRolloutInProgress failed", errorFlowTestCase{digestRolloutError: true}),
 		Entry("enableModuleOnNode failed", errorFlowTestCase{shouldBeOnNode: true, disableEnableError: true}),
 		Entry("disableModuleOnNode failed", errorFlowTestCase{disableEnableError: true}),
 		Entry("updateModuleStatus failed", errorFlowTestCase{moduleUpdateStatusErr: true}),
@@ -190,8 +198,38 @@ var _ = Describe("Reconcile", func() {
 			mockReconHelper.EXPECT().handleMIC(ctx, mod, targetedNodes).Return(nil),
 			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil),
 			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, nil),
-			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(nil),
-			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes).Return(nil),
+			mockReconHelper.EXPECT().isDigestRolloutInProgress(ctx, mod, targetedNodes).Return(false, nil),
+			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node, true).Return(false, nil),
+			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes, true).Return(nil),
+		)
+
+		res, err := mr.Reconcile(ctx, mod)
+
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should only roll a new digest out to one node at a time", func() {
+		otherNode := v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "otherNode"},
+		}
+		nodes := []v1.Node{node, otherNode}
+		nmcMLDConfigs := map[string]schedulingData{
+			nodeName:       enableSchedulingData,
+			otherNode.Name: {action: actionAdd, mld: &mld, node: &otherNode},
+		}
+		gomock.InOrder(
+			mockReconHelper.EXPECT().handleNetworkPolicies(ctx, mod).Return(nil),
+			mockNamespaceHelper.EXPECT().setLabel(ctx, mod.Namespace),
+			mockReconHelper.EXPECT().setFinalizerAndStatus(ctx, mod).Return(nil),
+			mn.EXPECT().GetSchedulableNodesBySelector(ctx, mod.Spec.Selector, module.InternalTolerations).Return(nodes, nil),
+			mockReconHelper.EXPECT().handleMIC(ctx, mod, nodes).Return(nil),
+			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil),
+			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, nodes, currentNMCs).Return(nmcMLDConfigs, nil),
+			mockReconHelper.EXPECT().isDigestRolloutInProgress(ctx, mod, nodes).Return(false, nil),
+			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, gomock.Any(), true).Return(true, nil),
+			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, gomock.Any(), false).Return(false, nil),
+			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, nodes, true).Return(nil),
 		)
 
 		res, err := mr.Reconcile(ctx, mod)
@@ -210,8 +248,9 @@ var _ = Describe("Reconcile", func() {
 			mockReconHelper.EXPECT().handleMIC(ctx, mod, targetedNodes).Return(nil),
 			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil),
 			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, nil),
+			mockReconHelper.EXPECT().isDigestRolloutInProgress(ctx, mod, targetedNodes).Return(false, nil),
 			mockReconHelper.EXPECT().disableModuleOnNode(ctx, mod.Namespace, mod.Name, node.Name).Return(nil),
-			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes).Return(nil),
+			mockReconHelper.EXPECT().updateModuleStatus(ctx, mod, targetedNodes, true).Return(nil),
 		)
 
 		res, err := mr.Reconcile(ctx, mod)
@@ -485,7 +524,7 @@ var _ = Describe("handleMIC", func() {
 	It("should return an error if we failed to get moduleLoaderData for kernel", func() {
 
 		mockKernelMapper.EXPECT().GetModuleLoaderDataForKernel(mod, gomock.Any()).Return(nil, errors.New("some error"))
-		mockMICAPI.EXPECT().CreateOrPatch(ctx, mod.Name, mod.Namespace, gomock.Any(), mod.Spec.ImageRepoSecret, v1.PullPolicy(""), true, mod.Spec.ImageRebuildTriggerGeneration, mod.Spec.Tolerations, mod).Return(nil)
+		mockMICAPI.EXPECT().CreateOrPatch(ctx, mod.Name, mod.Namespace, gomock.Any(), mod.Spec.ImageRepoSecret, v1.PullPolicy(""), true, mod.Spec.ImageRebuildTriggerGeneration, mod.Spec.BuildPriority, mod.Spec.Tolerations, nil, mod.Spec.ImageGC, mod).Return(nil)
 
 		err := mrh.handleMIC(ctx, mod, targetedNodes)
 		Expect(err).To(HaveOccurred())
@@ -498,7 +537,7 @@ var _ = Describe("handleMIC", func() {
 		mld := &api.ModuleLoaderData{ContainerImage: img}
 		mockKernelMapper.EXPECT().GetModuleLoaderDataForKernel(mod, gomock.Any()).Return(mld, nil)
 		mockMICAPI.EXPECT().CreateOrPatch(ctx, mod.Name, mod.Namespace, gomock.Any(), mod.Spec.ImageRepoSecret,
-			v1.PullPolicy(""), true, mod.Spec.ImageRebuildTriggerGeneration, mod.Spec.Tolerations, mod).Return(errors.New("some error"))
+			v1.PullPolicy(""), true, mod.Spec.ImageRebuildTriggerGeneration, mod.Spec.BuildPriority, mod.Spec.Tolerations, nil, mod.Spec.ImageGC, mod).Return(errors.New("some error"))
 
 		err := mrh.handleMIC(ctx, mod, targetedNodes)
 		Expect(err).To(HaveOccurred())
@@ -506,7 +545,7 @@ var _ = Describe("handleMIC", func() {
 	})
 
 	It("should not do anything if targetedNodes is empty", func() {
-		mockMICAPI.EXPECT().CreateOrPatch(ctx, mod.Name, mod.Namespace, gomock.Any(), mod.Spec.ImageRepoSecret, v1.PullPolicy(""), true, mod.Spec.ImageRebuildTriggerGeneration, mod.Spec.Tolerations, mod).Return(nil)
+		mockMICAPI.EXPECT().CreateOrPatch(ctx, mod.Name, mod.Namespace, gomock.Any(), mod.Spec.ImageRepoSecret, v1.PullPolicy(""), true, mod.Spec.ImageRebuildTriggerGeneration, mod.Spec.BuildPriority, mod.Spec.Tolerations, nil, mod.Spec.ImageGC, mod).Return(nil)
 		err := mrh.handleMIC(ctx, mod, []v1.Node{})
 		Expect(err).NotTo(HaveOccurred())
 	})
@@ -530,7 +569,38 @@ var _ = Describe("handleMIC", func() {
 		}
 		mockKernelMapper.EXPECT().GetModuleLoaderDataForKernel(mod, gomock.Any()).Return(mld, nil)
 		mockMICAPI.EXPECT().CreateOrPatch(ctx, mod.Name, mod.Namespace, []kmmv1beta1.ModuleImageSpec{expectedSpec},
-			mod.Spec.ImageRepoSecret, v1.PullPolicy(""), true, mod.Spec.ImageRebuildTriggerGeneration, mod.Spec.Tolerations, mod).Return(nil)
+			mod.Spec.ImageRepoSecret, v1.PullPolicy(""), true, mod.Spec.ImageRebuildTriggerGeneration, mod.Spec.BuildPriority, mod.Spec.Tolerations, nil, mod.Spec.ImageGC, mod).Return(nil)
+
+		err := mrh.handleMIC(ctx, mod, targetedNodes)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should request the pre-pull of the images onto the nodes", func() {
+		mod.Spec.ImagePrePull = &kmmv1beta1.ImagePrePullSpec{KernelVersions: []string{"upcoming version"}}
+		targetedNodes = append(targetedNodes, v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "other node"},
+			Status:     v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KernelVersion: "unmapped version"}},
+		})
+		targetedNodes[0].Status.NodeInfo.KernelVersion = "some version"
+
+		mld := &api.ModuleLoaderData{ContainerImage: "example.registry.com/org/image:current", KernelVersion: "some version"}
+		upcomingMLD := &api.ModuleLoaderData{ContainerImage: "example.registry.com/org/image:upcoming", KernelVersion: "upcoming version"}
+		expectedImages := []kmmv1beta1.ModuleImageSpec{
+			{Image: mld.ContainerImage, KernelVersion: mld.KernelVersion},
+			{Image: upcomingMLD.ContainerImage, KernelVersion: upcomingMLD.KernelVersion},
+		}
+		expectedPrePulls := []kmmv1beta1.PrePullSpec{
+			{Image: mld.ContainerImage, Nodes: []string{nodeName}},
+			{Image: upcomingMLD.ContainerImage, Nodes: []string{nodeName, "other node"}},
+		}
+
+		gomock.InOrder(
+			mockKernelMapper.EXPECT().GetModuleLoaderDataForKernel(mod, "some version").Return(mld, nil),
+			mockKernelMapper.EXPECT().GetModuleLoaderDataForKernel(mod, "unmapped version").Return(nil, module.ErrNoMatchingKernelMapping),
+			mockKernelMapper.EXPECT().GetModuleLoaderDataForKernel(mod, "upcoming version").Return(upcomingMLD, nil),
+			mockMICAPI.EXPECT().CreateOrPatch(ctx, mod.Name, mod.Namespace, expectedImages, mod.Spec.ImageRepoSecret, v1.PullPolicy(""),
+				true, mod.Spec.ImageRebuildTriggerGeneration, mod.Spec.BuildPriority, mod.Spec.Tolerations, expectedPrePulls, mod.Spec.ImageGC, mod),
+		)
 
 		err := mrh.handleMIC(ctx, mod, targetedNodes)
 		Expect(err).NotTo(HaveOccurred())
@@ -838,7 +908,7 @@ var _ = Describe("enableModuleOnNode", func() {
 
 		mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(nil, errors.New("some error"))
 
-		err := mrh.enableModuleOnNode(ctx, mld, &node)
+		_, err := mrh.enableModuleOnNode(ctx, mld, &node, true)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("failed to get moduleImagesConfig"))
 	})
@@ -850,10 +920,38 @@ var _ = Describe("enableModuleOnNode", func() {
 			mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageDoesNotExist),
 		)
 
-		err := mrh.enableModuleOnNode(ctx, mld, &node)
+		_, err := mrh.enableModuleOnNode(ctx, mld, &node, true)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	DescribeTable("should wait for the image to be pulled onto the node",
+		func(prePullState kmmv1beta1.PrePullState, updateNMC bool) {
+			mld.ImagePrePull = &kmmv1beta1.ImagePrePullSpec{}
+
+			gomock.InOrder(
+				mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+				mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+				mockMIC.EXPECT().GetPrePullState(gomock.Any(), containerImage, node.Name).Return(prePullState),
+			)
+			if updateNMC {
+				gomock.InOrder(
+					mockMIC.EXPECT().GetPinnedImage(gomock.Any(), containerImage).Return(containerImage),
+					clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+					helper.EXPECT().GetModuleSpecEntry(gomock.Any(), moduleNamespace, moduleName).Return(nil, 0),
+					helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(nil),
+					clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+				)
+			}
+
+			_, err := mrh.enableModuleOnNode(ctx, mld, &node, true)
+			Expect(err).NotTo(HaveOccurred())
+		},
+		Entry("pull not started", kmmv1beta1.PrePullState(""), false),
+		Entry("pulling", kmmv1beta1.PrePullPulling, false),
+		Entry("pulled", kmmv1beta1.PrePullPulled, true),
+		Entry("failed", kmmv1beta1.PrePullFailed, true),
+	)
+
 	It("NMC does not exist", func() {
 		nmc := &kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: node.Name},
@@ -862,12 +960,35 @@ var _ = Describe("enableModuleOnNode", func() {
 		gomock.InOrder(
 			mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
 			mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+			mockMIC.EXPECT().GetPinnedImage(gomock.Any(), containerImage).Return(containerImage),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			helper.EXPECT().GetModuleSpecEntry(nmc, moduleNamespace, moduleName).Return(nil, 0),
 			helper.EXPECT().SetModuleConfig(nmc, mld, expectedModuleConfig).Return(nil),
 			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
 		)
 
-		err := mrh.enableModuleOnNode(ctx, mld, &node)
+		_, err := mrh.enableModuleOnNode(ctx, mld, &node, true)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should pin the image to its verified digest", func() {
+		const pinnedImage = "example.com/repo@sha256:111"
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: node.Name},
+		}
+		expectedModuleConfig.ContainerImage = pinnedImage
+
+		gomock.InOrder(
+			mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+			mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+			mockMIC.EXPECT().GetPinnedImage(gomock.Any(), containerImage).Return(pinnedImage),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			helper.EXPECT().GetModuleSpecEntry(nmc, moduleNamespace, moduleName).Return(nil, 0),
+			helper.EXPECT().SetModuleConfig(nmc, mld, expectedModuleConfig).Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+		)
+
+		_, err := mrh.enableModuleOnNode(ctx, mld, &node, true)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -891,18 +1012,194 @@ var _ = Describe("enableModuleOnNode", func() {
 		gomock.InOrder(
 			mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
 			mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+			mockMIC.EXPECT().GetPinnedImage(gomock.Any(), containerImage).Return(containerImage),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
 				func(_ interface{}, _ interface{}, nmc *kmmv1beta1.NodeModulesConfig, _ ...ctrlclient.GetOption) error {
 					nmc.SetName(node.Name)
 					return nil
 				},
 			),
+			helper.EXPECT().GetModuleSpecEntry(nmcObj, moduleNamespace, moduleName).Return(nil, 0),
 			helper.EXPECT().SetModuleConfig(nmcObj, mld, expectedModuleConfig).Return(nil),
 			clnt.EXPECT().Patch(ctx, &nmcWithLabels, gomock.Any()).Return(nil),
 		)
 
-		err := mrh.enableModuleOnNode(ctx, mld, &node)
+		_, err := mrh.enableModuleOnNode(ctx, mld, &node, true)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	DescribeTable("should roll a new digest of the image out",
+		func(moduleVersion, currentVersion, currentKernel, currentImage string, allowDigestRollout bool, expectedImage string, expectedRollout bool) {
+			const newImage = containerImage + "@sha256:222"
+			mld.ModuleVersion = moduleVersion
+			currentSpec := kmmv1beta1.NodeModuleSpec{
+				ModuleItem: kmmv1beta1.ModuleItem{Version: currentVersion},
+				Config:     kmmv1beta1.ModuleConfig{KernelVersion: currentKernel, ContainerImage: currentImage},
+			}
+			expectedModuleConfig.ContainerImage = expectedImage
+
+			gomock.InOrder(
+				mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+				mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+				mockMIC.EXPECT().GetPinnedImage(gomock.Any(), containerImage).Return(newImage),
+				clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+				helper.EXPECT().GetModuleSpecEntry(gomock.Any(), moduleNamespace, moduleName).Return(&currentSpec, 0),
+				helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(nil),
+				clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			)
+
+			rolloutStarted, err := mrh.enableModuleOnNode(ctx, mld, &node, allowDigestRollout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rolloutStarted).To(Equal(expectedRollout))
+		},
+		Entry("new digest", "", "", "some version", containerImage+"@sha256:111", true, containerImage+"@sha256:222", true),
+		Entry("new digest while another node rolls it out", "", "", "some version", containerImage+"@sha256:111", false, containerImage+"@sha256:111", false),
+		Entry("same digest", "", "", "some version", containerImage+"@sha256:222", false, containerImage+"@sha256:222", false),
+		Entry("new version", "v2", "v1", "some version", containerImage+"@sha256:111", false, containerImage+"@sha256:222", false),
+		Entry("new kernel", "", "", "other kernel", containerImage+"@sha256:111", false, containerImage+"@sha256:222", false),
+		Entry("other image", "", "", "some version", "otherImage@sha256:111", false, containerImage+"@sha256:222", false),
+		Entry("image pinned for the first time", "", "", "some version", containerImage, false, containerImage+"@sha256:222", false),
+	)
+
+	It("should keep the previous digest if the digest of the image is unknown", func() {
+		currentSpec := kmmv1beta1.NodeModuleSpec{
+			Config: kmmv1beta1.ModuleConfig{KernelVersion: kernelVersion, ContainerImage: containerImage + "@sha256:111"},
+		}
+		expectedModuleConfig.ContainerImage = containerImage + "@sha256:111"
+
+		gomock.InOrder(
+			mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+			mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+			mockMIC.EXPECT().GetPinnedImage(gomock.Any(), containerImage).Return(containerImage),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			helper.EXPECT().GetModuleSpecEntry(gomock.Any(), moduleNamespace, moduleName).Return(&currentSpec, 0),
+			helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(nil),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(nil),
+		)
+
+		rolloutStarted, err := mrh.enableModuleOnNode(ctx, mld, &node, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rolloutStarted).To(BeFalse())
+	})
+
+	It("should reset the version labels of the node to roll a new digest of a versioned Module out", func() {
+		mld.ModuleVersion = "v1"
+		currentSpec := kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{Version: "v1"},
+			Config:     kmmv1beta1.ModuleConfig{KernelVersion: kernelVersion, ContainerImage: containerImage + "@sha256:111"},
+		}
+		expectedModuleConfig.ContainerImage = containerImage + "@sha256:111"
+
+		gomock.InOrder(
+			mockMIC.EXPECT().Get(ctx, moduleName, moduleNamespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+			mockMIC.EXPECT().GetImageState(gomock.Any(), containerImage).Return(kmmv1beta1.ImageExists),
+			mockMIC.EXPECT().GetPinnedImage(gomock.Any(), containerImage).Return(containerImage+"@sha256:222"),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			helper.EXPECT().GetModuleSpecEntry(gomock.Any(), moduleNamespace, moduleName).Return(&currentSpec, 0),
+			helper.EXPECT().SetModuleConfig(gomock.Any(), mld, expectedModuleConfig).Return(nil),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			clnt.EXPECT().Patch(ctx, &node, gomock.Any()).Return(nil),
+		)
+
+		rolloutStarted, err := mrh.enableModuleOnNode(ctx, mld, &node, true)
 		Expect(err).NotTo(HaveOccurred())
+		Expect(rolloutStarted).To(BeTrue())
+		Expect(node.Labels).To(Equal(map[string]string{
+			utils.GetWorkerPodVersionLabelName(moduleNamespace, moduleName):   constants.DigestRolloutVersionLabelValue,
+			utils.GetSchedulePodVersionLabelName(moduleNamespace, moduleName): constants.DigestRolloutVersionLabelValue,
+		}))
+	})
+})
+
+var _ = Describe("isDigestRolloutInProgress", func() {
+	const (
+		moduleNamespace = "moduleNamespace"
+		moduleName      = "moduleName"
+	)
+
+	var (
+		ctx    context.Context
+		clnt   *client.MockClient
+		helper *nmc.MockHelper
+		mrh    moduleReconcilerHelperAPI
+		mod    *kmmv1beta1.Module
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		helper = nmc.NewMockHelper(ctrl)
+		mrh = newModuleReconcilerHelper(clnt, nil, nil, helper, nil, operatorNamespace, scheme)
+		ctx = context.Background()
+		mod = &kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{Name: moduleName, Namespace: moduleNamespace},
+			Spec: kmmv1beta1.ModuleSpec{
+				ModuleLoader: &kmmv1beta1.ModuleLoaderSpec{},
+			},
+		}
+	})
+
+	DescribeTable("should check the version labels of the nodes for a versioned Module",
+		func(workerPodVersion, schedulePodVersion string, expected bool) {
+			mod.Spec.ModuleLoader.Container.Version = "v1"
+			node := v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node",
+					Labels: map[string]string{
+						utils.GetModuleVersionLabelName(moduleNamespace, moduleName):      "v1",
+						utils.GetWorkerPodVersionLabelName(moduleNamespace, moduleName):   workerPodVersion,
+						utils.GetSchedulePodVersionLabelName(moduleNamespace, moduleName): schedulePodVersion,
+					},
+				},
+			}
+
+			inProgress, err := mrh.isDigestRolloutInProgress(ctx, mod, []v1.Node{node})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inProgress).To(Equal(expected))
+		},
+		Entry("node up to date", "v1", "v1", false),
+		Entry("node unloading the Module", constants.DigestRolloutVersionLabelValue, constants.DigestRolloutVersionLabelValue, true),
+		Entry("node loading the Module", "v1", "", true),
+	)
+
+	DescribeTable("should compare the NMC spec and status of an unversioned Module",
+		func(status *kmmv1beta1.NodeModuleStatus, expected bool) {
+			spec := kmmv1beta1.NodeModuleSpec{
+				Config: kmmv1beta1.ModuleConfig{ContainerImage: "example.com/repo:tag@sha256:222"},
+			}
+
+			gomock.InOrder(
+				clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...ctrlclient.ListOption) error {
+						list.Items = []kmmv1beta1.NodeModulesConfig{{ObjectMeta: metav1.ObjectMeta{Name: "node"}}}
+						return nil
+					},
+				),
+				helper.EXPECT().GetModuleSpecEntry(gomock.Any(), moduleNamespace, moduleName).Return(&spec, 0),
+				helper.EXPECT().GetModuleStatusEntry(gomock.Any(), moduleNamespace, moduleName).Return(status),
+			)
+
+			inProgress, err := mrh.isDigestRolloutInProgress(ctx, mod, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inProgress).To(Equal(expected))
+		},
+		Entry("image loaded", &kmmv1beta1.NodeModuleStatus{
+			Config: kmmv1beta1.ModuleConfig{ContainerImage: "example.com/repo:tag@sha256:222"},
+		}, false),
+		Entry("image pinned for the first time", &kmmv1beta1.NodeModuleStatus{
+			Config: kmmv1beta1.ModuleConfig{ContainerImage: "example.com/repo:tag"},
+		}, false),
+		Entry("previous digest loaded", &kmmv1beta1.NodeModuleStatus{
+			Config: kmmv1beta1.ModuleConfig{ContainerImage: "example.com/repo:tag@sha256:111"},
+		}, true),
+		Entry("Module not loaded", nil, true),
+	)
+
+	It("should return an error if the NMCs could not be listed", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+
+		_, err := mrh.isDigestRolloutInProgress(ctx, mod, nil)
+		Expect(err).To(HaveOccurred())
 	})
 })
 
@@ -1166,6 +1463,51 @@ var _ = Describe("updateModuleLoaderStatus", func() {
 	})
 })
 
+var _ = Describe("updateModuleStatus", func() {
+	var (
+		ctx          context.Context
+		ctrl         *gomock.Controller
+		clnt         *client.MockClient
+		statusWriter *client.MockStatusWriter
+		mockMicAPI   *mic.MockMIC
+		mod          kmmv1beta1.Module
+		mrh          *moduleReconcilerHelper
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		statusWriter = client.NewMockStatusWriter(ctrl)
+		mockMicAPI = mic.NewMockMIC(ctrl)
+		mod = kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "modName",
+				Namespace:   "modNamespace",
+				Annotations: map[string]string{constants.ManagedClusterModuleRevisionAnnotation: "new-revision"},
+			},
+			Status: kmmv1beta1.ModuleStatus{ManagedClusterModuleRevision: "old-revision"},
+		}
+		mrh = &moduleReconcilerHelper{client: clnt, micAPI: mockMicAPI}
+	})
+
+	DescribeTable("should report the ManagedClusterModule revision", func(nodesConfigured bool, expectedRevision string) {
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			mockMicAPI.EXPECT().Get(ctx, mod.Name, mod.Namespace).Return(&kmmv1beta1.ModuleImagesConfig{}, nil),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &mod, gomock.Any()).Return(nil),
+		)
+
+		err := mrh.updateModuleStatus(ctx, &mod, nil, nodesConfigured)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mod.Status.ManagedClusterModuleRevision).To(Equal(expectedRevision))
+	},
+		Entry("all nodes configured", true, "new-revision"),
+		Entry("some nodes not configured", false, "old-revision"),
+	)
+})
+
 var _ = Describe("clearModuleLoaderStatus", func() {
 	var (
 		ctx  context.Context
@@ -1204,6 +1546,21 @@ var _ = Describe("clearModuleLoaderStatus", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(mod.Status.ModuleLoader).To(Equal(kmmv1beta1.DaemonSetStatus{}))
 	})
+
+	It("should patch status when the ManagedClusterModule revision changed", func() {
+		mod := kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{constants.ManagedClusterModuleRevisionAnnotation: "some-revision"},
+			},
+		}
+		statusWriter := client.NewMockStatusWriter(ctrl)
+		clnt.EXPECT().Status().Return(statusWriter)
+		statusWriter.EXPECT().Patch(ctx, &mod, gomock.Any()).Return(nil)
+
+		err := mrh.clearModuleLoaderStatus(ctx, &mod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mod.Status.ManagedClusterModuleRevision).To(Equal("some-revision"))
+	})
 })
 
 var _ = Describe("updateImageRebuildTriggerGenerationStatus", func() {
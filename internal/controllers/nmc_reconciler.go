@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/config"
@@ -330,7 +331,8 @@ func (h *nmcReconcilerHelperImpl) GarbageCollectInUseLabels(ctx context.Context,
 //     that would make a node not Ready, such as a reboot.
 //
 // An unloading worker Pod is created when the entry in .spec.modules has a different config compared to the entry in
-// .status.modules.
+// .status.modules. If the only difference is that the image in .spec.modules was pinned to its digest, the entry in
+// .status.modules is updated instead.
 func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 	ctx context.Context,
 	nmcObj *kmmv1beta1.NodeModulesConfig,
@@ -358,6 +360,13 @@ func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 		unload the kernel module, otherwise - load kernel modules, since the pod
 		is not running, the module cannot be loaded using the old kernel configuration
 		*/
+		if isFirstPinning(status.Config, spec.Config) {
+			logger.Info("Image in status was pinned to its digest in spec; updating status")
+			patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+			status.Config.ContainerImage = spec.Config.ContainerImage
+			return h.client.Status().Patch(ctx, nmcObj, patchFrom)
+		}
+
 		if !reflect.DeepEqual(spec.Config, status.Config) {
 			if spec.Config.KernelVersion == status.Config.KernelVersion {
 				logger.Info("Outdated config in status; creating unloader Pod")
@@ -398,6 +407,18 @@ func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 	return nil
 }
 
+// isFirstPinning returns true if the only difference between the loaded and desired configs is that the image of the
+// desired config is pinned to a digest of the loaded image. The Module does not need to be reloaded in that case.
+func isFirstPinning(loaded, desired kmmv1beta1.ModuleConfig) bool {
+	if strings.Contains(loaded.ContainerImage, "@") || !strings.HasPrefix(desired.ContainerImage, loaded.ContainerImage+"@") {
+		return false
+	}
+
+	loaded.ContainerImage = desired.ContainerImage
+
+	return reflect.DeepEqual(loaded, desired)
+}
+
 // ProcessUnconfiguredModuleStatus cleans up a NodeModuleStatus.
 // It should be called for each status entry for which the NodeModulesConfigs does not have a spec entry; this means
 // that KMM wants the module unloaded from the node.
@@ -684,6 +684,43 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		)
 	})
 
+	It("should only update the status if the image in the spec was pinned to its digest", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Config: kmmv1beta1.ModuleConfig{ContainerImage: "container-image@sha256:111", KernelVersion: "same kernel"},
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Config: kmmv1beta1.ModuleConfig{ContainerImage: "container-image", KernelVersion: "same kernel"},
+		}
+
+		sw := testclient.NewMockStatusWriter(gomock.NewController(GinkgoT()))
+
+		gomock.InOrder(
+			mockWorkerPodManager.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			client.EXPECT().Status().Return(sw),
+			sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+		)
+
+		Expect(
+			wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+		).NotTo(
+			HaveOccurred(),
+		)
+		Expect(status.Config).To(Equal(spec.Config))
+	})
+
 	It("should create an loader Pod if the spec is different from the status and kernels different equal", func() {
 		nmc := &kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
@@ -1967,3 +2004,22 @@ var _ = Describe("addEqualLabels", func() {
 			v1.Node{},
 			[]types.NamespacedName{}))
 })
+
+var _ = Describe("isFirstPinning", func() {
+	DescribeTable("should only return true if the loaded image was pinned to its digest",
+		func(loadedImage, desiredImage string, otherChange, expected bool) {
+			loaded := kmmv1beta1.ModuleConfig{KernelVersion: "some kernel", ContainerImage: loadedImage}
+			desired := kmmv1beta1.ModuleConfig{KernelVersion: "some kernel", ContainerImage: desiredImage}
+			if otherChange {
+				desired.InTreeModulesToRemove = []string{"intree"}
+			}
+
+			Expect(isFirstPinning(loaded, desired)).To(Equal(expected))
+		},
+		Entry("first pinning", "example.com/repo:tag", "example.com/repo:tag@sha256:111", false, true),
+		Entry("first pinning with another change", "example.com/repo:tag", "example.com/repo:tag@sha256:111", true, false),
+		Entry("new digest", "example.com/repo:tag@sha256:111", "example.com/repo:tag@sha256:222", false, false),
+		Entry("other image", "example.com/repo:other", "example.com/repo:tag@sha256:111", false, false),
+		Entry("same image", "example.com/repo:tag", "example.com/repo:tag", false, false),
+	)
+})
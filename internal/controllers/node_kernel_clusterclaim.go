@@ -3,7 +3,6 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"sort"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
@@ -18,8 +17,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/cluster"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/filter"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
 )
 
 const (
@@ -45,40 +46,52 @@ func (r *NodeKernelClusterClaimReconciler) Reconcile(ctx context.Context, req ct
 		return ctrl.Result{}, fmt.Errorf("could not list nodes: %v", err)
 	}
 
-	kernelsMap := make(map[string]struct{})
+	// The kernels are sorted: we do not want to update the ClusterClaims if the value only
+	// changed because of a different order in the nodes.
+	kernels := cluster.NodeKernelsFromNodes(nodeList.Items)
 
-	for _, n := range nodeList.Items {
-		kernelsMap[n.Status.NodeInfo.KernelVersion] = struct{}{}
+	if err := r.setClusterClaim(ctx, constants.KernelVersionsClusterClaimName, strings.Join(kernels.Versions(), "\n")); err != nil {
+		return ctrl.Result{}, err
 	}
 
-	// The map's order is unpredictable; we do not want to update the ClusterClaim if the value only
-	// changed because of a different order in the kernel versions.
-	// Copy the kernels into a slice and sort it alphabetically.
-	kernelsSlice := make([]string, 0, len(kernelsMap))
+	value, err := cluster.KernelsClaimValue(kernels)
+	if err != nil {
+		// The Hub falls back to the kernel versions ClusterClaim.
+		logger.Info(utils.WarnString(fmt.Sprintf("Could not publish the kernels ClusterClaim: %v", err)))
 
-	for k := range kernelsMap {
-		kernelsSlice = append(kernelsSlice, k)
+		cc := v1alpha1.ClusterClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: constants.KernelsClusterClaimName},
+		}
+
+		return ctrl.Result{}, client.IgnoreNotFound(r.client.Delete(ctx, &cc))
 	}
 
-	sort.Strings(kernelsSlice)
+	return ctrl.Result{}, r.setClusterClaim(ctx, constants.KernelsClusterClaimName, value)
+}
+
+func (r *NodeKernelClusterClaimReconciler) setClusterClaim(ctx context.Context, name, value string) error {
+	logger := log.FromContext(ctx).WithValues("name", name)
 
 	cc := v1alpha1.ClusterClaim{
-		ObjectMeta: metav1.ObjectMeta{Name: constants.KernelVersionsClusterClaimName},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
 	}
 
 	logger.Info("Creating or patching ClusterClaim")
 
 	opRes, err := controllerutil.CreateOrPatch(ctx, r.client, &cc, func() error {
 		cc.Spec = v1alpha1.ClusterClaimSpec{
-			Value: strings.Join(kernelsSlice, "\n"),
+			Value: value,
 		}
 
 		return nil
 	})
+	if err != nil {
+		return fmt.Errorf("could not create or patch ClusterClaim %s: %v", name, err)
+	}
 
 	logger.Info("Reconciled ClusterClaim", "res", opRes)
 
-	return ctrl.Result{}, err
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -86,14 +99,14 @@ func (r *NodeKernelClusterClaimReconciler) SetupWithManager(mgr ctrl.Manager) er
 	return ctrl.
 		NewControllerManagedBy(mgr).
 		Named(NodeKernelClusterClaimReconcilerName).
-		// Each time a Node's .status.nodeInfo.kernelVersion is updated, enqueue a reconciliation request
+		// Each time a Node's kernel or OS image is updated, enqueue a reconciliation request
 		For(
 			&v1.Node{},
 			builder.WithPredicates(
 				filter.NodeUpdateKernelChangedPredicate(),
 			),
 		).
-		// Each time our ClusterClaim is updated, enqueue an empty reconciliation request.
+		// Each time one of our ClusterClaims is updated, enqueue an empty reconciliation request.
 		// We list all nodes during reconciliation, so sending an empty request is OK.
 		Watches(
 			&v1alpha1.ClusterClaim{},
@@ -102,7 +115,8 @@ func (r *NodeKernelClusterClaimReconciler) SetupWithManager(mgr ctrl.Manager) er
 			}),
 			builder.WithPredicates(
 				predicate.NewPredicateFuncs(func(object client.Object) bool {
-					return object.GetName() == constants.KernelVersionsClusterClaimName
+					name := object.GetName()
+					return name == constants.KernelVersionsClusterClaimName || name == constants.KernelsClusterClaimName
 				}),
 			),
 		).
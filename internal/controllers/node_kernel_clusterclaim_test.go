@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -27,7 +29,11 @@ var _ = Describe("NodeKernelClusterClaimReconciler_Reconcile", func() {
 	})
 
 	It("should work as expected", func() {
-		const ccName = "kernel-versions.kmm.node.kubernetes.io"
+		const (
+			ccName        = "kernel-versions.kmm.node.kubernetes.io"
+			kernelsCCName = "kernels.kmm.node.kubernetes.io"
+			kernelsValue  = `[{"kernelVersion":"a1","nodes":2},{"kernelVersion":"a2","nodes":1},{"kernelVersion":"b","nodes":2}]`
+		)
 
 		ctx := context.Background()
 
@@ -39,6 +45,14 @@ var _ = Describe("NodeKernelClusterClaimReconciler_Reconcile", func() {
 
 		cc.Spec.Value = "a1\na2\nb"
 
+		kernelsCC := v1alpha1.ClusterClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: kernelsCCName},
+		}
+
+		initialKernelsCC := kernelsCC.DeepCopy()
+
+		kernelsCC.Spec.Value = kernelsValue
+
 		gomock.InOrder(
 			kubeClient.
 				EXPECT().
@@ -83,6 +97,49 @@ var _ = Describe("NodeKernelClusterClaimReconciler_Reconcile", func() {
 						Equal([]byte(`{"spec":{"value":"a1\na2\nb"}}`)),
 					)
 				}),
+			kubeClient.EXPECT().Get(ctx, types.NamespacedName{Name: kernelsCCName}, initialKernelsCC),
+			kubeClient.
+				EXPECT().
+				Patch(ctx, &kernelsCC, gomock.AssignableToTypeOf(ctrlclient.MergeFrom(initialKernelsCC))).
+				Do(func(_ context.Context, obj *v1alpha1.ClusterClaim, p ctrlclient.Patch, _ ...ctrlclient.PatchOption) {
+					Expect(
+						p.Data(&kernelsCC),
+					).To(
+						MatchJSON(`{"spec":{"value":` + strconv.Quote(kernelsValue) + `}}`),
+					)
+				}),
+		)
+
+		_, err := NewNodeKernelClusterClaimReconciler(kubeClient).Reconcile(ctx, ctrl.Request{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should delete the kernels ClusterClaim if the kernels do not fit in it", func() {
+		ctx := context.Background()
+
+		nodes := make([]v1.Node, 0, 20)
+		for i := 0; i < 20; i++ {
+			nodes = append(nodes, v1.Node{
+				Status: v1.NodeStatus{
+					NodeInfo: v1.NodeSystemInfo{KernelVersion: fmt.Sprintf("5.14.0-%03d.1.1.el9_4.x86_64-some-long-suffix", i)},
+				},
+			})
+		}
+
+		kernelsCC := v1alpha1.ClusterClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "kernels.kmm.node.kubernetes.io"},
+		}
+
+		gomock.InOrder(
+			kubeClient.
+				EXPECT().
+				List(ctx, &v1.NodeList{}).
+				Do(func(_ context.Context, nl *v1.NodeList, _ ...metav1.ListOptions) {
+					nl.Items = nodes
+				}),
+			kubeClient.EXPECT().Get(ctx, types.NamespacedName{Name: "kernel-versions.kmm.node.kubernetes.io"}, gomock.Any()),
+			kubeClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()),
+			kubeClient.EXPECT().Delete(ctx, &kernelsCC),
 		)
 
 		_, err := NewNodeKernelClusterClaimReconciler(kubeClient).Reconcile(ctx, ctrl.Request{})
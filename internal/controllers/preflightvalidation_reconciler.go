@@ -24,11 +24,14 @@ import (
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta2"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildsign"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/filter"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/metrics"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mic"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/pod"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/preflight"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -40,6 +43,10 @@ import (
 
 const (
 	PreflightValidationReconcilerName = "PreflightValidation"
+
+	// maxCompatibilityCheckRetries is the number of times a compatibility check pod that failed without a verdict is
+	// replaced before the verification of the Module fails.
+	maxCompatibilityCheckRetries = 2
 )
 
 // PreflightReconciler reconciles a PreflightValidation object
@@ -58,8 +65,11 @@ func NewPreflightValidationReconciler(
 	micAPI mic.MIC,
 	kernelAPI module.KernelMapper,
 	preflightAPI preflight.PreflightAPI,
+	compatibilityCheckerAPI pod.CompatibilityChecker,
+	resourceManager buildsign.ResourceManager,
 ) *preflightValidationReconciler {
-	helper := newPreflightReconcilerHelper(client, micAPI, metricsAPI, kernelAPI, preflightAPI)
+	helper := newPreflightReconcilerHelper(client, micAPI, metricsAPI, kernelAPI, preflightAPI, compatibilityCheckerAPI,
+		resourceManager)
 	return &preflightValidationReconciler{
 		client:       client,
 		filterAPI:    filterAPI,
@@ -74,6 +84,7 @@ func (r *preflightValidationReconciler) SetupWithManager(mgr ctrl.Manager) error
 		Named(PreflightValidationReconcilerName).
 		For(&v1beta2.PreflightValidation{}, builder.WithPredicates(filter.PreflightReconcilerUpdatePredicate())).
 		Owns(&kmmv1beta1.ModuleImagesConfig{}).
+		Owns(&v1.Pod{}).
 		Watches(
 			&kmmv1beta1.Module{},
 			handler.EnqueueRequestsFromMapFunc(r.filterAPI.EnqueueAllPreflightValidations),
@@ -126,25 +137,31 @@ type preflightReconcilerHelper interface {
 }
 
 type preflightReconcilerHelperImpl struct {
-	client       client.Client
-	micAPI       mic.MIC
-	metricsAPI   metrics.Metrics
-	kernelAPI    module.KernelMapper
-	preflightAPI preflight.PreflightAPI
+	client                  client.Client
+	micAPI                  mic.MIC
+	metricsAPI              metrics.Metrics
+	kernelAPI               module.KernelMapper
+	preflightAPI            preflight.PreflightAPI
+	compatibilityCheckerAPI pod.CompatibilityChecker
+	resourceManager         buildsign.ResourceManager
 }
 
 func newPreflightReconcilerHelper(client client.Client,
 	micAPI mic.MIC,
 	metricsAPI metrics.Metrics,
 	kernelAPI module.KernelMapper,
-	preflightAPI preflight.PreflightAPI) preflightReconcilerHelper {
+	preflightAPI preflight.PreflightAPI,
+	compatibilityCheckerAPI pod.CompatibilityChecker,
+	resourceManager buildsign.ResourceManager) preflightReconcilerHelper {
 
 	return &preflightReconcilerHelperImpl{
-		client:       client,
-		micAPI:       micAPI,
-		metricsAPI:   metricsAPI,
-		kernelAPI:    kernelAPI,
-		preflightAPI: preflightAPI,
+		client:                  client,
+		micAPI:                  micAPI,
+		metricsAPI:              metricsAPI,
+		kernelAPI:               kernelAPI,
+		preflightAPI:            preflightAPI,
+		compatibilityCheckerAPI: compatibilityCheckerAPI,
+		resourceManager:         resourceManager,
 	}
 }
 
@@ -167,25 +184,78 @@ func (p *preflightReconcilerHelperImpl) updateStatus(
 		modReason := "verification is not finished yet"
 		foundMIC, err := p.micAPI.Get(ctx, mod.Name+"-preflight", mod.Namespace)
 		if err == nil {
-			imageStatus := p.micAPI.GetImageState(foundMIC, mod.ContainerImage)
-			switch imageStatus {
-			case kmmv1beta1.ImageExists:
-				modStatus = v1beta2.VerificationSuccess
-				modReason = "verified image exists"
-			case kmmv1beta1.ImageDoesNotExist:
-				modStatus = v1beta2.VerificationFailure
-				modReason = "verified image does not exist"
-				if mod.Build != nil || mod.Sign != nil {
-					modReason += " and build/sign failed"
-				}
-			}
+			modStatus, modReason = preflight.ImageVerificationStatus(p.micAPI, foundMIC, mod)
+		}
+		if modStatus != v1beta2.VerificationSuccess {
+			p.preflightAPI.SetModuleStatus(pv, mod.Namespace, mod.Name, modStatus, modReason)
+			continue
+		}
+
+		// the image exists: its kernel modules must also load on the kernel
+		modStatus, modReason, err = p.checkCompatibility(ctx, mod, pv)
+		if err != nil {
+			return fmt.Errorf("failed to check the compatibility of module %s/%s: %v", mod.Namespace, mod.Name, err)
 		}
-		p.preflightAPI.SetModuleStatus(pv, mod.Namespace, mod.Name, modStatus, modReason)
+		p.preflightAPI.SetModuleCompatibilityStatus(pv, mod.Namespace, mod.Name, modStatus, modReason)
 	}
 
 	return p.client.Status().Patch(ctx, pv, client.MergeFrom(unmodifiedPV))
 }
 
+// checkCompatibility returns the status of the check of the kernel modules of the Module against the Module.symvers of
+// the kernel, starting it if needed. A check pod that failed without a verdict, for instance because an image could
+// not be pulled, is replaced up to maxCompatibilityCheckRetries times. The check pod is kept once it has completed, so
+// that its result stays available for the lifetime of the PreflightValidation.
+func (p *preflightReconcilerHelperImpl) checkCompatibility(ctx context.Context, mod *api.ModuleLoaderData,
+	pv *v1beta2.PreflightValidation) (string, string, error) {
+
+	logger := ctrl.LoggerFrom(ctx).WithValues("module", mod.Name, "namespace", mod.Namespace)
+
+	checkPod, err := p.compatibilityCheckerAPI.GetCheckPod(ctx, mod.Name, mod.Namespace, pv)
+	if err != nil {
+		return "", "", err
+	}
+
+	var attempt int32
+	if checkPod != nil {
+		attempt = p.compatibilityCheckerAPI.GetCheckPodAttempt(checkPod)
+		if attempt >= maxCompatibilityCheckRetries ||
+			p.compatibilityCheckerAPI.GetCheckPodStatus(checkPod) != pod.CompatibilityCheckFailed {
+			status, reason := preflight.CompatibilityVerificationStatus(p.compatibilityCheckerAPI, checkPod, mod.KernelVersion)
+			return status, reason, nil
+		}
+
+		logger.Info("The compatibility check pod failed, retrying", "pod", checkPod.Name,
+			"findings", p.compatibilityCheckerAPI.GetCheckPodFindings(checkPod))
+		if err = p.compatibilityCheckerAPI.DeleteCheckPod(ctx, checkPod); err != nil {
+			return "", "", fmt.Errorf("failed to delete the failed compatibility check pod %s: %v", checkPod.Name, err)
+		}
+		attempt++
+	}
+
+	symversImage, err := p.resourceManager.GetBuilderImage(ctx, mod.KernelVersion)
+	if err != nil || symversImage == "" {
+		logger.Info("No image with the Module.symvers of the kernel, not checking the compatibility", "error", err)
+		return v1beta2.VerificationUnverified, "verified image exists, but the compatibility of its kernel modules could " +
+			"not be checked: no DTK or builder image was found for kernel " + mod.KernelVersion, nil
+	}
+
+	check := pod.CompatibilityCheck{
+		Image:           mod.ContainerImage,
+		ImageRepoSecret: mod.ImageRepoSecret,
+		DirName:         mod.Modprobe.DirName,
+		KernelVersion:   mod.KernelVersion,
+		ModuleNames:     mod.KernelModuleNames(),
+		SymversImage:    symversImage,
+		Attempt:         attempt,
+	}
+	if err = p.compatibilityCheckerAPI.CreateCheckPod(ctx, mod.Name, mod.Namespace, &check, pv); err != nil {
+		return "", "", fmt.Errorf("failed to create the compatibility check pod: %v", err)
+	}
+
+	return v1beta2.VerificationInProgress, "verifying the compatibility of the kernel modules with kernel " + mod.KernelVersion, nil
+}
+
 func (p *preflightReconcilerHelperImpl) getModulesData(ctx context.Context, pv *v1beta2.PreflightValidation) ([]*api.ModuleLoaderData, []types.NamespacedName, error) {
 	modulesList := kmmv1beta1.ModuleList{}
 	err := p.client.List(ctx, &modulesList)
@@ -218,7 +288,7 @@ func (p *preflightReconcilerHelperImpl) processPreflightValidation(ctx context.C
 	errs := []error{}
 	for _, mod := range modsWithMapping {
 		status := p.preflightAPI.GetModuleStatus(pv, mod.Namespace, mod.Name)
-		if status == v1beta2.VerificationSuccess || status == v1beta2.VerificationFailure {
+		if preflight.VerificationDone(status) {
 			continue
 		}
 
@@ -229,10 +299,12 @@ func (p *preflightReconcilerHelperImpl) processPreflightValidation(ctx context.C
 			Sign:          mod.Sign,
 			RegistryTLS:   mod.RegistryTLS,
 			DirName:       mod.Modprobe.DirName,
+			ModuleNames:   mod.KernelModuleNames(),
+			FirmwarePath:  mod.Modprobe.FirmwarePath,
 		}
 		micName := mod.Name + "-preflight"
 		err := p.micAPI.CreateOrPatch(ctx, micName, mod.Namespace, []kmmv1beta1.ModuleImageSpec{micObjSpec},
-			mod.ImageRepoSecret, mod.ImagePullPolicy, pv.Spec.PushBuiltImage, nil, mod.Tolerations, pv)
+			mod.ImageRepoSecret, mod.ImagePullPolicy, pv.Spec.PushBuiltImage, nil, mod.BuildPriority, mod.Tolerations, nil, nil, pv)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("failed to apply %s/%s MIC: %v", mod.Namespace, mod.Name, err))
 		}
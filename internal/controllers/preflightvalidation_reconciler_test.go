@@ -10,10 +10,12 @@ import (
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta2"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildsign"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/metrics"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mic"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/pod"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/preflight"
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
@@ -89,12 +91,14 @@ var _ = Describe("PreflightValidationReconciler", func() {
 
 var _ = Describe("updateStatus", func() {
 	var (
-		mockCtrl         *gomock.Controller
-		mockClient       *client.MockClient
-		mockStatusWriter *client.MockStatusWriter
-		mockPreflight    *preflight.MockPreflightAPI
-		mockMic          *mic.MockMIC
-		p                preflightReconcilerHelper
+		mockCtrl                 *gomock.Controller
+		mockClient               *client.MockClient
+		mockStatusWriter         *client.MockStatusWriter
+		mockPreflight            *preflight.MockPreflightAPI
+		mockMic                  *mic.MockMIC
+		mockCompatibilityChecker *pod.MockCompatibilityChecker
+		mockResourceManager      *buildsign.MockResourceManager
+		p                        preflightReconcilerHelper
 	)
 
 	BeforeEach(func() {
@@ -103,7 +107,10 @@ var _ = Describe("updateStatus", func() {
 		mockStatusWriter = client.NewMockStatusWriter(mockCtrl)
 		mockPreflight = preflight.NewMockPreflightAPI(mockCtrl)
 		mockMic = mic.NewMockMIC(mockCtrl)
-		p = newPreflightReconcilerHelper(mockClient, mockMic, nil, nil, mockPreflight)
+		mockCompatibilityChecker = pod.NewMockCompatibilityChecker(mockCtrl)
+		mockResourceManager = buildsign.NewMockResourceManager(mockCtrl)
+		p = newPreflightReconcilerHelper(mockClient, mockMic, nil, nil, mockPreflight, mockCompatibilityChecker,
+			mockResourceManager)
 	})
 
 	ctx := context.Background()
@@ -113,11 +120,14 @@ var _ = Describe("updateStatus", func() {
 		foundMic1 := &kmmv1beta1.ModuleImagesConfig{}
 		foundMic2 := &kmmv1beta1.ModuleImagesConfig{}
 		foundMic3 := &kmmv1beta1.ModuleImagesConfig{}
+		foundMic5 := &kmmv1beta1.ModuleImagesConfig{}
 		modsWithMapping := []*api.ModuleLoaderData{
 			{
 				Name:           "mld name1",
 				Namespace:      "mld namespace1",
 				ContainerImage: "mld container image1",
+				KernelVersion:  "some kernel",
+				Modprobe:       kmmv1beta1.ModprobeSpec{ModuleName: "kmod1", DirName: "/opt"},
 			},
 			{
 				Name:           "mld name2",
@@ -134,6 +144,11 @@ var _ = Describe("updateStatus", func() {
 				Namespace:      "mld namespace4",
 				ContainerImage: "mld container image4",
 			},
+			{
+				Name:           "mld name5",
+				Namespace:      "mld namespace5",
+				ContainerImage: "mld container image5",
+			},
 		}
 		modsWithoutMapping := []types.NamespacedName{
 			{
@@ -146,7 +161,17 @@ var _ = Describe("updateStatus", func() {
 			mockPreflight.EXPECT().SetModuleStatus(pv, "some namespace", "some name", v1beta2.VerificationFailure, "mapping not found"),
 			mockMic.EXPECT().Get(ctx, "mld name1-preflight", "mld namespace1").Return(foundMic1, nil),
 			mockMic.EXPECT().GetImageState(foundMic1, "mld container image1").Return(kmmv1beta1.ImageExists),
-			mockPreflight.EXPECT().SetModuleStatus(pv, "mld namespace1", "mld name1", v1beta2.VerificationSuccess, "verified image exists"),
+			mockCompatibilityChecker.EXPECT().GetCheckPod(ctx, "mld name1", "mld namespace1", pv).Return(nil, nil),
+			mockResourceManager.EXPECT().GetBuilderImage(ctx, "some kernel").Return("dtk image", nil),
+			mockCompatibilityChecker.EXPECT().CreateCheckPod(ctx, "mld name1", "mld namespace1", &pod.CompatibilityCheck{
+				Image:         "mld container image1",
+				DirName:       "/opt",
+				KernelVersion: "some kernel",
+				ModuleNames:   []string{"kmod1"},
+				SymversImage:  "dtk image",
+			}, pv),
+			mockPreflight.EXPECT().SetModuleCompatibilityStatus(pv, "mld namespace1", "mld name1", v1beta2.VerificationInProgress,
+				"verifying the compatibility of the kernel modules with kernel some kernel"),
 			mockMic.EXPECT().Get(ctx, "mld name2-preflight", "mld namespace2").Return(foundMic2, nil),
 			mockMic.EXPECT().GetImageState(foundMic2, "mld container image2").Return(kmmv1beta1.ImageDoesNotExist),
 			mockPreflight.EXPECT().SetModuleStatus(pv, "mld namespace2", "mld name2", v1beta2.VerificationFailure, "verified image does not exist"),
@@ -155,6 +180,11 @@ var _ = Describe("updateStatus", func() {
 			mockPreflight.EXPECT().SetModuleStatus(pv, "mld namespace3", "mld name3", v1beta2.VerificationInProgress, "verification is not finished yet"),
 			mockMic.EXPECT().Get(ctx, "mld name4-preflight", "mld namespace4").Return(nil, fmt.Errorf("some error")),
 			mockPreflight.EXPECT().SetModuleStatus(pv, "mld namespace4", "mld name4", v1beta2.VerificationInProgress, "verification is not finished yet"),
+			mockMic.EXPECT().Get(ctx, "mld name5-preflight", "mld namespace5").Return(foundMic5, nil),
+			mockMic.EXPECT().GetImageState(foundMic5, "mld container image5").Return(kmmv1beta1.ImageInvalidLayout),
+			mockMic.EXPECT().GetImageLayoutFindings(foundMic5, "mld container image5").Return([]string{"finding 1", "finding 2"}),
+			mockPreflight.EXPECT().SetModuleStatus(pv, "mld namespace5", "mld name5", v1beta2.VerificationFailure,
+				"verified image does not contain the expected kernel modules: finding 1; finding 2"),
 			mockClient.EXPECT().Status().Return(mockStatusWriter),
 			mockStatusWriter.EXPECT().Patch(ctx, pv, gomock.Any()).Return(nil),
 		)
@@ -162,6 +192,133 @@ var _ = Describe("updateStatus", func() {
 		err := p.updateStatus(ctx, modsWithMapping, modsWithoutMapping, pv)
 		Expect(err).To(BeNil())
 	})
+
+	It("should return an error if the compatibility check pod could not be created", func() {
+		foundMic := &kmmv1beta1.ModuleImagesConfig{}
+		mld := &api.ModuleLoaderData{Name: "mld name", Namespace: "mld namespace", ContainerImage: "mld container image"}
+
+		gomock.InOrder(
+			mockMic.EXPECT().Get(ctx, "mld name-preflight", "mld namespace").Return(foundMic, nil),
+			mockMic.EXPECT().GetImageState(foundMic, "mld container image").Return(kmmv1beta1.ImageExists),
+			mockCompatibilityChecker.EXPECT().GetCheckPod(ctx, "mld name", "mld namespace", pv).Return(nil, nil),
+			mockResourceManager.EXPECT().GetBuilderImage(ctx, "").Return("dtk image", nil),
+			mockCompatibilityChecker.EXPECT().CreateCheckPod(ctx, "mld name", "mld namespace", gomock.Any(), pv).
+				Return(errors.New("some error")),
+		)
+
+		err := p.updateStatus(ctx, []*api.ModuleLoaderData{mld}, nil, pv)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("checkCompatibility", func() {
+	var (
+		mockCtrl                 *gomock.Controller
+		mockCompatibilityChecker *pod.MockCompatibilityChecker
+		mockResourceManager      *buildsign.MockResourceManager
+		p                        *preflightReconcilerHelperImpl
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockCompatibilityChecker = pod.NewMockCompatibilityChecker(mockCtrl)
+		mockResourceManager = buildsign.NewMockResourceManager(mockCtrl)
+		p = newPreflightReconcilerHelper(nil, nil, nil, nil, nil, mockCompatibilityChecker, mockResourceManager).(*preflightReconcilerHelperImpl)
+	})
+
+	ctx := context.Background()
+	pv := &v1beta2.PreflightValidation{}
+	mld := &api.ModuleLoaderData{Name: "mld name", Namespace: "mld namespace", KernelVersion: "some kernel"}
+
+	It("should return the result of the existing check pod", func() {
+		checkPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "check pod"}}
+
+		gomock.InOrder(
+			mockCompatibilityChecker.EXPECT().GetCheckPod(ctx, "mld name", "mld namespace", pv).Return(checkPod, nil),
+			mockCompatibilityChecker.EXPECT().GetCheckPodAttempt(checkPod).Return(int32(0)),
+			mockCompatibilityChecker.EXPECT().GetCheckPodStatus(checkPod).Return(pod.CompatibilityCheckIncompatible),
+			mockCompatibilityChecker.EXPECT().GetCheckPodStatus(checkPod).Return(pod.CompatibilityCheckIncompatible),
+			mockCompatibilityChecker.EXPECT().GetCheckPodFindings(checkPod).Return([]string{"finding 1", "finding 2"}),
+		)
+
+		status, reason, err := p.checkCompatibility(ctx, mld, pv)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(v1beta2.VerificationFailure))
+		Expect(reason).To(Equal("the kernel modules are not compatible with kernel some kernel: finding 1; finding 2"))
+	})
+
+	It("should replace a check pod that failed without a verdict", func() {
+		checkPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "check pod"}}
+
+		gomock.InOrder(
+			mockCompatibilityChecker.EXPECT().GetCheckPod(ctx, "mld name", "mld namespace", pv).Return(checkPod, nil),
+			mockCompatibilityChecker.EXPECT().GetCheckPodAttempt(checkPod).Return(int32(1)),
+			mockCompatibilityChecker.EXPECT().GetCheckPodStatus(checkPod).Return(pod.CompatibilityCheckFailed),
+			mockCompatibilityChecker.EXPECT().GetCheckPodFindings(checkPod).Return([]string{"some finding"}),
+			mockCompatibilityChecker.EXPECT().DeleteCheckPod(ctx, checkPod).Return(nil),
+			mockResourceManager.EXPECT().GetBuilderImage(ctx, "some kernel").Return("dtk image", nil),
+			mockCompatibilityChecker.EXPECT().CreateCheckPod(ctx, "mld name", "mld namespace", gomock.Any(), pv).DoAndReturn(
+				func(_ context.Context, _, _ string, check *pod.CompatibilityCheck, _ *v1beta2.PreflightValidation) error {
+					Expect(check.SymversImage).To(Equal("dtk image"))
+					Expect(check.Attempt).To(Equal(int32(2)))
+					return nil
+				}),
+		)
+
+		status, _, err := p.checkCompatibility(ctx, mld, pv)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(v1beta2.VerificationInProgress))
+	})
+
+	It("should fail once the check pod failed too many times", func() {
+		checkPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "check pod"}}
+
+		gomock.InOrder(
+			mockCompatibilityChecker.EXPECT().GetCheckPod(ctx, "mld name", "mld namespace", pv).Return(checkPod, nil),
+			mockCompatibilityChecker.EXPECT().GetCheckPodAttempt(checkPod).Return(int32(maxCompatibilityCheckRetries)),
+			mockCompatibilityChecker.EXPECT().GetCheckPodStatus(checkPod).Return(pod.CompatibilityCheckFailed),
+			mockCompatibilityChecker.EXPECT().GetCheckPodFindings(checkPod).Return([]string{"some finding"}),
+		)
+
+		status, reason, err := p.checkCompatibility(ctx, mld, pv)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(v1beta2.VerificationFailure))
+		Expect(reason).To(Equal("the compatibility check of the kernel modules failed, see pod check pod: some finding"))
+	})
+
+	It("should return an error if the failed check pod could not be deleted", func() {
+		checkPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "check pod"}}
+
+		gomock.InOrder(
+			mockCompatibilityChecker.EXPECT().GetCheckPod(ctx, "mld name", "mld namespace", pv).Return(checkPod, nil),
+			mockCompatibilityChecker.EXPECT().GetCheckPodAttempt(checkPod).Return(int32(0)),
+			mockCompatibilityChecker.EXPECT().GetCheckPodStatus(checkPod).Return(pod.CompatibilityCheckFailed),
+			mockCompatibilityChecker.EXPECT().GetCheckPodFindings(checkPod).Return([]string{"some finding"}),
+			mockCompatibilityChecker.EXPECT().DeleteCheckPod(ctx, checkPod).Return(errors.New("some error")),
+		)
+
+		_, _, err := p.checkCompatibility(ctx, mld, pv)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should report the Module as unverified if there is no image with the Module.symvers of the kernel", func() {
+		gomock.InOrder(
+			mockCompatibilityChecker.EXPECT().GetCheckPod(ctx, "mld name", "mld namespace", pv).Return(nil, nil),
+			mockResourceManager.EXPECT().GetBuilderImage(ctx, "some kernel").Return("", errors.New("some error")),
+		)
+
+		status, reason, err := p.checkCompatibility(ctx, mld, pv)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(v1beta2.VerificationUnverified))
+		Expect(reason).To(ContainSubstring("could not be checked"))
+	})
+
+	It("should return an error if the check pods could not be listed", func() {
+		mockCompatibilityChecker.EXPECT().GetCheckPod(ctx, "mld name", "mld namespace", pv).Return(nil, errors.New("some error"))
+
+		_, _, err := p.checkCompatibility(ctx, mld, pv)
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("getModulesData", func() {
@@ -180,7 +337,7 @@ var _ = Describe("getModulesData", func() {
 		mockMic = mic.NewMockMIC(mockCtrl)
 		mockPreflight = preflight.NewMockPreflightAPI(mockCtrl)
 		mockKernel = module.NewMockKernelMapper(mockCtrl)
-		p = newPreflightReconcilerHelper(mockClient, mockMic, nil, mockKernel, mockPreflight)
+		p = newPreflightReconcilerHelper(mockClient, mockMic, nil, mockKernel, mockPreflight, nil, nil)
 	})
 
 	ctx := context.Background()
@@ -233,7 +390,7 @@ var _ = Describe("processPreflightValidation", func() {
 		mockClient = client.NewMockClient(mockCtrl)
 		mockMic = mic.NewMockMIC(mockCtrl)
 		mockPreflight = preflight.NewMockPreflightAPI(mockCtrl)
-		p = newPreflightReconcilerHelper(mockClient, mockMic, nil, nil, mockPreflight)
+		p = newPreflightReconcilerHelper(mockClient, mockMic, nil, nil, mockPreflight, nil, nil)
 	})
 
 	ctx := context.Background()
@@ -275,10 +432,10 @@ var _ = Describe("processPreflightValidation", func() {
 			mockPreflight.EXPECT().GetModuleStatus(pv, "mld namespace2", "mld name2").Return(v1beta2.VerificationFailure),
 			mockPreflight.EXPECT().GetModuleStatus(pv, "mld namespace3", "mld name3").Return(v1beta2.VerificationInProgress),
 			mockMic.EXPECT().CreateOrPatch(ctx, "mld name3-preflight", "mld namespace3", []kmmv1beta1.ModuleImageSpec{expectedMic3},
-				nil, v1.PullPolicy(""), pv.Spec.PushBuiltImage, (*int)(nil), gomock.Any(), pv).Return(nil),
+				nil, v1.PullPolicy(""), pv.Spec.PushBuiltImage, (*int)(nil), int32(0), gomock.Any(), nil, nil, pv).Return(nil),
 			mockPreflight.EXPECT().GetModuleStatus(pv, "mld namespace4", "mld name4").Return(""),
 			mockMic.EXPECT().CreateOrPatch(ctx, "mld name4-preflight", "mld namespace4", []kmmv1beta1.ModuleImageSpec{expectedMic4},
-				nil, v1.PullPolicy(""), pv.Spec.PushBuiltImage, (*int)(nil), gomock.Any(), pv).Return(nil),
+				nil, v1.PullPolicy(""), pv.Spec.PushBuiltImage, (*int)(nil), int32(0), gomock.Any(), nil, nil, pv).Return(nil),
 		)
 
 		err := p.processPreflightValidation(ctx, modsWithMapping, pv)
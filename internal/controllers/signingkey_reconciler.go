@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/metrics"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/signingkey"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=signingkeys,verbs=get;list;watch;patch;update
+//+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=signingkeys/status,verbs=get;patch;update
+//+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=signingkeys/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=create;patch
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=create;get;list;patch;watch
+
+const (
+	SigningKeyReconcilerName = "SigningKeyReconciler"
+
+	// signingKeyExpiryWarningPeriod is the period before the expiry of a certificate during which warnings are emitted
+	signingKeyExpiryWarningPeriod = 30 * 24 * time.Hour
+	// signingKeyCheckInterval is the interval at which the expiry of the certificates is checked
+	signingKeyCheckInterval = 24 * time.Hour
+)
+
+// SigningKeyReconciler generates the key pairs of SigningKeys, rotates them when requested, and reports the expiry of
+// their certificates.
+type SigningKeyReconciler struct {
+	client     client.Client
+	generator  signingkey.Generator
+	metricsAPI metrics.Metrics
+	recorder   record.EventRecorder
+	scheme     *runtime.Scheme
+}
+
+func NewSigningKeyReconciler(client client.Client, generator signingkey.Generator, metricsAPI metrics.Metrics,
+	recorder record.EventRecorder, scheme *runtime.Scheme) *SigningKeyReconciler {
+	return &SigningKeyReconciler{
+		client:     client,
+		generator:  generator,
+		metricsAPI: metricsAPI,
+		recorder:   recorder,
+		scheme:     scheme,
+	}
+}
+
+func (r *SigningKeyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kmmv1beta1.SigningKey{}).
+		Owns(&v1.Secret{}).
+		Owns(&v1.ConfigMap{}).
+		Named(SigningKeyReconcilerName).
+		Complete(
+			reconcile.AsReconciler[*kmmv1beta1.SigningKey](mgr.GetClient(), r),
+		)
+}
+
+func (r *SigningKeyReconciler) Reconcile(ctx context.Context, sk *kmmv1beta1.SigningKey) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if sk.GetDeletionTimestamp() != nil {
+		r.metricsAPI.DeleteKMMSigningKeyExpiry(sk.Name, sk.Namespace)
+		skCopy := sk.DeepCopy()
+		controllerutil.RemoveFinalizer(sk, constants.SigningKeyFinalizer)
+		return ctrl.Result{}, r.client.Patch(ctx, sk, client.MergeFrom(skCopy))
+	}
+
+	if !controllerutil.ContainsFinalizer(sk, constants.SigningKeyFinalizer) {
+		skCopy := sk.DeepCopy()
+		controllerutil.AddFinalizer(sk, constants.SigningKeyFinalizer)
+		if err := r.client.Patch(ctx, sk, client.MergeFrom(skCopy)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to set the finalizer of SigningKey %s/%s: %v", sk.Namespace, sk.Name, err)
+		}
+	}
+
+	now := time.Now()
+
+	certData, rotation, found, err := r.getCertificate(ctx, sk)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var cert *x509.Certificate
+	if found {
+		cert, err = x509.ParseCertificate(certData)
+		// the key pair may already be enrolled on the nodes: never replace it unless a rotation is requested
+		if err != nil && sk.Spec.Rotation == rotation {
+			logger.Info(utils.WarnString("Invalid certificate, not replacing the key pair"), "error", err)
+			r.recorder.Eventf(sk, v1.EventTypeWarning, "InvalidCertificate",
+				"The certificate of Secret %s is invalid; fix it, or increase .spec.rotation to generate a new key pair: %v",
+				signingkey.SecretName(sk.Name), err)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	reason := ""
+	switch {
+	case !found:
+		reason = "KeyGenerated"
+	case sk.Spec.Rotation != rotation:
+		reason = "KeyRotated"
+	}
+
+	if reason != "" {
+		if certData, err = r.generateKeyPair(ctx, sk, now); err != nil {
+			return ctrl.Result{}, err
+		}
+		if cert, err = x509.ParseCertificate(certData); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to parse the generated certificate: %v", err)
+		}
+		logger.Info("Generated a new key pair", "reason", reason, "notAfter", cert.NotAfter)
+		r.recorder.Eventf(sk, v1.EventTypeNormal, reason, "Generated a new key pair, valid until %s",
+			cert.NotAfter.Format(time.RFC3339))
+	}
+
+	if err = r.publishCertificate(ctx, sk, certData); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = r.updateStatus(ctx, sk, cert, certData); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.metricsAPI.SetKMMSigningKeyExpiry(sk.Name, sk.Namespace, cert.NotAfter)
+
+	switch {
+	case !now.Before(cert.NotAfter):
+		r.recorder.Eventf(sk, v1.EventTypeWarning, "CertificateExpired", "The certificate expired on %s",
+			cert.NotAfter.Format(time.RFC3339))
+	case sk.Spec.RenewBefore != nil && !now.Before(cert.NotAfter.Add(-sk.Spec.RenewBefore.Duration)):
+		r.recorder.Eventf(sk, v1.EventTypeWarning, "KeyRenewalDue",
+			"The certificate expires on %s; increase .spec.rotation to rotate the key pair, then enroll the new certificate",
+			cert.NotAfter.Format(time.RFC3339))
+	case !now.Before(cert.NotAfter.Add(-signingKeyExpiryWarningPeriod)):
+		r.recorder.Eventf(sk, v1.EventTypeWarning, "CertificateExpiring", "The certificate expires on %s",
+			cert.NotAfter.Format(time.RFC3339))
+	}
+
+	res := ctrl.Result{RequeueAfter: signingKeyCheckInterval}
+	if sk.Spec.RenewBefore != nil {
+		if untilRenewal := cert.NotAfter.Add(-sk.Spec.RenewBefore.Duration).Sub(now); untilRenewal > 0 && untilRenewal < res.RequeueAfter {
+			res.RequeueAfter = untilRenewal
+		}
+	}
+
+	return res, nil
+}
+
+// getCertificate returns the certificate held by the SigningKey's Secret and the rotation the key pair was generated
+// for, or false if there is no private key yet. The rotation is recorded on the Secret together with the key pair, so
+// that a rotation is not handled twice if the status could not be updated afterwards.
+func (r *SigningKeyReconciler) getCertificate(ctx context.Context, sk *kmmv1beta1.SigningKey) ([]byte, int64, bool, error) {
+	secret := v1.Secret{}
+	secretName := signingkey.SecretName(sk.Name)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: secretName, Namespace: sk.Namespace}, &secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, 0, false, nil
+		}
+		return nil, 0, false, fmt.Errorf("failed to get Secret %s/%s: %v", sk.Namespace, secretName, err)
+	}
+	if len(secret.Data[constants.PrivateSignDataKey]) == 0 {
+		return nil, 0, false, nil
+	}
+
+	// Secrets generated before the rotation was recorded on them match the status
+	rotation := sk.Status.Rotation
+	if value, ok := secret.Annotations[signingkey.RotationAnnotation]; ok {
+		var err error
+		if rotation, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, 0, false, fmt.Errorf("invalid value %q for annotation %s of Secret %s/%s: %v",
+				value, signingkey.RotationAnnotation, sk.Namespace, secretName, err)
+		}
+	}
+
+	return secret.Data[constants.PublicSignDataKey], rotation, true, nil
+}
+
+func (r *SigningKeyReconciler) generateKeyPair(ctx context.Context, sk *kmmv1beta1.SigningKey, now time.Time) ([]byte, error) {
+	if sk.Spec.Validity.Duration <= 0 {
+		return nil, errors.New("the validity of the certificate must be positive")
+	}
+
+	commonName := sk.Spec.CommonName
+	if commonName == "" {
+		commonName = sk.Name
+	}
+
+	keyPair, err := r.generator.Generate(commonName, int(sk.Spec.KeySize), now, sk.Spec.Validity.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the key pair of SigningKey %s/%s: %v", sk.Namespace, sk.Name, err)
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: signingkey.SecretName(sk.Name), Namespace: sk.Namespace},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, r.client, secret, func() error {
+		metav1.SetMetaDataAnnotation(&secret.ObjectMeta, signingkey.RotationAnnotation, strconv.FormatInt(sk.Spec.Rotation, 10))
+		secret.Type = v1.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			constants.PrivateSignDataKey: keyPair.PrivateKey,
+			constants.PublicSignDataKey:  keyPair.Certificate,
+		}
+		return controllerutil.SetControllerReference(sk, secret, r.scheme)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create or patch Secret %s/%s: %v", secret.Namespace, secret.Name, err)
+	}
+
+	return keyPair.Certificate, nil
+}
+
+// publishCertificate copies the DER certificate to a ConfigMap, so that it can be read to be enrolled on the nodes
+// without giving access to the private key.
+func (r *SigningKeyReconciler) publishCertificate(ctx context.Context, sk *kmmv1beta1.SigningKey, certData []byte) error {
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: signingkey.CertificateConfigMapName(sk.Name), Namespace: sk.Namespace},
+	}
+	_, err := controllerutil.CreateOrPatch(ctx, r.client, cm, func() error {
+		cm.BinaryData = map[string][]byte{signingkey.CertificateDataKey: certData}
+		return controllerutil.SetControllerReference(sk, cm, r.scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or patch ConfigMap %s/%s: %v", cm.Namespace, cm.Name, err)
+	}
+	return nil
+}
+
+func (r *SigningKeyReconciler) updateStatus(ctx context.Context, sk *kmmv1beta1.SigningKey, cert *x509.Certificate,
+	certData []byte) error {
+
+	skCopy := sk.DeepCopy()
+	sk.Status = kmmv1beta1.SigningKeyStatus{
+		SecretName:           signingkey.SecretName(sk.Name),
+		CertificateConfigMap: signingkey.CertificateConfigMapName(sk.Name),
+		NotBefore:            &metav1.Time{Time: cert.NotBefore},
+		NotAfter:             &metav1.Time{Time: cert.NotAfter},
+		SerialNumber:         cert.SerialNumber.Text(16),
+		Fingerprint:          signingkey.Fingerprint(certData),
+		Rotation:             sk.Spec.Rotation,
+	}
+	if err := r.client.Status().Patch(ctx, sk, client.MergeFrom(skCopy)); err != nil {
+		return fmt.Errorf("failed to patch the status of SigningKey %s/%s: %v", sk.Namespace, sk.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/metrics"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/signingkey"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("SigningKeyReconciler_Reconcile", func() {
+	const (
+		skName      = "sk"
+		skNamespace = "some-namespace"
+	)
+
+	var (
+		ctrl          *gomock.Controller
+		clnt          *client.MockClient
+		statusWriter  *client.MockStatusWriter
+		mockGenerator *signingkey.MockGenerator
+		mockMetrics   *metrics.MockMetrics
+		fakeRecorder  *record.FakeRecorder
+		r             *SigningKeyReconciler
+		sk            *kmmv1beta1.SigningKey
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		statusWriter = client.NewMockStatusWriter(ctrl)
+		mockGenerator = signingkey.NewMockGenerator(ctrl)
+		mockMetrics = metrics.NewMockMetrics(ctrl)
+		fakeRecorder = record.NewFakeRecorder(10)
+		r = NewSigningKeyReconciler(clnt, mockGenerator, mockMetrics, fakeRecorder, scheme)
+		sk = &kmmv1beta1.SigningKey{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       skName,
+				Namespace:  skNamespace,
+				Finalizers: []string{constants.SigningKeyFinalizer},
+			},
+			Spec: kmmv1beta1.SigningKeySpec{
+				KeySize:  2048,
+				Validity: metav1.Duration{Duration: 365 * 24 * time.Hour},
+			},
+		}
+	})
+
+	ctx := context.Background()
+	secretNSN := types.NamespacedName{Name: "sk-signing-key", Namespace: skNamespace}
+	cmNSN := types.NamespacedName{Name: "sk-certificate", Namespace: skNamespace}
+	notFound := k8serrors.NewNotFound(schema.GroupResource{}, "whatever")
+
+	generateKeyPair := func(validity time.Duration) *signingkey.KeyPair {
+		keyPair, err := signingkey.NewGenerator().Generate(skName, 2048, time.Now().Add(-time.Hour), validity)
+		Expect(err).NotTo(HaveOccurred())
+		return keyPair
+	}
+
+	expectSecret := func(keyPair *signingkey.KeyPair) *gomock.Call {
+		return clnt.EXPECT().Get(ctx, secretNSN, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+				if keyPair == nil {
+					return notFound
+				}
+				secret.Data = map[string][]byte{
+					constants.PrivateSignDataKey: keyPair.PrivateKey,
+					constants.PublicSignDataKey:  keyPair.Certificate,
+				}
+				return nil
+			},
+		)
+	}
+
+	expectGeneration := func(keyPair *signingkey.KeyPair) {
+		gomock.InOrder(
+			mockGenerator.EXPECT().Generate(skName, 2048, gomock.Any(), 365*24*time.Hour).Return(keyPair, nil),
+			clnt.EXPECT().Get(ctx, secretNSN, gomock.Any()).Return(notFound),
+			clnt.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ interface{}, secret *v1.Secret, _ ...ctrlclient.CreateOption) error {
+					Expect(secret.Data).To(HaveKeyWithValue(constants.PrivateSignDataKey, keyPair.PrivateKey))
+					Expect(secret.Data).To(HaveKeyWithValue(constants.PublicSignDataKey, keyPair.Certificate))
+					Expect(secret.Annotations).To(
+						HaveKeyWithValue(signingkey.RotationAnnotation, strconv.FormatInt(sk.Spec.Rotation, 10)),
+					)
+					Expect(metav1.IsControlledBy(secret, sk)).To(BeTrue())
+					return nil
+				},
+			),
+		)
+	}
+
+	expectCertificateAndStatus := func(keyPair *signingkey.KeyPair) {
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, cmNSN, gomock.Any()).Return(notFound),
+			clnt.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ interface{}, cm *v1.ConfigMap, _ ...ctrlclient.CreateOption) error {
+					Expect(cm.BinaryData).To(HaveKeyWithValue(signingkey.CertificateDataKey, keyPair.Certificate))
+					return nil
+				},
+			),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, sk, gomock.Any()),
+			mockMetrics.EXPECT().SetKMMSigningKeyExpiry(skName, skNamespace, gomock.Any()),
+		)
+	}
+
+	It("should release the finalizer of a deleted SigningKey", func() {
+		sk.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+		gomock.InOrder(
+			mockMetrics.EXPECT().DeleteKMMSigningKeyExpiry(skName, skNamespace),
+			clnt.EXPECT().Patch(ctx, sk, gomock.Any()),
+		)
+
+		_, err := r.Reconcile(ctx, sk)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sk.Finalizers).To(BeEmpty())
+	})
+
+	It("should generate the key pair of a new SigningKey", func() {
+		sk.Finalizers = nil
+		keyPair := generateKeyPair(365 * 24 * time.Hour)
+
+		gomock.InOrder(
+			clnt.EXPECT().Patch(ctx, sk, gomock.Any()),
+			expectSecret(nil),
+		)
+		expectGeneration(keyPair)
+		expectCertificateAndStatus(keyPair)
+
+		res, err := r.Reconcile(ctx, sk)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(signingKeyCheckInterval))
+		Expect(sk.Finalizers).To(ContainElement(constants.SigningKeyFinalizer))
+		Expect(sk.Status.SecretName).To(Equal("sk-signing-key"))
+		Expect(sk.Status.CertificateConfigMap).To(Equal("sk-certificate"))
+		Expect(sk.Status.Fingerprint).To(Equal(signingkey.Fingerprint(keyPair.Certificate)))
+		Expect(sk.Status.NotAfter).NotTo(BeNil())
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("KeyGenerated")))
+	})
+
+	It("should keep a valid key pair", func() {
+		keyPair := generateKeyPair(365 * 24 * time.Hour)
+
+		expectSecret(keyPair)
+		expectCertificateAndStatus(keyPair)
+
+		_, err := r.Reconcile(ctx, sk)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sk.Status.Fingerprint).To(Equal(signingkey.Fingerprint(keyPair.Certificate)))
+		Expect(fakeRecorder.Events).To(BeEmpty())
+	})
+
+	It("should rotate the key pair when requested", func() {
+		sk.Spec.Rotation = 1
+		oldKeyPair := generateKeyPair(365 * 24 * time.Hour)
+		newKeyPair := generateKeyPair(365 * 24 * time.Hour)
+
+		expectSecret(oldKeyPair)
+		expectGeneration(newKeyPair)
+		expectCertificateAndStatus(newKeyPair)
+
+		_, err := r.Reconcile(ctx, sk)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sk.Status.Rotation).To(Equal(int64(1)))
+		Expect(sk.Status.Fingerprint).To(Equal(signingkey.Fingerprint(newKeyPair.Certificate)))
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("KeyRotated")))
+	})
+
+	It("should not rotate the key pair again if the status could not be updated after the rotation", func() {
+		sk.Spec.Rotation = 1
+		keyPair := generateKeyPair(365 * 24 * time.Hour)
+
+		clnt.EXPECT().Get(ctx, secretNSN, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+				secret.Annotations = map[string]string{signingkey.RotationAnnotation: "1"}
+				secret.Data = map[string][]byte{
+					constants.PrivateSignDataKey: keyPair.PrivateKey,
+					constants.PublicSignDataKey:  keyPair.Certificate,
+				}
+				return nil
+			},
+		)
+		expectCertificateAndStatus(keyPair)
+
+		_, err := r.Reconcile(ctx, sk)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sk.Status.Rotation).To(Equal(int64(1)))
+		Expect(sk.Status.Fingerprint).To(Equal(signingkey.Fingerprint(keyPair.Certificate)))
+		Expect(fakeRecorder.Events).To(BeEmpty())
+	})
+
+	It("should return an error if the rotation recorded on the Secret is invalid", func() {
+		keyPair := generateKeyPair(365 * 24 * time.Hour)
+
+		clnt.EXPECT().Get(ctx, secretNSN, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+				secret.Annotations = map[string]string{signingkey.RotationAnnotation: "one"}
+				secret.Data = map[string][]byte{
+					constants.PrivateSignDataKey: keyPair.PrivateKey,
+					constants.PublicSignDataKey:  keyPair.Certificate,
+				}
+				return nil
+			},
+		)
+
+		_, err := r.Reconcile(ctx, sk)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not rotate the key pair automatically before the certificate expires", func() {
+		sk.Spec.RenewBefore = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+		keyPair := generateKeyPair(10 * 24 * time.Hour)
+
+		expectSecret(keyPair)
+		expectCertificateAndStatus(keyPair)
+
+		_, err := r.Reconcile(ctx, sk)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sk.Status.Fingerprint).To(Equal(signingkey.Fingerprint(keyPair.Certificate)))
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("KeyRenewalDue")))
+	})
+
+	It("should not replace a key pair whose certificate is invalid", func() {
+		keyPair := generateKeyPair(365 * 24 * time.Hour)
+		keyPair.Certificate = []byte("invalid")
+
+		expectSecret(keyPair)
+
+		_, err := r.Reconcile(ctx, sk)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sk.Status.Fingerprint).To(BeEmpty())
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("InvalidCertificate")))
+	})
+
+	It("should not replace a private key without a certificate", func() {
+		keyPair := generateKeyPair(365 * 24 * time.Hour)
+		keyPair.Certificate = nil
+
+		expectSecret(keyPair)
+
+		_, err := r.Reconcile(ctx, sk)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("InvalidCertificate")))
+	})
+
+	It("should replace a key pair whose certificate is invalid when a rotation is requested", func() {
+		sk.Spec.Rotation = 1
+		oldKeyPair := generateKeyPair(365 * 24 * time.Hour)
+		oldKeyPair.Certificate = []byte("invalid")
+		newKeyPair := generateKeyPair(365 * 24 * time.Hour)
+
+		expectSecret(oldKeyPair)
+		expectGeneration(newKeyPair)
+		expectCertificateAndStatus(newKeyPair)
+
+		_, err := r.Reconcile(ctx, sk)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sk.Status.Fingerprint).To(Equal(signingkey.Fingerprint(newKeyPair.Certificate)))
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("KeyRotated")))
+	})
+
+	It("should requeue when the key pair is due for renewal", func() {
+		sk.Spec.RenewBefore = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+		keyPair := generateKeyPair(30*24*time.Hour + 2*time.Hour)
+
+		expectSecret(keyPair)
+		expectCertificateAndStatus(keyPair)
+
+		res, err := r.Reconcile(ctx, sk)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(BeNumerically("<=", time.Hour))
+	})
+
+	It("should warn about a certificate that expires soon", func() {
+		keyPair := generateKeyPair(10 * 24 * time.Hour)
+
+		expectSecret(keyPair)
+		expectCertificateAndStatus(keyPair)
+
+		_, err := r.Reconcile(ctx, sk)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("CertificateExpiring")))
+	})
+
+	It("should warn about an expired certificate", func() {
+		keyPair := generateKeyPair(time.Minute)
+
+		expectSecret(keyPair)
+		expectCertificateAndStatus(keyPair)
+
+		_, err := r.Reconcile(ctx, sk)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeRecorder.Events).To(Receive(ContainSubstring("CertificateExpired")))
+	})
+})
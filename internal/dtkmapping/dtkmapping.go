@@ -0,0 +1,61 @@
+package dtkmapping
+
+import (
+	"context"
+	"fmt"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/syncronizedmap"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//go:generate mockgen -source=dtkmapping.go -package=dtkmapping -destination=mock_dtkmapping.go
+
+type DTKMapping interface {
+	GetImage(ctx context.Context, kernelVersion string) (string, error)
+}
+
+type dtkMapping struct {
+	client             client.Client
+	kernelOsDtkMapping syncronizedmap.KernelOsDtkMapping
+}
+
+func New(client client.Client, kernelOsDtkMapping syncronizedmap.KernelOsDtkMapping) DTKMapping {
+	return &dtkMapping{
+		client:             client,
+		kernelOsDtkMapping: kernelOsDtkMapping,
+	}
+}
+
+// GetImage returns the DTK image of kernelVersion from the overrides of the KernelDTKMapping, then from the mapping
+// built in memory from the nodes and the driver-toolkit ImageStream, and finally from the status of the KernelDTKMapping
+// for the kernels that the nodes do not run anymore.
+func (dm *dtkMapping) GetImage(ctx context.Context, kernelVersion string) (string, error) {
+	mapping := kmmv1beta1.KernelDTKMapping{}
+	err := dm.client.Get(ctx, types.NamespacedName{Name: constants.KernelDTKMappingName}, &mapping)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get KernelDTKMapping %s: %v", constants.KernelDTKMappingName, err)
+	}
+
+	for _, o := range mapping.Spec.Overrides {
+		if o.KernelVersion == kernelVersion {
+			return o.DTKImage, nil
+		}
+	}
+
+	image, err := dm.kernelOsDtkMapping.GetImage(kernelVersion)
+	if err == nil {
+		return image, nil
+	}
+
+	for _, k := range mapping.Status.Kernels {
+		if k.KernelVersion == kernelVersion {
+			return k.DTKImage, nil
+		}
+	}
+
+	return "", err
+}
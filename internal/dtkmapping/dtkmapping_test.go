@@ -0,0 +1,112 @@
+package dtkmapping
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/syncronizedmap"
+	"go.uber.org/mock/gomock"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("GetImage", func() {
+	const kernelVersion = "5.14.0-284.el9.x86_64"
+
+	var (
+		ctrl      *gomock.Controller
+		clnt      *client.MockClient
+		mockKODM  *syncronizedmap.MockKernelOsDtkMapping
+		dm        DTKMapping
+		mapping   kmmv1beta1.KernelDTKMapping
+		mappingNS = types.NamespacedName{Name: "default"}
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mockKODM = syncronizedmap.NewMockKernelOsDtkMapping(ctrl)
+		dm = New(clnt, mockKODM)
+		mapping = kmmv1beta1.KernelDTKMapping{}
+	})
+
+	ctx := context.Background()
+
+	expectMapping := func() *gomock.Call {
+		return clnt.EXPECT().Get(ctx, mappingNS, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, m *kmmv1beta1.KernelDTKMapping, _ ...ctrlclient.GetOption) error {
+				mapping.DeepCopyInto(m)
+				return nil
+			},
+		)
+	}
+
+	It("should return an error if the KernelDTKMapping could not be read", func() {
+		clnt.EXPECT().Get(ctx, mappingNS, gomock.Any()).Return(errors.New("random error"))
+
+		_, err := dm.GetImage(ctx, kernelVersion)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should prefer an override", func() {
+		mapping.Spec.Overrides = []kmmv1beta1.KernelDTKOverride{{KernelVersion: kernelVersion, DTKImage: "override"}}
+		mapping.Status.Kernels = []kmmv1beta1.KernelDTKStatus{{KernelVersion: kernelVersion, DTKImage: "persisted"}}
+		expectMapping()
+
+		image, err := dm.GetImage(ctx, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image).To(Equal("override"))
+	})
+
+	It("should prefer the in-memory mapping over the persisted image", func() {
+		mapping.Status.Kernels = []kmmv1beta1.KernelDTKStatus{{KernelVersion: kernelVersion, DTKImage: "persisted"}}
+		gomock.InOrder(
+			expectMapping(),
+			mockKODM.EXPECT().GetImage(kernelVersion).Return("live", nil),
+		)
+
+		image, err := dm.GetImage(ctx, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image).To(Equal("live"))
+	})
+
+	It("should return the persisted image of a kernel that the nodes do not run anymore", func() {
+		mapping.Spec.Overrides = []kmmv1beta1.KernelDTKOverride{{KernelVersion: "other-kernel", DTKImage: "override"}}
+		mapping.Status.Kernels = []kmmv1beta1.KernelDTKStatus{{KernelVersion: kernelVersion, DTKImage: "persisted"}}
+		gomock.InOrder(
+			expectMapping(),
+			mockKODM.EXPECT().GetImage(kernelVersion).Return("", errors.New("not found")),
+		)
+
+		image, err := dm.GetImage(ctx, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image).To(Equal("persisted"))
+	})
+
+	It("should return an error if the kernel is not known", func() {
+		gomock.InOrder(
+			expectMapping(),
+			mockKODM.EXPECT().GetImage(kernelVersion).Return("", errors.New("not found")),
+		)
+
+		_, err := dm.GetImage(ctx, kernelVersion)
+		Expect(err).To(MatchError("not found"))
+	})
+
+	It("should fall back to the in-memory mapping", func() {
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, mappingNS, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "default")),
+			mockKODM.EXPECT().GetImage(kernelVersion).Return("live", nil),
+		)
+
+		image, err := dm.GetImage(ctx, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image).To(Equal("live"))
+	})
+})
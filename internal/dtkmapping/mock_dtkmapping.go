@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dtkmapping.go
+//
+// Generated by this command:
+//
+//	mockgen -source=dtkmapping.go -package=dtkmapping -destination=mock_dtkmapping.go
+//
+// Package dtkmapping is a generated GoMock package.
+package dtkmapping
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDTKMapping is a mock of DTKMapping interface.
+type MockDTKMapping struct {
+	ctrl     *gomock.Controller
+	recorder *MockDTKMappingMockRecorder
+}
+
+// MockDTKMappingMockRecorder is the mock recorder for MockDTKMapping.
+type MockDTKMappingMockRecorder struct {
+	mock *MockDTKMapping
+}
+
+// NewMockDTKMapping creates a new mock instance.
+func NewMockDTKMapping(ctrl *gomock.Controller) *MockDTKMapping {
+	mock := &MockDTKMapping{ctrl: ctrl}
+	mock.recorder = &MockDTKMappingMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDTKMapping) EXPECT() *MockDTKMappingMockRecorder {
+	return m.recorder
+}
+
+// GetImage mocks base method.
+func (m *MockDTKMapping) GetImage(ctx context.Context, kernelVersion string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImage", ctx, kernelVersion)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImage indicates an expected call of GetImage.
+func (mr *MockDTKMappingMockRecorder) GetImage(ctx, kernelVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImage", reflect.TypeOf((*MockDTKMapping)(nil).GetImage), ctx, kernelVersion)
+}
@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/kubectl/pkg/util/podutils"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -19,6 +20,7 @@ import (
 
 	hubv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	kmmcluster "github.com/rh-ecosystem-edge/kernel-module-management/internal/cluster"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/nmc"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
@@ -82,13 +84,19 @@ var kmmClusterClaimChanged predicate.Predicate = predicate.Funcs{
 			return false
 		}
 
-		newClusterClaim := clusterClaim(constants.KernelVersionsClusterClaimName, newManagedCluster.Status.ClusterClaims)
-		if newClusterClaim == nil {
-			return false
+		for _, name := range []string{constants.KernelVersionsClusterClaimName, constants.KernelsClusterClaimName} {
+			newClusterClaim := clusterClaim(name, newManagedCluster.Status.ClusterClaims)
+			if newClusterClaim == nil {
+				continue
+			}
+			oldClusterClaim := clusterClaim(name, oldManagedCluster.Status.ClusterClaims)
+
+			if !reflect.DeepEqual(newClusterClaim, oldClusterClaim) {
+				return true
+			}
 		}
-		oldClusterClaim := clusterClaim(constants.KernelVersionsClusterClaimName, oldManagedCluster.Status.ClusterClaims)
 
-		return !reflect.DeepEqual(newClusterClaim, oldClusterClaim)
+		return false
 	},
 }
 
@@ -269,7 +277,8 @@ func NodeUpdateKernelChangedPredicate() predicate.Predicate {
 				return false
 			}
 
-			return oldNode.Status.NodeInfo.KernelVersion != newNode.Status.NodeInfo.KernelVersion
+			return oldNode.Status.NodeInfo.KernelVersion != newNode.Status.NodeInfo.KernelVersion ||
+				oldNode.Status.NodeInfo.OSImage != newNode.Status.NodeInfo.OSImage
 		},
 	}
 }
@@ -399,6 +408,22 @@ func (f *Filter) FindManagedClusterModulesForCluster(ctx context.Context, cluste
 
 		logger.V(1).Info("Processing ManagedClusterModule")
 
+		if placement := mod.Spec.Placement; placement != nil {
+			clusterNames, err := kmmcluster.PlacementClusterNames(ctx, f.client, placement.Namespace, placement.Name)
+			if err != nil {
+				logger.Error(err, "could not determine if cluster is selected by ManagedClusterModule's Placement")
+				continue
+			}
+
+			if !clusterNames.Has(cluster.GetName()) {
+				logger.V(1).Info("Cluster is not selected by the ManagedClusterModule's Placement; skipping")
+				continue
+			}
+
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: mod.Name}})
+			continue
+		}
+
 		mcmSelectorMatchCluster, err := utils.IsObjectSelectedByLabels(cluster.GetLabels(), mod.Spec.Selector)
 		if err != nil {
 			logger.Error(err, "could not determine if cluster is selected by ManagedClusterModule", "node", cluster.GetName(), "module", mod.Name)
@@ -421,6 +446,46 @@ func (f *Filter) FindManagedClusterModulesForCluster(ctx context.Context, cluste
 	return reqs
 }
 
+// FindManagedClusterModulesForPlacementDecision returns the ManagedClusterModules referencing the Placement that
+// the PlacementDecision belongs to.
+func (f *Filter) FindManagedClusterModulesForPlacementDecision(ctx context.Context, decision client.Object) []reconcile.Request {
+	placementName := decision.GetLabels()[clusterv1beta1.PlacementLabel]
+
+	logger := ctrl.LoggerFrom(ctx).WithValues(
+		"placementdecision", decision.GetName(),
+		"placement", placementName,
+		"namespace", decision.GetNamespace(),
+	)
+
+	reqs := make([]reconcile.Request, 0)
+
+	if placementName == "" {
+		logger.V(1).Info("PlacementDecision does not belong to a Placement")
+		return reqs
+	}
+
+	mods := hubv1beta1.ManagedClusterModuleList{}
+
+	if err := f.client.List(ctx, &mods); err != nil {
+		logger.Error(err, "could not list ManagedClusterModules")
+		return reqs
+	}
+
+	for _, mod := range mods.Items {
+		placement := mod.Spec.Placement
+		if placement == nil || placement.Name != placementName || placement.Namespace != decision.GetNamespace() {
+			continue
+		}
+
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: mod.Name}})
+	}
+
+	logger.Info("Adding reconciliation requests", "count", len(reqs))
+	logger.V(1).Info("New requests", "requests", reqs)
+
+	return reqs
+}
+
 func (f *Filter) ManagedClusterModuleReconcilerManagedClusterPredicate() predicate.Predicate {
 	return predicate.Or(
 		predicate.LabelChangedPredicate{},
@@ -485,6 +550,29 @@ func PodReadinessChangedPredicate(logger logr.Logger) predicate.Predicate {
 	}
 }
 
+// EnqueueAllManagedClusterPreflightValidations returns the ManagedClusterPreflightValidations that are not being
+// deleted, so that a change to a ManagedClusterModule is validated by all of them.
+func (f *Filter) EnqueueAllManagedClusterPreflightValidations(ctx context.Context, mcm client.Object) []reconcile.Request {
+	reqs := make([]reconcile.Request, 0)
+
+	logger := ctrl.LoggerFrom(ctx).WithValues("managedclustermodule", mcm.GetName())
+	logger.Info("Listing all managed cluster preflights")
+	preflights := hubv1beta1.ManagedClusterPreflightValidationList{}
+	if err := f.client.List(ctx, &preflights); err != nil {
+		logger.Error(err, "could not list managed cluster preflights")
+		return reqs
+	}
+
+	for _, preflight := range preflights.Items {
+		// skip the preflight being deleted
+		if preflight.GetDeletionTimestamp() != nil {
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: preflight.Name}})
+	}
+	return reqs
+}
+
 func PreflightReconcilerUpdatePredicate() predicate.Predicate {
 	return predicate.GenerationChangedPredicate{}
 }
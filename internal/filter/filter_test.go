@@ -2,6 +2,7 @@ package filter
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -17,6 +18,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -257,6 +259,20 @@ var _ = Describe("kmmClusterClaimChanged", func() {
 			},
 		},
 	}
+	managedCluster3 := clusterv1.ManagedCluster{
+		Status: clusterv1.ManagedClusterStatus{
+			ClusterClaims: []clusterv1.ManagedClusterClaim{
+				{
+					Name:  constants.KernelVersionsClusterClaimName,
+					Value: "a-kernel-version",
+				},
+				{
+					Name:  constants.KernelsClusterClaimName,
+					Value: `[{"kernelVersion":"a-kernel-version","architecture":"amd64"}]`,
+				},
+			},
+		},
+	}
 
 	DescribeTable(
 		"should work as expected",
@@ -272,6 +288,8 @@ var _ = Describe("kmmClusterClaimChanged", func() {
 		Entry(nil, event.UpdateEvent{ObjectOld: &managedCluster1, ObjectNew: &clusterv1.ManagedCluster{}}, false),
 		Entry(nil, event.UpdateEvent{ObjectOld: &managedCluster1, ObjectNew: &managedCluster1}, false),
 		Entry(nil, event.UpdateEvent{ObjectOld: &managedCluster1, ObjectNew: &managedCluster2}, true),
+		Entry(nil, event.UpdateEvent{ObjectOld: &managedCluster1, ObjectNew: &managedCluster3}, true),
+		Entry(nil, event.UpdateEvent{ObjectOld: &managedCluster3, ObjectNew: &managedCluster3}, false),
 	)
 })
 
@@ -501,6 +519,12 @@ var _ = Describe("NodeUpdateKernelChangedPredicate", func() {
 		},
 	}
 
+	node3 := v1.Node{
+		Status: v1.NodeStatus{
+			NodeInfo: v1.NodeSystemInfo{KernelVersion: "v1", OSImage: "os-2"},
+		},
+	}
+
 	DescribeTable(
 		"should work as expected",
 		func(updateEvent event.UpdateEvent, expectedResult bool) {
@@ -515,6 +539,7 @@ var _ = Describe("NodeUpdateKernelChangedPredicate", func() {
 		Entry(nil, event.UpdateEvent{ObjectOld: &v1.Node{}, ObjectNew: &v1.Pod{}}, false),
 		Entry(nil, event.UpdateEvent{ObjectOld: &node1, ObjectNew: &node1}, false),
 		Entry(nil, event.UpdateEvent{ObjectOld: &node1, ObjectNew: &node2}, true),
+		Entry(nil, event.UpdateEvent{ObjectOld: &node1, ObjectNew: &node3}, true),
 	)
 })
 
@@ -773,6 +798,120 @@ var _ = Describe("FindManagedClusterModulesForCluster", func() {
 		reqs := f.FindManagedClusterModulesForCluster(ctx, &cluster)
 		Expect(reqs).To(Equal([]reconcile.Request{expectedReq}))
 	})
+
+	It("should return the ManagedClusterModules whose Placement selects the cluster", func() {
+		cluster := clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		}
+
+		matchingMod := hubv1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "matching-mod"},
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				Placement: &hubv1beta1.PlacementReference{Name: "matching-placement", Namespace: "ns"},
+			},
+		}
+
+		mod := hubv1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "mod"},
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				Placement: &hubv1beta1.PlacementReference{Name: "placement", Namespace: "ns"},
+			},
+		}
+
+		decisions := func(clusterName string) func(_ interface{}, list *clusterv1beta1.PlacementDecisionList, _ ...interface{}) error {
+			return func(_ interface{}, list *clusterv1beta1.PlacementDecisionList, _ ...interface{}) error {
+				list.Items = []clusterv1beta1.PlacementDecision{
+					{
+						Status: clusterv1beta1.PlacementDecisionStatus{
+							Decisions: []clusterv1beta1.ClusterDecision{{ClusterName: clusterName}},
+						},
+					},
+				}
+				return nil
+			}
+		}
+
+		gomock.InOrder(
+			clnt.EXPECT().List(context.Background(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *hubv1beta1.ManagedClusterModuleList, _ ...interface{}) error {
+					list.Items = []hubv1beta1.ManagedClusterModule{matchingMod, mod}
+					return nil
+				},
+			),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(decisions("cluster")),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(decisions("other-cluster")),
+		)
+
+		expectedReq := reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: matchingMod.Name},
+		}
+
+		reqs := f.FindManagedClusterModulesForCluster(ctx, &cluster)
+		Expect(reqs).To(Equal([]reconcile.Request{expectedReq}))
+	})
+})
+
+var _ = Describe("FindManagedClusterModulesForPlacementDecision", func() {
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		clnt = mockClient.NewMockClient(mockCtrl)
+		f = New(clnt, nil)
+	})
+
+	ctx := context.Background()
+
+	It("should return nothing if the PlacementDecision has no Placement label", func() {
+		Expect(
+			f.FindManagedClusterModulesForPlacementDecision(ctx, &clusterv1beta1.PlacementDecision{}),
+		).To(
+			BeEmpty(),
+		)
+	})
+
+	It("should return only ManagedClusterModules referencing the Placement", func() {
+		decision := clusterv1beta1.PlacementDecision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "placement-decision-1",
+				Namespace: "ns",
+				Labels:    map[string]string{clusterv1beta1.PlacementLabel: "placement"},
+			},
+		}
+
+		matchingMod := hubv1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "matching-mod"},
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				Placement: &hubv1beta1.PlacementReference{Name: "placement", Namespace: "ns"},
+			},
+		}
+
+		otherNamespaceMod := hubv1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-namespace-mod"},
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				Placement: &hubv1beta1.PlacementReference{Name: "placement", Namespace: "other-ns"},
+			},
+		}
+
+		selectorMod := hubv1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "selector-mod"},
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				Selector: map[string]string{"key": "value"},
+			},
+		}
+
+		clnt.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *hubv1beta1.ManagedClusterModuleList, _ ...interface{}) error {
+				list.Items = []hubv1beta1.ManagedClusterModule{matchingMod, otherNamespaceMod, selectorMod}
+				return nil
+			},
+		)
+
+		expectedReq := reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: matchingMod.Name},
+		}
+
+		reqs := f.FindManagedClusterModulesForPlacementDecision(ctx, &decision)
+		Expect(reqs).To(Equal([]reconcile.Request{expectedReq}))
+	})
 })
 
 var _ = Describe("ManagedClusterModuleReconcilerManagedClusterPredicate", func() {
@@ -977,6 +1116,45 @@ var _ = Describe("FindPreflightsForModule", func() {
 
 })
 
+var _ = Describe("EnqueueAllManagedClusterPreflightValidations", func() {
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		clnt = mockClient.NewMockClient(mockCtrl)
+		f = New(clnt, nil)
+	})
+
+	ctx := context.Background()
+
+	It("should return nothing if listing the preflights fails", func() {
+		clnt.EXPECT().List(ctx, gomock.Any()).Return(errors.New("some error"))
+
+		res := f.EnqueueAllManagedClusterPreflightValidations(ctx, &hubv1beta1.ManagedClusterModule{})
+		Expect(res).To(BeEmpty())
+	})
+
+	It("should return the preflights that are not being deleted", func() {
+		now := metav1.Now()
+
+		clnt.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *hubv1beta1.ManagedClusterPreflightValidationList, _ ...interface{}) error {
+				list.Items = []hubv1beta1.ManagedClusterPreflightValidation{
+					{ObjectMeta: metav1.ObjectMeta{Name: "preflight"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "deleted-preflight", DeletionTimestamp: &now}},
+				}
+				return nil
+			},
+		)
+
+		expectedRes := []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Name: "preflight"}},
+		}
+
+		res := f.EnqueueAllManagedClusterPreflightValidations(ctx, &hubv1beta1.ManagedClusterModule{})
+		Expect(res).To(Equal(expectedRes))
+	})
+})
+
 var _ = Describe("ImageStreamReconcilerPredicate", func() {
 
 	var p predicate.Predicate = New(nil, nil).ImageStreamReconcilerPredicate()
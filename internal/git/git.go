@@ -0,0 +1,205 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	uploadPackService           = "git-upload-pack"
+	uploadPackAdvertisementType = "application/x-" + uploadPackService + "-advertisement"
+
+	// peeledSuffix is appended by the servers to annotated tags to advertise the commit they point to.
+	peeledSuffix = "^{}"
+
+	requestTimeout = 30 * time.Second
+)
+
+//go:generate mockgen -source=git.go -package=git -destination=mock_git.go
+
+type Git interface {
+	ResolveRef(ctx context.Context, uri, ref, namespace string, sourceSecret *v1.LocalObjectReference) (string, error)
+}
+
+type git struct {
+	client     client.Client
+	httpClient *http.Client
+}
+
+func NewGit(client client.Client) Git {
+	return &git{
+		client:     client,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// ResolveRef resolves ref, a branch, a tag or a commit of the Git repository at uri, to the commit it points to. An
+// empty ref resolves to the default branch of the repository.
+// The references are listed with the smart HTTP protocol, authenticated with the username and password of
+// sourceSecret, if any. The refs of repositories that are not served over HTTP(S), such as SSH ones, cannot be listed:
+// only full commits can be used for them.
+func (g *git) ResolveRef(ctx context.Context, uri, ref, namespace string, sourceSecret *v1.LocalObjectReference) (string, error) {
+	if isCommit(ref) {
+		return ref, nil
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("ref %q must be a full commit: Git repository %s is not served over HTTP(S)", ref, uri)
+	}
+
+	refs, err := g.listRefs(ctx, u, namespace, sourceSecret)
+	if err != nil {
+		return "", fmt.Errorf("could not list the references of Git repository %s: %v", uri, err)
+	}
+
+	if ref == "" {
+		if commit, ok := refs["HEAD"]; ok {
+			return commit, nil
+		}
+		return "", fmt.Errorf("the default branch of Git repository %s was not advertised", uri)
+	}
+
+	candidates := []string{"refs/heads/" + ref, "refs/tags/" + ref}
+	if strings.HasPrefix(ref, "refs/") {
+		candidates = []string{ref}
+	}
+	for _, name := range candidates {
+		if commit, ok := refs[name+peeledSuffix]; ok {
+			return commit, nil
+		}
+		if commit, ok := refs[name]; ok {
+			return commit, nil
+		}
+	}
+
+	// abbreviated commits are not advertised, but they cannot change either
+	if len(ref) >= 7 && isHex(ref) {
+		return ref, nil
+	}
+
+	return "", fmt.Errorf("ref %s was not found in Git repository %s", ref, uri)
+}
+
+// listRefs returns the commits of the references advertised by the upload-pack service of the repository.
+func (g *git) listRefs(ctx context.Context, u *url.URL, namespace string, sourceSecret *v1.LocalObjectReference) (map[string]string, error) {
+	infoRefs := u.JoinPath("info", "refs")
+	infoRefs.RawQuery = url.Values{"service": {uploadPackService}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, infoRefs.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "git/kmm")
+
+	if sourceSecret != nil {
+		secret := v1.Secret{}
+		nsn := types.NamespacedName{Name: sourceSecret.Name, Namespace: namespace}
+		if err = g.client.Get(ctx, nsn, &secret); err != nil {
+			return nil, fmt.Errorf("could not get source Secret %s: %v", nsn, err)
+		}
+		if password, ok := secret.Data[v1.BasicAuthPasswordKey]; ok {
+			req.SetBasicAuth(string(secret.Data[v1.BasicAuthUsernameKey]), string(password))
+		}
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != uploadPackAdvertisementType {
+		return nil, fmt.Errorf("unexpected content type %q: the server does not support the smart HTTP protocol", contentType)
+	}
+
+	return parseAdvertisement(resp.Body)
+}
+
+// parseAdvertisement parses the pkt-lines of a reference advertisement: a service announcement, then one line per
+// reference, the first one followed by the capabilities of the server.
+func parseAdvertisement(r io.Reader) (map[string]string, error) {
+	br := bufio.NewReader(r)
+	refs := make(map[string]string)
+
+	flushes := 0
+	for flushes < 2 {
+		line, err := readPktLine(br)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference advertisement: %v", err)
+		}
+		if line == nil {
+			flushes++
+			continue
+		}
+		if flushes == 0 {
+			// service announcement
+			continue
+		}
+
+		line = bytes.TrimSuffix(line, []byte("\n"))
+		line, _, _ = bytes.Cut(line, []byte{0})
+		commit, name, ok := strings.Cut(string(line), " ")
+		if !ok {
+			return nil, fmt.Errorf("invalid reference line %q", line)
+		}
+		refs[name] = commit
+	}
+
+	return refs, nil
+}
+
+// readPktLine returns the payload of the next pkt-line, or nil for a flush packet.
+func readPktLine(br *bufio.Reader) ([]byte, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(br, prefix[:]); err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.ParseUint(string(prefix[:]), 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pkt-line length %q", prefix)
+	}
+	if length == 0 {
+		return nil, nil
+	}
+	if length < 4 {
+		return nil, fmt.Errorf("invalid pkt-line length %d", length)
+	}
+
+	payload := make([]byte, length-4)
+	if _, err = io.ReadFull(br, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// isCommit returns true if ref is a full SHA-1 or SHA-256 commit.
+func isCommit(ref string) bool {
+	return (len(ref) == 40 || len(ref) == 64) && isHex(ref)
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	mainCommit        = "1111111111111111111111111111111111111111"
+	releaseCommit     = "2222222222222222222222222222222222222222"
+	tagObject         = "3333333333333333333333333333333333333333"
+	taggedCommit      = "4444444444444444444444444444444444444444"
+	lightweightCommit = "5555555555555555555555555555555555555555"
+)
+
+// pktLine encodes payload as a pkt-line of the Git protocol.
+func pktLine(payload string) string {
+	return fmt.Sprintf("%04x%s", len(payload)+4, payload)
+}
+
+// gitServer is a local Git server advertising the references of a repository with the smart HTTP protocol. It
+// requires basic authentication if username is set.
+type gitServer struct {
+	*httptest.Server
+
+	refs     [][2]string
+	username string
+	password string
+}
+
+func newGitServer(refs [][2]string, username, password string) *gitServer {
+	gs := &gitServer{refs: refs, username: username, password: password}
+
+	gs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repo.git/info/refs" || r.URL.Query().Get("service") != "git-upload-pack" {
+			http.NotFound(w, r)
+			return
+		}
+		if gs.username != "" {
+			if username, password, ok := r.BasicAuth(); !ok || username != gs.username || password != gs.password {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+
+		var body strings.Builder
+		body.WriteString(pktLine("# service=git-upload-pack\n"))
+		body.WriteString("0000")
+		for i, ref := range gs.refs {
+			line := ref[1] + " " + ref[0]
+			if i == 0 {
+				line += "\x00multi_ack side-band-64k symref=HEAD:refs/heads/main agent=git/2.43.0"
+			}
+			body.WriteString(pktLine(line + "\n"))
+		}
+		body.WriteString("0000")
+
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+		_, _ = w.Write([]byte(body.String()))
+	}))
+
+	return gs
+}
+
+var _ = Describe("ResolveRef", func() {
+	var (
+		ctrl   *gomock.Controller
+		clnt   *client.MockClient
+		g      Git
+		server *gitServer
+	)
+
+	refs := [][2]string{
+		{"HEAD", mainCommit},
+		{"refs/heads/main", mainCommit},
+		{"refs/heads/release-1.0", releaseCommit},
+		{"refs/tags/v1.0", tagObject},
+		{"refs/tags/v1.0^{}", taggedCommit},
+		{"refs/tags/v0.9", lightweightCommit},
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		g = NewGit(clnt)
+		server = newGitServer(refs, "", "")
+		DeferCleanup(server.Close)
+	})
+
+	ctx := context.Background()
+
+	DescribeTable("should resolve the ref to the commit it points to",
+		func(ref, expected string) {
+			commit, err := g.ResolveRef(ctx, server.URL+"/repo.git", ref, "some-namespace", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commit).To(Equal(expected))
+		},
+		Entry("default branch", "", mainCommit),
+		Entry("branch", "release-1.0", releaseCommit),
+		Entry("full branch name", "refs/heads/release-1.0", releaseCommit),
+		Entry("annotated tag", "v1.0", taggedCommit),
+		Entry("lightweight tag", "v0.9", lightweightCommit),
+		Entry("full commit", "abcdefabcdefabcdefabcdefabcdefabcdefabcd", "abcdefabcdefabcdefabcdefabcdefabcdefabcd"),
+		Entry("abbreviated commit", "abcdef1", "abcdef1"),
+	)
+
+	It("should return an error if the ref does not exist", func() {
+		_, err := g.ResolveRef(ctx, server.URL+"/repo.git", "missing", "some-namespace", nil)
+		Expect(err).To(MatchError(ContainSubstring("ref missing was not found")))
+	})
+
+	It("should return an error if the repository does not exist", func() {
+		_, err := g.ResolveRef(ctx, server.URL+"/missing.git", "main", "some-namespace", nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error for the refs of repositories that are not served over HTTP", func() {
+		_, err := g.ResolveRef(ctx, "git@github.com:org/repo.git", "main", "some-namespace", nil)
+		Expect(err).To(MatchError(ContainSubstring("must be a full commit")))
+	})
+
+	It("should use the commits of repositories that are not served over HTTP as is", func() {
+		const commit = "1111111111111111111111111111111111111111"
+
+		Expect(g.ResolveRef(ctx, "git@github.com:org/repo.git", commit, "some-namespace", nil)).To(Equal(commit))
+	})
+
+	Context("with a repository requiring authentication", func() {
+		BeforeEach(func() {
+			server.username = "user"
+			server.password = "token"
+		})
+
+		sourceSecret := &v1.LocalObjectReference{Name: "git-credentials"}
+		nsn := types.NamespacedName{Name: "git-credentials", Namespace: "some-namespace"}
+
+		It("should authenticate with the source Secret", func() {
+			clnt.EXPECT().Get(ctx, nsn, gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+					secret.Data = map[string][]byte{
+						v1.BasicAuthUsernameKey: []byte("user"),
+						v1.BasicAuthPasswordKey: []byte("token"),
+					}
+					return nil
+				},
+			)
+
+			commit, err := g.ResolveRef(ctx, server.URL+"/repo.git", "main", "some-namespace", sourceSecret)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commit).To(Equal(mainCommit))
+		})
+
+		It("should return an error if the credentials are wrong", func() {
+			clnt.EXPECT().Get(ctx, nsn, gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...ctrlclient.GetOption) error {
+					secret.Data = map[string][]byte{
+						v1.BasicAuthUsernameKey: []byte("user"),
+						v1.BasicAuthPasswordKey: []byte("wrong"),
+					}
+					return nil
+				},
+			)
+
+			_, err := g.ResolveRef(ctx, server.URL+"/repo.git", "main", "some-namespace", sourceSecret)
+			Expect(err).To(MatchError(ContainSubstring("401")))
+		})
+
+		It("should return an error if the source Secret could not be fetched", func() {
+			clnt.EXPECT().Get(ctx, nsn, gomock.Any()).Return(errors.New("some error"))
+
+			_, err := g.ResolveRef(ctx, server.URL+"/repo.git", "main", "some-namespace", sourceSecret)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("parseAdvertisement", func() {
+	It("should return an error if the advertisement is truncated", func() {
+		_, err := parseAdvertisement(strings.NewReader(pktLine("# service=git-upload-pack\n") + "0000" + "003f" + mainCommit))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if a pkt-line length is invalid", func() {
+		_, err := parseAdvertisement(strings.NewReader("zzzz"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: git.go
+//
+// Generated by this command:
+//
+//	mockgen -source=git.go -package=git -destination=mock_git.go
+//
+// Package git is a generated GoMock package.
+package git
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+)
+
+// MockGit is a mock of Git interface.
+type MockGit struct {
+	ctrl     *gomock.Controller
+	recorder *MockGitMockRecorder
+}
+
+// MockGitMockRecorder is the mock recorder for MockGit.
+type MockGitMockRecorder struct {
+	mock *MockGit
+}
+
+// NewMockGit creates a new mock instance.
+func NewMockGit(ctrl *gomock.Controller) *MockGit {
+	mock := &MockGit{ctrl: ctrl}
+	mock.recorder = &MockGitMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGit) EXPECT() *MockGitMockRecorder {
+	return m.recorder
+}
+
+// ResolveRef mocks base method.
+func (m *MockGit) ResolveRef(ctx context.Context, uri, ref, namespace string, sourceSecret *v1.LocalObjectReference) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveRef", ctx, uri, ref, namespace, sourceSecret)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveRef indicates an expected call of ResolveRef.
+func (mr *MockGitMockRecorder) ResolveRef(ctx, uri, ref, namespace, sourceSecret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveRef", reflect.TypeOf((*MockGit)(nil).ResolveRef), ctx, uri, ref, namespace, sourceSecret)
+}
@@ -2,20 +2,26 @@ package manifestwork
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
 	reflect "reflect"
 	"strings"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
 
 	hubv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
@@ -61,14 +67,23 @@ var moduleStatusJSONPaths = []workv1.JsonPath{
 		Name: "moduleLoader.nodesMatchingSelectorNumber",
 		Path: ".status.moduleLoader.nodesMatchingSelectorNumber",
 	},
+	{
+		Name: moduleRevisionFeedbackName,
+		Path: ".status.managedClusterModuleRevision",
+	},
 }
 
+// moduleRevisionFeedbackName is the name of the status feedback reporting the revision of the ManagedClusterModule that
+// the Module on the Spoke cluster runs.
+const moduleRevisionFeedbackName = "managedClusterModuleRevision"
+
 //go:generate mockgen -source=manifestwork.go -package=manifestwork -destination=mock_manifestwork.go
 
 type ManifestWorkCreator interface {
 	GarbageCollect(ctx context.Context, clusters clusterv1.ManagedClusterList, mcm hubv1beta1.ManagedClusterModule) error
 	GetOwnedManifestWorks(ctx context.Context, mcm hubv1beta1.ManagedClusterModule) (*workv1.ManifestWorkList, error)
-	SetManifestWorkAsDesired(ctx context.Context, mw *workv1.ManifestWork, mcm hubv1beta1.ManagedClusterModule, kernelVersions []string) error
+	SetManifestWorkAsDesired(ctx context.Context, mw *workv1.ManifestWork, mcm hubv1beta1.ManagedClusterModule,
+		cluster clusterv1.ManagedCluster, kernelVersions []string) error
 }
 
 type manifestWorkGenerator struct {
@@ -123,17 +138,31 @@ func (mwg *manifestWorkGenerator) SetManifestWorkAsDesired(
 	ctx context.Context,
 	mw *workv1.ManifestWork,
 	mcm hubv1beta1.ManagedClusterModule,
+	cluster clusterv1.ManagedCluster,
 	kernelVersions []string) error {
 	if mw == nil {
 		return errors.New("mw cannot be nil")
 	}
 
-	// Ensure no Build and Sign specs reach the Spoke cluster
-	mcm.Spec.ModuleSpec.ModuleLoader.Container.Build = nil
-	mcm.Spec.ModuleSpec.ModuleLoader.Container.Sign = nil
+	revision, err := Revision(&mcm)
+	if err != nil {
+		return err
+	}
+
+	// the hub builds and signs the images of the overridden ModuleSpec, so the kernel mappings are generated from it
+	clusterMCM, err := ModuleForCluster(&mcm, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to apply the overrides of cluster %s: %v", cluster.Name, err)
+	}
+
+	moduleSpec := clusterMCM.Spec.ModuleSpec
 
 	// Recreate Module KernelMappings
-	mcm.Spec.ModuleSpec.ModuleLoader.Container.KernelMappings = mwg.managedClusterKernelMappings(ctx, mcm, kernelVersions)
+	moduleSpec.ModuleLoader.Container.KernelMappings = mwg.managedClusterKernelMappings(ctx, *clusterMCM, kernelVersions)
+
+	// Ensure no Build and Sign specs reach the Spoke cluster, including the ones set by the overrides
+	moduleSpec.ModuleLoader.Container.Build = nil
+	moduleSpec.ModuleLoader.Container.Sign = nil
 
 	kind := reflect.TypeOf(kmmv1beta1.Module{}).Name()
 	gvk := kmmv1beta1.GroupVersion.WithKind(kind)
@@ -147,10 +176,13 @@ func (mwg *manifestWorkGenerator) SetManifestWorkAsDesired(
 			Name:      mcm.Name,
 			Namespace: mcm.Spec.SpokeNamespace,
 		},
-		Spec:   mcm.Spec.ModuleSpec,
+		Spec:   moduleSpec,
 		Status: kmmv1beta1.ModuleStatus{},
 	}
 
+	// the Module reports the revision back once it runs it
+	mod.SetAnnotations(map[string]string{constants.ManagedClusterModuleRevisionAnnotation: revision})
+
 	manifest := workv1.Manifest{
 		RawExtension: runtime.RawExtension{Object: mod},
 	}
@@ -161,6 +193,13 @@ func (mwg *manifestWorkGenerator) SetManifestWorkAsDesired(
 
 	mw.SetLabels(standardLabels)
 
+	annotations := mw.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[constants.ManagedClusterModuleRevisionAnnotation] = revision
+	mw.SetAnnotations(annotations)
+
 	mw.Spec = workv1.ManifestWorkSpec{
 		Workload: workv1.ManifestsTemplate{
 			Manifests: []workv1.Manifest{manifest},
@@ -206,13 +245,7 @@ func (mwg *manifestWorkGenerator) managedClusterKernelMappings(
 	mcm hubv1beta1.ManagedClusterModule,
 	kernelVersions []string) []kmmv1beta1.KernelMapping {
 
-	mod := &kmmv1beta1.Module{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      mcm.Name,
-			Namespace: mcm.Spec.SpokeNamespace,
-		},
-		Spec: mcm.Spec.ModuleSpec,
-	}
+	mod := BaseModule(&mcm)
 
 	logger := log.FromContext(ctx)
 
@@ -252,3 +285,202 @@ func (mwg *manifestWorkGenerator) mappingFromModuleLoaderData(mld *api.ModuleLoa
 		InTreeModulesToRemove: mld.InTreeModulesToRemove,
 	}
 }
+
+// ModuleLoaderStatus returns the status of the ModuleLoader of the Module deployed by the ManifestWork, as synced back
+// by the feedback rules, or nil if it was not synced back yet.
+func ModuleLoaderStatus(mw *workv1.ManifestWork) *kmmv1beta1.DaemonSetStatus {
+	for _, manifest := range mw.Status.ResourceStatus.Manifests {
+		if manifest.ResourceMeta.Group != kmmv1beta1.GroupVersion.Group || manifest.ResourceMeta.Resource != "modules" {
+			continue
+		}
+
+		var status *kmmv1beta1.DaemonSetStatus
+		for _, value := range manifest.StatusFeedbacks.Values {
+			if value.Value.Integer == nil || !strings.HasPrefix(value.Name, "moduleLoader.") {
+				continue
+			}
+
+			if status == nil {
+				status = &kmmv1beta1.DaemonSetStatus{}
+			}
+			switch value.Name {
+			case "moduleLoader.availableNumber":
+				status.AvailableNumber = int32(*value.Value.Integer)
+			case "moduleLoader.desiredNumber":
+				status.DesiredNumber = int32(*value.Value.Integer)
+			case "moduleLoader.nodesMatchingSelectorNumber":
+				status.NodesMatchingSelectorNumber = int32(*value.Value.Integer)
+			}
+		}
+		return status
+	}
+
+	return nil
+}
+
+// Revision returns an identifier of the ModuleSpec, SpokeNamespace and Overrides of the ManagedClusterModule, which
+// the ManifestWorks created from it are annotated with.
+func Revision(mcm *hubv1beta1.ManagedClusterModule) (string, error) {
+	data, err := json.Marshal(struct {
+		ModuleSpec     kmmv1beta1.ModuleSpec        `json:"moduleSpec"`
+		SpokeNamespace string                       `json:"spokeNamespace"`
+		Overrides      []hubv1beta1.ClusterOverride `json:"overrides,omitempty"`
+	}{
+		ModuleSpec:     mcm.Spec.ModuleSpec,
+		SpokeNamespace: mcm.Spec.SpokeNamespace,
+		Overrides:      mcm.Spec.Overrides,
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not marshal the spec of ManagedClusterModule %s: %v", mcm.Name, err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data))[:16], nil
+}
+
+// GetRevision returns the revision of the ManagedClusterModule the ManifestWork was created from.
+func GetRevision(mw *workv1.ManifestWork) string {
+	return mw.GetAnnotations()[constants.ManagedClusterModuleRevisionAnnotation]
+}
+
+// ModuleRevision returns the revision of the ManagedClusterModule that the Module deployed by the ManifestWork runs,
+// as synced back by the feedback rules, or an empty string if it was not synced back yet.
+func ModuleRevision(mw *workv1.ManifestWork) string {
+	for _, manifest := range mw.Status.ResourceStatus.Manifests {
+		if manifest.ResourceMeta.Group != kmmv1beta1.GroupVersion.Group || manifest.ResourceMeta.Resource != "modules" {
+			continue
+		}
+
+		for _, value := range manifest.StatusFeedbacks.Values {
+			if value.Name == moduleRevisionFeedbackName && value.Value.String != nil {
+				return *value.Value.String
+			}
+		}
+	}
+
+	return ""
+}
+
+// IsModuleAvailable returns true if the ManifestWork is available for its current generation, if the Module on the
+// Spoke cluster reports the revision of the ManifestWork and, if the Module loads a kernel module, if the kernel module
+// is loaded on all the nodes of the managed cluster that need it.
+func IsModuleAvailable(mw *workv1.ManifestWork, loadsKernelModule bool) bool {
+	cond := meta.FindStatusCondition(mw.Status.Conditions, workv1.WorkAvailable)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.ObservedGeneration != mw.Generation {
+		return false
+	}
+	if ModuleRevision(mw) != GetRevision(mw) {
+		return false
+	}
+	if !loadsKernelModule {
+		return true
+	}
+
+	status := ModuleLoaderStatus(mw)
+
+	return status != nil && status.AvailableNumber >= status.DesiredNumber
+}
+
+// OverrideSelectsCluster returns true if the override applies to the cluster.
+func OverrideSelectsCluster(override hubv1beta1.ClusterOverride, cluster clusterv1.ManagedCluster) bool {
+	if override.ClusterName != "" {
+		return override.ClusterName == cluster.Name
+	}
+
+	return len(override.ClusterSelector) > 0 &&
+		labels.SelectorFromSet(override.ClusterSelector).Matches(labels.Set(cluster.Labels))
+}
+
+// PatchModule applies the patch of the override to the JSON representation of a Module. Overrides may only patch
+// the spec of the Module.
+func PatchModule(data []byte, override hubv1beta1.ClusterOverride) ([]byte, error) {
+	patch, err := yaml.YAMLToJSON([]byte(override.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the patch: %v", err)
+	}
+
+	var patched []byte
+	switch override.Type {
+	case hubv1beta1.JSONPatchType:
+		jsonPatch, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode the JSON patch: %v", err)
+		}
+		if patched, err = jsonPatch.Apply(data); err != nil {
+			return nil, fmt.Errorf("could not apply the JSON patch: %v", err)
+		}
+	case hubv1beta1.StrategicMergePatchType, "":
+		if patched, err = strategicpatch.StrategicMergePatch(data, patch, kmmv1beta1.Module{}); err != nil {
+			return nil, fmt.Errorf("could not apply the strategic merge patch: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown patch type %q", override.Type)
+	}
+
+	var original, result map[string]interface{}
+	if err = json.Unmarshal(data, &original); err != nil {
+		return nil, fmt.Errorf("could not unmarshal the Module: %v", err)
+	}
+	if err = json.Unmarshal(patched, &result); err != nil {
+		return nil, fmt.Errorf("could not unmarshal the patched Module: %v", err)
+	}
+	delete(original, "spec")
+	delete(result, "spec")
+	if !reflect.DeepEqual(original, result) {
+		return nil, errors.New("the patch may only change the spec of the Module")
+	}
+
+	return patched, nil
+}
+
+// ModuleForCluster returns a copy of the ManagedClusterModule whose ModuleSpec is patched by the overrides that apply
+// to the cluster. It is the ModuleSpec that the images are built and signed from on the hub, and that is deployed to
+// the cluster.
+func ModuleForCluster(mcm *hubv1beta1.ManagedClusterModule,
+	cluster clusterv1.ManagedCluster) (*hubv1beta1.ManagedClusterModule, error) {
+
+	mcm = mcm.DeepCopy()
+
+	var data []byte
+
+	for i, override := range mcm.Spec.Overrides {
+		if !OverrideSelectsCluster(override, cluster) {
+			continue
+		}
+
+		if data == nil {
+			var err error
+			if data, err = json.Marshal(BaseModule(mcm)); err != nil {
+				return nil, fmt.Errorf("could not marshal the Module: %v", err)
+			}
+		}
+
+		patched, err := PatchModule(data, override)
+		if err != nil {
+			return nil, fmt.Errorf("override %d: %v", i, err)
+		}
+		data = patched
+	}
+
+	if data == nil {
+		return mcm, nil
+	}
+
+	patchedMod := &kmmv1beta1.Module{}
+	if err := json.Unmarshal(data, patchedMod); err != nil {
+		return nil, fmt.Errorf("could not unmarshal the patched Module: %v", err)
+	}
+	mcm.Spec.ModuleSpec = patchedMod.Spec
+
+	return mcm, nil
+}
+
+// BaseModule returns the Module that the overrides of the ManagedClusterModule patch.
+func BaseModule(mcm *hubv1beta1.ManagedClusterModule) *kmmv1beta1.Module {
+	return &kmmv1beta1.Module{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcm.Name,
+			Namespace: mcm.Spec.SpokeNamespace,
+		},
+		Spec: mcm.Spec.ModuleSpec,
+	}
+}
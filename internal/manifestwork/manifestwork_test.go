@@ -2,12 +2,14 @@ package manifestwork
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
@@ -38,6 +40,7 @@ var _ = Describe("moduleStatusJSONPaths", func() {
 			workv1.JsonPath{Name: "moduleLoader.availableNumber", Path: ".status.moduleLoader.availableNumber"},
 			workv1.JsonPath{Name: "moduleLoader.desiredNumber", Path: ".status.moduleLoader.desiredNumber"},
 			workv1.JsonPath{Name: "moduleLoader.nodesMatchingSelectorNumber", Path: ".status.moduleLoader.nodesMatchingSelectorNumber"},
+			workv1.JsonPath{Name: "managedClusterModuleRevision", Path: ".status.managedClusterModuleRevision"},
 		))
 	})
 })
@@ -227,7 +230,7 @@ var _ = Describe("SetManifestWorkAsDesired", func() {
 		mwc := NewCreator(clnt, scheme, mockKM, "")
 
 		Expect(
-			mwc.SetManifestWorkAsDesired(context.Background(), nil, hubv1beta1.ManagedClusterModule{}, nil),
+			mwc.SetManifestWorkAsDesired(context.Background(), nil, hubv1beta1.ManagedClusterModule{}, clusterv1.ManagedCluster{}, nil),
 		).To(
 			HaveOccurred(),
 		)
@@ -240,7 +243,7 @@ var _ = Describe("SetManifestWorkAsDesired", func() {
 
 		mwc := NewCreator(clnt, scheme, mockKM, "")
 
-		err := mwc.SetManifestWorkAsDesired(context.Background(), mw, mcm, []string{kernelVersion})
+		err := mwc.SetManifestWorkAsDesired(context.Background(), mw, mcm, clusterv1.ManagedCluster{}, []string{kernelVersion})
 		Expect(err).NotTo(HaveOccurred())
 		Expect(mw.Spec.Workload.Manifests).To(HaveLen(1))
 
@@ -280,10 +283,18 @@ var _ = Describe("SetManifestWorkAsDesired", func() {
 
 		mwc := NewCreator(clnt, scheme, mockKM, "")
 
-		err := mwc.SetManifestWorkAsDesired(context.Background(), mw, mcm, []string{kernelVersion})
+		revision, err := Revision(&mcm)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = mwc.SetManifestWorkAsDesired(context.Background(), mw, mcm, clusterv1.ManagedCluster{}, []string{kernelVersion})
 
 		Expect(err).NotTo(HaveOccurred())
 		Expect(constants.ManagedClusterModuleNameLabel).To(BeKeyOf(mw.Labels))
+		Expect(GetRevision(mw)).To(Equal(revision))
+		Expect(mcm.Spec.ModuleSpec.ModuleLoader.Container.KernelMappings).NotTo(Equal(expectedModuleSpec.ModuleLoader.Container.KernelMappings))
+		Expect((mw.Spec.Workload.Manifests[0].RawExtension.Object).(*kmmv1beta1.Module).Annotations).To(
+			HaveKeyWithValue(constants.ManagedClusterModuleRevisionAnnotation, revision),
+		)
 		Expect(mw.Spec.Workload.Manifests).To(HaveLen(1))
 		Expect((mw.Spec.Workload.Manifests[0].RawExtension.Object).(*kmmv1beta1.Module).Spec).To(Equal(expectedModuleSpec))
 
@@ -294,4 +305,306 @@ var _ = Describe("SetManifestWorkAsDesired", func() {
 		Expect(mw.Spec.ManifestConfigs[0].FeedbackRules[0].Type).To(Equal(workv1.JSONPathsType))
 		Expect(mw.Spec.ManifestConfigs[0].FeedbackRules[0].JsonPaths).To(Equal(moduleStatusJSONPaths))
 	})
+
+	It("should apply the overrides selecting the cluster", func() {
+		cluster := clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster",
+				Labels: map[string]string{"env": "prod"},
+			},
+		}
+
+		mcm.Spec.Overrides = []hubv1beta1.ClusterOverride{
+			{
+				ClusterSelector: map[string]string{"env": "prod"},
+				Patch: `
+spec:
+  imageRepoSecret:
+    name: prod-secret
+  moduleLoader:
+    container:
+      kernelMappings:
+        - regexp: ^.+$
+          containerImage: prod-image
+          build:
+            dockerfileConfigMap:
+              name: prod-dockerfile
+`,
+			},
+			{
+				ClusterName: "other-cluster",
+				Patch:       `{"spec": {"selector": {"other": "value"}}}`,
+			},
+			{
+				ClusterName: "cluster",
+				Type:        hubv1beta1.JSONPatchType,
+				Patch:       `[{"op": "add", "path": "/spec/selector/override", "value": "true"}]`,
+			},
+		}
+
+		prodMLD := api.ModuleLoaderData{ContainerImage: "prod-image", KernelVersion: kernelVersion}
+
+		gomock.InOrder(
+			mockKM.EXPECT().GetModuleLoaderDataForKernel(gomock.Any(), kernelVersion).DoAndReturn(
+				func(mod *kmmv1beta1.Module, _ string) (*api.ModuleLoaderData, error) {
+					// the kernel mappings are generated from the overridden Module
+					Expect(mod.Spec.ModuleLoader.Container.KernelMappings[0].ContainerImage).To(Equal("prod-image"))
+					return &prodMLD, nil
+				},
+			),
+		)
+
+		mwc := NewCreator(clnt, scheme, mockKM, "")
+
+		err := mwc.SetManifestWorkAsDesired(context.Background(), mw, mcm, cluster, []string{kernelVersion})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mw.Spec.Workload.Manifests).To(HaveLen(1))
+
+		mod := (mw.Spec.Workload.Manifests[0].RawExtension.Object).(*kmmv1beta1.Module)
+		Expect(mod.Namespace).To(Equal(spokeNamespace))
+		Expect(mod.Spec.ImageRepoSecret).To(Equal(&v1.LocalObjectReference{Name: "prod-secret"}))
+		Expect(mod.Spec.Selector).To(Equal(map[string]string{"key": "value", "override": "true"}))
+		Expect(mod.Spec.ModuleLoader.Container.KernelMappings).To(Equal([]kmmv1beta1.KernelMapping{
+			{Literal: kernelVersion, ContainerImage: "prod-image"},
+		}))
+		Expect(mod.Spec.ModuleLoader.Container.Build).To(BeNil())
+		Expect(mod.Spec.ModuleLoader.Container.Sign).To(BeNil())
+		Expect(mw.Spec.ManifestConfigs[0].ResourceIdentifier.Namespace).To(Equal(spokeNamespace))
+	})
+
+	It("should not deploy the build and sign sections set by the overrides", func() {
+		mcm.Spec.ModuleSpec.ModuleLoader.Container.Build = nil
+		mcm.Spec.ModuleSpec.ModuleLoader.Container.Sign = nil
+		mcm.Spec.Overrides = []hubv1beta1.ClusterOverride{
+			{
+				ClusterName: "cluster",
+				Patch:       `{"spec": {"moduleLoader": {"container": {"build": {}, "sign": {}}}}}`,
+			},
+		}
+
+		mockKM.EXPECT().GetModuleLoaderDataForKernel(gomock.Any(), kernelVersion).Return(&mld, nil)
+
+		mwc := NewCreator(clnt, scheme, mockKM, "")
+
+		cluster := clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+
+		err := mwc.SetManifestWorkAsDesired(context.Background(), mw, mcm, cluster, []string{kernelVersion})
+		Expect(err).NotTo(HaveOccurred())
+
+		mod := (mw.Spec.Workload.Manifests[0].RawExtension.Object).(*kmmv1beta1.Module)
+		Expect(mod.Spec.ModuleLoader.Container.Build).To(BeNil())
+		Expect(mod.Spec.ModuleLoader.Container.Sign).To(BeNil())
+	})
+
+	It("should return an error if an override cannot be applied", func() {
+		mcm.Spec.Overrides = []hubv1beta1.ClusterOverride{
+			{
+				ClusterName: "cluster",
+				Type:        hubv1beta1.JSONPatchType,
+				Patch:       `[{"op": "remove", "path": "/spec/missing"}]`,
+			},
+		}
+
+		mwc := NewCreator(clnt, scheme, mockKM, "")
+
+		cluster := clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+
+		err := mwc.SetManifestWorkAsDesired(context.Background(), mw, mcm, cluster, []string{kernelVersion})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to apply the overrides of cluster cluster"))
+	})
+})
+
+var _ = Describe("ModuleLoaderStatus", func() {
+	intValue := func(v int64) *int64 { return &v }
+
+	moduleManifest := func(values ...workv1.FeedbackValue) workv1.ManifestCondition {
+		return workv1.ManifestCondition{
+			ResourceMeta: workv1.ManifestResourceMeta{
+				Group:    kmmv1beta1.GroupVersion.Group,
+				Resource: "modules",
+			},
+			StatusFeedbacks: workv1.StatusFeedbackResult{Values: values},
+		}
+	}
+
+	It("should return nil if the status was not synced back yet", func() {
+		mw := &workv1.ManifestWork{}
+		mw.Status.ResourceStatus.Manifests = []workv1.ManifestCondition{
+			moduleManifest(workv1.FeedbackValue{
+				Name:  "devicePlugin.availableNumber",
+				Value: workv1.FieldValue{Type: workv1.Integer, Integer: intValue(1)},
+			}),
+		}
+
+		Expect(ModuleLoaderStatus(mw)).To(BeNil())
+	})
+
+	It("should return the status of the ModuleLoader", func() {
+		mw := &workv1.ManifestWork{}
+		mw.Status.ResourceStatus.Manifests = []workv1.ManifestCondition{
+			{
+				ResourceMeta: workv1.ManifestResourceMeta{Resource: "configmaps"},
+			},
+			moduleManifest(
+				workv1.FeedbackValue{
+					Name:  "moduleLoader.availableNumber",
+					Value: workv1.FieldValue{Type: workv1.Integer, Integer: intValue(1)},
+				},
+				workv1.FeedbackValue{
+					Name:  "moduleLoader.desiredNumber",
+					Value: workv1.FieldValue{Type: workv1.Integer, Integer: intValue(2)},
+				},
+				workv1.FeedbackValue{
+					Name:  "moduleLoader.nodesMatchingSelectorNumber",
+					Value: workv1.FieldValue{Type: workv1.Integer, Integer: intValue(3)},
+				},
+			),
+		}
+
+		Expect(ModuleLoaderStatus(mw)).To(Equal(&kmmv1beta1.DaemonSetStatus{
+			AvailableNumber:             1,
+			DesiredNumber:               2,
+			NodesMatchingSelectorNumber: 3,
+		}))
+	})
+})
+
+var _ = Describe("Revision", func() {
+	It("should only change with the ModuleSpec and the SpokeNamespace", func() {
+		mcm := &hubv1beta1.ManagedClusterModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "mcm"},
+			Spec: hubv1beta1.ManagedClusterModuleSpec{
+				ModuleSpec:     kmmv1beta1.ModuleSpec{Selector: map[string]string{"key": "value"}},
+				SpokeNamespace: "namespace",
+			},
+		}
+
+		revision, err := Revision(mcm)
+		Expect(err).NotTo(HaveOccurred())
+
+		mcm.Spec.Rollout = &hubv1beta1.RolloutStrategy{Paused: true}
+		Expect(Revision(mcm)).To(Equal(revision))
+
+		mcm.Spec.SpokeNamespace = "other-namespace"
+		Expect(Revision(mcm)).NotTo(Equal(revision))
+	})
+})
+
+var _ = Describe("IsModuleAvailable", func() {
+	intValue := func(v int64) *int64 { return &v }
+	stringValue := func(v string) *string { return &v }
+
+	const revision = "some-revision"
+
+	var mw *workv1.ManifestWork
+
+	BeforeEach(func() {
+		mw = &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Generation:  2,
+				Annotations: map[string]string{constants.ManagedClusterModuleRevisionAnnotation: revision},
+			},
+		}
+		mw.Status.Conditions = []metav1.Condition{
+			{Type: workv1.WorkAvailable, Status: metav1.ConditionTrue, ObservedGeneration: 2},
+		}
+		mw.Status.ResourceStatus.Manifests = []workv1.ManifestCondition{
+			{
+				ResourceMeta: workv1.ManifestResourceMeta{
+					Group:    kmmv1beta1.GroupVersion.Group,
+					Resource: "modules",
+				},
+				StatusFeedbacks: workv1.StatusFeedbackResult{
+					Values: []workv1.FeedbackValue{
+						{
+							Name:  moduleRevisionFeedbackName,
+							Value: workv1.FieldValue{Type: workv1.String, String: stringValue(revision)},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("should return false if the ManifestWork is not available", func() {
+		Expect(IsModuleAvailable(&workv1.ManifestWork{}, false)).To(BeFalse())
+	})
+
+	It("should return true if the ManifestWork is available and no kernel module is loaded", func() {
+		Expect(IsModuleAvailable(mw, false)).To(BeTrue())
+		Expect(IsModuleAvailable(mw, true)).To(BeFalse())
+	})
+
+	It("should return false if the availability was not observed for the current generation", func() {
+		mw.Generation = 3
+
+		Expect(IsModuleAvailable(mw, false)).To(BeFalse())
+	})
+
+	It("should return false until the Module reports the revision of the ManifestWork", func() {
+		mw.Status.ResourceStatus.Manifests[0].StatusFeedbacks.Values[0].Value.String = stringValue("old-revision")
+		Expect(IsModuleAvailable(mw, false)).To(BeFalse())
+
+		mw.Status.ResourceStatus.Manifests[0].StatusFeedbacks.Values = nil
+		Expect(IsModuleAvailable(mw, false)).To(BeFalse())
+	})
+
+	It("should return whether the kernel module is loaded on all the nodes", func() {
+		feedbacks := &mw.Status.ResourceStatus.Manifests[0].StatusFeedbacks
+		feedbacks.Values = append(feedbacks.Values,
+			workv1.FeedbackValue{
+				Name:  "moduleLoader.availableNumber",
+				Value: workv1.FieldValue{Type: workv1.Integer, Integer: intValue(1)},
+			},
+			workv1.FeedbackValue{
+				Name:  "moduleLoader.desiredNumber",
+				Value: workv1.FieldValue{Type: workv1.Integer, Integer: intValue(2)},
+			},
+		)
+
+		Expect(IsModuleAvailable(mw, true)).To(BeFalse())
+
+		*feedbacks.Values[1].Value.Integer = 2
+		Expect(IsModuleAvailable(mw, true)).To(BeTrue())
+	})
+})
+
+var _ = Describe("PatchModule", func() {
+	mod := kmmv1beta1.Module{
+		ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "namespace"},
+		Spec:       kmmv1beta1.ModuleSpec{Selector: map[string]string{"key": "value"}},
+	}
+
+	DescribeTable("should patch the spec of the Module",
+		func(patchType hubv1beta1.PatchType, patch string) {
+			data, err := json.Marshal(mod)
+			Expect(err).NotTo(HaveOccurred())
+
+			patched, err := PatchModule(data, hubv1beta1.ClusterOverride{Type: patchType, Patch: patch})
+			Expect(err).NotTo(HaveOccurred())
+
+			patchedMod := kmmv1beta1.Module{}
+			Expect(json.Unmarshal(patched, &patchedMod)).To(Succeed())
+			Expect(patchedMod.ObjectMeta).To(Equal(mod.ObjectMeta))
+			Expect(patchedMod.Spec.Selector).To(Equal(map[string]string{"key": "other"}))
+		},
+		Entry("strategic merge patch", hubv1beta1.StrategicMergePatchType, "spec:\n  selector:\n    key: other"),
+		Entry("JSON patch", hubv1beta1.JSONPatchType, `[{"op": "replace", "path": "/spec/selector/key", "value": "other"}]`),
+	)
+
+	DescribeTable("should reject the patches",
+		func(patchType hubv1beta1.PatchType, patch string) {
+			data, err := json.Marshal(mod)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = PatchModule(data, hubv1beta1.ClusterOverride{Type: patchType, Patch: patch})
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("changing the metadata", hubv1beta1.StrategicMergePatchType, `{"metadata": {"namespace": "other"}}`),
+		Entry("adding labels", hubv1beta1.JSONPatchType, `[{"op": "add", "path": "/metadata/labels", "value": {"key": "value"}}]`),
+		Entry("that cannot be parsed", hubv1beta1.StrategicMergePatchType, `{"spec": `),
+		Entry("that are not JSON patches", hubv1beta1.JSONPatchType, `{"spec": {}}`),
+		Entry("of an unknown type", hubv1beta1.PatchType("Merge"), `{"spec": {}}`),
+	)
 })
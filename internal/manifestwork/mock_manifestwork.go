@@ -71,15 +71,15 @@ func (mr *MockManifestWorkCreatorMockRecorder) GetOwnedManifestWorks(ctx, mcm an
 }
 
 // SetManifestWorkAsDesired mocks base method.
-func (m *MockManifestWorkCreator) SetManifestWorkAsDesired(ctx context.Context, mw *v10.ManifestWork, mcm v1beta1.ManagedClusterModule, kernelVersions []string) error {
+func (m *MockManifestWorkCreator) SetManifestWorkAsDesired(ctx context.Context, mw *v10.ManifestWork, mcm v1beta1.ManagedClusterModule, cluster v1.ManagedCluster, kernelVersions []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetManifestWorkAsDesired", ctx, mw, mcm, kernelVersions)
+	ret := m.ctrl.Call(m, "SetManifestWorkAsDesired", ctx, mw, mcm, cluster, kernelVersions)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetManifestWorkAsDesired indicates an expected call of SetManifestWorkAsDesired.
-func (mr *MockManifestWorkCreatorMockRecorder) SetManifestWorkAsDesired(ctx, mw, mcm, kernelVersions any) *gomock.Call {
+func (mr *MockManifestWorkCreatorMockRecorder) SetManifestWorkAsDesired(ctx, mw, mcm, cluster, kernelVersions any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetManifestWorkAsDesired", reflect.TypeOf((*MockManifestWorkCreator)(nil).SetManifestWorkAsDesired), ctx, mw, mcm, kernelVersions)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetManifestWorkAsDesired", reflect.TypeOf((*MockManifestWorkCreator)(nil).SetManifestWorkAsDesired), ctx, mw, mcm, cluster, kernelVersions)
 }
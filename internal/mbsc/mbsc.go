@@ -3,12 +3,15 @@ package mbsc
 import (
 	"context"
 	"fmt"
-	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"slices"
 
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -23,6 +26,19 @@ type MBSC interface {
 	GetImageSpec(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string) *kmmv1beta1.ModuleBuildSignSpec
 	SetImageStatus(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string, action kmmv1beta1.BuildOrSignAction, status kmmv1beta1.BuildOrSignStatus)
 	GetImageStatus(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string, action kmmv1beta1.BuildOrSignAction) kmmv1beta1.BuildOrSignStatus
+	UpdateImagesSpecs(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig) error
+	RemoveImages(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig, images sets.Set[string]) error
+	GetImageBuildInputsHash(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string) string
+	SetImageBuildInputsHash(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image, hash string)
+	GetImageSigningKeyFingerprint(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string) string
+	SetImageSigningKeyFingerprint(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image, fingerprint string)
+	SetImageAction(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string, action kmmv1beta1.BuildOrSignAction)
+	RemoveImageStatus(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string)
+	GetImageState(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string, action kmmv1beta1.BuildOrSignAction) *kmmv1beta1.BuildSignImageState
+	SetImageFailure(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string, action kmmv1beta1.BuildOrSignAction,
+		reason kmmv1beta1.BuildOrSignFailureReason, nextAttemptTime *metav1.Time)
+	SetImageLogConfigMap(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image, logConfigMap string)
+	SetImageSignedFiles(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string, signedFiles []string)
 }
 
 type mbsc struct {
@@ -71,6 +87,7 @@ func (m *mbsc) CreateOrPatch(ctx context.Context, micObj *kmmv1beta1.ModuleImage
 		mbscObj.Spec.ImageRepoSecret = micObj.Spec.ImageRepoSecret
 		mbscObj.Spec.PushBuiltImage = micObj.Spec.PushBuiltImage
 		mbscObj.Spec.Tolerations = micObj.Spec.Tolerations
+		mbscObj.Spec.BuildPriority = micObj.Spec.BuildPriority
 		return controllerutil.SetControllerReference(micObj, mbscObj, m.scheme)
 	})
 	return err
@@ -93,6 +110,18 @@ func (m *mbsc) SetImageStatus(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image s
 	}
 	for i, imageStatus := range mbscObj.Status.Images {
 		if imageStatus.Image == image {
+			imageState.BuildInputsHash = imageStatus.BuildInputsHash
+			imageState.SigningKeyFingerprint = imageStatus.SigningKeyFingerprint
+			if imageStatus.Action == action {
+				imageState.LogConfigMap = imageStatus.LogConfigMap
+				imageState.SignedFiles = imageStatus.SignedFiles
+			}
+			// the failed attempts are kept until the action succeeds
+			if status != kmmv1beta1.ActionSuccess && imageStatus.Action == action {
+				imageState.FailedAttempts = imageStatus.FailedAttempts
+				imageState.FailureReason = imageStatus.FailureReason
+				imageState.NextAttemptTime = imageStatus.NextAttemptTime
+			}
 			mbscObj.Status.Images[i] = imageState
 			return
 		}
@@ -109,6 +138,161 @@ func (m *mbsc) GetImageStatus(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image s
 	return kmmv1beta1.BuildOrSignStatus("")
 }
 
+// UpdateImagesSpecs copies the specs of the MIC images into the matching MBSC images, so that changes made to
+// images that were already built or signed are taken into account. The action of each image is preserved.
+func (m *mbsc) UpdateImagesSpecs(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig) error {
+	mbscObj, err := m.Get(ctx, micObj.Name, micObj.Namespace)
+	if err != nil {
+		return err
+	}
+	if mbscObj == nil {
+		return nil
+	}
+
+	patchFrom := client.MergeFrom(mbscObj.DeepCopy())
+	changed := false
+	for i, imageSpec := range mbscObj.Spec.Images {
+		for _, micImageSpec := range micObj.Spec.Images {
+			if micImageSpec.Image == imageSpec.Image && !equality.Semantic.DeepEqual(micImageSpec, imageSpec.ModuleImageSpec) {
+				mbscObj.Spec.Images[i].ModuleImageSpec = micImageSpec
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if err = m.client.Patch(ctx, mbscObj, patchFrom); err != nil {
+		return fmt.Errorf("failed to patch ModuleBuildSignConfig object %s/%s: %v", mbscObj.Namespace, mbscObj.Name, err)
+	}
+	return nil
+}
+
+// RemoveImages removes the images from the spec and the status of the MBSC, so that they are not built or signed
+// anymore.
+func (m *mbsc) RemoveImages(ctx context.Context, mbscObj *kmmv1beta1.ModuleBuildSignConfig, images sets.Set[string]) error {
+	patchFrom := client.MergeFrom(mbscObj.DeepCopy())
+	specImages := slices.DeleteFunc(slices.Clone(mbscObj.Spec.Images), func(imageSpec kmmv1beta1.ModuleBuildSignSpec) bool {
+		return images.Has(imageSpec.Image)
+	})
+	if len(specImages) != len(mbscObj.Spec.Images) {
+		mbscObj.Spec.Images = specImages
+		if err := m.client.Patch(ctx, mbscObj, patchFrom); err != nil {
+			return fmt.Errorf("failed to patch ModuleBuildSignConfig object %s/%s: %v", mbscObj.Namespace, mbscObj.Name, err)
+		}
+	}
+
+	patchFrom = client.MergeFrom(mbscObj.DeepCopy())
+	statusImages := slices.DeleteFunc(slices.Clone(mbscObj.Status.Images), func(imageState kmmv1beta1.BuildSignImageState) bool {
+		return images.Has(imageState.Image)
+	})
+	if len(statusImages) != len(mbscObj.Status.Images) {
+		mbscObj.Status.Images = statusImages
+		if err := m.client.Status().Patch(ctx, mbscObj, patchFrom); err != nil {
+			return fmt.Errorf("failed to patch the status of ModuleBuildSignConfig object %s/%s: %v",
+				mbscObj.Namespace, mbscObj.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *mbsc) GetImageBuildInputsHash(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string) string {
+	for _, imageState := range mbscObj.Status.Images {
+		if imageState.Image == image {
+			return imageState.BuildInputsHash
+		}
+	}
+	return ""
+}
+
+func (m *mbsc) SetImageBuildInputsHash(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image, hash string) {
+	for i, imageState := range mbscObj.Status.Images {
+		if imageState.Image == image {
+			mbscObj.Status.Images[i].BuildInputsHash = hash
+			return
+		}
+	}
+}
+
+func (m *mbsc) GetImageSigningKeyFingerprint(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string) string {
+	for _, imageState := range mbscObj.Status.Images {
+		if imageState.Image == image {
+			return imageState.SigningKeyFingerprint
+		}
+	}
+	return ""
+}
+
+func (m *mbsc) SetImageSigningKeyFingerprint(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image, fingerprint string) {
+	for i, imageState := range mbscObj.Status.Images {
+		if imageState.Image == image {
+			mbscObj.Status.Images[i].SigningKeyFingerprint = fingerprint
+			return
+		}
+	}
+}
+
+func (m *mbsc) SetImageLogConfigMap(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image, logConfigMap string) {
+	for i, imageState := range mbscObj.Status.Images {
+		if imageState.Image == image {
+			mbscObj.Status.Images[i].LogConfigMap = logConfigMap
+			return
+		}
+	}
+}
+
+func (m *mbsc) SetImageSignedFiles(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string, signedFiles []string) {
+	for i, imageState := range mbscObj.Status.Images {
+		if imageState.Image == image {
+			mbscObj.Status.Images[i].SignedFiles = signedFiles
+			return
+		}
+	}
+}
+
+func (m *mbsc) SetImageAction(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string, action kmmv1beta1.BuildOrSignAction) {
+	for i, imageSpec := range mbscObj.Spec.Images {
+		if imageSpec.Image == image {
+			mbscObj.Spec.Images[i].Action = action
+			return
+		}
+	}
+}
+
+func (m *mbsc) RemoveImageStatus(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string) {
+	mbscObj.Status.Images = slices.DeleteFunc(mbscObj.Status.Images, func(imageState kmmv1beta1.BuildSignImageState) bool {
+		return imageState.Image == image
+	})
+}
+
+func (m *mbsc) GetImageState(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string,
+	action kmmv1beta1.BuildOrSignAction) *kmmv1beta1.BuildSignImageState {
+
+	for _, imageState := range mbscObj.Status.Images {
+		if imageState.Image == image && imageState.Action == action {
+			return &imageState
+		}
+	}
+	return nil
+}
+
+// SetImageFailure records a new failed attempt of the action, and the time after which it can be retried.
+func (m *mbsc) SetImageFailure(mbscObj *kmmv1beta1.ModuleBuildSignConfig, image string, action kmmv1beta1.BuildOrSignAction,
+	reason kmmv1beta1.BuildOrSignFailureReason, nextAttemptTime *metav1.Time) {
+
+	m.SetImageStatus(mbscObj, image, action, kmmv1beta1.ActionFailure)
+	for i, imageState := range mbscObj.Status.Images {
+		if imageState.Image == image {
+			mbscObj.Status.Images[i].FailedAttempts++
+			mbscObj.Status.Images[i].FailureReason = reason
+			mbscObj.Status.Images[i].NextAttemptTime = nextAttemptTime
+			return
+		}
+	}
+}
+
 func setModuleImageSpec(mbscObj *kmmv1beta1.ModuleBuildSignConfig, moduleImageSpec *kmmv1beta1.ModuleImageSpec, action kmmv1beta1.BuildOrSignAction) {
 	specEntry := kmmv1beta1.ModuleBuildSignSpec{
 		ModuleImageSpec: *moduleImageSpec,
@@ -13,6 +13,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("setModuleImageSpec", func() {
@@ -265,3 +267,338 @@ var _ = Describe("GetImageStatus", func() {
 		Expect(res).To(Equal(kmmv1beta1.BuildOrSignStatus("")))
 	})
 })
+
+var _ = Describe("UpdateImagesSpecs", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockClient *client.MockClient
+		mbscAPI    MBSC
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = client.NewMockClient(ctrl)
+		mbscAPI = New(mockClient, nil)
+	})
+
+	ctx := context.Background()
+	nsn := types.NamespacedName{Name: "some name", Namespace: "some namespace"}
+	testMIC := kmmv1beta1.ModuleImagesConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: nsn.Name, Namespace: nsn.Namespace},
+		Spec: kmmv1beta1.ModuleImagesConfigSpec{
+			Images: []kmmv1beta1.ModuleImageSpec{
+				{
+					Image: "image1",
+					Build: &kmmv1beta1.Build{BuildArgs: []kmmv1beta1.BuildArg{{Name: "name", Value: "new value"}}},
+				},
+			},
+		},
+	}
+
+	It("mbsc object does not exist", func() {
+		mockClient.EXPECT().Get(ctx, nsn, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, nsn.Name))
+
+		err := mbscAPI.UpdateImagesSpecs(ctx, &testMIC)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("client get returns some error", func() {
+		mockClient.EXPECT().Get(ctx, nsn, gomock.Any()).Return(fmt.Errorf("some error"))
+
+		err := mbscAPI.UpdateImagesSpecs(ctx, &testMIC)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("specs did not change", func() {
+		mockClient.EXPECT().Get(ctx, nsn, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, mbscObj *kmmv1beta1.ModuleBuildSignConfig, _ ...ctrlclient.GetOption) error {
+				mbscObj.Spec.Images = []kmmv1beta1.ModuleBuildSignSpec{
+					{ModuleImageSpec: testMIC.Spec.Images[0], Action: kmmv1beta1.SignImage},
+				}
+				return nil
+			},
+		)
+
+		err := mbscAPI.UpdateImagesSpecs(ctx, &testMIC)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("specs changed, the action is preserved", func() {
+		gomock.InOrder(
+			mockClient.EXPECT().Get(ctx, nsn, gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, mbscObj *kmmv1beta1.ModuleBuildSignConfig, _ ...ctrlclient.GetOption) error {
+					mbscObj.Spec.Images = []kmmv1beta1.ModuleBuildSignSpec{
+						{
+							ModuleImageSpec: kmmv1beta1.ModuleImageSpec{
+								Image: "image1",
+								Build: &kmmv1beta1.Build{BuildArgs: []kmmv1beta1.BuildArg{{Name: "name", Value: "old value"}}},
+							},
+							Action: kmmv1beta1.SignImage,
+						},
+					}
+					return nil
+				},
+			),
+			mockClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, mbscObj *kmmv1beta1.ModuleBuildSignConfig, _ ctrlclient.Patch, _ ...ctrlclient.PatchOption) error {
+					Expect(mbscObj.Spec.Images[0].ModuleImageSpec).To(Equal(testMIC.Spec.Images[0]))
+					Expect(mbscObj.Spec.Images[0].Action).To(Equal(kmmv1beta1.SignImage))
+					return nil
+				},
+			),
+		)
+
+		err := mbscAPI.UpdateImagesSpecs(ctx, &testMIC)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("BuildInputsHash", func() {
+	mbscAPI := New(nil, nil)
+
+	It("set and get the build inputs hash of images", func() {
+		testMBSC := kmmv1beta1.ModuleBuildSignConfig{
+			Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+				Images: []kmmv1beta1.BuildSignImageState{
+					{
+						Image:  "image1",
+						Status: kmmv1beta1.ActionSuccess,
+						Action: kmmv1beta1.BuildImage,
+					},
+				},
+			},
+		}
+
+		By("image status is present")
+		mbscAPI.SetImageBuildInputsHash(&testMBSC, "image1", "1234")
+		Expect(mbscAPI.GetImageBuildInputsHash(&testMBSC, "image1")).To(Equal("1234"))
+
+		By("image status is not present")
+		mbscAPI.SetImageBuildInputsHash(&testMBSC, "image2", "5678")
+		Expect(testMBSC.Status.Images).To(HaveLen(1))
+		Expect(mbscAPI.GetImageBuildInputsHash(&testMBSC, "image2")).To(BeEmpty())
+
+		By("the hash is preserved when the status changes")
+		mbscAPI.SetImageStatus(&testMBSC, "image1", kmmv1beta1.SignImage, kmmv1beta1.ActionSuccess)
+		Expect(mbscAPI.GetImageBuildInputsHash(&testMBSC, "image1")).To(Equal("1234"))
+	})
+})
+
+var _ = Describe("SigningKeyFingerprint", func() {
+	mbscAPI := New(nil, nil)
+
+	It("set and get the SigningKey fingerprint of images", func() {
+		testMBSC := kmmv1beta1.ModuleBuildSignConfig{
+			Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+				Images: []kmmv1beta1.BuildSignImageState{
+					{
+						Image:  "image1",
+						Status: kmmv1beta1.ActionSuccess,
+						Action: kmmv1beta1.SignImage,
+					},
+				},
+			},
+		}
+
+		By("image status is present")
+		mbscAPI.SetImageSigningKeyFingerprint(&testMBSC, "image1", "abcd")
+		Expect(mbscAPI.GetImageSigningKeyFingerprint(&testMBSC, "image1")).To(Equal("abcd"))
+
+		By("image status is not present")
+		mbscAPI.SetImageSigningKeyFingerprint(&testMBSC, "image2", "ef01")
+		Expect(testMBSC.Status.Images).To(HaveLen(1))
+		Expect(mbscAPI.GetImageSigningKeyFingerprint(&testMBSC, "image2")).To(BeEmpty())
+
+		By("the fingerprint is preserved when the status changes")
+		mbscAPI.SetImageStatus(&testMBSC, "image1", kmmv1beta1.SignImage, kmmv1beta1.ActionFailure)
+		Expect(mbscAPI.GetImageSigningKeyFingerprint(&testMBSC, "image1")).To(Equal("abcd"))
+	})
+})
+
+var _ = Describe("SetImageLogConfigMap", func() {
+	mbscAPI := New(nil, nil)
+
+	It("link the archived logs of an image", func() {
+		testMBSC := kmmv1beta1.ModuleBuildSignConfig{}
+
+		By("image status is not present")
+		mbscAPI.SetImageLogConfigMap(&testMBSC, "image1", "some-log")
+		Expect(testMBSC.Status.Images).To(BeEmpty())
+
+		By("image status is present")
+		mbscAPI.SetImageStatus(&testMBSC, "image1", kmmv1beta1.BuildImage, kmmv1beta1.ActionFailure)
+		mbscAPI.SetImageLogConfigMap(&testMBSC, "image1", "some-log")
+		Expect(mbscAPI.GetImageState(&testMBSC, "image1", kmmv1beta1.BuildImage).LogConfigMap).To(Equal("some-log"))
+
+		By("the link is preserved while the action is the same")
+		mbscAPI.SetImageStatus(&testMBSC, "image1", kmmv1beta1.BuildImage, kmmv1beta1.ActionSuccess)
+		Expect(mbscAPI.GetImageState(&testMBSC, "image1", kmmv1beta1.BuildImage).LogConfigMap).To(Equal("some-log"))
+
+		By("the link is dropped when the action changes")
+		mbscAPI.SetImageStatus(&testMBSC, "image1", kmmv1beta1.SignImage, kmmv1beta1.ActionSuccess)
+		Expect(mbscAPI.GetImageState(&testMBSC, "image1", kmmv1beta1.SignImage).LogConfigMap).To(BeEmpty())
+	})
+})
+
+var _ = Describe("SetImageAction", func() {
+	mbscAPI := New(nil, nil)
+
+	It("set the action of an image", func() {
+		testMBSC := kmmv1beta1.ModuleBuildSignConfig{
+			Spec: kmmv1beta1.ModuleBuildSignConfigSpec{
+				Images: []kmmv1beta1.ModuleBuildSignSpec{
+					{
+						ModuleImageSpec: kmmv1beta1.ModuleImageSpec{Image: "image1"},
+						Action:          kmmv1beta1.SignImage,
+					},
+				},
+			},
+		}
+
+		mbscAPI.SetImageAction(&testMBSC, "image1", kmmv1beta1.BuildImage)
+		Expect(testMBSC.Spec.Images[0].Action).To(Equal(kmmv1beta1.BuildImage))
+	})
+})
+
+var _ = Describe("RemoveImageStatus", func() {
+	mbscAPI := New(nil, nil)
+
+	It("remove the status of an image", func() {
+		testMBSC := kmmv1beta1.ModuleBuildSignConfig{
+			Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+				Images: []kmmv1beta1.BuildSignImageState{
+					{Image: "image1"},
+					{Image: "image2"},
+				},
+			},
+		}
+
+		mbscAPI.RemoveImageStatus(&testMBSC, "image1")
+		Expect(testMBSC.Status.Images).To(Equal([]kmmv1beta1.BuildSignImageState{{Image: "image2"}}))
+	})
+})
+
+var _ = Describe("RemoveImages", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockClient *client.MockClient
+		mockStatus *client.MockStatusWriter
+		mbscAPI    MBSC
+		testMBSC   kmmv1beta1.ModuleBuildSignConfig
+	)
+
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = client.NewMockClient(ctrl)
+		mockStatus = client.NewMockStatusWriter(ctrl)
+		mbscAPI = New(mockClient, nil)
+		testMBSC = kmmv1beta1.ModuleBuildSignConfig{
+			Spec: kmmv1beta1.ModuleBuildSignConfigSpec{
+				Images: []kmmv1beta1.ModuleBuildSignSpec{
+					{ModuleImageSpec: kmmv1beta1.ModuleImageSpec{Image: "image1"}},
+					{ModuleImageSpec: kmmv1beta1.ModuleImageSpec{Image: "image2"}},
+				},
+			},
+			Status: kmmv1beta1.ModuleBuildSignConfigStatus{
+				Images: []kmmv1beta1.BuildSignImageState{{Image: "image1"}},
+			},
+		}
+	})
+
+	It("should remove the images from the spec and the status", func() {
+		gomock.InOrder(
+			mockClient.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+			mockClient.EXPECT().Status().Return(mockStatus),
+			mockStatus.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(nil),
+		)
+
+		err := mbscAPI.RemoveImages(ctx, &testMBSC, sets.New("image1"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testMBSC.Spec.Images).To(Equal([]kmmv1beta1.ModuleBuildSignSpec{
+			{ModuleImageSpec: kmmv1beta1.ModuleImageSpec{Image: "image2"}},
+		}))
+		Expect(testMBSC.Status.Images).To(BeEmpty())
+	})
+
+	It("should not patch the MBSC if it does not contain the images", func() {
+		err := mbscAPI.RemoveImages(ctx, &testMBSC, sets.New("image3"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return an error if the spec cannot be patched", func() {
+		mockClient.EXPECT().Patch(ctx, &testMBSC, gomock.Any()).Return(fmt.Errorf("some error"))
+
+		err := mbscAPI.RemoveImages(ctx, &testMBSC, sets.New("image1"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("SetImageFailure", func() {
+	mbscAPI := New(nil, nil)
+
+	It("record the failed attempts of an image until it succeeds", func() {
+		testMBSC := kmmv1beta1.ModuleBuildSignConfig{}
+		nextAttemptTime := metav1.Now()
+
+		By("first failure")
+		mbscAPI.SetImageFailure(&testMBSC, "image1", kmmv1beta1.BuildImage, kmmv1beta1.FailurePush, &nextAttemptTime)
+		Expect(mbscAPI.GetImageState(&testMBSC, "image1", kmmv1beta1.BuildImage)).To(Equal(&kmmv1beta1.BuildSignImageState{
+			Image:           "image1",
+			Action:          kmmv1beta1.BuildImage,
+			Status:          kmmv1beta1.ActionFailure,
+			FailedAttempts:  1,
+			FailureReason:   kmmv1beta1.FailurePush,
+			NextAttemptTime: &nextAttemptTime,
+		}))
+
+		By("the failed attempts are preserved when the retry is queued")
+		mbscAPI.SetImageStatus(&testMBSC, "image1", kmmv1beta1.BuildImage, kmmv1beta1.ActionQueued)
+		Expect(mbscAPI.GetImageState(&testMBSC, "image1", kmmv1beta1.BuildImage).FailedAttempts).To(Equal(int32(1)))
+
+		By("second failure")
+		mbscAPI.SetImageFailure(&testMBSC, "image1", kmmv1beta1.BuildImage, kmmv1beta1.FailureCompile, nil)
+		imageState := mbscAPI.GetImageState(&testMBSC, "image1", kmmv1beta1.BuildImage)
+		Expect(imageState.FailedAttempts).To(Equal(int32(2)))
+		Expect(imageState.FailureReason).To(Equal(kmmv1beta1.FailureCompile))
+		Expect(imageState.NextAttemptTime).To(BeNil())
+
+		By("the failed attempts are reset when the action succeeds")
+		mbscAPI.SetImageStatus(&testMBSC, "image1", kmmv1beta1.BuildImage, kmmv1beta1.ActionSuccess)
+		Expect(mbscAPI.GetImageState(&testMBSC, "image1", kmmv1beta1.BuildImage)).To(Equal(&kmmv1beta1.BuildSignImageState{
+			Image:  "image1",
+			Action: kmmv1beta1.BuildImage,
+			Status: kmmv1beta1.ActionSuccess,
+		}))
+
+		By("image state is not present")
+		Expect(mbscAPI.GetImageState(&testMBSC, "image1", kmmv1beta1.SignImage)).To(BeNil())
+	})
+})
+
+var _ = Describe("SetImageSignedFiles", func() {
+	mbscAPI := New(nil, nil)
+
+	It("report the signed files of an image", func() {
+		testMBSC := kmmv1beta1.ModuleBuildSignConfig{}
+		signedFiles := []string{"/modules/a.ko", "/modules/b.ko.xz"}
+
+		By("image status is not present")
+		mbscAPI.SetImageSignedFiles(&testMBSC, "image1", signedFiles)
+		Expect(testMBSC.Status.Images).To(BeEmpty())
+
+		By("image status is present")
+		mbscAPI.SetImageStatus(&testMBSC, "image1", kmmv1beta1.SignImage, kmmv1beta1.ActionSuccess)
+		mbscAPI.SetImageSignedFiles(&testMBSC, "image1", signedFiles)
+		Expect(mbscAPI.GetImageState(&testMBSC, "image1", kmmv1beta1.SignImage).SignedFiles).To(Equal(signedFiles))
+
+		By("the files are preserved while the action is the same")
+		mbscAPI.SetImageStatus(&testMBSC, "image1", kmmv1beta1.SignImage, kmmv1beta1.ActionFailure)
+		Expect(mbscAPI.GetImageState(&testMBSC, "image1", kmmv1beta1.SignImage).SignedFiles).To(Equal(signedFiles))
+
+		By("the files are dropped when the action changes")
+		mbscAPI.SetImageStatus(&testMBSC, "image1", kmmv1beta1.BuildImage, kmmv1beta1.ActionSuccess)
+		Expect(mbscAPI.GetImageState(&testMBSC, "image1", kmmv1beta1.BuildImage).SignedFiles).To(BeEmpty())
+	})
+})
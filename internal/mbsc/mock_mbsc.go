@@ -14,6 +14,8 @@ import (
 
 	v1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	gomock "go.uber.org/mock/gomock"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	sets "k8s.io/apimachinery/pkg/util/sets"
 )
 
 // MockMBSC is a mock of MBSC interface.
@@ -82,6 +84,34 @@ func (mr *MockMBSCMockRecorder) Get(ctx, name, namespace any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMBSC)(nil).Get), ctx, name, namespace)
 }
 
+// GetImageBuildInputsHash mocks base method.
+func (m *MockMBSC) GetImageBuildInputsHash(mbscObj *v1beta1.ModuleBuildSignConfig, image string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageBuildInputsHash", mbscObj, image)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetImageBuildInputsHash indicates an expected call of GetImageBuildInputsHash.
+func (mr *MockMBSCMockRecorder) GetImageBuildInputsHash(mbscObj, image any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageBuildInputsHash", reflect.TypeOf((*MockMBSC)(nil).GetImageBuildInputsHash), mbscObj, image)
+}
+
+// GetImageSigningKeyFingerprint mocks base method.
+func (m *MockMBSC) GetImageSigningKeyFingerprint(mbscObj *v1beta1.ModuleBuildSignConfig, image string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageSigningKeyFingerprint", mbscObj, image)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetImageSigningKeyFingerprint indicates an expected call of GetImageSigningKeyFingerprint.
+func (mr *MockMBSCMockRecorder) GetImageSigningKeyFingerprint(mbscObj, image any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageSigningKeyFingerprint", reflect.TypeOf((*MockMBSC)(nil).GetImageSigningKeyFingerprint), mbscObj, image)
+}
+
 // GetImageSpec mocks base method.
 func (m *MockMBSC) GetImageSpec(mbscObj *v1beta1.ModuleBuildSignConfig, image string) *v1beta1.ModuleBuildSignSpec {
 	m.ctrl.T.Helper()
@@ -96,6 +126,20 @@ func (mr *MockMBSCMockRecorder) GetImageSpec(mbscObj, image any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageSpec", reflect.TypeOf((*MockMBSC)(nil).GetImageSpec), mbscObj, image)
 }
 
+// GetImageState mocks base method.
+func (m *MockMBSC) GetImageState(mbscObj *v1beta1.ModuleBuildSignConfig, image string, action v1beta1.BuildOrSignAction) *v1beta1.BuildSignImageState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageState", mbscObj, image, action)
+	ret0, _ := ret[0].(*v1beta1.BuildSignImageState)
+	return ret0
+}
+
+// GetImageState indicates an expected call of GetImageState.
+func (mr *MockMBSCMockRecorder) GetImageState(mbscObj, image, action any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageState", reflect.TypeOf((*MockMBSC)(nil).GetImageState), mbscObj, image, action)
+}
+
 // GetImageStatus mocks base method.
 func (m *MockMBSC) GetImageStatus(mbscObj *v1beta1.ModuleBuildSignConfig, image string, action v1beta1.BuildOrSignAction) v1beta1.BuildOrSignStatus {
 	m.ctrl.T.Helper()
@@ -110,6 +154,104 @@ func (mr *MockMBSCMockRecorder) GetImageStatus(mbscObj, image, action any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageStatus", reflect.TypeOf((*MockMBSC)(nil).GetImageStatus), mbscObj, image, action)
 }
 
+// RemoveImageStatus mocks base method.
+func (m *MockMBSC) RemoveImageStatus(mbscObj *v1beta1.ModuleBuildSignConfig, image string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveImageStatus", mbscObj, image)
+}
+
+// RemoveImageStatus indicates an expected call of RemoveImageStatus.
+func (mr *MockMBSCMockRecorder) RemoveImageStatus(mbscObj, image any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveImageStatus", reflect.TypeOf((*MockMBSC)(nil).RemoveImageStatus), mbscObj, image)
+}
+
+// RemoveImages mocks base method.
+func (m *MockMBSC) RemoveImages(ctx context.Context, mbscObj *v1beta1.ModuleBuildSignConfig, images sets.Set[string]) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveImages", ctx, mbscObj, images)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveImages indicates an expected call of RemoveImages.
+func (mr *MockMBSCMockRecorder) RemoveImages(ctx, mbscObj, images any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveImages", reflect.TypeOf((*MockMBSC)(nil).RemoveImages), ctx, mbscObj, images)
+}
+
+// SetImageAction mocks base method.
+func (m *MockMBSC) SetImageAction(mbscObj *v1beta1.ModuleBuildSignConfig, image string, action v1beta1.BuildOrSignAction) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetImageAction", mbscObj, image, action)
+}
+
+// SetImageAction indicates an expected call of SetImageAction.
+func (mr *MockMBSCMockRecorder) SetImageAction(mbscObj, image, action any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageAction", reflect.TypeOf((*MockMBSC)(nil).SetImageAction), mbscObj, image, action)
+}
+
+// SetImageBuildInputsHash mocks base method.
+func (m *MockMBSC) SetImageBuildInputsHash(mbscObj *v1beta1.ModuleBuildSignConfig, image, hash string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetImageBuildInputsHash", mbscObj, image, hash)
+}
+
+// SetImageBuildInputsHash indicates an expected call of SetImageBuildInputsHash.
+func (mr *MockMBSCMockRecorder) SetImageBuildInputsHash(mbscObj, image, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageBuildInputsHash", reflect.TypeOf((*MockMBSC)(nil).SetImageBuildInputsHash), mbscObj, image, hash)
+}
+
+// SetImageFailure mocks base method.
+func (m *MockMBSC) SetImageFailure(mbscObj *v1beta1.ModuleBuildSignConfig, image string, action v1beta1.BuildOrSignAction, reason v1beta1.BuildOrSignFailureReason, nextAttemptTime *v1.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetImageFailure", mbscObj, image, action, reason, nextAttemptTime)
+}
+
+// SetImageFailure indicates an expected call of SetImageFailure.
+func (mr *MockMBSCMockRecorder) SetImageFailure(mbscObj, image, action, reason, nextAttemptTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageFailure", reflect.TypeOf((*MockMBSC)(nil).SetImageFailure), mbscObj, image, action, reason, nextAttemptTime)
+}
+
+// SetImageLogConfigMap mocks base method.
+func (m *MockMBSC) SetImageLogConfigMap(mbscObj *v1beta1.ModuleBuildSignConfig, image, logConfigMap string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetImageLogConfigMap", mbscObj, image, logConfigMap)
+}
+
+// SetImageLogConfigMap indicates an expected call of SetImageLogConfigMap.
+func (mr *MockMBSCMockRecorder) SetImageLogConfigMap(mbscObj, image, logConfigMap any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageLogConfigMap", reflect.TypeOf((*MockMBSC)(nil).SetImageLogConfigMap), mbscObj, image, logConfigMap)
+}
+
+// SetImageSignedFiles mocks base method.
+func (m *MockMBSC) SetImageSignedFiles(mbscObj *v1beta1.ModuleBuildSignConfig, image string, signedFiles []string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetImageSignedFiles", mbscObj, image, signedFiles)
+}
+
+// SetImageSignedFiles indicates an expected call of SetImageSignedFiles.
+func (mr *MockMBSCMockRecorder) SetImageSignedFiles(mbscObj, image, signedFiles any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageSignedFiles", reflect.TypeOf((*MockMBSC)(nil).SetImageSignedFiles), mbscObj, image, signedFiles)
+}
+
+// SetImageSigningKeyFingerprint mocks base method.
+func (m *MockMBSC) SetImageSigningKeyFingerprint(mbscObj *v1beta1.ModuleBuildSignConfig, image, fingerprint string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetImageSigningKeyFingerprint", mbscObj, image, fingerprint)
+}
+
+// SetImageSigningKeyFingerprint indicates an expected call of SetImageSigningKeyFingerprint.
+func (mr *MockMBSCMockRecorder) SetImageSigningKeyFingerprint(mbscObj, image, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageSigningKeyFingerprint", reflect.TypeOf((*MockMBSC)(nil).SetImageSigningKeyFingerprint), mbscObj, image, fingerprint)
+}
+
 // SetImageStatus mocks base method.
 func (m *MockMBSC) SetImageStatus(mbscObj *v1beta1.ModuleBuildSignConfig, image string, action v1beta1.BuildOrSignAction, status v1beta1.BuildOrSignStatus) {
 	m.ctrl.T.Helper()
@@ -121,3 +263,17 @@ func (mr *MockMBSCMockRecorder) SetImageStatus(mbscObj, image, action, status an
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageStatus", reflect.TypeOf((*MockMBSC)(nil).SetImageStatus), mbscObj, image, action, status)
 }
+
+// UpdateImagesSpecs mocks base method.
+func (m *MockMBSC) UpdateImagesSpecs(ctx context.Context, micObj *v1beta1.ModuleImagesConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateImagesSpecs", ctx, micObj)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateImagesSpecs indicates an expected call of UpdateImagesSpecs.
+func (mr *MockMBSCMockRecorder) UpdateImagesSpecs(ctx, micObj any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImagesSpecs", reflect.TypeOf((*MockMBSC)(nil).UpdateImagesSpecs), ctx, micObj)
+}
@@ -1,19 +1,22 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	runtimemetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 // When adding metric names, see https://prometheus.io/docs/practices/naming/#metric-names
 const (
-	kmmModulesQuery         = "kmm_module_num"
-	kmmInClusterBuildQuery  = "kmm_in_cluster_build_num"
-	kmmInClusterSignQuery   = "kmm_in_cluster_sign_num"
-	kmmDevicePluginQuery    = "kmm_device_plugin_num"
-	kmmPreflightQuery       = "kmm_preflight_num"
-	kmmModprobeArgsQuery    = "kmm_modprobe_args"
-	kmmModprobeRawArgsQuery = "kmm_modprobe_raw_args"
+	kmmModulesQuery          = "kmm_module_num"
+	kmmInClusterBuildQuery   = "kmm_in_cluster_build_num"
+	kmmInClusterSignQuery    = "kmm_in_cluster_sign_num"
+	kmmDevicePluginQuery     = "kmm_device_plugin_num"
+	kmmPreflightQuery        = "kmm_preflight_num"
+	kmmModprobeArgsQuery     = "kmm_modprobe_args"
+	kmmModprobeRawArgsQuery  = "kmm_modprobe_raw_args"
+	kmmSigningKeyExpiryQuery = "kmm_signing_key_expiration_timestamp_seconds"
 )
 
 //go:generate mockgen -source=metrics.go -package=metrics -destination=mock_metrics_api.go
@@ -28,6 +31,8 @@ type Metrics interface {
 	SetKMMPreflightsNum(value int)
 	SetKMMModprobeArgs(modName, namespace, modprobeArgs string)
 	SetKMMModprobeRawArgs(modName, namespace, modprobeArgs string)
+	SetKMMSigningKeyExpiry(name, namespace string, notAfter time.Time)
+	DeleteKMMSigningKeyExpiry(name, namespace string)
 }
 
 type metrics struct {
@@ -38,6 +43,7 @@ type metrics struct {
 	kmmPreflightResourceNum     prometheus.Gauge
 	kmmModprobeArgs             *prometheus.GaugeVec
 	kmmModprobeRawArgs          *prometheus.GaugeVec
+	kmmSigningKeyExpiry         *prometheus.GaugeVec
 }
 
 func New() Metrics {
@@ -93,6 +99,14 @@ func New() Metrics {
 		[]string{"name", "namespace", "modprobeRawArgs"},
 	)
 
+	kmmSigningKeyExpiry := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: kmmSigningKeyExpiryQuery,
+			Help: "for a given SigningKey, the time at which its certificate expires",
+		},
+		[]string{"name", "namespace"},
+	)
+
 	return &metrics{
 		kmmModuleResourcesNum:       kmmModuleResourcesNum,
 		kmmInClusterBuildNum:        kmmInClusterBuildNum,
@@ -101,6 +115,7 @@ func New() Metrics {
 		kmmPreflightResourceNum:     kmmPreflightResourceNum,
 		kmmModprobeArgs:             kmmModprobeArgs,
 		kmmModprobeRawArgs:          kmmModprobeRawArgs,
+		kmmSigningKeyExpiry:         kmmSigningKeyExpiry,
 	}
 }
 
@@ -112,6 +127,7 @@ func (m *metrics) Register() {
 		m.kmmDevicePluginResourcesNum,
 		m.kmmPreflightResourceNum,
 		m.kmmModprobeArgs,
+		m.kmmSigningKeyExpiry,
 	)
 }
 
@@ -142,3 +158,11 @@ func (m *metrics) SetKMMModprobeArgs(modName, namespace, modprobeArgs string) {
 func (m *metrics) SetKMMModprobeRawArgs(modName, namespace, modprobeRawArgs string) {
 	m.kmmModprobeRawArgs.WithLabelValues(modName, namespace, modprobeRawArgs).Set(float64(1))
 }
+
+func (m *metrics) SetKMMSigningKeyExpiry(name, namespace string, notAfter time.Time) {
+	m.kmmSigningKeyExpiry.WithLabelValues(name, namespace).Set(float64(notAfter.Unix()))
+}
+
+func (m *metrics) DeleteKMMSigningKeyExpiry(name, namespace string) {
+	m.kmmSigningKeyExpiry.DeleteLabelValues(name, namespace)
+}
@@ -10,6 +10,7 @@ package metrics
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -37,6 +38,18 @@ func (m *MockMetrics) EXPECT() *MockMetricsMockRecorder {
 	return m.recorder
 }
 
+// DeleteKMMSigningKeyExpiry mocks base method.
+func (m *MockMetrics) DeleteKMMSigningKeyExpiry(name, namespace string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteKMMSigningKeyExpiry", name, namespace)
+}
+
+// DeleteKMMSigningKeyExpiry indicates an expected call of DeleteKMMSigningKeyExpiry.
+func (mr *MockMetricsMockRecorder) DeleteKMMSigningKeyExpiry(name, namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKMMSigningKeyExpiry", reflect.TypeOf((*MockMetrics)(nil).DeleteKMMSigningKeyExpiry), name, namespace)
+}
+
 // Register mocks base method.
 func (m *MockMetrics) Register() {
 	m.ctrl.T.Helper()
@@ -132,3 +145,15 @@ func (mr *MockMetricsMockRecorder) SetKMMPreflightsNum(value any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKMMPreflightsNum", reflect.TypeOf((*MockMetrics)(nil).SetKMMPreflightsNum), value)
 }
+
+// SetKMMSigningKeyExpiry mocks base method.
+func (m *MockMetrics) SetKMMSigningKeyExpiry(name, namespace string, notAfter time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetKMMSigningKeyExpiry", name, namespace, notAfter)
+}
+
+// SetKMMSigningKeyExpiry indicates an expected call of SetKMMSigningKeyExpiry.
+func (mr *MockMetricsMockRecorder) SetKMMSigningKeyExpiry(name, namespace, notAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKMMSigningKeyExpiry", reflect.TypeOf((*MockMetrics)(nil).SetKMMSigningKeyExpiry), name, namespace, notAfter)
+}
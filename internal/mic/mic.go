@@ -3,6 +3,9 @@ package mic
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"slices"
+	"strings"
 
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	v1 "k8s.io/api/core/v1"
@@ -15,17 +18,58 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// ImageIndexKey is the key of the field index of the ModuleImagesConfigs by the images of their spec.
+const ImageIndexKey = "spec.images.image"
+
+// IndexImages returns the images of the spec of a ModuleImagesConfig, so that they are indexed by ImageIndexKey.
+func IndexImages(obj client.Object) []string {
+	micObj, ok := obj.(*kmmv1beta1.ModuleImagesConfig)
+	if !ok {
+		return nil
+	}
+
+	images := make([]string, 0, len(micObj.Spec.Images))
+	for _, imageSpec := range micObj.Spec.Images {
+		images = append(images, imageSpec.Image)
+	}
+	return images
+}
+
+// SharedImageState is the state of an image according to the other ModuleImagesConfigs of the cluster that reference
+// it.
+type SharedImageState struct {
+	// Verified is the state of the image in a ModuleImagesConfig that verified it, if any: the image either exists
+	// or does not have the expected layout.
+	Verified *kmmv1beta1.ModuleImageState
+	// Builder is the ModuleImagesConfig building the image, if any; the image must not be built by another
+	// ModuleImagesConfig while it is set.
+	Builder *types.NamespacedName
+}
+
 //go:generate mockgen -source=mic.go -package=mic -destination=mock_mic.go
 
 type MIC interface {
 	CreateOrPatch(ctx context.Context, name, ns string, images []kmmv1beta1.ModuleImageSpec,
 		imageRepoSecret *v1.LocalObjectReference, pullPolicy v1.PullPolicy, pushBuiltImage bool,
-		imageRebuildTriggerGeneration *int, tolerations []v1.Toleration, owner metav1.Object) error
+		imageRebuildTriggerGeneration *int, buildPriority int32, tolerations []v1.Toleration,
+		prePulls []kmmv1beta1.PrePullSpec, imageGC *kmmv1beta1.ImageGCSpec, owner metav1.Object) error
 	Get(ctx context.Context, name, ns string) (*kmmv1beta1.ModuleImagesConfig, error)
 	GetModuleImageSpec(micObj *kmmv1beta1.ModuleImagesConfig, image string) *kmmv1beta1.ModuleImageSpec
 	SetImageStatus(micObj *kmmv1beta1.ModuleImagesConfig, image string, status kmmv1beta1.ImageState)
 	GetImageState(micObj *kmmv1beta1.ModuleImagesConfig, image string) kmmv1beta1.ImageState
 	DoAllImagesExist(micObj *kmmv1beta1.ModuleImagesConfig) bool
+	SetImageBuildInputsHash(micObj *kmmv1beta1.ModuleImagesConfig, image, hash string)
+	SetImageVerified(micObj *kmmv1beta1.ModuleImagesConfig, image, digest string, verifiedTime metav1.Time)
+	SetImageVerificationFailed(micObj *kmmv1beta1.ModuleImagesConfig, image string, failedTime metav1.Time) int32
+	SetImageLayoutFindings(micObj *kmmv1beta1.ModuleImagesConfig, image string, findings []string)
+	GetImageLayoutFindings(micObj *kmmv1beta1.ModuleImagesConfig, image string) []string
+	GetImageVerificationFailures(micObj *kmmv1beta1.ModuleImagesConfig, image string) int32
+	GetImageDigest(micObj *kmmv1beta1.ModuleImagesConfig, image string) string
+	GetPinnedImage(micObj *kmmv1beta1.ModuleImagesConfig, image string) string
+	GetPrePullState(micObj *kmmv1beta1.ModuleImagesConfig, image, nodeName string) kmmv1beta1.PrePullState
+	ListByImage(ctx context.Context, image string) ([]kmmv1beta1.ModuleImagesConfig, error)
+	ListImages(ctx context.Context) ([]string, error)
+	GetSharedImageState(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig, image string) (*SharedImageState, error)
 }
 
 type micImpl struct {
@@ -42,7 +86,8 @@ func New(client client.Client, scheme *runtime.Scheme) MIC {
 
 func (mici *micImpl) CreateOrPatch(ctx context.Context, name, ns string, images []kmmv1beta1.ModuleImageSpec,
 	imageRepoSecret *v1.LocalObjectReference, pullPolicy v1.PullPolicy, pushBuiltImage bool,
-	imageRebuildTriggerGeneration *int, tolerations []v1.Toleration, owner metav1.Object) error {
+	imageRebuildTriggerGeneration *int, buildPriority int32, tolerations []v1.Toleration,
+	prePulls []kmmv1beta1.PrePullSpec, imageGC *kmmv1beta1.ImageGCSpec, owner metav1.Object) error {
 
 	logger := log.FromContext(ctx)
 
@@ -63,7 +108,10 @@ func (mici *micImpl) CreateOrPatch(ctx context.Context, name, ns string, images
 			ImagePullPolicy:               pullPolicy,
 			PushBuiltImage:                pushBuiltImage,
 			ImageRebuildTriggerGeneration: imageRebuildTriggerGeneration,
+			BuildPriority:                 buildPriority,
 			Tolerations:                   tolerations,
+			PrePulls:                      prePulls,
+			ImageGC:                       imageGC,
 		}
 
 		return controllerutil.SetControllerReference(owner, mic, mici.scheme)
@@ -103,6 +151,17 @@ func (mici *micImpl) SetImageStatus(micObj *kmmv1beta1.ModuleImagesConfig, image
 	}
 	for i, imageStatus := range micObj.Status.ImagesStates {
 		if imageStatus.Image == image {
+			imageState.BuildInputsHash = imageStatus.BuildInputsHash
+			// the digest is only valid as long as the image keeps existing
+			if imageStatus.Status == kmmv1beta1.ImageExists && status == kmmv1beta1.ImageExists {
+				imageState.Digest = imageStatus.Digest
+				imageState.LastVerifiedTime = imageStatus.LastVerifiedTime
+			}
+			// the failed verifications are counted as long as the status of the image does not change
+			if imageStatus.Status == status {
+				imageState.VerificationFailures = imageStatus.VerificationFailures
+				imageState.LastVerificationFailureTime = imageStatus.LastVerificationFailureTime
+			}
 			micObj.Status.ImagesStates[i] = imageState
 			return
 		}
@@ -110,6 +169,179 @@ func (mici *micImpl) SetImageStatus(micObj *kmmv1beta1.ModuleImagesConfig, image
 	micObj.Status.ImagesStates = append(micObj.Status.ImagesStates, imageState)
 }
 
+func (mici *micImpl) SetImageBuildInputsHash(micObj *kmmv1beta1.ModuleImagesConfig, image, hash string) {
+	for i, imageState := range micObj.Status.ImagesStates {
+		if imageState.Image == image {
+			micObj.Status.ImagesStates[i].BuildInputsHash = hash
+			return
+		}
+	}
+}
+
+// SetImageVerified records that the image was pulled at verifiedTime; digest is the digest that nodes are pinned to.
+func (mici *micImpl) SetImageVerified(micObj *kmmv1beta1.ModuleImagesConfig, image, digest string, verifiedTime metav1.Time) {
+	for i, imageState := range micObj.Status.ImagesStates {
+		if imageState.Image == image {
+			micObj.Status.ImagesStates[i].Digest = digest
+			micObj.Status.ImagesStates[i].LastVerifiedTime = &verifiedTime
+			micObj.Status.ImagesStates[i].VerificationFailures = 0
+			micObj.Status.ImagesStates[i].LastVerificationFailureTime = nil
+			return
+		}
+	}
+}
+
+// SetImageVerificationFailed records that the image could not be pulled from the registry, and returns the number of
+// consecutive failures.
+func (mici *micImpl) SetImageVerificationFailed(micObj *kmmv1beta1.ModuleImagesConfig, image string, failedTime metav1.Time) int32 {
+	for i, imageState := range micObj.Status.ImagesStates {
+		if imageState.Image == image {
+			micObj.Status.ImagesStates[i].VerificationFailures++
+			micObj.Status.ImagesStates[i].LastVerificationFailureTime = &failedTime
+			return micObj.Status.ImagesStates[i].VerificationFailures
+		}
+	}
+	return 0
+}
+
+// SetImageLayoutFindings records what is wrong with the content of the image; they are cleared when the status of the
+// image is set again.
+func (mici *micImpl) SetImageLayoutFindings(micObj *kmmv1beta1.ModuleImagesConfig, image string, findings []string) {
+	for i, imageState := range micObj.Status.ImagesStates {
+		if imageState.Image == image {
+			micObj.Status.ImagesStates[i].LayoutFindings = findings
+			return
+		}
+	}
+}
+
+func (mici *micImpl) GetImageLayoutFindings(micObj *kmmv1beta1.ModuleImagesConfig, image string) []string {
+	for _, imageState := range micObj.Status.ImagesStates {
+		if imageState.Image == image {
+			return imageState.LayoutFindings
+		}
+	}
+	return nil
+}
+
+func (mici *micImpl) GetImageVerificationFailures(micObj *kmmv1beta1.ModuleImagesConfig, image string) int32 {
+	for _, imageState := range micObj.Status.ImagesStates {
+		if imageState.Image == image {
+			return imageState.VerificationFailures
+		}
+	}
+	return 0
+}
+
+func (mici *micImpl) GetImageDigest(micObj *kmmv1beta1.ModuleImagesConfig, image string) string {
+	for _, imageState := range micObj.Status.ImagesStates {
+		if imageState.Image == image {
+			return imageState.Digest
+		}
+	}
+	return ""
+}
+
+// GetPinnedImage returns the image referenced by the digest that was verified, or the image itself if its digest is
+// not known. The tag is kept in front of the digest, so that the image the digest was resolved from is known.
+func (mici *micImpl) GetPinnedImage(micObj *kmmv1beta1.ModuleImagesConfig, image string) string {
+	if strings.Contains(image, "@") {
+		return image
+	}
+
+	for _, imageState := range micObj.Status.ImagesStates {
+		if imageState.Image == image && imageState.Digest != "" {
+			return image + "@" + imageState.Digest
+		}
+	}
+
+	return image
+}
+
+// GetPrePullState returns the progress of the pulling of the image onto the node, or an empty string if it did not
+// start yet.
+func (mici *micImpl) GetPrePullState(micObj *kmmv1beta1.ModuleImagesConfig, image, nodeName string) kmmv1beta1.PrePullState {
+	pinnedImage := mici.GetPinnedImage(micObj, image)
+	for _, prePullState := range micObj.Status.PrePullStates {
+		if prePullState.Image == pinnedImage && prePullState.Node == nodeName {
+			return prePullState.State
+		}
+	}
+	return ""
+}
+
+// ListByImage returns the ModuleImagesConfigs of all namespaces whose spec contains the image.
+func (mici *micImpl) ListByImage(ctx context.Context, image string) ([]kmmv1beta1.ModuleImagesConfig, error) {
+	micList := kmmv1beta1.ModuleImagesConfigList{}
+	if err := mici.client.List(ctx, &micList, client.MatchingFields{ImageIndexKey: image}); err != nil {
+		return nil, fmt.Errorf("could not list the ModuleImagesConfigs of image %s: %v", image, err)
+	}
+	return micList.Items, nil
+}
+
+// ListImages returns the images of the spec of the ModuleImagesConfigs of all namespaces.
+func (mici *micImpl) ListImages(ctx context.Context) ([]string, error) {
+	micList := kmmv1beta1.ModuleImagesConfigList{}
+	if err := mici.client.List(ctx, &micList); err != nil {
+		return nil, fmt.Errorf("could not list the ModuleImagesConfigs: %v", err)
+	}
+
+	images := sets.New[string]()
+	for i := range micList.Items {
+		images.Insert(IndexImages(&micList.Items[i])...)
+	}
+	return sets.List(images), nil
+}
+
+// GetSharedImageState looks the image up in the other ModuleImagesConfigs of the cluster, so that each image is only
+// verified and built once. When several ModuleImagesConfigs need to build the same image, the first one by namespace
+// and name builds it.
+// The state of an image verified by another ModuleImagesConfig is only shared if the image is pulled with the same
+// credentials and has the same expected layout; otherwise, it has to be verified again.
+func (mici *micImpl) GetSharedImageState(ctx context.Context, micObj *kmmv1beta1.ModuleImagesConfig,
+	image string) (*SharedImageState, error) {
+
+	mics, err := mici.ListByImage(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+
+	key := client.ObjectKeyFromObject(micObj)
+	imageSpec := mici.GetModuleImageSpec(micObj, image)
+	building := isBuilding(imageSpec, mici.GetImageState(micObj, image))
+
+	sharedImageState := SharedImageState{}
+	for _, other := range mics {
+		otherKey := client.ObjectKeyFromObject(&other)
+		if otherKey == key || other.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		otherImageState := getModuleImageState(&other, image)
+		if otherImageState == nil {
+			continue
+		}
+
+		otherImageSpec := mici.GetModuleImageSpec(&other, image)
+		if otherImageState.Status == kmmv1beta1.ImageExists || otherImageState.Status == kmmv1beta1.ImageInvalidLayout {
+			if canShareImageState(micObj, &other, imageSpec, otherImageSpec) {
+				return &SharedImageState{Verified: otherImageState}, nil
+			}
+			continue
+		}
+
+		if !isBuilding(otherImageSpec, otherImageState.Status) ||
+			(building && otherKey.String() > key.String()) {
+			continue
+		}
+		if sharedImageState.Builder == nil || otherKey.String() < sharedImageState.Builder.String() {
+			sharedImageState.Builder = &otherKey
+		}
+	}
+
+	return &sharedImageState, nil
+}
+
 func (mici *micImpl) GetImageState(micObj *kmmv1beta1.ModuleImagesConfig, image string) kmmv1beta1.ImageState {
 	for _, imageState := range micObj.Status.ImagesStates {
 		if imageState.Image == image {
@@ -136,6 +368,40 @@ func (mici *micImpl) DoAllImagesExist(micObj *kmmv1beta1.ModuleImagesConfig) boo
 	return true
 }
 
+func getModuleImageState(micObj *kmmv1beta1.ModuleImagesConfig, image string) *kmmv1beta1.ModuleImageState {
+	for _, imageState := range micObj.Status.ImagesStates {
+		if imageState.Image == image {
+			return &imageState
+		}
+	}
+	return nil
+}
+
+// isBuilding returns true if the image is being built or signed, or is about to be, according to its state.
+// Builds and signs that failed and are retried keep their state; ImageDoesNotExist means that they will not be retried.
+func isBuilding(imageSpec *kmmv1beta1.ModuleImageSpec, imageState kmmv1beta1.ImageState) bool {
+	return imageSpec != nil &&
+		(imageState == kmmv1beta1.ImageNeedsBuilding || imageState == kmmv1beta1.ImageNeedsSigning)
+}
+
+// canShareImageState returns true if micObj can take the state of the image from other: the image must be pulled with
+// the same pull secret, which is only the same within a namespace, and be expected to have the same layout.
+func canShareImageState(micObj, other *kmmv1beta1.ModuleImagesConfig, imageSpec, otherImageSpec *kmmv1beta1.ModuleImageSpec) bool {
+	if !reflect.DeepEqual(micObj.Spec.ImageRepoSecret, other.Spec.ImageRepoSecret) ||
+		(micObj.Spec.ImageRepoSecret != nil && micObj.Namespace != other.Namespace) {
+		return false
+	}
+
+	if imageSpec == nil || otherImageSpec == nil {
+		return false
+	}
+
+	return imageSpec.DirName == otherImageSpec.DirName &&
+		imageSpec.KernelVersion == otherImageSpec.KernelVersion &&
+		imageSpec.FirmwarePath == otherImageSpec.FirmwarePath &&
+		slices.Equal(imageSpec.ModuleNames, otherImageSpec.ModuleNames)
+}
+
 func filterDuplicateImages(images []kmmv1beta1.ModuleImageSpec) []kmmv1beta1.ModuleImageSpec {
 	imagesSet := sets.New[string]()
 	filteredImages := make([]kmmv1beta1.ModuleImageSpec, 0, len(images))
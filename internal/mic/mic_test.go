@@ -45,7 +45,7 @@ var _ = Describe("CreateOrPatch", func() {
 
 		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
 
-		err := micAPI.CreateOrPatch(ctx, micName, micNamespace, []v1beta1.ModuleImageSpec{}, nil, "", false, nil, nil, &kmmv1beta1.Module{})
+		err := micAPI.CreateOrPatch(ctx, micName, micNamespace, []v1beta1.ModuleImageSpec{}, nil, "", false, nil, 0, nil, nil, nil, &kmmv1beta1.Module{})
 
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("failed to create or patch"))
@@ -81,7 +81,7 @@ var _ = Describe("CreateOrPatch", func() {
 			},
 		}
 
-		err := micAPI.CreateOrPatch(ctx, micName, micNamespace, images, imageRepoSecret, "", true, nil, nil, owner)
+		err := micAPI.CreateOrPatch(ctx, micName, micNamespace, images, imageRepoSecret, "", true, nil, 0, nil, nil, nil, owner)
 
 		Expect(err).NotTo(HaveOccurred())
 	})
@@ -128,7 +128,7 @@ var _ = Describe("CreateOrPatch", func() {
 			},
 		}
 
-		err := micAPI.CreateOrPatch(ctx, micName, micNamespace, images, nil, v1.PullIfNotPresent, true, nil, nil, owner)
+		err := micAPI.CreateOrPatch(ctx, micName, micNamespace, images, nil, v1.PullIfNotPresent, true, nil, 10, nil, nil, nil, owner)
 
 		Expect(err).NotTo(HaveOccurred())
 	})
@@ -392,3 +392,412 @@ var _ = Describe("filterDuplicateImages", func() {
 		Expect(res).To(Equal(expectedRes))
 	})
 })
+
+var _ = Describe("SetImageBuildInputsHash", func() {
+	var (
+		micAPI MIC
+	)
+
+	BeforeEach(func() {
+		micAPI = New(nil, nil)
+	})
+
+	It("set the build inputs hash of present images only", func() {
+		testMic := kmmv1beta1.ModuleImagesConfig{
+			Status: kmmv1beta1.ModuleImagesConfigStatus{
+				ImagesStates: []kmmv1beta1.ModuleImageState{
+					{
+						Image:  "image 1",
+						Status: kmmv1beta1.ImageExists,
+					},
+				},
+			},
+		}
+
+		By("image status is present")
+		micAPI.SetImageBuildInputsHash(&testMic, "image 1", "1234")
+		Expect(testMic.Status.ImagesStates[0].BuildInputsHash).To(Equal("1234"))
+
+		By("image status is not present")
+		micAPI.SetImageBuildInputsHash(&testMic, "image 2", "5678")
+		Expect(testMic.Status.ImagesStates).To(HaveLen(1))
+
+		By("the hash is preserved when the status changes")
+		micAPI.SetImageStatus(&testMic, "image 1", kmmv1beta1.ImageNeedsBuilding)
+		Expect(testMic.Status.ImagesStates[0].BuildInputsHash).To(Equal("1234"))
+	})
+})
+
+var _ = Describe("SetImageVerified", func() {
+	var (
+		micAPI MIC
+	)
+
+	BeforeEach(func() {
+		micAPI = New(nil, nil)
+	})
+
+	It("should keep the digest only while the image exists", func() {
+		testMic := kmmv1beta1.ModuleImagesConfig{
+			Status: kmmv1beta1.ModuleImagesConfigStatus{
+				ImagesStates: []kmmv1beta1.ModuleImageState{
+					{
+						Image:  "image 1",
+						Status: kmmv1beta1.ImageExists,
+					},
+				},
+			},
+		}
+
+		now := metav1.Now()
+		micAPI.SetImageVerified(&testMic, "image 1", "sha256:111", now)
+		Expect(testMic.Status.ImagesStates[0].Digest).To(Equal("sha256:111"))
+		Expect(testMic.Status.ImagesStates[0].LastVerifiedTime).To(Equal(&now))
+		Expect(micAPI.GetImageDigest(&testMic, "image 1")).To(Equal("sha256:111"))
+
+		By("the status is set again")
+		micAPI.SetImageStatus(&testMic, "image 1", kmmv1beta1.ImageExists)
+		Expect(testMic.Status.ImagesStates[0].Digest).To(Equal("sha256:111"))
+		Expect(testMic.Status.ImagesStates[0].LastVerifiedTime).To(Equal(&now))
+
+		By("the image has to be rebuilt")
+		micAPI.SetImageStatus(&testMic, "image 1", kmmv1beta1.ImageNeedsBuilding)
+		Expect(testMic.Status.ImagesStates[0].Digest).To(BeEmpty())
+		Expect(testMic.Status.ImagesStates[0].LastVerifiedTime).To(BeNil())
+	})
+})
+
+var _ = Describe("SetImageVerificationFailed", func() {
+	var (
+		micAPI MIC
+	)
+
+	BeforeEach(func() {
+		micAPI = New(nil, nil)
+	})
+
+	It("should count the consecutive failures while the status of the image does not change", func() {
+		testMic := kmmv1beta1.ModuleImagesConfig{
+			Status: kmmv1beta1.ModuleImagesConfigStatus{
+				ImagesStates: []kmmv1beta1.ModuleImageState{
+					{
+						Image:  "image 1",
+						Status: kmmv1beta1.ImageExists,
+					},
+				},
+			},
+		}
+
+		now := metav1.Now()
+		Expect(micAPI.SetImageVerificationFailed(&testMic, "image 1", now)).To(Equal(int32(1)))
+		Expect(micAPI.SetImageVerificationFailed(&testMic, "image 1", now)).To(Equal(int32(2)))
+		Expect(testMic.Status.ImagesStates[0].LastVerificationFailureTime).To(Equal(&now))
+
+		By("the status is set again")
+		micAPI.SetImageStatus(&testMic, "image 1", kmmv1beta1.ImageExists)
+		Expect(testMic.Status.ImagesStates[0].VerificationFailures).To(Equal(int32(2)))
+
+		By("the image is verified")
+		micAPI.SetImageVerified(&testMic, "image 1", "sha256:111", now)
+		Expect(testMic.Status.ImagesStates[0].VerificationFailures).To(BeZero())
+		Expect(testMic.Status.ImagesStates[0].LastVerificationFailureTime).To(BeNil())
+
+		By("the status of the image changes")
+		micAPI.SetImageVerificationFailed(&testMic, "image 1", now)
+		micAPI.SetImageStatus(&testMic, "image 1", kmmv1beta1.ImageDoesNotExist)
+		Expect(testMic.Status.ImagesStates[0].VerificationFailures).To(BeZero())
+		Expect(testMic.Status.ImagesStates[0].LastVerificationFailureTime).To(BeNil())
+	})
+
+	It("should return 0 if the image has no status", func() {
+		Expect(micAPI.SetImageVerificationFailed(&kmmv1beta1.ModuleImagesConfig{}, "image 1", metav1.Now())).To(BeZero())
+	})
+})
+
+var _ = Describe("SetImageLayoutFindings", func() {
+	var (
+		micAPI MIC
+	)
+
+	BeforeEach(func() {
+		micAPI = New(nil, nil)
+	})
+
+	It("should keep the findings until the status is set again", func() {
+		testMic := kmmv1beta1.ModuleImagesConfig{}
+
+		micAPI.SetImageStatus(&testMic, "image 1", kmmv1beta1.ImageInvalidLayout)
+		micAPI.SetImageLayoutFindings(&testMic, "image 1", []string{"finding 1", "finding 2"})
+		Expect(micAPI.GetImageLayoutFindings(&testMic, "image 1")).To(Equal([]string{"finding 1", "finding 2"}))
+		Expect(micAPI.GetImageLayoutFindings(&testMic, "image 2")).To(BeNil())
+
+		By("the image is valid again")
+		micAPI.SetImageStatus(&testMic, "image 1", kmmv1beta1.ImageExists)
+		Expect(micAPI.GetImageLayoutFindings(&testMic, "image 1")).To(BeNil())
+	})
+})
+
+var _ = Describe("GetPinnedImage", func() {
+	var (
+		micAPI MIC
+	)
+
+	BeforeEach(func() {
+		micAPI = New(nil, nil)
+	})
+
+	DescribeTable("should reference the image by its digest when it is known",
+		func(image, digest, expected string) {
+			testMic := kmmv1beta1.ModuleImagesConfig{
+				Status: kmmv1beta1.ModuleImagesConfigStatus{
+					ImagesStates: []kmmv1beta1.ModuleImageState{
+						{Image: image, Status: kmmv1beta1.ImageExists, Digest: digest},
+					},
+				},
+			}
+			Expect(micAPI.GetPinnedImage(&testMic, image)).To(Equal(expected))
+		},
+		Entry("unknown digest", "example.com/repo:tag", "", "example.com/repo:tag"),
+		Entry("tag", "example.com/repo:tag", "sha256:111", "example.com/repo:tag@sha256:111"),
+		Entry("registry port", "example.com:5000/repo:tag", "sha256:111", "example.com:5000/repo:tag@sha256:111"),
+		Entry("registry port without tag", "example.com:5000/repo", "sha256:111", "example.com:5000/repo@sha256:111"),
+		Entry("already pinned", "example.com/repo@sha256:000", "sha256:111", "example.com/repo@sha256:000"),
+	)
+})
+
+var _ = Describe("GetPrePullState", func() {
+	var (
+		micAPI MIC
+	)
+
+	BeforeEach(func() {
+		micAPI = New(nil, nil)
+	})
+
+	testMic := kmmv1beta1.ModuleImagesConfig{
+		Status: kmmv1beta1.ModuleImagesConfigStatus{
+			ImagesStates: []kmmv1beta1.ModuleImageState{
+				{Image: "example.com/repo:tag", Status: kmmv1beta1.ImageExists, Digest: "sha256:111"},
+			},
+			PrePullStates: []kmmv1beta1.PrePullNodeState{
+				{Image: "example.com/repo:tag@sha256:111", Node: "node1", State: kmmv1beta1.PrePullPulled},
+				{Image: "example.com/repo:tag@sha256:000", Node: "node2", State: kmmv1beta1.PrePullPulled},
+			},
+		},
+	}
+
+	It("should return the state of the pinned image on the node", func() {
+		Expect(micAPI.GetPrePullState(&testMic, "example.com/repo:tag", "node1")).To(Equal(kmmv1beta1.PrePullPulled))
+	})
+
+	It("should ignore the pulls of other digests", func() {
+		Expect(micAPI.GetPrePullState(&testMic, "example.com/repo:tag", "node2")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("IndexImages", func() {
+	It("should return the images of the spec", func() {
+		testMic := kmmv1beta1.ModuleImagesConfig{
+			Spec: kmmv1beta1.ModuleImagesConfigSpec{
+				Images: []kmmv1beta1.ModuleImageSpec{{Image: "image 1"}, {Image: "image 2"}},
+			},
+		}
+		Expect(IndexImages(&testMic)).To(Equal([]string{"image 1", "image 2"}))
+	})
+
+	It("should not index other objects", func() {
+		Expect(IndexImages(&kmmv1beta1.Module{})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("ListImages", func() {
+	var (
+		ctx        context.Context
+		mockClient *client.MockClient
+		micAPI     MIC
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockClient = client.NewMockClient(gomock.NewController(GinkgoT()))
+		micAPI = New(mockClient, scheme)
+	})
+
+	It("should return an error if the MICs could not be listed", func() {
+		mockClient.EXPECT().List(ctx, gomock.Any()).Return(fmt.Errorf("some error"))
+
+		_, err := micAPI.ListImages(ctx)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return the images of all MICs once", func() {
+		mockClient.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, list *kmmv1beta1.ModuleImagesConfigList, _ ...ctrlclient.ListOption) error {
+				list.Items = []kmmv1beta1.ModuleImagesConfig{
+					{Spec: kmmv1beta1.ModuleImagesConfigSpec{Images: []kmmv1beta1.ModuleImageSpec{{Image: "image 2"}, {Image: "image 1"}}}},
+					{Spec: kmmv1beta1.ModuleImagesConfigSpec{Images: []kmmv1beta1.ModuleImageSpec{{Image: "image 1"}}}},
+				}
+				return nil
+			},
+		)
+
+		images, err := micAPI.ListImages(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(images).To(Equal([]string{"image 1", "image 2"}))
+	})
+})
+
+var _ = Describe("GetSharedImageState", func() {
+	const image = "example.com/repo:tag"
+
+	var (
+		ctx        context.Context
+		ctrl       *gomock.Controller
+		mockClient *client.MockClient
+		micAPI     MIC
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = client.NewMockClient(ctrl)
+		micAPI = New(mockClient, scheme)
+	})
+
+	newMIC := func(namespace string, state kmmv1beta1.ImageState, build bool) kmmv1beta1.ModuleImagesConfig {
+		micObj := kmmv1beta1.ModuleImagesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "mic", Namespace: namespace},
+			Spec: kmmv1beta1.ModuleImagesConfigSpec{
+				Images: []kmmv1beta1.ModuleImageSpec{{Image: image}},
+			},
+		}
+		if build {
+			micObj.Spec.Images[0].Build = &kmmv1beta1.Build{}
+		}
+		if state != "" {
+			micObj.Status.ImagesStates = []kmmv1beta1.ModuleImageState{{Image: image, Status: state}}
+		}
+		return micObj
+	}
+
+	expectList := func(mics ...kmmv1beta1.ModuleImagesConfig) {
+		mockClient.EXPECT().List(ctx, &kmmv1beta1.ModuleImagesConfigList{}, ctrlclient.MatchingFields{ImageIndexKey: image}).DoAndReturn(
+			func(_ interface{}, list *kmmv1beta1.ModuleImagesConfigList, _ ...ctrlclient.ListOption) error {
+				list.Items = mics
+				return nil
+			},
+		)
+	}
+
+	It("should return an error if the MICs cannot be listed", func() {
+		micObj := newMIC("ns-b", "", false)
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
+
+		_, err := micAPI.GetSharedImageState(ctx, &micObj, image)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return the state of the image in a MIC according to which it exists", func() {
+		micObj := newMIC("ns-b", "", false)
+		existing := newMIC("ns-c", kmmv1beta1.ImageExists, false)
+		existing.Status.ImagesStates[0].Digest = "sha256:111"
+		expectList(micObj, newMIC("ns-a", kmmv1beta1.ImageNeedsBuilding, true), existing)
+
+		res, err := micAPI.GetSharedImageState(ctx, &micObj, image)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Verified).To(Equal(&existing.Status.ImagesStates[0]))
+		Expect(res.Builder).To(BeNil())
+	})
+
+	It("should return the state of the image in a MIC according to which it does not have the expected layout", func() {
+		micObj := newMIC("ns-b", "", true)
+		invalid := newMIC("ns-a", kmmv1beta1.ImageInvalidLayout, true)
+		invalid.Status.ImagesStates[0].LayoutFindings = []string{"some finding"}
+		expectList(invalid)
+
+		res, err := micAPI.GetSharedImageState(ctx, &micObj, image)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Verified).To(Equal(&invalid.Status.ImagesStates[0]))
+		Expect(res.Builder).To(BeNil())
+	})
+
+	It("should share the state of the image between MICs using the same pull secret in the same namespace", func() {
+		micObj := newMIC("ns-b", "", false)
+		micObj.Spec.ImageRepoSecret = &v1.LocalObjectReference{Name: "pull-secret"}
+		existing := newMIC("ns-b", kmmv1beta1.ImageExists, false)
+		existing.Name = "other-mic"
+		existing.Spec.ImageRepoSecret = &v1.LocalObjectReference{Name: "pull-secret"}
+		expectList(existing)
+
+		res, err := micAPI.GetSharedImageState(ctx, &micObj, image)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Verified).To(Equal(&existing.Status.ImagesStates[0]))
+	})
+
+	DescribeTable("should not share the state of an existing image",
+		func(mutate func(micObj, other *kmmv1beta1.ModuleImagesConfig)) {
+			micObj := newMIC("ns-b", "", false)
+			existing := newMIC("ns-c", kmmv1beta1.ImageExists, false)
+			mutate(&micObj, &existing)
+			expectList(existing)
+
+			res, err := micAPI.GetSharedImageState(ctx, &micObj, image)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(&SharedImageState{}))
+		},
+		Entry("pulled with a pull secret by the MIC only", func(micObj, _ *kmmv1beta1.ModuleImagesConfig) {
+			micObj.Spec.ImageRepoSecret = &v1.LocalObjectReference{Name: "pull-secret"}
+		}),
+		Entry("pulled with a pull secret by the other MIC only", func(_, other *kmmv1beta1.ModuleImagesConfig) {
+			other.Spec.ImageRepoSecret = &v1.LocalObjectReference{Name: "pull-secret"}
+		}),
+		Entry("pulled with pull secrets of the same name in different namespaces", func(micObj, other *kmmv1beta1.ModuleImagesConfig) {
+			micObj.Spec.ImageRepoSecret = &v1.LocalObjectReference{Name: "pull-secret"}
+			other.Spec.ImageRepoSecret = &v1.LocalObjectReference{Name: "pull-secret"}
+		}),
+		Entry("expected with another directory", func(_, other *kmmv1beta1.ModuleImagesConfig) {
+			other.Spec.Images[0].DirName = "/other"
+		}),
+		Entry("expected with other modules", func(_, other *kmmv1beta1.ModuleImagesConfig) {
+			other.Spec.Images[0].ModuleNames = []string{"other"}
+		}),
+		Entry("expected for another kernel", func(_, other *kmmv1beta1.ModuleImagesConfig) {
+			other.Spec.Images[0].KernelVersion = "other"
+		}),
+		Entry("expected with another firmware path", func(_, other *kmmv1beta1.ModuleImagesConfig) {
+			other.Spec.Images[0].FirmwarePath = "/other"
+		}),
+	)
+
+	It("should ignore the state of the MIC itself", func() {
+		micObj := newMIC("ns-b", kmmv1beta1.ImageNeedsBuilding, true)
+		expectList(micObj)
+
+		res, err := micAPI.GetSharedImageState(ctx, &micObj, image)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(&SharedImageState{}))
+	})
+
+	DescribeTable("should return the MIC building the image",
+		func(state kmmv1beta1.ImageState, otherNamespace string, otherState kmmv1beta1.ImageState, otherBuild, expectBuilder bool) {
+			micObj := newMIC("ns-b", state, true)
+			other := newMIC(otherNamespace, otherState, otherBuild)
+			expectList(other)
+
+			res, err := micAPI.GetSharedImageState(ctx, &micObj, image)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Verified).To(BeNil())
+			if expectBuilder {
+				Expect(res.Builder).To(Equal(&types.NamespacedName{Namespace: otherNamespace, Name: "mic"}))
+			} else {
+				Expect(res.Builder).To(BeNil())
+			}
+		},
+		Entry("not built yet, other MIC building", kmmv1beta1.ImageState(""), "ns-c", kmmv1beta1.ImageNeedsBuilding, true, true),
+		Entry("not built yet, other MIC signing", kmmv1beta1.ImageState(""), "ns-c", kmmv1beta1.ImageNeedsSigning, false, true),
+		Entry("not built yet, other MIC failed to build", kmmv1beta1.ImageState(""), "ns-c", kmmv1beta1.ImageDoesNotExist, true, false),
+		Entry("not built yet, image missing in other MIC", kmmv1beta1.ImageState(""), "ns-c", kmmv1beta1.ImageDoesNotExist, false, false),
+		Entry("both building, other MIC first", kmmv1beta1.ImageNeedsBuilding, "ns-a", kmmv1beta1.ImageNeedsBuilding, true, true),
+		Entry("both building, MIC first", kmmv1beta1.ImageNeedsBuilding, "ns-c", kmmv1beta1.ImageNeedsBuilding, true, false),
+	)
+})
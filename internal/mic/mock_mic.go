@@ -42,17 +42,17 @@ func (m *MockMIC) EXPECT() *MockMICMockRecorder {
 }
 
 // CreateOrPatch mocks base method.
-func (m *MockMIC) CreateOrPatch(ctx context.Context, name, ns string, images []v1beta1.ModuleImageSpec, imageRepoSecret *v1.LocalObjectReference, pullPolicy v1.PullPolicy, pushBuiltImage bool, imageRebuildTriggerGeneration *int, tolerations []v1.Toleration, owner v10.Object) error {
+func (m *MockMIC) CreateOrPatch(ctx context.Context, name, ns string, images []v1beta1.ModuleImageSpec, imageRepoSecret *v1.LocalObjectReference, pullPolicy v1.PullPolicy, pushBuiltImage bool, imageRebuildTriggerGeneration *int, buildPriority int32, tolerations []v1.Toleration, prePulls []v1beta1.PrePullSpec, imageGC *v1beta1.ImageGCSpec, owner v10.Object) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrPatch", ctx, name, ns, images, imageRepoSecret, pullPolicy, pushBuiltImage, imageRebuildTriggerGeneration, tolerations, owner)
+	ret := m.ctrl.Call(m, "CreateOrPatch", ctx, name, ns, images, imageRepoSecret, pullPolicy, pushBuiltImage, imageRebuildTriggerGeneration, buildPriority, tolerations, prePulls, imageGC, owner)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateOrPatch indicates an expected call of CreateOrPatch.
-func (mr *MockMICMockRecorder) CreateOrPatch(ctx, name, ns, images, imageRepoSecret, pullPolicy, pushBuiltImage, imageRebuildTriggerGeneration, tolerations, owner any) *gomock.Call {
+func (mr *MockMICMockRecorder) CreateOrPatch(ctx, name, ns, images, imageRepoSecret, pullPolicy, pushBuiltImage, imageRebuildTriggerGeneration, buildPriority, tolerations, prePulls, imageGC, owner any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrPatch", reflect.TypeOf((*MockMIC)(nil).CreateOrPatch), ctx, name, ns, images, imageRepoSecret, pullPolicy, pushBuiltImage, imageRebuildTriggerGeneration, tolerations, owner)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrPatch", reflect.TypeOf((*MockMIC)(nil).CreateOrPatch), ctx, name, ns, images, imageRepoSecret, pullPolicy, pushBuiltImage, imageRebuildTriggerGeneration, buildPriority, tolerations, prePulls, imageGC, owner)
 }
 
 // DoAllImagesExist mocks base method.
@@ -84,6 +84,34 @@ func (mr *MockMICMockRecorder) Get(ctx, name, ns any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMIC)(nil).Get), ctx, name, ns)
 }
 
+// GetImageDigest mocks base method.
+func (m *MockMIC) GetImageDigest(micObj *v1beta1.ModuleImagesConfig, image string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageDigest", micObj, image)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetImageDigest indicates an expected call of GetImageDigest.
+func (mr *MockMICMockRecorder) GetImageDigest(micObj, image any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageDigest", reflect.TypeOf((*MockMIC)(nil).GetImageDigest), micObj, image)
+}
+
+// GetImageLayoutFindings mocks base method.
+func (m *MockMIC) GetImageLayoutFindings(micObj *v1beta1.ModuleImagesConfig, image string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageLayoutFindings", micObj, image)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetImageLayoutFindings indicates an expected call of GetImageLayoutFindings.
+func (mr *MockMICMockRecorder) GetImageLayoutFindings(micObj, image any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageLayoutFindings", reflect.TypeOf((*MockMIC)(nil).GetImageLayoutFindings), micObj, image)
+}
+
 // GetImageState mocks base method.
 func (m *MockMIC) GetImageState(micObj *v1beta1.ModuleImagesConfig, image string) v1beta1.ImageState {
 	m.ctrl.T.Helper()
@@ -98,6 +126,20 @@ func (mr *MockMICMockRecorder) GetImageState(micObj, image any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageState", reflect.TypeOf((*MockMIC)(nil).GetImageState), micObj, image)
 }
 
+// GetImageVerificationFailures mocks base method.
+func (m *MockMIC) GetImageVerificationFailures(micObj *v1beta1.ModuleImagesConfig, image string) int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageVerificationFailures", micObj, image)
+	ret0, _ := ret[0].(int32)
+	return ret0
+}
+
+// GetImageVerificationFailures indicates an expected call of GetImageVerificationFailures.
+func (mr *MockMICMockRecorder) GetImageVerificationFailures(micObj, image any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageVerificationFailures", reflect.TypeOf((*MockMIC)(nil).GetImageVerificationFailures), micObj, image)
+}
+
 // GetModuleImageSpec mocks base method.
 func (m *MockMIC) GetModuleImageSpec(micObj *v1beta1.ModuleImagesConfig, image string) *v1beta1.ModuleImageSpec {
 	m.ctrl.T.Helper()
@@ -112,6 +154,103 @@ func (mr *MockMICMockRecorder) GetModuleImageSpec(micObj, image any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleImageSpec", reflect.TypeOf((*MockMIC)(nil).GetModuleImageSpec), micObj, image)
 }
 
+// GetPinnedImage mocks base method.
+func (m *MockMIC) GetPinnedImage(micObj *v1beta1.ModuleImagesConfig, image string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPinnedImage", micObj, image)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetPinnedImage indicates an expected call of GetPinnedImage.
+func (mr *MockMICMockRecorder) GetPinnedImage(micObj, image any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPinnedImage", reflect.TypeOf((*MockMIC)(nil).GetPinnedImage), micObj, image)
+}
+
+// GetPrePullState mocks base method.
+func (m *MockMIC) GetPrePullState(micObj *v1beta1.ModuleImagesConfig, image, nodeName string) v1beta1.PrePullState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrePullState", micObj, image, nodeName)
+	ret0, _ := ret[0].(v1beta1.PrePullState)
+	return ret0
+}
+
+// GetPrePullState indicates an expected call of GetPrePullState.
+func (mr *MockMICMockRecorder) GetPrePullState(micObj, image, nodeName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrePullState", reflect.TypeOf((*MockMIC)(nil).GetPrePullState), micObj, image, nodeName)
+}
+
+// GetSharedImageState mocks base method.
+func (m *MockMIC) GetSharedImageState(ctx context.Context, micObj *v1beta1.ModuleImagesConfig, image string) (*SharedImageState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedImageState", ctx, micObj, image)
+	ret0, _ := ret[0].(*SharedImageState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedImageState indicates an expected call of GetSharedImageState.
+func (mr *MockMICMockRecorder) GetSharedImageState(ctx, micObj, image any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedImageState", reflect.TypeOf((*MockMIC)(nil).GetSharedImageState), ctx, micObj, image)
+}
+
+// ListByImage mocks base method.
+func (m *MockMIC) ListByImage(ctx context.Context, image string) ([]v1beta1.ModuleImagesConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByImage", ctx, image)
+	ret0, _ := ret[0].([]v1beta1.ModuleImagesConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByImage indicates an expected call of ListByImage.
+func (mr *MockMICMockRecorder) ListByImage(ctx, image any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByImage", reflect.TypeOf((*MockMIC)(nil).ListByImage), ctx, image)
+}
+
+// ListImages mocks base method.
+func (m *MockMIC) ListImages(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImages", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImages indicates an expected call of ListImages.
+func (mr *MockMICMockRecorder) ListImages(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImages", reflect.TypeOf((*MockMIC)(nil).ListImages), ctx)
+}
+
+// SetImageBuildInputsHash mocks base method.
+func (m *MockMIC) SetImageBuildInputsHash(micObj *v1beta1.ModuleImagesConfig, image, hash string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetImageBuildInputsHash", micObj, image, hash)
+}
+
+// SetImageBuildInputsHash indicates an expected call of SetImageBuildInputsHash.
+func (mr *MockMICMockRecorder) SetImageBuildInputsHash(micObj, image, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageBuildInputsHash", reflect.TypeOf((*MockMIC)(nil).SetImageBuildInputsHash), micObj, image, hash)
+}
+
+// SetImageLayoutFindings mocks base method.
+func (m *MockMIC) SetImageLayoutFindings(micObj *v1beta1.ModuleImagesConfig, image string, findings []string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetImageLayoutFindings", micObj, image, findings)
+}
+
+// SetImageLayoutFindings indicates an expected call of SetImageLayoutFindings.
+func (mr *MockMICMockRecorder) SetImageLayoutFindings(micObj, image, findings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageLayoutFindings", reflect.TypeOf((*MockMIC)(nil).SetImageLayoutFindings), micObj, image, findings)
+}
+
 // SetImageStatus mocks base method.
 func (m *MockMIC) SetImageStatus(micObj *v1beta1.ModuleImagesConfig, image string, status v1beta1.ImageState) {
 	m.ctrl.T.Helper()
@@ -123,3 +262,29 @@ func (mr *MockMICMockRecorder) SetImageStatus(micObj, image, status any) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageStatus", reflect.TypeOf((*MockMIC)(nil).SetImageStatus), micObj, image, status)
 }
+
+// SetImageVerificationFailed mocks base method.
+func (m *MockMIC) SetImageVerificationFailed(micObj *v1beta1.ModuleImagesConfig, image string, failedTime v10.Time) int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetImageVerificationFailed", micObj, image, failedTime)
+	ret0, _ := ret[0].(int32)
+	return ret0
+}
+
+// SetImageVerificationFailed indicates an expected call of SetImageVerificationFailed.
+func (mr *MockMICMockRecorder) SetImageVerificationFailed(micObj, image, failedTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageVerificationFailed", reflect.TypeOf((*MockMIC)(nil).SetImageVerificationFailed), micObj, image, failedTime)
+}
+
+// SetImageVerified mocks base method.
+func (m *MockMIC) SetImageVerified(micObj *v1beta1.ModuleImagesConfig, image, digest string, verifiedTime v10.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetImageVerified", micObj, image, digest, verifiedTime)
+}
+
+// SetImageVerified indicates an expected call of SetImageVerified.
+func (mr *MockMICMockRecorder) SetImageVerified(micObj, image, digest, verifiedTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageVerified", reflect.TypeOf((*MockMIC)(nil).SetImageVerified), micObj, image, digest, verifiedTime)
+}
@@ -8,7 +8,9 @@ import (
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/kernel"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/signingkey"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
+	v1 "k8s.io/api/core/v1"
 )
 
 var ErrNoMatchingKernelMapping = errors.New("kernel mapping not found")
@@ -143,6 +145,8 @@ func (kh *kernelMapperHelper) prepareModuleLoaderData(mapping *kmmv1beta1.Kernel
 	mld.Modprobe = mod.Spec.ModuleLoader.Container.Modprobe
 	mld.ModuleVersion = mod.Spec.ModuleLoader.Container.Version
 	mld.ImagePullPolicy = mod.Spec.ModuleLoader.Container.ImagePullPolicy
+	mld.BuildPriority = mod.Spec.BuildPriority
+	mld.ImagePrePull = mod.Spec.ImagePrePull
 	mld.Owner = mod
 
 	return mld, nil
@@ -181,32 +185,23 @@ func (kh *kernelMapperHelper) getRelevantBuild(moduleBuild *kmmv1beta1.Build, ma
 	buildConfig.BuildArgs = kh.buildArgOverrider.ApplyBuildArgOverrides(buildConfig.BuildArgs, mappingBuild.BuildArgs...)
 
 	buildConfig.Secrets = append(buildConfig.Secrets, mappingBuild.Secrets...)
+	buildConfig.ContextConfigMaps = append(buildConfig.ContextConfigMaps, mappingBuild.ContextConfigMaps...)
+	buildConfig.ContextSecrets = append(buildConfig.ContextSecrets, mappingBuild.ContextSecrets...)
+
+	if mappingBuild.RetryPolicy != nil {
+		buildConfig.RetryPolicy = mappingBuild.RetryPolicy.DeepCopy()
+	}
+
+	if mappingBuild.Git != nil {
+		buildConfig.Git = mappingBuild.Git.DeepCopy()
+	}
+
 	return buildConfig
 }
 
 func (kh *kernelMapperHelper) getRelevantSign(moduleSign *kmmv1beta1.Sign, mappingSign *kmmv1beta1.Sign, kernelVersion string) (*kmmv1beta1.Sign, error) {
-	var signConfig *kmmv1beta1.Sign
-	if moduleSign == nil {
-		// km.Sign cannot be nil in case mod.Sign is nil, checked above
-		signConfig = mappingSign.DeepCopy()
-	} else if mappingSign == nil {
-		signConfig = moduleSign.DeepCopy()
-	} else {
-		signConfig = moduleSign.DeepCopy()
-
-		if mappingSign.UnsignedImage != "" {
-			signConfig.UnsignedImage = mappingSign.UnsignedImage
-		}
-
-		if mappingSign.KeySecret != nil {
-			signConfig.KeySecret = mappingSign.KeySecret
-		}
-		if mappingSign.CertSecret != nil {
-			signConfig.CertSecret = mappingSign.CertSecret
-		}
-		//append (not overwrite) any files in the km to the defaults
-		signConfig.FilesToSign = append(signConfig.FilesToSign, mappingSign.FilesToSign...)
-	}
+	// km.Sign cannot be nil in case mod.Sign is nil, checked above
+	signConfig := MergeSign(moduleSign, mappingSign)
 
 	osConfigEnvVars, err := utils.KernelComponentsAsEnvVars(
 		kernel.NormalizeVersion(kernelVersion),
@@ -225,5 +220,64 @@ func (kh *kernelMapperHelper) getRelevantSign(moduleSign *kmmv1beta1.Sign, mappi
 	}
 	signConfig.FilesToSign = filesToSign
 
+	// the key pair of a SigningKey is held by a Secret generated by KMM
+	if signConfig.SigningKey != nil {
+		secretRef := &v1.LocalObjectReference{Name: signingkey.SecretName(signConfig.SigningKey.Name)}
+		signConfig.KeySecret = secretRef
+		signConfig.CertSecret = secretRef
+	}
+
 	return signConfig, nil
 }
+
+// MergeSign returns the Sign configuration of a kernel mapping, given the Sign section of the Module and the one of
+// the mapping, either of which may be nil. Templates are not replaced.
+func MergeSign(moduleSign *kmmv1beta1.Sign, mappingSign *kmmv1beta1.Sign) *kmmv1beta1.Sign {
+	if moduleSign == nil {
+		return mappingSign.DeepCopy()
+	}
+
+	signConfig := moduleSign.DeepCopy()
+	if mappingSign == nil {
+		return signConfig
+	}
+
+	if mappingSign.UnsignedImage != "" {
+		signConfig.UnsignedImage = mappingSign.UnsignedImage
+	}
+
+	// a mapping's signing key replaces the Module's one, whether it is a Secret, a PKCS#11 key, a signing service
+	// or a SigningKey
+	if mappingSign.KeySecret != nil || mappingSign.PKCS11 != nil || mappingSign.SigningService != nil ||
+		mappingSign.SigningKey != nil {
+		signConfig.KeySecret = mappingSign.KeySecret
+		signConfig.PKCS11 = mappingSign.PKCS11.DeepCopy()
+		signConfig.SigningService = mappingSign.SigningService.DeepCopy()
+		signConfig.SigningKey = mappingSign.SigningKey
+	}
+	// a SigningKey brings its own certificate, which cannot be replaced
+	if signConfig.SigningKey != nil {
+		signConfig.CertSecret = nil
+	} else if mappingSign.CertSecret != nil {
+		signConfig.CertSecret = mappingSign.CertSecret
+	}
+	switch {
+	case mappingSign.AutoDiscover != nil:
+		// the mapping's auto-discovery replaces the Module's files or auto-discovery
+		signConfig.AutoDiscover = mappingSign.AutoDiscover.DeepCopy()
+		signConfig.FilesToSign = nil
+	case signConfig.AutoDiscover != nil && len(mappingSign.FilesToSign) > 0:
+		// the mapping's files replace the Module's auto-discovery
+		signConfig.AutoDiscover = nil
+		signConfig.FilesToSign = mappingSign.FilesToSign
+	default:
+		//append (not overwrite) any files in the km to the defaults
+		signConfig.FilesToSign = append(signConfig.FilesToSign, mappingSign.FilesToSign...)
+	}
+
+	if mappingSign.RetryPolicy != nil {
+		signConfig.RetryPolicy = mappingSign.RetryPolicy.DeepCopy()
+	}
+
+	return signConfig
+}
@@ -384,6 +384,33 @@ var _ = Describe("getRelevantBuild", func() {
 		Expect(res.DockerfileConfigMap).To(Equal(mappingBuild.DockerfileConfigMap))
 		Expect(res.BaseImageRegistryTLS).To(Equal(moduleBuild.BaseImageRegistryTLS))
 	})
+	It("kernel mapping and module loader builds are present, build context is merged", func() {
+		moduleBuild := &kmmv1beta1.Build{
+			ContextConfigMaps: []kmmv1beta1.BuildContextConfigMap{{ConfigMap: v1.LocalObjectReference{Name: "module-cm"}}},
+			ContextSecrets:    []kmmv1beta1.BuildContextSecret{{Secret: v1.LocalObjectReference{Name: "module-secret"}}},
+			Git:               &kmmv1beta1.GitBuildSource{URI: "https://example.org/module.git"},
+		}
+		mappingBuild := &kmmv1beta1.Build{
+			ContextConfigMaps: []kmmv1beta1.BuildContextConfigMap{{ConfigMap: v1.LocalObjectReference{Name: "mapping-cm"}}},
+			Git:               &kmmv1beta1.GitBuildSource{URI: "https://example.org/mapping.git", Ref: "v1"},
+		}
+
+		res := kh.getRelevantBuild(moduleBuild, mappingBuild)
+		Expect(res.ContextConfigMaps).To(Equal(append(moduleBuild.ContextConfigMaps, mappingBuild.ContextConfigMaps...)))
+		Expect(res.ContextSecrets).To(Equal(moduleBuild.ContextSecrets))
+		Expect(res.Git).To(Equal(mappingBuild.Git))
+	})
+	It("kernel mapping and module loader builds are present, the retry policy of the mapping is used", func() {
+		moduleBuild := &kmmv1beta1.Build{
+			RetryPolicy: &kmmv1beta1.RetryPolicy{MaxAttempts: 2},
+		}
+		mappingBuild := &kmmv1beta1.Build{
+			RetryPolicy: &kmmv1beta1.RetryPolicy{MaxAttempts: 5},
+		}
+
+		Expect(kh.getRelevantBuild(moduleBuild, mappingBuild).RetryPolicy).To(Equal(mappingBuild.RetryPolicy))
+		Expect(kh.getRelevantBuild(moduleBuild, &kmmv1beta1.Build{}).RetryPolicy).To(Equal(moduleBuild.RetryPolicy))
+	})
 })
 
 var _ = Describe("getRelevantSign", func() {
@@ -486,6 +513,152 @@ var _ = Describe("getRelevantSign", func() {
 				CertSecret:    &v1.LocalObjectReference{Name: certSecret},
 			},
 		),
+		Entry(
+			"km.Sign KeySecret replaces the default PKCS#11 key",
+			&kmmv1beta1.Sign{
+				UnsignedImage: unsignedImage,
+				PKCS11:        &kmmv1beta1.PKCS11Key{URI: "pkcs11:object=key", ModulePath: "/lib/pkcs11.so"},
+				CertSecret:    &v1.LocalObjectReference{Name: certSecret},
+			},
+			&kmmv1beta1.Sign{
+				KeySecret:   &v1.LocalObjectReference{Name: keySecret},
+				FilesToSign: strings.Split(filesToSign, ":"),
+			},
+		),
 	)
 
+	It("should replace the default KeySecret with the mapping's PKCS#11 key", func() {
+		pkcs11 := &kmmv1beta1.PKCS11Key{URI: "pkcs11:object=key", ModulePath: "/lib/pkcs11.so"}
+
+		actual, err := kh.getRelevantSign(
+			&kmmv1beta1.Sign{
+				UnsignedImage: unsignedImage,
+				KeySecret:     &v1.LocalObjectReference{Name: keySecret},
+				CertSecret:    &v1.LocalObjectReference{Name: certSecret},
+			},
+			&kmmv1beta1.Sign{PKCS11: pkcs11},
+			kernelVersion,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual.KeySecret).To(BeNil())
+		Expect(actual.PKCS11).To(Equal(pkcs11))
+	})
+
+	It("should replace the default PKCS#11 key with the mapping's signing service", func() {
+		signingService := &kmmv1beta1.SigningService{URL: "https://signer.example.org/sign"}
+
+		actual, err := kh.getRelevantSign(
+			&kmmv1beta1.Sign{
+				UnsignedImage: unsignedImage,
+				PKCS11:        &kmmv1beta1.PKCS11Key{URI: "pkcs11:object=key", ModulePath: "/lib/pkcs11.so"},
+				CertSecret:    &v1.LocalObjectReference{Name: certSecret},
+			},
+			&kmmv1beta1.Sign{SigningService: signingService},
+			kernelVersion,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual.PKCS11).To(BeNil())
+		Expect(actual.SigningService).To(Equal(signingService))
+	})
+
+	It("should replace the default FilesToSign with the mapping's auto-discovery", func() {
+		autoDiscover := &kmmv1beta1.SignAutoDiscovery{Include: []string{"kernel/drivers/*"}}
+
+		actual, err := kh.getRelevantSign(
+			&kmmv1beta1.Sign{FilesToSign: strings.Split(filesToSign, ":")},
+			&kmmv1beta1.Sign{AutoDiscover: autoDiscover},
+			kernelVersion,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual.FilesToSign).To(BeEmpty())
+		Expect(actual.AutoDiscover).To(Equal(autoDiscover))
+	})
+
+	It("should replace the default auto-discovery with the mapping's FilesToSign", func() {
+		actual, err := kh.getRelevantSign(
+			&kmmv1beta1.Sign{AutoDiscover: &kmmv1beta1.SignAutoDiscovery{}},
+			&kmmv1beta1.Sign{FilesToSign: strings.Split(filesToSign, ":")},
+			kernelVersion,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual.FilesToSign).To(Equal(strings.Split(filesToSign, ":")))
+		Expect(actual.AutoDiscover).To(BeNil())
+	})
+
+	It("should keep the default auto-discovery", func() {
+		autoDiscover := &kmmv1beta1.SignAutoDiscovery{Exclude: []string{"*/test.ko"}}
+
+		actual, err := kh.getRelevantSign(
+			&kmmv1beta1.Sign{AutoDiscover: autoDiscover},
+			&kmmv1beta1.Sign{KeySecret: &v1.LocalObjectReference{Name: keySecret}},
+			kernelVersion,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual.FilesToSign).To(BeEmpty())
+		Expect(actual.AutoDiscover).To(Equal(autoDiscover))
+	})
+
+	It("should use the Secret of the SigningKey for the key and the certificate", func() {
+		signingKey := &v1.LocalObjectReference{Name: "sk"}
+
+		actual, err := kh.getRelevantSign(
+			&kmmv1beta1.Sign{
+				UnsignedImage: unsignedImage,
+				KeySecret:     &v1.LocalObjectReference{Name: keySecret},
+				CertSecret:    &v1.LocalObjectReference{Name: certSecret},
+			},
+			&kmmv1beta1.Sign{SigningKey: signingKey},
+			kernelVersion,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual.SigningKey).To(Equal(signingKey))
+		Expect(actual.KeySecret).To(Equal(&v1.LocalObjectReference{Name: "sk-signing-key"}))
+		Expect(actual.CertSecret).To(Equal(&v1.LocalObjectReference{Name: "sk-signing-key"}))
+	})
+
+	It("should replace the default SigningKey with the mapping's KeySecret", func() {
+		actual, err := kh.getRelevantSign(
+			&kmmv1beta1.Sign{SigningKey: &v1.LocalObjectReference{Name: "sk"}},
+			&kmmv1beta1.Sign{
+				KeySecret:  &v1.LocalObjectReference{Name: keySecret},
+				CertSecret: &v1.LocalObjectReference{Name: certSecret},
+			},
+			kernelVersion,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual.SigningKey).To(BeNil())
+		Expect(actual.KeySecret).To(Equal(&v1.LocalObjectReference{Name: keySecret}))
+		Expect(actual.CertSecret).To(Equal(&v1.LocalObjectReference{Name: certSecret}))
+	})
+
+	It("should keep the certificate of the default SigningKey", func() {
+		actual, err := kh.getRelevantSign(
+			&kmmv1beta1.Sign{SigningKey: &v1.LocalObjectReference{Name: "sk"}},
+			&kmmv1beta1.Sign{CertSecret: &v1.LocalObjectReference{Name: certSecret}},
+			kernelVersion,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual.CertSecret).To(Equal(&v1.LocalObjectReference{Name: "sk-signing-key"}))
+	})
+
+})
+
+var _ = Describe("MergeSign", func() {
+	It("should return nil if there is no Sign section", func() {
+		Expect(MergeSign(nil, nil)).To(BeNil())
+	})
+
+	It("should drop the Module's certificate if the mapping uses a SigningKey", func() {
+		signingKey := &v1.LocalObjectReference{Name: "sk"}
+
+		actual := MergeSign(
+			&kmmv1beta1.Sign{
+				KeySecret:   &v1.LocalObjectReference{Name: "key"},
+				CertSecret:  &v1.LocalObjectReference{Name: "cert"},
+				FilesToSign: []string{"/opt/mod.ko"},
+			},
+			&kmmv1beta1.Sign{SigningKey: signingKey},
+		)
+		Expect(actual).To(Equal(&kmmv1beta1.Sign{SigningKey: signingKey, FilesToSign: []string{"/opt/mod.ko"}}))
+	})
 })
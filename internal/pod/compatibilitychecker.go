@@ -0,0 +1,328 @@
+package pod
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type CompatibilityCheckStatus string
+
+const (
+	CompatibilityCheckInProgress   CompatibilityCheckStatus = "inProgress"
+	CompatibilityCheckCompatible   CompatibilityCheckStatus = "compatible"
+	CompatibilityCheckIncompatible CompatibilityCheckStatus = "incompatible"
+	CompatibilityCheckSkipped      CompatibilityCheckStatus = "skipped"
+	// CompatibilityCheckFailed means that the pod failed without a verdict of the checker, for instance because an
+	// image could not be pulled or the pod was evicted; the check can be retried.
+	CompatibilityCheckFailed CompatibilityCheckStatus = "failed"
+
+	compatibilityCheckLabelKey = "kmm.node.kubernetes.io/compatibility-check"
+
+	modulesCopierContainerName = "modules"
+	checkerContainerName       = "checker"
+
+	compatibilityCheckModulesDir = "/kmm/modules"
+
+	// incompatibleExitCode is the exit code of compatibilityCheckScript if the kernel modules do not match the kernel.
+	incompatibleExitCode = 65
+	// skippedExitCode is the exit code of compatibilityCheckScript if the image lacks what is needed for the check.
+	skippedExitCode = 66
+)
+
+// modulesCopyScript copies the kernel modules of a kmod image to the volume shared with the checker container. It is
+// passed the directory of the modules and the kernel version as positional parameters.
+const modulesCopyScript = `cp -R "$1/lib/modules/$2/." ` + compatibilityCheckModulesDir + `/`
+
+// compatibilityCheckScript runs in the DTK or builder image of a kernel and checks that the kernel modules copied from
+// the kmod image would load on it: their vermagic must match the kernel, and the symbols they use must be exported by
+// the kernel, or by the other kernel modules of the image, with the same CRC. It is passed the kernel version and the
+// names of the kernel modules as positional parameters.
+const compatibilityCheckScript = `kver="$1"
+shift
+modules=` + compatibilityCheckModulesDir + `
+symvers=""
+for f in "/usr/src/kernels/$kver/Module.symvers" "/lib/modules/$kver/build/Module.symvers" "/usr/src/linux-headers-$kver/Module.symvers"; do
+	if [ -f "$f" ]; then
+		symvers="$f"
+		break
+	fi
+done
+if [ -z "$symvers" ]; then
+	printf 'Module.symvers of kernel %s was not found in the image' "$kver" > /dev/termination-log
+	exit 66
+fi
+for tool in modprobe modinfo find awk; do
+	if ! command -v "$tool" >/dev/null 2>&1; then
+		printf '%s was not found in the image' "$tool" > /dev/termination-log
+		exit 66
+	fi
+done
+tmp=$(mktemp -d)
+awk '{ print $1, $2 }' "$symvers" > "$tmp/exports"
+find "$modules" -name '*.ko*' | while read -r ko; do
+	modprobe --show-exports "$ko" 2>/dev/null | awk '{ print $1, $2 }' >> "$tmp/exports"
+done
+: > "$tmp/findings"
+for name in "$@"; do
+	underscores=$(echo "$name" | tr - _)
+	dashes=$(echo "$name" | tr _ -)
+	ko=$(find "$modules" -name "$underscores.ko*" -o -name "$dashes.ko*" | head -n 1)
+	# missing kernel modules are reported by the verification of the image
+	[ -n "$ko" ] || continue
+	vermagic=$(modinfo -F vermagic "$ko" 2>/dev/null | cut -d ' ' -f 1)
+	if [ "${vermagic%+}" != "${kver%+}" ]; then
+		echo "$name: vermagic $vermagic does not match kernel $kver" >> "$tmp/findings"
+	fi
+	modprobe --dump-modversions "$ko" 2>/dev/null | awk -v name="$name" -v kver="$kver" '
+		FNR == NR { crc[$2] = tolower($1); next }
+		!($2 in crc) { print name ": symbol " $2 " is not exported by kernel " kver; next }
+		crc[$2] != tolower($1) { print name ": symbol " $2 " has CRC " $1 " but kernel " kver " exports it with CRC " crc[$2] }
+	' "$tmp/exports" - | head -n 10 >> "$tmp/findings"
+done
+if [ -s "$tmp/findings" ]; then
+	head -c 4096 "$tmp/findings" > /dev/termination-log
+	exit 65
+fi
+`
+
+// CompatibilityCheck describes the kernel modules to check and the kernel they must be compatible with.
+type CompatibilityCheck struct {
+	// Image is the kmod image containing the kernel modules.
+	Image string
+	// ImageRepoSecret is the pull secret of Image, if any.
+	ImageRepoSecret *v1.LocalObjectReference
+	// DirName is the root directory of the kernel modules in Image.
+	DirName string
+	// KernelVersion is the kernel with which the kernel modules must be compatible.
+	KernelVersion string
+	// ModuleNames are the names of the kernel modules to check.
+	ModuleNames []string
+	// SymversImage is the image containing the Module.symvers of KernelVersion, such as its DTK or builder image.
+	SymversImage string
+	// Attempt is the number of check pods that failed before this one.
+	Attempt int32
+}
+
+//go:generate mockgen -source=compatibilitychecker.go -package=pod -destination=mock_compatibilitychecker.go
+
+type CompatibilityChecker interface {
+	CreateCheckPod(ctx context.Context, name, namespace string, check *CompatibilityCheck, owner metav1.Object) error
+	GetCheckPod(ctx context.Context, name, namespace string, owner metav1.Object) (*v1.Pod, error)
+	DeleteCheckPod(ctx context.Context, pod *v1.Pod) error
+	GetCheckPodStatus(pod *v1.Pod) CompatibilityCheckStatus
+	GetCheckPodFindings(pod *v1.Pod) []string
+	GetCheckPodAttempt(pod *v1.Pod) int32
+}
+
+type compatibilityCheckerImpl struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+func NewCompatibilityChecker(client client.Client, scheme *runtime.Scheme) CompatibilityChecker {
+	return &compatibilityCheckerImpl{
+		client: client,
+		scheme: scheme,
+	}
+}
+
+// CreateCheckPod creates a pod checking that the kernel modules of the image would load on the kernel. The kernel
+// modules are copied from the kmod image by an init container, then checked against the Module.symvers of the kernel
+// in its DTK or builder image.
+func (cci *compatibilityCheckerImpl) CreateCheckPod(ctx context.Context, name, namespace string, check *CompatibilityCheck,
+	owner metav1.Object) error {
+
+	imagePullSecrets := []v1.LocalObjectReference{}
+	if check.ImageRepoSecret != nil {
+		imagePullSecrets = []v1.LocalObjectReference{*check.ImageRepoSecret}
+	}
+
+	volumeMounts := []v1.VolumeMount{
+		{
+			Name:      "modules",
+			MountPath: compatibilityCheckModulesDir,
+		},
+	}
+
+	checkerCommand := append(
+		[]string{"/bin/sh", "-c", compatibilityCheckScript, "check-compatibility", check.KernelVersion},
+		check.ModuleNames...,
+	)
+
+	annotations := map[string]string{}
+	if check.Attempt > 0 {
+		annotations[constants.ResourceAttemptAnnotation] = fmt.Sprintf("%d", check.Attempt)
+	}
+
+	checkPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: name + "-compatibility-check-",
+			Namespace:    namespace,
+			Labels:       map[string]string{compatibilityCheckLabelKey: name},
+			Annotations:  annotations,
+		},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{
+				{
+					Name:         modulesCopierContainerName,
+					Image:        check.Image,
+					Command:      []string{"/bin/sh", "-c", modulesCopyScript, "copy-modules", check.DirName, check.KernelVersion},
+					VolumeMounts: volumeMounts,
+				},
+			},
+			Containers: []v1.Container{
+				{
+					Name:         checkerContainerName,
+					Image:        check.SymversImage,
+					Command:      checkerCommand,
+					VolumeMounts: volumeMounts,
+				},
+			},
+			RestartPolicy:    v1.RestartPolicyNever,
+			ImagePullSecrets: imagePullSecrets,
+			Volumes: []v1.Volume{
+				{
+					Name:         "modules",
+					VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+				},
+			},
+		},
+	}
+
+	if err := ctrl.SetControllerReference(owner, checkPod, cci.scheme); err != nil {
+		return fmt.Errorf("failed to set owner for the compatibility check pod of %s: %v", name, err)
+	}
+
+	return cci.client.Create(ctx, checkPod)
+}
+
+// GetCheckPod returns the compatibility check pod of name created for owner, or nil if there is none. Pods being
+// deleted are ignored, so that a failed check can be replaced right away.
+func (cci *compatibilityCheckerImpl) GetCheckPod(ctx context.Context, name, namespace string, owner metav1.Object) (*v1.Pod, error) {
+	pl := v1.PodList{}
+
+	ml := client.MatchingLabels{compatibilityCheckLabelKey: name}
+
+	if err := cci.client.List(ctx, &pl, client.InNamespace(namespace), ml); err != nil {
+		return nil, fmt.Errorf("could not list the compatibility check pods of %s: %v", name, err)
+	}
+
+	for i := range pl.Items {
+		if pl.Items[i].DeletionTimestamp == nil && metav1.IsControlledBy(&pl.Items[i], owner) {
+			return &pl.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (cci *compatibilityCheckerImpl) DeleteCheckPod(ctx context.Context, pod *v1.Pod) error {
+
+	return deletePod(cci.client, ctx, pod)
+}
+
+func (cci *compatibilityCheckerImpl) GetCheckPodStatus(pod *v1.Pod) CompatibilityCheckStatus {
+	switch pod.Status.Phase {
+	case v1.PodSucceeded:
+		return CompatibilityCheckCompatible
+	case v1.PodFailed:
+		terminated := getCheckerTerminatedState(pod)
+		if terminated == nil {
+			return CompatibilityCheckFailed
+		}
+		switch terminated.ExitCode {
+		case incompatibleExitCode:
+			return CompatibilityCheckIncompatible
+		case skippedExitCode:
+			return CompatibilityCheckSkipped
+		}
+		return CompatibilityCheckFailed
+	case v1.PodUnknown:
+		return CompatibilityCheckFailed
+	}
+
+	// an image that cannot be pulled does not fail the pod
+	for _, statuses := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, s := range statuses {
+			if s.State.Waiting == nil {
+				continue
+			}
+			if reason := s.State.Waiting.Reason; reason == imagePullBackOffReason || reason == errImagePullReason {
+				return CompatibilityCheckFailed
+			}
+		}
+	}
+
+	return CompatibilityCheckInProgress
+}
+
+// GetCheckPodFindings returns why the kernel modules are incompatible with the kernel, or why they could not be
+// checked, as reported by the checker. If the pod failed without a verdict of the checker, it returns why the pod
+// failed instead.
+func (cci *compatibilityCheckerImpl) GetCheckPodFindings(pod *v1.Pod) []string {
+	terminated := getCheckerTerminatedState(pod)
+	if terminated != nil && (terminated.ExitCode == incompatibleExitCode || terminated.ExitCode == skippedExitCode) {
+		return strings.FieldsFunc(terminated.Message, func(r rune) bool { return r == '\n' })
+	}
+
+	findings := make([]string, 0)
+
+	if pod.Status.Reason != "" {
+		findings = append(findings, withMessage("pod "+pod.Name+" failed: "+pod.Status.Reason, pod.Status.Message))
+	}
+
+	for _, statuses := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, s := range statuses {
+			switch {
+			case s.State.Waiting != nil && (s.State.Waiting.Reason == imagePullBackOffReason || s.State.Waiting.Reason == errImagePullReason):
+				finding := fmt.Sprintf("container %s could not pull image %s", s.Name, s.Image)
+				findings = append(findings, withMessage(finding, s.State.Waiting.Message))
+			case s.State.Terminated != nil && s.State.Terminated.ExitCode != 0:
+				finding := fmt.Sprintf("container %s exited with code %d", s.Name, s.State.Terminated.ExitCode)
+				if s.State.Terminated.Reason != "" {
+					finding += " (" + s.State.Terminated.Reason + ")"
+				}
+				findings = append(findings, withMessage(finding, s.State.Terminated.Message))
+			}
+		}
+	}
+
+	return findings
+}
+
+// GetCheckPodAttempt returns the number of check pods that failed before pod.
+func (cci *compatibilityCheckerImpl) GetCheckPodAttempt(pod *v1.Pod) int32 {
+	attempt, err := strconv.ParseInt(pod.Annotations[constants.ResourceAttemptAnnotation], 10, 32)
+	if err != nil {
+		return 0
+	}
+
+	return int32(attempt)
+}
+
+func withMessage(finding, message string) string {
+	if message = strings.TrimSpace(message); message == "" {
+		return finding
+	}
+
+	return finding + ": " + message
+}
+
+func getCheckerTerminatedState(pod *v1.Pod) *v1.ContainerStateTerminated {
+	for _, s := range pod.Status.ContainerStatuses {
+		if s.Name == checkerContainerName {
+			return s.State.Terminated
+		}
+	}
+
+	return nil
+}
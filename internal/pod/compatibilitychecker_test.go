@@ -0,0 +1,326 @@
+package pod
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta2"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("CreateCheckPod", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		cc   CompatibilityChecker
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		cc = NewCompatibilityChecker(clnt, scheme)
+	})
+
+	ctx := context.Background()
+	owner := &v1beta2.PreflightValidation{ObjectMeta: metav1.ObjectMeta{Name: "some preflight"}}
+
+	It("should copy the kernel modules from the kmod image and check them in the symvers image", func() {
+		check := CompatibilityCheck{
+			Image:           "kmod-image",
+			ImageRepoSecret: &v1.LocalObjectReference{Name: "pull-secret"},
+			DirName:         "/opt",
+			KernelVersion:   "some kernel",
+			ModuleNames:     []string{"kmod-a", "kmod_b"},
+			SymversImage:    "dtk-image",
+		}
+
+		clnt.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, obj ctrlclient.Object, _ ...ctrlclient.CreateOption) error {
+				checkPod := obj.(*v1.Pod)
+				Expect(checkPod.GenerateName).To(Equal("some-name-compatibility-check-"))
+				Expect(checkPod.Namespace).To(Equal("some-namespace"))
+				Expect(checkPod.Labels).To(HaveKeyWithValue(compatibilityCheckLabelKey, "some-name"))
+				Expect(metav1.IsControlledBy(checkPod, owner)).To(BeTrue())
+				Expect(checkPod.Spec.ImagePullSecrets).To(Equal([]v1.LocalObjectReference{{Name: "pull-secret"}}))
+				Expect(checkPod.Spec.RestartPolicy).To(Equal(v1.RestartPolicyNever))
+
+				Expect(checkPod.Spec.InitContainers).To(HaveLen(1))
+				Expect(checkPod.Spec.InitContainers[0].Image).To(Equal("kmod-image"))
+				Expect(checkPod.Spec.InitContainers[0].Command).To(Equal([]string{
+					"/bin/sh", "-c", modulesCopyScript, "copy-modules", "/opt", "some kernel",
+				}))
+
+				Expect(checkPod.Spec.Containers).To(HaveLen(1))
+				Expect(checkPod.Spec.Containers[0].Image).To(Equal("dtk-image"))
+				Expect(checkPod.Spec.Containers[0].Command).To(Equal([]string{
+					"/bin/sh", "-c", compatibilityCheckScript, "check-compatibility", "some kernel", "kmod-a", "kmod_b",
+				}))
+				Expect(checkPod.Spec.Containers[0].VolumeMounts).To(Equal(checkPod.Spec.InitContainers[0].VolumeMounts))
+				return nil
+			})
+
+		err := cc.CreateCheckPod(ctx, "some-name", "some-namespace", &check, owner)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should record the attempt of a check replacing failed ones", func() {
+		check := CompatibilityCheck{Attempt: 2}
+
+		clnt.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, obj ctrlclient.Object, _ ...ctrlclient.CreateOption) error {
+				Expect(obj.GetAnnotations()).To(HaveKeyWithValue(constants.ResourceAttemptAnnotation, "2"))
+				Expect(cc.GetCheckPodAttempt(obj.(*v1.Pod))).To(Equal(int32(2)))
+				return nil
+			})
+
+		err := cc.CreateCheckPod(ctx, "some-name", "some-namespace", &check, owner)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetCheckPod", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		cc   CompatibilityChecker
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		cc = NewCompatibilityChecker(clnt, scheme)
+	})
+
+	ctx := context.Background()
+	owner := &v1beta2.PreflightValidation{ObjectMeta: metav1.ObjectMeta{Name: "some preflight", UID: "some-uid"}}
+	ml := ctrlclient.MatchingLabels{compatibilityCheckLabelKey: "some-name"}
+
+	It("should return an error if the pods could not be listed", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), ctrlclient.InNamespace("some-namespace"), ml).Return(errors.New("some error"))
+
+		_, err := cc.GetCheckPod(ctx, "some-name", "some-namespace", owner)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return the pod controlled by the owner", func() {
+		otherOwner := &v1beta2.PreflightValidation{ObjectMeta: metav1.ObjectMeta{Name: "other preflight", UID: "other-uid"}}
+		otherPod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+		Expect(controllerutil.SetControllerReference(otherOwner, &otherPod, scheme)).To(Succeed())
+		ownedPod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "owned"}}
+		Expect(controllerutil.SetControllerReference(owner, &ownedPod, scheme)).To(Succeed())
+
+		clnt.EXPECT().List(ctx, gomock.Any(), ctrlclient.InNamespace("some-namespace"), ml).DoAndReturn(
+			func(_ context.Context, list *v1.PodList, _ ...ctrlclient.ListOption) error {
+				list.Items = []v1.Pod{otherPod, ownedPod}
+				return nil
+			},
+		)
+
+		checkPod, err := cc.GetCheckPod(ctx, "some-name", "some-namespace", owner)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkPod.Name).To(Equal("owned"))
+	})
+
+	It("should ignore the pods being deleted", func() {
+		deletedPod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "deleted", DeletionTimestamp: &metav1.Time{}}}
+		Expect(controllerutil.SetControllerReference(owner, &deletedPod, scheme)).To(Succeed())
+
+		clnt.EXPECT().List(ctx, gomock.Any(), ctrlclient.InNamespace("some-namespace"), ml).DoAndReturn(
+			func(_ context.Context, list *v1.PodList, _ ...ctrlclient.ListOption) error {
+				list.Items = []v1.Pod{deletedPod}
+				return nil
+			},
+		)
+
+		checkPod, err := cc.GetCheckPod(ctx, "some-name", "some-namespace", owner)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkPod).To(BeNil())
+	})
+
+	It("should return nil if there is no pod", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), ctrlclient.InNamespace("some-namespace"), ml).Return(nil)
+
+		checkPod, err := cc.GetCheckPod(ctx, "some-name", "some-namespace", owner)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkPod).To(BeNil())
+	})
+})
+
+var _ = Describe("DeleteCheckPod", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		cc   CompatibilityChecker
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		cc = NewCompatibilityChecker(clnt, scheme)
+	})
+
+	ctx := context.Background()
+
+	It("should delete the pod", func() {
+		pod := v1.Pod{}
+		clnt.EXPECT().Delete(ctx, &pod).Return(nil)
+		Expect(cc.DeleteCheckPod(ctx, &pod)).To(Succeed())
+	})
+
+	It("should return an error if the pod could not be deleted", func() {
+		pod := v1.Pod{}
+		clnt.EXPECT().Delete(ctx, &pod).Return(errors.New("some error"))
+		Expect(cc.DeleteCheckPod(ctx, &pod)).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("GetCheckPodStatus", func() {
+	cc := NewCompatibilityChecker(nil, scheme)
+
+	checkerTerminated := func(exitCode int32) []v1.ContainerStatus {
+		return []v1.ContainerStatus{
+			{
+				Name:  checkerContainerName,
+				State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: exitCode}},
+			},
+		}
+	}
+
+	waiting := func(reason string) []v1.ContainerStatus {
+		return []v1.ContainerStatus{
+			{
+				Name:  modulesCopierContainerName,
+				State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: reason}},
+			},
+		}
+	}
+
+	DescribeTable("should return the status of the check", func(status v1.PodStatus, expected CompatibilityCheckStatus) {
+		Expect(cc.GetCheckPodStatus(&v1.Pod{Status: status})).To(Equal(expected))
+	},
+		Entry("succeeded", v1.PodStatus{Phase: v1.PodSucceeded}, CompatibilityCheckCompatible),
+		Entry("incompatible",
+			v1.PodStatus{Phase: v1.PodFailed, ContainerStatuses: checkerTerminated(incompatibleExitCode)},
+			CompatibilityCheckIncompatible,
+		),
+		Entry("skipped",
+			v1.PodStatus{Phase: v1.PodFailed, ContainerStatuses: checkerTerminated(skippedExitCode)},
+			CompatibilityCheckSkipped,
+		),
+		Entry("failed with another exit code",
+			v1.PodStatus{Phase: v1.PodFailed, ContainerStatuses: checkerTerminated(1)},
+			CompatibilityCheckFailed,
+		),
+		Entry("failed before the checker ran", v1.PodStatus{Phase: v1.PodFailed}, CompatibilityCheckFailed),
+		Entry("unknown", v1.PodStatus{Phase: v1.PodUnknown}, CompatibilityCheckFailed),
+		Entry("image cannot be pulled",
+			v1.PodStatus{Phase: v1.PodPending, InitContainerStatuses: waiting(imagePullBackOffReason)},
+			CompatibilityCheckFailed,
+		),
+		Entry("pending", v1.PodStatus{Phase: v1.PodPending, InitContainerStatuses: waiting("PodInitializing")},
+			CompatibilityCheckInProgress,
+		),
+		Entry("running", v1.PodStatus{Phase: v1.PodRunning}, CompatibilityCheckInProgress),
+	)
+})
+
+var _ = Describe("GetCheckPodFindings", func() {
+	cc := NewCompatibilityChecker(nil, scheme)
+
+	It("should return the termination message of the checker, line by line", func() {
+		pod := v1.Pod{
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: checkerContainerName,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								ExitCode: incompatibleExitCode,
+								Message:  "kmod-a: vermagic 5.14.0 does not match kernel 6.0.0\nkmod-a: symbol foo is not exported by kernel 6.0.0\n",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		Expect(cc.GetCheckPodFindings(&pod)).To(Equal([]string{
+			"kmod-a: vermagic 5.14.0 does not match kernel 6.0.0",
+			"kmod-a: symbol foo is not exported by kernel 6.0.0",
+		}))
+	})
+
+	It("should return nothing if the checker has not terminated", func() {
+		Expect(cc.GetCheckPodFindings(&v1.Pod{})).To(BeEmpty())
+	})
+
+	It("should return why the pod failed without a verdict of the checker", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "check-pod"},
+			Status: v1.PodStatus{
+				Reason:  "Evicted",
+				Message: "The node was low on resource: memory.",
+				InitContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  modulesCopierContainerName,
+						Image: "kmod-image",
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{ExitCode: 1, Message: "cp: cannot stat\n"},
+						},
+					},
+				},
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  checkerContainerName,
+						Image: "dtk-image",
+						State: v1.ContainerState{
+							Waiting: &v1.ContainerStateWaiting{Reason: errImagePullReason, Message: "manifest unknown"},
+						},
+					},
+				},
+			},
+		}
+
+		Expect(cc.GetCheckPodFindings(&pod)).To(Equal([]string{
+			"pod check-pod failed: Evicted: The node was low on resource: memory.",
+			"container modules exited with code 1: cp: cannot stat",
+			"container checker could not pull image dtk-image: manifest unknown",
+		}))
+	})
+
+	It("should return the exit code of a checker that crashed", func() {
+		pod := v1.Pod{
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  checkerContainerName,
+						State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+					},
+				},
+			},
+		}
+
+		Expect(cc.GetCheckPodFindings(&pod)).To(Equal([]string{"container checker exited with code 137 (OOMKilled)"}))
+	})
+})
+
+var _ = Describe("GetCheckPodAttempt", func() {
+	cc := NewCompatibilityChecker(nil, scheme)
+
+	DescribeTable("should return the attempt recorded on the pod", func(annotations map[string]string, expected int32) {
+		pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+		Expect(cc.GetCheckPodAttempt(&pod)).To(Equal(expected))
+	},
+		Entry("no annotation", nil, int32(0)),
+		Entry("valid annotation", map[string]string{constants.ResourceAttemptAnnotation: "3"}, int32(3)),
+		Entry("invalid annotation", map[string]string{constants.ResourceAttemptAnnotation: "three"}, int32(0)),
+	)
+})
@@ -3,6 +3,8 @@ package pod
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,8 +17,10 @@ type PullPodStatus string
 
 const (
 	PullImageFailed        PullPodStatus = "pullFailed"
+	PullImageNotFound      PullPodStatus = "pullNotFound"
 	PullImageSuccess       PullPodStatus = "pullSuccess"
 	PullImageInProcess     PullPodStatus = "pullInProcess"
+	PullImageInvalidLayout PullPodStatus = "invalidLayout"
 	PullImageUnexpectedErr PullPodStatus = "unexpectedError"
 
 	imagePullBackOffReason = "ImagePullBackOff"
@@ -29,17 +33,103 @@ const (
 
 	pullPodTypeOneTime  = "one-time-pull"
 	pullPodUntilSuccess = "until-success"
+	pullPodTypePrePull  = "pre-pull"
+
+	// invalidLayoutExitCode is the exit code of layoutValidationScript if the image does not have the expected layout.
+	invalidLayoutExitCode = 65
 )
 
+// imageNotFoundMessages are the errors with which the registries, as reported by the container runtimes, tell that
+// the image does not exist, as opposed to failing to serve it.
+var imageNotFoundMessages = []string{"manifest unknown", "name unknown"}
+
+// layoutValidationScript checks the content of a kmod image and writes what is wrong with it to the termination
+// message of the container. It is passed the directory of the modules, the kernel version, the firmware path and the
+// names of the kernel modules as positional parameters, so that they do not need to be quoted. The kernel modules are
+// only looked for, and their vermagic checked, if the image has the tools needed: modinfo, or sed and the decompressor
+// of compressed modules.
+const layoutValidationScript = `dir="$1/lib/modules/$2"
+kver="$2"
+firmware="$3"
+shift 3
+findings=""
+finding() {
+	findings="${findings}$1
+"
+}
+vermagic() {
+	if command -v modinfo >/dev/null 2>&1; then
+		v=$(modinfo -F vermagic "$1" 2>/dev/null)
+		if [ -n "$v" ]; then
+			echo "${v%% *}"
+			return
+		fi
+	fi
+	case "$1" in
+	*.ko.xz) decompress="xz -dc" ;;
+	*.ko.zst) decompress="zstd -qdc" ;;
+	*.ko.gz) decompress="gzip -dc" ;;
+	*) decompress="cat" ;;
+	esac
+	if command -v "${decompress%% *}" >/dev/null 2>&1 && command -v sed >/dev/null 2>&1; then
+		$decompress "$1" 2>/dev/null | tr '\0' '\n' | sed -n 's/^vermagic=\([^ ]*\).*/\1/p' | head -n 1
+	fi
+}
+if [ ! -d "$dir" ]; then
+	finding "directory $dir does not exist"
+else
+	[ -f "$dir/modules.dep" ] || finding "$dir/modules.dep does not exist; depmod must be run when building the image"
+	if command -v find >/dev/null 2>&1 && command -v tr >/dev/null 2>&1; then
+		for name in "$@"; do
+			underscores=$(echo "$name" | tr - _)
+			dashes=$(echo "$name" | tr _ -)
+			ko=$(find "$dir" -name "$underscores.ko*" -o -name "$dashes.ko*" | head -n 1)
+			if [ -z "$ko" ]; then
+				finding "kernel module $name was not found in $dir"
+				continue
+			fi
+			v=$(vermagic "$ko")
+			if [ -n "$v" ] && [ "${v%+}" != "${kver%+}" ]; then
+				finding "kernel module $ko was built for kernel $v"
+			fi
+		done
+	fi
+fi
+if [ -n "$firmware" ] && [ ! -e "$firmware" ]; then
+	finding "firmware path $firmware does not exist"
+fi
+if [ -n "$findings" ]; then
+	printf '%s' "$findings" > /dev/termination-log
+	exit 65
+fi
+`
+
+// ImageLayout describes the content expected in a kmod image.
+type ImageLayout struct {
+	// DirName is the root directory of the kernel modules in the image.
+	DirName string
+	// KernelVersion is the kernel for which the kernel modules are built.
+	KernelVersion string
+	// ModuleNames are the names of the kernel modules that the image must contain.
+	ModuleNames []string
+	// FirmwarePath is the path of the firmware that the image must contain, if any.
+	FirmwarePath string
+}
+
 //go:generate mockgen -source=imagepuller.go -package=pod -destination=mock_imagepuller.go
 
 type ImagePuller interface {
-	CreatePullPod(ctx context.Context, name, namespace, imageToPull string, oneTimePod bool,
+	CreatePullPod(ctx context.Context, name, namespace, imageToPull string, layout *ImageLayout, oneTimePod bool,
 		imageRepoSecret *v1.LocalObjectReference, pullPolicy v1.PullPolicy, owner metav1.Object) error
+	CreatePrePullPod(ctx context.Context, name, namespace, imageToPull, nodeName string,
+		imageRepoSecret *v1.LocalObjectReference, tolerations []v1.Toleration, owner metav1.Object) error
 	DeletePod(ctx context.Context, pod *v1.Pod) error
 	ListPullPods(ctx context.Context, name, namespace string) ([]v1.Pod, error)
+	ListPrePullPods(ctx context.Context, name, namespace string) ([]v1.Pod, error)
 	GetPullPodForImage(pods []v1.Pod, image string) *v1.Pod
 	GetPullPodImage(pod v1.Pod) string
+	GetPullPodImageDigest(pod v1.Pod) string
+	GetPullPodLayoutFindings(pod v1.Pod) []string
 	GetPullPodStatus(pod *v1.Pod) PullPodStatus
 }
 
@@ -55,48 +145,45 @@ func NewImagePuller(client client.Client, scheme *runtime.Scheme) ImagePuller {
 	}
 }
 
-func (ipi *imagePullerImpl) CreatePullPod(ctx context.Context, name, namespace, imageToPull string, oneTimePod bool,
-	imageRepoSecret *v1.LocalObjectReference, pullPolicy v1.PullPolicy, owner metav1.Object) error {
+// CreatePullPod creates a pod pulling the image to verify that it exists. If layout is set, the pod also validates
+// the content of the image, and fails with the invalid layout status if it is not as expected.
+func (ipi *imagePullerImpl) CreatePullPod(ctx context.Context, name, namespace, imageToPull string, layout *ImageLayout,
+	oneTimePod bool, imageRepoSecret *v1.LocalObjectReference, pullPolicy v1.PullPolicy, owner metav1.Object) error {
 
 	pullPodTypeLabelValue := pullPodUntilSuccess
 	if oneTimePod {
 		pullPodTypeLabelValue = pullPodTypeOneTime
 	}
 
-	imagePullSecrets := []v1.LocalObjectReference{}
-	if imageRepoSecret != nil {
-		imagePullSecrets = []v1.LocalObjectReference{*imageRepoSecret}
+	pullPod := newPullPod(name+"-pull-pod-", namespace, name, pullPodTypeLabelValue, imageToPull, imageRepoSecret, pullPolicy)
+	if layout != nil {
+		pullPod.Spec.Containers[0].Command = layoutValidationCommand(layout)
 	}
 
-	pullPod := v1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: name + "-pull-pod-",
-			Namespace:    namespace,
-			Labels: map[string]string{
-				imageOwnerLabelKey:  name,
-				PullPodTypeLabelKey: pullPodTypeLabelValue,
-			},
-		},
-		Spec: v1.PodSpec{
-			Containers: []v1.Container{
-				{
-					Name:            pullerContainerName,
-					Image:           imageToPull,
-					Command:         []string{"/bin/sh", "-c", "exit 0"},
-					ImagePullPolicy: pullPolicy,
-				},
-			},
-			RestartPolicy:    v1.RestartPolicyNever,
-			ImagePullSecrets: imagePullSecrets,
-		},
+	err := ctrl.SetControllerReference(owner, pullPod, ipi.scheme)
+	if err != nil {
+		return fmt.Errorf("failed to set owner for pullPod for image %s: %v", imageToPull, err)
 	}
 
-	err := ctrl.SetControllerReference(owner, &pullPod, ipi.scheme)
+	return ipi.client.Create(ctx, pullPod)
+}
+
+// CreatePrePullPod creates a pod pulling the image onto the node, so that it is already present there when the kernel
+// module is loaded.
+func (ipi *imagePullerImpl) CreatePrePullPod(ctx context.Context, name, namespace, imageToPull, nodeName string,
+	imageRepoSecret *v1.LocalObjectReference, tolerations []v1.Toleration, owner metav1.Object) error {
+
+	prePullPod := newPullPod(name+"-pre-pull-pod-", namespace, name, pullPodTypePrePull, imageToPull, imageRepoSecret,
+		v1.PullIfNotPresent)
+	prePullPod.Spec.NodeName = nodeName
+	prePullPod.Spec.Tolerations = tolerations
+
+	err := ctrl.SetControllerReference(owner, prePullPod, ipi.scheme)
 	if err != nil {
-		return fmt.Errorf("failed to set owner for pullPod for image %s: %v", imageToPull, err)
+		return fmt.Errorf("failed to set owner for the pre-pull pod for image %s on node %s: %v", imageToPull, nodeName, err)
 	}
 
-	return ipi.client.Create(ctx, &pullPod)
+	return ipi.client.Create(ctx, prePullPod)
 }
 
 func (ipi *imagePullerImpl) DeletePod(ctx context.Context, pod *v1.Pod) error {
@@ -117,6 +204,26 @@ func (ipi *imagePullerImpl) ListPullPods(ctx context.Context, name, namespace st
 		return nil, fmt.Errorf("could not list module image pods for module %s: %v", name, err)
 	}
 
+	pullPods := make([]v1.Pod, 0, len(pl.Items))
+	for _, p := range pl.Items {
+		if p.GetLabels()[PullPodTypeLabelKey] != pullPodTypePrePull {
+			pullPods = append(pullPods, p)
+		}
+	}
+
+	return pullPods, nil
+}
+
+func (ipi *imagePullerImpl) ListPrePullPods(ctx context.Context, name, namespace string) ([]v1.Pod, error) {
+
+	pl := v1.PodList{}
+
+	ml := client.MatchingLabels{imageOwnerLabelKey: name, PullPodTypeLabelKey: pullPodTypePrePull}
+
+	if err := ipi.client.List(ctx, &pl, client.InNamespace(namespace), ml); err != nil {
+		return nil, fmt.Errorf("could not list the pre-pull pods for module %s: %v", name, err)
+	}
+
 	return pl.Items, nil
 }
 
@@ -134,11 +241,44 @@ func (ipi *imagePullerImpl) GetPullPodImage(pod v1.Pod) string {
 	return pod.Spec.Containers[0].Image
 }
 
+// GetPullPodImageDigest returns the digest of the image pulled by the pod, or an empty string if the container
+// runtime did not report it.
+func (ipi *imagePullerImpl) GetPullPodImageDigest(pod v1.Pod) string {
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return ""
+	}
+
+	// the image ID has the form [docker-pullable://]registry/repo@sha256:...; runtimes that report the ID of the
+	// image configuration instead do not give us the digest of the manifest.
+	imageID := pod.Status.ContainerStatuses[0].ImageID
+	i := strings.LastIndex(imageID, "@")
+	if i == -1 {
+		return ""
+	}
+	return imageID[i+1:]
+}
+
+// GetPullPodLayoutFindings returns what is wrong with the content of the image pulled by the pod, if it failed the
+// validation of its layout.
+func (ipi *imagePullerImpl) GetPullPodLayoutFindings(pod v1.Pod) []string {
+	terminated := getPullerTerminatedState(&pod)
+	if terminated == nil || terminated.ExitCode != invalidLayoutExitCode {
+		return nil
+	}
+
+	return strings.FieldsFunc(terminated.Message, func(r rune) bool { return r == '\n' })
+}
+
 func (ipi *imagePullerImpl) GetPullPodStatus(pod *v1.Pod) PullPodStatus {
 	switch pod.Status.Phase {
 	case v1.PodSucceeded:
 		return PullImageSuccess
-	case v1.PodFailed, v1.PodUnknown:
+	case v1.PodFailed:
+		if terminated := getPullerTerminatedState(pod); terminated != nil && terminated.ExitCode == invalidLayoutExitCode {
+			return PullImageInvalidLayout
+		}
+		return PullImageUnexpectedErr
+	case v1.PodUnknown:
 		return PullImageUnexpectedErr
 	case v1.PodRunning:
 		return PullImageInProcess
@@ -159,10 +299,60 @@ func (ipi *imagePullerImpl) GetPullPodStatus(pod *v1.Pod) PullPodStatus {
 			return PullImageInProcess
 		}
 
-		if waitingReason := pod.Status.ContainerStatuses[0].State.Waiting.Reason; waitingReason == imagePullBackOffReason || waitingReason == errImagePullReason {
+		if waiting := pod.Status.ContainerStatuses[0].State.Waiting; waiting.Reason == imagePullBackOffReason || waiting.Reason == errImagePullReason {
+			if slices.ContainsFunc(imageNotFoundMessages, func(m string) bool { return strings.Contains(waiting.Message, m) }) {
+				return PullImageNotFound
+			}
 			return PullImageFailed
 		}
 	}
 
 	return PullImageUnexpectedErr
 }
+
+func newPullPod(generateName, namespace, owner, podType, imageToPull string, imageRepoSecret *v1.LocalObjectReference,
+	pullPolicy v1.PullPolicy) *v1.Pod {
+
+	imagePullSecrets := []v1.LocalObjectReference{}
+	if imageRepoSecret != nil {
+		imagePullSecrets = []v1.LocalObjectReference{*imageRepoSecret}
+	}
+
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: generateName,
+			Namespace:    namespace,
+			Labels: map[string]string{
+				imageOwnerLabelKey:  owner,
+				PullPodTypeLabelKey: podType,
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:            pullerContainerName,
+					Image:           imageToPull,
+					Command:         []string{"/bin/sh", "-c", "exit 0"},
+					ImagePullPolicy: pullPolicy,
+				},
+			},
+			RestartPolicy:    v1.RestartPolicyNever,
+			ImagePullSecrets: imagePullSecrets,
+		},
+	}
+}
+
+func getPullerTerminatedState(pod *v1.Pod) *v1.ContainerStateTerminated {
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return nil
+	}
+
+	return pod.Status.ContainerStatuses[0].State.Terminated
+}
+
+func layoutValidationCommand(layout *ImageLayout) []string {
+	command := []string{"/bin/sh", "-c", layoutValidationScript, "validate-layout", layout.DirName, layout.KernelVersion,
+		layout.FirmwarePath}
+
+	return append(command, layout.ModuleNames...)
+}
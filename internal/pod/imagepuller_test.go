@@ -59,6 +59,77 @@ var _ = Describe("ListPullPods", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(pullPods).To(BeNil())
 	})
+
+	It("should not return the pre-pull pods", func() {
+		hl := ctrlclient.HasLabels{PullPodTypeLabelKey}
+		ml := ctrlclient.MatchingLabels{imageOwnerLabelKey: testName}
+		pullPod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "pull-pod",
+				Labels: map[string]string{PullPodTypeLabelKey: pullPodTypeOneTime},
+			},
+		}
+		prePullPod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "pre-pull-pod",
+				Labels: map[string]string{PullPodTypeLabelKey: pullPodTypePrePull},
+			},
+		}
+
+		clnt.EXPECT().List(context.Background(), gomock.Any(), ctrlclient.InNamespace(testNamespace), hl, ml).DoAndReturn(
+			func(_ interface{}, podList *v1.PodList, _ ...interface{}) error {
+				podList.Items = []v1.Pod{pullPod, prePullPod}
+				return nil
+			},
+		)
+
+		pullPods, err := ip.ListPullPods(ctx, testName, testNamespace)
+		Expect(err).To(BeNil())
+		Expect(pullPods).To(Equal([]v1.Pod{pullPod}))
+	})
+})
+
+var _ = Describe("ListPrePullPods", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		ip   ImagePuller
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		ip = NewImagePuller(clnt, scheme)
+	})
+
+	ctx := context.Background()
+	testName := "some name"
+	testNamespace := "some namespace"
+
+	It("list succeeded", func() {
+		ml := ctrlclient.MatchingLabels{imageOwnerLabelKey: testName, PullPodTypeLabelKey: pullPodTypePrePull}
+
+		clnt.EXPECT().List(context.Background(), gomock.Any(), ctrlclient.InNamespace(testNamespace), ml).DoAndReturn(
+			func(_ interface{}, podList *v1.PodList, _ ...interface{}) error {
+				podList.Items = []v1.Pod{{}, {}}
+				return nil
+			},
+		)
+
+		prePullPods, err := ip.ListPrePullPods(ctx, testName, testNamespace)
+		Expect(err).To(BeNil())
+		Expect(prePullPods).To(HaveLen(2))
+	})
+
+	It("list failed", func() {
+		ml := ctrlclient.MatchingLabels{imageOwnerLabelKey: testName, PullPodTypeLabelKey: pullPodTypePrePull}
+
+		clnt.EXPECT().List(context.Background(), gomock.Any(), ctrlclient.InNamespace(testNamespace), ml).Return(fmt.Errorf("some error"))
+
+		prePullPods, err := ip.ListPrePullPods(ctx, testName, testNamespace)
+		Expect(err).To(HaveOccurred())
+		Expect(prePullPods).To(BeNil())
+	})
 })
 
 var _ = Describe("DeletePod", func() {
@@ -143,6 +214,35 @@ var _ = Describe("GetPullPodForImage", func() {
 	})
 })
 
+var _ = Describe("GetPullPodImageDigest", func() {
+	var (
+		ip ImagePuller
+	)
+
+	BeforeEach(func() {
+		ip = NewImagePuller(nil, nil)
+	})
+
+	DescribeTable("should return the digest from the image ID",
+		func(imageID, expected string) {
+			pod := v1.Pod{
+				Status: v1.PodStatus{
+					ContainerStatuses: []v1.ContainerStatus{{ImageID: imageID}},
+				},
+			}
+			Expect(ip.GetPullPodImageDigest(pod)).To(Equal(expected))
+		},
+		Entry("CRI-O", "example.com/repo@sha256:111", "sha256:111"),
+		Entry("docker-pullable", "docker-pullable://example.com/repo@sha256:111", "sha256:111"),
+		Entry("image configuration ID", "sha256:222", ""),
+		Entry("no image ID", "", ""),
+	)
+
+	It("should return an empty digest if there is no container status", func() {
+		Expect(ip.GetPullPodImageDigest(v1.Pod{})).To(BeEmpty())
+	})
+})
+
 var _ = Describe("CreatePullPod", func() {
 	var (
 		ctrl *gomock.Controller
@@ -200,7 +300,123 @@ var _ = Describe("CreatePullPod", func() {
 				}
 				return nil
 			})
-		err := ip.CreatePullPod(ctx, testName, testNamespace, testImage, false, &testRepoSecret, imagePullPolicy, &testMic)
+		err := ip.CreatePullPod(ctx, testName, testNamespace, testImage, nil, false, &testRepoSecret, imagePullPolicy, &testMic)
+		Expect(err).To(BeNil())
+	})
+
+	It("should validate the layout of the image if it is set", func() {
+		layout := ImageLayout{
+			DirName:       "/opt",
+			KernelVersion: "some kernel",
+			ModuleNames:   []string{"kmod-a", "kmod_b"},
+			FirmwarePath:  "/firmware",
+		}
+
+		clnt.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, obj ctrlclient.Object, opts ...ctrlclient.CreateOption) error {
+				pullPod := obj.(*v1.Pod)
+				Expect(pullPod.Spec.Containers[0].Command).To(Equal([]string{
+					"/bin/sh", "-c", layoutValidationScript, "validate-layout", "/opt", "some kernel", "/firmware",
+					"kmod-a", "kmod_b",
+				}))
+				return nil
+			})
+		err := ip.CreatePullPod(ctx, testName, testNamespace, testImage, &layout, true, &testRepoSecret, imagePullPolicy, &testMic)
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("GetPullPodLayoutFindings", func() {
+	ip := NewImagePuller(nil, scheme)
+
+	It("should return the findings of a pod that failed the layout validation", func() {
+		pod := v1.Pod{
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								ExitCode: invalidLayoutExitCode,
+								Message:  "finding 1\nfinding 2\n",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		Expect(ip.GetPullPodLayoutFindings(pod)).To(Equal([]string{"finding 1", "finding 2"}))
+	})
+
+	It("should return nothing if the pod did not fail the layout validation", func() {
+		pod := v1.Pod{
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{ExitCode: 1, Message: "some error"},
+						},
+					},
+				},
+			},
+		}
+
+		Expect(ip.GetPullPodLayoutFindings(pod)).To(BeEmpty())
+		Expect(ip.GetPullPodLayoutFindings(v1.Pod{})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("CreatePrePullPod", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		ip   ImagePuller
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		ip = NewImagePuller(clnt, scheme)
+	})
+
+	ctx := context.Background()
+	testMic := kmmv1beta1.ModuleImagesConfig{}
+	tolerations := []v1.Toleration{{Key: "some key", Operator: v1.TolerationOpExists}}
+
+	It("check the pod fields", func() {
+		expectedPod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "some name-pre-pull-pod-",
+				Namespace:    "some namespace",
+				Labels: map[string]string{
+					imageOwnerLabelKey:  "some name",
+					PullPodTypeLabelKey: pullPodTypePrePull,
+				},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{
+						Name:            pullerContainerName,
+						Image:           "some image",
+						Command:         []string{"/bin/sh", "-c", "exit 0"},
+						ImagePullPolicy: v1.PullIfNotPresent,
+					},
+				},
+				NodeName:         "some node",
+				RestartPolicy:    v1.RestartPolicyNever,
+				ImagePullSecrets: []v1.LocalObjectReference{},
+				Tolerations:      tolerations,
+			},
+		}
+
+		clnt.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, prePullPod *v1.Pod, _ ...ctrlclient.CreateOption) error {
+				prePullPod.OwnerReferences = nil
+				Expect(prePullPod).To(Equal(&expectedPod))
+				return nil
+			})
+
+		err := ip.CreatePrePullPod(ctx, "some name", "some namespace", "some image", "some node", nil, tolerations, &testMic)
 		Expect(err).To(BeNil())
 	})
 })
@@ -235,6 +451,18 @@ var _ = Describe("GetPullPodStatus", func() {
 		res = ip.GetPullPodStatus(&testPod)
 		Expect(res).To(Equal(PullImageUnexpectedErr))
 
+		By("phase PodFailed after the layout validation failed")
+		invalidLayoutPod := testPod.DeepCopy()
+		invalidLayoutPod.Status.ContainerStatuses = []v1.ContainerStatus{
+			{
+				State: v1.ContainerState{
+					Terminated: &v1.ContainerStateTerminated{ExitCode: invalidLayoutExitCode},
+				},
+			},
+		}
+		res = ip.GetPullPodStatus(invalidLayoutPod)
+		Expect(res).To(Equal(PullImageInvalidLayout))
+
 		By("phase PodUnknown")
 		testPod.Status.Phase = v1.PodUnknown
 		res = ip.GetPullPodStatus(&testPod)
@@ -281,5 +509,17 @@ var _ = Describe("GetPullPodStatus", func() {
 		testPod.Status.ContainerStatuses[0].State.Waiting.Reason = errImagePullReason
 		res = ip.GetPullPodStatus(&testPod)
 		Expect(res).To(Equal(PullImageFailed))
+
+		By("the registry reports that the image does not exist")
+		testPod.Status.ContainerStatuses[0].State.Waiting.Message = "initializing source docker://example.org/some/image:some-tag: " +
+			"reading manifest some-tag in example.org/some/image: manifest unknown"
+		res = ip.GetPullPodStatus(&testPod)
+		Expect(res).To(Equal(PullImageNotFound))
+
+		By("the registry could not be reached")
+		testPod.Status.ContainerStatuses[0].State.Waiting.Message = "pinging container registry example.org: " +
+			"Get \"https://example.org/v2/\": dial tcp: lookup example.org: no such host"
+		res = ip.GetPullPodStatus(&testPod)
+		Expect(res).To(Equal(PullImageFailed))
 	})
 })
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: compatibilitychecker.go
+//
+// Generated by this command:
+//
+//	mockgen -source=compatibilitychecker.go -package=pod -destination=mock_compatibilitychecker.go
+//
+// Package pod is a generated GoMock package.
+package pod
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	v10 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MockCompatibilityChecker is a mock of CompatibilityChecker interface.
+type MockCompatibilityChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockCompatibilityCheckerMockRecorder
+}
+
+// MockCompatibilityCheckerMockRecorder is the mock recorder for MockCompatibilityChecker.
+type MockCompatibilityCheckerMockRecorder struct {
+	mock *MockCompatibilityChecker
+}
+
+// NewMockCompatibilityChecker creates a new mock instance.
+func NewMockCompatibilityChecker(ctrl *gomock.Controller) *MockCompatibilityChecker {
+	mock := &MockCompatibilityChecker{ctrl: ctrl}
+	mock.recorder = &MockCompatibilityCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCompatibilityChecker) EXPECT() *MockCompatibilityCheckerMockRecorder {
+	return m.recorder
+}
+
+// CreateCheckPod mocks base method.
+func (m *MockCompatibilityChecker) CreateCheckPod(ctx context.Context, name, namespace string, check *CompatibilityCheck, owner v10.Object) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCheckPod", ctx, name, namespace, check, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCheckPod indicates an expected call of CreateCheckPod.
+func (mr *MockCompatibilityCheckerMockRecorder) CreateCheckPod(ctx, name, namespace, check, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCheckPod", reflect.TypeOf((*MockCompatibilityChecker)(nil).CreateCheckPod), ctx, name, namespace, check, owner)
+}
+
+// DeleteCheckPod mocks base method.
+func (m *MockCompatibilityChecker) DeleteCheckPod(ctx context.Context, pod *v1.Pod) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCheckPod", ctx, pod)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCheckPod indicates an expected call of DeleteCheckPod.
+func (mr *MockCompatibilityCheckerMockRecorder) DeleteCheckPod(ctx, pod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCheckPod", reflect.TypeOf((*MockCompatibilityChecker)(nil).DeleteCheckPod), ctx, pod)
+}
+
+// GetCheckPod mocks base method.
+func (m *MockCompatibilityChecker) GetCheckPod(ctx context.Context, name, namespace string, owner v10.Object) (*v1.Pod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckPod", ctx, name, namespace, owner)
+	ret0, _ := ret[0].(*v1.Pod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCheckPod indicates an expected call of GetCheckPod.
+func (mr *MockCompatibilityCheckerMockRecorder) GetCheckPod(ctx, name, namespace, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckPod", reflect.TypeOf((*MockCompatibilityChecker)(nil).GetCheckPod), ctx, name, namespace, owner)
+}
+
+// GetCheckPodAttempt mocks base method.
+func (m *MockCompatibilityChecker) GetCheckPodAttempt(pod *v1.Pod) int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckPodAttempt", pod)
+	ret0, _ := ret[0].(int32)
+	return ret0
+}
+
+// GetCheckPodAttempt indicates an expected call of GetCheckPodAttempt.
+func (mr *MockCompatibilityCheckerMockRecorder) GetCheckPodAttempt(pod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckPodAttempt", reflect.TypeOf((*MockCompatibilityChecker)(nil).GetCheckPodAttempt), pod)
+}
+
+// GetCheckPodFindings mocks base method.
+func (m *MockCompatibilityChecker) GetCheckPodFindings(pod *v1.Pod) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckPodFindings", pod)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetCheckPodFindings indicates an expected call of GetCheckPodFindings.
+func (mr *MockCompatibilityCheckerMockRecorder) GetCheckPodFindings(pod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckPodFindings", reflect.TypeOf((*MockCompatibilityChecker)(nil).GetCheckPodFindings), pod)
+}
+
+// GetCheckPodStatus mocks base method.
+func (m *MockCompatibilityChecker) GetCheckPodStatus(pod *v1.Pod) CompatibilityCheckStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckPodStatus", pod)
+	ret0, _ := ret[0].(CompatibilityCheckStatus)
+	return ret0
+}
+
+// GetCheckPodStatus indicates an expected call of GetCheckPodStatus.
+func (mr *MockCompatibilityCheckerMockRecorder) GetCheckPodStatus(pod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckPodStatus", reflect.TypeOf((*MockCompatibilityChecker)(nil).GetCheckPodStatus), pod)
+}
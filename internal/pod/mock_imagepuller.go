@@ -40,18 +40,32 @@ func (m *MockImagePuller) EXPECT() *MockImagePullerMockRecorder {
 	return m.recorder
 }
 
+// CreatePrePullPod mocks base method.
+func (m *MockImagePuller) CreatePrePullPod(ctx context.Context, name, namespace, imageToPull, nodeName string, imageRepoSecret *v1.LocalObjectReference, tolerations []v1.Toleration, owner v10.Object) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePrePullPod", ctx, name, namespace, imageToPull, nodeName, imageRepoSecret, tolerations, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePrePullPod indicates an expected call of CreatePrePullPod.
+func (mr *MockImagePullerMockRecorder) CreatePrePullPod(ctx, name, namespace, imageToPull, nodeName, imageRepoSecret, tolerations, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePrePullPod", reflect.TypeOf((*MockImagePuller)(nil).CreatePrePullPod), ctx, name, namespace, imageToPull, nodeName, imageRepoSecret, tolerations, owner)
+}
+
 // CreatePullPod mocks base method.
-func (m *MockImagePuller) CreatePullPod(ctx context.Context, name, namespace, imageToPull string, oneTimePod bool, imageRepoSecret *v1.LocalObjectReference, pullPolicy v1.PullPolicy, owner v10.Object) error {
+func (m *MockImagePuller) CreatePullPod(ctx context.Context, name, namespace, imageToPull string, layout *ImageLayout, oneTimePod bool, imageRepoSecret *v1.LocalObjectReference, pullPolicy v1.PullPolicy, owner v10.Object) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePullPod", ctx, name, namespace, imageToPull, oneTimePod, imageRepoSecret, pullPolicy, owner)
+	ret := m.ctrl.Call(m, "CreatePullPod", ctx, name, namespace, imageToPull, layout, oneTimePod, imageRepoSecret, pullPolicy, owner)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreatePullPod indicates an expected call of CreatePullPod.
-func (mr *MockImagePullerMockRecorder) CreatePullPod(ctx, name, namespace, imageToPull, oneTimePod, imageRepoSecret, pullPolicy, owner any) *gomock.Call {
+func (mr *MockImagePullerMockRecorder) CreatePullPod(ctx, name, namespace, imageToPull, layout, oneTimePod, imageRepoSecret, pullPolicy, owner any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePullPod", reflect.TypeOf((*MockImagePuller)(nil).CreatePullPod), ctx, name, namespace, imageToPull, oneTimePod, imageRepoSecret, pullPolicy, owner)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePullPod", reflect.TypeOf((*MockImagePuller)(nil).CreatePullPod), ctx, name, namespace, imageToPull, layout, oneTimePod, imageRepoSecret, pullPolicy, owner)
 }
 
 // DeletePod mocks base method.
@@ -96,6 +110,34 @@ func (mr *MockImagePullerMockRecorder) GetPullPodImage(pod any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPullPodImage", reflect.TypeOf((*MockImagePuller)(nil).GetPullPodImage), pod)
 }
 
+// GetPullPodImageDigest mocks base method.
+func (m *MockImagePuller) GetPullPodImageDigest(pod v1.Pod) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPullPodImageDigest", pod)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetPullPodImageDigest indicates an expected call of GetPullPodImageDigest.
+func (mr *MockImagePullerMockRecorder) GetPullPodImageDigest(pod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPullPodImageDigest", reflect.TypeOf((*MockImagePuller)(nil).GetPullPodImageDigest), pod)
+}
+
+// GetPullPodLayoutFindings mocks base method.
+func (m *MockImagePuller) GetPullPodLayoutFindings(pod v1.Pod) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPullPodLayoutFindings", pod)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetPullPodLayoutFindings indicates an expected call of GetPullPodLayoutFindings.
+func (mr *MockImagePullerMockRecorder) GetPullPodLayoutFindings(pod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPullPodLayoutFindings", reflect.TypeOf((*MockImagePuller)(nil).GetPullPodLayoutFindings), pod)
+}
+
 // GetPullPodStatus mocks base method.
 func (m *MockImagePuller) GetPullPodStatus(pod *v1.Pod) PullPodStatus {
 	m.ctrl.T.Helper()
@@ -110,6 +152,21 @@ func (mr *MockImagePullerMockRecorder) GetPullPodStatus(pod any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPullPodStatus", reflect.TypeOf((*MockImagePuller)(nil).GetPullPodStatus), pod)
 }
 
+// ListPrePullPods mocks base method.
+func (m *MockImagePuller) ListPrePullPods(ctx context.Context, name, namespace string) ([]v1.Pod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPrePullPods", ctx, name, namespace)
+	ret0, _ := ret[0].([]v1.Pod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPrePullPods indicates an expected call of ListPrePullPods.
+func (mr *MockImagePullerMockRecorder) ListPrePullPods(ctx, name, namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPrePullPods", reflect.TypeOf((*MockImagePuller)(nil).ListPrePullPods), ctx, name, namespace)
+}
+
 // ListPullPods mocks base method.
 func (m *MockImagePuller) ListPullPods(ctx context.Context, name, namespace string) ([]v1.Pod, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +66,18 @@ func (mr *MockPreflightAPIMockRecorder) GetModuleStatus(pv, namespace, name any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleStatus", reflect.TypeOf((*MockPreflightAPI)(nil).GetModuleStatus), pv, namespace, name)
 }
 
+// SetModuleCompatibilityStatus mocks base method.
+func (m *MockPreflightAPI) SetModuleCompatibilityStatus(pv *v1beta2.PreflightValidation, namespace, name, status, reason string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetModuleCompatibilityStatus", pv, namespace, name, status, reason)
+}
+
+// SetModuleCompatibilityStatus indicates an expected call of SetModuleCompatibilityStatus.
+func (mr *MockPreflightAPIMockRecorder) SetModuleCompatibilityStatus(pv, namespace, name, status, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetModuleCompatibilityStatus", reflect.TypeOf((*MockPreflightAPI)(nil).SetModuleCompatibilityStatus), pv, namespace, name, status, reason)
+}
+
 // SetModuleStatus mocks base method.
 func (m *MockPreflightAPI) SetModuleStatus(pv *v1beta2.PreflightValidation, namespace, name, status, reason string) {
 	m.ctrl.T.Helper()
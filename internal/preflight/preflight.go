@@ -1,7 +1,14 @@
 package preflight
 
 import (
+	"strings"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta2"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mic"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/pod"
+	v1 "k8s.io/api/core/v1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -12,6 +19,7 @@ const VerificationStatusReasonVerified = "Verification successful (%s), this Mod
 
 type PreflightAPI interface {
 	SetModuleStatus(pv *v1beta2.PreflightValidation, namespace, name, status, reason string)
+	SetModuleCompatibilityStatus(pv *v1beta2.PreflightValidation, namespace, name, status, reason string)
 	GetModuleStatus(pv *v1beta2.PreflightValidation, namespace, name string) string
 	AllModulesVerified(pv *v1beta2.PreflightValidation) bool
 }
@@ -24,8 +32,17 @@ type preflight struct {
 }
 
 func (p *preflight) SetModuleStatus(pv *v1beta2.PreflightValidation, namespace, name, status, reason string) {
-	stage := v1beta2.VerificationStageImage
-	if status == v1beta2.VerificationSuccess || status == v1beta2.VerificationFailure {
+	p.setModuleStatus(pv, namespace, name, v1beta2.VerificationStageImage, status, reason)
+}
+
+// SetModuleCompatibilityStatus sets the status of a Module whose image exists, and whose kernel modules are being
+// checked against the kernel.
+func (p *preflight) SetModuleCompatibilityStatus(pv *v1beta2.PreflightValidation, namespace, name, status, reason string) {
+	p.setModuleStatus(pv, namespace, name, v1beta2.VerificationStageCompatibility, status, reason)
+}
+
+func (p *preflight) setModuleStatus(pv *v1beta2.PreflightValidation, namespace, name, stage, status, reason string) {
+	if VerificationDone(status) {
 		stage = v1beta2.VerificationStageDone
 	}
 	newStatus := v1beta2.PreflightValidationModuleStatus{
@@ -59,9 +76,55 @@ func (p *preflight) GetModuleStatus(pv *v1beta2.PreflightValidation, namespace,
 
 func (p *preflight) AllModulesVerified(pv *v1beta2.PreflightValidation) bool {
 	for _, moduleStatus := range pv.Status.Modules {
-		if !(moduleStatus.VerificationStatus == v1beta2.VerificationSuccess || moduleStatus.VerificationStatus == v1beta2.VerificationFailure) {
+		if !VerificationDone(moduleStatus.VerificationStatus) {
 			return false
 		}
 	}
 	return true
 }
+
+// VerificationDone returns true if the verification of a Module has ended with status, whatever its result.
+func VerificationDone(status string) bool {
+	return status == v1beta2.VerificationSuccess || status == v1beta2.VerificationFailure ||
+		status == v1beta2.VerificationUnverified
+}
+
+// ImageVerificationStatus returns the verification status and reason of a Module's preflight, given the state of its
+// image in the preflight MIC.
+func ImageVerificationStatus(micAPI mic.MIC, micObj *kmmv1beta1.ModuleImagesConfig, mld *api.ModuleLoaderData) (string, string) {
+	switch micAPI.GetImageState(micObj, mld.ContainerImage) {
+	case kmmv1beta1.ImageExists:
+		return v1beta2.VerificationSuccess, "verified image exists"
+	case kmmv1beta1.ImageDoesNotExist:
+		reason := "verified image does not exist"
+		if mld.Build != nil || mld.Sign != nil {
+			reason += " and build/sign failed"
+		}
+		return v1beta2.VerificationFailure, reason
+	case kmmv1beta1.ImageInvalidLayout:
+		return v1beta2.VerificationFailure, "verified image does not contain the expected kernel modules: " +
+			strings.Join(micAPI.GetImageLayoutFindings(micObj, mld.ContainerImage), "; ")
+	}
+
+	return v1beta2.VerificationInProgress, "verification is not finished yet"
+}
+
+// CompatibilityVerificationStatus returns the verification status and reason of a Module's preflight, given the state
+// of the pod checking its kernel modules against the kernel.
+func CompatibilityVerificationStatus(checkerAPI pod.CompatibilityChecker, checkPod *v1.Pod, kernelVersion string) (string, string) {
+	switch checkerAPI.GetCheckPodStatus(checkPod) {
+	case pod.CompatibilityCheckCompatible:
+		return v1beta2.VerificationSuccess, "verified image exists and its kernel modules are compatible with kernel " + kernelVersion
+	case pod.CompatibilityCheckIncompatible:
+		return v1beta2.VerificationFailure, "the kernel modules are not compatible with kernel " + kernelVersion + ": " +
+			strings.Join(checkerAPI.GetCheckPodFindings(checkPod), "; ")
+	case pod.CompatibilityCheckSkipped:
+		return v1beta2.VerificationUnverified, "verified image exists, but the compatibility of its kernel modules could " +
+			"not be checked: " + strings.Join(checkerAPI.GetCheckPodFindings(checkPod), "; ")
+	case pod.CompatibilityCheckFailed:
+		return v1beta2.VerificationFailure, "the compatibility check of the kernel modules failed, see pod " + checkPod.Name +
+			": " + strings.Join(checkerAPI.GetCheckPodFindings(checkPod), "; ")
+	}
+
+	return v1beta2.VerificationInProgress, "verifying the compatibility of the kernel modules with kernel " + kernelVersion
+}
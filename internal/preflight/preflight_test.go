@@ -3,7 +3,14 @@ package preflight
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta2"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/mic"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/pod"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("SetModuleStatus", func() {
@@ -44,6 +51,43 @@ var _ = Describe("SetModuleStatus", func() {
 		Expect(pv.Status.Modules[1].VerificationStatus).To(Equal("new status"))
 		Expect(pv.Status.Modules[1].StatusReason).To(Equal("new reason"))
 	})
+
+	It("should set the stage from the status", func() {
+		preflightAPI.SetModuleStatus(pv, "test-namespace", "test-name", v1beta2.VerificationInProgress, "some reason")
+		Expect(pv.Status.Modules[0].VerificationStage).To(Equal(v1beta2.VerificationStageImage))
+
+		preflightAPI.SetModuleStatus(pv, "test-namespace", "test-name", v1beta2.VerificationFailure, "some reason")
+		Expect(pv.Status.Modules[0].VerificationStage).To(Equal(v1beta2.VerificationStageDone))
+	})
+})
+
+var _ = Describe("SetModuleCompatibilityStatus", func() {
+	var (
+		preflightAPI PreflightAPI
+		pv           *v1beta2.PreflightValidation
+	)
+
+	BeforeEach(func() {
+		preflightAPI = NewPreflightAPI()
+		pv = &v1beta2.PreflightValidation{}
+	})
+
+	It("should set the compatibility stage while the check is in progress", func() {
+		preflightAPI.SetModuleCompatibilityStatus(pv, "test-namespace", "test-name", v1beta2.VerificationInProgress, "some reason")
+		Expect(pv.Status.Modules).To(HaveLen(1))
+		Expect(pv.Status.Modules[0].VerificationStage).To(Equal(v1beta2.VerificationStageCompatibility))
+		Expect(pv.Status.Modules[0].StatusReason).To(Equal("some reason"))
+	})
+
+	It("should set the done stage once the check is finished", func() {
+		preflightAPI.SetModuleCompatibilityStatus(pv, "test-namespace", "test-name", v1beta2.VerificationSuccess, "some reason")
+		Expect(pv.Status.Modules[0].VerificationStage).To(Equal(v1beta2.VerificationStageDone))
+	})
+
+	It("should set the done stage if the compatibility could not be checked", func() {
+		preflightAPI.SetModuleCompatibilityStatus(pv, "test-namespace", "test-name", v1beta2.VerificationUnverified, "some reason")
+		Expect(pv.Status.Modules[0].VerificationStage).To(Equal(v1beta2.VerificationStageDone))
+	})
 })
 
 var _ = Describe("GetModuleStatus", func() {
@@ -112,9 +156,97 @@ var _ = Describe("AllModulesVerified", func() {
 
 	})
 
+	It("should return true if the compatibility of a module could not be checked", func() {
+		pv.Status.Modules[0].VerificationStatus = v1beta2.VerificationSuccess
+		pv.Status.Modules[1].VerificationStatus = v1beta2.VerificationUnverified
+		Expect(preflightAPI.AllModulesVerified(pv)).To(BeTrue())
+	})
+
 	It("should return false if any module is not verified", func() {
 		pv.Status.Modules[0].VerificationStatus = v1beta2.VerificationInProgress
 		pv.Status.Modules[1].VerificationStatus = v1beta2.VerificationFailure
 		Expect(preflightAPI.AllModulesVerified(pv)).To(BeFalse())
 	})
 })
+
+var _ = Describe("ImageVerificationStatus", func() {
+	const image = "example.org/repo/image:tag"
+
+	var (
+		ctrl       *gomock.Controller
+		mockMICAPI *mic.MockMIC
+		micObj     *kmmv1beta1.ModuleImagesConfig
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockMICAPI = mic.NewMockMIC(ctrl)
+		micObj = &kmmv1beta1.ModuleImagesConfig{}
+	})
+
+	DescribeTable("should return the verification status matching the image state",
+		func(state kmmv1beta1.ImageState, buildOrSign bool, expectedStatus, expectedReason string) {
+			mld := &api.ModuleLoaderData{ContainerImage: image}
+			if buildOrSign {
+				mld.Build = &kmmv1beta1.Build{}
+			}
+
+			mockMICAPI.EXPECT().GetImageState(micObj, image).Return(state)
+			if state == kmmv1beta1.ImageInvalidLayout {
+				mockMICAPI.EXPECT().GetImageLayoutFindings(micObj, image).Return([]string{"finding-1", "finding-2"})
+			}
+
+			status, reason := ImageVerificationStatus(mockMICAPI, micObj, mld)
+			Expect(status).To(Equal(expectedStatus))
+			Expect(reason).To(Equal(expectedReason))
+		},
+		Entry("image exists", kmmv1beta1.ImageExists, false,
+			v1beta2.VerificationSuccess, "verified image exists"),
+		Entry("image does not exist", kmmv1beta1.ImageDoesNotExist, false,
+			v1beta2.VerificationFailure, "verified image does not exist"),
+		Entry("image does not exist and build failed", kmmv1beta1.ImageDoesNotExist, true,
+			v1beta2.VerificationFailure, "verified image does not exist and build/sign failed"),
+		Entry("image has an invalid layout", kmmv1beta1.ImageInvalidLayout, false,
+			v1beta2.VerificationFailure, "verified image does not contain the expected kernel modules: finding-1; finding-2"),
+		Entry("image state is not known yet", kmmv1beta1.ImageState(""), false,
+			v1beta2.VerificationInProgress, "verification is not finished yet"),
+	)
+})
+
+var _ = Describe("CompatibilityVerificationStatus", func() {
+	var (
+		ctrl           *gomock.Controller
+		mockCheckerAPI *pod.MockCompatibilityChecker
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockCheckerAPI = pod.NewMockCompatibilityChecker(ctrl)
+	})
+
+	checkPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "check-pod"}}
+
+	DescribeTable("should return the verification status matching the state of the check",
+		func(checkStatus pod.CompatibilityCheckStatus, expectedStatus, expectedReason string) {
+			mockCheckerAPI.EXPECT().GetCheckPodStatus(checkPod).Return(checkStatus)
+			if checkStatus == pod.CompatibilityCheckIncompatible || checkStatus == pod.CompatibilityCheckSkipped ||
+				checkStatus == pod.CompatibilityCheckFailed {
+				mockCheckerAPI.EXPECT().GetCheckPodFindings(checkPod).Return([]string{"finding-1", "finding-2"})
+			}
+
+			status, reason := CompatibilityVerificationStatus(mockCheckerAPI, checkPod, "6.0.0")
+			Expect(status).To(Equal(expectedStatus))
+			Expect(reason).To(Equal(expectedReason))
+		},
+		Entry("compatible", pod.CompatibilityCheckCompatible,
+			v1beta2.VerificationSuccess, "verified image exists and its kernel modules are compatible with kernel 6.0.0"),
+		Entry("incompatible", pod.CompatibilityCheckIncompatible,
+			v1beta2.VerificationFailure, "the kernel modules are not compatible with kernel 6.0.0: finding-1; finding-2"),
+		Entry("skipped", pod.CompatibilityCheckSkipped,
+			v1beta2.VerificationUnverified, "verified image exists, but the compatibility of its kernel modules could not be checked: finding-1; finding-2"),
+		Entry("failed", pod.CompatibilityCheckFailed,
+			v1beta2.VerificationFailure, "the compatibility check of the kernel modules failed, see pod check-pod: finding-1; finding-2"),
+		Entry("in progress", pod.CompatibilityCheckInProgress,
+			v1beta2.VerificationInProgress, "verifying the compatibility of the kernel modules with kernel 6.0.0"),
+	)
+})
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: registry.go
+//
+// Generated by this command:
+//
+//	mockgen -source=registry.go -package=registry -destination=mock_registry.go
+//
+// Package registry is a generated GoMock package.
+package registry
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	gomock "go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+)
+
+// MockRegistry is a mock of Registry interface.
+type MockRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistryMockRecorder
+}
+
+// MockRegistryMockRecorder is the mock recorder for MockRegistry.
+type MockRegistryMockRecorder struct {
+	mock *MockRegistry
+}
+
+// NewMockRegistry creates a new mock instance.
+func NewMockRegistry(ctrl *gomock.Controller) *MockRegistry {
+	mock := &MockRegistry{ctrl: ctrl}
+	mock.recorder = &MockRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegistry) EXPECT() *MockRegistryMockRecorder {
+	return m.recorder
+}
+
+// DeleteImage mocks base method.
+func (m *MockRegistry) DeleteImage(ctx context.Context, image string, tlsOptions *v1beta1.TLSOptions, namespace string, pullSecret *v1.LocalObjectReference, inUseImages []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImage", ctx, image, tlsOptions, namespace, pullSecret, inUseImages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteImage indicates an expected call of DeleteImage.
+func (mr *MockRegistryMockRecorder) DeleteImage(ctx, image, tlsOptions, namespace, pullSecret, inUseImages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImage", reflect.TypeOf((*MockRegistry)(nil).DeleteImage), ctx, image, tlsOptions, namespace, pullSecret, inUseImages)
+}
+
+// GetDigest mocks base method.
+func (m *MockRegistry) GetDigest(ctx context.Context, image string, tlsOptions *v1beta1.TLSOptions, namespace string, pullSecret *v1.LocalObjectReference) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDigest", ctx, image, tlsOptions, namespace, pullSecret)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDigest indicates an expected call of GetDigest.
+func (mr *MockRegistryMockRecorder) GetDigest(ctx, image, tlsOptions, namespace, pullSecret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDigest", reflect.TypeOf((*MockRegistry)(nil).GetDigest), ctx, image, tlsOptions, namespace, pullSecret)
+}
@@ -0,0 +1,374 @@
+package registry
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/authn/kubernetes"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	configv1 "github.com/openshift/api/config/v1"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clusterImageConfigName is the name of the cluster-scoped image.config.openshift.io object.
+const clusterImageConfigName = "cluster"
+
+// ErrImageShared is returned by DeleteImage when the manifest of the image is referenced by an image still in use.
+var ErrImageShared = errors.New("the manifest of the image is referenced by an image still in use")
+
+//go:generate mockgen -source=registry.go -package=registry -destination=mock_registry.go
+
+type Registry interface {
+	GetDigest(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, namespace string,
+		pullSecret *v1.LocalObjectReference) (string, error)
+	DeleteImage(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, namespace string,
+		pullSecret *v1.LocalObjectReference, inUseImages []string) error
+}
+
+type registry struct {
+	client    client.Client
+	transport http.RoundTripper
+}
+
+func NewRegistry(client client.Client) Registry {
+	return &registry{
+		client:    client,
+		transport: remote.DefaultTransport,
+	}
+}
+
+type digestCacheKey struct{}
+
+type digestCacheEntry struct {
+	image      string
+	tlsOptions kmmv1beta1.TLSOptions
+	namespace  string
+	pullSecret string
+}
+
+// WithDigestCache returns a context in which GetDigest resolves each image once, so that a registry is only
+// contacted once per image while handling the same request.
+func WithDigestCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, digestCacheKey{}, make(map[digestCacheEntry]string))
+}
+
+// GetDigest resolves image to the digest of its manifest.
+// Images that are already referenced by digest are returned as-is without contacting the registry; this is also how
+// the mirrors of ImageDigestMirrorSets and ImageContentSourcePolicies are used, as they only apply to digests.
+// If the registry of image cannot be reached, the mirrors of the cluster's ImageTagMirrorSets are tried in order.
+// The insecure and blocked registries of the cluster's image configuration are honored, if present.
+// If ctx was returned by WithDigestCache, the digest is only resolved the first time.
+func (r *registry) GetDigest(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, namespace string,
+	pullSecret *v1.LocalObjectReference) (string, error) {
+
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("could not parse image %s: %v", image, err)
+	}
+
+	if digest, ok := ref.(name.Digest); ok {
+		return digest.DigestStr(), nil
+	}
+
+	cache, _ := ctx.Value(digestCacheKey{}).(map[digestCacheEntry]string)
+	entry := digestCacheEntry{image: image, namespace: namespace}
+	if tlsOptions != nil {
+		entry.tlsOptions = *tlsOptions
+	}
+	if pullSecret != nil {
+		entry.pullSecret = pullSecret.Name
+	}
+	if digest, ok := cache[entry]; ok {
+		return digest, nil
+	}
+
+	digest, err := r.headDigest(ctx, image, tlsOptions, namespace, pullSecret)
+	if err != nil {
+		mirrors, mirrorsErr := r.getTagMirrors(ctx, ref)
+		if mirrorsErr != nil {
+			return "", errors.Join(err, mirrorsErr)
+		}
+		for _, mirror := range mirrors {
+			if digest, err = r.headDigest(ctx, mirror, tlsOptions, namespace, pullSecret); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if cache != nil {
+		cache[entry] = digest
+	}
+
+	return digest, nil
+}
+
+func (r *registry) headDigest(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, namespace string,
+	pullSecret *v1.LocalObjectReference) (string, error) {
+
+	ref, opts, err := r.getRemoteOptions(ctx, image, tlsOptions, namespace, pullSecret)
+	if err != nil {
+		return "", err
+	}
+
+	desc, err := remote.Head(ref, opts...)
+	if err != nil {
+		return "", fmt.Errorf("could not get the manifest of image %s: %v", image, err)
+	}
+
+	return desc.Digest.String(), nil
+}
+
+// getTagMirrors returns the images that mirror ref according to the cluster's ImageTagMirrorSets, in the order in
+// which they are defined.
+func (r *registry) getTagMirrors(ctx context.Context, ref name.Reference) ([]string, error) {
+	itmsList := configv1.ImageTagMirrorSetList{}
+
+	if err := r.client.List(ctx, &itmsList); err != nil {
+		// not running on OpenShift
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not list ImageTagMirrorSets: %v", err)
+	}
+
+	repo := ref.Context().Name()
+	host := ref.Context().RegistryStr()
+	mirrors := make([]string, 0)
+
+	for _, itms := range itmsList.Items {
+		for _, itm := range itms.Spec.ImageTagMirrors {
+			var path string
+			if suffix, ok := strings.CutPrefix(itm.Source, "*"); ok {
+				if !strings.HasSuffix(host, suffix) {
+					continue
+				}
+				path = strings.TrimPrefix(repo, host)
+			} else if rest, ok := strings.CutPrefix(repo, itm.Source); ok && (rest == "" || strings.HasPrefix(rest, "/")) {
+				path = rest
+			} else {
+				continue
+			}
+			for _, mirror := range itm.Mirrors {
+				mirrors = append(mirrors, string(mirror)+path+":"+ref.Identifier())
+			}
+		}
+	}
+
+	return mirrors, nil
+}
+
+// DeleteImage deletes image from its registry.
+// The tag of image is deleted if the registry supports it, so that the other tags referencing the same manifest are
+// kept. Otherwise the manifest is deleted by digest, which removes all its tags; ErrImageShared is returned instead if
+// one of inUseImages resolves to the same manifest. Images that do not exist anymore are ignored.
+func (r *registry) DeleteImage(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, namespace string,
+	pullSecret *v1.LocalObjectReference, inUseImages []string) error {
+
+	ref, opts, err := r.getRemoteOptions(ctx, image, tlsOptions, namespace, pullSecret)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := ref.(name.Digest); !ok {
+		err = remote.Delete(ref, opts...)
+		if err == nil || isNotFound(err) {
+			return nil
+		}
+		if !isUnsupported(err) {
+			return fmt.Errorf("could not delete image %s: %v", image, err)
+		}
+
+		desc, err := remote.Head(ref, opts...)
+		if err != nil {
+			if isNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("could not get the manifest of image %s: %v", image, err)
+		}
+		ref = ref.Context().Digest(desc.Digest.String())
+	}
+
+	shared, err := r.isManifestInUse(ref.(name.Digest), image, inUseImages, opts)
+	if err != nil {
+		return err
+	}
+	if shared {
+		return ErrImageShared
+	}
+
+	if err = remote.Delete(ref, opts...); err != nil && !isNotFound(err) {
+		return fmt.Errorf("could not delete image %s: %v", image, err)
+	}
+
+	return nil
+}
+
+// isManifestInUse returns true if one of inUseImages, other than image, resolves to the manifest digest.
+// Only the images of the same repository are resolved, since deleting a manifest does not affect other repositories.
+func (r *registry) isManifestInUse(digest name.Digest, image string, inUseImages []string, opts []remote.Option) (bool, error) {
+	for _, inUseImage := range inUseImages {
+		if inUseImage == image {
+			continue
+		}
+
+		inUseRef, err := name.ParseReference(inUseImage)
+		if err != nil || inUseRef.Context().Name() != digest.Context().Name() {
+			continue
+		}
+
+		if d, ok := inUseRef.(name.Digest); ok {
+			if d.DigestStr() == digest.DigestStr() {
+				return true, nil
+			}
+			continue
+		}
+
+		desc, err := remote.Head(inUseRef, opts...)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return false, fmt.Errorf("could not get the manifest of image %s: %v", inUseImage, err)
+		}
+		if desc.Digest.String() == digest.DigestStr() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// getRemoteOptions returns the reference to image and the options to access its registry.
+// The insecure and blocked registries of the cluster's image configuration are honored, if present.
+func (r *registry) getRemoteOptions(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, namespace string,
+	pullSecret *v1.LocalObjectReference) (name.Reference, []remote.Option, error) {
+
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse image %s: %v", image, err)
+	}
+
+	registrySources, err := r.getClusterRegistrySources(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get the cluster's image configuration: %v", err)
+	}
+
+	registryHost := ref.Context().RegistryStr()
+
+	if matchesAnyRegistry(registryHost, registrySources.BlockedRegistries) {
+		return nil, nil, fmt.Errorf("registry %s is blocked by the cluster's image configuration", registryHost)
+	}
+
+	nameOpts := make([]name.Option, 0)
+
+	if (tlsOptions != nil && tlsOptions.Insecure) || matchesAnyRegistry(registryHost, registrySources.InsecureRegistries) {
+		nameOpts = append(nameOpts, name.Insecure)
+	}
+
+	if ref, err = name.ParseReference(image, nameOpts...); err != nil {
+		return nil, nil, fmt.Errorf("could not parse image %s: %v", image, err)
+	}
+
+	keychain, err := r.getKeychain(ctx, namespace, pullSecret)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get the keychain for image %s: %v", image, err)
+	}
+
+	transport := r.transport
+
+	if tlsOptions != nil && tlsOptions.InsecureSkipTLSVerify {
+		if t, ok := transport.(*http.Transport); ok {
+			t = t.Clone()
+			t.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+			transport = t
+		}
+	}
+
+	opts := []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(keychain),
+		remote.WithTransport(transport),
+	}
+
+	return ref, opts, nil
+}
+
+func (r *registry) getClusterRegistrySources(ctx context.Context) (*configv1.RegistrySources, error) {
+	imageConfig := configv1.Image{}
+
+	if err := r.client.Get(ctx, types.NamespacedName{Name: clusterImageConfigName}, &imageConfig); err != nil {
+		// not running on OpenShift, or the cluster does not define any image configuration
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return &configv1.RegistrySources{}, nil
+		}
+		return nil, err
+	}
+
+	return &imageConfig.Spec.RegistrySources, nil
+}
+
+func (r *registry) getKeychain(ctx context.Context, namespace string, pullSecret *v1.LocalObjectReference) (authn.Keychain, error) {
+	if pullSecret == nil || pullSecret.Name == "" {
+		return authn.NewMultiKeychain(), nil
+	}
+
+	secret := v1.Secret{}
+	nsn := types.NamespacedName{Name: pullSecret.Name, Namespace: namespace}
+
+	if err := r.client.Get(ctx, nsn, &secret); err != nil {
+		return nil, fmt.Errorf("could not get pull secret %s: %v", nsn, err)
+	}
+
+	return kubernetes.NewFromPullSecrets(ctx, []v1.Secret{secret})
+}
+
+// isNotFound returns true if the registry reported that the manifest does not exist.
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
+
+// isUnsupported returns true if the registry refused the operation because it does not support it, as most registries
+// do for deleting tags.
+func isUnsupported(err error) bool {
+	var terr *transport.Error
+	if !errors.As(err, &terr) {
+		return false
+	}
+
+	if terr.StatusCode == http.StatusMethodNotAllowed {
+		return true
+	}
+
+	return slices.ContainsFunc(terr.Errors, func(d transport.Diagnostic) bool {
+		return d.Code == transport.UnsupportedErrorCode
+	})
+}
+
+// matchesAnyRegistry returns true if host matches one of the registries.
+// Registries may be prefixed with a "*." wildcard to match any subdomain, as allowed by the cluster's image
+// configuration.
+func matchesAnyRegistry(host string, registries []string) bool {
+	return slices.ContainsFunc(registries, func(r string) bool {
+		if suffix, ok := strings.CutPrefix(r, "*"); ok {
+			return strings.HasSuffix(host, suffix)
+		}
+		return host == r
+	})
+}
@@ -0,0 +1,317 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const manifestDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+var _ = Describe("GetDigest", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockClient *client.MockClient
+		server     *httptest.Server
+		reg        Registry
+		host       string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = client.NewMockClient(ctrl)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/v2/":
+				w.WriteHeader(http.StatusOK)
+			case r.Method == http.MethodHead && r.URL.Path == "/v2/some/image/manifests/some-tag":
+				w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+				w.Header().Set("Content-Length", "100")
+				w.Header().Set("Docker-Content-Digest", manifestDigest)
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		DeferCleanup(server.Close)
+		host = strings.TrimPrefix(server.URL, "http://")
+		reg = NewRegistry(mockClient)
+	})
+
+	ctx := context.Background()
+	imageConfigNSN := types.NamespacedName{Name: clusterImageConfigName}
+
+	It("should return the digest of images referenced by digest without contacting the registry", func() {
+		digest, err := reg.GetDigest(ctx, "example.org/some/image@"+manifestDigest, nil, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal(manifestDigest))
+	})
+
+	It("should return an error if the image cannot be parsed", func() {
+		_, err := reg.GetDigest(ctx, "NOT A VALID IMAGE", nil, "", nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the cluster image configuration cannot be fetched", func() {
+		mockClient.EXPECT().Get(ctx, imageConfigNSN, gomock.Any()).Return(errors.New("some error"))
+		mockClient.EXPECT().List(ctx, &configv1.ImageTagMirrorSetList{}).Return(nil)
+
+		_, err := reg.GetDigest(ctx, host+"/some/image:some-tag", nil, "", nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the registry is blocked", func() {
+		mockClient.EXPECT().Get(ctx, imageConfigNSN, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, imageConfig *configv1.Image, _ ...ctrlclient.GetOption) error {
+				imageConfig.Spec.RegistrySources.BlockedRegistries = []string{host}
+				return nil
+			},
+		)
+		mockClient.EXPECT().List(ctx, &configv1.ImageTagMirrorSetList{}).Return(nil)
+
+		_, err := reg.GetDigest(ctx, host+"/some/image:some-tag", nil, "", nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should resolve the digest from a mirror of the cluster's ImageTagMirrorSets", func() {
+		mockClient.EXPECT().Get(ctx, imageConfigNSN, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, imageConfig *configv1.Image, _ ...ctrlclient.GetOption) error {
+				imageConfig.Spec.RegistrySources.BlockedRegistries = []string{"registry.example.org"}
+				return nil
+			},
+		).Times(3)
+		mockClient.EXPECT().List(ctx, &configv1.ImageTagMirrorSetList{}).DoAndReturn(
+			func(_ interface{}, itmsList *configv1.ImageTagMirrorSetList, _ ...ctrlclient.ListOption) error {
+				itmsList.Items = []configv1.ImageTagMirrorSet{
+					{
+						Spec: configv1.ImageTagMirrorSetSpec{
+							ImageTagMirrors: []configv1.ImageTagMirrors{
+								{Source: "registry.example.org/other", Mirrors: []configv1.ImageMirror{"unused.example.org"}},
+								{Source: "*.example.org", Mirrors: []configv1.ImageMirror{configv1.ImageMirror(host + "/missing")}},
+								{Source: "registry.example.org/some", Mirrors: []configv1.ImageMirror{configv1.ImageMirror(host + "/some")}},
+							},
+						},
+					},
+				}
+				return nil
+			},
+		)
+
+		digest, err := reg.GetDigest(ctx, "registry.example.org/some/image:some-tag", &kmmv1beta1.TLSOptions{Insecure: true}, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal(manifestDigest))
+	})
+
+	It("should return an error if the ImageTagMirrorSets cannot be listed", func() {
+		mockClient.EXPECT().Get(ctx, imageConfigNSN, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "cluster"))
+		mockClient.EXPECT().List(ctx, &configv1.ImageTagMirrorSetList{}).Return(errors.New("some error"))
+
+		_, err := reg.GetDigest(ctx, host+"/some/other-image:some-tag", &kmmv1beta1.TLSOptions{Insecure: true}, "", nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the pull secret cannot be fetched", func() {
+		gomock.InOrder(
+			mockClient.EXPECT().Get(ctx, imageConfigNSN, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "cluster")),
+			mockClient.EXPECT().Get(ctx, types.NamespacedName{Name: "pull-secret", Namespace: "some-namespace"}, gomock.Any()).
+				Return(errors.New("some error")),
+			mockClient.EXPECT().List(ctx, &configv1.ImageTagMirrorSetList{}).Return(nil),
+		)
+
+		_, err := reg.GetDigest(ctx, host+"/some/image:some-tag", &kmmv1beta1.TLSOptions{Insecure: true}, "some-namespace",
+			&v1.LocalObjectReference{Name: "pull-secret"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should resolve the digest from an insecure registry", func() {
+		mockClient.EXPECT().Get(ctx, imageConfigNSN, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "cluster"))
+
+		digest, err := reg.GetDigest(ctx, host+"/some/image:some-tag", &kmmv1beta1.TLSOptions{Insecure: true}, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal(manifestDigest))
+	})
+
+	It("should resolve the digest from a registry marked insecure in the cluster image configuration", func() {
+		mockClient.EXPECT().Get(ctx, imageConfigNSN, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, imageConfig *configv1.Image, _ ...ctrlclient.GetOption) error {
+				imageConfig.Spec.RegistrySources.InsecureRegistries = []string{host}
+				return nil
+			},
+		)
+
+		digest, err := reg.GetDigest(ctx, host+"/some/image:some-tag", nil, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal(manifestDigest))
+	})
+
+	It("should return an error if the image does not exist", func() {
+		mockClient.EXPECT().Get(ctx, imageConfigNSN, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "cluster"))
+		mockClient.EXPECT().List(ctx, &configv1.ImageTagMirrorSetList{}).Return(nil)
+
+		_, err := reg.GetDigest(ctx, host+"/some/other-image:some-tag", &kmmv1beta1.TLSOptions{Insecure: true}, "", nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should only contact the registry once per image when the digests are cached", func() {
+		cacheCtx := WithDigestCache(ctx)
+		tlsOptions := &kmmv1beta1.TLSOptions{Insecure: true}
+		mockClient.EXPECT().Get(cacheCtx, imageConfigNSN, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "cluster"))
+
+		for i := 0; i < 2; i++ {
+			digest, err := reg.GetDigest(cacheCtx, host+"/some/image:some-tag", tlsOptions, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(digest).To(Equal(manifestDigest))
+		}
+	})
+})
+
+var _ = Describe("DeleteImage", func() {
+	const otherDigest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+
+	var (
+		ctrl              *gomock.Controller
+		mockClient        *client.MockClient
+		server            *httptest.Server
+		reg               Registry
+		host              string
+		deleted           []string
+		tagDeleteAllowed  bool
+		manifestsByTag    map[string]string
+		insecureTLSOption = &kmmv1beta1.TLSOptions{Insecure: true}
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = client.NewMockClient(ctrl)
+		deleted = nil
+		tagDeleteAllowed = false
+		manifestsByTag = map[string]string{
+			"some-tag":   manifestDigest,
+			"shared-tag": manifestDigest,
+			"other-tag":  otherDigest,
+		}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v2/" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			reference, ok := strings.CutPrefix(r.URL.Path, "/v2/some/image/manifests/")
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			digest, isTag := manifestsByTag[reference]
+
+			switch {
+			case r.Method == http.MethodHead && isTag:
+				w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+				w.Header().Set("Content-Length", "100")
+				w.Header().Set("Docker-Content-Digest", digest)
+				w.WriteHeader(http.StatusOK)
+			case r.Method == http.MethodDelete && isTag && tagDeleteAllowed:
+				deleted = append(deleted, r.URL.Path)
+				w.WriteHeader(http.StatusAccepted)
+			case r.Method == http.MethodDelete && (reference == manifestDigest || reference == otherDigest):
+				deleted = append(deleted, r.URL.Path)
+				w.WriteHeader(http.StatusAccepted)
+			case r.Method == http.MethodDelete && isTag:
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors":[{"code":"UNSUPPORTED","message":"The operation is unsupported."}]}`))
+			case r.Method == http.MethodDelete:
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		DeferCleanup(server.Close)
+		host = strings.TrimPrefix(server.URL, "http://")
+		reg = NewRegistry(mockClient)
+	})
+
+	ctx := context.Background()
+	imageConfigNSN := types.NamespacedName{Name: clusterImageConfigName}
+
+	BeforeEach(func() {
+		mockClient.EXPECT().Get(ctx, imageConfigNSN, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "cluster"))
+	})
+
+	It("should only delete the tag if the registry supports it", func() {
+		tagDeleteAllowed = true
+
+		err := reg.DeleteImage(ctx, host+"/some/image:some-tag", insecureTLSOption, "", nil, []string{host + "/some/image:shared-tag"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(Equal([]string{"/v2/some/image/manifests/some-tag"}))
+	})
+
+	It("should delete the manifest of the tag by digest if the registry does not support deleting tags", func() {
+		inUseImages := []string{
+			host + "/some/image:some-tag",
+			host + "/some/image:other-tag",
+			host + "/some/other-image:shared-tag",
+		}
+
+		err := reg.DeleteImage(ctx, host+"/some/image:some-tag", insecureTLSOption, "", nil, inUseImages)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(Equal([]string{"/v2/some/image/manifests/" + manifestDigest}))
+	})
+
+	It("should not delete the manifest if an image in use resolves to the same digest", func() {
+		err := reg.DeleteImage(ctx, host+"/some/image:some-tag", insecureTLSOption, "", nil, []string{host + "/some/image:shared-tag"})
+		Expect(err).To(MatchError(ErrImageShared))
+		Expect(deleted).To(BeEmpty())
+	})
+
+	It("should not delete images referenced by digest if an image in use has the same digest", func() {
+		err := reg.DeleteImage(ctx, host+"/some/image@"+manifestDigest, insecureTLSOption, "", nil, []string{host + "/some/image:shared-tag"})
+		Expect(err).To(MatchError(ErrImageShared))
+		Expect(deleted).To(BeEmpty())
+	})
+
+	It("should delete images referenced by digest", func() {
+		err := reg.DeleteImage(ctx, host+"/some/image@"+manifestDigest, insecureTLSOption, "", nil, []string{host + "/some/image:other-tag"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(HaveLen(1))
+	})
+
+	It("should ignore the images that do not exist", func() {
+		err := reg.DeleteImage(ctx, host+"/some/other-image:some-tag", insecureTLSOption, "", nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(BeEmpty())
+	})
+
+	It("should return an error if the registry does not allow deleting the image", func() {
+		err := reg.DeleteImage(ctx, host+"/some/image@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+			insecureTLSOption, "", nil, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("matchesAnyRegistry", func() {
+	DescribeTable("should match registries",
+		func(host string, registries []string, expected bool) {
+			Expect(matchesAnyRegistry(host, registries)).To(Equal(expected))
+		},
+		Entry("no registries", "example.org", nil, false),
+		Entry("exact match", "example.org", []string{"other.org", "example.org"}, true),
+		Entry("no match", "example.org", []string{"other.org"}, false),
+		Entry("wildcard match", "registry.example.org", []string{"*.example.org"}, true),
+		Entry("wildcard does not match the domain itself", "example.org", []string{"*.example.org"}, false),
+	)
+})
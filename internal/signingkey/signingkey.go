@@ -0,0 +1,104 @@
+package signingkey
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+//go:generate mockgen -source=signingkey.go -package=signingkey -destination=mock_signingkey.go
+
+// CertificateDataKey is the key of the DER certificate in the ConfigMap publishing it.
+const CertificateDataKey = "cert.der"
+
+// RotationAnnotation records, on the Secret holding a key pair, the rotation of the SigningKey it was generated for.
+const RotationAnnotation = "kmm.node.kubernetes.io/signing-key-rotation"
+
+// moduleSigningExtKeyUsage restricts the certificate to the signing of kernel modules on the kernels that support it.
+var moduleSigningExtKeyUsage = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 2312, 16, 1, 2}
+
+// SecretName returns the name of the Secret holding the key pair of the SigningKey.
+func SecretName(signingKeyName string) string {
+	return signingKeyName + "-signing-key"
+}
+
+// CertificateConfigMapName returns the name of the ConfigMap publishing the certificate of the SigningKey.
+func CertificateConfigMapName(signingKeyName string) string {
+	return signingKeyName + "-certificate"
+}
+
+// KeyPair holds a PEM private key and its DER self-signed certificate.
+type KeyPair struct {
+	PrivateKey  []byte
+	Certificate []byte
+}
+
+type Generator interface {
+	Generate(commonName string, keySize int, notBefore time.Time, validity time.Duration) (*KeyPair, error)
+}
+
+type generator struct{}
+
+func NewGenerator() Generator {
+	return &generator{}
+}
+
+// Generate creates an RSA key and a self-signed certificate suitable for the signing of kernel modules.
+func (g *generator) Generate(commonName string, keySize int, notBefore time.Time, validity time.Duration) (*KeyPair, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the RSA key: %v", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the serial number: %v", err)
+	}
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the public key: %v", err)
+	}
+	subjectKeyID := sha1.Sum(publicKey)
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(validity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		UnknownExtKeyUsage:    []asn1.ObjectIdentifier{moduleSigningExtKeyUsage},
+		BasicConstraintsValid: true,
+		SubjectKeyId:          subjectKeyID[:],
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the certificate: %v", err)
+	}
+
+	privateKey, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the private key: %v", err)
+	}
+
+	return &KeyPair{
+		PrivateKey:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKey}),
+		Certificate: cert,
+	}, nil
+}
+
+// Fingerprint returns the hexadecimal SHA-256 fingerprint of a DER certificate.
+func Fingerprint(certificate []byte) string {
+	sum := sha256.Sum256(certificate)
+	return hex.EncodeToString(sum[:])
+}
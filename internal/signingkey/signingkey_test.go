@@ -0,0 +1,57 @@
+package signingkey
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	It("should generate a key pair suitable for module signing", func() {
+		notBefore := time.Now().Truncate(time.Second)
+
+		keyPair, err := NewGenerator().Generate("kmm-test", 2048, notBefore, 24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+
+		block, _ := pem.Decode(keyPair.PrivateKey)
+		Expect(block).NotTo(BeNil())
+		Expect(block.Type).To(Equal("PRIVATE KEY"))
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+
+		cert, err := x509.ParseCertificate(keyPair.Certificate)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Subject.CommonName).To(Equal("kmm-test"))
+		Expect(cert.NotBefore).To(BeTemporally("==", notBefore))
+		Expect(cert.NotAfter).To(BeTemporally("==", notBefore.Add(24*time.Hour)))
+		Expect(cert.IsCA).To(BeFalse())
+		Expect(cert.KeyUsage).To(Equal(x509.KeyUsageDigitalSignature))
+		Expect(cert.ExtKeyUsage).To(ConsistOf(x509.ExtKeyUsageCodeSigning))
+		Expect(cert.UnknownExtKeyUsage).To(HaveLen(1))
+		Expect(cert.UnknownExtKeyUsage[0].Equal(moduleSigningExtKeyUsage)).To(BeTrue())
+		Expect(cert.SubjectKeyId).NotTo(BeEmpty())
+		Expect(cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature)).To(Succeed())
+		Expect(key.(*rsa.PrivateKey).PublicKey.Equal(cert.PublicKey)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Fingerprint", func() {
+	It("should return the hexadecimal SHA-256 of the certificate", func() {
+		sum := sha256.Sum256([]byte("certificate"))
+
+		Expect(Fingerprint([]byte("certificate"))).To(Equal(hex.EncodeToString(sum[:])))
+	})
+})
+
+var _ = Describe("names", func() {
+	It("should derive the names of the Secret and ConfigMap from the SigningKey", func() {
+		Expect(SecretName("sk")).To(Equal("sk-signing-key"))
+		Expect(CertificateConfigMapName("sk")).To(Equal("sk-certificate"))
+	})
+})
@@ -81,15 +81,15 @@ func (m *MockManagedClusterModuleStatusUpdater) EXPECT() *MockManagedClusterModu
 }
 
 // ManagedClusterModuleUpdateStatus mocks base method.
-func (m *MockManagedClusterModuleStatusUpdater) ManagedClusterModuleUpdateStatus(ctx context.Context, mcm *v1beta1.ManagedClusterModule, ownedManifestWorks []v11.ManifestWork) error {
+func (m *MockManagedClusterModuleStatusUpdater) ManagedClusterModuleUpdateStatus(ctx context.Context, mcm *v1beta1.ManagedClusterModule, clusters []v1beta1.ClusterModuleStatus, rollout *v1beta1.RolloutStatus, ownedManifestWorks []v11.ManifestWork) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ManagedClusterModuleUpdateStatus", ctx, mcm, ownedManifestWorks)
+	ret := m.ctrl.Call(m, "ManagedClusterModuleUpdateStatus", ctx, mcm, clusters, rollout, ownedManifestWorks)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ManagedClusterModuleUpdateStatus indicates an expected call of ManagedClusterModuleUpdateStatus.
-func (mr *MockManagedClusterModuleStatusUpdaterMockRecorder) ManagedClusterModuleUpdateStatus(ctx, mcm, ownedManifestWorks any) *gomock.Call {
+func (mr *MockManagedClusterModuleStatusUpdaterMockRecorder) ManagedClusterModuleUpdateStatus(ctx, mcm, clusters, rollout, ownedManifestWorks any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedClusterModuleUpdateStatus", reflect.TypeOf((*MockManagedClusterModuleStatusUpdater)(nil).ManagedClusterModuleUpdateStatus), ctx, mcm, ownedManifestWorks)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedClusterModuleUpdateStatus", reflect.TypeOf((*MockManagedClusterModuleStatusUpdater)(nil).ManagedClusterModuleUpdateStatus), ctx, mcm, clusters, rollout, ownedManifestWorks)
 }
@@ -2,15 +2,20 @@ package statusupdater
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	hubv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/manifestwork"
 )
 
 //go:generate mockgen -source=statusupdater.go -package=statusupdater -destination=mock_statusupdater.go
@@ -24,7 +29,7 @@ type ModuleStatusUpdater interface {
 
 type ManagedClusterModuleStatusUpdater interface {
 	ManagedClusterModuleUpdateStatus(ctx context.Context, mcm *hubv1beta1.ManagedClusterModule,
-		ownedManifestWorks []workv1.ManifestWork) error
+		clusters []hubv1beta1.ClusterModuleStatus, rollout *hubv1beta1.RolloutStatus, ownedManifestWorks []workv1.ManifestWork) error
 }
 
 type moduleStatusUpdater struct {
@@ -72,13 +77,22 @@ func (m *moduleStatusUpdater) ModuleUpdateStatus(ctx context.Context,
 	return m.client.Status().Patch(ctx, mod, client.MergeFrom(unmodifiedMod))
 }
 
+// ManagedClusterModuleUpdateStatus updates the counters of the ManagedClusterModule, the progress of its rollout and
+// the state of the Module on each selected managed cluster, whose ManifestWork details are filled in from
+// ownedManifestWorks, and the conditions aggregating them.
 func (m *managedClusterModuleStatusUpdater) ManagedClusterModuleUpdateStatus(ctx context.Context,
 	mcm *hubv1beta1.ManagedClusterModule,
+	clusters []hubv1beta1.ClusterModuleStatus,
+	rollout *hubv1beta1.RolloutStatus,
 	ownedManifestWorks []workv1.ManifestWork) error {
 
 	var numApplied int32
 	var numDegraded int32
-	for _, mw := range ownedManifestWorks {
+	manifestWorks := make(map[string]*workv1.ManifestWork, len(ownedManifestWorks))
+	for i, mw := range ownedManifestWorks {
+		// ManifestWorks are created in the namespace of their cluster
+		manifestWorks[mw.Namespace] = &ownedManifestWorks[i]
+
 		for _, condition := range mw.Status.Conditions {
 			if condition.Status != metav1.ConditionTrue {
 				continue
@@ -99,5 +113,107 @@ func (m *managedClusterModuleStatusUpdater) ManagedClusterModuleUpdateStatus(ctx
 	mcm.Status.NumberApplied = numApplied
 	mcm.Status.NumberDegraded = numDegraded
 
+	clusters = slices.Clone(clusters)
+	slices.SortFunc(clusters, func(a, b hubv1beta1.ClusterModuleStatus) int {
+		return strings.Compare(a.ClusterName, b.ClusterName)
+	})
+
+	var notReady, notApplied, notAvailable, degraded []string
+	for i := range clusters {
+		cluster := &clusters[i]
+		mw, ok := manifestWorks[cluster.ClusterName]
+		if ok {
+			cluster.ManifestWorkApplied = meta.IsStatusConditionTrue(mw.Status.Conditions, workv1.WorkApplied)
+			cluster.ManifestWorkAvailable = meta.IsStatusConditionTrue(mw.Status.Conditions, workv1.WorkAvailable)
+			cluster.ModuleLoader = manifestwork.ModuleLoaderStatus(mw)
+			if meta.IsStatusConditionTrue(mw.Status.Conditions, workv1.WorkDegraded) {
+				degraded = append(degraded, cluster.ClusterName)
+			}
+		}
+
+		if !cluster.ImagesReady {
+			notReady = append(notReady, cluster.ClusterName)
+		}
+		if !cluster.ManifestWorkApplied {
+			notApplied = append(notApplied, cluster.ClusterName)
+		}
+		if !ok || !manifestwork.IsModuleAvailable(mw, mcm.Spec.ModuleSpec.ModuleLoader != nil) {
+			notAvailable = append(notAvailable, cluster.ClusterName)
+		}
+	}
+	if len(clusters) == 0 {
+		clusters = nil
+	}
+	mcm.Status.Clusters = clusters
+	mcm.Status.Rollout = rollout
+
+	setClustersCondition(mcm, hubv1beta1.ManagedClusterModuleImagesReady, notReady, false,
+		"ImagesReady", "ImagesNotReady", "kmod images are not ready for clusters")
+	setClustersCondition(mcm, hubv1beta1.ManagedClusterModuleApplied, notApplied, false,
+		"ManifestWorksApplied", "ManifestWorksNotApplied", "ManifestWorks are not applied on clusters")
+	setClustersCondition(mcm, hubv1beta1.ManagedClusterModuleAvailable, notAvailable, false,
+		"ModuleAvailable", "ModuleNotAvailable", "the kernel module is not loaded on all the nodes of clusters")
+	setClustersCondition(mcm, hubv1beta1.ManagedClusterModuleDegraded, degraded, true,
+		"ManifestWorksNotDegraded", "ManifestWorksDegraded", "ManifestWorks are degraded on clusters")
+	setRolloutPausedCondition(mcm, rollout)
+
 	return m.client.Status().Patch(ctx, mcm, client.MergeFrom(unmodifiedMCM))
 }
+
+// setRolloutPausedCondition sets the RolloutPaused condition of the ManagedClusterModule, or removes it if there is no
+// rollout.
+func setRolloutPausedCondition(mcm *hubv1beta1.ManagedClusterModule, rollout *hubv1beta1.RolloutStatus) {
+	if rollout == nil {
+		meta.RemoveStatusCondition(&mcm.Status.Conditions, hubv1beta1.ManagedClusterModuleRolloutPaused)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               hubv1beta1.ManagedClusterModuleRolloutPaused,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: mcm.Generation,
+		Reason:             "RolloutNotPaused",
+	}
+
+	if rollout.Stage < rollout.Stages {
+		switch {
+		case mcm.Spec.Rollout != nil && mcm.Spec.Rollout.Paused:
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "RolloutPaused"
+			condition.Message = rollout.Message
+		case rollout.PausedRevision != "":
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "ManifestWorksDegraded"
+			condition.Message = rollout.Message
+		}
+	}
+
+	meta.SetStatusCondition(&mcm.Status.Conditions, condition)
+}
+
+// setClustersCondition sets a condition of the ManagedClusterModule according to the clusters for which it does not
+// hold; the condition is true if there are none, unless it is a negative condition such as Degraded.
+func setClustersCondition(mcm *hubv1beta1.ManagedClusterModule, conditionType string, clusters []string, negative bool,
+	reason, clustersReason, clustersMessage string) {
+
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: mcm.Generation,
+		Reason:             reason,
+	}
+	if len(clusters) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = clustersReason
+		condition.Message = fmt.Sprintf("%s: %s", clustersMessage, strings.Join(clusters, ", "))
+	}
+	if negative {
+		if condition.Status == metav1.ConditionTrue {
+			condition.Status = metav1.ConditionFalse
+		} else {
+			condition.Status = metav1.ConditionTrue
+		}
+	}
+
+	meta.SetStatusCondition(&mcm.Status.Conditions, condition)
+}
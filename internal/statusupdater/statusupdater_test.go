@@ -9,6 +9,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
 
@@ -153,12 +154,123 @@ var _ = Describe("ManagedClusterModule status update", func() {
 			Items: []workv1.ManifestWork{mw, degradedMW},
 		}
 
-		res := su.ManagedClusterModuleUpdateStatus(context.Background(), mcm, manifestWorkList.Items)
+		clusters := []hubv1beta1.ClusterModuleStatus{
+			{ClusterName: "another-namespace", ImagesReady: true},
+			{ClusterName: "a-namespace", ImagesReady: true},
+		}
+
+		res := su.ManagedClusterModuleUpdateStatus(context.Background(), mcm, clusters, nil, manifestWorkList.Items)
 
 		Expect(res).To(BeNil())
 		Expect(mcm.Status.NumberDesired).To(BeEquivalentTo(len(manifestWorkList.Items)))
 		Expect(mcm.Status.NumberApplied).To(BeEquivalentTo(1))
 		Expect(mcm.Status.NumberDegraded).To(BeEquivalentTo(1))
+		Expect(mcm.Status.Clusters).To(Equal([]hubv1beta1.ClusterModuleStatus{
+			{ClusterName: "a-namespace", ImagesReady: true, ManifestWorkApplied: true},
+			{ClusterName: "another-namespace", ImagesReady: true},
+		}))
+
+		cond := meta.FindStatusCondition(mcm.Status.Conditions, hubv1beta1.ManagedClusterModuleImagesReady)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+
+		cond = meta.FindStatusCondition(mcm.Status.Conditions, hubv1beta1.ManagedClusterModuleApplied)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Message).To(ContainSubstring("another-namespace"))
+
+		cond = meta.FindStatusCondition(mcm.Status.Conditions, hubv1beta1.ManagedClusterModuleAvailable)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Message).To(ContainSubstring("a-namespace, another-namespace"))
+
+		cond = meta.FindStatusCondition(mcm.Status.Conditions, hubv1beta1.ManagedClusterModuleDegraded)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal("ManifestWorksDegraded"))
+		Expect(cond.Message).To(ContainSubstring("another-namespace"))
+	})
+
+	It("should report the module as available once loaded on all the nodes of the cluster", func() {
+		statusWrite := client.NewMockStatusWriter(ctrl)
+		clnt.EXPECT().Status().Return(statusWrite)
+		statusWrite.EXPECT().Patch(context.Background(), mcm, gomock.Any()).Return(nil)
+
+		mcm.Spec.ModuleSpec.ModuleLoader = &kmmv1beta1.ModuleLoaderSpec{}
+
+		intValue := func(v int64) *int64 { return &v }
+		mw := workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "cluster-1",
+			},
+			Status: workv1.ManifestWorkStatus{
+				Conditions: []metav1.Condition{
+					{Type: workv1.WorkApplied, Status: metav1.ConditionTrue},
+					{Type: workv1.WorkAvailable, Status: metav1.ConditionTrue},
+				},
+				ResourceStatus: workv1.ManifestResourceStatus{
+					Manifests: []workv1.ManifestCondition{
+						{
+							ResourceMeta: workv1.ManifestResourceMeta{
+								Group:    kmmv1beta1.GroupVersion.Group,
+								Resource: "modules",
+							},
+							StatusFeedbacks: workv1.StatusFeedbackResult{
+								Values: []workv1.FeedbackValue{
+									{
+										Name:  "moduleLoader.availableNumber",
+										Value: workv1.FieldValue{Type: workv1.Integer, Integer: intValue(2)},
+									},
+									{
+										Name:  "moduleLoader.desiredNumber",
+										Value: workv1.FieldValue{Type: workv1.Integer, Integer: intValue(2)},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		clusters := []hubv1beta1.ClusterModuleStatus{{ClusterName: "cluster-1", ImagesReady: true}}
+
+		err := su.ManagedClusterModuleUpdateStatus(context.Background(), mcm, clusters, nil, []workv1.ManifestWork{mw})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mcm.Status.Clusters).To(HaveLen(1))
+		Expect(mcm.Status.Clusters[0].ModuleLoader).To(Equal(&kmmv1beta1.DaemonSetStatus{
+			AvailableNumber: 2,
+			DesiredNumber:   2,
+		}))
+		Expect(meta.IsStatusConditionTrue(mcm.Status.Conditions, hubv1beta1.ManagedClusterModuleApplied)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(mcm.Status.Conditions, hubv1beta1.ManagedClusterModuleAvailable)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(mcm.Status.Conditions, hubv1beta1.ManagedClusterModuleDegraded)).To(BeFalse())
+	})
+	It("should report a rollout paused because of degraded ManifestWorks", func() {
+		statusWrite := client.NewMockStatusWriter(ctrl)
+		clnt.EXPECT().Status().Return(statusWrite)
+		statusWrite.EXPECT().Patch(context.Background(), mcm, gomock.Any()).Return(nil)
+
+		rollout := &hubv1beta1.RolloutStatus{
+			Revision:       "some-revision",
+			Stage:          1,
+			Stages:         2,
+			PausedRevision: "some-revision",
+			Message:        "the rollout is paused",
+		}
+
+		err := su.ManagedClusterModuleUpdateStatus(context.Background(), mcm, nil, rollout, nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mcm.Status.Rollout).To(Equal(rollout))
+
+		cond := meta.FindStatusCondition(mcm.Status.Conditions, hubv1beta1.ManagedClusterModuleRolloutPaused)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal("ManifestWorksDegraded"))
+		Expect(cond.Message).To(Equal("the rollout is paused"))
 	})
 })
 
@@ -0,0 +1,6 @@
+package hub
+
+// The hub serves the KernelBuilderCatalog webhook of the webhook package; this marker only adds it to the webhook
+// configuration of the hub.
+
+//+kubebuilder:webhook:path=/validate-kmm-sigs-x-k8s-io-v1beta1-kernelbuildercatalog,mutating=false,failurePolicy=fail,sideEffects=None,groups=kmm.sigs.x-k8s.io,resources=kernelbuildercatalogs,verbs=create;update,versions=v1beta1,name=vkernelbuildercatalog.kb.io,admissionReviewVersions=v1
@@ -18,11 +18,14 @@ package hub
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/go-logr/logr"
 	"github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/manifestwork"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/version"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/webhook"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -62,6 +65,14 @@ func (m *ManagedClusterModuleValidator) ValidateCreate(ctx context.Context, obj
 
 	m.logger.Info("Validating ManagedClusterModule creation", "name", mcm.Name, "namespace", mcm.Namespace)
 
+	if err := validateClusterSelection(mcm); err != nil {
+		return nil, err
+	}
+
+	if err := validateOverrides(mcm); err != nil {
+		return nil, fmt.Errorf("failed to validate overrides: %v", err)
+	}
+
 	return m.m.ValidateCreate(ctx, &kmmv1beta1.Module{Spec: mcm.Spec.ModuleSpec})
 }
 
@@ -79,6 +90,14 @@ func (m *ManagedClusterModuleValidator) ValidateUpdate(ctx context.Context, oldO
 
 	m.logger.Info("Validating ManagedClusterModule update", "name", oldMCM.Name, "namespace", oldMCM.Namespace)
 
+	if err := validateClusterSelection(newMCM); err != nil {
+		return nil, err
+	}
+
+	if err := validateOverrides(newMCM); err != nil {
+		return nil, fmt.Errorf("failed to validate overrides: %v", err)
+	}
+
 	return m.m.ValidateUpdate(ctx, &kmmv1beta1.Module{Spec: oldMCM.Spec.ModuleSpec}, &kmmv1beta1.Module{Spec: newMCM.Spec.ModuleSpec})
 }
 
@@ -86,3 +105,36 @@ func (m *ManagedClusterModuleValidator) ValidateUpdate(ctx context.Context, oldO
 func (m *ManagedClusterModuleValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	return nil, webhook.NotImplemented
 }
+
+func validateClusterSelection(mcm *v1beta1.ManagedClusterModule) error {
+	if (mcm.Spec.Selector == nil) == (mcm.Spec.Placement == nil) {
+		return errors.New("exactly one of selector and placement must be set")
+	}
+
+	return nil
+}
+
+// validateOverrides checks that each override selects clusters and applies to the Module of the ManagedClusterModule on
+// its own; the overrides that apply to the same cluster are only applied together when the Module is deployed.
+func validateOverrides(mcm *v1beta1.ManagedClusterModule) error {
+	var data []byte
+
+	for i, override := range mcm.Spec.Overrides {
+		if (override.ClusterName == "") == (len(override.ClusterSelector) == 0) {
+			return fmt.Errorf("override %d: exactly one of clusterName and clusterSelector must be set", i)
+		}
+
+		if data == nil {
+			var err error
+			if data, err = json.Marshal(manifestwork.BaseModule(mcm)); err != nil {
+				return fmt.Errorf("could not marshal the Module: %v", err)
+			}
+		}
+
+		if _, err := manifestwork.PatchModule(data, override); err != nil {
+			return fmt.Errorf("override %d: %v", i, err)
+		}
+	}
+
+	return nil
+}
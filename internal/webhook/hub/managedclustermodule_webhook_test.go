@@ -0,0 +1,105 @@
+package hub
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rh-ecosystem-edge/kernel-module-management/api-hub/v1beta1"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/version"
+)
+
+var _ = Describe("validateClusterSelection", func() {
+	placement := &v1beta1.PlacementReference{Name: "placement", Namespace: "namespace"}
+
+	DescribeTable("should require exactly one of selector and placement",
+		func(selector map[string]string, placement *v1beta1.PlacementReference, expectError bool) {
+			mcm := &v1beta1.ManagedClusterModule{
+				Spec: v1beta1.ManagedClusterModuleSpec{Selector: selector, Placement: placement},
+			}
+
+			err := validateClusterSelection(mcm)
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("selector only", map[string]string{"key": "value"}, nil, false),
+		Entry("empty selector only", map[string]string{}, nil, false),
+		Entry("placement only", nil, placement, false),
+		Entry("neither", nil, nil, true),
+		Entry("both", map[string]string{"key": "value"}, placement, true),
+	)
+})
+
+var _ = Describe("validateOverrides", func() {
+	DescribeTable("should validate the overrides",
+		func(override v1beta1.ClusterOverride, expectedErr string) {
+			mcm := &v1beta1.ManagedClusterModule{
+				ObjectMeta: metav1.ObjectMeta{Name: "mcm"},
+				Spec: v1beta1.ManagedClusterModuleSpec{
+					SpokeNamespace: "namespace",
+					ModuleSpec:     kmmv1beta1.ModuleSpec{Selector: map[string]string{"key": "value"}},
+					Overrides:      []v1beta1.ClusterOverride{override},
+				},
+			}
+
+			err := validateOverrides(mcm)
+			if expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+			}
+		},
+		Entry("strategic merge patch",
+			v1beta1.ClusterOverride{ClusterName: "cluster", Patch: `{"spec": {"selector": {"key": "other"}}}`}, ""),
+		Entry("JSON patch",
+			v1beta1.ClusterOverride{
+				ClusterName: "cluster",
+				Type:        v1beta1.JSONPatchType,
+				Patch:       `[{"op": "replace", "path": "/spec/selector/key", "value": "other"}]`,
+			},
+			"",
+		),
+		Entry("no cluster selected",
+			v1beta1.ClusterOverride{Patch: `{"spec": {}}`}, "exactly one of clusterName and clusterSelector must be set"),
+		Entry("patch changing the metadata",
+			v1beta1.ClusterOverride{ClusterName: "cluster", Patch: `{"metadata": {"namespace": "other"}}`},
+			"may only change the spec"),
+		Entry("JSON patch that does not apply",
+			v1beta1.ClusterOverride{
+				ClusterName: "cluster",
+				Type:        v1beta1.JSONPatchType,
+				Patch:       `[{"op": "remove", "path": "/spec/missing"}]`,
+			},
+			"could not apply the JSON patch"),
+	)
+})
+
+var _ = Describe("ValidateCreate", func() {
+	validator := NewManagedClusterModuleValidator(GinkgoLogr, &version.OCPVersion{Major: 4, Minor: 21})
+
+	It("should reject a ManagedClusterModule that selects no managed clusters", func() {
+		_, err := validator.ValidateCreate(context.Background(), &v1beta1.ManagedClusterModule{})
+		Expect(err).To(MatchError(ContainSubstring("exactly one of selector and placement must be set")))
+	})
+})
+
+var _ = Describe("ValidateUpdate", func() {
+	validator := NewManagedClusterModuleValidator(GinkgoLogr, &version.OCPVersion{Major: 4, Minor: 21})
+
+	It("should reject a ManagedClusterModule that sets both a selector and a placement", func() {
+		oldMCM := &v1beta1.ManagedClusterModule{
+			Spec: v1beta1.ManagedClusterModuleSpec{Selector: map[string]string{"key": "value"}},
+		}
+		newMCM := oldMCM.DeepCopy()
+		newMCM.Spec.Placement = &v1beta1.PlacementReference{Name: "placement", Namespace: "namespace"}
+
+		_, err := validator.ValidateUpdate(context.Background(), oldMCM, newMCM)
+		Expect(err).To(MatchError(ContainSubstring("exactly one of selector and placement must be set")))
+	})
+})
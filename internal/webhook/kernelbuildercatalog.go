@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/buildercatalog"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// KernelBuilderCatalogValidator validates KernelBuilderCatalog resources.
+type KernelBuilderCatalogValidator struct {
+	logger logr.Logger
+}
+
+func NewKernelBuilderCatalogValidator(logger logr.Logger) *KernelBuilderCatalogValidator {
+	return &KernelBuilderCatalogValidator{logger: logger}
+}
+
+func (v *KernelBuilderCatalogValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&kmmv1beta1.KernelBuilderCatalog{}).
+		WithValidator(v).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-kmm-sigs-x-k8s-io-v1beta1-kernelbuildercatalog,mutating=false,failurePolicy=fail,sideEffects=None,groups=kmm.sigs.x-k8s.io,resources=kernelbuildercatalogs,verbs=create;update,versions=v1beta1,name=vkernelbuildercatalog.kb.io,admissionReviewVersions=v1
+
+func (v *KernelBuilderCatalogValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	catalog, ok := obj.(*kmmv1beta1.KernelBuilderCatalog)
+	if !ok {
+		return nil, fmt.Errorf("bad type for the object; expected %v, got %v", catalog, obj)
+	}
+
+	v.logger.Info("Validating KernelBuilderCatalog creation", "name", catalog.Name)
+	return nil, buildercatalog.Validate(catalog)
+}
+
+func (v *KernelBuilderCatalogValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldCatalog, ok := oldObj.(*kmmv1beta1.KernelBuilderCatalog)
+	if !ok {
+		return nil, fmt.Errorf("bad type for the old object; expected %v, got %v", oldCatalog, oldObj)
+	}
+
+	newCatalog, ok := newObj.(*kmmv1beta1.KernelBuilderCatalog)
+	if !ok {
+		return nil, fmt.Errorf("bad type for the new object; expected %v, got %v", newCatalog, newObj)
+	}
+
+	v.logger.Info("Validating KernelBuilderCatalog update", "name", oldCatalog.Name)
+	return nil, buildercatalog.Validate(newCatalog)
+}
+
+func (v *KernelBuilderCatalogValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, NotImplemented
+}
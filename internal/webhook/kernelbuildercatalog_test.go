@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+)
+
+var _ = Describe("KernelBuilderCatalogValidator", func() {
+	v := NewKernelBuilderCatalogValidator(GinkgoLogr)
+	ctx := context.TODO()
+
+	validCatalog := &kmmv1beta1.KernelBuilderCatalog{
+		Spec: kmmv1beta1.KernelBuilderCatalogSpec{
+			Entries: []kmmv1beta1.KernelBuilderEntry{
+				{KernelVersionRegexp: `-generic$`, BuilderImage: "some-image"},
+			},
+		},
+	}
+
+	invalidCatalog := &kmmv1beta1.KernelBuilderCatalog{
+		Spec: kmmv1beta1.KernelBuilderCatalogSpec{
+			Entries: []kmmv1beta1.KernelBuilderEntry{
+				{KernelVersionRegexp: `-generic$`, BuilderImage: "some-image"},
+				{OSImageRegexp: `^Ubuntu (`, BuilderImage: "some-image"},
+			},
+		},
+	}
+
+	It("should accept a catalog with valid regular expressions", func() {
+		_, err := v.ValidateCreate(ctx, validCatalog)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = v.ValidateUpdate(ctx, invalidCatalog, validCatalog)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject a catalog with an invalid regular expression", func() {
+		_, err := v.ValidateCreate(ctx, invalidCatalog)
+		Expect(err).To(MatchError(ContainSubstring("entries[1].osImageRegexp")))
+
+		_, err = v.ValidateUpdate(ctx, validCatalog, invalidCatalog)
+		Expect(err).To(MatchError(ContainSubstring("entries[1].osImageRegexp")))
+	})
+
+	It("ValidateDelete should return not implemented", func() {
+		_, err := v.ValidateDelete(ctx, nil)
+		Expect(err).To(Equal(NotImplemented))
+	})
+})
@@ -20,16 +20,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"regexp"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/go-logr/logr"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/version"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -236,7 +239,15 @@ func validateModuleLoaderContainerSpec(container kmmv1beta1.ModuleLoaderContaine
 		}
 	}
 
+	if err := validateBuild(container.Build); err != nil {
+		return fmt.Errorf("invalid build: %v", err)
+	}
+
 	for idx, km := range container.KernelMappings {
+		if err := validateBuild(km.Build); err != nil {
+			return fmt.Errorf("invalid build at kernelMappings[%d]: %v", idx, err)
+		}
+
 		if km.Regexp != "" && km.Literal != "" {
 			return fmt.Errorf("regexp and literal are mutually exclusive properties at kernelMappings[%d]", idx)
 		}
@@ -270,6 +281,76 @@ func validateModuleLoaderContainerSpec(container kmmv1beta1.ModuleLoaderContaine
 	return nil
 }
 
+func validateBuild(build *kmmv1beta1.Build) error {
+	if build == nil {
+		return nil
+	}
+
+	for i, cm := range build.ContextConfigMaps {
+		if err := validateBuildContextDir(cm.DestinationDir); err != nil {
+			return fmt.Errorf("contextConfigMaps[%d]: %v", i, err)
+		}
+	}
+
+	for i, s := range build.ContextSecrets {
+		if err := validateBuildContextDir(s.DestinationDir); err != nil {
+			return fmt.Errorf("contextSecrets[%d]: %v", i, err)
+		}
+	}
+
+	if build.Git != nil {
+		if build.Git.URI == "" {
+			return errors.New("git.uri is required when git is set")
+		}
+
+		if err := validateBuildContextDir(build.Git.ContextDir); err != nil {
+			return fmt.Errorf("git: %v", err)
+		}
+	}
+
+	if err := validateRetryPolicy(build.RetryPolicy); err != nil {
+		return fmt.Errorf("retryPolicy: %v", err)
+	}
+
+	return nil
+}
+
+// validateRetryPolicy checks that the durations of the retry policy are positive.
+func validateRetryPolicy(retryPolicy *kmmv1beta1.RetryPolicy) error {
+	if retryPolicy == nil {
+		return nil
+	}
+
+	durations := []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{name: "backoff", duration: retryPolicy.Backoff},
+		{name: "maxBackoff", duration: retryPolicy.MaxBackoff},
+		{name: "timeout", duration: retryPolicy.Timeout},
+	}
+	for _, d := range durations {
+		if d.duration != nil && d.duration.Duration <= 0 {
+			return fmt.Errorf("%s must be positive", d.name)
+		}
+	}
+
+	return nil
+}
+
+// validateBuildContextDir checks that dir stays within the build context.
+func validateBuildContextDir(dir string) error {
+	if filepath.IsAbs(dir) {
+		return fmt.Errorf("%q must be a relative path", dir)
+	}
+
+	if cleaned := filepath.Clean(dir); cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return fmt.Errorf("%q must not point outside of the build context", dir)
+	}
+
+	return nil
+}
+
 func validateModprobe(modprobe kmmv1beta1.ModprobeSpec) error {
 	moduleName := modprobe.ModuleName
 	moduleNameDefined := moduleName != ""
@@ -315,14 +396,102 @@ func validateSignSection(sign *kmmv1beta1.Sign, dirName string) error {
 	if sign == nil {
 		return nil
 	}
-	if len(sign.FilesToSign) == 0 {
-		return fmt.Errorf("filesToSign is required when Sign is set")
+	if len(sign.FilesToSign) != 0 && sign.AutoDiscover != nil {
+		return errors.New("filesToSign and autoDiscover are mutually exclusive")
+	}
+	if err := validateSignAutoDiscovery(sign.AutoDiscover); err != nil {
+		return fmt.Errorf("autoDiscover: %v", err)
 	}
 	for _, filePath := range sign.FilesToSign {
 		if !strings.HasPrefix(filePath, dirName+"/") {
 			return fmt.Errorf("filesToSign[%q] must be under dirName %q", filePath, dirName)
 		}
 	}
+	if err := validateRetryPolicy(sign.RetryPolicy); err != nil {
+		return fmt.Errorf("retryPolicy: %v", err)
+	}
+	signingKeys := 0
+	for _, isSet := range []bool{sign.KeySecret != nil, sign.PKCS11 != nil, sign.SigningService != nil, sign.SigningKey != nil} {
+		if isSet {
+			signingKeys++
+		}
+	}
+	if signingKeys > 1 {
+		return errors.New("keySecret, pkcs11, signingService and signingKey are mutually exclusive")
+	}
+	if sign.SigningKey != nil && sign.CertSecret != nil {
+		return errors.New("certSecret and signingKey are mutually exclusive")
+	}
+	if err := validatePKCS11Key(sign.PKCS11); err != nil {
+		return fmt.Errorf("pkcs11: %v", err)
+	}
+	if err := validateSigningService(sign.SigningService); err != nil {
+		return fmt.Errorf("signingService: %v", err)
+	}
+	return nil
+}
+
+// validateMergedSign validates the requirements that the Sign section of a kernel mapping may fulfill through the Sign
+// section of the Module, once both are merged.
+func validateMergedSign(sign *kmmv1beta1.Sign) error {
+	if len(sign.FilesToSign) == 0 && sign.AutoDiscover == nil {
+		return errors.New("filesToSign or autoDiscover is required when Sign is set")
+	}
+	if sign.SigningKey == nil && sign.CertSecret == nil {
+		return errors.New("certSecret is required unless signingKey is set")
+	}
+	return nil
+}
+
+// autoDiscoveryPatternRegexp only allows the characters of shell patterns that are safe in a case statement
+var autoDiscoveryPatternRegexp = regexp.MustCompile(`^[A-Za-z0-9_.*?/+\[\]-]+$`)
+
+func validateSignAutoDiscovery(autoDiscover *kmmv1beta1.SignAutoDiscovery) error {
+	if autoDiscover == nil {
+		return nil
+	}
+	for _, pattern := range append(append([]string{}, autoDiscover.Include...), autoDiscover.Exclude...) {
+		if !autoDiscoveryPatternRegexp.MatchString(pattern) {
+			return fmt.Errorf("pattern %q must only contain letters, digits and the characters _.*?/+[]-", pattern)
+		}
+	}
+	return nil
+}
+
+var pkcs11ModulePathRegexp = regexp.MustCompile(`^/[A-Za-z0-9._/+-]+$`)
+
+func validatePKCS11Key(pkcs11 *kmmv1beta1.PKCS11Key) error {
+	if pkcs11 == nil {
+		return nil
+	}
+	if !strings.HasPrefix(pkcs11.URI, "pkcs11:") {
+		return fmt.Errorf("uri %q must start with pkcs11:", pkcs11.URI)
+	}
+	if strings.Contains(pkcs11.URI, "'") {
+		return fmt.Errorf("uri %q must not contain single quotes", pkcs11.URI)
+	}
+	// the module path is used as is in the sign's shell commands
+	if !pkcs11ModulePathRegexp.MatchString(pkcs11.ModulePath) {
+		return fmt.Errorf("modulePath %q must be an absolute path without spaces or special characters", pkcs11.ModulePath)
+	}
+	return nil
+}
+
+func validateSigningService(signingService *kmmv1beta1.SigningService) error {
+	// an empty URL defaults to the operator's signing service
+	if signingService == nil || signingService.URL == "" {
+		return nil
+	}
+	u, err := url.Parse(signingService.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %v", signingService.URL, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("url %q must be an https URL", signingService.URL)
+	}
+	if strings.Contains(signingService.URL, "'") {
+		return fmt.Errorf("url %q must not contain single quotes", signingService.URL)
+	}
 	return nil
 }
 
@@ -334,11 +503,17 @@ func validateFilesToSign(container kmmv1beta1.ModuleLoaderContainerSpec) error {
 		return fmt.Errorf("global Sign: %v", err)
 	}
 
-	// Validate Sign section in each kernelMapping
+	// Validate Sign section in each kernelMapping, then the Sign configuration it results in
 	for idx, km := range container.KernelMappings {
 		if err := validateSignSection(km.Sign, dirName); err != nil {
 			return fmt.Errorf("kernelMappings[%d].Sign: %v", idx, err)
 		}
+		if km.Sign == nil && container.Sign == nil {
+			continue
+		}
+		if err := validateMergedSign(module.MergeSign(container.Sign, km.Sign)); err != nil {
+			return fmt.Errorf("kernelMappings[%d].Sign merged with the global Sign: %v", idx, err)
+		}
 	}
 
 	return nil
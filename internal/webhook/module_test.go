@@ -20,12 +20,14 @@ import (
 	"context"
 	v1 "k8s.io/api/core/v1"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/version"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func getLengthAfterSlash(s string) int {
@@ -263,6 +265,207 @@ var _ = Describe("validateModuleLoaderContainerSpec", func() {
 
 })
 
+var _ = Describe("validateBuild", func() {
+	DescribeTable("should validate the build context",
+		func(build *kmmv1beta1.Build, expectError bool) {
+			err := validateBuild(build)
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("no build", nil, false),
+		Entry("no build context", &kmmv1beta1.Build{}, false),
+		Entry(
+			"valid build context",
+			&kmmv1beta1.Build{
+				ContextConfigMaps: []kmmv1beta1.BuildContextConfigMap{{DestinationDir: "patches"}},
+				ContextSecrets:    []kmmv1beta1.BuildContextSecret{{DestinationDir: "./keys/../certs"}},
+				Git:               &kmmv1beta1.GitBuildSource{URI: "https://example.org/repo.git", ContextDir: "driver"},
+			},
+			false,
+		),
+		Entry(
+			"absolute ConfigMap destination",
+			&kmmv1beta1.Build{ContextConfigMaps: []kmmv1beta1.BuildContextConfigMap{{DestinationDir: "/patches"}}},
+			true,
+		),
+		Entry(
+			"Secret destination outside of the build context",
+			&kmmv1beta1.Build{ContextSecrets: []kmmv1beta1.BuildContextSecret{{DestinationDir: "keys/../../certs"}}},
+			true,
+		),
+		Entry("Git without URI", &kmmv1beta1.Build{Git: &kmmv1beta1.GitBuildSource{}}, true),
+		Entry(
+			"Git context dir outside of the repository",
+			&kmmv1beta1.Build{Git: &kmmv1beta1.GitBuildSource{URI: "https://example.org/repo.git", ContextDir: ".."}},
+			true,
+		),
+		Entry(
+			"valid retry policy",
+			&kmmv1beta1.Build{
+				RetryPolicy: &kmmv1beta1.RetryPolicy{
+					MaxAttempts: 3,
+					Backoff:     &metav1.Duration{Duration: time.Minute},
+					Timeout:     &metav1.Duration{Duration: time.Hour},
+				},
+			},
+			false,
+		),
+		Entry(
+			"retry policy with a negative timeout",
+			&kmmv1beta1.Build{RetryPolicy: &kmmv1beta1.RetryPolicy{Timeout: &metav1.Duration{Duration: -time.Hour}}},
+			true,
+		),
+	)
+})
+
+var _ = Describe("validateSignSection", func() {
+	DescribeTable("should validate the signing key",
+		func(keySecret *v1.LocalObjectReference, pkcs11 *kmmv1beta1.PKCS11Key, signingService *kmmv1beta1.SigningService, expectError bool) {
+			sign := &kmmv1beta1.Sign{
+				KeySecret:      keySecret,
+				PKCS11:         pkcs11,
+				SigningService: signingService,
+				CertSecret:     &v1.LocalObjectReference{Name: "cert"},
+				FilesToSign:    []string{"/opt/lib/modules/mod.ko"},
+			}
+			err := validateSignSection(sign, "/opt")
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("key Secret", &v1.LocalObjectReference{Name: "key"}, nil, nil, false),
+		Entry(
+			"valid PKCS#11 key",
+			nil,
+			&kmmv1beta1.PKCS11Key{URI: "pkcs11:token=secureboot;object=key", ModulePath: "/usr/lib64/pkcs11/libsofthsm2.so"},
+			nil,
+			false,
+		),
+		Entry(
+			"key Secret and PKCS#11 key",
+			&v1.LocalObjectReference{Name: "key"},
+			&kmmv1beta1.PKCS11Key{URI: "pkcs11:object=key", ModulePath: "/lib/p11.so"},
+			nil,
+			true,
+		),
+		Entry("invalid URI scheme", nil, &kmmv1beta1.PKCS11Key{URI: "file:/key", ModulePath: "/lib/p11.so"}, nil, true),
+		Entry("URI with a single quote", nil, &kmmv1beta1.PKCS11Key{URI: "pkcs11:object='key", ModulePath: "/lib/p11.so"}, nil, true),
+		Entry("relative module path", nil, &kmmv1beta1.PKCS11Key{URI: "pkcs11:object=key", ModulePath: "lib/p11.so"}, nil, true),
+		Entry("module path with a shell command", nil, &kmmv1beta1.PKCS11Key{URI: "pkcs11:object=key", ModulePath: "/lib/p11.so;id"}, nil, true),
+		Entry("signing service", nil, nil, &kmmv1beta1.SigningService{URL: "https://signer.example.org/sign"}, false),
+		Entry("default signing service", nil, nil, &kmmv1beta1.SigningService{}, false),
+		Entry(
+			"key Secret and signing service",
+			&v1.LocalObjectReference{Name: "key"},
+			nil,
+			&kmmv1beta1.SigningService{URL: "https://signer.example.org/sign"},
+			true,
+		),
+		Entry("plain HTTP signing service", nil, nil, &kmmv1beta1.SigningService{URL: "http://signer.example.org/sign"}, true),
+		Entry("signing service URL with a single quote", nil, nil, &kmmv1beta1.SigningService{URL: "https://signer/'sign"}, true),
+	)
+
+	DescribeTable("should validate the files to sign",
+		func(filesToSign []string, autoDiscover *kmmv1beta1.SignAutoDiscovery, expectError bool) {
+			sign := &kmmv1beta1.Sign{
+				KeySecret:    &v1.LocalObjectReference{Name: "key"},
+				CertSecret:   &v1.LocalObjectReference{Name: "cert"},
+				FilesToSign:  filesToSign,
+				AutoDiscover: autoDiscover,
+			}
+			err := validateSignSection(sign, "/opt")
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("explicit files", []string{"/opt/lib/modules/mod.ko"}, nil, false),
+		Entry("file outside of dirName", []string{"/lib/modules/mod.ko"}, nil, true),
+		Entry("auto-discovery", nil, &kmmv1beta1.SignAutoDiscovery{}, false),
+		Entry(
+			"auto-discovery with patterns",
+			nil,
+			&kmmv1beta1.SignAutoDiscovery{Include: []string{"kernel/drivers/*", "*/mlx5_[a-z]*.ko"}, Exclude: []string{"*/test-?.ko.xz"}},
+			false,
+		),
+		Entry("files and auto-discovery", []string{"/opt/lib/modules/mod.ko"}, &kmmv1beta1.SignAutoDiscovery{}, true),
+		Entry("pattern with a shell command", nil, &kmmv1beta1.SignAutoDiscovery{Include: []string{"*) id; ("}}, true),
+		Entry("pattern with a variable", nil, &kmmv1beta1.SignAutoDiscovery{Exclude: []string{"$HOME"}}, true),
+	)
+
+	DescribeTable("should validate the SigningKey",
+		func(keySecret, certSecret, signingKey *v1.LocalObjectReference, expectError bool) {
+			sign := &kmmv1beta1.Sign{
+				KeySecret:   keySecret,
+				CertSecret:  certSecret,
+				SigningKey:  signingKey,
+				FilesToSign: []string{"/opt/lib/modules/mod.ko"},
+			}
+			err := validateSignSection(sign, "/opt")
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("SigningKey", nil, nil, &v1.LocalObjectReference{Name: "sk"}, false),
+		Entry("SigningKey and key Secret", &v1.LocalObjectReference{Name: "key"}, nil, &v1.LocalObjectReference{Name: "sk"}, true),
+		Entry("SigningKey and certificate Secret", nil, &v1.LocalObjectReference{Name: "cert"}, &v1.LocalObjectReference{Name: "sk"}, true),
+	)
+})
+
+var _ = Describe("validateFilesToSign", func() {
+	key := &v1.LocalObjectReference{Name: "key"}
+	cert := &v1.LocalObjectReference{Name: "cert"}
+	files := []string{"/opt/lib/modules/mod.ko"}
+
+	DescribeTable("should validate the Sign configuration of each kernel mapping once merged with the global one",
+		func(globalSign, mappingSign *kmmv1beta1.Sign, expectError bool) {
+			container := kmmv1beta1.ModuleLoaderContainerSpec{
+				Modprobe:       kmmv1beta1.ModprobeSpec{DirName: "/opt"},
+				Sign:           globalSign,
+				KernelMappings: []kmmv1beta1.KernelMapping{{Regexp: ".*", Sign: mappingSign}},
+			}
+			err := validateFilesToSign(container)
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("no Sign section", nil, nil, false),
+		Entry("complete global Sign", &kmmv1beta1.Sign{KeySecret: key, CertSecret: cert, FilesToSign: files}, nil, false),
+		Entry("global Sign without certificate", &kmmv1beta1.Sign{KeySecret: key, FilesToSign: files}, nil, true),
+		Entry("global Sign without files", &kmmv1beta1.Sign{KeySecret: key, CertSecret: cert}, nil, true),
+		Entry("complete mapping Sign", nil, &kmmv1beta1.Sign{KeySecret: key, CertSecret: cert, FilesToSign: files}, false),
+		Entry("mapping Sign without certificate", nil, &kmmv1beta1.Sign{KeySecret: key, FilesToSign: files}, true),
+		Entry(
+			"mapping Sign replacing the key and relying on the global certificate and files",
+			&kmmv1beta1.Sign{KeySecret: key, CertSecret: cert, FilesToSign: files},
+			&kmmv1beta1.Sign{KeySecret: &v1.LocalObjectReference{Name: "other-key"}},
+			false,
+		),
+		Entry(
+			"mapping Sign providing the certificate missing from the global Sign",
+			&kmmv1beta1.Sign{KeySecret: key, FilesToSign: files},
+			&kmmv1beta1.Sign{CertSecret: cert},
+			false,
+		),
+		Entry(
+			"mapping SigningKey replacing the global key and certificate",
+			&kmmv1beta1.Sign{KeySecret: key, CertSecret: cert, FilesToSign: files},
+			&kmmv1beta1.Sign{SigningKey: &v1.LocalObjectReference{Name: "sk"}},
+			false,
+		),
+	)
+})
+
 var _ = Describe("validateModprobe", func() {
 	It("should fail when moduleName and rawArgs are missing", func() {
 		Expect(